@@ -132,6 +132,9 @@ module Bosh::Template::Test
           'log_prefix' => 'cfnetworking',
           'metron_address' => '127.0.0.1:3457',
           'skip_ssl_validation' => false,
+          'debug_server_host' => '127.0.0.1',
+          'debug_server_port' => 31947,
+          'enable_prometheus_metrics' => false,
           'uaa_ca' => '/var/vcap/jobs/policy-server-asg-syncer/config/certs/uaa_ca.crt',
           'asg_poll_interval_seconds' => 60,
           'retry_deadline_seconds' => 300,
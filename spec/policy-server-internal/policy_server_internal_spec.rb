@@ -97,6 +97,11 @@ module Bosh::Template::Test
           'max_idle_connections' => 10,
           'max_open_connections' => 5,
           'connections_max_lifetime_seconds' => 54,
+          'enable_watch' => false,
+          'watch_poll_interval_seconds' => 1,
+          'watch_history_size' => 1000,
+          'cache_policies' => false,
+          'enable_prometheus_metrics' => false,
           'tag_length' => 1,
           'metron_address' => '127.0.0.1:4567',
           'log_level' => 'error',
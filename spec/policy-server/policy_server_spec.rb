@@ -167,8 +167,16 @@ module Bosh::Template::Test
           'metron_address' => '127.0.0.1:6789',
           'log_level' => 'debug',
           'cleanup_interval' => 60,
+          'group_members_update_interval' => 60,
+          'policy_events_retention_days' => 90,
           'max_policies' => 2,
           'enable_space_developer_self_service' => true,
+          'scope_permissions' => {},
+          'role_permissions' => {},
+          'enable_cross_space_consent' => false,
+          'enable_prometheus_metrics' => false,
+          'tracing_endpoint' => '',
+          'tracing_sample_ratio' => 0.1,
           'allowed_cors_domains' => ['some-cors-domain'],
           'uaa_ca' => '/var/vcap/jobs/policy-server/config/certs/uaa_ca.crt',
           'request_timeout' => 5,
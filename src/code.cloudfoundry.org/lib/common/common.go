@@ -3,14 +3,17 @@ package common
 import (
 	"crypto/tls"
 	"fmt"
+	"net/http"
 	"time"
 
 	"code.cloudfoundry.org/bbs/db/sqldb/helpers/monitor"
 	"code.cloudfoundry.org/cf-networking-helpers/metrics"
+	"code.cloudfoundry.org/debugserver"
 	"code.cloudfoundry.org/lager/v3"
 	"code.cloudfoundry.org/lager/v3/lagerflags"
 	"code.cloudfoundry.org/policy-server/server_metrics"
 	"code.cloudfoundry.org/policy-server/store"
+	"github.com/jmoiron/sqlx"
 	"github.com/tedsuo/ifrit"
 	"github.com/tedsuo/ifrit/http_server"
 	"github.com/tedsuo/rata"
@@ -39,6 +42,30 @@ func InitMetricsEmitter(logger lager.Logger, wrappedStore *store.MetricsWrapper,
 	return metrics.NewMetricsEmitter(logger, emitInterval, metricSources...)
 }
 
+// InitPrometheusSources returns the sources of the gauges that the Prometheus
+// registry serves besides the durations and counters sent to it
+func InitPrometheusSources(wrappedStore *store.MetricsWrapper, db *sqlx.DB) []metrics.MetricSource {
+	metricSources := []metrics.MetricSource{metrics.NewUptimeSource()}
+	if wrappedStore != nil {
+		metricSources = append(metricSources, server_metrics.NewTotalPoliciesSource(wrappedStore))
+	}
+	return append(metricSources, server_metrics.NewDBStatsSources(db)...)
+}
+
+// InitDebugServer returns the debug server, which also serves the metrics
+// handler on /metrics when it is not nil
+func InitDebugServer(host string, port int, sink *lager.ReconfigurableSink, metricsHandler http.Handler) ifrit.Runner {
+	addr := fmt.Sprintf("%s:%d", host, port)
+	if metricsHandler == nil {
+		return debugserver.Runner(addr, sink)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metricsHandler)
+	mux.Handle("/", debugserver.Handler(sink))
+	return http_server.New(addr, mux)
+}
+
 func InitServer(logger lager.Logger, tlsConfig *tls.Config, host string, port int, handlers rata.Handlers, routes rata.Routes) ifrit.Runner {
 	router, err := rata.NewRouter(routes, handlers)
 	if err != nil {
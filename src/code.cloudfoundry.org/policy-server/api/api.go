@@ -2,15 +2,21 @@ package api
 
 //go:generate counterfeiter -generate
 
-import "code.cloudfoundry.org/policy-server/store"
+import (
+	"encoding/json"
+	"time"
+
+	"code.cloudfoundry.org/policy-server/store"
+)
 
 var ICMPDefault = -1
 var AppLifecycleDefault = "all"
 
 //counterfeiter:generate -o fakes/policy_mapper.go --fake-name PolicyMapper . PolicyMapper
 type PolicyMapper interface {
-	AsStorePolicy([]byte) ([]store.Policy, error) // unmarshal
-	AsBytes([]store.Policy) ([]byte, error)       // marshal
+	AsStorePolicy([]byte) ([]store.Policy, error)                           // unmarshal
+	AsBytes([]store.Policy) ([]byte, error)                                 // marshal
+	AsBytesWithPagination([]store.Policy, store.Pagination) ([]byte, error) // marshal
 }
 
 //counterfeiter:generate -o fakes/asg_mapper.go --fake-name AsgMapper . AsgMapper
@@ -20,12 +26,57 @@ type AsgMapper interface {
 
 type PoliciesPayload struct {
 	TotalPolicies int      `json:"total_policies"`
+	Next          int      `json:"next,omitempty"`
 	Policies      []Policy `json:"policies"`
 }
 
 type Policy struct {
 	Source      Source      `json:"source"`
 	Destination Destination `json:"destination"`
+	Description string      `json:"description,omitempty"`
+	Metadata    *Metadata   `json:"metadata,omitempty"`
+	ExpiresAt   *time.Time  `json:"expires_at,omitempty"`
+	Action      string      `json:"action,omitempty"`
+}
+
+// CreateDryRunPayload reports what creating the policies of a request would
+// do. Policies are returned as they were given in the request.
+type CreateDryRunPayload struct {
+	WouldCreate    []json.RawMessage `json:"would_create"`
+	WouldBePending []json.RawMessage `json:"would_be_pending"`
+	AlreadyExist   []json.RawMessage `json:"already_exist"`
+	WouldFail      []PolicyFailure   `json:"would_fail"`
+}
+
+// DeleteDryRunPayload reports what deleting the policies of a request would
+// do. Policies are returned as they were given in the request.
+type DeleteDryRunPayload struct {
+	WouldDelete []json.RawMessage `json:"would_delete"`
+	DoNotExist  []json.RawMessage `json:"do_not_exist"`
+	WouldFail   []PolicyFailure   `json:"would_fail"`
+}
+
+// Codes of the reasons a policy of a request is rejected
+const (
+	FailureInvalidPolicy = "invalid_policy"
+	FailureForbidden     = "forbidden"
+	FailureQuotaExceeded = "quota_exceeded"
+	FailureOverlap       = "overlapping_policy"
+	FailureDenied        = "denied_policy"
+)
+
+// PolicyFailure is a policy of a request that is rejected, together with its
+// index in the request and the reason it is rejected
+type PolicyFailure struct {
+	Index  int             `json:"index"`
+	Policy json.RawMessage `json:"policy"`
+	Code   string          `json:"code"`
+	Reason string          `json:"reason"`
+}
+
+type Metadata struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
 }
 
 type Source struct {
@@ -41,6 +92,8 @@ type Destination struct {
 	Ports    Ports     `json:"ports"`
 	Type     string    `json:"type,omitempty"`
 	IPs      []IPRange `json:"ips,omitempty"`
+	ICMPType *int      `json:"icmp_type,omitempty"`
+	ICMPCode *int      `json:"icmp_code,omitempty"`
 }
 
 type IPRange struct {
@@ -59,6 +112,109 @@ type Tag struct {
 	Type string `json:"type"`
 }
 
+type QuotasPayload struct {
+	Quotas []Quota `json:"quotas"`
+}
+
+type Quota struct {
+	Type        string `json:"type"`
+	GUID        string `json:"guid"`
+	MaxPolicies int    `json:"max_policies"`
+}
+
+type PolicyOverlapsPayload struct {
+	TotalOverlaps int             `json:"total_overlaps"`
+	Overlaps      []PolicyOverlap `json:"overlaps"`
+}
+
+// PolicyOverlap is a set of policies whose port ranges overlap or are
+// adjacent, and the policy that they are merged into. Policies with
+// different metadata are not merged.
+type PolicyOverlap struct {
+	Policies          []Policy `json:"policies"`
+	Merged            Policy   `json:"merged"`
+	MetadataConflicts bool     `json:"metadata_conflicts,omitempty"`
+}
+
+// SpacePoliciesPayload is the complete set of policies whose source is a
+// space or one of its apps. LastUpdated identifies the version of the
+// policies that a reconcile is based on. It is a string because it does not
+// fit in the integers of every JSON parser.
+type SpacePoliciesPayload struct {
+	LastUpdated   string   `json:"last_updated"`
+	TotalPolicies int      `json:"total_policies"`
+	Policies      []Policy `json:"policies"`
+}
+
+// PolicyManifest lists policies by the names of their apps, spaces and orgs
+// rather than their guids, so that they can be moved between foundations
+type PolicyManifest struct {
+	Policies []ManifestPolicy `json:"policies"`
+}
+
+type ManifestPolicy struct {
+	Source      ManifestGroup       `json:"source"`
+	Destination ManifestDestination `json:"destination"`
+	Description string              `json:"description,omitempty"`
+	Metadata    *Metadata           `json:"metadata,omitempty"`
+	ExpiresAt   *time.Time          `json:"expires_at,omitempty"`
+	Action      string              `json:"action,omitempty"`
+}
+
+// ManifestGroup names an app, or every app of a space or org when the app or
+// space name is left out
+type ManifestGroup struct {
+	Org   string `json:"org"`
+	Space string `json:"space,omitempty"`
+	App   string `json:"app,omitempty"`
+}
+
+type ManifestDestination struct {
+	ManifestGroup
+	Protocol string `json:"protocol"`
+	Ports    Ports  `json:"ports"`
+	ICMPType *int   `json:"icmp_type,omitempty"`
+	ICMPCode *int   `json:"icmp_code,omitempty"`
+}
+
+// PolicyImportPayload reports how importing a manifest changes the policies.
+// Policies are returned as they were given in the manifest.
+type PolicyImportPayload struct {
+	Create    []ManifestPolicy `json:"create"`
+	Update    []ManifestPolicy `json:"update"`
+	Unchanged []ManifestPolicy `json:"unchanged"`
+}
+
+type PolicyEventsPayload struct {
+	TotalEvents int           `json:"total_events"`
+	Next        int           `json:"next,omitempty"`
+	Events      []PolicyEvent `json:"events"`
+}
+
+type PolicyEvent struct {
+	ID         int      `json:"id"`
+	Action     string   `json:"action"`
+	Actor      string   `json:"actor"`
+	ClientID   string   `json:"client_id,omitempty"`
+	ApprovedBy string   `json:"approved_by,omitempty"`
+	Policies   []Policy `json:"policies"`
+	Timestamp  string   `json:"timestamp"`
+}
+
+type PendingPoliciesPayload struct {
+	TotalPendingPolicies int             `json:"total_pending_policies"`
+	PendingPolicies      []PendingPolicy `json:"pending_policies"`
+}
+
+type PendingPolicy struct {
+	ID                   int    `json:"id"`
+	Policy               Policy `json:"policy"`
+	DestinationSpaceGUID string `json:"destination_space_guid"`
+	RequestedBy          string `json:"requested_by"`
+	ClientID             string `json:"client_id,omitempty"`
+	Timestamp            string `json:"timestamp"`
+}
+
 type AsgsPayload struct {
 	Next           int             `json:"next"`
 	SecurityGroups []SecurityGroup `json:"security_groups"`
@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"time"
 
 	"code.cloudfoundry.org/cf-networking-helpers/httperror"
 	"code.cloudfoundry.org/cf-networking-helpers/marshal"
@@ -46,6 +47,10 @@ func (p *policyMapper) AsStorePolicy(bytes []byte) ([]store.Policy, error) {
 }
 
 func (p *policyMapper) AsBytes(storePolicies []store.Policy) ([]byte, error) {
+	return p.AsBytesWithPagination(storePolicies, store.Pagination{})
+}
+
+func (p *policyMapper) AsBytesWithPagination(storePolicies []store.Policy, pagination store.Pagination) ([]byte, error) {
 	// convert store.Policy to api.Policy
 	apiPolicies := make([]Policy, len(storePolicies))
 	for i, policy := range storePolicies {
@@ -55,6 +60,7 @@ func (p *policyMapper) AsBytes(storePolicies []store.Policy) ([]byte, error) {
 	// convert api.Policy payload to bytes
 	payload := &PoliciesPayload{
 		TotalPolicies: len(apiPolicies),
+		Next:          pagination.Next,
 		Policies:      apiPolicies,
 	}
 
@@ -66,14 +72,41 @@ func (p *policyMapper) AsBytes(storePolicies []store.Policy) ([]byte, error) {
 }
 
 func (p *Policy) asStorePolicy() store.Policy {
+	ports := p.Destination.Ports
+	if p.Destination.Protocol == "all" {
+		ports = Ports{Start: 1, End: 65535}
+	}
 	port := 0
-	if p.Destination.Ports.Start == p.Destination.Ports.End {
-		port = p.Destination.Ports.Start
+	if ports.Start == ports.End {
+		port = ports.Start
 	}
+	metadata := store.Metadata{Description: p.Description}
+	if p.Metadata != nil {
+		metadata.Labels = p.Metadata.Labels
+		metadata.Annotations = p.Metadata.Annotations
+	}
+	icmpType, icmpCode := 0, 0
+	if p.Destination.Protocol == "icmp" {
+		icmpType, icmpCode = ICMPDefault, ICMPDefault
+		if p.Destination.ICMPType != nil {
+			icmpType = *p.Destination.ICMPType
+		}
+		if p.Destination.ICMPCode != nil {
+			icmpCode = *p.Destination.ICMPCode
+		}
+	}
+	var expiresAt *time.Time
+	if p.ExpiresAt != nil {
+		// expiries are stored with a precision of seconds
+		expiry := p.ExpiresAt.UTC().Truncate(time.Second)
+		expiresAt = &expiry
+	}
+
 	return store.Policy{
 		Source: store.Source{
-			ID:  p.Source.ID,
-			Tag: p.Source.Tag,
+			ID:   p.Source.ID,
+			Tag:  p.Source.Tag,
+			Type: p.Source.storeType(),
 		},
 		Destination: store.Destination{
 			ID:       p.Destination.ID,
@@ -81,31 +114,82 @@ func (p *Policy) asStorePolicy() store.Policy {
 			Protocol: p.Destination.Protocol,
 			Port:     port,
 			Ports: store.Ports{
-				Start: p.Destination.Ports.Start,
-				End:   p.Destination.Ports.End,
+				Start: ports.Start,
+				End:   ports.End,
 			},
+			ICMPType: icmpType,
+			ICMPCode: icmpCode,
 		},
+		Metadata:  metadata,
+		ExpiresAt: expiresAt,
+		Action:    p.storeAction(),
 	}
 }
 
+// storeAction drops the default allow action so that allow policies are
+// stored the same whether or not the action was given.
+func (p *Policy) storeAction() string {
+	if p.Action == store.PolicyActionAllow {
+		return ""
+	}
+	return p.Action
+}
+
+// storeType drops the "app" type so that app sources are stored the same
+// whether or not the type was given.
+func (s Source) storeType() string {
+	if s.Type == store.GroupTypeApp {
+		return ""
+	}
+	return s.Type
+}
+
 func mapStorePolicy(storePolicy store.Policy) Policy {
+	var metadata *Metadata
+	if len(storePolicy.Metadata.Labels) > 0 || len(storePolicy.Metadata.Annotations) > 0 {
+		metadata = &Metadata{
+			Labels:      nonNilMap(storePolicy.Metadata.Labels),
+			Annotations: nonNilMap(storePolicy.Metadata.Annotations),
+		}
+	}
+
+	destination := Destination{
+		ID:       storePolicy.Destination.ID,
+		Tag:      storePolicy.Destination.Tag,
+		Protocol: storePolicy.Destination.Protocol,
+		Ports: Ports{
+			Start: storePolicy.Destination.Ports.Start,
+			End:   storePolicy.Destination.Ports.End,
+		},
+	}
+	if storePolicy.Destination.Protocol == "icmp" {
+		icmpType := storePolicy.Destination.ICMPType
+		icmpCode := storePolicy.Destination.ICMPCode
+		destination.ICMPType = &icmpType
+		destination.ICMPCode = &icmpCode
+	}
+
 	return Policy{
 		Source: Source{
-			ID:  storePolicy.Source.ID,
-			Tag: storePolicy.Source.Tag,
-		},
-		Destination: Destination{
-			ID:       storePolicy.Destination.ID,
-			Tag:      storePolicy.Destination.Tag,
-			Protocol: storePolicy.Destination.Protocol,
-			Ports: Ports{
-				Start: storePolicy.Destination.Ports.Start,
-				End:   storePolicy.Destination.Ports.End,
-			},
+			ID:   storePolicy.Source.ID,
+			Tag:  storePolicy.Source.Tag,
+			Type: storePolicy.Source.Type,
 		},
+		Destination: destination,
+		Description: storePolicy.Metadata.Description,
+		Metadata:    metadata,
+		ExpiresAt:   storePolicy.ExpiresAt,
+		Action:      storePolicy.Action,
 	}
 }
 
+func nonNilMap(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
+
 func MapStoreTag(tag store.Tag) Tag {
 	return Tag{
 		ID:   tag.ID,
@@ -122,3 +206,126 @@ func MapStoreTags(tags []store.Tag) []Tag {
 	}
 	return apiTags
 }
+
+func MapStoreQuota(quota store.Quota) Quota {
+	return Quota{
+		Type:        quota.Type,
+		GUID:        quota.GUID,
+		MaxPolicies: quota.MaxPolicies,
+	}
+}
+
+func MapStoreQuotas(quotas []store.Quota) []Quota {
+	apiQuotas := []Quota{}
+
+	for _, quota := range quotas {
+		apiQuotas = append(apiQuotas, MapStoreQuota(quota))
+	}
+	return apiQuotas
+}
+
+func MapStorePolicies(storePolicies []store.Policy) []Policy {
+	apiPolicies := []Policy{}
+	for _, policy := range storePolicies {
+		apiPolicies = append(apiPolicies, mapStorePolicy(policy))
+	}
+	return apiPolicies
+}
+
+func MapStorePolicyOverlaps(overlaps []store.PolicyOverlap) []PolicyOverlap {
+	apiOverlaps := []PolicyOverlap{}
+
+	for _, overlap := range overlaps {
+		policies := []Policy{}
+		for _, policy := range overlap.Policies {
+			policies = append(policies, mapStorePolicy(policy))
+		}
+
+		apiOverlaps = append(apiOverlaps, PolicyOverlap{
+			Policies:          policies,
+			Merged:            mapStorePolicy(overlap.Merged),
+			MetadataConflicts: overlap.MetadataConflicts(),
+		})
+	}
+	return apiOverlaps
+}
+
+// MapStorePolicyManifest names the source and destination of a policy
+func MapStorePolicyManifest(storePolicy store.Policy, source, destination ManifestGroup) ManifestPolicy {
+	policy := mapStorePolicy(storePolicy)
+	return ManifestPolicy{
+		Source: source,
+		Destination: ManifestDestination{
+			ManifestGroup: destination,
+			Protocol:      policy.Destination.Protocol,
+			Ports:         policy.Destination.Ports,
+			ICMPType:      policy.Destination.ICMPType,
+			ICMPCode:      policy.Destination.ICMPCode,
+		},
+		Description: policy.Description,
+		Metadata:    policy.Metadata,
+		ExpiresAt:   policy.ExpiresAt,
+		Action:      policy.Action,
+	}
+}
+
+// AsPolicy returns the policy of the manifest for the resolved guids of its
+// source and destination
+func (p ManifestPolicy) AsPolicy(source Source, destinationID string) Policy {
+	return Policy{
+		Source: source,
+		Destination: Destination{
+			ID:       destinationID,
+			Protocol: p.Destination.Protocol,
+			Ports:    p.Destination.Ports,
+			ICMPType: p.Destination.ICMPType,
+			ICMPCode: p.Destination.ICMPCode,
+		},
+		Description: p.Description,
+		Metadata:    p.Metadata,
+		ExpiresAt:   p.ExpiresAt,
+		Action:      p.Action,
+	}
+}
+
+func MapStorePendingPolicies(pending []store.PendingPolicy) []PendingPolicy {
+	apiPending := []PendingPolicy{}
+
+	for _, p := range pending {
+		apiPending = append(apiPending, MapStorePendingPolicy(p))
+	}
+	return apiPending
+}
+
+func MapStorePendingPolicy(p store.PendingPolicy) PendingPolicy {
+	return PendingPolicy{
+		ID:                   p.ID,
+		Policy:               mapStorePolicy(p.Policy),
+		DestinationSpaceGUID: p.DestinationSpaceGUID,
+		RequestedBy:          p.RequestedBy,
+		ClientID:             p.ClientID,
+		Timestamp:            p.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func MapStorePolicyEvents(events []store.PolicyEvent) []PolicyEvent {
+	apiEvents := []PolicyEvent{}
+
+	for _, event := range events {
+		policies := []Policy{}
+		for _, policy := range event.Policies {
+			policies = append(policies, mapStorePolicy(policy))
+		}
+
+		apiEvents = append(apiEvents, PolicyEvent{
+			ID:         event.ID,
+			Action:     event.Action,
+			Actor:      event.Actor,
+			ClientID:   event.ClientID,
+			ApprovedBy: event.ApprovedBy,
+			Policies:   policies,
+			Timestamp:  event.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return apiEvents
+}
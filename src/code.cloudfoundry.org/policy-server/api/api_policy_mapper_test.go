@@ -3,6 +3,7 @@ package api_test
 import (
 	"encoding/json"
 	"errors"
+	"time"
 
 	hfakes "code.cloudfoundry.org/cf-networking-helpers/fakes"
 	"code.cloudfoundry.org/cf-networking-helpers/marshal"
@@ -112,6 +113,152 @@ var _ = Describe("ApiPolicyMapper", func() {
 			}))
 		})
 
+		Context("when the source has a type", func() {
+			It("maps the type, dropping the default app type", func() {
+				policies, err := mapper.AsStorePolicy(
+					[]byte(`{
+						"policies": [{
+							"source": { "id": "some-space-id", "type": "space" },
+							"destination": {
+								"id": "some-dst-id",
+								"protocol": "tcp",
+								"ports": { "start": 8080, "end": 8080 }
+							}
+						}, {
+							"source": { "id": "some-app-id", "type": "app" },
+							"destination": {
+								"id": "some-dst-id",
+								"protocol": "tcp",
+								"ports": { "start": 8080, "end": 8080 }
+							}
+						}]
+					}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies[0].Source).To(Equal(store.Source{ID: "some-space-id", Type: "space"}))
+				Expect(policies[1].Source).To(Equal(store.Source{ID: "some-app-id"}))
+			})
+		})
+
+		Context("when the policy has a description and metadata", func() {
+			It("maps them to the store metadata", func() {
+				policies, err := mapper.AsStorePolicy(
+					[]byte(`{
+						"policies": [{
+							"source": { "id": "some-src-id" },
+							"destination": {
+								"id": "some-dst-id",
+								"protocol": "tcp",
+								"ports": { "start": 8080, "end": 8080 }
+							},
+							"description": "some description",
+							"metadata": {
+								"labels": { "team": "orders" },
+								"annotations": { "ticket": "NET-123" }
+							}
+						}]
+					}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies[0].Metadata).To(Equal(store.Metadata{
+					Description: "some description",
+					Labels:      map[string]string{"team": "orders"},
+					Annotations: map[string]string{"ticket": "NET-123"},
+				}))
+			})
+		})
+
+		Context("when the policy has an expiry", func() {
+			It("maps it to UTC with a precision of seconds", func() {
+				policies, err := mapper.AsStorePolicy(
+					[]byte(`{
+						"policies": [{
+							"source": { "id": "some-src-id" },
+							"destination": {
+								"id": "some-dst-id",
+								"protocol": "tcp",
+								"ports": { "start": 8080, "end": 8080 }
+							},
+							"expires_at": "2030-01-02T05:04:05.678+02:00"
+						}]
+					}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies[0].ExpiresAt).NotTo(BeNil())
+				Expect(*policies[0].ExpiresAt).To(Equal(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)))
+			})
+		})
+
+		Context("when the policy is for icmp", func() {
+			It("maps the type and code, defaulting to any", func() {
+				policies, err := mapper.AsStorePolicy(
+					[]byte(`{
+						"policies": [{
+							"source": { "id": "some-src-id" },
+							"destination": { "id": "some-dst-id", "protocol": "icmp", "icmp_type": 8, "icmp_code": 0 }
+						}, {
+							"source": { "id": "some-src-id" },
+							"destination": { "id": "some-dst-id", "protocol": "icmp" }
+						}]
+					}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies[0].Destination).To(Equal(store.Destination{
+					ID:       "some-dst-id",
+					Protocol: "icmp",
+					ICMPType: 8,
+					ICMPCode: 0,
+				}))
+				Expect(policies[1].Destination).To(Equal(store.Destination{
+					ID:       "some-dst-id",
+					Protocol: "icmp",
+					ICMPType: -1,
+					ICMPCode: -1,
+				}))
+			})
+		})
+
+		Context("when the policy has an action", func() {
+			It("maps deny, dropping the default allow action", func() {
+				policies, err := mapper.AsStorePolicy(
+					[]byte(`{
+						"policies": [{
+							"source": { "id": "some-src-id" },
+							"destination": { "id": "some-dst-id", "protocol": "tcp", "ports": { "start": 8080, "end": 8080 } },
+							"action": "deny"
+						}, {
+							"source": { "id": "some-src-id" },
+							"destination": { "id": "some-dst-id", "protocol": "tcp", "ports": { "start": 9090, "end": 9090 } },
+							"action": "allow"
+						}]
+					}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies[0].Action).To(Equal("deny"))
+				Expect(policies[1].Action).To(BeEmpty())
+			})
+		})
+
+		Context("when the policy is for all protocols", func() {
+			It("maps it to a single destination covering every port", func() {
+				policies, err := mapper.AsStorePolicy(
+					[]byte(`{
+						"policies": [{
+							"source": { "id": "some-src-id" },
+							"destination": { "id": "some-dst-id", "protocol": "all" }
+						}]
+					}`),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies).To(HaveLen(1))
+				Expect(policies[0].Destination).To(Equal(store.Destination{
+					ID:       "some-dst-id",
+					Protocol: "all",
+					Ports:    store.Ports{Start: 1, End: 65535},
+				}))
+			})
+		})
+
 		Context("when unmarshalling fails", func() {
 			BeforeEach(func() {
 				fakeUnmarshaler.UnmarshalReturns(errors.New("banana"))
@@ -233,6 +380,197 @@ var _ = Describe("ApiPolicyMapper", func() {
 				}`)))
 			})
 		})
+		Context("when the policy has a description and metadata", func() {
+			It("includes them in the payload", func() {
+				payload, err := mapper.AsBytes([]store.Policy{
+					{
+						Source: store.Source{ID: "some-src-id"},
+						Destination: store.Destination{
+							ID:       "some-dst-id",
+							Protocol: "tcp",
+							Ports:    store.Ports{Start: 8080, End: 8080},
+						},
+						Metadata: store.Metadata{
+							Description: "some description",
+							Labels:      map[string]string{"team": "orders"},
+						},
+					},
+					{
+						Source: store.Source{ID: "some-src-id"},
+						Destination: store.Destination{
+							ID:       "some-other-dst-id",
+							Protocol: "tcp",
+							Ports:    store.Ports{Start: 8080, End: 8080},
+						},
+						Metadata: store.Metadata{Description: "only a description"},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload).To(MatchJSON([]byte(`{
+					"total_policies": 2,
+					"policies": [
+						{
+							"source": { "id": "some-src-id" },
+							"destination": {
+								"id": "some-dst-id",
+								"protocol": "tcp",
+								"ports": { "start": 8080, "end": 8080 }
+							},
+							"description": "some description",
+							"metadata": {
+								"labels": { "team": "orders" },
+								"annotations": {}
+							}
+						},
+						{
+							"source": { "id": "some-src-id" },
+							"destination": {
+								"id": "some-other-dst-id",
+								"protocol": "tcp",
+								"ports": { "start": 8080, "end": 8080 }
+							},
+							"description": "only a description"
+						}
+					]
+				}`)))
+			})
+		})
+
+		Context("when the policy source is a space", func() {
+			It("includes the source type", func() {
+				payload, err := mapper.AsBytes([]store.Policy{
+					{
+						Source: store.Source{ID: "some-space-id", Tag: "some-tag", Type: "space"},
+						Destination: store.Destination{
+							ID:       "some-dst-id",
+							Protocol: "tcp",
+							Ports: store.Ports{
+								Start: 8080,
+								End:   8080,
+							},
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload).To(MatchJSON([]byte(`{
+					"total_policies": 1,
+					"policies": [
+						{
+							"source": { "id": "some-space-id", "tag": "some-tag", "type": "space" },
+							"destination": {
+								"id": "some-dst-id",
+								"protocol": "tcp",
+								"ports": {
+									"start": 8080,
+									"end": 8080
+								}
+							}
+						}
+					]
+				}`)))
+			})
+		})
+		Context("when the policy denies traffic", func() {
+			It("includes the action", func() {
+				payload, err := mapper.AsBytes([]store.Policy{
+					{
+						Source: store.Source{ID: "some-src-id"},
+						Destination: store.Destination{
+							ID:       "some-dst-id",
+							Protocol: "tcp",
+							Ports:    store.Ports{Start: 8080, End: 8080},
+						},
+						Action: store.PolicyActionDeny,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload).To(MatchJSON([]byte(`{
+					"total_policies": 1,
+					"policies": [
+						{
+							"source": { "id": "some-src-id" },
+							"destination": {
+								"id": "some-dst-id",
+								"protocol": "tcp",
+								"ports": { "start": 8080, "end": 8080 }
+							},
+							"action": "deny"
+						}
+					]
+				}`)))
+			})
+		})
+
+		Context("when the policy is for icmp", func() {
+			It("includes the type and code", func() {
+				payload, err := mapper.AsBytes([]store.Policy{
+					{
+						Source: store.Source{ID: "some-src-id"},
+						Destination: store.Destination{
+							ID:       "some-dst-id",
+							Protocol: "icmp",
+							ICMPType: 3,
+							ICMPCode: -1,
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload).To(MatchJSON([]byte(`{
+					"total_policies": 1,
+					"policies": [
+						{
+							"source": { "id": "some-src-id" },
+							"destination": {
+								"id": "some-dst-id",
+								"protocol": "icmp",
+								"ports": { "start": 0, "end": 0 },
+								"icmp_type": 3,
+								"icmp_code": -1
+							}
+						}
+					]
+				}`)))
+			})
+		})
+
+		Context("when the policy has an expiry", func() {
+			It("includes it in the payload", func() {
+				expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+				payload, err := mapper.AsBytes([]store.Policy{
+					{
+						Source: store.Source{ID: "some-src-id", Tag: "some-tag"},
+						Destination: store.Destination{
+							ID:       "some-dst-id",
+							Protocol: "tcp",
+							Ports: store.Ports{
+								Start: 8080,
+								End:   8080,
+							},
+						},
+						ExpiresAt: &expiresAt,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload).To(MatchJSON([]byte(`{
+					"total_policies": 1,
+					"policies": [
+						{
+							"source": { "id": "some-src-id", "tag": "some-tag" },
+							"destination": {
+								"id": "some-dst-id",
+								"protocol": "tcp",
+								"ports": {
+									"start": 8080,
+									"end": 8080
+								}
+							},
+							"expires_at": "2030-01-02T03:04:05Z"
+						}
+					]
+				}`)))
+			})
+		})
+
 		Context("when marshalling fails", func() {
 			BeforeEach(func() {
 				fakeMarshaler.MarshalReturns(nil, errors.New("banana"))
@@ -281,6 +619,21 @@ var _ = Describe("ApiPolicyMapper", func() {
 		)
 	})
 
+	Describe("MapStoreQuotas", func() {
+		It("maps store quotas to api quotas", func() {
+			result := api.MapStoreQuotas([]store.Quota{
+				{Type: "space", GUID: "some-space-guid", MaxPolicies: 10},
+			})
+			Expect(result).To(Equal([]api.Quota{
+				{Type: "space", GUID: "some-space-guid", MaxPolicies: 10},
+			}))
+		})
+
+		It("returns an empty list when there are no quotas", func() {
+			Expect(api.MapStoreQuotas(nil)).To(Equal([]api.Quota{}))
+		})
+	})
+
 	Describe("MapStoreTags", func() {
 		DescribeTable("should map store tags to api tags", func(input []store.Tag, expected []api.Tag) {
 			result := api.MapStoreTags(input)
@@ -322,4 +675,277 @@ var _ = Describe("ApiPolicyMapper", func() {
 			),
 		)
 	})
+
+	Describe("MapStorePolicies", func() {
+		It("maps store policies to api policies", func() {
+			result := api.MapStorePolicies([]store.Policy{{
+				Source: store.Source{ID: "some-space-id", Type: "space"},
+				Destination: store.Destination{
+					ID:       "some-dst-id",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+				Metadata: store.Metadata{Description: "some description"},
+			}})
+
+			Expect(result).To(Equal([]api.Policy{{
+				Source: api.Source{ID: "some-space-id", Type: "space"},
+				Destination: api.Destination{
+					ID:       "some-dst-id",
+					Protocol: "tcp",
+					Ports:    api.Ports{Start: 8080, End: 8080},
+				},
+				Description: "some description",
+			}}))
+		})
+
+		It("returns an empty list when there are no policies", func() {
+			Expect(api.MapStorePolicies(nil)).To(Equal([]api.Policy{}))
+		})
+	})
+
+	Describe("MapStorePolicyOverlaps", func() {
+		It("maps store policy overlaps to api policy overlaps", func() {
+			storePolicy := func(start, end int) store.Policy {
+				return store.Policy{
+					Source: store.Source{ID: "some-src-id"},
+					Destination: store.Destination{
+						ID:       "some-dst-id",
+						Protocol: "tcp",
+						Ports:    store.Ports{Start: start, End: end},
+					},
+				}
+			}
+			apiPolicy := func(start, end int) api.Policy {
+				return api.Policy{
+					Source: api.Source{ID: "some-src-id"},
+					Destination: api.Destination{
+						ID:       "some-dst-id",
+						Protocol: "tcp",
+						Ports:    api.Ports{Start: start, End: end},
+					},
+				}
+			}
+
+			result := api.MapStorePolicyOverlaps([]store.PolicyOverlap{{
+				Policies: []store.Policy{storePolicy(8080, 8090), storePolicy(8085, 9000)},
+				Merged:   storePolicy(8080, 9000),
+			}})
+
+			Expect(result).To(Equal([]api.PolicyOverlap{{
+				Policies: []api.Policy{apiPolicy(8080, 8090), apiPolicy(8085, 9000)},
+				Merged:   apiPolicy(8080, 9000),
+			}}))
+		})
+
+		It("reports overlaps whose policies have different metadata", func() {
+			overlaps := api.MapStorePolicyOverlaps([]store.PolicyOverlap{{
+				Policies: []store.Policy{
+					{Metadata: store.Metadata{Description: "first"}},
+					{Metadata: store.Metadata{Description: "second"}},
+				},
+				Merged: store.Policy{Metadata: store.Metadata{Description: "first"}},
+			}})
+
+			Expect(overlaps).To(HaveLen(1))
+			Expect(overlaps[0].MetadataConflicts).To(BeTrue())
+		})
+
+		It("returns an empty list when there are no overlaps", func() {
+			Expect(api.MapStorePolicyOverlaps(nil)).To(Equal([]api.PolicyOverlap{}))
+		})
+	})
+
+	Describe("MapStorePolicyManifest", func() {
+		It("names the source and destination of the policy", func() {
+			expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+			storePolicy := store.Policy{
+				Source: store.Source{ID: "some-space-guid", Type: "space"},
+				Destination: store.Destination{
+					ID:       "some-dst-id",
+					Protocol: "icmp",
+					ICMPType: 8,
+					ICMPCode: -1,
+				},
+				Metadata: store.Metadata{
+					Description: "some description",
+					Labels:      map[string]string{"team": "payments"},
+				},
+				ExpiresAt: &expiry,
+				Action:    store.PolicyActionDeny,
+			}
+			source := api.ManifestGroup{Org: "some-org", Space: "some-space"}
+			destination := api.ManifestGroup{Org: "some-org", Space: "other-space", App: "some-app"}
+
+			result := api.MapStorePolicyManifest(storePolicy, source, destination)
+
+			icmpType, icmpCode := 8, -1
+			Expect(result).To(Equal(api.ManifestPolicy{
+				Source: source,
+				Destination: api.ManifestDestination{
+					ManifestGroup: destination,
+					Protocol:      "icmp",
+					ICMPType:      &icmpType,
+					ICMPCode:      &icmpCode,
+				},
+				Description: "some description",
+				Metadata: &api.Metadata{
+					Labels:      map[string]string{"team": "payments"},
+					Annotations: map[string]string{},
+				},
+				ExpiresAt: &expiry,
+				Action:    "deny",
+			}))
+		})
+	})
+
+	Describe("ManifestPolicy.AsPolicy", func() {
+		It("returns the policy for the given guids", func() {
+			manifestPolicy := api.ManifestPolicy{
+				Source: api.ManifestGroup{Org: "some-org", Space: "some-space", App: "some-app"},
+				Destination: api.ManifestDestination{
+					ManifestGroup: api.ManifestGroup{Org: "some-org", Space: "some-space", App: "other-app"},
+					Protocol:      "tcp",
+					Ports:         api.Ports{Start: 8080, End: 8090},
+				},
+				Description: "some description",
+			}
+
+			Expect(manifestPolicy.AsPolicy(api.Source{ID: "some-app-guid"}, "other-app-guid")).To(Equal(api.Policy{
+				Source: api.Source{ID: "some-app-guid"},
+				Destination: api.Destination{
+					ID:       "other-app-guid",
+					Protocol: "tcp",
+					Ports:    api.Ports{Start: 8080, End: 8090},
+				},
+				Description: "some description",
+			}))
+		})
+	})
+
+	Describe("MapStorePolicyEvents", func() {
+		It("maps store policy events to api policy events", func() {
+			result := api.MapStorePolicyEvents([]store.PolicyEvent{{
+				ID:         7,
+				Action:     store.PolicyEventCreate,
+				Actor:      "some-user",
+				ClientID:   "some-client",
+				ApprovedBy: "some-approver",
+				Policies: []store.Policy{{
+					Source: store.Source{ID: "some-src-id"},
+					Destination: store.Destination{
+						ID:       "some-dst-id",
+						Protocol: "tcp",
+						Ports:    store.Ports{Start: 8080, End: 8081},
+					},
+				}},
+				CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("some-zone", 3600)),
+			}})
+
+			Expect(result).To(Equal([]api.PolicyEvent{{
+				ID:         7,
+				Action:     "create",
+				Actor:      "some-user",
+				ClientID:   "some-client",
+				ApprovedBy: "some-approver",
+				Policies: []api.Policy{{
+					Source: api.Source{ID: "some-src-id"},
+					Destination: api.Destination{
+						ID:       "some-dst-id",
+						Protocol: "tcp",
+						Ports:    api.Ports{Start: 8080, End: 8081},
+					},
+				}},
+				Timestamp: "2026-01-02T02:04:05Z",
+			}}))
+		})
+
+		It("returns an empty list when there are no events", func() {
+			Expect(api.MapStorePolicyEvents(nil)).To(Equal([]api.PolicyEvent{}))
+		})
+	})
+
+	Describe("MapStorePendingPolicies", func() {
+		It("maps store pending policies to api pending policies", func() {
+			result := api.MapStorePendingPolicies([]store.PendingPolicy{{
+				ID: 3,
+				Policy: store.Policy{
+					Source: store.Source{ID: "some-src-id"},
+					Destination: store.Destination{
+						ID:       "some-dst-id",
+						Protocol: "tcp",
+						Ports:    store.Ports{Start: 8080, End: 8080},
+					},
+				},
+				DestinationSpaceGUID: "some-space-guid",
+				RequestedBy:          "some-user",
+				ClientID:             "some-client",
+				CreatedAt:            time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			}})
+
+			Expect(result).To(Equal([]api.PendingPolicy{{
+				ID: 3,
+				Policy: api.Policy{
+					Source: api.Source{ID: "some-src-id"},
+					Destination: api.Destination{
+						ID:       "some-dst-id",
+						Protocol: "tcp",
+						Ports:    api.Ports{Start: 8080, End: 8080},
+					},
+				},
+				DestinationSpaceGUID: "some-space-guid",
+				RequestedBy:          "some-user",
+				ClientID:             "some-client",
+				Timestamp:            "2026-01-02T03:04:05Z",
+			}}))
+		})
+
+		It("returns an empty list when there are no pending policies", func() {
+			Expect(api.MapStorePendingPolicies(nil)).To(Equal([]api.PendingPolicy{}))
+		})
+	})
+
+	Describe("AsBytesWithPagination", func() {
+		It("includes the next cursor in the payload", func() {
+			payload, err := mapper.AsBytesWithPagination([]store.Policy{
+				{
+					Source: store.Source{ID: "some-src-id"},
+					Destination: store.Destination{
+						ID:       "some-dst-id",
+						Protocol: "some-protocol",
+						Ports: store.Ports{
+							Start: 8080,
+							End:   8080,
+						},
+					},
+				},
+			}, store.Pagination{Next: 42})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(payload).To(MatchJSON([]byte(`{
+				"total_policies": 1,
+				"next": 42,
+				"policies": [
+					{
+						"source": { "id": "some-src-id" },
+						"destination": {
+							"id": "some-dst-id",
+							"protocol": "some-protocol",
+							"ports": {
+								"start": 8080,
+								"end": 8080
+							}
+						}
+					}
+				]
+			}`)))
+		})
+
+		Context("when there is no next page", func() {
+			It("omits the next field", func() {
+				payload, err := mapper.AsBytesWithPagination([]store.Policy{}, store.Pagination{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload).To(MatchJSON([]byte(`{ "total_policies": 0, "policies": [] }`)))
+			})
+		})
+	})
 })
@@ -43,6 +43,10 @@ func (p *policyMapper) AsStorePolicy(bytes []byte) ([]store.Policy, error) {
 }
 
 func (p *policyMapper) AsBytes(storePolicies []store.Policy) ([]byte, error) {
+	return p.AsBytesWithPagination(storePolicies, store.Pagination{})
+}
+
+func (p *policyMapper) AsBytesWithPagination(storePolicies []store.Policy, pagination store.Pagination) ([]byte, error) {
 	// convert store.Policy to api_v0.Policy
 	apiPolicies := []Policy{}
 	for _, policy := range storePolicies {
@@ -55,6 +59,7 @@ func (p *policyMapper) AsBytes(storePolicies []store.Policy) ([]byte, error) {
 	// convert api_v0.Policy payload to bytes
 	payload := &Policies{
 		TotalPolicies: len(apiPolicies),
+		Next:          pagination.Next,
 		Policies:      apiPolicies,
 	}
 	bytes, err := p.Marshaler.Marshal(payload)
@@ -87,6 +92,18 @@ func mapStorePolicy(storePolicy store.Policy) (Policy, bool) {
 	if storePolicy.Destination.Ports.Start != storePolicy.Destination.Ports.End {
 		return Policy{}, false
 	}
+	// v0 has no source type, so space and org policies would look like app policies
+	if storePolicy.Source.Type != "" {
+		return Policy{}, false
+	}
+	// v0 has no deny policies, which would be read as allowing the traffic
+	if storePolicy.IsDeny() {
+		return Policy{}, false
+	}
+	// v0 has no icmp type and code, and no policies for all protocols
+	if storePolicy.Destination.Protocol == "icmp" || storePolicy.Destination.Protocol == "all" {
+		return Policy{}, false
+	}
 	return Policy{
 		Source: Source{
 			ID:  storePolicy.Source.ID,
@@ -268,6 +268,80 @@ var _ = Describe("ApiPolicyMapper v000", func() {
 				Expect(payload).To(MatchJSON([]byte(`{ "total_policies": 0, "policies": [] }`)))
 			})
 		})
+		Context("when the source is not an app", func() {
+			It("ignores a store.Policy that cannot be mapped to an api.Policy", func() {
+				payload, err := mapper.AsBytes([]store.Policy{
+					{
+						Source: store.Source{ID: "some-space-id", Type: "space"},
+						Destination: store.Destination{
+							ID:       "some-dst-id",
+							Protocol: "some-protocol",
+							Ports: store.Ports{
+								Start: 8080,
+								End:   8080,
+							},
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload).To(MatchJSON([]byte(`{ "total_policies": 0, "policies": [] }`)))
+			})
+		})
+
+		Context("when the policy denies traffic", func() {
+			It("ignores a store.Policy that cannot be mapped to an api.Policy", func() {
+				payload, err := mapper.AsBytes([]store.Policy{
+					{
+						Source: store.Source{ID: "some-src-id"},
+						Destination: store.Destination{
+							ID:       "some-dst-id",
+							Protocol: "tcp",
+							Port:     8080,
+							Ports:    store.Ports{Start: 8080, End: 8080},
+						},
+						Action: store.PolicyActionDeny,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload).To(MatchJSON([]byte(`{ "total_policies": 0, "policies": [] }`)))
+			})
+		})
+
+		Context("when the policy is for icmp", func() {
+			It("ignores a store.Policy that cannot be mapped to an api.Policy", func() {
+				payload, err := mapper.AsBytes([]store.Policy{
+					{
+						Source: store.Source{ID: "some-src-id"},
+						Destination: store.Destination{
+							ID:       "some-dst-id",
+							Protocol: "icmp",
+							ICMPType: 8,
+							ICMPCode: 0,
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload).To(MatchJSON([]byte(`{ "total_policies": 0, "policies": [] }`)))
+			})
+		})
+
+		Context("when the policy is for all protocols", func() {
+			It("ignores a store.Policy that cannot be mapped to an api.Policy", func() {
+				payload, err := mapper.AsBytes([]store.Policy{
+					{
+						Source: store.Source{ID: "some-src-id"},
+						Destination: store.Destination{
+							ID:       "some-dst-id",
+							Protocol: "all",
+							Ports:    store.Ports{Start: 1, End: 65535},
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload).To(MatchJSON([]byte(`{ "total_policies": 0, "policies": [] }`)))
+			})
+		})
+
 		Context("when marshalling fails", func() {
 			BeforeEach(func() {
 				fakeMarshaler.MarshalReturns(nil, errors.New("banana"))
@@ -283,4 +357,45 @@ var _ = Describe("ApiPolicyMapper v000", func() {
 			})
 		})
 	})
+
+	Describe("AsBytesWithPagination", func() {
+		It("includes the next cursor in the payload", func() {
+			payload, err := mapper.AsBytesWithPagination([]store.Policy{
+				{
+					Source: store.Source{ID: "some-src-id"},
+					Destination: store.Destination{
+						ID:       "some-dst-id",
+						Protocol: "some-protocol",
+						Ports: store.Ports{
+							Start: 8080,
+							End:   8080,
+						},
+					},
+				},
+			}, store.Pagination{Next: 42})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(payload).To(MatchJSON([]byte(`{
+				"total_policies": 1,
+				"next": 42,
+				"policies": [
+					{
+						"source": { "id": "some-src-id" },
+						"destination": {
+							"id": "some-dst-id",
+							"protocol": "some-protocol",
+							"port": 8080
+						}
+					}
+				]
+			}`)))
+		})
+
+		Context("when there is no next page", func() {
+			It("omits the next field", func() {
+				payload, err := mapper.AsBytesWithPagination([]store.Policy{}, store.Pagination{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload).To(MatchJSON([]byte(`{ "total_policies": 0, "policies": [] }`)))
+			})
+		})
+	})
 })
@@ -2,6 +2,7 @@ package api_v0_internal
 
 type Policies struct {
 	TotalPolicies int      `json:"total_policies"`
+	Next          int      `json:"next,omitempty"`
 	Policies      []Policy `json:"policies"`
 }
 
@@ -26,6 +26,10 @@ func (p *policyMapper) AsStorePolicy(bytes []byte) ([]store.Policy, error) {
 }
 
 func (p *policyMapper) AsBytes(storePolicies []store.Policy) ([]byte, error) {
+	return p.AsBytesWithPagination(storePolicies, store.Pagination{})
+}
+
+func (p *policyMapper) AsBytesWithPagination(storePolicies []store.Policy, pagination store.Pagination) ([]byte, error) {
 	// convert store.Policy to api_v0_internal.Policy
 	apiPolicies := []Policy{}
 	for _, policy := range storePolicies {
@@ -38,6 +42,7 @@ func (p *policyMapper) AsBytes(storePolicies []store.Policy) ([]byte, error) {
 	// convert api_v0_internal.Policy payload to bytes
 	payload := &Policies{
 		TotalPolicies: len(apiPolicies),
+		Next:          pagination.Next,
 		Policies:      apiPolicies,
 	}
 	bytes, err := p.Marshaler.Marshal(payload)
@@ -51,6 +56,14 @@ func mapStorePolicy(storePolicy store.Policy) (Policy, bool) {
 	if storePolicy.Destination.Ports.Start != storePolicy.Destination.Ports.End {
 		return Policy{}, false
 	}
+	// v0 has no deny policies, which would be read as allowing the traffic
+	if storePolicy.IsDeny() {
+		return Policy{}, false
+	}
+	// v0 has no icmp type and code
+	if storePolicy.Destination.Protocol == "icmp" {
+		return Policy{}, false
+	}
 	return Policy{
 		Source: Source{
 			ID:  storePolicy.Source.ID,
@@ -167,6 +167,42 @@ var _ = Describe("ApiPolicyMapper v000", func() {
 				Expect(payload).To(MatchJSON([]byte(`{ "total_policies": 0, "policies": [] }`)))
 			})
 		})
+		Context("when the policy denies traffic", func() {
+			It("ignores a store.Policy that cannot be mapped to an api.Policy", func() {
+				payload, err := mapper.AsBytes([]store.Policy{
+					{
+						Source: store.Source{ID: "some-src-id"},
+						Destination: store.Destination{
+							ID:       "some-dst-id",
+							Protocol: "tcp",
+							Port:     8080,
+							Ports:    store.Ports{Start: 8080, End: 8080},
+						},
+						Action: store.PolicyActionDeny,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload).To(MatchJSON([]byte(`{ "total_policies": 0, "policies": [] }`)))
+			})
+		})
+
+		Context("when the policy is for icmp", func() {
+			It("ignores a store.Policy that cannot be mapped to an api.Policy", func() {
+				payload, err := mapper.AsBytes([]store.Policy{
+					{
+						Source: store.Source{ID: "some-src-id"},
+						Destination: store.Destination{
+							ID:       "some-dst-id",
+							Protocol: "icmp",
+							ICMPType: 8,
+							ICMPCode: 0,
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload).To(MatchJSON([]byte(`{ "total_policies": 0, "policies": [] }`)))
+			})
+		})
 		Context("when marshalling fails", func() {
 			BeforeEach(func() {
 				fakeMarshaler.MarshalReturns(nil, errors.New("banana"))
@@ -22,6 +22,20 @@ type PolicyMapper struct {
 		result1 []byte
 		result2 error
 	}
+	AsBytesWithPaginationStub        func([]store.Policy, store.Pagination) ([]byte, error)
+	asBytesWithPaginationMutex       sync.RWMutex
+	asBytesWithPaginationArgsForCall []struct {
+		arg1 []store.Policy
+		arg2 store.Pagination
+	}
+	asBytesWithPaginationReturns struct {
+		result1 []byte
+		result2 error
+	}
+	asBytesWithPaginationReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	AsStorePolicyStub        func([]byte) ([]store.Policy, error)
 	asStorePolicyMutex       sync.RWMutex
 	asStorePolicyArgsForCall []struct {
@@ -108,6 +122,76 @@ func (fake *PolicyMapper) AsBytesReturnsOnCall(i int, result1 []byte, result2 er
 	}{result1, result2}
 }
 
+func (fake *PolicyMapper) AsBytesWithPagination(arg1 []store.Policy, arg2 store.Pagination) ([]byte, error) {
+	var arg1Copy []store.Policy
+	if arg1 != nil {
+		arg1Copy = make([]store.Policy, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.asBytesWithPaginationMutex.Lock()
+	ret, specificReturn := fake.asBytesWithPaginationReturnsOnCall[len(fake.asBytesWithPaginationArgsForCall)]
+	fake.asBytesWithPaginationArgsForCall = append(fake.asBytesWithPaginationArgsForCall, struct {
+		arg1 []store.Policy
+		arg2 store.Pagination
+	}{arg1Copy, arg2})
+	stub := fake.AsBytesWithPaginationStub
+	fakeReturns := fake.asBytesWithPaginationReturns
+	fake.recordInvocation("AsBytesWithPagination", []interface{}{arg1Copy, arg2})
+	fake.asBytesWithPaginationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PolicyMapper) AsBytesWithPaginationCallCount() int {
+	fake.asBytesWithPaginationMutex.RLock()
+	defer fake.asBytesWithPaginationMutex.RUnlock()
+	return len(fake.asBytesWithPaginationArgsForCall)
+}
+
+func (fake *PolicyMapper) AsBytesWithPaginationCalls(stub func([]store.Policy, store.Pagination) ([]byte, error)) {
+	fake.asBytesWithPaginationMutex.Lock()
+	defer fake.asBytesWithPaginationMutex.Unlock()
+	fake.AsBytesWithPaginationStub = stub
+}
+
+func (fake *PolicyMapper) AsBytesWithPaginationArgsForCall(i int) ([]store.Policy, store.Pagination) {
+	fake.asBytesWithPaginationMutex.RLock()
+	defer fake.asBytesWithPaginationMutex.RUnlock()
+	argsForCall := fake.asBytesWithPaginationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PolicyMapper) AsBytesWithPaginationReturns(result1 []byte, result2 error) {
+	fake.asBytesWithPaginationMutex.Lock()
+	defer fake.asBytesWithPaginationMutex.Unlock()
+	fake.AsBytesWithPaginationStub = nil
+	fake.asBytesWithPaginationReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyMapper) AsBytesWithPaginationReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.asBytesWithPaginationMutex.Lock()
+	defer fake.asBytesWithPaginationMutex.Unlock()
+	fake.AsBytesWithPaginationStub = nil
+	if fake.asBytesWithPaginationReturnsOnCall == nil {
+		fake.asBytesWithPaginationReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.asBytesWithPaginationReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *PolicyMapper) AsStorePolicy(arg1 []byte) ([]store.Policy, error) {
 	var arg1Copy []byte
 	if arg1 != nil {
@@ -182,6 +266,8 @@ func (fake *PolicyMapper) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.asBytesMutex.RLock()
 	defer fake.asBytesMutex.RUnlock()
+	fake.asBytesWithPaginationMutex.RLock()
+	defer fake.asBytesWithPaginationMutex.RUnlock()
 	fake.asStorePolicyMutex.RLock()
 	defer fake.asStorePolicyMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
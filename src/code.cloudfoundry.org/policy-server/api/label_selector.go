@@ -0,0 +1,163 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	selectorEquals    = "="
+	selectorNotEquals = "!="
+	selectorIn        = "in"
+	selectorNotIn     = "notin"
+	selectorExists    = "exists"
+	selectorNotExists = "!exists"
+)
+
+// LabelSelector is a parsed label_selector query parameter. It follows the
+// Cloud Controller v3 syntax: a comma separated list of requirements that
+// must all match, each one of "key=value", "key==value", "key!=value",
+// "key in (v1,v2)", "key notin (v1,v2)", "key" or "!key".
+type LabelSelector []labelRequirement
+
+type labelRequirement struct {
+	key      string
+	operator string
+	values   []string
+}
+
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	if strings.TrimSpace(selector) == "" {
+		return nil, errors.New("label selector may not be empty")
+	}
+
+	var requirements LabelSelector
+	for _, part := range splitRequirements(selector) {
+		requirement, err := parseRequirement(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %s", part, err)
+		}
+		requirements = append(requirements, requirement)
+	}
+	return requirements, nil
+}
+
+// Matches returns true when the labels satisfy every requirement.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r labelRequirement) matches(labels map[string]string) bool {
+	value, ok := labels[r.key]
+	switch r.operator {
+	case selectorEquals:
+		return ok && value == r.values[0]
+	case selectorNotEquals:
+		return !ok || value != r.values[0]
+	case selectorIn:
+		return ok && containsString(r.values, value)
+	case selectorNotIn:
+		return !ok || !containsString(r.values, value)
+	case selectorExists:
+		return ok
+	case selectorNotExists:
+		return !ok
+	}
+	return false
+}
+
+// splitRequirements splits on the commas that are not inside a set of values
+func splitRequirements(selector string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, selector[start:])
+}
+
+func parseRequirement(requirement string) (labelRequirement, error) {
+	if key, values, ok := splitSetRequirement(requirement, " notin "); ok {
+		return newSetRequirement(key, selectorNotIn, values)
+	}
+	if key, values, ok := splitSetRequirement(requirement, " in "); ok {
+		return newSetRequirement(key, selectorIn, values)
+	}
+
+	for _, operator := range []string{"!=", "==", "="} {
+		if i := strings.Index(requirement, operator); i >= 0 {
+			key := strings.TrimSpace(requirement[:i])
+			value := strings.TrimSpace(requirement[i+len(operator):])
+			op := selectorEquals
+			if operator == "!=" {
+				op = selectorNotEquals
+			}
+			return newRequirement(key, op, []string{value})
+		}
+	}
+
+	if strings.HasPrefix(requirement, "!") {
+		return newRequirement(strings.TrimSpace(requirement[1:]), selectorNotExists, nil)
+	}
+	return newRequirement(requirement, selectorExists, nil)
+}
+
+func splitSetRequirement(requirement, operator string) (string, string, bool) {
+	i := strings.Index(requirement, operator)
+	if i < 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(requirement[:i]), strings.TrimSpace(requirement[i+len(operator):]), true
+}
+
+func newSetRequirement(key, operator, values string) (labelRequirement, error) {
+	if !strings.HasPrefix(values, "(") || !strings.HasSuffix(values, ")") {
+		return labelRequirement{}, errors.New("values must be wrapped in parentheses")
+	}
+
+	var parsed []string
+	for _, value := range strings.Split(values[1:len(values)-1], ",") {
+		parsed = append(parsed, strings.TrimSpace(value))
+	}
+	return newRequirement(key, operator, parsed)
+}
+
+func newRequirement(key, operator string, values []string) (labelRequirement, error) {
+	err := validateMetadataKey(key)
+	if err != nil {
+		return labelRequirement{}, fmt.Errorf("key %s", err)
+	}
+	for _, value := range values {
+		err = validateLabelValue(value)
+		if err != nil {
+			return labelRequirement{}, fmt.Errorf("value %s", err)
+		}
+	}
+	return labelRequirement{key: key, operator: operator, values: values}, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
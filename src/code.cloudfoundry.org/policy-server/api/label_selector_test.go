@@ -0,0 +1,66 @@
+package api_test
+
+import (
+	"code.cloudfoundry.org/policy-server/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LabelSelector", func() {
+	labels := map[string]string{
+		"team":                "orders",
+		"env":                 "prod",
+		"example.com/tier":    "backend",
+		"no-value-label":      "",
+		"another-team-member": "bob",
+	}
+
+	DescribeTable("matching labels",
+		func(selector string, expected bool) {
+			labelSelector, err := api.ParseLabelSelector(selector)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(labelSelector.Matches(labels)).To(Equal(expected))
+		},
+		Entry("equals", "team=orders", true),
+		Entry("double equals", "team==orders", true),
+		Entry("equals with a different value", "team=payments", false),
+		Entry("not equals", "team!=payments", true),
+		Entry("not equals with the same value", "team!=orders", false),
+		Entry("not equals with a missing key", "missing!=orders", true),
+		Entry("in", "env in (dev,prod)", true),
+		Entry("in without the value", "env in (dev,staging)", false),
+		Entry("notin", "env notin (dev,staging)", true),
+		Entry("notin with the value", "env notin (dev, prod)", false),
+		Entry("exists", "team", true),
+		Entry("exists with an empty value", "no-value-label", true),
+		Entry("exists with a missing key", "missing", false),
+		Entry("not exists", "!missing", true),
+		Entry("not exists with an existing key", "!team", false),
+		Entry("prefixed keys", "example.com/tier=backend", true),
+		Entry("multiple requirements", "team=orders,env in (dev,prod),!missing", true),
+		Entry("multiple requirements with one failing", "team=orders,env notin (dev,prod)", false),
+	)
+
+	It("does not match policies without labels unless every requirement allows it", func() {
+		labelSelector, err := api.ParseLabelSelector("team=orders")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(labelSelector.Matches(nil)).To(BeFalse())
+
+		labelSelector, err = api.ParseLabelSelector("!team,env!=prod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(labelSelector.Matches(nil)).To(BeTrue())
+	})
+
+	DescribeTable("invalid selectors",
+		func(selector, expectedError string) {
+			_, err := api.ParseLabelSelector(selector)
+			Expect(err).To(MatchError(ContainSubstring(expectedError)))
+		},
+		Entry("empty", "", "label selector may not be empty"),
+		Entry("empty requirement", "team=orders,", `invalid label selector "": key name must be`),
+		Entry("invalid key", "-team=orders", `invalid label selector "-team=orders": key name must be`),
+		Entry("invalid prefix", "Example.com/tier=backend", "key prefix must be a DNS subdomain"),
+		Entry("invalid value", "team=orders!", "value must be at most 63"),
+		Entry("set without parentheses", "env in dev,prod", "values must be wrapped in parentheses"),
+	)
+})
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// These limits follow the Cloud Controller v3 metadata conventions.
+const (
+	maxDescriptionLength     = 255
+	maxMetadataPrefixLength  = 253
+	maxMetadataNameLength    = 63
+	maxLabelValueLength      = 63
+	maxAnnotationValueLength = 5000
+)
+
+var (
+	metadataNamePattern   = regexp.MustCompile(`^[A-Za-z0-9]([-_.A-Za-z0-9]*[A-Za-z0-9])?$`)
+	metadataPrefixPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+func validateMetadata(description string, metadata *Metadata) error {
+	if len(description) > maxDescriptionLength {
+		return fmt.Errorf("invalid description, must be at most %d characters", maxDescriptionLength)
+	}
+
+	if metadata == nil {
+		return nil
+	}
+
+	for key, value := range metadata.Labels {
+		err := validateMetadataKey(key)
+		if err != nil {
+			return fmt.Errorf("invalid label key %q: %s", key, err)
+		}
+		err = validateLabelValue(value)
+		if err != nil {
+			return fmt.Errorf("invalid label value %q: %s", value, err)
+		}
+	}
+
+	for key, value := range metadata.Annotations {
+		err := validateMetadataKey(key)
+		if err != nil {
+			return fmt.Errorf("invalid annotation key %q: %s", key, err)
+		}
+		if len(value) > maxAnnotationValueLength {
+			return fmt.Errorf("invalid annotation value for key %q: must be at most %d characters", key, maxAnnotationValueLength)
+		}
+	}
+	return nil
+}
+
+func validateMetadataKey(key string) error {
+	name := key
+	if i := strings.Index(key, "/"); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if len(prefix) == 0 || len(prefix) > maxMetadataPrefixLength || !metadataPrefixPattern.MatchString(prefix) {
+			return fmt.Errorf("prefix must be a DNS subdomain of at most %d characters", maxMetadataPrefixLength)
+		}
+	}
+
+	if len(name) == 0 || len(name) > maxMetadataNameLength || !metadataNamePattern.MatchString(name) {
+		return fmt.Errorf("name must be at most %d alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character", maxMetadataNameLength)
+	}
+	return nil
+}
+
+func validateLabelValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > maxLabelValueLength || !metadataNamePattern.MatchString(value) {
+		return fmt.Errorf("must be at most %d alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character", maxLabelValueLength)
+	}
+	return nil
+}
@@ -3,8 +3,15 @@ package api
 import (
 	"errors"
 	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/policy-server/store"
 )
 
+// MaxExpiresAt is the latest expiry that the MySQL timestamp column of the
+// policies can hold
+var MaxExpiresAt = time.Date(2038, time.January, 19, 3, 14, 7, 0, time.UTC)
+
 //counterfeiter:generate -o fakes/policy_validator.go --fake-name PolicyValidator . policyValidator
 type policyValidator interface {
 	ValidatePolicies(policies []Policy) error
@@ -22,33 +29,104 @@ func (v *PolicyValidator) ValidatePolicies(policies []Policy) error {
 			return errors.New("missing source id")
 		}
 
+		switch policy.Source.Type {
+		case "", store.GroupTypeApp, store.GroupTypeSpace, store.GroupTypeOrg:
+		default:
+			return fmt.Errorf("invalid source type %s, specify either app, space or org", policy.Source.Type)
+		}
+
 		if policy.Destination.ID == "" {
 			return errors.New("missing destination id")
 		}
 
-		if policy.Destination.Protocol != "udp" && policy.Destination.Protocol != "tcp" {
-			return errors.New("invalid destination protocol, specify either udp or tcp")
+		switch policy.Destination.Protocol {
+		case "udp", "tcp":
+			err := validatePorts(policy.Destination)
+			if err != nil {
+				return err
+			}
+		case "icmp":
+			err := validateICMP(policy.Destination)
+			if err != nil {
+				return err
+			}
+		case "all":
+			ports := policy.Destination.Ports
+			if ports != (Ports{}) && ports != (Ports{Start: 1, End: 65535}) {
+				return fmt.Errorf("invalid port range %d-%d, all must cover ports 1-65535", ports.Start, ports.End)
+			}
+			if policy.Destination.ICMPType != nil || policy.Destination.ICMPCode != nil {
+				return errors.New("icmp_type and icmp_code may only be specified for icmp")
+			}
+		default:
+			return errors.New("invalid destination protocol, specify either udp, tcp, icmp or all")
 		}
 
-		if policy.Destination.Ports.Start > policy.Destination.Ports.End {
-			return fmt.Errorf("invalid port range %d-%d, start must be less than or equal to end", policy.Destination.Ports.Start, policy.Destination.Ports.End)
+		switch policy.Action {
+		case "", store.PolicyActionAllow, store.PolicyActionDeny:
+		default:
+			return fmt.Errorf("invalid action %s, specify either allow or deny", policy.Action)
 		}
 
-		if policy.Destination.Ports.Start < 0 {
-			return fmt.Errorf("invalid start port %d, must be in range 1-65535", policy.Destination.Ports.Start)
+		if policy.ExpiresAt != nil && policy.ExpiresAt.After(MaxExpiresAt) {
+			return fmt.Errorf("invalid expires_at %s, must not be later than %s",
+				policy.ExpiresAt.UTC().Format(time.RFC3339), MaxExpiresAt.Format(time.RFC3339))
 		}
 
-		if policy.Destination.Ports.Start == 0 {
-			return fmt.Errorf("missing start port")
+		if policy.Source.Tag != "" || policy.Destination.Tag != "" {
+			return errors.New("tags may not be specified")
 		}
 
-		if policy.Destination.Ports.End > 65535 {
-			return fmt.Errorf("invalid end port %d, must be in range 1-65535", policy.Destination.Ports.End)
+		err := validateMetadata(policy.Description, policy.Metadata)
+		if err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		if policy.Source.Tag != "" || policy.Destination.Tag != "" {
-			return errors.New("tags may not be specified")
-		}
+func validatePorts(destination Destination) error {
+	if destination.ICMPType != nil || destination.ICMPCode != nil {
+		return errors.New("icmp_type and icmp_code may only be specified for icmp")
+	}
+
+	if destination.Ports.Start > destination.Ports.End {
+		return fmt.Errorf("invalid port range %d-%d, start must be less than or equal to end", destination.Ports.Start, destination.Ports.End)
+	}
+
+	if destination.Ports.Start < 0 {
+		return fmt.Errorf("invalid start port %d, must be in range 1-65535", destination.Ports.Start)
+	}
+
+	if destination.Ports.Start == 0 {
+		return fmt.Errorf("missing start port")
+	}
+
+	if destination.Ports.End > 65535 {
+		return fmt.Errorf("invalid end port %d, must be in range 1-65535", destination.Ports.End)
+	}
+	return nil
+}
+
+// validateICMP checks the type and code of an icmp destination. Leaving out
+// the type or code, or setting it to -1, allows any type or code.
+func validateICMP(destination Destination) error {
+	if destination.Ports != (Ports{}) {
+		return errors.New("ports may not be specified for icmp")
+	}
+
+	if destination.ICMPType != nil && (*destination.ICMPType < ICMPDefault || *destination.ICMPType > 255) {
+		return fmt.Errorf("invalid icmp_type %d, must be in range 0-255 or -1 for any type", *destination.ICMPType)
+	}
+
+	if destination.ICMPCode != nil && (*destination.ICMPCode < ICMPDefault || *destination.ICMPCode > 255) {
+		return fmt.Errorf("invalid icmp_code %d, must be in range 0-255 or -1 for any code", *destination.ICMPCode)
+	}
+
+	anyType := destination.ICMPType == nil || *destination.ICMPType == ICMPDefault
+	anyCode := destination.ICMPCode == nil || *destination.ICMPCode == ICMPDefault
+	if anyType && !anyCode {
+		return errors.New("icmp_code may only be specified with an icmp_type")
 	}
 	return nil
 }
@@ -1,6 +1,9 @@
 package api_test
 
 import (
+	"strings"
+	"time"
+
 	"code.cloudfoundry.org/policy-server/api"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -75,6 +78,117 @@ var _ = Describe("PolicyValidator", func() {
 			})
 		})
 
+		Context("when the source is a space or an org", func() {
+			It("does not error", func() {
+				for _, sourceType := range []string{"app", "space", "org"} {
+					policies := []api.Policy{
+						api.Policy{
+							Source: api.Source{
+								ID:   "some-source-id",
+								Type: sourceType,
+							},
+							Destination: api.Destination{
+								ID:       "some-destination-id",
+								Protocol: "tcp",
+								Ports: api.Ports{
+									Start: 42,
+									End:   42,
+								},
+							},
+						},
+					}
+
+					err := validator.ValidatePolicies(policies)
+					Expect(err).NotTo(HaveOccurred())
+				}
+			})
+		})
+
+		Context("when the source type is invalid", func() {
+			It("returns a useful error", func() {
+				policies := []api.Policy{
+					api.Policy{
+						Source: api.Source{
+							ID:   "some-source-id",
+							Type: "foundation",
+						},
+						Destination: api.Destination{
+							ID:       "some-destination-id",
+							Protocol: "tcp",
+							Ports: api.Ports{
+								Start: 42,
+								End:   42,
+							},
+						},
+					},
+				}
+
+				err := validator.ValidatePolicies(policies)
+				Expect(err).To(MatchError("invalid source type foundation, specify either app, space or org"))
+			})
+		})
+
+		Context("when the policy has metadata", func() {
+			var policies []api.Policy
+
+			BeforeEach(func() {
+				policies = []api.Policy{
+					api.Policy{
+						Source: api.Source{
+							ID: "some-source-id",
+						},
+						Destination: api.Destination{
+							ID:       "some-destination-id",
+							Protocol: "tcp",
+							Ports: api.Ports{
+								Start: 42,
+								End:   42,
+							},
+						},
+						Description: "some description",
+						Metadata: &api.Metadata{
+							Labels:      map[string]string{"team": "orders", "example.com/tier": "backend", "empty": ""},
+							Annotations: map[string]string{"contact": "Team Orders <orders@example.com>"},
+						},
+					},
+				}
+			})
+
+			It("does not error", func() {
+				err := validator.ValidatePolicies(policies)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			DescribeTable("when the metadata is invalid",
+				func(modify func(*api.Policy), expectedError string) {
+					modify(&policies[0])
+					err := validator.ValidatePolicies(policies)
+					Expect(err).To(MatchError(ContainSubstring(expectedError)))
+				},
+				Entry("description too long", func(p *api.Policy) {
+					p.Description = strings.Repeat("a", 256)
+				}, "invalid description, must be at most 255 characters"),
+				Entry("label key with invalid characters", func(p *api.Policy) {
+					p.Metadata.Labels = map[string]string{"team!": "orders"}
+				}, `invalid label key "team!": name must be`),
+				Entry("label key with an invalid prefix", func(p *api.Policy) {
+					p.Metadata.Labels = map[string]string{"-example.com/team": "orders"}
+				}, `invalid label key "-example.com/team": prefix must be a DNS subdomain`),
+				Entry("label key name too long", func(p *api.Policy) {
+					p.Metadata.Labels = map[string]string{strings.Repeat("a", 64): "orders"}
+				}, "name must be at most 63"),
+				Entry("label value too long", func(p *api.Policy) {
+					p.Metadata.Labels = map[string]string{"team": strings.Repeat("a", 64)}
+				}, "invalid label value"),
+				Entry("annotation key with invalid characters", func(p *api.Policy) {
+					p.Metadata.Annotations = map[string]string{"contact me": "orders"}
+				}, `invalid annotation key "contact me"`),
+				Entry("annotation value too long", func(p *api.Policy) {
+					p.Metadata.Annotations = map[string]string{"contact": strings.Repeat("a", 5001)}
+				}, `invalid annotation value for key "contact": must be at most 5000 characters`),
+			)
+		})
+
 		Context("when destination id is missing", func() {
 			It("returns a useful error", func() {
 				policies := []api.Policy{
@@ -121,7 +235,201 @@ var _ = Describe("PolicyValidator", func() {
 				}
 
 				err := validator.ValidatePolicies(policies)
-				Expect(err).To(MatchError("invalid destination protocol, specify either udp or tcp"))
+				Expect(err).To(MatchError("invalid destination protocol, specify either udp, tcp, icmp or all"))
+			})
+		})
+
+		Context("when the protocol is icmp", func() {
+			var policies []api.Policy
+
+			intPtr := func(i int) *int { return &i }
+
+			BeforeEach(func() {
+				policies = []api.Policy{
+					api.Policy{
+						Source: api.Source{
+							ID: "foo",
+						},
+						Destination: api.Destination{
+							ID:       "bar",
+							Protocol: "icmp",
+							ICMPType: intPtr(8),
+							ICMPCode: intPtr(0),
+						},
+					},
+				}
+			})
+
+			It("does not require ports", func() {
+				err := validator.ValidatePolicies(policies)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when type and code are omitted", func() {
+				It("does not error", func() {
+					policies[0].Destination.ICMPType = nil
+					policies[0].Destination.ICMPCode = nil
+					err := validator.ValidatePolicies(policies)
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+
+			Context("when ports are specified", func() {
+				It("returns a useful error", func() {
+					policies[0].Destination.Ports = api.Ports{Start: 42, End: 42}
+					err := validator.ValidatePolicies(policies)
+					Expect(err).To(MatchError("ports may not be specified for icmp"))
+				})
+			})
+
+			Context("when the type is out of range", func() {
+				It("returns a useful error", func() {
+					policies[0].Destination.ICMPType = intPtr(256)
+					err := validator.ValidatePolicies(policies)
+					Expect(err).To(MatchError("invalid icmp_type 256, must be in range 0-255 or -1 for any type"))
+				})
+			})
+
+			Context("when the code is out of range", func() {
+				It("returns a useful error", func() {
+					policies[0].Destination.ICMPCode = intPtr(-2)
+					err := validator.ValidatePolicies(policies)
+					Expect(err).To(MatchError("invalid icmp_code -2, must be in range 0-255 or -1 for any code"))
+				})
+			})
+
+			Context("when a code is given without a type", func() {
+				It("returns a useful error", func() {
+					policies[0].Destination.ICMPType = intPtr(-1)
+					err := validator.ValidatePolicies(policies)
+					Expect(err).To(MatchError("icmp_code may only be specified with an icmp_type"))
+				})
+			})
+		})
+
+		Context("when the protocol is all", func() {
+			var policies []api.Policy
+
+			BeforeEach(func() {
+				policies = []api.Policy{
+					api.Policy{
+						Source: api.Source{
+							ID: "foo",
+						},
+						Destination: api.Destination{
+							ID:       "bar",
+							Protocol: "all",
+						},
+					},
+				}
+			})
+
+			It("does not require ports", func() {
+				err := validator.ValidatePolicies(policies)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("allows the full port range", func() {
+				policies[0].Destination.Ports = api.Ports{Start: 1, End: 65535}
+				err := validator.ValidatePolicies(policies)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when a smaller port range is specified", func() {
+				It("returns a useful error", func() {
+					policies[0].Destination.Ports = api.Ports{Start: 42, End: 42}
+					err := validator.ValidatePolicies(policies)
+					Expect(err).To(MatchError("invalid port range 42-42, all must cover ports 1-65535"))
+				})
+			})
+
+			Context("when an icmp type is specified", func() {
+				It("returns a useful error", func() {
+					icmpType := 8
+					policies[0].Destination.ICMPType = &icmpType
+					err := validator.ValidatePolicies(policies)
+					Expect(err).To(MatchError("icmp_type and icmp_code may only be specified for icmp"))
+				})
+			})
+		})
+
+		Context("when icmp type or code is given for tcp or udp", func() {
+			It("returns a useful error", func() {
+				icmpType := 8
+				policies := []api.Policy{
+					api.Policy{
+						Source: api.Source{
+							ID: "foo",
+						},
+						Destination: api.Destination{
+							ID:       "bar",
+							Protocol: "udp",
+							Ports: api.Ports{
+								Start: 42,
+								End:   42,
+							},
+							ICMPType: &icmpType,
+						},
+					},
+				}
+
+				err := validator.ValidatePolicies(policies)
+				Expect(err).To(MatchError("icmp_type and icmp_code may only be specified for icmp"))
+			})
+		})
+
+		Context("when the action is invalid", func() {
+			It("returns a useful error", func() {
+				policies := []api.Policy{
+					api.Policy{
+						Source: api.Source{
+							ID: "foo",
+						},
+						Destination: api.Destination{
+							ID:       "bar",
+							Protocol: "tcp",
+							Ports: api.Ports{
+								Start: 42,
+								End:   42,
+							},
+						},
+						Action: "reject",
+					},
+				}
+
+				err := validator.ValidatePolicies(policies)
+				Expect(err).To(MatchError("invalid action reject, specify either allow or deny"))
+
+				policies[0].Action = "deny"
+				Expect(validator.ValidatePolicies(policies)).To(Succeed())
+			})
+		})
+
+		Context("when the policy expires after the latest supported expiry", func() {
+			It("returns a useful error", func() {
+				expiresAt := api.MaxExpiresAt.Add(time.Second)
+				policies := []api.Policy{
+					api.Policy{
+						Source: api.Source{
+							ID: "foo",
+						},
+						Destination: api.Destination{
+							ID:       "bar",
+							Protocol: "tcp",
+							Ports: api.Ports{
+								Start: 42,
+								End:   42,
+							},
+						},
+						ExpiresAt: &expiresAt,
+					},
+				}
+
+				err := validator.ValidatePolicies(policies)
+				Expect(err).To(MatchError("invalid expires_at 2038-01-19T03:14:08Z, must not be later than 2038-01-19T03:14:07Z"))
+
+				expiresAt = api.MaxExpiresAt
+				Expect(validator.ValidatePolicies(policies)).To(Succeed())
 			})
 		})
 
@@ -3,6 +3,7 @@ package cc_client
 //go:generate counterfeiter -generate
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -13,10 +14,15 @@ import (
 
 	"code.cloudfoundry.org/cf-networking-helpers/json_client"
 	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/tracing"
 )
 
 const SECURITY_GROUPS_PER_PAGE = 5000
 
+// resourcesPerPage is the largest page of apps, spaces or orgs that the
+// Cloud-Controller returns
+const resourcesPerPage = 5000
+
 //counterfeiter:generate -o fakes/cc_client.go --fake-name CCClient . CCClient
 type CCClient interface {
 	GetAppSpaces(token string, appGUIDs []string) (map[string]string, error)
@@ -24,8 +30,13 @@ type CCClient interface {
 	GetSpaceGUIDs(token string, appGUIDs []string) ([]string, error)
 	GetSubjectSpace(token, subjectId string, spaces SpaceResponse) (*SpaceResource, error)
 	GetSubjectSpaces(token, subjectId string) (map[string]struct{}, error)
+	GetSubjectRoles(token, subjectId string) ([]Role, error)
 	GetLiveAppGUIDs(token string, appGUIDs []string) (map[string]struct{}, error)
 	GetLiveSpaceGUIDs(token string, spaceGUIDs []string) (map[string]struct{}, error)
+	GetLiveOrgGUIDs(token string, orgGUIDs []string) (map[string]struct{}, error)
+	GetApps(token string, filter ResourceFilter) ([]Resource, error)
+	GetSpaces(token string, filter ResourceFilter) ([]Resource, error)
+	GetOrgs(token string, filter ResourceFilter) ([]Resource, error)
 	GetSecurityGroupsLastUpdate(token string) (time.Time, error)
 	GetSecurityGroupsWithPage(token string, page int) (GetSecurityGroupsResponse, error)
 	GetSecurityGroups(token string) ([]SecurityGroupResource, error)
@@ -37,6 +48,19 @@ type Client struct {
 	InternalJSONClient json_client.JsonClient
 }
 
+// Traced returns a copy of the client that records a span for every request
+// to the Cloud-Controller, as child of the span in the context
+func (c *Client) Traced(ctx context.Context) interface{} {
+	traced := *c
+	if c.ExternalJSONClient != nil {
+		traced.ExternalJSONClient = &tracing.JsonClient{Client: c.ExternalJSONClient, Context: ctx, Service: "cc"}
+	}
+	if c.InternalJSONClient != nil {
+		traced.InternalJSONClient = &tracing.JsonClient{Client: c.InternalJSONClient, Context: ctx, Service: "cc"}
+	}
+	return &traced
+}
+
 type Href struct {
 	Href string `json:"href"`
 }
@@ -127,6 +151,80 @@ type SpacesV3Response struct {
 	} `json:"resources"`
 }
 
+type OrganizationsV3Response struct {
+	Pagination struct {
+		TotalPages int `json:"total_pages"`
+	} `json:"pagination"`
+	Resources []struct {
+		GUID string `json:"guid"`
+	} `json:"resources"`
+}
+
+// ResourceFilter selects the apps, spaces or orgs that have one of the given
+// guids or names. ParentGUIDs selects apps by the guid of their space, and
+// spaces by the guid of their org. An empty filter selects nothing.
+type ResourceFilter struct {
+	GUIDs       []string
+	Names       []string
+	ParentGUIDs []string
+}
+
+// Resource is an app, space or org. ParentGUID is the guid of the space of an
+// app, or of the org of a space.
+type Resource struct {
+	GUID       string
+	Name       string
+	ParentGUID string
+}
+
+type ResourcesV3Response struct {
+	Pagination Pagination `json:"pagination"`
+	Resources  []struct {
+		GUID          string `json:"guid"`
+		Name          string `json:"name"`
+		Relationships map[string]struct {
+			Data struct {
+				GUID string `json:"guid"`
+			} `json:"data"`
+		} `json:"relationships"`
+	} `json:"resources"`
+}
+
+// Role types, as the Cloud-Controller names them
+const (
+	RoleSpaceDeveloper = "space_developer"
+	RoleSpaceManager   = "space_manager"
+	RoleSpaceAuditor   = "space_auditor"
+	RoleOrgManager     = "org_manager"
+)
+
+// Role is a role that a user or client has in a space, or in an org when
+// SpaceGUID is empty
+type Role struct {
+	Type      string
+	SpaceGUID string
+	OrgGUID   string
+}
+
+type RolesV3Response struct {
+	Pagination Pagination `json:"pagination"`
+	Resources  []struct {
+		Type          string `json:"type"`
+		Relationships struct {
+			Space struct {
+				Data struct {
+					GUID string `json:"guid"`
+				} `json:"data"`
+			} `json:"space"`
+			Organization struct {
+				Data struct {
+					GUID string `json:"guid"`
+				} `json:"data"`
+			} `json:"organization"`
+		} `json:"relationships"`
+	} `json:"resources"`
+}
+
 type SpaceResponse struct {
 	Entity SpaceEntity `json:"entity"`
 }
@@ -254,6 +352,110 @@ func (c *Client) GetLiveSpaceGUIDs(token string, spaceGUIDs []string) (map[strin
 	return liveSpaceGUIDs, nil
 }
 
+func (c *Client) GetLiveOrgGUIDs(token string, orgGUIDs []string) (map[string]struct{}, error) {
+	c.Logger.Info("get-live-org-guids", lager.Data{"candidate-org-guids": orgGUIDs})
+	token = fmt.Sprintf("bearer %s", token)
+
+	liveOrgGUIDs := make(map[string]struct{})
+
+	values := url.Values{}
+	values.Add("guids", strings.Join(orgGUIDs, ","))
+	// Add +1 incase len is 0 - avoiding a capi error
+	values.Add("per_page", strconv.Itoa(len(orgGUIDs)+1))
+
+	route := fmt.Sprintf("/v3/organizations?%s", values.Encode())
+	c.Logger.Debug("live-org-guid-request", lager.Data{"route": route})
+
+	var response OrganizationsV3Response
+	err := c.ExternalJSONClient.Do("GET", route, nil, &response, token)
+	if err != nil {
+		return nil, fmt.Errorf("json client do: %s", err)
+	}
+
+	// TotalPages will never be greater than 1, we are setting per_page equal to size of org_guids list
+	if response.Pagination.TotalPages > 1 {
+		return nil, fmt.Errorf("pagination support not yet implemented")
+	}
+
+	c.Logger.Debug("live-org-guid-response", lager.Data{"resources": response.Resources})
+
+	for _, org := range response.Resources {
+		liveOrgGUIDs[org.GUID] = struct{}{}
+	}
+
+	return liveOrgGUIDs, nil
+}
+
+func (c *Client) GetApps(token string, filter ResourceFilter) ([]Resource, error) {
+	c.Logger.Info("get-apps", lager.Data{"filter": filter})
+	return c.getResources(token, "/v3/apps", "space_guids", "space", filter)
+}
+
+func (c *Client) GetSpaces(token string, filter ResourceFilter) ([]Resource, error) {
+	c.Logger.Info("get-spaces", lager.Data{"filter": filter})
+	return c.getResources(token, "/v3/spaces", "organization_guids", "organization", filter)
+}
+
+func (c *Client) GetOrgs(token string, filter ResourceFilter) ([]Resource, error) {
+	c.Logger.Info("get-orgs", lager.Data{"filter": filter})
+	return c.getResources(token, "/v3/organizations", "", "", filter)
+}
+
+// getResources lists every page of the resources that match the filter. An
+// empty filter matches nothing rather than every resource.
+func (c *Client) getResources(token, path, parentQueryParam, parentRelationship string, filter ResourceFilter) ([]Resource, error) {
+	resources := []Resource{}
+	hasParentFilter := parentQueryParam != "" && len(filter.ParentGUIDs) > 0
+	if len(filter.GUIDs) == 0 && len(filter.Names) == 0 && !hasParentFilter {
+		return resources, nil
+	}
+
+	token = fmt.Sprintf("bearer %s", token)
+
+	values := url.Values{}
+	if len(filter.GUIDs) > 0 {
+		values.Add("guids", strings.Join(filter.GUIDs, ","))
+	}
+	if len(filter.Names) > 0 {
+		values.Add("names", strings.Join(filter.Names, ","))
+	}
+	if hasParentFilter {
+		values.Add(parentQueryParam, strings.Join(filter.ParentGUIDs, ","))
+	}
+	values.Add("per_page", strconv.Itoa(resourcesPerPage))
+
+	route := fmt.Sprintf("%s?%s", path, values.Encode())
+	for route != "" {
+		c.Logger.Debug("get-resources-request", lager.Data{"route": route})
+
+		var response ResourcesV3Response
+		err := c.ExternalJSONClient.Do("GET", route, nil, &response, token)
+		if err != nil {
+			return nil, fmt.Errorf("json client do: %s", err)
+		}
+
+		for _, r := range response.Resources {
+			resources = append(resources, Resource{
+				GUID:       r.GUID,
+				Name:       r.Name,
+				ParentGUID: r.Relationships[parentRelationship].Data.GUID,
+			})
+		}
+
+		route = ""
+		if response.Pagination.Next.Href != "" {
+			next, err := url.Parse(response.Pagination.Next.Href)
+			if err != nil {
+				return nil, fmt.Errorf("parsing next page: %s", err)
+			}
+			route = fmt.Sprintf("%s?%s", path, next.RawQuery)
+		}
+	}
+
+	c.Logger.Debug("get-resources-response", lager.Data{"resources": resources})
+	return resources, nil
+}
+
 func (c *Client) GetSpaceGUIDs(token string, appGUIDs []string) ([]string, error) {
 	c.Logger.Info("get-space-guids", lager.Data{"app-guids": appGUIDs})
 	mapping, err := c.GetAppSpaces(token, appGUIDs)
@@ -408,6 +610,50 @@ func (c *Client) GetSubjectSpaces(token, subjectId string) (map[string]struct{},
 	return subjectSpaces, nil
 }
 
+// GetSubjectRoles lists the space developer, space manager, space auditor and
+// org manager roles of a user or client
+func (c *Client) GetSubjectRoles(token, subjectId string) ([]Role, error) {
+	c.Logger.Info("get-subject-roles", lager.Data{"subject-id": subjectId})
+	token = fmt.Sprintf("bearer %s", token)
+
+	values := url.Values{}
+	values.Add("user_guids", subjectId)
+	values.Add("types", strings.Join([]string{RoleSpaceDeveloper, RoleSpaceManager, RoleSpaceAuditor, RoleOrgManager}, ","))
+	values.Add("per_page", strconv.Itoa(resourcesPerPage))
+
+	roles := []Role{}
+	route := fmt.Sprintf("/v3/roles?%s", values.Encode())
+	for route != "" {
+		c.Logger.Debug("get-subject-roles-request", lager.Data{"route": route})
+
+		var response RolesV3Response
+		err := c.ExternalJSONClient.Do("GET", route, nil, &response, token)
+		if err != nil {
+			return nil, fmt.Errorf("json client do: %s", err)
+		}
+
+		for _, r := range response.Resources {
+			roles = append(roles, Role{
+				Type:      r.Type,
+				SpaceGUID: r.Relationships.Space.Data.GUID,
+				OrgGUID:   r.Relationships.Organization.Data.GUID,
+			})
+		}
+
+		route = ""
+		if response.Pagination.Next.Href != "" {
+			next, err := url.Parse(response.Pagination.Next.Href)
+			if err != nil {
+				return nil, fmt.Errorf("parsing next page: %s", err)
+			}
+			route = fmt.Sprintf("/v3/roles?%s", next.RawQuery)
+		}
+	}
+
+	c.Logger.Debug("get-subject-roles-response", lager.Data{"roles": roles})
+	return roles, nil
+}
+
 func (c *Client) GetSecurityGroupsWithPage(token string, page int) (GetSecurityGroupsResponse, error) {
 	c.Logger.Info("get-security-groups-with-page", lager.Data{"page": page})
 
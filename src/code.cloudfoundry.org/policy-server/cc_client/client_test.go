@@ -235,6 +235,182 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("GetApps", func() {
+		var (
+			passedToken string
+			passedRoute string
+		)
+
+		BeforeEach(func() {
+			fakeExternalJSONClient.DoStub = func(method, route string, reqData, respData interface{}, token string) error {
+				passedToken = token
+				passedRoute = route
+				_ = json.Unmarshal([]byte(fixtures.AppsV3WithNames), respData)
+				return nil
+			}
+		})
+
+		It("returns the apps with their names and spaces", func() {
+			apps, err := client.GetApps("some-token", cc_client.ResourceFilter{
+				Names:       []string{"app-1", "app-2"},
+				ParentGUIDs: []string{"space-1-guid", "space-2-guid"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apps).To(Equal([]cc_client.Resource{
+				{GUID: "app-1-guid", Name: "app-1", ParentGUID: "space-1-guid"},
+				{GUID: "app-2-guid", Name: "app-2", ParentGUID: "space-2-guid"},
+			}))
+
+			Expect(passedToken).To(Equal("bearer some-token"))
+			Expect(passedRoute).To(Equal("/v3/apps?names=app-1%2Capp-2&per_page=5000&space_guids=space-1-guid%2Cspace-2-guid"))
+		})
+
+		It("filters the apps by guid", func() {
+			_, err := client.GetApps("some-token", cc_client.ResourceFilter{GUIDs: []string{"app-1-guid", "app-2-guid"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(passedRoute).To(Equal("/v3/apps?guids=app-1-guid%2Capp-2-guid&per_page=5000"))
+		})
+
+		It("lists the apps of spaces", func() {
+			_, err := client.GetApps("some-token", cc_client.ResourceFilter{ParentGUIDs: []string{"space-1-guid"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(passedRoute).To(Equal("/v3/apps?per_page=5000&space_guids=space-1-guid"))
+		})
+
+		Context("when the filter is empty", func() {
+			It("returns no apps without calling the Cloud-Controller", func() {
+				apps, err := client.GetApps("some-token", cc_client.ResourceFilter{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(apps).To(BeEmpty())
+
+				Expect(fakeExternalJSONClient.DoCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the json client returns an error", func() {
+			BeforeEach(func() {
+				fakeExternalJSONClient.DoReturns(errors.New("banana"))
+			})
+
+			It("returns the error", func() {
+				_, err := client.GetApps("some-token", cc_client.ResourceFilter{Names: []string{"app-1"}})
+				Expect(err).To(MatchError("json client do: banana"))
+			})
+		})
+	})
+
+	Describe("GetSpaces", func() {
+		BeforeEach(func() {
+			fakeExternalJSONClient.DoStub = func(method, route string, reqData, respData interface{}, token string) error {
+				if fakeExternalJSONClient.DoCallCount() == 1 {
+					_ = json.Unmarshal([]byte(fixtures.SpacesV3WithNamesPage1), respData)
+				} else {
+					_ = json.Unmarshal([]byte(fixtures.SpacesV3WithNamesPage2), respData)
+				}
+				return nil
+			}
+		})
+
+		It("returns the spaces of every page with their orgs", func() {
+			spaces, err := client.GetSpaces("some-token", cc_client.ResourceFilter{
+				Names:       []string{"space-1", "space-2"},
+				ParentGUIDs: []string{"org-1-guid"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spaces).To(Equal([]cc_client.Resource{
+				{GUID: "space-1-guid", Name: "space-1", ParentGUID: "org-1-guid"},
+				{GUID: "space-2-guid", Name: "space-2", ParentGUID: "org-1-guid"},
+			}))
+
+			Expect(fakeExternalJSONClient.DoCallCount()).To(Equal(2))
+			_, route, _, _, _ := fakeExternalJSONClient.DoArgsForCall(0)
+			Expect(route).To(Equal("/v3/spaces?names=space-1%2Cspace-2&organization_guids=org-1-guid&per_page=5000"))
+			_, route, _, _, _ = fakeExternalJSONClient.DoArgsForCall(1)
+			Expect(route).To(Equal("/v3/spaces?names=space-1%2Cspace-2&page=2&per_page=1"))
+		})
+	})
+
+	Describe("GetOrgs", func() {
+		var passedRoute string
+
+		BeforeEach(func() {
+			fakeExternalJSONClient.DoStub = func(method, route string, reqData, respData interface{}, token string) error {
+				passedRoute = route
+				_ = json.Unmarshal([]byte(fixtures.OrganizationV3LiveOrgs), respData)
+				return nil
+			}
+		})
+
+		It("returns the orgs with their names", func() {
+			orgs, err := client.GetOrgs("some-token", cc_client.ResourceFilter{
+				Names:       []string{"org-1", "org-2"},
+				ParentGUIDs: []string{"ignored-guid"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orgs).To(Equal([]cc_client.Resource{
+				{GUID: "live-org-1-guid", Name: "org-1"},
+				{GUID: "live-org-2-guid", Name: "org-2"},
+			}))
+
+			Expect(passedRoute).To(Equal("/v3/organizations?names=org-1%2Corg-2&per_page=5000"))
+		})
+	})
+
+	Describe("GetLiveOrgGUIDs", func() {
+		var (
+			passedToken string
+			passedRoute string
+		)
+
+		BeforeEach(func() {
+			fakeExternalJSONClient.DoStub = func(method, route string, reqData, respData interface{}, token string) error {
+				passedToken = token
+				passedRoute = route
+				_ = json.Unmarshal([]byte(fixtures.OrganizationV3LiveOrgs), respData)
+				return nil
+			}
+		})
+
+		It("returns the live org guids filtered by given org guids", func() {
+			liveOrgGUIDs, err := client.GetLiveOrgGUIDs("some-token", []string{"live-org-1-guid", "live-org-2-guid", "dead-org-1-guid"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(liveOrgGUIDs).To(Equal(map[string]struct{}{
+				"live-org-1-guid": {},
+				"live-org-2-guid": {},
+			}))
+
+			Expect(passedToken).To(Equal("bearer some-token"))
+			Expect(passedRoute).To(Equal("/v3/organizations?guids=live-org-1-guid%2Clive-org-2-guid%2Cdead-org-1-guid&per_page=4"))
+		})
+
+		Context("when the json client returns an error", func() {
+			BeforeEach(func() {
+				fakeExternalJSONClient.DoReturns(errors.New("banana"))
+			})
+
+			It("returns the error", func() {
+				_, err := client.GetLiveOrgGUIDs("some-token", []string{})
+				Expect(err).To(MatchError(ContainSubstring("json client do: banana")))
+			})
+		})
+
+		Context("when there are multiple pages", func() {
+			BeforeEach(func() {
+				fakeExternalJSONClient.DoStub = func(method, route string, reqData, respData interface{}, token string) error {
+					_ = json.Unmarshal([]byte(fixtures.OrganizationV3MultiplePages), respData)
+					return nil
+				}
+			})
+
+			It("should immediately return an error", func() {
+				_, err := client.GetLiveOrgGUIDs("some-token", []string{})
+				Expect(err).To(MatchError("pagination support not yet implemented"))
+			})
+		})
+	})
+
 	Describe("GetSpaceGUIDs", func() {
 		BeforeEach(func() {
 			fakeExternalJSONClient.DoStub = func(method, route string, reqData, respData interface{}, token string) error {
@@ -508,6 +684,49 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("GetSubjectRoles", func() {
+		BeforeEach(func() {
+			fakeExternalJSONClient.DoStub = func(method, route string, reqData, respData interface{}, token string) error {
+				if fakeExternalJSONClient.DoCallCount() == 1 {
+					_ = json.Unmarshal([]byte(fixtures.RolesV3Page1), respData)
+				} else {
+					_ = json.Unmarshal([]byte(fixtures.RolesV3Page2), respData)
+				}
+				return nil
+			}
+		})
+
+		It("returns the space and org roles of every page", func() {
+			roles, err := client.GetSubjectRoles("some-token", "some-subject-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roles).To(Equal([]cc_client.Role{
+				{Type: "space_developer", SpaceGUID: "space-1-guid"},
+				{Type: "space_auditor", SpaceGUID: "space-2-guid"},
+				{Type: "org_manager", OrgGUID: "org-1-guid"},
+			}))
+
+			Expect(fakeExternalJSONClient.DoCallCount()).To(Equal(2))
+			method, route, _, _, token := fakeExternalJSONClient.DoArgsForCall(0)
+			Expect(method).To(Equal("GET"))
+			Expect(route).To(Equal("/v3/roles?per_page=5000&types=space_developer%2Cspace_manager%2Cspace_auditor%2Corg_manager&user_guids=some-subject-id"))
+			Expect(token).To(Equal("bearer some-token"))
+			_, route, _, _, _ = fakeExternalJSONClient.DoArgsForCall(1)
+			Expect(route).To(Equal("/v3/roles?page=2&per_page=2&types=space_developer%2Cspace_manager%2Cspace_auditor%2Corg_manager&user_guids=some-subject-id"))
+		})
+
+		Context("when the json client returns an error", func() {
+			BeforeEach(func() {
+				fakeExternalJSONClient.DoStub = nil
+				fakeExternalJSONClient.DoReturns(errors.New("banana"))
+			})
+
+			It("returns the error", func() {
+				_, err := client.GetSubjectRoles("some-token", "some-subject-id")
+				Expect(err).To(MatchError("json client do: banana"))
+			})
+		})
+	})
+
 	Describe("GetSubjectSpace", func() {
 		space := cc_client.SpaceResponse{
 			Entity: cc_client.SpaceEntity{
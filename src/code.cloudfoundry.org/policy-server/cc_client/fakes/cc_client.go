@@ -23,6 +23,20 @@ type CCClient struct {
 		result1 map[string]string
 		result2 error
 	}
+	GetAppsStub        func(string, cc_client.ResourceFilter) ([]cc_client.Resource, error)
+	getAppsMutex       sync.RWMutex
+	getAppsArgsForCall []struct {
+		arg1 string
+		arg2 cc_client.ResourceFilter
+	}
+	getAppsReturns struct {
+		result1 []cc_client.Resource
+		result2 error
+	}
+	getAppsReturnsOnCall map[int]struct {
+		result1 []cc_client.Resource
+		result2 error
+	}
 	GetLiveAppGUIDsStub        func(string, []string) (map[string]struct{}, error)
 	getLiveAppGUIDsMutex       sync.RWMutex
 	getLiveAppGUIDsArgsForCall []struct {
@@ -37,6 +51,20 @@ type CCClient struct {
 		result1 map[string]struct{}
 		result2 error
 	}
+	GetLiveOrgGUIDsStub        func(string, []string) (map[string]struct{}, error)
+	getLiveOrgGUIDsMutex       sync.RWMutex
+	getLiveOrgGUIDsArgsForCall []struct {
+		arg1 string
+		arg2 []string
+	}
+	getLiveOrgGUIDsReturns struct {
+		result1 map[string]struct{}
+		result2 error
+	}
+	getLiveOrgGUIDsReturnsOnCall map[int]struct {
+		result1 map[string]struct{}
+		result2 error
+	}
 	GetLiveSpaceGUIDsStub        func(string, []string) (map[string]struct{}, error)
 	getLiveSpaceGUIDsMutex       sync.RWMutex
 	getLiveSpaceGUIDsArgsForCall []struct {
@@ -51,6 +79,20 @@ type CCClient struct {
 		result1 map[string]struct{}
 		result2 error
 	}
+	GetOrgsStub        func(string, cc_client.ResourceFilter) ([]cc_client.Resource, error)
+	getOrgsMutex       sync.RWMutex
+	getOrgsArgsForCall []struct {
+		arg1 string
+		arg2 cc_client.ResourceFilter
+	}
+	getOrgsReturns struct {
+		result1 []cc_client.Resource
+		result2 error
+	}
+	getOrgsReturnsOnCall map[int]struct {
+		result1 []cc_client.Resource
+		result2 error
+	}
 	GetSecurityGroupsStub        func(string) ([]cc_client.SecurityGroupResource, error)
 	getSecurityGroupsMutex       sync.RWMutex
 	getSecurityGroupsArgsForCall []struct {
@@ -119,6 +161,34 @@ type CCClient struct {
 		result1 []string
 		result2 error
 	}
+	GetSpacesStub        func(string, cc_client.ResourceFilter) ([]cc_client.Resource, error)
+	getSpacesMutex       sync.RWMutex
+	getSpacesArgsForCall []struct {
+		arg1 string
+		arg2 cc_client.ResourceFilter
+	}
+	getSpacesReturns struct {
+		result1 []cc_client.Resource
+		result2 error
+	}
+	getSpacesReturnsOnCall map[int]struct {
+		result1 []cc_client.Resource
+		result2 error
+	}
+	GetSubjectRolesStub        func(string, string) ([]cc_client.Role, error)
+	getSubjectRolesMutex       sync.RWMutex
+	getSubjectRolesArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getSubjectRolesReturns struct {
+		result1 []cc_client.Role
+		result2 error
+	}
+	getSubjectRolesReturnsOnCall map[int]struct {
+		result1 []cc_client.Role
+		result2 error
+	}
 	GetSubjectSpaceStub        func(string, string, cc_client.SpaceResponse) (*cc_client.SpaceResource, error)
 	getSubjectSpaceMutex       sync.RWMutex
 	getSubjectSpaceArgsForCall []struct {
@@ -222,6 +292,71 @@ func (fake *CCClient) GetAppSpacesReturnsOnCall(i int, result1 map[string]string
 	}{result1, result2}
 }
 
+func (fake *CCClient) GetApps(arg1 string, arg2 cc_client.ResourceFilter) ([]cc_client.Resource, error) {
+	fake.getAppsMutex.Lock()
+	ret, specificReturn := fake.getAppsReturnsOnCall[len(fake.getAppsArgsForCall)]
+	fake.getAppsArgsForCall = append(fake.getAppsArgsForCall, struct {
+		arg1 string
+		arg2 cc_client.ResourceFilter
+	}{arg1, arg2})
+	stub := fake.GetAppsStub
+	fakeReturns := fake.getAppsReturns
+	fake.recordInvocation("GetApps", []interface{}{arg1, arg2})
+	fake.getAppsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CCClient) GetAppsCallCount() int {
+	fake.getAppsMutex.RLock()
+	defer fake.getAppsMutex.RUnlock()
+	return len(fake.getAppsArgsForCall)
+}
+
+func (fake *CCClient) GetAppsCalls(stub func(string, cc_client.ResourceFilter) ([]cc_client.Resource, error)) {
+	fake.getAppsMutex.Lock()
+	defer fake.getAppsMutex.Unlock()
+	fake.GetAppsStub = stub
+}
+
+func (fake *CCClient) GetAppsArgsForCall(i int) (string, cc_client.ResourceFilter) {
+	fake.getAppsMutex.RLock()
+	defer fake.getAppsMutex.RUnlock()
+	argsForCall := fake.getAppsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *CCClient) GetAppsReturns(result1 []cc_client.Resource, result2 error) {
+	fake.getAppsMutex.Lock()
+	defer fake.getAppsMutex.Unlock()
+	fake.GetAppsStub = nil
+	fake.getAppsReturns = struct {
+		result1 []cc_client.Resource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CCClient) GetAppsReturnsOnCall(i int, result1 []cc_client.Resource, result2 error) {
+	fake.getAppsMutex.Lock()
+	defer fake.getAppsMutex.Unlock()
+	fake.GetAppsStub = nil
+	if fake.getAppsReturnsOnCall == nil {
+		fake.getAppsReturnsOnCall = make(map[int]struct {
+			result1 []cc_client.Resource
+			result2 error
+		})
+	}
+	fake.getAppsReturnsOnCall[i] = struct {
+		result1 []cc_client.Resource
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *CCClient) GetLiveAppGUIDs(arg1 string, arg2 []string) (map[string]struct{}, error) {
 	var arg2Copy []string
 	if arg2 != nil {
@@ -292,6 +427,76 @@ func (fake *CCClient) GetLiveAppGUIDsReturnsOnCall(i int, result1 map[string]str
 	}{result1, result2}
 }
 
+func (fake *CCClient) GetLiveOrgGUIDs(arg1 string, arg2 []string) (map[string]struct{}, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.getLiveOrgGUIDsMutex.Lock()
+	ret, specificReturn := fake.getLiveOrgGUIDsReturnsOnCall[len(fake.getLiveOrgGUIDsArgsForCall)]
+	fake.getLiveOrgGUIDsArgsForCall = append(fake.getLiveOrgGUIDsArgsForCall, struct {
+		arg1 string
+		arg2 []string
+	}{arg1, arg2Copy})
+	stub := fake.GetLiveOrgGUIDsStub
+	fakeReturns := fake.getLiveOrgGUIDsReturns
+	fake.recordInvocation("GetLiveOrgGUIDs", []interface{}{arg1, arg2Copy})
+	fake.getLiveOrgGUIDsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CCClient) GetLiveOrgGUIDsCallCount() int {
+	fake.getLiveOrgGUIDsMutex.RLock()
+	defer fake.getLiveOrgGUIDsMutex.RUnlock()
+	return len(fake.getLiveOrgGUIDsArgsForCall)
+}
+
+func (fake *CCClient) GetLiveOrgGUIDsCalls(stub func(string, []string) (map[string]struct{}, error)) {
+	fake.getLiveOrgGUIDsMutex.Lock()
+	defer fake.getLiveOrgGUIDsMutex.Unlock()
+	fake.GetLiveOrgGUIDsStub = stub
+}
+
+func (fake *CCClient) GetLiveOrgGUIDsArgsForCall(i int) (string, []string) {
+	fake.getLiveOrgGUIDsMutex.RLock()
+	defer fake.getLiveOrgGUIDsMutex.RUnlock()
+	argsForCall := fake.getLiveOrgGUIDsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *CCClient) GetLiveOrgGUIDsReturns(result1 map[string]struct{}, result2 error) {
+	fake.getLiveOrgGUIDsMutex.Lock()
+	defer fake.getLiveOrgGUIDsMutex.Unlock()
+	fake.GetLiveOrgGUIDsStub = nil
+	fake.getLiveOrgGUIDsReturns = struct {
+		result1 map[string]struct{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CCClient) GetLiveOrgGUIDsReturnsOnCall(i int, result1 map[string]struct{}, result2 error) {
+	fake.getLiveOrgGUIDsMutex.Lock()
+	defer fake.getLiveOrgGUIDsMutex.Unlock()
+	fake.GetLiveOrgGUIDsStub = nil
+	if fake.getLiveOrgGUIDsReturnsOnCall == nil {
+		fake.getLiveOrgGUIDsReturnsOnCall = make(map[int]struct {
+			result1 map[string]struct{}
+			result2 error
+		})
+	}
+	fake.getLiveOrgGUIDsReturnsOnCall[i] = struct {
+		result1 map[string]struct{}
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *CCClient) GetLiveSpaceGUIDs(arg1 string, arg2 []string) (map[string]struct{}, error) {
 	var arg2Copy []string
 	if arg2 != nil {
@@ -362,6 +567,71 @@ func (fake *CCClient) GetLiveSpaceGUIDsReturnsOnCall(i int, result1 map[string]s
 	}{result1, result2}
 }
 
+func (fake *CCClient) GetOrgs(arg1 string, arg2 cc_client.ResourceFilter) ([]cc_client.Resource, error) {
+	fake.getOrgsMutex.Lock()
+	ret, specificReturn := fake.getOrgsReturnsOnCall[len(fake.getOrgsArgsForCall)]
+	fake.getOrgsArgsForCall = append(fake.getOrgsArgsForCall, struct {
+		arg1 string
+		arg2 cc_client.ResourceFilter
+	}{arg1, arg2})
+	stub := fake.GetOrgsStub
+	fakeReturns := fake.getOrgsReturns
+	fake.recordInvocation("GetOrgs", []interface{}{arg1, arg2})
+	fake.getOrgsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CCClient) GetOrgsCallCount() int {
+	fake.getOrgsMutex.RLock()
+	defer fake.getOrgsMutex.RUnlock()
+	return len(fake.getOrgsArgsForCall)
+}
+
+func (fake *CCClient) GetOrgsCalls(stub func(string, cc_client.ResourceFilter) ([]cc_client.Resource, error)) {
+	fake.getOrgsMutex.Lock()
+	defer fake.getOrgsMutex.Unlock()
+	fake.GetOrgsStub = stub
+}
+
+func (fake *CCClient) GetOrgsArgsForCall(i int) (string, cc_client.ResourceFilter) {
+	fake.getOrgsMutex.RLock()
+	defer fake.getOrgsMutex.RUnlock()
+	argsForCall := fake.getOrgsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *CCClient) GetOrgsReturns(result1 []cc_client.Resource, result2 error) {
+	fake.getOrgsMutex.Lock()
+	defer fake.getOrgsMutex.Unlock()
+	fake.GetOrgsStub = nil
+	fake.getOrgsReturns = struct {
+		result1 []cc_client.Resource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CCClient) GetOrgsReturnsOnCall(i int, result1 []cc_client.Resource, result2 error) {
+	fake.getOrgsMutex.Lock()
+	defer fake.getOrgsMutex.Unlock()
+	fake.GetOrgsStub = nil
+	if fake.getOrgsReturnsOnCall == nil {
+		fake.getOrgsReturnsOnCall = make(map[int]struct {
+			result1 []cc_client.Resource
+			result2 error
+		})
+	}
+	fake.getOrgsReturnsOnCall[i] = struct {
+		result1 []cc_client.Resource
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *CCClient) GetSecurityGroups(arg1 string) ([]cc_client.SecurityGroupResource, error) {
 	fake.getSecurityGroupsMutex.Lock()
 	ret, specificReturn := fake.getSecurityGroupsReturnsOnCall[len(fake.getSecurityGroupsArgsForCall)]
@@ -690,6 +960,136 @@ func (fake *CCClient) GetSpaceGUIDsReturnsOnCall(i int, result1 []string, result
 	}{result1, result2}
 }
 
+func (fake *CCClient) GetSpaces(arg1 string, arg2 cc_client.ResourceFilter) ([]cc_client.Resource, error) {
+	fake.getSpacesMutex.Lock()
+	ret, specificReturn := fake.getSpacesReturnsOnCall[len(fake.getSpacesArgsForCall)]
+	fake.getSpacesArgsForCall = append(fake.getSpacesArgsForCall, struct {
+		arg1 string
+		arg2 cc_client.ResourceFilter
+	}{arg1, arg2})
+	stub := fake.GetSpacesStub
+	fakeReturns := fake.getSpacesReturns
+	fake.recordInvocation("GetSpaces", []interface{}{arg1, arg2})
+	fake.getSpacesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CCClient) GetSpacesCallCount() int {
+	fake.getSpacesMutex.RLock()
+	defer fake.getSpacesMutex.RUnlock()
+	return len(fake.getSpacesArgsForCall)
+}
+
+func (fake *CCClient) GetSpacesCalls(stub func(string, cc_client.ResourceFilter) ([]cc_client.Resource, error)) {
+	fake.getSpacesMutex.Lock()
+	defer fake.getSpacesMutex.Unlock()
+	fake.GetSpacesStub = stub
+}
+
+func (fake *CCClient) GetSpacesArgsForCall(i int) (string, cc_client.ResourceFilter) {
+	fake.getSpacesMutex.RLock()
+	defer fake.getSpacesMutex.RUnlock()
+	argsForCall := fake.getSpacesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *CCClient) GetSpacesReturns(result1 []cc_client.Resource, result2 error) {
+	fake.getSpacesMutex.Lock()
+	defer fake.getSpacesMutex.Unlock()
+	fake.GetSpacesStub = nil
+	fake.getSpacesReturns = struct {
+		result1 []cc_client.Resource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CCClient) GetSpacesReturnsOnCall(i int, result1 []cc_client.Resource, result2 error) {
+	fake.getSpacesMutex.Lock()
+	defer fake.getSpacesMutex.Unlock()
+	fake.GetSpacesStub = nil
+	if fake.getSpacesReturnsOnCall == nil {
+		fake.getSpacesReturnsOnCall = make(map[int]struct {
+			result1 []cc_client.Resource
+			result2 error
+		})
+	}
+	fake.getSpacesReturnsOnCall[i] = struct {
+		result1 []cc_client.Resource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CCClient) GetSubjectRoles(arg1 string, arg2 string) ([]cc_client.Role, error) {
+	fake.getSubjectRolesMutex.Lock()
+	ret, specificReturn := fake.getSubjectRolesReturnsOnCall[len(fake.getSubjectRolesArgsForCall)]
+	fake.getSubjectRolesArgsForCall = append(fake.getSubjectRolesArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetSubjectRolesStub
+	fakeReturns := fake.getSubjectRolesReturns
+	fake.recordInvocation("GetSubjectRoles", []interface{}{arg1, arg2})
+	fake.getSubjectRolesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CCClient) GetSubjectRolesCallCount() int {
+	fake.getSubjectRolesMutex.RLock()
+	defer fake.getSubjectRolesMutex.RUnlock()
+	return len(fake.getSubjectRolesArgsForCall)
+}
+
+func (fake *CCClient) GetSubjectRolesCalls(stub func(string, string) ([]cc_client.Role, error)) {
+	fake.getSubjectRolesMutex.Lock()
+	defer fake.getSubjectRolesMutex.Unlock()
+	fake.GetSubjectRolesStub = stub
+}
+
+func (fake *CCClient) GetSubjectRolesArgsForCall(i int) (string, string) {
+	fake.getSubjectRolesMutex.RLock()
+	defer fake.getSubjectRolesMutex.RUnlock()
+	argsForCall := fake.getSubjectRolesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *CCClient) GetSubjectRolesReturns(result1 []cc_client.Role, result2 error) {
+	fake.getSubjectRolesMutex.Lock()
+	defer fake.getSubjectRolesMutex.Unlock()
+	fake.GetSubjectRolesStub = nil
+	fake.getSubjectRolesReturns = struct {
+		result1 []cc_client.Role
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CCClient) GetSubjectRolesReturnsOnCall(i int, result1 []cc_client.Role, result2 error) {
+	fake.getSubjectRolesMutex.Lock()
+	defer fake.getSubjectRolesMutex.Unlock()
+	fake.GetSubjectRolesStub = nil
+	if fake.getSubjectRolesReturnsOnCall == nil {
+		fake.getSubjectRolesReturnsOnCall = make(map[int]struct {
+			result1 []cc_client.Role
+			result2 error
+		})
+	}
+	fake.getSubjectRolesReturnsOnCall[i] = struct {
+		result1 []cc_client.Role
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *CCClient) GetSubjectSpace(arg1 string, arg2 string, arg3 cc_client.SpaceResponse) (*cc_client.SpaceResource, error) {
 	fake.getSubjectSpaceMutex.Lock()
 	ret, specificReturn := fake.getSubjectSpaceReturnsOnCall[len(fake.getSubjectSpaceArgsForCall)]
@@ -826,10 +1226,16 @@ func (fake *CCClient) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.getAppSpacesMutex.RLock()
 	defer fake.getAppSpacesMutex.RUnlock()
+	fake.getAppsMutex.RLock()
+	defer fake.getAppsMutex.RUnlock()
 	fake.getLiveAppGUIDsMutex.RLock()
 	defer fake.getLiveAppGUIDsMutex.RUnlock()
+	fake.getLiveOrgGUIDsMutex.RLock()
+	defer fake.getLiveOrgGUIDsMutex.RUnlock()
 	fake.getLiveSpaceGUIDsMutex.RLock()
 	defer fake.getLiveSpaceGUIDsMutex.RUnlock()
+	fake.getOrgsMutex.RLock()
+	defer fake.getOrgsMutex.RUnlock()
 	fake.getSecurityGroupsMutex.RLock()
 	defer fake.getSecurityGroupsMutex.RUnlock()
 	fake.getSecurityGroupsLastUpdateMutex.RLock()
@@ -840,6 +1246,10 @@ func (fake *CCClient) Invocations() map[string][][]interface{} {
 	defer fake.getSpaceMutex.RUnlock()
 	fake.getSpaceGUIDsMutex.RLock()
 	defer fake.getSpaceGUIDsMutex.RUnlock()
+	fake.getSpacesMutex.RLock()
+	defer fake.getSpacesMutex.RUnlock()
+	fake.getSubjectRolesMutex.RLock()
+	defer fake.getSubjectRolesMutex.RUnlock()
 	fake.getSubjectSpaceMutex.RLock()
 	defer fake.getSubjectSpaceMutex.RUnlock()
 	fake.getSubjectSpacesMutex.RLock()
@@ -0,0 +1,55 @@
+package fixtures
+
+const OrganizationV3LiveOrgs = `{
+   "pagination": {
+      "total_results": 2,
+      "total_pages": 1,
+      "first": {
+        "href": "https://foo.bar/v3/organizations?page=1"
+      },
+      "last": {
+        "href": "https://foo.bar/v3/organizations?page=1"
+      },
+      "next": null,
+      "previous": null
+   },
+   "resources": [
+      {
+         "guid": "live-org-1-guid",
+         "created_at": "2018-07-24T17:49:02Z",
+         "updated_at": "2018-07-24T17:49:02Z",
+         "name": "org-1"
+      },
+      {
+         "guid": "live-org-2-guid",
+         "created_at": "2018-07-24T17:49:02Z",
+         "updated_at": "2018-07-24T17:49:02Z",
+         "name": "org-2"
+      }
+   ]
+}`
+
+const OrganizationV3MultiplePages = `{
+   "pagination": {
+      "total_results": 2,
+      "total_pages": 2,
+      "first": {
+        "href": "https://foo.bar/v3/organizations?page=1"
+      },
+      "last": {
+        "href": "https://foo.bar/v3/organizations?page=2"
+      },
+      "next": {
+        "href": "https://foo.bar/v3/organizations?page=2"
+      },
+      "previous": null
+   },
+   "resources": [
+      {
+         "guid": "live-org-1-guid",
+         "created_at": "2018-07-24T17:49:02Z",
+         "updated_at": "2018-07-24T17:49:02Z",
+         "name": "org-1"
+      }
+   ]
+}`
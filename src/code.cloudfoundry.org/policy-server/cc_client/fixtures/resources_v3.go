@@ -0,0 +1,100 @@
+package fixtures
+
+const AppsV3WithNames = `{
+   "pagination": {
+      "total_results": 2,
+      "total_pages": 1,
+      "first": {
+        "href": "https://foo.bar/v3/apps?page=1"
+      },
+      "last": {
+        "href": "https://foo.bar/v3/apps?page=1"
+      },
+      "next": null,
+      "previous": null
+   },
+   "resources": [
+      {
+         "guid": "app-1-guid",
+         "name": "app-1",
+         "relationships": {
+            "space": {
+               "data": {
+                  "guid": "space-1-guid"
+               }
+            }
+         }
+      },
+      {
+         "guid": "app-2-guid",
+         "name": "app-2",
+         "relationships": {
+            "space": {
+               "data": {
+                  "guid": "space-2-guid"
+               }
+            }
+         }
+      }
+   ]
+}`
+
+const SpacesV3WithNamesPage1 = `{
+   "pagination": {
+      "total_results": 2,
+      "total_pages": 2,
+      "first": {
+        "href": "https://foo.bar/v3/spaces?names=space-1%2Cspace-2&page=1&per_page=1"
+      },
+      "last": {
+        "href": "https://foo.bar/v3/spaces?names=space-1%2Cspace-2&page=2&per_page=1"
+      },
+      "next": {
+        "href": "https://foo.bar/v3/spaces?names=space-1%2Cspace-2&page=2&per_page=1"
+      },
+      "previous": null
+   },
+   "resources": [
+      {
+         "guid": "space-1-guid",
+         "name": "space-1",
+         "relationships": {
+            "organization": {
+               "data": {
+                  "guid": "org-1-guid"
+               }
+            }
+         }
+      }
+   ]
+}`
+
+const SpacesV3WithNamesPage2 = `{
+   "pagination": {
+      "total_results": 2,
+      "total_pages": 2,
+      "first": {
+        "href": "https://foo.bar/v3/spaces?names=space-1%2Cspace-2&page=1&per_page=1"
+      },
+      "last": {
+        "href": "https://foo.bar/v3/spaces?names=space-1%2Cspace-2&page=2&per_page=1"
+      },
+      "next": null,
+      "previous": {
+        "href": "https://foo.bar/v3/spaces?names=space-1%2Cspace-2&page=1&per_page=1"
+      }
+   },
+   "resources": [
+      {
+         "guid": "space-2-guid",
+         "name": "space-2",
+         "relationships": {
+            "organization": {
+               "data": {
+                  "guid": "org-1-guid"
+               }
+            }
+         }
+      }
+   ]
+}`
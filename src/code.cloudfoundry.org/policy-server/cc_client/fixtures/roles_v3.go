@@ -0,0 +1,145 @@
+package fixtures
+
+const RolesV3Page1 = `{
+   "pagination": {
+      "total_results": 3,
+      "total_pages": 2,
+      "first": {
+        "href": "https://foo.bar/v3/roles?page=1&per_page=2&types=space_developer%2Cspace_manager%2Cspace_auditor%2Corg_manager&user_guids=some-subject-id"
+      },
+      "last": {
+        "href": "https://foo.bar/v3/roles?page=2&per_page=2&types=space_developer%2Cspace_manager%2Cspace_auditor%2Corg_manager&user_guids=some-subject-id"
+      },
+      "next": {
+        "href": "https://foo.bar/v3/roles?page=2&per_page=2&types=space_developer%2Cspace_manager%2Cspace_auditor%2Corg_manager&user_guids=some-subject-id"
+      },
+      "previous": null
+   },
+   "resources": [
+      {
+         "guid": "role-1-guid",
+         "type": "space_developer",
+         "relationships": {
+            "user": {
+               "data": {
+                  "guid": "some-subject-id"
+               }
+            },
+            "space": {
+               "data": {
+                  "guid": "space-1-guid"
+               }
+            },
+            "organization": {
+               "data": null
+            }
+         }
+      },
+      {
+         "guid": "role-2-guid",
+         "type": "space_auditor",
+         "relationships": {
+            "user": {
+               "data": {
+                  "guid": "some-subject-id"
+               }
+            },
+            "space": {
+               "data": {
+                  "guid": "space-2-guid"
+               }
+            },
+            "organization": {
+               "data": null
+            }
+         }
+      }
+   ]
+}`
+
+const RolesV3Page2 = `{
+   "pagination": {
+      "total_results": 3,
+      "total_pages": 2,
+      "first": {
+        "href": "https://foo.bar/v3/roles?page=1&per_page=2&types=space_developer%2Cspace_manager%2Cspace_auditor%2Corg_manager&user_guids=some-subject-id"
+      },
+      "last": {
+        "href": "https://foo.bar/v3/roles?page=2&per_page=2&types=space_developer%2Cspace_manager%2Cspace_auditor%2Corg_manager&user_guids=some-subject-id"
+      },
+      "next": null,
+      "previous": {
+        "href": "https://foo.bar/v3/roles?page=1&per_page=2&types=space_developer%2Cspace_manager%2Cspace_auditor%2Corg_manager&user_guids=some-subject-id"
+      }
+   },
+   "resources": [
+      {
+         "guid": "role-3-guid",
+         "type": "org_manager",
+         "relationships": {
+            "user": {
+               "data": {
+                  "guid": "some-subject-id"
+               }
+            },
+            "space": {
+               "data": null
+            },
+            "organization": {
+               "data": {
+                  "guid": "org-1-guid"
+               }
+            }
+         }
+      }
+   ]
+}`
+
+const SubjectRoles = `{
+   "pagination": {
+      "total_results": 2,
+      "total_pages": 1,
+      "next": null,
+      "previous": null
+   },
+   "resources": [
+      {
+         "guid": "role-1-guid",
+         "type": "space_developer",
+         "relationships": {
+            "user": {
+               "data": {
+                  "guid": "some-user-or-client-id"
+               }
+            },
+            "space": {
+               "data": {
+                  "guid": "space-1-guid"
+               }
+            },
+            "organization": {
+               "data": null
+            }
+         }
+      },
+      {
+         "guid": "role-2-guid",
+         "type": "space_auditor",
+         "relationships": {
+            "user": {
+               "data": {
+                  "guid": "some-user-or-client-id"
+               }
+            },
+            "space": {
+               "data": {
+                  "guid": "space-2-guid"
+               }
+            },
+            "organization": {
+               "data": null
+            }
+         }
+      }
+   ]
+}`
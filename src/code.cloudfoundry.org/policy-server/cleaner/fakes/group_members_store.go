@@ -0,0 +1,179 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type GroupMembersStore struct {
+	AllStub        func() ([]store.Policy, error)
+	allMutex       sync.RWMutex
+	allArgsForCall []struct {
+	}
+	allReturns struct {
+		result1 []store.Policy
+		result2 error
+	}
+	allReturnsOnCall map[int]struct {
+		result1 []store.Policy
+		result2 error
+	}
+	SetGroupMembersStub        func(map[string][]string) error
+	setGroupMembersMutex       sync.RWMutex
+	setGroupMembersArgsForCall []struct {
+		arg1 map[string][]string
+	}
+	setGroupMembersReturns struct {
+		result1 error
+	}
+	setGroupMembersReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *GroupMembersStore) All() ([]store.Policy, error) {
+	fake.allMutex.Lock()
+	ret, specificReturn := fake.allReturnsOnCall[len(fake.allArgsForCall)]
+	fake.allArgsForCall = append(fake.allArgsForCall, struct {
+	}{})
+	stub := fake.AllStub
+	fakeReturns := fake.allReturns
+	fake.recordInvocation("All", []interface{}{})
+	fake.allMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *GroupMembersStore) AllCallCount() int {
+	fake.allMutex.RLock()
+	defer fake.allMutex.RUnlock()
+	return len(fake.allArgsForCall)
+}
+
+func (fake *GroupMembersStore) AllCalls(stub func() ([]store.Policy, error)) {
+	fake.allMutex.Lock()
+	defer fake.allMutex.Unlock()
+	fake.AllStub = stub
+}
+
+func (fake *GroupMembersStore) AllReturns(result1 []store.Policy, result2 error) {
+	fake.allMutex.Lock()
+	defer fake.allMutex.Unlock()
+	fake.AllStub = nil
+	fake.allReturns = struct {
+		result1 []store.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *GroupMembersStore) AllReturnsOnCall(i int, result1 []store.Policy, result2 error) {
+	fake.allMutex.Lock()
+	defer fake.allMutex.Unlock()
+	fake.AllStub = nil
+	if fake.allReturnsOnCall == nil {
+		fake.allReturnsOnCall = make(map[int]struct {
+			result1 []store.Policy
+			result2 error
+		})
+	}
+	fake.allReturnsOnCall[i] = struct {
+		result1 []store.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *GroupMembersStore) SetGroupMembers(arg1 map[string][]string) error {
+	fake.setGroupMembersMutex.Lock()
+	ret, specificReturn := fake.setGroupMembersReturnsOnCall[len(fake.setGroupMembersArgsForCall)]
+	fake.setGroupMembersArgsForCall = append(fake.setGroupMembersArgsForCall, struct {
+		arg1 map[string][]string
+	}{arg1})
+	stub := fake.SetGroupMembersStub
+	fakeReturns := fake.setGroupMembersReturns
+	fake.recordInvocation("SetGroupMembers", []interface{}{arg1})
+	fake.setGroupMembersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *GroupMembersStore) SetGroupMembersCallCount() int {
+	fake.setGroupMembersMutex.RLock()
+	defer fake.setGroupMembersMutex.RUnlock()
+	return len(fake.setGroupMembersArgsForCall)
+}
+
+func (fake *GroupMembersStore) SetGroupMembersCalls(stub func(map[string][]string) error) {
+	fake.setGroupMembersMutex.Lock()
+	defer fake.setGroupMembersMutex.Unlock()
+	fake.SetGroupMembersStub = stub
+}
+
+func (fake *GroupMembersStore) SetGroupMembersArgsForCall(i int) map[string][]string {
+	fake.setGroupMembersMutex.RLock()
+	defer fake.setGroupMembersMutex.RUnlock()
+	argsForCall := fake.setGroupMembersArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *GroupMembersStore) SetGroupMembersReturns(result1 error) {
+	fake.setGroupMembersMutex.Lock()
+	defer fake.setGroupMembersMutex.Unlock()
+	fake.SetGroupMembersStub = nil
+	fake.setGroupMembersReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *GroupMembersStore) SetGroupMembersReturnsOnCall(i int, result1 error) {
+	fake.setGroupMembersMutex.Lock()
+	defer fake.setGroupMembersMutex.Unlock()
+	fake.SetGroupMembersStub = nil
+	if fake.setGroupMembersReturnsOnCall == nil {
+		fake.setGroupMembersReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setGroupMembersReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *GroupMembersStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.allMutex.RLock()
+	defer fake.allMutex.RUnlock()
+	fake.setGroupMembersMutex.RLock()
+	defer fake.setGroupMembersMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *GroupMembersStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
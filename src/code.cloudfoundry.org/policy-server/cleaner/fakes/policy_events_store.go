@@ -0,0 +1,113 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+	"time"
+)
+
+type PolicyEventsStore struct {
+	DeleteEventsBeforeStub        func(time.Time) (int, error)
+	deleteEventsBeforeMutex       sync.RWMutex
+	deleteEventsBeforeArgsForCall []struct {
+		arg1 time.Time
+	}
+	deleteEventsBeforeReturns struct {
+		result1 int
+		result2 error
+	}
+	deleteEventsBeforeReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PolicyEventsStore) DeleteEventsBefore(arg1 time.Time) (int, error) {
+	fake.deleteEventsBeforeMutex.Lock()
+	ret, specificReturn := fake.deleteEventsBeforeReturnsOnCall[len(fake.deleteEventsBeforeArgsForCall)]
+	fake.deleteEventsBeforeArgsForCall = append(fake.deleteEventsBeforeArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	stub := fake.DeleteEventsBeforeStub
+	fakeReturns := fake.deleteEventsBeforeReturns
+	fake.recordInvocation("DeleteEventsBefore", []interface{}{arg1})
+	fake.deleteEventsBeforeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PolicyEventsStore) DeleteEventsBeforeCallCount() int {
+	fake.deleteEventsBeforeMutex.RLock()
+	defer fake.deleteEventsBeforeMutex.RUnlock()
+	return len(fake.deleteEventsBeforeArgsForCall)
+}
+
+func (fake *PolicyEventsStore) DeleteEventsBeforeCalls(stub func(time.Time) (int, error)) {
+	fake.deleteEventsBeforeMutex.Lock()
+	defer fake.deleteEventsBeforeMutex.Unlock()
+	fake.DeleteEventsBeforeStub = stub
+}
+
+func (fake *PolicyEventsStore) DeleteEventsBeforeArgsForCall(i int) time.Time {
+	fake.deleteEventsBeforeMutex.RLock()
+	defer fake.deleteEventsBeforeMutex.RUnlock()
+	argsForCall := fake.deleteEventsBeforeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PolicyEventsStore) DeleteEventsBeforeReturns(result1 int, result2 error) {
+	fake.deleteEventsBeforeMutex.Lock()
+	defer fake.deleteEventsBeforeMutex.Unlock()
+	fake.DeleteEventsBeforeStub = nil
+	fake.deleteEventsBeforeReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyEventsStore) DeleteEventsBeforeReturnsOnCall(i int, result1 int, result2 error) {
+	fake.deleteEventsBeforeMutex.Lock()
+	defer fake.deleteEventsBeforeMutex.Unlock()
+	fake.DeleteEventsBeforeStub = nil
+	if fake.deleteEventsBeforeReturnsOnCall == nil {
+		fake.deleteEventsBeforeReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.deleteEventsBeforeReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyEventsStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.deleteEventsBeforeMutex.RLock()
+	defer fake.deleteEventsBeforeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PolicyEventsStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
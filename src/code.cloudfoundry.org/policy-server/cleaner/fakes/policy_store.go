@@ -3,6 +3,7 @@ package fakes
 
 import (
 	"sync"
+	"time"
 
 	"code.cloudfoundry.org/policy-server/store"
 )
@@ -20,15 +21,30 @@ type PolicyStore struct {
 		result1 []store.Policy
 		result2 error
 	}
-	DeleteStub        func([]store.Policy) error
-	deleteMutex       sync.RWMutex
-	deleteArgsForCall []struct {
+	DeleteExpiredStub        func(time.Time, store.Actor) ([]store.Policy, error)
+	deleteExpiredMutex       sync.RWMutex
+	deleteExpiredArgsForCall []struct {
+		arg1 time.Time
+		arg2 store.Actor
+	}
+	deleteExpiredReturns struct {
+		result1 []store.Policy
+		result2 error
+	}
+	deleteExpiredReturnsOnCall map[int]struct {
+		result1 []store.Policy
+		result2 error
+	}
+	DeleteWithEventStub        func([]store.Policy, store.Actor) error
+	deleteWithEventMutex       sync.RWMutex
+	deleteWithEventArgsForCall []struct {
 		arg1 []store.Policy
+		arg2 store.Actor
 	}
-	deleteReturns struct {
+	deleteWithEventReturns struct {
 		result1 error
 	}
-	deleteReturnsOnCall map[int]struct {
+	deleteWithEventReturnsOnCall map[int]struct {
 		result1 error
 	}
 	invocations      map[string][][]interface{}
@@ -91,23 +107,89 @@ func (fake *PolicyStore) AllReturnsOnCall(i int, result1 []store.Policy, result2
 	}{result1, result2}
 }
 
-func (fake *PolicyStore) Delete(arg1 []store.Policy) error {
+func (fake *PolicyStore) DeleteExpired(arg1 time.Time, arg2 store.Actor) ([]store.Policy, error) {
+	fake.deleteExpiredMutex.Lock()
+	ret, specificReturn := fake.deleteExpiredReturnsOnCall[len(fake.deleteExpiredArgsForCall)]
+	fake.deleteExpiredArgsForCall = append(fake.deleteExpiredArgsForCall, struct {
+		arg1 time.Time
+		arg2 store.Actor
+	}{arg1, arg2})
+	stub := fake.DeleteExpiredStub
+	fakeReturns := fake.deleteExpiredReturns
+	fake.recordInvocation("DeleteExpired", []interface{}{arg1, arg2})
+	fake.deleteExpiredMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PolicyStore) DeleteExpiredCallCount() int {
+	fake.deleteExpiredMutex.RLock()
+	defer fake.deleteExpiredMutex.RUnlock()
+	return len(fake.deleteExpiredArgsForCall)
+}
+
+func (fake *PolicyStore) DeleteExpiredCalls(stub func(time.Time, store.Actor) ([]store.Policy, error)) {
+	fake.deleteExpiredMutex.Lock()
+	defer fake.deleteExpiredMutex.Unlock()
+	fake.DeleteExpiredStub = stub
+}
+
+func (fake *PolicyStore) DeleteExpiredArgsForCall(i int) (time.Time, store.Actor) {
+	fake.deleteExpiredMutex.RLock()
+	defer fake.deleteExpiredMutex.RUnlock()
+	argsForCall := fake.deleteExpiredArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PolicyStore) DeleteExpiredReturns(result1 []store.Policy, result2 error) {
+	fake.deleteExpiredMutex.Lock()
+	defer fake.deleteExpiredMutex.Unlock()
+	fake.DeleteExpiredStub = nil
+	fake.deleteExpiredReturns = struct {
+		result1 []store.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyStore) DeleteExpiredReturnsOnCall(i int, result1 []store.Policy, result2 error) {
+	fake.deleteExpiredMutex.Lock()
+	defer fake.deleteExpiredMutex.Unlock()
+	fake.DeleteExpiredStub = nil
+	if fake.deleteExpiredReturnsOnCall == nil {
+		fake.deleteExpiredReturnsOnCall = make(map[int]struct {
+			result1 []store.Policy
+			result2 error
+		})
+	}
+	fake.deleteExpiredReturnsOnCall[i] = struct {
+		result1 []store.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyStore) DeleteWithEvent(arg1 []store.Policy, arg2 store.Actor) error {
 	var arg1Copy []store.Policy
 	if arg1 != nil {
 		arg1Copy = make([]store.Policy, len(arg1))
 		copy(arg1Copy, arg1)
 	}
-	fake.deleteMutex.Lock()
-	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
-	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+	fake.deleteWithEventMutex.Lock()
+	ret, specificReturn := fake.deleteWithEventReturnsOnCall[len(fake.deleteWithEventArgsForCall)]
+	fake.deleteWithEventArgsForCall = append(fake.deleteWithEventArgsForCall, struct {
 		arg1 []store.Policy
-	}{arg1Copy})
-	stub := fake.DeleteStub
-	fakeReturns := fake.deleteReturns
-	fake.recordInvocation("Delete", []interface{}{arg1Copy})
-	fake.deleteMutex.Unlock()
+		arg2 store.Actor
+	}{arg1Copy, arg2})
+	stub := fake.DeleteWithEventStub
+	fakeReturns := fake.deleteWithEventReturns
+	fake.recordInvocation("DeleteWithEvent", []interface{}{arg1Copy, arg2})
+	fake.deleteWithEventMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -115,44 +197,44 @@ func (fake *PolicyStore) Delete(arg1 []store.Policy) error {
 	return fakeReturns.result1
 }
 
-func (fake *PolicyStore) DeleteCallCount() int {
-	fake.deleteMutex.RLock()
-	defer fake.deleteMutex.RUnlock()
-	return len(fake.deleteArgsForCall)
+func (fake *PolicyStore) DeleteWithEventCallCount() int {
+	fake.deleteWithEventMutex.RLock()
+	defer fake.deleteWithEventMutex.RUnlock()
+	return len(fake.deleteWithEventArgsForCall)
 }
 
-func (fake *PolicyStore) DeleteCalls(stub func([]store.Policy) error) {
-	fake.deleteMutex.Lock()
-	defer fake.deleteMutex.Unlock()
-	fake.DeleteStub = stub
+func (fake *PolicyStore) DeleteWithEventCalls(stub func([]store.Policy, store.Actor) error) {
+	fake.deleteWithEventMutex.Lock()
+	defer fake.deleteWithEventMutex.Unlock()
+	fake.DeleteWithEventStub = stub
 }
 
-func (fake *PolicyStore) DeleteArgsForCall(i int) []store.Policy {
-	fake.deleteMutex.RLock()
-	defer fake.deleteMutex.RUnlock()
-	argsForCall := fake.deleteArgsForCall[i]
-	return argsForCall.arg1
+func (fake *PolicyStore) DeleteWithEventArgsForCall(i int) ([]store.Policy, store.Actor) {
+	fake.deleteWithEventMutex.RLock()
+	defer fake.deleteWithEventMutex.RUnlock()
+	argsForCall := fake.deleteWithEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *PolicyStore) DeleteReturns(result1 error) {
-	fake.deleteMutex.Lock()
-	defer fake.deleteMutex.Unlock()
-	fake.DeleteStub = nil
-	fake.deleteReturns = struct {
+func (fake *PolicyStore) DeleteWithEventReturns(result1 error) {
+	fake.deleteWithEventMutex.Lock()
+	defer fake.deleteWithEventMutex.Unlock()
+	fake.DeleteWithEventStub = nil
+	fake.deleteWithEventReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *PolicyStore) DeleteReturnsOnCall(i int, result1 error) {
-	fake.deleteMutex.Lock()
-	defer fake.deleteMutex.Unlock()
-	fake.DeleteStub = nil
-	if fake.deleteReturnsOnCall == nil {
-		fake.deleteReturnsOnCall = make(map[int]struct {
+func (fake *PolicyStore) DeleteWithEventReturnsOnCall(i int, result1 error) {
+	fake.deleteWithEventMutex.Lock()
+	defer fake.deleteWithEventMutex.Unlock()
+	fake.DeleteWithEventStub = nil
+	if fake.deleteWithEventReturnsOnCall == nil {
+		fake.deleteWithEventReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.deleteReturnsOnCall[i] = struct {
+	fake.deleteWithEventReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
@@ -162,8 +244,10 @@ func (fake *PolicyStore) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.allMutex.RLock()
 	defer fake.allMutex.RUnlock()
-	fake.deleteMutex.RLock()
-	defer fake.deleteMutex.RUnlock()
+	fake.deleteExpiredMutex.RLock()
+	defer fake.deleteExpiredMutex.RUnlock()
+	fake.deleteWithEventMutex.RLock()
+	defer fake.deleteWithEventMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
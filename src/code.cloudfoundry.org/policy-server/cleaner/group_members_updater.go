@@ -0,0 +1,125 @@
+package cleaner
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/cc_client"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+)
+
+//counterfeiter:generate -o fakes/group_members_store.go --fake-name GroupMembersStore . groupMembersStore
+type groupMembersStore interface {
+	All() ([]store.Policy, error)
+	SetGroupMembers(map[string][]string) error
+}
+
+// GroupMembersUpdater stores the apps of the spaces and orgs that are the
+// sources of policies. The internal API lists a policy from each of them, as
+// it cannot reach the Cloud-Controller to look them up itself.
+type GroupMembersUpdater struct {
+	Logger             lager.Logger
+	Store              groupMembersStore
+	UAAClient          uaa_client.UAAClient
+	CCClient           cc_client.CCClient
+	CCRequestChunkSize int
+}
+
+func NewGroupMembersUpdater(logger lager.Logger, store groupMembersStore, uaaClient uaa_client.UAAClient,
+	ccClient cc_client.CCClient, ccRequestChunkSize int) *GroupMembersUpdater {
+	return &GroupMembersUpdater{
+		Logger:             logger,
+		Store:              store,
+		UAAClient:          uaaClient,
+		CCClient:           ccClient,
+		CCRequestChunkSize: ccRequestChunkSize,
+	}
+}
+
+func (u *GroupMembersUpdater) Update() error {
+	policies, err := u.Store.All()
+	if err != nil {
+		u.Logger.Error("store-list-policies-failed", err)
+		return fmt.Errorf("database read failed for c2c policies: %s", err)
+	}
+
+	spaceGUIDs := policySourceGUIDs(policies, store.GroupTypeSpace)
+	orgGUIDs := policySourceGUIDs(policies, store.GroupTypeOrg)
+	members := map[string][]string{}
+	if len(spaceGUIDs) > 0 || len(orgGUIDs) > 0 {
+		members, err = u.getMembers(spaceGUIDs, orgGUIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = u.Store.SetGroupMembers(members)
+	if err != nil {
+		u.Logger.Error("store-set-group-members-failed", err)
+		return fmt.Errorf("database write failed: %s", err)
+	}
+	return nil
+}
+
+// getMembers looks up the apps of the spaces, and of every space of the orgs
+func (u *GroupMembersUpdater) getMembers(spaceGUIDs, orgGUIDs []string) (map[string][]string, error) {
+	token, err := u.UAAClient.GetToken()
+	if err != nil {
+		u.Logger.Error("get-uaa-token-failed", err)
+		return nil, fmt.Errorf("get UAA token failed: %s", err)
+	}
+
+	orgSpaces := map[string][]string{}
+	for _, orgGUIDchunk := range getChunks(orgGUIDs, u.CCRequestChunkSize) {
+		spaces, err := u.CCClient.GetSpaces(token, cc_client.ResourceFilter{ParentGUIDs: orgGUIDchunk})
+		if err != nil {
+			u.Logger.Error("cc-get-spaces-failed", err)
+			return nil, fmt.Errorf("get spaces from Cloud-Controller failed: %s", err)
+		}
+		for _, space := range spaces {
+			orgSpaces[space.ParentGUID] = append(orgSpaces[space.ParentGUID], space.GUID)
+		}
+	}
+
+	allSpaceGUIDs := append([]string{}, spaceGUIDs...)
+	for _, spaces := range orgSpaces {
+		allSpaceGUIDs = append(allSpaceGUIDs, spaces...)
+	}
+
+	spaceApps := map[string][]string{}
+	for _, spaceGUIDchunk := range getChunks(uniqueGUIDs(allSpaceGUIDs), u.CCRequestChunkSize) {
+		apps, err := u.CCClient.GetApps(token, cc_client.ResourceFilter{ParentGUIDs: spaceGUIDchunk})
+		if err != nil {
+			u.Logger.Error("cc-get-apps-failed", err)
+			return nil, fmt.Errorf("get apps from Cloud-Controller failed: %s", err)
+		}
+		for _, app := range apps {
+			spaceApps[app.ParentGUID] = append(spaceApps[app.ParentGUID], app.GUID)
+		}
+	}
+
+	members := map[string][]string{}
+	for _, spaceGUID := range spaceGUIDs {
+		members[spaceGUID] = append([]string{}, spaceApps[spaceGUID]...)
+	}
+	for _, orgGUID := range orgGUIDs {
+		members[orgGUID] = []string{}
+		for _, spaceGUID := range orgSpaces[orgGUID] {
+			members[orgGUID] = append(members[orgGUID], spaceApps[spaceGUID]...)
+		}
+	}
+	return members, nil
+}
+
+func uniqueGUIDs(guids []string) []string {
+	seen := map[string]bool{}
+	var unique []string
+	for _, guid := range guids {
+		if !seen[guid] {
+			seen[guid] = true
+			unique = append(unique, guid)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,158 @@
+package cleaner_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/cc_client"
+	ccfakes "code.cloudfoundry.org/policy-server/cc_client/fakes"
+	"code.cloudfoundry.org/policy-server/cleaner"
+	"code.cloudfoundry.org/policy-server/cleaner/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	uaafakes "code.cloudfoundry.org/policy-server/uaa_client/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GroupMembersUpdater", func() {
+	var (
+		updater       *cleaner.GroupMembersUpdater
+		fakeStore     *fakes.GroupMembersStore
+		fakeUAAClient *uaafakes.UAAClient
+		fakeCCClient  *ccfakes.CCClient
+		logger        *lagertest.TestLogger
+	)
+
+	var policyFrom = func(source store.Source) store.Policy {
+		return store.Policy{
+			Source:      source,
+			Destination: store.Destination{ID: "some-app-guid", Protocol: "tcp", Ports: store.Ports{Start: 8080, End: 8080}},
+		}
+	}
+
+	BeforeEach(func() {
+		fakeStore = &fakes.GroupMembersStore{}
+		fakeUAAClient = &uaafakes.UAAClient{}
+		fakeCCClient = &ccfakes.CCClient{}
+		logger = lagertest.NewTestLogger("test")
+		updater = cleaner.NewGroupMembersUpdater(logger, fakeStore, fakeUAAClient, fakeCCClient, 0)
+
+		fakeStore.AllReturns([]store.Policy{
+			policyFrom(store.Source{ID: "some-space-guid", Type: store.GroupTypeSpace}),
+			policyFrom(store.Source{ID: "some-org-guid", Type: store.GroupTypeOrg}),
+			policyFrom(store.Source{ID: "another-app-guid"}),
+		}, nil)
+		fakeUAAClient.GetTokenReturns("valid-token", nil)
+		fakeCCClient.GetSpacesReturns([]cc_client.Resource{
+			{GUID: "org-space-guid", ParentGUID: "some-org-guid"},
+			{GUID: "some-space-guid", ParentGUID: "some-org-guid"},
+		}, nil)
+		fakeCCClient.GetAppsReturns([]cc_client.Resource{
+			{GUID: "space-app-guid", ParentGUID: "some-space-guid"},
+			{GUID: "org-app-guid", ParentGUID: "org-space-guid"},
+		}, nil)
+	})
+
+	It("stores the apps of the spaces and orgs that are sources of policies", func() {
+		Expect(updater.Update()).To(Succeed())
+
+		Expect(fakeCCClient.GetSpacesCallCount()).To(Equal(1))
+		token, filter := fakeCCClient.GetSpacesArgsForCall(0)
+		Expect(token).To(Equal("valid-token"))
+		Expect(filter).To(Equal(cc_client.ResourceFilter{ParentGUIDs: []string{"some-org-guid"}}))
+
+		Expect(fakeCCClient.GetAppsCallCount()).To(Equal(1))
+		token, filter = fakeCCClient.GetAppsArgsForCall(0)
+		Expect(token).To(Equal("valid-token"))
+		Expect(filter.ParentGUIDs).To(ConsistOf("some-space-guid", "org-space-guid"))
+
+		Expect(fakeStore.SetGroupMembersCallCount()).To(Equal(1))
+		Expect(fakeStore.SetGroupMembersArgsForCall(0)).To(Equal(map[string][]string{
+			"some-space-guid": {"space-app-guid"},
+			"some-org-guid":   {"org-app-guid", "space-app-guid"},
+		}))
+	})
+
+	Context("when no policies are from spaces or orgs", func() {
+		BeforeEach(func() {
+			fakeStore.AllReturns([]store.Policy{policyFrom(store.Source{ID: "another-app-guid"})}, nil)
+		})
+
+		It("clears the members without calling the Cloud-Controller", func() {
+			Expect(updater.Update()).To(Succeed())
+
+			Expect(fakeUAAClient.GetTokenCallCount()).To(Equal(0))
+			Expect(fakeCCClient.GetAppsCallCount()).To(Equal(0))
+			Expect(fakeStore.SetGroupMembersArgsForCall(0)).To(Equal(map[string][]string{}))
+		})
+	})
+
+	Context("when a space has no apps", func() {
+		BeforeEach(func() {
+			fakeCCClient.GetAppsReturns([]cc_client.Resource{}, nil)
+		})
+
+		It("stores the space without apps", func() {
+			Expect(updater.Update()).To(Succeed())
+
+			Expect(fakeStore.SetGroupMembersArgsForCall(0)).To(Equal(map[string][]string{
+				"some-space-guid": {},
+				"some-org-guid":   {},
+			}))
+		})
+	})
+
+	Context("when listing the policies fails", func() {
+		BeforeEach(func() {
+			fakeStore.AllReturns(nil, errors.New("banana"))
+		})
+
+		It("returns a meaningful error", func() {
+			Expect(updater.Update()).To(MatchError("database read failed for c2c policies: banana"))
+			Expect(fakeStore.SetGroupMembersCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when getting the UAA token fails", func() {
+		BeforeEach(func() {
+			fakeUAAClient.GetTokenReturns("", errors.New("banana"))
+		})
+
+		It("returns a meaningful error", func() {
+			Expect(updater.Update()).To(MatchError("get UAA token failed: banana"))
+			Expect(fakeStore.SetGroupMembersCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when getting the spaces fails", func() {
+		BeforeEach(func() {
+			fakeCCClient.GetSpacesReturns(nil, errors.New("banana"))
+		})
+
+		It("keeps the members and returns a meaningful error", func() {
+			Expect(updater.Update()).To(MatchError("get spaces from Cloud-Controller failed: banana"))
+			Expect(fakeStore.SetGroupMembersCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when getting the apps fails", func() {
+		BeforeEach(func() {
+			fakeCCClient.GetAppsReturns(nil, errors.New("banana"))
+		})
+
+		It("keeps the members and returns a meaningful error", func() {
+			Expect(updater.Update()).To(MatchError("get apps from Cloud-Controller failed: banana"))
+			Expect(fakeStore.SetGroupMembersCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when storing the members fails", func() {
+		BeforeEach(func() {
+			fakeStore.SetGroupMembersReturns(errors.New("banana"))
+		})
+
+		It("returns a meaningful error", func() {
+			Expect(updater.Update()).To(MatchError("database write failed: banana"))
+		})
+	})
+})
@@ -3,18 +3,30 @@ package cleaner
 //go:generate counterfeiter -generate
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager/v3"
 	"code.cloudfoundry.org/policy-server/cc_client"
 	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/tracing"
 	"code.cloudfoundry.org/policy-server/uaa_client"
 )
 
 //counterfeiter:generate -o fakes/policy_store.go --fake-name PolicyStore . policyStore
 type policyStore interface {
 	All() ([]store.Policy, error)
-	Delete([]store.Policy) error
+	DeleteWithEvent([]store.Policy, store.Actor) error
+	DeleteExpired(time.Time, store.Actor) ([]store.Policy, error)
+}
+
+const metricPoliciesExpired = "PoliciesExpired"
+
+//counterfeiter:generate -o fakes/metrics_sender.go --fake-name MetricsSender . metricsSender
+type metricsSender interface {
+	IncrementCounter(string)
 }
 
 type PolicyCleaner struct {
@@ -23,48 +35,85 @@ type PolicyCleaner struct {
 	UAAClient             uaa_client.UAAClient
 	CCClient              cc_client.CCClient
 	CCAppRequestChunkSize int
+	MetricsSender         metricsSender
+	Clock                 clock.Clock
 }
 
 func NewPolicyCleaner(logger lager.Logger, store policyStore, uaaClient uaa_client.UAAClient,
-	ccClient cc_client.CCClient, ccAppRequestChunkSize int) *PolicyCleaner {
+	ccClient cc_client.CCClient, ccAppRequestChunkSize int, metricsSender metricsSender) *PolicyCleaner {
 	return &PolicyCleaner{
 		Logger:                logger,
 		Store:                 store,
 		UAAClient:             uaaClient,
 		CCClient:              ccClient,
 		CCAppRequestChunkSize: ccAppRequestChunkSize,
+		MetricsSender:         metricsSender,
+		Clock:                 clock.NewClock(),
 	}
 }
 
+// Traced returns a copy of the cleaner whose clients and store record spans
+// as children of the span in the context
+func (p *PolicyCleaner) Traced(ctx context.Context) interface{} {
+	return tracing.BindFields(ctx, p)
+}
+
+// DeleteStalePolicies deletes the expired policies, and then the policies of
+// apps, spaces and orgs that no longer exist. The expired policies are deleted
+// and returned even when looking up the stale ones fails.
 func (p *PolicyCleaner) DeleteStalePolicies() ([]store.Policy, error) {
+	expiredPolicies, err := p.deleteExpiredPolicies()
+	if err != nil {
+		return []store.Policy{}, err
+	}
+
 	policies, err := p.Store.All()
 	if err != nil {
 		p.Logger.Error("store-list-policies-failed", err)
-		return []store.Policy{}, fmt.Errorf("database read failed for c2c policies: %s", err)
+		return expiredPolicies, fmt.Errorf("database read failed for c2c policies: %s", err)
 	}
 
 	token, err := p.UAAClient.GetToken()
 	if err != nil {
 		p.Logger.Error("get-uaa-token-failed", err)
-		return []store.Policy{}, fmt.Errorf("get UAA token failed: %s", err)
+		return expiredPolicies, fmt.Errorf("get UAA token failed: %s", err)
 	}
 
 	policiesToDelete, err := p.getC2CPoliciesToDelete(policies, token)
 	if err != nil {
-		return []store.Policy{}, err
+		return expiredPolicies, err
 	}
 
 	p.Logger.Info("deleting stale policies:", lager.Data{
 		"total_c2c_policies": len(policiesToDelete),
 		"stale_c2c_policies": policiesToDelete,
 	})
-	err = p.Store.Delete(policiesToDelete)
+	err = p.Store.DeleteWithEvent(policiesToDelete, store.SystemActor)
 	if err != nil {
 		p.Logger.Error("store-delete-policies-failed", err)
-		return []store.Policy{}, fmt.Errorf("database write failed: %s", err)
+		return expiredPolicies, fmt.Errorf("database write failed: %s", err)
+	}
+
+	return append(policiesToDelete, expiredPolicies...), nil
+}
+
+// deleteExpiredPolicies deletes the policies that have expired by now in one
+// transaction, so that a policy whose expiry is extended meanwhile is kept
+func (p *PolicyCleaner) deleteExpiredPolicies() ([]store.Policy, error) {
+	expiredPolicies, err := p.Store.DeleteExpired(p.Clock.Now(), store.SystemActor)
+	if err != nil {
+		p.Logger.Error("store-delete-expired-policies-failed", err)
+		return nil, fmt.Errorf("database write failed: %s", err)
 	}
 
-	return policiesToDelete, nil
+	for _, policy := range expiredPolicies {
+		p.Logger.Info("policy-expired", lager.Data{
+			"policy":     policy,
+			"expires_at": policy.ExpiresAt.UTC().Format(time.RFC3339),
+		})
+		p.MetricsSender.IncrementCounter(metricPoliciesExpired)
+	}
+	return expiredPolicies, nil
 }
 
 func (p *PolicyCleaner) DeleteStalePoliciesWrapper() error {
@@ -91,6 +140,34 @@ func (p *PolicyCleaner) getC2CPoliciesToDelete(policies []store.Policy, token st
 		c2cPoliciesToDelete = append(c2cPoliciesToDelete, toDelete...)
 	}
 
+	spaceGUIDs := policySourceGUIDs(policies, store.GroupTypeSpace)
+	for _, spaceGUIDchunk := range getChunks(spaceGUIDs, p.CCAppRequestChunkSize) {
+		liveSpaceGUIDs, err := p.CCClient.GetLiveSpaceGUIDs(token, spaceGUIDchunk)
+		if err != nil {
+			p.Logger.Error("cc-get-space-guids-failed", err)
+			return nil, fmt.Errorf("get space guids from Cloud-Controller failed: %s", err)
+		}
+
+		staleSpaceGUIDs := getStaleAppGUIDs(liveSpaceGUIDs, spaceGUIDchunk)
+		toDelete := getStaleSourcePolicies(policies, staleSpaceGUIDs, c2cPoliciesToDelete)
+
+		c2cPoliciesToDelete = append(c2cPoliciesToDelete, toDelete...)
+	}
+
+	orgGUIDs := policySourceGUIDs(policies, store.GroupTypeOrg)
+	for _, orgGUIDchunk := range getChunks(orgGUIDs, p.CCAppRequestChunkSize) {
+		liveOrgGUIDs, err := p.CCClient.GetLiveOrgGUIDs(token, orgGUIDchunk)
+		if err != nil {
+			p.Logger.Error("cc-get-org-guids-failed", err)
+			return nil, fmt.Errorf("get org guids from Cloud-Controller failed: %s", err)
+		}
+
+		staleOrgGUIDs := getStaleAppGUIDs(liveOrgGUIDs, orgGUIDchunk)
+		toDelete := getStaleSourcePolicies(policies, staleOrgGUIDs, c2cPoliciesToDelete)
+
+		c2cPoliciesToDelete = append(c2cPoliciesToDelete, toDelete...)
+	}
+
 	return c2cPoliciesToDelete, nil
 }
 
@@ -116,10 +193,54 @@ func getStalePolicies(policyList []store.Policy, staleAppGUIDs map[string]struct
 	return stalePolicies
 }
 
+// getStaleSourcePolicies returns the space and org policies whose source is
+// stale, skipping those that are already being deleted for a stale destination
+func getStaleSourcePolicies(policyList []store.Policy, staleGUIDs map[string]struct{}, alreadyStale []store.Policy) []store.Policy {
+	var stalePolicies []store.Policy
+	for _, p := range policyList {
+		if p.Source.Type == "" {
+			continue
+		}
+		if _, found := staleGUIDs[p.Source.ID]; !found {
+			continue
+		}
+		if containsPolicy(alreadyStale, p) {
+			continue
+		}
+		stalePolicies = append(stalePolicies, p)
+	}
+	return stalePolicies
+}
+
+func containsPolicy(policyList []store.Policy, policy store.Policy) bool {
+	for _, p := range policyList {
+		if p.Equals(policy) {
+			return true
+		}
+	}
+	return false
+}
+
+func policySourceGUIDs(policyList []store.Policy, sourceType string) []string {
+	guidSet := make(map[string]struct{})
+	for _, p := range policyList {
+		if p.Source.Type == sourceType {
+			guidSet[p.Source.ID] = struct{}{}
+		}
+	}
+	var guids []string
+	for guid := range guidSet {
+		guids = append(guids, guid)
+	}
+	return guids
+}
+
 func policyAppGUIDs(policyList []store.Policy) []string {
 	appGUIDset := make(map[string]struct{})
 	for _, p := range policyList {
-		appGUIDset[p.Source.ID] = struct{}{}
+		if p.Source.Type == "" {
+			appGUIDset[p.Source.ID] = struct{}{}
+		}
 		appGUIDset[p.Destination.ID] = struct{}{}
 	}
 	var appGUIDs []string
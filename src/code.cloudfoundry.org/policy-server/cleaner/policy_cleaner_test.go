@@ -2,7 +2,9 @@ package cleaner_test
 
 import (
 	"errors"
+	"time"
 
+	"code.cloudfoundry.org/clock/fakeclock"
 	"code.cloudfoundry.org/lager/v3/lagertest"
 	ccfakes "code.cloudfoundry.org/policy-server/cc_client/fakes"
 	"code.cloudfoundry.org/policy-server/cleaner"
@@ -20,6 +22,8 @@ var _ = Describe("PolicyCleaner", func() {
 		fakeStore     *fakes.PolicyStore
 		fakeUAAClient *uaafakes.UAAClient
 		fakeCCClient  *ccfakes.CCClient
+		fakeMetrics   *fakes.MetricsSender
+		fakeClock     *fakeclock.FakeClock
 		logger        *lagertest.TestLogger
 		c2cPolicies   []store.Policy
 	)
@@ -63,8 +67,11 @@ var _ = Describe("PolicyCleaner", func() {
 		fakeStore = &fakes.PolicyStore{}
 		fakeUAAClient = &uaafakes.UAAClient{}
 		fakeCCClient = &ccfakes.CCClient{}
+		fakeMetrics = &fakes.MetricsSender{}
+		fakeClock = fakeclock.NewFakeClock(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC))
 		logger = lagertest.NewTestLogger("test")
-		policyCleaner = cleaner.NewPolicyCleaner(logger, fakeStore, fakeUAAClient, fakeCCClient, 0)
+		policyCleaner = cleaner.NewPolicyCleaner(logger, fakeStore, fakeUAAClient, fakeCCClient, 0, fakeMetrics)
+		policyCleaner.Clock = fakeClock
 
 		fakeUAAClient.GetTokenReturns("valid-token", nil)
 		fakeStore.AllReturns(c2cPolicies, nil)
@@ -92,8 +99,10 @@ var _ = Describe("PolicyCleaner", func() {
 
 		stalePolicies := c2cPolicies[1:]
 
-		Expect(fakeStore.DeleteCallCount()).To(Equal(1))
-		Expect(fakeStore.DeleteArgsForCall(0)).To(Equal(stalePolicies))
+		Expect(fakeStore.DeleteWithEventCallCount()).To(Equal(1))
+		policies, actor := fakeStore.DeleteWithEventArgsForCall(0)
+		Expect(policies).To(Equal(stalePolicies))
+		Expect(actor).To(Equal(store.SystemActor))
 
 		Expect(logger).To(gbytes.Say("deleting stale policies:.*c2c_policies.*dead-guid.*dead-guid.*total_c2c_policies\":2"))
 		Expect(deletedPolicies).To(Equal(stalePolicies))
@@ -107,6 +116,8 @@ var _ = Describe("PolicyCleaner", func() {
 				UAAClient:             fakeUAAClient,
 				CCClient:              fakeCCClient,
 				CCAppRequestChunkSize: 1,
+				MetricsSender:         fakeMetrics,
+				Clock:                 fakeClock,
 			}
 		})
 
@@ -127,9 +138,9 @@ var _ = Describe("PolicyCleaner", func() {
 			))
 
 			stalePolicies := c2cPolicies[1:]
-			Expect(fakeStore.DeleteCallCount()).To(Equal(1))
+			Expect(fakeStore.DeleteWithEventCallCount()).To(Equal(1))
 
-			deletedPolicies := fakeStore.DeleteArgsForCall(0)
+			deletedPolicies, _ := fakeStore.DeleteWithEventArgsForCall(0)
 			Expect(deletedPolicies).To(Equal(stalePolicies))
 
 			Expect(logger).To(gbytes.Say("deleting stale policies:.*c2c_policies.*dead-guid.*dead-guid.*total_c2c_policies\":2"))
@@ -139,6 +150,170 @@ var _ = Describe("PolicyCleaner", func() {
 		})
 	})
 
+	Context("when there are space and org policies", func() {
+		var sourcePolicies []store.Policy
+
+		BeforeEach(func() {
+			sourcePolicies = []store.Policy{{
+				Source:      store.Source{ID: "live-space-guid", Type: store.GroupTypeSpace},
+				Destination: store.Destination{ID: "live-guid", Protocol: "tcp", Ports: store.Ports{Start: 8080, End: 8080}},
+			}, {
+				Source:      store.Source{ID: "dead-space-guid", Type: store.GroupTypeSpace},
+				Destination: store.Destination{ID: "live-guid", Protocol: "tcp", Ports: store.Ports{Start: 8080, End: 8080}},
+			}, {
+				Source:      store.Source{ID: "dead-org-guid", Type: store.GroupTypeOrg},
+				Destination: store.Destination{ID: "dead-guid", Protocol: "tcp", Ports: store.Ports{Start: 8080, End: 8080}},
+			}, {
+				Source:      store.Source{ID: "live-org-guid", Type: store.GroupTypeOrg},
+				Destination: store.Destination{ID: "live-guid", Protocol: "tcp", Ports: store.Ports{Start: 8080, End: 8080}},
+			}}
+			fakeStore.AllReturns(sourcePolicies, nil)
+			fakeCCClient.GetLiveSpaceGUIDsReturns(map[string]struct{}{"live-space-guid": {}}, nil)
+			fakeCCClient.GetLiveOrgGUIDsReturns(map[string]struct{}{"live-org-guid": {}}, nil)
+		})
+
+		It("checks the sources against the live spaces and orgs", func() {
+			deletedPolicies, err := policyCleaner.DeleteStalePolicies()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, guids := fakeCCClient.GetLiveAppGUIDsArgsForCall(0)
+			Expect(guids).To(ConsistOf("live-guid", "dead-guid"))
+
+			Expect(fakeCCClient.GetLiveSpaceGUIDsCallCount()).To(Equal(1))
+			token, spaceGUIDs := fakeCCClient.GetLiveSpaceGUIDsArgsForCall(0)
+			Expect(token).To(Equal("valid-token"))
+			Expect(spaceGUIDs).To(ConsistOf("live-space-guid", "dead-space-guid"))
+
+			Expect(fakeCCClient.GetLiveOrgGUIDsCallCount()).To(Equal(1))
+			_, orgGUIDs := fakeCCClient.GetLiveOrgGUIDsArgsForCall(0)
+			Expect(orgGUIDs).To(ConsistOf("live-org-guid", "dead-org-guid"))
+
+			Expect(deletedPolicies).To(ConsistOf(sourcePolicies[1], sourcePolicies[2]))
+			policies, _ := fakeStore.DeleteWithEventArgsForCall(0)
+			Expect(policies).To(HaveLen(2))
+		})
+
+		Context("when getting the spaces from the Cloud-Controller fails", func() {
+			BeforeEach(func() {
+				fakeCCClient.GetLiveSpaceGUIDsReturns(nil, errors.New("potato"))
+			})
+
+			It("returns a meaningful error", func() {
+				_, err := policyCleaner.DeleteStalePolicies()
+				Expect(err).To(MatchError("get space guids from Cloud-Controller failed: potato"))
+				Expect(logger).To(gbytes.Say("cc-get-space-guids-failed.*potato"))
+			})
+		})
+
+		Context("when getting the orgs from the Cloud-Controller fails", func() {
+			BeforeEach(func() {
+				fakeCCClient.GetLiveOrgGUIDsReturns(nil, errors.New("potato"))
+			})
+
+			It("returns a meaningful error", func() {
+				_, err := policyCleaner.DeleteStalePolicies()
+				Expect(err).To(MatchError("get org guids from Cloud-Controller failed: potato"))
+				Expect(logger).To(gbytes.Say("cc-get-org-guids-failed.*potato"))
+			})
+		})
+	})
+
+	Context("when some policies have expired", func() {
+		var expiredPolicy store.Policy
+
+		BeforeEach(func() {
+			expiresAt := fakeClock.Now().Add(-time.Minute)
+			notExpiresAt := fakeClock.Now().Add(time.Minute)
+			expiredPolicy = store.Policy{
+				Source:      store.Source{ID: "live-guid"},
+				Destination: store.Destination{ID: "live-guid", Protocol: "tcp", Ports: store.Ports{Start: 9000, End: 9000}},
+				ExpiresAt:   &expiresAt,
+			}
+			c2cPolicies[0].ExpiresAt = &notExpiresAt
+			fakeStore.DeleteExpiredReturns([]store.Policy{expiredPolicy}, nil)
+		})
+
+		It("deletes the expired policies along with the stale ones", func() {
+			deletedPolicies, err := policyCleaner.DeleteStalePolicies()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.DeleteExpiredCallCount()).To(Equal(1))
+			now, actor := fakeStore.DeleteExpiredArgsForCall(0)
+			Expect(now).To(Equal(fakeClock.Now()))
+			Expect(actor).To(Equal(store.SystemActor))
+
+			Expect(fakeStore.DeleteWithEventCallCount()).To(Equal(1))
+			stalePolicies, _ := fakeStore.DeleteWithEventArgsForCall(0)
+			Expect(stalePolicies).To(Equal(c2cPolicies[1:]))
+
+			Expect(deletedPolicies).To(ConsistOf(expiredPolicy, c2cPolicies[1], c2cPolicies[2]))
+		})
+
+		It("emits a metric and logs each expired policy", func() {
+			_, err := policyCleaner.DeleteStalePolicies()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetrics.IncrementCounterCallCount()).To(Equal(1))
+			Expect(fakeMetrics.IncrementCounterArgsForCall(0)).To(Equal("PoliciesExpired"))
+			Expect(logger).To(gbytes.Say(`policy-expired.*"expires_at":"2030-01-02T03:03:05Z"`))
+		})
+
+		Context("when getting the UAA token fails", func() {
+			BeforeEach(func() {
+				fakeUAAClient.GetTokenReturns("", errors.New("potato"))
+			})
+
+			It("still deletes and returns the expired policies", func() {
+				deletedPolicies, err := policyCleaner.DeleteStalePolicies()
+				Expect(err).To(MatchError("get UAA token failed: potato"))
+				Expect(deletedPolicies).To(Equal([]store.Policy{expiredPolicy}))
+
+				Expect(fakeStore.DeleteExpiredCallCount()).To(Equal(1))
+				Expect(fakeStore.DeleteWithEventCallCount()).To(Equal(0))
+				Expect(fakeMetrics.IncrementCounterCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when getting the apps from the Cloud-Controller fails", func() {
+			BeforeEach(func() {
+				fakeCCClient.GetLiveAppGUIDsReturns(nil, errors.New("potato"))
+			})
+
+			It("still deletes and returns the expired policies", func() {
+				deletedPolicies, err := policyCleaner.DeleteStalePolicies()
+				Expect(err).To(MatchError("get app guids from Cloud-Controller failed: potato"))
+				Expect(deletedPolicies).To(Equal([]store.Policy{expiredPolicy}))
+				Expect(fakeStore.DeleteWithEventCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when listing the remaining policies fails", func() {
+			BeforeEach(func() {
+				fakeStore.AllReturns(nil, errors.New("potato"))
+			})
+
+			It("still returns the expired policies", func() {
+				deletedPolicies, err := policyCleaner.DeleteStalePolicies()
+				Expect(err).To(MatchError("database read failed for c2c policies: potato"))
+				Expect(deletedPolicies).To(Equal([]store.Policy{expiredPolicy}))
+			})
+		})
+
+		Context("when deleting the expired policies fails", func() {
+			BeforeEach(func() {
+				fakeStore.DeleteExpiredReturns(nil, errors.New("potato"))
+			})
+
+			It("returns a meaningful error and does not go on", func() {
+				_, err := policyCleaner.DeleteStalePolicies()
+				Expect(err).To(MatchError("database write failed: potato"))
+				Expect(logger).To(gbytes.Say("store-delete-expired-policies-failed.*potato"))
+				Expect(fakeMetrics.IncrementCounterCallCount()).To(Equal(0))
+				Expect(fakeStore.AllCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Context("When retrieving policies from the db fails", func() {
 		BeforeEach(func() {
 			fakeStore.AllReturns([]store.Policy{}, errors.New("potato"))
@@ -189,7 +364,7 @@ var _ = Describe("PolicyCleaner", func() {
 
 	Context("When deleting the policies fails", func() {
 		BeforeEach(func() {
-			fakeStore.DeleteReturns(errors.New("potato"))
+			fakeStore.DeleteWithEventReturns(errors.New("potato"))
 		})
 
 		It("returns a meaningful error", func() {
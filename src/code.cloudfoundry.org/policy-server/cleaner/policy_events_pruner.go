@@ -0,0 +1,49 @@
+package cleaner
+
+import (
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager/v3"
+)
+
+//counterfeiter:generate -o fakes/policy_events_store.go --fake-name PolicyEventsStore . policyEventsStore
+type policyEventsStore interface {
+	DeleteEventsBefore(time.Time) (int, error)
+}
+
+// PolicyEventsPruner deletes the policy events that are older than the
+// retention, so that the history of policy changes does not grow forever
+type PolicyEventsPruner struct {
+	Logger    lager.Logger
+	Store     policyEventsStore
+	Retention time.Duration
+	Clock     clock.Clock
+}
+
+func NewPolicyEventsPruner(logger lager.Logger, store policyEventsStore, retention time.Duration) *PolicyEventsPruner {
+	return &PolicyEventsPruner{
+		Logger:    logger,
+		Store:     store,
+		Retention: retention,
+		Clock:     clock.NewClock(),
+	}
+}
+
+func (p *PolicyEventsPruner) Prune() error {
+	before := p.Clock.Now().Add(-p.Retention)
+	deleted, err := p.Store.DeleteEventsBefore(before)
+	if err != nil {
+		p.Logger.Error("store-delete-policy-events-failed", err)
+		return fmt.Errorf("database write failed: %s", err)
+	}
+
+	if deleted > 0 {
+		p.Logger.Info("pruned-policy-events", lager.Data{
+			"deleted": deleted,
+			"before":  before.UTC().Format(time.RFC3339),
+		})
+	}
+	return nil
+}
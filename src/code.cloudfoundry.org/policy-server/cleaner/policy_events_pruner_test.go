@@ -0,0 +1,56 @@
+package cleaner_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/cleaner"
+	"code.cloudfoundry.org/policy-server/cleaner/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("PolicyEventsPruner", func() {
+	var (
+		pruner    *cleaner.PolicyEventsPruner
+		fakeStore *fakes.PolicyEventsStore
+		fakeClock *fakeclock.FakeClock
+		logger    *lagertest.TestLogger
+		now       time.Time
+	)
+
+	BeforeEach(func() {
+		fakeStore = &fakes.PolicyEventsStore{}
+		logger = lagertest.NewTestLogger("test")
+		now = time.Date(2030, 1, 31, 12, 0, 0, 0, time.UTC)
+		fakeClock = fakeclock.NewFakeClock(now)
+		pruner = cleaner.NewPolicyEventsPruner(logger, fakeStore, 30*24*time.Hour)
+		pruner.Clock = fakeClock
+
+		fakeStore.DeleteEventsBeforeReturns(3, nil)
+	})
+
+	It("deletes the events that are older than the retention", func() {
+		err := pruner.Prune()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeStore.DeleteEventsBeforeCallCount()).To(Equal(1))
+		Expect(fakeStore.DeleteEventsBeforeArgsForCall(0)).To(Equal(time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)))
+		Expect(logger).To(gbytes.Say("pruned-policy-events.*\"deleted\":3"))
+	})
+
+	Context("when deleting the events fails", func() {
+		BeforeEach(func() {
+			fakeStore.DeleteEventsBeforeReturns(0, errors.New("potato"))
+		})
+
+		It("returns a meaningful error", func() {
+			err := pruner.Prune()
+			Expect(err).To(MatchError("database write failed: potato"))
+			Expect(logger).To(gbytes.Say("store-delete-policy-events-failed.*potato"))
+		})
+	})
+})
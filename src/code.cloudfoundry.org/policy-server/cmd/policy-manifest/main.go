@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/cf-networking-helpers/json_client"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/api"
+)
+
+const usage = `usage: policy-manifest -api <url> -token <token> [-skip-ssl-validation] <command>
+
+commands:
+  export [-id <guid,...>]       print the policies as a manifest
+  import -file <path> [-yes]    show what importing the manifest changes, then apply it
+`
+
+func main() {
+	err := mainWithError(os.Args[1:], os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "policy-manifest: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func mainWithError(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("policy-manifest", flag.ContinueOnError)
+	apiURL := flags.String("api", "", "url of the policy server, e.g. https://api.example.com")
+	token := flags.String("token", "", "network.admin token, e.g. from 'cf oauth-token'")
+	skipSSLValidation := flags.Bool("skip-ssl-validation", false, "skip verification of the policy server certificate")
+	flags.Usage = func() { fmt.Fprint(flags.Output(), usage) }
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *apiURL == "" || *token == "" || flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("missing arguments")
+	}
+
+	httpClient := &http.Client{
+		Timeout: 60 * time.Second,
+		Transport: &http.Transport{
+			// #nosec G402 - only skipped when the operator asks for it
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: *skipSSLValidation},
+		},
+	}
+	client := json_client.New(lager.NewLogger("policy-manifest"), httpClient, strings.TrimSuffix(*apiURL, "/"))
+	// 'cf oauth-token' prints the token with its type
+	authToken := *token
+	if !strings.HasPrefix(strings.ToLower(authToken), "bearer ") {
+		authToken = "Bearer " + authToken
+	}
+
+	command, commandArgs := flags.Arg(0), flags.Args()[1:]
+	switch command {
+	case "export":
+		return exportPolicies(client, authToken, commandArgs, stdout)
+	case "import":
+		return importPolicies(client, authToken, commandArgs, stdin, stdout)
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown command '%s'", command)
+	}
+}
+
+func exportPolicies(client json_client.JsonClient, authToken string, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	ids := flags.String("id", "", "comma separated app, space or org guids whose policies are exported")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	route := "/networking/v1/external/policies/export"
+	if *ids != "" {
+		route += "?" + url.Values{"id": {*ids}}.Encode()
+	}
+
+	var manifest api.PolicyManifest
+	err = client.Do("GET", route, nil, &manifest, authToken)
+	if err != nil {
+		return fmt.Errorf("exporting policies: %s", err)
+	}
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %s", err)
+	}
+	_, err = fmt.Fprintln(stdout, string(manifestBytes))
+	return err
+}
+
+func importPolicies(client json_client.JsonClient, authToken string, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := flags.String("file", "", "path to the manifest, or - for stdin")
+	yes := flags.Bool("yes", false, "apply the changes without asking")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("missing -file")
+	}
+
+	var manifestBytes []byte
+	if *file == "-" {
+		manifestBytes, err = io.ReadAll(stdin)
+	} else {
+		manifestBytes, err = os.ReadFile(*file)
+	}
+	if err != nil {
+		return fmt.Errorf("reading manifest: %s", err)
+	}
+
+	var manifest api.PolicyManifest
+	err = json.Unmarshal(manifestBytes, &manifest)
+	if err != nil {
+		return fmt.Errorf("parsing manifest: %s", err)
+	}
+
+	var diff api.PolicyImportPayload
+	err = client.Do("POST", "/networking/v1/external/policies/import?dry_run=true", manifest, &diff, authToken)
+	if err != nil {
+		return fmt.Errorf("previewing import: %s", err)
+	}
+
+	printDiff(stdout, diff)
+	if len(diff.Create) == 0 && len(diff.Update) == 0 {
+		fmt.Fprintln(stdout, "nothing to import")
+		return nil
+	}
+
+	if !*yes {
+		if *file == "-" {
+			return errors.New("the manifest was read from stdin, pass -yes to apply it")
+		}
+		fmt.Fprint(stdout, "apply these changes? [y/N] ")
+		answer, _ := bufio.NewReader(stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Fprintln(stdout, "import cancelled")
+			return nil
+		}
+	}
+
+	err = client.Do("POST", "/networking/v1/external/policies/import", manifest, &diff, authToken)
+	if err != nil {
+		return fmt.Errorf("importing policies: %s", err)
+	}
+	fmt.Fprintf(stdout, "created %d and updated %d policies\n", len(diff.Create), len(diff.Update))
+	return nil
+}
+
+func printDiff(stdout io.Writer, diff api.PolicyImportPayload) {
+	for _, policy := range diff.Create {
+		fmt.Fprintf(stdout, "+ %s\n", describePolicy(policy))
+	}
+	for _, policy := range diff.Update {
+		fmt.Fprintf(stdout, "~ %s\n", describePolicy(policy))
+	}
+	fmt.Fprintf(stdout, "%d to create, %d to update, %d unchanged\n", len(diff.Create), len(diff.Update), len(diff.Unchanged))
+}
+
+func describePolicy(policy api.ManifestPolicy) string {
+	ports := ""
+	switch {
+	case policy.Destination.ICMPType != nil:
+		ports = fmt.Sprintf(" type %d", *policy.Destination.ICMPType)
+		if policy.Destination.ICMPCode != nil {
+			ports += fmt.Sprintf(" code %d", *policy.Destination.ICMPCode)
+		}
+	case policy.Destination.Ports.Start != 0:
+		ports = fmt.Sprintf(" %d-%d", policy.Destination.Ports.Start, policy.Destination.Ports.End)
+	}
+
+	action := "allow"
+	if policy.Action != "" {
+		action = policy.Action
+	}
+	return fmt.Sprintf("%s %s -> %s %s%s", action, describeGroup(policy.Source),
+		describeGroup(policy.Destination.ManifestGroup), policy.Destination.Protocol, ports)
+}
+
+func describeGroup(group api.ManifestGroup) string {
+	return strings.Join(nonEmpty(group.Org, group.Space, group.App), "/")
+}
+
+func nonEmpty(values ...string) []string {
+	var result []string
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -24,6 +24,7 @@ import (
 	"code.cloudfoundry.org/policy-server/asg_syncer"
 	"code.cloudfoundry.org/policy-server/cc_client"
 	"code.cloudfoundry.org/policy-server/config"
+	"code.cloudfoundry.org/policy-server/server_metrics"
 	"code.cloudfoundry.org/policy-server/store"
 	"code.cloudfoundry.org/policy-server/uaa_client"
 	"github.com/cloudfoundry/dropsonde"
@@ -57,7 +58,7 @@ func main() {
 	if conf.LogLevel != "" {
 		loggerConfig.LogLevel = conf.LogLevel
 	}
-	logger, _ := lagerflags.NewFromConfig(fmt.Sprintf("%s.%s", logPrefix, jobPrefix), loggerConfig)
+	logger, reconfigurableSink := lagerflags.NewFromConfig(fmt.Sprintf("%s.%s", logPrefix, jobPrefix), loggerConfig)
 
 	connectionPool, err := db.NewConnectionPool(
 		conf.Database,
@@ -76,8 +77,17 @@ func main() {
 		Conn: connectionPool,
 	}
 
-	metricsSender := &metrics.MetricsSender{
-		Logger: logger.Session("time-metric-emitter"),
+	metricsSender := server_metrics.MultiSender{
+		&metrics.MetricsSender{
+			Logger: logger.Session("time-metric-emitter"),
+		},
+	}
+
+	var prometheusRegistry *server_metrics.PrometheusRegistry
+	if conf.EnablePrometheusMetrics {
+		prometheusRegistry = server_metrics.NewPrometheusRegistry("policy_server_asg_syncer")
+		prometheusRegistry.AddSources(common.InitPrometheusSources(nil, connectionPool.DB)...)
+		metricsSender = append(metricsSender, prometheusRegistry)
 	}
 
 	err = dropsonde.Initialize(conf.MetronAddress, jobPrefix)
@@ -151,6 +161,15 @@ func main() {
 		{Name: "asg-syncer", Runner: asgSyncer},
 	}
 
+	if conf.DebugServerPort != 0 {
+		var metricsHandler http.Handler
+		if prometheusRegistry != nil {
+			metricsHandler = prometheusRegistry
+		}
+		debugServer := common.InitDebugServer(conf.DebugServerHost, conf.DebugServerPort, reconfigurableSink, metricsHandler)
+		members = append(members, grouper.Member{Name: "debug-server", Runner: debugServer})
+	}
+
 	logger.Info("starting-asg-syncer", lager.Data{"interval": conf.ASGSyncInterval})
 
 	group := grouper.NewOrdered(os.Interrupt, members)
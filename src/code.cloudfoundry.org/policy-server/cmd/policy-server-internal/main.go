@@ -16,13 +16,14 @@ import (
 	"code.cloudfoundry.org/cf-networking-helpers/middleware"
 	middlewareAdapter "code.cloudfoundry.org/cf-networking-helpers/middleware/adapter"
 	"code.cloudfoundry.org/cf-networking-helpers/mutualtls"
-	"code.cloudfoundry.org/debugserver"
+	"code.cloudfoundry.org/cf-networking-helpers/poller"
 	"code.cloudfoundry.org/lager/v3"
 	"code.cloudfoundry.org/lager/v3/lagerflags"
 	"code.cloudfoundry.org/lib/common"
 	"code.cloudfoundry.org/policy-server/api"
 	"code.cloudfoundry.org/policy-server/config"
 	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/server_metrics"
 	"code.cloudfoundry.org/policy-server/store"
 	"github.com/cloudfoundry/dropsonde"
 	"github.com/tedsuo/ifrit"
@@ -33,6 +34,11 @@ import (
 
 const (
 	jobPrefix = "policy-server-internal"
+
+	defaultWatchPollInterval = 1 * time.Second
+	defaultWatchHistorySize  = 1000
+	watchBacklog             = 100
+	watchHeartbeatInterval   = 30 * time.Second
 )
 
 var (
@@ -71,13 +77,16 @@ func main() {
 		log.Fatal(err.Error())
 	}
 
-	dataStore := store.New(
+	var dataStore store.Store = store.New(
 		connectionPool,
 		&store.GroupTable{},
 		&store.DestinationTable{},
 		&store.PolicyTable{},
 		conf.TagLength,
 	)
+	if conf.CachePolicies {
+		dataStore = store.NewPolicyCache(dataStore)
+	}
 
 	securityGroupsStore := &store.SGStore{
 		Conn: connectionPool,
@@ -85,8 +94,16 @@ func main() {
 
 	tagDataStore := store.NewTagStore(connectionPool, &store.GroupTable{}, conf.TagLength)
 
-	metricsSender := &metrics.MetricsSender{
-		Logger: logger.Session("time-metric-emitter"),
+	metricsSender := server_metrics.MultiSender{
+		&metrics.MetricsSender{
+			Logger: logger.Session("time-metric-emitter"),
+		},
+	}
+
+	var prometheusRegistry *server_metrics.PrometheusRegistry
+	if conf.EnablePrometheusMetrics {
+		prometheusRegistry = server_metrics.NewPrometheusRegistry("policy_server_internal")
+		metricsSender = append(metricsSender, prometheusRegistry)
 	}
 
 	wrappedStore := &store.MetricsWrapper{
@@ -110,6 +127,14 @@ func main() {
 
 	internalPoliciesLastUpdatedHandlerV1 := handlers.NewPoliciesLastUpdatedInternal(logger, wrappedStore, errorResponse)
 
+	watchHistorySize := conf.WatchHistorySize
+	if watchHistorySize == 0 {
+		watchHistorySize = defaultWatchHistorySize
+	}
+	policiesWatchHub := handlers.NewPoliciesWatchHub(logger.Session("policies-watch-hub"), wrappedStore, watchHistorySize, watchBacklog)
+	internalPoliciesWatchHandlerV1 := handlers.NewPoliciesWatchInternal(logger, policiesWatchHub,
+		marshal.MarshalFunc(json.Marshal), errorResponse, watchHeartbeatInterval)
+
 	createTagsHandlerV1 := &handlers.TagsCreate{
 		Store:         wrappedStore,
 		ErrorResponse: errorResponse,
@@ -157,6 +182,14 @@ func main() {
 		"internal_security_groups":       metricsWrap("InternalSecurityGroups", logWrap(securityGroupsHandlerV1)),
 	}
 
+	if conf.EnableWatch {
+		internalRoutes = append(internalRoutes,
+			rata.Route{Name: "internal_policies_watch", Method: "GET", Path: "/networking/:version/internal/policies/watch"})
+		// the watch streams until the client leaves, so its duration is not
+		// a request time and is kept out of the request metrics
+		internalHandlers["internal_policies_watch"] = logWrap(internalPoliciesWatchHandlerV1)
+	}
+
 	for key, handler := range internalHandlers {
 		wrappedHandler := hstsHeaderWrapper.Wrap(handler)
 		internalHandlers[key] = wrappedHandler
@@ -168,7 +201,13 @@ func main() {
 	}
 
 	internalServer := common.InitServer(logger, tlsConfig, conf.ListenHost, conf.InternalListenPort, internalHandlers, internalRoutes)
-	debugServer := debugserver.Runner(fmt.Sprintf("%s:%d", conf.DebugServerHost, conf.DebugServerPort), reconfigurableSink)
+
+	var metricsHandler http.Handler
+	if prometheusRegistry != nil {
+		prometheusRegistry.AddSources(common.InitPrometheusSources(wrappedStore, connectionPool.DB)...)
+		metricsHandler = prometheusRegistry
+	}
+	debugServer := common.InitDebugServer(conf.DebugServerHost, conf.DebugServerPort, reconfigurableSink, metricsHandler)
 
 	uptimeHandler := &handlers.UptimeHandler{
 		StartTime: time.Now(),
@@ -188,12 +227,28 @@ func main() {
 	healthCheckServer := common.InitServer(logger, nil, "127.0.0.1",
 		conf.HealthCheckPort, healthHandlers, healthRoutes)
 
+	watchPollInterval := time.Duration(conf.WatchPollIntervalSeconds) * time.Second
+	if watchPollInterval == 0 {
+		watchPollInterval = defaultWatchPollInterval
+	}
+	policiesWatchPoller := &poller.Poller{
+		Logger:                 logger.Session("policies-watch-poller"),
+		PollInterval:           watchPollInterval,
+		RunBeforeFirstInterval: true,
+		SingleCycleFunc:        policiesWatchHub.Poll,
+	}
+
 	members := grouper.Members{
 		{Name: "metrics-emitter", Runner: metricsEmitter},
-		{Name: "internal-http-server", Runner: internalServer},
-		{Name: "debug-server", Runner: debugServer},
-		{Name: "health-check-server", Runner: healthCheckServer},
 	}
+	if conf.EnableWatch {
+		members = append(members, grouper.Member{Name: "policies-watch-poller", Runner: policiesWatchPoller})
+	}
+	members = append(members,
+		grouper.Member{Name: "internal-http-server", Runner: internalServer},
+		grouper.Member{Name: "debug-server", Runner: debugServer},
+		grouper.Member{Name: "health-check-server", Runner: healthCheckServer},
+	)
 
 	logger.Info("starting internal server", lager.Data{"listen-address": conf.ListenHost, "port": conf.InternalListenPort})
 
@@ -21,7 +21,6 @@ import (
 	"code.cloudfoundry.org/cf-networking-helpers/middleware"
 	middlewareAdapter "code.cloudfoundry.org/cf-networking-helpers/middleware/adapter"
 	"code.cloudfoundry.org/cf-networking-helpers/poller"
-	"code.cloudfoundry.org/debugserver"
 	"code.cloudfoundry.org/lager/v3"
 	"code.cloudfoundry.org/lager/v3/lagerflags"
 	"code.cloudfoundry.org/policy-server/adapter"
@@ -32,13 +31,17 @@ import (
 	"code.cloudfoundry.org/policy-server/config"
 	"code.cloudfoundry.org/policy-server/handlers"
 	psmiddleware "code.cloudfoundry.org/policy-server/middleware"
+	"code.cloudfoundry.org/policy-server/server_metrics"
 	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/tracing"
 	"code.cloudfoundry.org/policy-server/uaa_client"
 	"github.com/cloudfoundry/dropsonde"
 	"github.com/tedsuo/ifrit"
 	"github.com/tedsuo/ifrit/grouper"
 	"github.com/tedsuo/ifrit/sigmon"
 	"github.com/tedsuo/rata"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 const (
@@ -132,13 +135,24 @@ func main() {
 
 	tagDataStore := store.NewTagStore(connectionPool, &store.GroupTable{}, conf.TagLength)
 
-	metricsSender := &metrics.MetricsSender{
-		Logger: logger.Session("time-metric-emitter"),
+	metricsSender := server_metrics.MultiSender{
+		&metrics.MetricsSender{
+			Logger: logger.Session("time-metric-emitter"),
+		},
+	}
+
+	var prometheusRegistry *server_metrics.PrometheusRegistry
+	if conf.EnablePrometheusMetrics {
+		prometheusRegistry = server_metrics.NewPrometheusRegistry("policy_server")
+		metricsSender = append(metricsSender, prometheusRegistry)
 	}
 
 	wrappedStore := &store.MetricsWrapper{
 		Store:         c2cPolicyStore,
 		TagStore:      tagDataStore,
+		EventsStore:   &store.EventsStore{Conn: connectionPool},
+		QuotasStore:   &store.QuotasStore{Conn: connectionPool},
+		PendingStore:  &store.PendingStore{Conn: connectionPool},
 		MetricsSender: metricsSender,
 	}
 
@@ -151,33 +165,76 @@ func main() {
 		Logger:             logger,
 	}
 
-	policyGuard := handlers.NewPolicyGuard(uaaClient, ccClient)
-	quotaGuard := handlers.NewQuotaGuard(wrappedStore, conf.MaxPolicies)
-	policyFilter := handlers.NewPolicyFilter(uaaClient, ccClient, 100)
+	roles, err := handlers.NewRoles(conf.ScopePermissions, conf.RolePermissions)
+	if err != nil {
+		log.Fatalf("%s.%s: invalid roles: %s", logPrefix, jobPrefix, err)
+	}
+
+	policyGuard := handlers.NewPolicyGuard(uaaClient, ccClient, roles)
+	quotaGuard := handlers.NewQuotaGuard(wrappedStore, wrappedStore, uaaClient, ccClient, conf.MaxPolicies)
+	policyFilter := handlers.NewPolicyFilter(uaaClient, ccClient, 100, roles)
+	policyConsent := handlers.NewPolicyConsent(uaaClient, ccClient, roles, conf.EnableCrossSpaceConsent)
 
 	policyMapperV0 := api_v0.NewPolicyMapper(marshal.UnmarshalFunc(json.Unmarshal), marshal.MarshalFunc(json.Marshal), &api_v0.Validator{})
 	policyMapperV1 := api.NewPolicyMapper(marshal.UnmarshalFunc(json.Unmarshal), marshal.MarshalFunc(json.Marshal), &api.PolicyValidator{})
 
 	createPolicyHandlerV1 := handlers.NewPoliciesCreate(wrappedStore, policyMapperV1,
-		policyGuard, quotaGuard, errorResponse)
+		policyGuard, quotaGuard, policyConsent, errorResponse)
 	createPolicyHandlerV0 := handlers.NewPoliciesCreate(wrappedStore, policyMapperV0,
-		policyGuard, quotaGuard, errorResponse)
+		policyGuard, quotaGuard, policyConsent, errorResponse)
 
 	deletePolicyHandlerV1 := handlers.NewPoliciesDelete(wrappedStore, policyMapperV1,
 		policyGuard, errorResponse)
 	deletePolicyHandlerV0 := handlers.NewPoliciesDelete(wrappedStore, policyMapperV0,
 		policyGuard, errorResponse)
 
+	replacePolicyHandlerV1 := handlers.NewPoliciesReplace(wrappedStore, policyMapperV1,
+		policyGuard, quotaGuard, policyConsent, adapter.RataAdapter{}, errorResponse)
+
 	policiesIndexHandlerV1 := handlers.NewPoliciesIndex(wrappedStore, policyMapperV1, policyFilter, policyGuard, errorResponse)
 	policiesIndexHandlerV0 := handlers.NewPoliciesIndex(wrappedStore, policyMapperV0, policyFilter, policyGuard, errorResponse)
 
 	policyCleaner := cleaner.NewPolicyCleaner(logger.Session("policy-cleaner"), wrappedStore, uaaClient,
-		ccClient, 100)
+		ccClient, 100, metricsSender)
+	groupMembersUpdater := cleaner.NewGroupMembersUpdater(logger.Session("group-members-updater"), wrappedStore,
+		uaaClient, ccClient, 100)
+	policyEventsPruner := cleaner.NewPolicyEventsPruner(logger.Session("policy-events-pruner"), wrappedStore,
+		time.Duration(conf.PolicyEventsRetentionDays)*24*time.Hour)
+
+	pendingPoliciesIndexHandler := handlers.NewPendingPoliciesIndex(wrappedStore, policyConsent,
+		marshal.MarshalFunc(json.Marshal), errorResponse)
+	pendingPoliciesApproveHandler := handlers.NewPendingPoliciesApprove(wrappedStore, wrappedStore, policyConsent,
+		quotaGuard, adapter.RataAdapter{}, errorResponse)
+	pendingPoliciesDeleteHandler := handlers.NewPendingPoliciesDelete(wrappedStore, policyConsent,
+		adapter.RataAdapter{}, errorResponse)
 
 	policiesCleanupHandler := handlers.NewPoliciesCleanup(policyMapperV1, policyCleaner, errorResponse)
 
+	policyOverlapsIndexHandler := handlers.NewPoliciesOverlapsIndex(wrappedStore, marshal.MarshalFunc(json.Marshal), errorResponse)
+	policyOverlapsMergeHandler := handlers.NewPoliciesOverlapsMerge(wrappedStore, marshal.MarshalFunc(json.Marshal), errorResponse)
+
+	policyManifestResolver := handlers.NewPolicyManifestResolver(uaaClient, ccClient)
+	policiesExportHandler := handlers.NewPoliciesExport(wrappedStore, policyManifestResolver, marshal.MarshalFunc(json.Marshal), errorResponse)
+	policiesImportHandler := handlers.NewPoliciesImport(wrappedStore, policyMapperV1, policyManifestResolver,
+		quotaGuard, marshal.MarshalFunc(json.Marshal), errorResponse)
+
+	spaceSources := handlers.NewSpaceSources(uaaClient, ccClient)
+	spacePoliciesIndexHandler := handlers.NewSpacePoliciesIndex(wrappedStore, spaceSources, adapter.RataAdapter{},
+		marshal.MarshalFunc(json.Marshal), errorResponse)
+	spacePoliciesReconcileHandler := handlers.NewSpacePoliciesReconcile(wrappedStore, policyMapperV1, spaceSources,
+		quotaGuard, adapter.RataAdapter{}, marshal.MarshalFunc(json.Marshal), errorResponse)
+
 	tagsIndexHandler := handlers.NewTagsIndex(wrappedStore, marshal.MarshalFunc(json.Marshal), errorResponse)
 
+	policyEventsIndexHandler := handlers.NewPolicyEventsIndex(wrappedStore, marshal.MarshalFunc(json.Marshal), errorResponse)
+
+	quotasIndexHandler := handlers.NewQuotasIndex(wrappedStore, marshal.MarshalFunc(json.Marshal), errorResponse)
+	quotasUpdateHandler := handlers.NewQuotasUpdate(wrappedStore, marshal.MarshalFunc(json.Marshal), adapter.RataAdapter{}, errorResponse)
+	quotasDeleteHandler := handlers.NewQuotasDelete(wrappedStore, adapter.RataAdapter{}, errorResponse)
+
+	quarantineCreateHandler := handlers.NewQuarantineCreate(wrappedStore, adapter.RataAdapter{}, errorResponse)
+	quarantineDeleteHandler := handlers.NewQuarantineDelete(wrappedStore, adapter.RataAdapter{}, errorResponse)
+
 	healthHandler := handlers.NewHealth(wrappedStore, errorResponse)
 
 	checkVersionWrapper := &handlers.CheckVersionWrapper{
@@ -212,6 +269,26 @@ func main() {
 		})
 	}
 
+	v1VersionWrap := func(v1Handler http.Handler) http.Handler {
+		return checkVersionWrapper.CheckVersion(map[string]http.Handler{
+			"v1": v1Handler,
+		})
+	}
+
+	var tracerProvider *sdktrace.TracerProvider
+	tracingMiddleware := &tracing.Middleware{}
+	if conf.TracingEndpoint != "" {
+		tracerProvider, err = tracing.NewProvider(conf.TracingEndpoint, "policy-server", conf.TracingSampleRatio)
+		if err != nil {
+			log.Fatalf("%s.%s: creating tracer provider: %s", logPrefix, jobPrefix, err)
+		}
+		tracingLogger := logger.Session("tracing")
+		otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+			tracingLogger.Error("export-spans", err)
+		}))
+		tracingMiddleware = tracing.NewMiddleware(tracerProvider)
+	}
+
 	authAdminWrap := func(handler http.Handler) http.Handler {
 		networkAdminAuthenticator := handlers.Authenticator{
 			Client:        uaaClient,
@@ -219,18 +296,24 @@ func main() {
 			ErrorResponse: errorResponse,
 			ScopeChecking: true,
 		}
-		return networkAdminAuthenticator.Wrap(handler)
+		return networkAdminAuthenticator.Wrap(tracingMiddleware.Bind(handler))
 	}
 
-	authWriteWrap := func(handler http.Handler) http.Handler {
-		networkWriteAuthenticator := handlers.Authenticator{
-			Client:        uaaClient,
-			Scopes:        []string{"network.admin", "network.write"},
-			ErrorResponse: errorResponse,
-			ScopeChecking: !conf.EnableSpaceDeveloperSelfService,
+	authPermissionWrap := func(permission handlers.Permission, scopeChecking bool) func(http.Handler) http.Handler {
+		return func(handler http.Handler) http.Handler {
+			permissionAuthenticator := handlers.Authenticator{
+				Client:        uaaClient,
+				Scopes:        roles.ScopesWith(permission),
+				ErrorResponse: errorResponse,
+				ScopeChecking: scopeChecking,
+			}
+			return permissionAuthenticator.Wrap(tracingMiddleware.Bind(handler))
 		}
-		return networkWriteAuthenticator.Wrap(handler)
 	}
+	authReadWrap := authPermissionWrap(handlers.PermissionRead, !conf.EnableSpaceDeveloperSelfService)
+	authWriteWrap := authPermissionWrap(handlers.PermissionWrite, !conf.EnableSpaceDeveloperSelfService)
+	authCleanupWrap := authPermissionWrap(handlers.PermissionCleanup, true)
+	authTagsWrap := authPermissionWrap(handlers.PermissionTags, true)
 
 	externalRoutes := rata.Routes{
 		{Name: "uptime", Method: "GET", Path: "/"},
@@ -240,8 +323,24 @@ func main() {
 		{Name: "create_policies", Method: "POST", Path: "/networking/:version/external/policies"},
 		{Name: "delete_policies", Method: "POST", Path: "/networking/:version/external/policies/delete"},
 		{Name: "policies_index", Method: "GET", Path: "/networking/:version/external/policies"},
+		{Name: "replace_policies", Method: "PUT", Path: "/networking/:version/external/apps/:guid/policies"},
+		{Name: "space_policies_index", Method: "GET", Path: "/networking/:version/external/spaces/:guid/policies"},
+		{Name: "reconcile_space_policies", Method: "PUT", Path: "/networking/:version/external/spaces/:guid/policies"},
+		{Name: "quarantine_app", Method: "POST", Path: "/networking/:version/external/apps/:guid/quarantine"},
+		{Name: "release_app", Method: "DELETE", Path: "/networking/:version/external/apps/:guid/quarantine"},
 		{Name: "cleanup", Method: "POST", Path: "/networking/:version/external/policies/cleanup"},
+		{Name: "policy_events_index", Method: "GET", Path: "/networking/:version/external/policies/events"},
+		{Name: "pending_policies_index", Method: "GET", Path: "/networking/:version/external/policies/pending"},
+		{Name: "approve_pending_policy", Method: "POST", Path: "/networking/:version/external/policies/pending/:id/approve"},
+		{Name: "delete_pending_policy", Method: "DELETE", Path: "/networking/:version/external/policies/pending/:id"},
+		{Name: "policy_overlaps_index", Method: "GET", Path: "/networking/:version/external/policies/overlaps"},
+		{Name: "merge_policy_overlaps", Method: "POST", Path: "/networking/:version/external/policies/overlaps/merge"},
+		{Name: "export_policies", Method: "GET", Path: "/networking/:version/external/policies/export"},
+		{Name: "import_policies", Method: "POST", Path: "/networking/:version/external/policies/import"},
 		{Name: "tags_index", Method: "GET", Path: "/networking/:version/external/tags"},
+		{Name: "quotas_index", Method: "GET", Path: "/networking/:version/external/quotas"},
+		{Name: "update_quota", Method: "PUT", Path: "/networking/:version/external/quotas/:type/:guid"},
+		{Name: "delete_quota", Method: "DELETE", Path: "/networking/:version/external/quotas/:type/:guid"},
 	}
 
 	corsMiddleware := psmiddleware.CORS{}
@@ -259,7 +358,7 @@ func main() {
 		"options": http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		}),
 		"uptime": metricsWrap("Uptime", logWrap(uptimeHandler)),
-		"health": metricsWrap("Health", logWrap(healthHandler)),
+		"health": metricsWrap("Health", logWrap(tracingMiddleware.Bind(healthHandler))),
 
 		"create_policies": metricsWrap("CreatePolicies",
 			logWrap(v0Andv1VersionWrap(authWriteWrap(createPolicyHandlerV1), authWriteWrap(createPolicyHandlerV0)))),
@@ -267,14 +366,62 @@ func main() {
 		"delete_policies": metricsWrap("DeletePolicies",
 			logWrap(v0Andv1VersionWrap(authWriteWrap(deletePolicyHandlerV1), authWriteWrap(deletePolicyHandlerV0)))),
 
+		"replace_policies": metricsWrap("ReplacePolicies",
+			logWrap(v1VersionWrap(authWriteWrap(replacePolicyHandlerV1)))),
+
+		"space_policies_index": metricsWrap("SpacePoliciesIndex",
+			logWrap(v1VersionWrap(authAdminWrap(spacePoliciesIndexHandler)))),
+
+		"reconcile_space_policies": metricsWrap("ReconcileSpacePolicies",
+			logWrap(v1VersionWrap(authAdminWrap(spacePoliciesReconcileHandler)))),
+
 		"policies_index": metricsWrap("PoliciesIndex",
-			logWrap(v0Andv1VersionWrap(authWriteWrap(policiesIndexHandlerV1), authWriteWrap(policiesIndexHandlerV0)))),
+			logWrap(v0Andv1VersionWrap(authReadWrap(policiesIndexHandlerV1), authReadWrap(policiesIndexHandlerV0)))),
 
 		"cleanup": metricsWrap("Cleanup",
-			logWrap(v0Andv1VersionWrap(authAdminWrap(policiesCleanupHandler), authAdminWrap(policiesCleanupHandler)))),
+			logWrap(v0Andv1VersionWrap(authCleanupWrap(policiesCleanupHandler), authCleanupWrap(policiesCleanupHandler)))),
+
+		"policy_events_index": metricsWrap("PolicyEventsIndex",
+			logWrap(v1VersionWrap(authAdminWrap(policyEventsIndexHandler)))),
+
+		"pending_policies_index": metricsWrap("PendingPoliciesIndex",
+			logWrap(v1VersionWrap(authWriteWrap(pendingPoliciesIndexHandler)))),
+
+		"approve_pending_policy": metricsWrap("ApprovePendingPolicy",
+			logWrap(v1VersionWrap(authWriteWrap(pendingPoliciesApproveHandler)))),
+
+		"delete_pending_policy": metricsWrap("DeletePendingPolicy",
+			logWrap(v1VersionWrap(authWriteWrap(pendingPoliciesDeleteHandler)))),
+
+		"policy_overlaps_index": metricsWrap("PolicyOverlapsIndex",
+			logWrap(v1VersionWrap(authAdminWrap(policyOverlapsIndexHandler)))),
+
+		"merge_policy_overlaps": metricsWrap("MergePolicyOverlaps",
+			logWrap(v1VersionWrap(authAdminWrap(policyOverlapsMergeHandler)))),
+
+		"export_policies": metricsWrap("ExportPolicies",
+			logWrap(v1VersionWrap(authAdminWrap(policiesExportHandler)))),
+
+		"import_policies": metricsWrap("ImportPolicies",
+			logWrap(v1VersionWrap(authAdminWrap(policiesImportHandler)))),
 
 		"tags_index": metricsWrap("TagsIndex",
-			logWrap(v0Andv1VersionWrap(authAdminWrap(tagsIndexHandler), authAdminWrap(tagsIndexHandler)))),
+			logWrap(v0Andv1VersionWrap(authTagsWrap(tagsIndexHandler), authTagsWrap(tagsIndexHandler)))),
+
+		"quotas_index": metricsWrap("QuotasIndex",
+			logWrap(v1VersionWrap(authAdminWrap(quotasIndexHandler)))),
+
+		"update_quota": metricsWrap("UpdateQuota",
+			logWrap(v1VersionWrap(authAdminWrap(quotasUpdateHandler)))),
+
+		"delete_quota": metricsWrap("DeleteQuota",
+			logWrap(v1VersionWrap(authAdminWrap(quotasDeleteHandler)))),
+
+		"quarantine_app": metricsWrap("QuarantineApp",
+			logWrap(v1VersionWrap(authAdminWrap(quarantineCreateHandler)))),
+
+		"release_app": metricsWrap("ReleaseApp",
+			logWrap(v1VersionWrap(authAdminWrap(quarantineDeleteHandler)))),
 
 		"whoami": metricsWrap("WhoAmI",
 			logWrap(v0Andv1VersionWrap(authAdminWrap(whoamiHandler), authAdminWrap(whoamiHandler)))),
@@ -287,7 +434,7 @@ func main() {
 		if conf.EnableTLS {
 			wrappedHandler = hstsHeaderWrapper.Wrap(wrappedHandler)
 		}
-		externalHandlers[key] = wrappedHandler
+		externalHandlers[key] = tracingMiddleware.Wrap(key, wrappedHandler)
 	}
 
 	err = dropsonde.Initialize(conf.MetronAddress, dropsondeOrigin)
@@ -308,13 +455,42 @@ func main() {
 
 	externalServer := common.InitServer(logger, serverTLSConfig, conf.ListenHost, conf.ListenPort, externalHandlers, externalRoutesWithOptions)
 	policyPoller := initPoller(logger, conf, policyCleaner)
-	debugServer := debugserver.Runner(fmt.Sprintf("%s:%d", conf.DebugServerHost, conf.DebugServerPort), reconfigurableSink)
+	groupMembersPoller := &poller.Poller{
+		Logger:          logger.Session("group-members-poller"),
+		PollInterval:    time.Duration(conf.GroupMembersUpdateInterval) * time.Second,
+		SingleCycleFunc: groupMembersUpdater.Update,
+	}
 
-	members := grouper.Members{
+	var metricsHandler http.Handler
+	if prometheusRegistry != nil {
+		prometheusRegistry.AddSources(common.InitPrometheusSources(wrappedStore, connectionPool.DB)...)
+		metricsHandler = prometheusRegistry
+	}
+	debugServer := common.InitDebugServer(conf.DebugServerHost, conf.DebugServerPort, reconfigurableSink, metricsHandler)
+
+	members := grouper.Members{}
+	if tracerProvider != nil {
+		// the ordered group stops its members in reverse, so the tracer
+		// provider stops last and sends the spans of the requests that are
+		// still served
+		members = append(members, grouper.Member{Name: "tracing", Runner: &tracing.Runner{
+			Logger:   logger.Session("tracing"),
+			Provider: tracerProvider,
+		}})
+	}
+	members = append(members, grouper.Members{
 		{Name: "metrics_emitter", Runner: metricsEmitter},
 		{Name: "http_server", Runner: externalServer},
 		{Name: "policy-cleaner-poller", Runner: policyPoller},
+		{Name: "group-members-poller", Runner: groupMembersPoller},
 		{Name: "debug-server", Runner: debugServer},
+	}...)
+	if conf.PolicyEventsRetentionDays > 0 {
+		members = append(members, grouper.Member{Name: "policy-events-pruner-poller", Runner: &poller.Poller{
+			Logger:          logger.Session("policy-events-pruner-poller"),
+			PollInterval:    time.Duration(conf.CleanupInterval) * time.Second,
+			SingleCycleFunc: policyEventsPruner.Prune,
+		}})
 	}
 
 	logger.Info("starting external server", lager.Data{"listen-address": conf.ListenHost, "port": conf.ListenPort})
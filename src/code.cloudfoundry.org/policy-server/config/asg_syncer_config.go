@@ -31,6 +31,11 @@ type ASGSyncerConfig struct {
 	LogPrefix            string    `json:"log_prefix" validate:"nonzero"`
 	MetronAddress        string    `json:"metron_address" validate:"nonzero"`
 	SkipSSLValidation    bool      `json:"skip_ssl_validation"`
+	DebugServerHost      string    `json:"debug_server_host"`
+	DebugServerPort      int       `json:"debug_server_port" validate:"min=0"`
+	// EnablePrometheusMetrics serves metrics on the debug server, which only
+	// runs when DebugServerPort is set
+	EnablePrometheusMetrics bool `json:"enable_prometheus_metrics"`
 	locket.ClientLocketConfig
 }
 
@@ -53,7 +53,10 @@ var _ = Describe("ASGSyncerConfig", func() {
 					"locket_client_cert_file": "some/client/cert/locket.cert",
 					"locket_client_key_file":  "some/client/cert/locket.key",
 				},
-				"retry_deadline_seconds": 300,
+				"retry_deadline_seconds":    300,
+				"debug_server_host":         "127.0.0.1",
+				"debug_server_port":         31823,
+				"enable_prometheus_metrics": true,
 			}
 			file, err = os.CreateTemp(os.TempDir(), "config-")
 			Expect(err).NotTo(HaveOccurred())
@@ -89,6 +92,9 @@ var _ = Describe("ASGSyncerConfig", func() {
 				Expect(c.MetronAddress).To(Equal("127.0.0.1:3457"))
 				Expect(c.SkipSSLValidation).To(Equal(true))
 				Expect(c.RetryDeadline).To(Equal(300))
+				Expect(c.DebugServerHost).To(Equal("127.0.0.1"))
+				Expect(c.DebugServerPort).To(Equal(31823))
+				Expect(c.EnablePrometheusMetrics).To(BeTrue())
 			})
 		})
 
@@ -11,35 +11,43 @@ import (
 )
 
 type Config struct {
-	ListenHost                      string    `json:"listen_host" validate:"nonzero"`
-	ListenPort                      int       `json:"listen_port" validate:"nonzero"`
-	LogPrefix                       string    `json:"log_prefix" validate:"nonzero"`
-	EnableTLS                       bool      `json:"enable_tls"`
-	ServerCertFile                  string    `json:"server_cert_file"`
-	ServerKeyFile                   string    `json:"server_key_file"`
-	DebugServerHost                 string    `json:"debug_server_host" validate:"nonzero"`
-	DebugServerPort                 int       `json:"debug_server_port" validate:"nonzero"`
-	UAAClient                       string    `json:"uaa_client" validate:"nonzero"`
-	UAAClientSecret                 string    `json:"uaa_client_secret" validate:"nonzero"`
-	UAACA                           string    `json:"uaa_ca"`
-	UAAURL                          string    `json:"uaa_url" validate:"nonzero"`
-	UAAPort                         int       `json:"uaa_port" validate:"nonzero"`
-	CCURL                           string    `json:"cc_url" validate:"nonzero"`
-	CCCA                            string    `json:"cc_ca_cert"`
-	SkipSSLValidation               bool      `json:"skip_ssl_validation"`
-	Database                        db.Config `json:"database" validate:"nonzero"`
-	DatabaseMigrationTimeout        int       `json:"database_migration_timeout" validate:"min=1"`
-	TagLength                       int       `json:"tag_length" validate:"nonzero"`
-	MetronAddress                   string    `json:"metron_address" validate:"nonzero"`
-	LogLevel                        string    `json:"log_level"`
-	CleanupInterval                 int       `json:"cleanup_interval" validate:"min=1"`
-	CCAppRequestChunkSize           int       `json:"cc_app_request_chunk_size"`
-	MaxPolicies                     int       `json:"max_policies" validate:"min=1"`
-	EnableSpaceDeveloperSelfService bool      `json:"enable_space_developer_self_service"`
-	AllowedCORSDomains              []string  `json:"allowed_cors_domains"`
-	MaxIdleConnections              int       `json:"max_idle_connections" validate:"min=0"`
-	MaxOpenConnections              int       `json:"max_open_connections" validate:"min=0"`
-	MaxConnectionsLifetimeSeconds   int       `json:"connections_max_lifetime_seconds" validate:"min=0"`
+	ListenHost                      string              `json:"listen_host" validate:"nonzero"`
+	ListenPort                      int                 `json:"listen_port" validate:"nonzero"`
+	LogPrefix                       string              `json:"log_prefix" validate:"nonzero"`
+	EnableTLS                       bool                `json:"enable_tls"`
+	ServerCertFile                  string              `json:"server_cert_file"`
+	ServerKeyFile                   string              `json:"server_key_file"`
+	DebugServerHost                 string              `json:"debug_server_host" validate:"nonzero"`
+	DebugServerPort                 int                 `json:"debug_server_port" validate:"nonzero"`
+	UAAClient                       string              `json:"uaa_client" validate:"nonzero"`
+	UAAClientSecret                 string              `json:"uaa_client_secret" validate:"nonzero"`
+	UAACA                           string              `json:"uaa_ca"`
+	UAAURL                          string              `json:"uaa_url" validate:"nonzero"`
+	UAAPort                         int                 `json:"uaa_port" validate:"nonzero"`
+	CCURL                           string              `json:"cc_url" validate:"nonzero"`
+	CCCA                            string              `json:"cc_ca_cert"`
+	SkipSSLValidation               bool                `json:"skip_ssl_validation"`
+	Database                        db.Config           `json:"database" validate:"nonzero"`
+	DatabaseMigrationTimeout        int                 `json:"database_migration_timeout" validate:"min=1"`
+	TagLength                       int                 `json:"tag_length" validate:"nonzero"`
+	MetronAddress                   string              `json:"metron_address" validate:"nonzero"`
+	LogLevel                        string              `json:"log_level"`
+	CleanupInterval                 int                 `json:"cleanup_interval" validate:"min=1"`
+	GroupMembersUpdateInterval      int                 `json:"group_members_update_interval" validate:"min=1"`
+	PolicyEventsRetentionDays       int                 `json:"policy_events_retention_days" validate:"min=0"`
+	CCAppRequestChunkSize           int                 `json:"cc_app_request_chunk_size"`
+	MaxPolicies                     int                 `json:"max_policies" validate:"min=1"`
+	EnableSpaceDeveloperSelfService bool                `json:"enable_space_developer_self_service"`
+	AllowedCORSDomains              []string            `json:"allowed_cors_domains"`
+	MaxIdleConnections              int                 `json:"max_idle_connections" validate:"min=0"`
+	MaxOpenConnections              int                 `json:"max_open_connections" validate:"min=0"`
+	MaxConnectionsLifetimeSeconds   int                 `json:"connections_max_lifetime_seconds" validate:"min=0"`
+	ScopePermissions                map[string][]string `json:"scope_permissions"`
+	RolePermissions                 map[string][]string `json:"role_permissions"`
+	EnableCrossSpaceConsent         bool                `json:"enable_cross_space_consent"`
+	EnablePrometheusMetrics         bool                `json:"enable_prometheus_metrics"`
+	TracingEndpoint                 string              `json:"tracing_endpoint"`
+	TracingSampleRatio              float64             `json:"tracing_sample_ratio" validate:"min=0,max=1"`
 }
 
 func (c *Config) Validate() error {
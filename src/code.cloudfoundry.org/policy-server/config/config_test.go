@@ -60,9 +60,17 @@ var _ = Describe("Config", func() {
 					"metron_address": "http://1.2.3.4:9999",
 					"log_level": "debug",
 					"cleanup_interval": 2,
+					"group_members_update_interval": 7,
+					"policy_events_retention_days": 90,
 					"max_policies": 3,
 					"enable_space_developer_self_service": true,
-					"allowed_cors_domains": ["https://foo.bar", "https://bar.foo"]
+					"allowed_cors_domains": ["https://foo.bar", "https://bar.foo"],
+					"scope_permissions": {"network.read": ["read"]},
+					"role_permissions": {"space_manager": []},
+					"enable_cross_space_consent": true,
+					"enable_prometheus_metrics": true,
+					"tracing_endpoint": "http://otel-collector:4318",
+					"tracing_sample_ratio": 0.25
 				}`)
 				c, err := config.New(file.Name())
 				Expect(err).NotTo(HaveOccurred())
@@ -96,8 +104,16 @@ var _ = Describe("Config", func() {
 				Expect(c.MetronAddress).To(Equal("http://1.2.3.4:9999"))
 				Expect(c.LogLevel).To(Equal("debug"))
 				Expect(c.CleanupInterval).To(Equal(2))
+				Expect(c.GroupMembersUpdateInterval).To(Equal(7))
+				Expect(c.PolicyEventsRetentionDays).To(Equal(90))
 				Expect(c.MaxPolicies).To(Equal(3))
 				Expect(c.EnableSpaceDeveloperSelfService).To(BeTrue())
+				Expect(c.ScopePermissions).To(Equal(map[string][]string{"network.read": {"read"}}))
+				Expect(c.RolePermissions).To(Equal(map[string][]string{"space_manager": {}}))
+				Expect(c.EnableCrossSpaceConsent).To(BeTrue())
+				Expect(c.EnablePrometheusMetrics).To(BeTrue())
+				Expect(c.TracingEndpoint).To(Equal("http://otel-collector:4318"))
+				Expect(c.TracingSampleRatio).To(Equal(0.25))
 				Expect(c.AllowedCORSDomains).To(Equal([]string{
 					"https://foo.bar",
 					"https://bar.foo",
@@ -159,11 +175,12 @@ var _ = Describe("Config", func() {
 						"timeout":       5,
 						"database_name": "network_policy",
 					},
-					"database_migration_timeout": 88,
-					"tag_length":                 2,
-					"metron_address":             "http://1.2.3.4:9999",
-					"cleanup_interval":           2,
-					"max_policies":               3,
+					"database_migration_timeout":    88,
+					"tag_length":                    2,
+					"metron_address":                "http://1.2.3.4:9999",
+					"cleanup_interval":              2,
+					"group_members_update_interval": 7,
+					"max_policies":                  3,
 				}
 				delete(allData, missingFlag)
 				Expect(json.NewEncoder(file).Encode(allData)).To(Succeed())
@@ -184,6 +201,7 @@ var _ = Describe("Config", func() {
 			Entry("missing tag length", "tag_length", "TagLength: zero value"),
 			Entry("missing metron address", "metron_address", "MetronAddress: zero value"),
 			Entry("missing cleanup interval", "cleanup_interval", "CleanupInterval: less than min"),
+			Entry("missing group members update interval", "group_members_update_interval", "GroupMembersUpdateInterval: less than min"),
 			Entry("missing max policies", "max_policies", "MaxPolicies: less than min"),
 			Entry("missing database migration timeout", "database_migration_timeout", "DatabaseMigrationTimeout: less than min"),
 		)
@@ -218,15 +236,27 @@ var _ = Describe("Config", func() {
 						"timeout":       5,
 						"database_name": "network_policy",
 					},
-					"database_migration_timeout": 88,
-					"tag_length":                 2,
-					"metron_address":             "http://1.2.3.4:9999",
-					"log_level":                  "info",
-					"cleanup_interval":           2,
-					"max_policies":               3,
+					"database_migration_timeout":    88,
+					"tag_length":                    2,
+					"metron_address":                "http://1.2.3.4:9999",
+					"log_level":                     "info",
+					"cleanup_interval":              2,
+					"group_members_update_interval": 7,
+					"max_policies":                  3,
 				}
 			})
 
+			Context("when the tracing sample ratio is more than 1", func() {
+				BeforeEach(func() {
+					allData["tracing_sample_ratio"] = 1.5
+					Expect(json.NewEncoder(file).Encode(allData)).To(Succeed())
+				})
+				It("returns an error", func() {
+					_, err = config.New(file.Name())
+					Expect(err).To(MatchError("invalid config: TracingSampleRatio: greater than max"))
+				})
+			})
+
 			Context("when the config file is missing a db type", func() {
 				BeforeEach(func() {
 					delete(allData["database"].(map[string]interface{}), "type")
@@ -27,6 +27,11 @@ type InternalConfig struct {
 	MaxIdleConnections            int       `json:"max_idle_connections" validate:"min=0"`
 	MaxOpenConnections            int       `json:"max_open_connections" validate:"min=0"`
 	MaxConnectionsLifetimeSeconds int       `json:"connections_max_lifetime_seconds" validate:"min=0"`
+	EnableWatch                   bool      `json:"enable_watch"`
+	WatchPollIntervalSeconds      int       `json:"watch_poll_interval_seconds" validate:"min=0"`
+	WatchHistorySize              int       `json:"watch_history_size" validate:"min=0"`
+	CachePolicies                 bool      `json:"cache_policies"`
+	EnablePrometheusMetrics       bool      `json:"enable_prometheus_metrics"`
 }
 
 func (c *InternalConfig) Validate() error {
@@ -47,6 +47,11 @@ var _ = Describe("InternalConfig", func() {
 					"max_idle_connections": 4,
 					"max_open_connections": 5,
 					"connections_max_lifetime_seconds": 45,
+					"enable_watch": true,
+					"watch_poll_interval_seconds": 3,
+					"watch_history_size": 500,
+					"cache_policies": true,
+					"enable_prometheus_metrics": true,
 					"tag_length": 2,
 					"metron_address": "http://1.2.3.4:9999",
 					"log_level": "debug"
@@ -75,6 +80,11 @@ var _ = Describe("InternalConfig", func() {
 				Expect(c.MaxIdleConnections).To(Equal(4))
 				Expect(c.MaxOpenConnections).To(Equal(5))
 				Expect(c.MaxConnectionsLifetimeSeconds).To(Equal(45))
+				Expect(c.EnableWatch).To(BeTrue())
+				Expect(c.WatchPollIntervalSeconds).To(Equal(3))
+				Expect(c.WatchHistorySize).To(Equal(500))
+				Expect(c.CachePolicies).To(BeTrue())
+				Expect(c.EnablePrometheusMetrics).To(BeTrue())
 			})
 		})
 
@@ -11,6 +11,8 @@ import (
 	"code.cloudfoundry.org/lager/v3"
 	"code.cloudfoundry.org/lager/v3/lagerflags"
 	"code.cloudfoundry.org/lib/common"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/tracing"
 	"code.cloudfoundry.org/policy-server/uaa_client"
 )
 
@@ -50,6 +52,16 @@ func getTokenData(req *http.Request) uaa_client.CheckTokenResponse {
 	return uaa_client.CheckTokenResponse{}
 }
 
+// getActor identifies the subject of a token for the policy events audit log.
+// Client credentials tokens have no user name, so the subject is used instead.
+func getActor(tokenData uaa_client.CheckTokenResponse) store.Actor {
+	name := tokenData.UserName
+	if name == "" {
+		name = tokenData.Subject
+	}
+	return store.Actor{Name: name, ClientID: tokenData.ClientID}
+}
+
 func (a *Authenticator) Wrap(handle http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		logger := getLogger(req)
@@ -65,7 +77,7 @@ func (a *Authenticator) Wrap(handle http.Handler) http.Handler {
 		token := authorization[0]
 		token = strings.TrimPrefix(token, "Bearer ")
 		token = strings.TrimPrefix(token, "bearer ")
-		tokenData, err := a.Client.CheckToken(token)
+		tokenData, err := tracing.Bind(req.Context(), a.Client).CheckToken(token)
 		if err != nil {
 			a.ErrorResponse.Unauthorized(logger, w, err, "failed to verify token with uaa")
 			return
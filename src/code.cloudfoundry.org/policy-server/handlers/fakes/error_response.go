@@ -17,6 +17,14 @@ type ErrorResponse struct {
 		arg3 error
 		arg4 string
 	}
+	ConflictStub        func(lager.Logger, http.ResponseWriter, error, string)
+	conflictMutex       sync.RWMutex
+	conflictArgsForCall []struct {
+		arg1 lager.Logger
+		arg2 http.ResponseWriter
+		arg3 error
+		arg4 string
+	}
 	ForbiddenStub        func(lager.Logger, http.ResponseWriter, error, string)
 	forbiddenMutex       sync.RWMutex
 	forbiddenArgsForCall []struct {
@@ -96,6 +104,41 @@ func (fake *ErrorResponse) BadRequestArgsForCall(i int) (lager.Logger, http.Resp
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
+func (fake *ErrorResponse) Conflict(arg1 lager.Logger, arg2 http.ResponseWriter, arg3 error, arg4 string) {
+	fake.conflictMutex.Lock()
+	fake.conflictArgsForCall = append(fake.conflictArgsForCall, struct {
+		arg1 lager.Logger
+		arg2 http.ResponseWriter
+		arg3 error
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.ConflictStub
+	fake.recordInvocation("Conflict", []interface{}{arg1, arg2, arg3, arg4})
+	fake.conflictMutex.Unlock()
+	if stub != nil {
+		fake.ConflictStub(arg1, arg2, arg3, arg4)
+	}
+}
+
+func (fake *ErrorResponse) ConflictCallCount() int {
+	fake.conflictMutex.RLock()
+	defer fake.conflictMutex.RUnlock()
+	return len(fake.conflictArgsForCall)
+}
+
+func (fake *ErrorResponse) ConflictCalls(stub func(lager.Logger, http.ResponseWriter, error, string)) {
+	fake.conflictMutex.Lock()
+	defer fake.conflictMutex.Unlock()
+	fake.ConflictStub = stub
+}
+
+func (fake *ErrorResponse) ConflictArgsForCall(i int) (lager.Logger, http.ResponseWriter, error, string) {
+	fake.conflictMutex.RLock()
+	defer fake.conflictMutex.RUnlock()
+	argsForCall := fake.conflictArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
 func (fake *ErrorResponse) Forbidden(arg1 lager.Logger, arg2 http.ResponseWriter, arg3 error, arg4 string) {
 	fake.forbiddenMutex.Lock()
 	fake.forbiddenArgsForCall = append(fake.forbiddenArgsForCall, struct {
@@ -276,6 +319,8 @@ func (fake *ErrorResponse) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.badRequestMutex.RLock()
 	defer fake.badRequestMutex.RUnlock()
+	fake.conflictMutex.RLock()
+	defer fake.conflictMutex.RUnlock()
 	fake.forbiddenMutex.RLock()
 	defer fake.forbiddenMutex.RUnlock()
 	fake.internalServerErrorMutex.RLock()
@@ -0,0 +1,409 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type PoliciesWatchStore struct {
+	AllStub        func() ([]store.Policy, error)
+	allMutex       sync.RWMutex
+	allArgsForCall []struct {
+	}
+	allReturns struct {
+		result1 []store.Policy
+		result2 error
+	}
+	allReturnsOnCall map[int]struct {
+		result1 []store.Policy
+		result2 error
+	}
+	ExpiredCountStub        func(time.Time) (int, error)
+	expiredCountMutex       sync.RWMutex
+	expiredCountArgsForCall []struct {
+		arg1 time.Time
+	}
+	expiredCountReturns struct {
+		result1 int
+		result2 error
+	}
+	expiredCountReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	GroupMembersStub        func([]string) ([]store.GroupMember, error)
+	groupMembersMutex       sync.RWMutex
+	groupMembersArgsForCall []struct {
+		arg1 []string
+	}
+	groupMembersReturns struct {
+		result1 []store.GroupMember
+		result2 error
+	}
+	groupMembersReturnsOnCall map[int]struct {
+		result1 []store.GroupMember
+		result2 error
+	}
+	LastUpdatedStub        func() (int, error)
+	lastUpdatedMutex       sync.RWMutex
+	lastUpdatedArgsForCall []struct {
+	}
+	lastUpdatedReturns struct {
+		result1 int
+		result2 error
+	}
+	lastUpdatedReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	QuarantinedStub        func() ([]string, error)
+	quarantinedMutex       sync.RWMutex
+	quarantinedArgsForCall []struct {
+	}
+	quarantinedReturns struct {
+		result1 []string
+		result2 error
+	}
+	quarantinedReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PoliciesWatchStore) All() ([]store.Policy, error) {
+	fake.allMutex.Lock()
+	ret, specificReturn := fake.allReturnsOnCall[len(fake.allArgsForCall)]
+	fake.allArgsForCall = append(fake.allArgsForCall, struct {
+	}{})
+	stub := fake.AllStub
+	fakeReturns := fake.allReturns
+	fake.recordInvocation("All", []interface{}{})
+	fake.allMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PoliciesWatchStore) AllCallCount() int {
+	fake.allMutex.RLock()
+	defer fake.allMutex.RUnlock()
+	return len(fake.allArgsForCall)
+}
+
+func (fake *PoliciesWatchStore) AllCalls(stub func() ([]store.Policy, error)) {
+	fake.allMutex.Lock()
+	defer fake.allMutex.Unlock()
+	fake.AllStub = stub
+}
+
+func (fake *PoliciesWatchStore) AllReturns(result1 []store.Policy, result2 error) {
+	fake.allMutex.Lock()
+	defer fake.allMutex.Unlock()
+	fake.AllStub = nil
+	fake.allReturns = struct {
+		result1 []store.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PoliciesWatchStore) AllReturnsOnCall(i int, result1 []store.Policy, result2 error) {
+	fake.allMutex.Lock()
+	defer fake.allMutex.Unlock()
+	fake.AllStub = nil
+	if fake.allReturnsOnCall == nil {
+		fake.allReturnsOnCall = make(map[int]struct {
+			result1 []store.Policy
+			result2 error
+		})
+	}
+	fake.allReturnsOnCall[i] = struct {
+		result1 []store.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PoliciesWatchStore) ExpiredCount(arg1 time.Time) (int, error) {
+	fake.expiredCountMutex.Lock()
+	ret, specificReturn := fake.expiredCountReturnsOnCall[len(fake.expiredCountArgsForCall)]
+	fake.expiredCountArgsForCall = append(fake.expiredCountArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	stub := fake.ExpiredCountStub
+	fakeReturns := fake.expiredCountReturns
+	fake.recordInvocation("ExpiredCount", []interface{}{arg1})
+	fake.expiredCountMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PoliciesWatchStore) ExpiredCountCallCount() int {
+	fake.expiredCountMutex.RLock()
+	defer fake.expiredCountMutex.RUnlock()
+	return len(fake.expiredCountArgsForCall)
+}
+
+func (fake *PoliciesWatchStore) ExpiredCountCalls(stub func(time.Time) (int, error)) {
+	fake.expiredCountMutex.Lock()
+	defer fake.expiredCountMutex.Unlock()
+	fake.ExpiredCountStub = stub
+}
+
+func (fake *PoliciesWatchStore) ExpiredCountArgsForCall(i int) time.Time {
+	fake.expiredCountMutex.RLock()
+	defer fake.expiredCountMutex.RUnlock()
+	argsForCall := fake.expiredCountArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PoliciesWatchStore) ExpiredCountReturns(result1 int, result2 error) {
+	fake.expiredCountMutex.Lock()
+	defer fake.expiredCountMutex.Unlock()
+	fake.ExpiredCountStub = nil
+	fake.expiredCountReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PoliciesWatchStore) ExpiredCountReturnsOnCall(i int, result1 int, result2 error) {
+	fake.expiredCountMutex.Lock()
+	defer fake.expiredCountMutex.Unlock()
+	fake.ExpiredCountStub = nil
+	if fake.expiredCountReturnsOnCall == nil {
+		fake.expiredCountReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.expiredCountReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PoliciesWatchStore) GroupMembers(arg1 []string) ([]store.GroupMember, error) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.groupMembersMutex.Lock()
+	ret, specificReturn := fake.groupMembersReturnsOnCall[len(fake.groupMembersArgsForCall)]
+	fake.groupMembersArgsForCall = append(fake.groupMembersArgsForCall, struct {
+		arg1 []string
+	}{arg1Copy})
+	stub := fake.GroupMembersStub
+	fakeReturns := fake.groupMembersReturns
+	fake.recordInvocation("GroupMembers", []interface{}{arg1Copy})
+	fake.groupMembersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PoliciesWatchStore) GroupMembersCallCount() int {
+	fake.groupMembersMutex.RLock()
+	defer fake.groupMembersMutex.RUnlock()
+	return len(fake.groupMembersArgsForCall)
+}
+
+func (fake *PoliciesWatchStore) GroupMembersCalls(stub func([]string) ([]store.GroupMember, error)) {
+	fake.groupMembersMutex.Lock()
+	defer fake.groupMembersMutex.Unlock()
+	fake.GroupMembersStub = stub
+}
+
+func (fake *PoliciesWatchStore) GroupMembersArgsForCall(i int) []string {
+	fake.groupMembersMutex.RLock()
+	defer fake.groupMembersMutex.RUnlock()
+	argsForCall := fake.groupMembersArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PoliciesWatchStore) GroupMembersReturns(result1 []store.GroupMember, result2 error) {
+	fake.groupMembersMutex.Lock()
+	defer fake.groupMembersMutex.Unlock()
+	fake.GroupMembersStub = nil
+	fake.groupMembersReturns = struct {
+		result1 []store.GroupMember
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PoliciesWatchStore) GroupMembersReturnsOnCall(i int, result1 []store.GroupMember, result2 error) {
+	fake.groupMembersMutex.Lock()
+	defer fake.groupMembersMutex.Unlock()
+	fake.GroupMembersStub = nil
+	if fake.groupMembersReturnsOnCall == nil {
+		fake.groupMembersReturnsOnCall = make(map[int]struct {
+			result1 []store.GroupMember
+			result2 error
+		})
+	}
+	fake.groupMembersReturnsOnCall[i] = struct {
+		result1 []store.GroupMember
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PoliciesWatchStore) LastUpdated() (int, error) {
+	fake.lastUpdatedMutex.Lock()
+	ret, specificReturn := fake.lastUpdatedReturnsOnCall[len(fake.lastUpdatedArgsForCall)]
+	fake.lastUpdatedArgsForCall = append(fake.lastUpdatedArgsForCall, struct {
+	}{})
+	stub := fake.LastUpdatedStub
+	fakeReturns := fake.lastUpdatedReturns
+	fake.recordInvocation("LastUpdated", []interface{}{})
+	fake.lastUpdatedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PoliciesWatchStore) LastUpdatedCallCount() int {
+	fake.lastUpdatedMutex.RLock()
+	defer fake.lastUpdatedMutex.RUnlock()
+	return len(fake.lastUpdatedArgsForCall)
+}
+
+func (fake *PoliciesWatchStore) LastUpdatedCalls(stub func() (int, error)) {
+	fake.lastUpdatedMutex.Lock()
+	defer fake.lastUpdatedMutex.Unlock()
+	fake.LastUpdatedStub = stub
+}
+
+func (fake *PoliciesWatchStore) LastUpdatedReturns(result1 int, result2 error) {
+	fake.lastUpdatedMutex.Lock()
+	defer fake.lastUpdatedMutex.Unlock()
+	fake.LastUpdatedStub = nil
+	fake.lastUpdatedReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PoliciesWatchStore) LastUpdatedReturnsOnCall(i int, result1 int, result2 error) {
+	fake.lastUpdatedMutex.Lock()
+	defer fake.lastUpdatedMutex.Unlock()
+	fake.LastUpdatedStub = nil
+	if fake.lastUpdatedReturnsOnCall == nil {
+		fake.lastUpdatedReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.lastUpdatedReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PoliciesWatchStore) Quarantined() ([]string, error) {
+	fake.quarantinedMutex.Lock()
+	ret, specificReturn := fake.quarantinedReturnsOnCall[len(fake.quarantinedArgsForCall)]
+	fake.quarantinedArgsForCall = append(fake.quarantinedArgsForCall, struct {
+	}{})
+	stub := fake.QuarantinedStub
+	fakeReturns := fake.quarantinedReturns
+	fake.recordInvocation("Quarantined", []interface{}{})
+	fake.quarantinedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PoliciesWatchStore) QuarantinedCallCount() int {
+	fake.quarantinedMutex.RLock()
+	defer fake.quarantinedMutex.RUnlock()
+	return len(fake.quarantinedArgsForCall)
+}
+
+func (fake *PoliciesWatchStore) QuarantinedCalls(stub func() ([]string, error)) {
+	fake.quarantinedMutex.Lock()
+	defer fake.quarantinedMutex.Unlock()
+	fake.QuarantinedStub = stub
+}
+
+func (fake *PoliciesWatchStore) QuarantinedReturns(result1 []string, result2 error) {
+	fake.quarantinedMutex.Lock()
+	defer fake.quarantinedMutex.Unlock()
+	fake.QuarantinedStub = nil
+	fake.quarantinedReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PoliciesWatchStore) QuarantinedReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.quarantinedMutex.Lock()
+	defer fake.quarantinedMutex.Unlock()
+	fake.QuarantinedStub = nil
+	if fake.quarantinedReturnsOnCall == nil {
+		fake.quarantinedReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.quarantinedReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PoliciesWatchStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.allMutex.RLock()
+	defer fake.allMutex.RUnlock()
+	fake.expiredCountMutex.RLock()
+	defer fake.expiredCountMutex.RUnlock()
+	fake.groupMembersMutex.RLock()
+	defer fake.groupMembersMutex.RUnlock()
+	fake.lastUpdatedMutex.RLock()
+	defer fake.lastUpdatedMutex.RUnlock()
+	fake.quarantinedMutex.RLock()
+	defer fake.quarantinedMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PoliciesWatchStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,157 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/policy-server/handlers"
+)
+
+type PoliciesWatcher struct {
+	UnwatchStub        func(*handlers.PolicyWatch)
+	unwatchMutex       sync.RWMutex
+	unwatchArgsForCall []struct {
+		arg1 *handlers.PolicyWatch
+	}
+	WatchStub        func(string, int, bool) (*handlers.PolicyWatch, error)
+	watchMutex       sync.RWMutex
+	watchArgsForCall []struct {
+		arg1 string
+		arg2 int
+		arg3 bool
+	}
+	watchReturns struct {
+		result1 *handlers.PolicyWatch
+		result2 error
+	}
+	watchReturnsOnCall map[int]struct {
+		result1 *handlers.PolicyWatch
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PoliciesWatcher) Unwatch(arg1 *handlers.PolicyWatch) {
+	fake.unwatchMutex.Lock()
+	fake.unwatchArgsForCall = append(fake.unwatchArgsForCall, struct {
+		arg1 *handlers.PolicyWatch
+	}{arg1})
+	stub := fake.UnwatchStub
+	fake.recordInvocation("Unwatch", []interface{}{arg1})
+	fake.unwatchMutex.Unlock()
+	if stub != nil {
+		fake.UnwatchStub(arg1)
+	}
+}
+
+func (fake *PoliciesWatcher) UnwatchCallCount() int {
+	fake.unwatchMutex.RLock()
+	defer fake.unwatchMutex.RUnlock()
+	return len(fake.unwatchArgsForCall)
+}
+
+func (fake *PoliciesWatcher) UnwatchCalls(stub func(*handlers.PolicyWatch)) {
+	fake.unwatchMutex.Lock()
+	defer fake.unwatchMutex.Unlock()
+	fake.UnwatchStub = stub
+}
+
+func (fake *PoliciesWatcher) UnwatchArgsForCall(i int) *handlers.PolicyWatch {
+	fake.unwatchMutex.RLock()
+	defer fake.unwatchMutex.RUnlock()
+	argsForCall := fake.unwatchArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PoliciesWatcher) Watch(arg1 string, arg2 int, arg3 bool) (*handlers.PolicyWatch, error) {
+	fake.watchMutex.Lock()
+	ret, specificReturn := fake.watchReturnsOnCall[len(fake.watchArgsForCall)]
+	fake.watchArgsForCall = append(fake.watchArgsForCall, struct {
+		arg1 string
+		arg2 int
+		arg3 bool
+	}{arg1, arg2, arg3})
+	stub := fake.WatchStub
+	fakeReturns := fake.watchReturns
+	fake.recordInvocation("Watch", []interface{}{arg1, arg2, arg3})
+	fake.watchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PoliciesWatcher) WatchCallCount() int {
+	fake.watchMutex.RLock()
+	defer fake.watchMutex.RUnlock()
+	return len(fake.watchArgsForCall)
+}
+
+func (fake *PoliciesWatcher) WatchCalls(stub func(string, int, bool) (*handlers.PolicyWatch, error)) {
+	fake.watchMutex.Lock()
+	defer fake.watchMutex.Unlock()
+	fake.WatchStub = stub
+}
+
+func (fake *PoliciesWatcher) WatchArgsForCall(i int) (string, int, bool) {
+	fake.watchMutex.RLock()
+	defer fake.watchMutex.RUnlock()
+	argsForCall := fake.watchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *PoliciesWatcher) WatchReturns(result1 *handlers.PolicyWatch, result2 error) {
+	fake.watchMutex.Lock()
+	defer fake.watchMutex.Unlock()
+	fake.WatchStub = nil
+	fake.watchReturns = struct {
+		result1 *handlers.PolicyWatch
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PoliciesWatcher) WatchReturnsOnCall(i int, result1 *handlers.PolicyWatch, result2 error) {
+	fake.watchMutex.Lock()
+	defer fake.watchMutex.Unlock()
+	fake.WatchStub = nil
+	if fake.watchReturnsOnCall == nil {
+		fake.watchReturnsOnCall = make(map[int]struct {
+			result1 *handlers.PolicyWatch
+			result2 error
+		})
+	}
+	fake.watchReturnsOnCall[i] = struct {
+		result1 *handlers.PolicyWatch
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PoliciesWatcher) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.unwatchMutex.RLock()
+	defer fake.unwatchMutex.RUnlock()
+	fake.watchMutex.RLock()
+	defer fake.watchMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PoliciesWatcher) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
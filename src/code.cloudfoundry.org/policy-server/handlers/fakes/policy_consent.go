@@ -0,0 +1,294 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+)
+
+type PolicyConsent struct {
+	ApprovableStub        func([]store.PendingPolicy, uaa_client.CheckTokenResponse) ([]store.PendingPolicy, error)
+	approvableMutex       sync.RWMutex
+	approvableArgsForCall []struct {
+		arg1 []store.PendingPolicy
+		arg2 uaa_client.CheckTokenResponse
+	}
+	approvableReturns struct {
+		result1 []store.PendingPolicy
+		result2 error
+	}
+	approvableReturnsOnCall map[int]struct {
+		result1 []store.PendingPolicy
+		result2 error
+	}
+	CanApproveStub        func(string, uaa_client.CheckTokenResponse) (bool, error)
+	canApproveMutex       sync.RWMutex
+	canApproveArgsForCall []struct {
+		arg1 string
+		arg2 uaa_client.CheckTokenResponse
+	}
+	canApproveReturns struct {
+		result1 bool
+		result2 error
+	}
+	canApproveReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	PendingPoliciesStub        func([]store.Policy, uaa_client.CheckTokenResponse) ([]store.Policy, []store.PendingPolicy, error)
+	pendingPoliciesMutex       sync.RWMutex
+	pendingPoliciesArgsForCall []struct {
+		arg1 []store.Policy
+		arg2 uaa_client.CheckTokenResponse
+	}
+	pendingPoliciesReturns struct {
+		result1 []store.Policy
+		result2 []store.PendingPolicy
+		result3 error
+	}
+	pendingPoliciesReturnsOnCall map[int]struct {
+		result1 []store.Policy
+		result2 []store.PendingPolicy
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PolicyConsent) Approvable(arg1 []store.PendingPolicy, arg2 uaa_client.CheckTokenResponse) ([]store.PendingPolicy, error) {
+	var arg1Copy []store.PendingPolicy
+	if arg1 != nil {
+		arg1Copy = make([]store.PendingPolicy, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.approvableMutex.Lock()
+	ret, specificReturn := fake.approvableReturnsOnCall[len(fake.approvableArgsForCall)]
+	fake.approvableArgsForCall = append(fake.approvableArgsForCall, struct {
+		arg1 []store.PendingPolicy
+		arg2 uaa_client.CheckTokenResponse
+	}{arg1Copy, arg2})
+	stub := fake.ApprovableStub
+	fakeReturns := fake.approvableReturns
+	fake.recordInvocation("Approvable", []interface{}{arg1Copy, arg2})
+	fake.approvableMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PolicyConsent) ApprovableCallCount() int {
+	fake.approvableMutex.RLock()
+	defer fake.approvableMutex.RUnlock()
+	return len(fake.approvableArgsForCall)
+}
+
+func (fake *PolicyConsent) ApprovableCalls(stub func([]store.PendingPolicy, uaa_client.CheckTokenResponse) ([]store.PendingPolicy, error)) {
+	fake.approvableMutex.Lock()
+	defer fake.approvableMutex.Unlock()
+	fake.ApprovableStub = stub
+}
+
+func (fake *PolicyConsent) ApprovableArgsForCall(i int) ([]store.PendingPolicy, uaa_client.CheckTokenResponse) {
+	fake.approvableMutex.RLock()
+	defer fake.approvableMutex.RUnlock()
+	argsForCall := fake.approvableArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PolicyConsent) ApprovableReturns(result1 []store.PendingPolicy, result2 error) {
+	fake.approvableMutex.Lock()
+	defer fake.approvableMutex.Unlock()
+	fake.ApprovableStub = nil
+	fake.approvableReturns = struct {
+		result1 []store.PendingPolicy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyConsent) ApprovableReturnsOnCall(i int, result1 []store.PendingPolicy, result2 error) {
+	fake.approvableMutex.Lock()
+	defer fake.approvableMutex.Unlock()
+	fake.ApprovableStub = nil
+	if fake.approvableReturnsOnCall == nil {
+		fake.approvableReturnsOnCall = make(map[int]struct {
+			result1 []store.PendingPolicy
+			result2 error
+		})
+	}
+	fake.approvableReturnsOnCall[i] = struct {
+		result1 []store.PendingPolicy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyConsent) CanApprove(arg1 string, arg2 uaa_client.CheckTokenResponse) (bool, error) {
+	fake.canApproveMutex.Lock()
+	ret, specificReturn := fake.canApproveReturnsOnCall[len(fake.canApproveArgsForCall)]
+	fake.canApproveArgsForCall = append(fake.canApproveArgsForCall, struct {
+		arg1 string
+		arg2 uaa_client.CheckTokenResponse
+	}{arg1, arg2})
+	stub := fake.CanApproveStub
+	fakeReturns := fake.canApproveReturns
+	fake.recordInvocation("CanApprove", []interface{}{arg1, arg2})
+	fake.canApproveMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PolicyConsent) CanApproveCallCount() int {
+	fake.canApproveMutex.RLock()
+	defer fake.canApproveMutex.RUnlock()
+	return len(fake.canApproveArgsForCall)
+}
+
+func (fake *PolicyConsent) CanApproveCalls(stub func(string, uaa_client.CheckTokenResponse) (bool, error)) {
+	fake.canApproveMutex.Lock()
+	defer fake.canApproveMutex.Unlock()
+	fake.CanApproveStub = stub
+}
+
+func (fake *PolicyConsent) CanApproveArgsForCall(i int) (string, uaa_client.CheckTokenResponse) {
+	fake.canApproveMutex.RLock()
+	defer fake.canApproveMutex.RUnlock()
+	argsForCall := fake.canApproveArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PolicyConsent) CanApproveReturns(result1 bool, result2 error) {
+	fake.canApproveMutex.Lock()
+	defer fake.canApproveMutex.Unlock()
+	fake.CanApproveStub = nil
+	fake.canApproveReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyConsent) CanApproveReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.canApproveMutex.Lock()
+	defer fake.canApproveMutex.Unlock()
+	fake.CanApproveStub = nil
+	if fake.canApproveReturnsOnCall == nil {
+		fake.canApproveReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.canApproveReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyConsent) PendingPolicies(arg1 []store.Policy, arg2 uaa_client.CheckTokenResponse) ([]store.Policy, []store.PendingPolicy, error) {
+	var arg1Copy []store.Policy
+	if arg1 != nil {
+		arg1Copy = make([]store.Policy, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.pendingPoliciesMutex.Lock()
+	ret, specificReturn := fake.pendingPoliciesReturnsOnCall[len(fake.pendingPoliciesArgsForCall)]
+	fake.pendingPoliciesArgsForCall = append(fake.pendingPoliciesArgsForCall, struct {
+		arg1 []store.Policy
+		arg2 uaa_client.CheckTokenResponse
+	}{arg1Copy, arg2})
+	stub := fake.PendingPoliciesStub
+	fakeReturns := fake.pendingPoliciesReturns
+	fake.recordInvocation("PendingPolicies", []interface{}{arg1Copy, arg2})
+	fake.pendingPoliciesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *PolicyConsent) PendingPoliciesCallCount() int {
+	fake.pendingPoliciesMutex.RLock()
+	defer fake.pendingPoliciesMutex.RUnlock()
+	return len(fake.pendingPoliciesArgsForCall)
+}
+
+func (fake *PolicyConsent) PendingPoliciesCalls(stub func([]store.Policy, uaa_client.CheckTokenResponse) ([]store.Policy, []store.PendingPolicy, error)) {
+	fake.pendingPoliciesMutex.Lock()
+	defer fake.pendingPoliciesMutex.Unlock()
+	fake.PendingPoliciesStub = stub
+}
+
+func (fake *PolicyConsent) PendingPoliciesArgsForCall(i int) ([]store.Policy, uaa_client.CheckTokenResponse) {
+	fake.pendingPoliciesMutex.RLock()
+	defer fake.pendingPoliciesMutex.RUnlock()
+	argsForCall := fake.pendingPoliciesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PolicyConsent) PendingPoliciesReturns(result1 []store.Policy, result2 []store.PendingPolicy, result3 error) {
+	fake.pendingPoliciesMutex.Lock()
+	defer fake.pendingPoliciesMutex.Unlock()
+	fake.PendingPoliciesStub = nil
+	fake.pendingPoliciesReturns = struct {
+		result1 []store.Policy
+		result2 []store.PendingPolicy
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *PolicyConsent) PendingPoliciesReturnsOnCall(i int, result1 []store.Policy, result2 []store.PendingPolicy, result3 error) {
+	fake.pendingPoliciesMutex.Lock()
+	defer fake.pendingPoliciesMutex.Unlock()
+	fake.PendingPoliciesStub = nil
+	if fake.pendingPoliciesReturnsOnCall == nil {
+		fake.pendingPoliciesReturnsOnCall = make(map[int]struct {
+			result1 []store.Policy
+			result2 []store.PendingPolicy
+			result3 error
+		})
+	}
+	fake.pendingPoliciesReturnsOnCall[i] = struct {
+		result1 []store.Policy
+		result2 []store.PendingPolicy
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *PolicyConsent) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.approvableMutex.RLock()
+	defer fake.approvableMutex.RUnlock()
+	fake.canApproveMutex.RLock()
+	defer fake.canApproveMutex.RUnlock()
+	fake.pendingPoliciesMutex.RLock()
+	defer fake.pendingPoliciesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PolicyConsent) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
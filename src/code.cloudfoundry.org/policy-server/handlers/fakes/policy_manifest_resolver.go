@@ -0,0 +1,209 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type PolicyManifestResolver struct {
+	ManifestPoliciesStub        func([]store.Policy) ([]api.ManifestPolicy, []store.Policy, error)
+	manifestPoliciesMutex       sync.RWMutex
+	manifestPoliciesArgsForCall []struct {
+		arg1 []store.Policy
+	}
+	manifestPoliciesReturns struct {
+		result1 []api.ManifestPolicy
+		result2 []store.Policy
+		result3 error
+	}
+	manifestPoliciesReturnsOnCall map[int]struct {
+		result1 []api.ManifestPolicy
+		result2 []store.Policy
+		result3 error
+	}
+	PoliciesStub        func([]api.ManifestPolicy) ([]api.Policy, error)
+	policiesMutex       sync.RWMutex
+	policiesArgsForCall []struct {
+		arg1 []api.ManifestPolicy
+	}
+	policiesReturns struct {
+		result1 []api.Policy
+		result2 error
+	}
+	policiesReturnsOnCall map[int]struct {
+		result1 []api.Policy
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PolicyManifestResolver) ManifestPolicies(arg1 []store.Policy) ([]api.ManifestPolicy, []store.Policy, error) {
+	var arg1Copy []store.Policy
+	if arg1 != nil {
+		arg1Copy = make([]store.Policy, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.manifestPoliciesMutex.Lock()
+	ret, specificReturn := fake.manifestPoliciesReturnsOnCall[len(fake.manifestPoliciesArgsForCall)]
+	fake.manifestPoliciesArgsForCall = append(fake.manifestPoliciesArgsForCall, struct {
+		arg1 []store.Policy
+	}{arg1Copy})
+	stub := fake.ManifestPoliciesStub
+	fakeReturns := fake.manifestPoliciesReturns
+	fake.recordInvocation("ManifestPolicies", []interface{}{arg1Copy})
+	fake.manifestPoliciesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *PolicyManifestResolver) ManifestPoliciesCallCount() int {
+	fake.manifestPoliciesMutex.RLock()
+	defer fake.manifestPoliciesMutex.RUnlock()
+	return len(fake.manifestPoliciesArgsForCall)
+}
+
+func (fake *PolicyManifestResolver) ManifestPoliciesCalls(stub func([]store.Policy) ([]api.ManifestPolicy, []store.Policy, error)) {
+	fake.manifestPoliciesMutex.Lock()
+	defer fake.manifestPoliciesMutex.Unlock()
+	fake.ManifestPoliciesStub = stub
+}
+
+func (fake *PolicyManifestResolver) ManifestPoliciesArgsForCall(i int) []store.Policy {
+	fake.manifestPoliciesMutex.RLock()
+	defer fake.manifestPoliciesMutex.RUnlock()
+	argsForCall := fake.manifestPoliciesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PolicyManifestResolver) ManifestPoliciesReturns(result1 []api.ManifestPolicy, result2 []store.Policy, result3 error) {
+	fake.manifestPoliciesMutex.Lock()
+	defer fake.manifestPoliciesMutex.Unlock()
+	fake.ManifestPoliciesStub = nil
+	fake.manifestPoliciesReturns = struct {
+		result1 []api.ManifestPolicy
+		result2 []store.Policy
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *PolicyManifestResolver) ManifestPoliciesReturnsOnCall(i int, result1 []api.ManifestPolicy, result2 []store.Policy, result3 error) {
+	fake.manifestPoliciesMutex.Lock()
+	defer fake.manifestPoliciesMutex.Unlock()
+	fake.ManifestPoliciesStub = nil
+	if fake.manifestPoliciesReturnsOnCall == nil {
+		fake.manifestPoliciesReturnsOnCall = make(map[int]struct {
+			result1 []api.ManifestPolicy
+			result2 []store.Policy
+			result3 error
+		})
+	}
+	fake.manifestPoliciesReturnsOnCall[i] = struct {
+		result1 []api.ManifestPolicy
+		result2 []store.Policy
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *PolicyManifestResolver) Policies(arg1 []api.ManifestPolicy) ([]api.Policy, error) {
+	var arg1Copy []api.ManifestPolicy
+	if arg1 != nil {
+		arg1Copy = make([]api.ManifestPolicy, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.policiesMutex.Lock()
+	ret, specificReturn := fake.policiesReturnsOnCall[len(fake.policiesArgsForCall)]
+	fake.policiesArgsForCall = append(fake.policiesArgsForCall, struct {
+		arg1 []api.ManifestPolicy
+	}{arg1Copy})
+	stub := fake.PoliciesStub
+	fakeReturns := fake.policiesReturns
+	fake.recordInvocation("Policies", []interface{}{arg1Copy})
+	fake.policiesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PolicyManifestResolver) PoliciesCallCount() int {
+	fake.policiesMutex.RLock()
+	defer fake.policiesMutex.RUnlock()
+	return len(fake.policiesArgsForCall)
+}
+
+func (fake *PolicyManifestResolver) PoliciesCalls(stub func([]api.ManifestPolicy) ([]api.Policy, error)) {
+	fake.policiesMutex.Lock()
+	defer fake.policiesMutex.Unlock()
+	fake.PoliciesStub = stub
+}
+
+func (fake *PolicyManifestResolver) PoliciesArgsForCall(i int) []api.ManifestPolicy {
+	fake.policiesMutex.RLock()
+	defer fake.policiesMutex.RUnlock()
+	argsForCall := fake.policiesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PolicyManifestResolver) PoliciesReturns(result1 []api.Policy, result2 error) {
+	fake.policiesMutex.Lock()
+	defer fake.policiesMutex.Unlock()
+	fake.PoliciesStub = nil
+	fake.policiesReturns = struct {
+		result1 []api.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyManifestResolver) PoliciesReturnsOnCall(i int, result1 []api.Policy, result2 error) {
+	fake.policiesMutex.Lock()
+	defer fake.policiesMutex.Unlock()
+	fake.PoliciesStub = nil
+	if fake.policiesReturnsOnCall == nil {
+		fake.policiesReturnsOnCall = make(map[int]struct {
+			result1 []api.Policy
+			result2 error
+		})
+	}
+	fake.policiesReturnsOnCall[i] = struct {
+		result1 []api.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyManifestResolver) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.manifestPoliciesMutex.RLock()
+	defer fake.manifestPoliciesMutex.RUnlock()
+	fake.policiesMutex.RLock()
+	defer fake.policiesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PolicyManifestResolver) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -8,6 +8,30 @@ import (
 )
 
 type PolicyStore struct {
+	AllStub        func() ([]store.Policy, error)
+	allMutex       sync.RWMutex
+	allArgsForCall []struct {
+	}
+	allReturns struct {
+		result1 []store.Policy
+		result2 error
+	}
+	allReturnsOnCall map[int]struct {
+		result1 []store.Policy
+		result2 error
+	}
+	ApprovePendingStub        func(store.PendingPolicy, store.Actor) error
+	approvePendingMutex       sync.RWMutex
+	approvePendingArgsForCall []struct {
+		arg1 store.PendingPolicy
+		arg2 store.Actor
+	}
+	approvePendingReturns struct {
+		result1 error
+	}
+	approvePendingReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ByGuidsStub        func([]string, []string, bool) ([]store.Policy, error)
 	byGuidsMutex       sync.RWMutex
 	byGuidsArgsForCall []struct {
@@ -23,32 +47,207 @@ type PolicyStore struct {
 		result1 []store.Policy
 		result2 error
 	}
-	CreateStub        func([]store.Policy) error
-	createMutex       sync.RWMutex
-	createArgsForCall []struct {
+	CreateWithEventStub        func([]store.Policy, store.Actor) error
+	createWithEventMutex       sync.RWMutex
+	createWithEventArgsForCall []struct {
+		arg1 []store.Policy
+		arg2 store.Actor
+	}
+	createWithEventReturns struct {
+		result1 error
+	}
+	createWithEventReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateWithPendingStub        func([]store.Policy, []store.Policy, []store.PendingPolicy, store.Actor) error
+	createWithPendingMutex       sync.RWMutex
+	createWithPendingArgsForCall []struct {
+		arg1 []store.Policy
+		arg2 []store.Policy
+		arg3 []store.PendingPolicy
+		arg4 store.Actor
+	}
+	createWithPendingReturns struct {
+		result1 error
+	}
+	createWithPendingReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteWithEventStub        func([]store.Policy, store.Actor) error
+	deleteWithEventMutex       sync.RWMutex
+	deleteWithEventArgsForCall []struct {
+		arg1 []store.Policy
+		arg2 store.Actor
+	}
+	deleteWithEventReturns struct {
+		result1 error
+	}
+	deleteWithEventReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ImportWithEventStub        func([]store.Policy, []store.Policy, store.Actor) error
+	importWithEventMutex       sync.RWMutex
+	importWithEventArgsForCall []struct {
 		arg1 []store.Policy
+		arg2 []store.Policy
+		arg3 store.Actor
 	}
-	createReturns struct {
+	importWithEventReturns struct {
 		result1 error
 	}
-	createReturnsOnCall map[int]struct {
+	importWithEventReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteStub        func([]store.Policy) error
-	deleteMutex       sync.RWMutex
-	deleteArgsForCall []struct {
+	MergeWithEventStub        func([]store.Policy, []store.Policy, store.Actor) error
+	mergeWithEventMutex       sync.RWMutex
+	mergeWithEventArgsForCall []struct {
 		arg1 []store.Policy
+		arg2 []store.Policy
+		arg3 store.Actor
+	}
+	mergeWithEventReturns struct {
+		result1 error
+	}
+	mergeWithEventReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReplaceForSourceStub        func(string, []store.Policy, []store.PendingPolicy, store.Actor, func([]store.Policy) error) error
+	replaceForSourceMutex       sync.RWMutex
+	replaceForSourceArgsForCall []struct {
+		arg1 string
+		arg2 []store.Policy
+		arg3 []store.PendingPolicy
+		arg4 store.Actor
+		arg5 func([]store.Policy) error
 	}
-	deleteReturns struct {
+	replaceForSourceReturns struct {
 		result1 error
 	}
-	deleteReturnsOnCall map[int]struct {
+	replaceForSourceReturnsOnCall map[int]struct {
 		result1 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *PolicyStore) All() ([]store.Policy, error) {
+	fake.allMutex.Lock()
+	ret, specificReturn := fake.allReturnsOnCall[len(fake.allArgsForCall)]
+	fake.allArgsForCall = append(fake.allArgsForCall, struct {
+	}{})
+	stub := fake.AllStub
+	fakeReturns := fake.allReturns
+	fake.recordInvocation("All", []interface{}{})
+	fake.allMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PolicyStore) AllCallCount() int {
+	fake.allMutex.RLock()
+	defer fake.allMutex.RUnlock()
+	return len(fake.allArgsForCall)
+}
+
+func (fake *PolicyStore) AllCalls(stub func() ([]store.Policy, error)) {
+	fake.allMutex.Lock()
+	defer fake.allMutex.Unlock()
+	fake.AllStub = stub
+}
+
+func (fake *PolicyStore) AllReturns(result1 []store.Policy, result2 error) {
+	fake.allMutex.Lock()
+	defer fake.allMutex.Unlock()
+	fake.AllStub = nil
+	fake.allReturns = struct {
+		result1 []store.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyStore) AllReturnsOnCall(i int, result1 []store.Policy, result2 error) {
+	fake.allMutex.Lock()
+	defer fake.allMutex.Unlock()
+	fake.AllStub = nil
+	if fake.allReturnsOnCall == nil {
+		fake.allReturnsOnCall = make(map[int]struct {
+			result1 []store.Policy
+			result2 error
+		})
+	}
+	fake.allReturnsOnCall[i] = struct {
+		result1 []store.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyStore) ApprovePending(arg1 store.PendingPolicy, arg2 store.Actor) error {
+	fake.approvePendingMutex.Lock()
+	ret, specificReturn := fake.approvePendingReturnsOnCall[len(fake.approvePendingArgsForCall)]
+	fake.approvePendingArgsForCall = append(fake.approvePendingArgsForCall, struct {
+		arg1 store.PendingPolicy
+		arg2 store.Actor
+	}{arg1, arg2})
+	stub := fake.ApprovePendingStub
+	fakeReturns := fake.approvePendingReturns
+	fake.recordInvocation("ApprovePending", []interface{}{arg1, arg2})
+	fake.approvePendingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *PolicyStore) ApprovePendingCallCount() int {
+	fake.approvePendingMutex.RLock()
+	defer fake.approvePendingMutex.RUnlock()
+	return len(fake.approvePendingArgsForCall)
+}
+
+func (fake *PolicyStore) ApprovePendingCalls(stub func(store.PendingPolicy, store.Actor) error) {
+	fake.approvePendingMutex.Lock()
+	defer fake.approvePendingMutex.Unlock()
+	fake.ApprovePendingStub = stub
+}
+
+func (fake *PolicyStore) ApprovePendingArgsForCall(i int) (store.PendingPolicy, store.Actor) {
+	fake.approvePendingMutex.RLock()
+	defer fake.approvePendingMutex.RUnlock()
+	argsForCall := fake.approvePendingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PolicyStore) ApprovePendingReturns(result1 error) {
+	fake.approvePendingMutex.Lock()
+	defer fake.approvePendingMutex.Unlock()
+	fake.ApprovePendingStub = nil
+	fake.approvePendingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PolicyStore) ApprovePendingReturnsOnCall(i int, result1 error) {
+	fake.approvePendingMutex.Lock()
+	defer fake.approvePendingMutex.Unlock()
+	fake.ApprovePendingStub = nil
+	if fake.approvePendingReturnsOnCall == nil {
+		fake.approvePendingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.approvePendingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *PolicyStore) ByGuids(arg1 []string, arg2 []string, arg3 bool) ([]store.Policy, error) {
 	var arg1Copy []string
 	if arg1 != nil {
@@ -125,23 +324,243 @@ func (fake *PolicyStore) ByGuidsReturnsOnCall(i int, result1 []store.Policy, res
 	}{result1, result2}
 }
 
-func (fake *PolicyStore) Create(arg1 []store.Policy) error {
+func (fake *PolicyStore) CreateWithEvent(arg1 []store.Policy, arg2 store.Actor) error {
+	var arg1Copy []store.Policy
+	if arg1 != nil {
+		arg1Copy = make([]store.Policy, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.createWithEventMutex.Lock()
+	ret, specificReturn := fake.createWithEventReturnsOnCall[len(fake.createWithEventArgsForCall)]
+	fake.createWithEventArgsForCall = append(fake.createWithEventArgsForCall, struct {
+		arg1 []store.Policy
+		arg2 store.Actor
+	}{arg1Copy, arg2})
+	stub := fake.CreateWithEventStub
+	fakeReturns := fake.createWithEventReturns
+	fake.recordInvocation("CreateWithEvent", []interface{}{arg1Copy, arg2})
+	fake.createWithEventMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *PolicyStore) CreateWithEventCallCount() int {
+	fake.createWithEventMutex.RLock()
+	defer fake.createWithEventMutex.RUnlock()
+	return len(fake.createWithEventArgsForCall)
+}
+
+func (fake *PolicyStore) CreateWithEventCalls(stub func([]store.Policy, store.Actor) error) {
+	fake.createWithEventMutex.Lock()
+	defer fake.createWithEventMutex.Unlock()
+	fake.CreateWithEventStub = stub
+}
+
+func (fake *PolicyStore) CreateWithEventArgsForCall(i int) ([]store.Policy, store.Actor) {
+	fake.createWithEventMutex.RLock()
+	defer fake.createWithEventMutex.RUnlock()
+	argsForCall := fake.createWithEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PolicyStore) CreateWithEventReturns(result1 error) {
+	fake.createWithEventMutex.Lock()
+	defer fake.createWithEventMutex.Unlock()
+	fake.CreateWithEventStub = nil
+	fake.createWithEventReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PolicyStore) CreateWithEventReturnsOnCall(i int, result1 error) {
+	fake.createWithEventMutex.Lock()
+	defer fake.createWithEventMutex.Unlock()
+	fake.CreateWithEventStub = nil
+	if fake.createWithEventReturnsOnCall == nil {
+		fake.createWithEventReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createWithEventReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PolicyStore) CreateWithPending(arg1 []store.Policy, arg2 []store.Policy, arg3 []store.PendingPolicy, arg4 store.Actor) error {
+	var arg1Copy []store.Policy
+	if arg1 != nil {
+		arg1Copy = make([]store.Policy, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	var arg2Copy []store.Policy
+	if arg2 != nil {
+		arg2Copy = make([]store.Policy, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	var arg3Copy []store.PendingPolicy
+	if arg3 != nil {
+		arg3Copy = make([]store.PendingPolicy, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.createWithPendingMutex.Lock()
+	ret, specificReturn := fake.createWithPendingReturnsOnCall[len(fake.createWithPendingArgsForCall)]
+	fake.createWithPendingArgsForCall = append(fake.createWithPendingArgsForCall, struct {
+		arg1 []store.Policy
+		arg2 []store.Policy
+		arg3 []store.PendingPolicy
+		arg4 store.Actor
+	}{arg1Copy, arg2Copy, arg3Copy, arg4})
+	stub := fake.CreateWithPendingStub
+	fakeReturns := fake.createWithPendingReturns
+	fake.recordInvocation("CreateWithPending", []interface{}{arg1Copy, arg2Copy, arg3Copy, arg4})
+	fake.createWithPendingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *PolicyStore) CreateWithPendingCallCount() int {
+	fake.createWithPendingMutex.RLock()
+	defer fake.createWithPendingMutex.RUnlock()
+	return len(fake.createWithPendingArgsForCall)
+}
+
+func (fake *PolicyStore) CreateWithPendingCalls(stub func([]store.Policy, []store.Policy, []store.PendingPolicy, store.Actor) error) {
+	fake.createWithPendingMutex.Lock()
+	defer fake.createWithPendingMutex.Unlock()
+	fake.CreateWithPendingStub = stub
+}
+
+func (fake *PolicyStore) CreateWithPendingArgsForCall(i int) ([]store.Policy, []store.Policy, []store.PendingPolicy, store.Actor) {
+	fake.createWithPendingMutex.RLock()
+	defer fake.createWithPendingMutex.RUnlock()
+	argsForCall := fake.createWithPendingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *PolicyStore) CreateWithPendingReturns(result1 error) {
+	fake.createWithPendingMutex.Lock()
+	defer fake.createWithPendingMutex.Unlock()
+	fake.CreateWithPendingStub = nil
+	fake.createWithPendingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PolicyStore) CreateWithPendingReturnsOnCall(i int, result1 error) {
+	fake.createWithPendingMutex.Lock()
+	defer fake.createWithPendingMutex.Unlock()
+	fake.CreateWithPendingStub = nil
+	if fake.createWithPendingReturnsOnCall == nil {
+		fake.createWithPendingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createWithPendingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PolicyStore) DeleteWithEvent(arg1 []store.Policy, arg2 store.Actor) error {
+	var arg1Copy []store.Policy
+	if arg1 != nil {
+		arg1Copy = make([]store.Policy, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.deleteWithEventMutex.Lock()
+	ret, specificReturn := fake.deleteWithEventReturnsOnCall[len(fake.deleteWithEventArgsForCall)]
+	fake.deleteWithEventArgsForCall = append(fake.deleteWithEventArgsForCall, struct {
+		arg1 []store.Policy
+		arg2 store.Actor
+	}{arg1Copy, arg2})
+	stub := fake.DeleteWithEventStub
+	fakeReturns := fake.deleteWithEventReturns
+	fake.recordInvocation("DeleteWithEvent", []interface{}{arg1Copy, arg2})
+	fake.deleteWithEventMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *PolicyStore) DeleteWithEventCallCount() int {
+	fake.deleteWithEventMutex.RLock()
+	defer fake.deleteWithEventMutex.RUnlock()
+	return len(fake.deleteWithEventArgsForCall)
+}
+
+func (fake *PolicyStore) DeleteWithEventCalls(stub func([]store.Policy, store.Actor) error) {
+	fake.deleteWithEventMutex.Lock()
+	defer fake.deleteWithEventMutex.Unlock()
+	fake.DeleteWithEventStub = stub
+}
+
+func (fake *PolicyStore) DeleteWithEventArgsForCall(i int) ([]store.Policy, store.Actor) {
+	fake.deleteWithEventMutex.RLock()
+	defer fake.deleteWithEventMutex.RUnlock()
+	argsForCall := fake.deleteWithEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PolicyStore) DeleteWithEventReturns(result1 error) {
+	fake.deleteWithEventMutex.Lock()
+	defer fake.deleteWithEventMutex.Unlock()
+	fake.DeleteWithEventStub = nil
+	fake.deleteWithEventReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PolicyStore) DeleteWithEventReturnsOnCall(i int, result1 error) {
+	fake.deleteWithEventMutex.Lock()
+	defer fake.deleteWithEventMutex.Unlock()
+	fake.DeleteWithEventStub = nil
+	if fake.deleteWithEventReturnsOnCall == nil {
+		fake.deleteWithEventReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteWithEventReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PolicyStore) ImportWithEvent(arg1 []store.Policy, arg2 []store.Policy, arg3 store.Actor) error {
 	var arg1Copy []store.Policy
 	if arg1 != nil {
 		arg1Copy = make([]store.Policy, len(arg1))
 		copy(arg1Copy, arg1)
 	}
-	fake.createMutex.Lock()
-	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
-	fake.createArgsForCall = append(fake.createArgsForCall, struct {
+	var arg2Copy []store.Policy
+	if arg2 != nil {
+		arg2Copy = make([]store.Policy, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.importWithEventMutex.Lock()
+	ret, specificReturn := fake.importWithEventReturnsOnCall[len(fake.importWithEventArgsForCall)]
+	fake.importWithEventArgsForCall = append(fake.importWithEventArgsForCall, struct {
 		arg1 []store.Policy
-	}{arg1Copy})
-	stub := fake.CreateStub
-	fakeReturns := fake.createReturns
-	fake.recordInvocation("Create", []interface{}{arg1Copy})
-	fake.createMutex.Unlock()
+		arg2 []store.Policy
+		arg3 store.Actor
+	}{arg1Copy, arg2Copy, arg3})
+	stub := fake.ImportWithEventStub
+	fakeReturns := fake.importWithEventReturns
+	fake.recordInvocation("ImportWithEvent", []interface{}{arg1Copy, arg2Copy, arg3})
+	fake.importWithEventMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -149,65 +568,147 @@ func (fake *PolicyStore) Create(arg1 []store.Policy) error {
 	return fakeReturns.result1
 }
 
-func (fake *PolicyStore) CreateCallCount() int {
-	fake.createMutex.RLock()
-	defer fake.createMutex.RUnlock()
-	return len(fake.createArgsForCall)
+func (fake *PolicyStore) ImportWithEventCallCount() int {
+	fake.importWithEventMutex.RLock()
+	defer fake.importWithEventMutex.RUnlock()
+	return len(fake.importWithEventArgsForCall)
 }
 
-func (fake *PolicyStore) CreateCalls(stub func([]store.Policy) error) {
-	fake.createMutex.Lock()
-	defer fake.createMutex.Unlock()
-	fake.CreateStub = stub
+func (fake *PolicyStore) ImportWithEventCalls(stub func([]store.Policy, []store.Policy, store.Actor) error) {
+	fake.importWithEventMutex.Lock()
+	defer fake.importWithEventMutex.Unlock()
+	fake.ImportWithEventStub = stub
 }
 
-func (fake *PolicyStore) CreateArgsForCall(i int) []store.Policy {
-	fake.createMutex.RLock()
-	defer fake.createMutex.RUnlock()
-	argsForCall := fake.createArgsForCall[i]
-	return argsForCall.arg1
+func (fake *PolicyStore) ImportWithEventArgsForCall(i int) ([]store.Policy, []store.Policy, store.Actor) {
+	fake.importWithEventMutex.RLock()
+	defer fake.importWithEventMutex.RUnlock()
+	argsForCall := fake.importWithEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *PolicyStore) CreateReturns(result1 error) {
-	fake.createMutex.Lock()
-	defer fake.createMutex.Unlock()
-	fake.CreateStub = nil
-	fake.createReturns = struct {
+func (fake *PolicyStore) ImportWithEventReturns(result1 error) {
+	fake.importWithEventMutex.Lock()
+	defer fake.importWithEventMutex.Unlock()
+	fake.ImportWithEventStub = nil
+	fake.importWithEventReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *PolicyStore) CreateReturnsOnCall(i int, result1 error) {
-	fake.createMutex.Lock()
-	defer fake.createMutex.Unlock()
-	fake.CreateStub = nil
-	if fake.createReturnsOnCall == nil {
-		fake.createReturnsOnCall = make(map[int]struct {
+func (fake *PolicyStore) ImportWithEventReturnsOnCall(i int, result1 error) {
+	fake.importWithEventMutex.Lock()
+	defer fake.importWithEventMutex.Unlock()
+	fake.ImportWithEventStub = nil
+	if fake.importWithEventReturnsOnCall == nil {
+		fake.importWithEventReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.createReturnsOnCall[i] = struct {
+	fake.importWithEventReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *PolicyStore) Delete(arg1 []store.Policy) error {
+func (fake *PolicyStore) MergeWithEvent(arg1 []store.Policy, arg2 []store.Policy, arg3 store.Actor) error {
 	var arg1Copy []store.Policy
 	if arg1 != nil {
 		arg1Copy = make([]store.Policy, len(arg1))
 		copy(arg1Copy, arg1)
 	}
-	fake.deleteMutex.Lock()
-	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
-	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+	var arg2Copy []store.Policy
+	if arg2 != nil {
+		arg2Copy = make([]store.Policy, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.mergeWithEventMutex.Lock()
+	ret, specificReturn := fake.mergeWithEventReturnsOnCall[len(fake.mergeWithEventArgsForCall)]
+	fake.mergeWithEventArgsForCall = append(fake.mergeWithEventArgsForCall, struct {
 		arg1 []store.Policy
-	}{arg1Copy})
-	stub := fake.DeleteStub
-	fakeReturns := fake.deleteReturns
-	fake.recordInvocation("Delete", []interface{}{arg1Copy})
-	fake.deleteMutex.Unlock()
+		arg2 []store.Policy
+		arg3 store.Actor
+	}{arg1Copy, arg2Copy, arg3})
+	stub := fake.MergeWithEventStub
+	fakeReturns := fake.mergeWithEventReturns
+	fake.recordInvocation("MergeWithEvent", []interface{}{arg1Copy, arg2Copy, arg3})
+	fake.mergeWithEventMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *PolicyStore) MergeWithEventCallCount() int {
+	fake.mergeWithEventMutex.RLock()
+	defer fake.mergeWithEventMutex.RUnlock()
+	return len(fake.mergeWithEventArgsForCall)
+}
+
+func (fake *PolicyStore) MergeWithEventCalls(stub func([]store.Policy, []store.Policy, store.Actor) error) {
+	fake.mergeWithEventMutex.Lock()
+	defer fake.mergeWithEventMutex.Unlock()
+	fake.MergeWithEventStub = stub
+}
+
+func (fake *PolicyStore) MergeWithEventArgsForCall(i int) ([]store.Policy, []store.Policy, store.Actor) {
+	fake.mergeWithEventMutex.RLock()
+	defer fake.mergeWithEventMutex.RUnlock()
+	argsForCall := fake.mergeWithEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *PolicyStore) MergeWithEventReturns(result1 error) {
+	fake.mergeWithEventMutex.Lock()
+	defer fake.mergeWithEventMutex.Unlock()
+	fake.MergeWithEventStub = nil
+	fake.mergeWithEventReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PolicyStore) MergeWithEventReturnsOnCall(i int, result1 error) {
+	fake.mergeWithEventMutex.Lock()
+	defer fake.mergeWithEventMutex.Unlock()
+	fake.MergeWithEventStub = nil
+	if fake.mergeWithEventReturnsOnCall == nil {
+		fake.mergeWithEventReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.mergeWithEventReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PolicyStore) ReplaceForSource(arg1 string, arg2 []store.Policy, arg3 []store.PendingPolicy, arg4 store.Actor, arg5 func([]store.Policy) error) error {
+	var arg2Copy []store.Policy
+	if arg2 != nil {
+		arg2Copy = make([]store.Policy, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	var arg3Copy []store.PendingPolicy
+	if arg3 != nil {
+		arg3Copy = make([]store.PendingPolicy, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.replaceForSourceMutex.Lock()
+	ret, specificReturn := fake.replaceForSourceReturnsOnCall[len(fake.replaceForSourceArgsForCall)]
+	fake.replaceForSourceArgsForCall = append(fake.replaceForSourceArgsForCall, struct {
+		arg1 string
+		arg2 []store.Policy
+		arg3 []store.PendingPolicy
+		arg4 store.Actor
+		arg5 func([]store.Policy) error
+	}{arg1, arg2Copy, arg3Copy, arg4, arg5})
+	stub := fake.ReplaceForSourceStub
+	fakeReturns := fake.replaceForSourceReturns
+	fake.recordInvocation("ReplaceForSource", []interface{}{arg1, arg2Copy, arg3Copy, arg4, arg5})
+	fake.replaceForSourceMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1
@@ -215,44 +716,44 @@ func (fake *PolicyStore) Delete(arg1 []store.Policy) error {
 	return fakeReturns.result1
 }
 
-func (fake *PolicyStore) DeleteCallCount() int {
-	fake.deleteMutex.RLock()
-	defer fake.deleteMutex.RUnlock()
-	return len(fake.deleteArgsForCall)
+func (fake *PolicyStore) ReplaceForSourceCallCount() int {
+	fake.replaceForSourceMutex.RLock()
+	defer fake.replaceForSourceMutex.RUnlock()
+	return len(fake.replaceForSourceArgsForCall)
 }
 
-func (fake *PolicyStore) DeleteCalls(stub func([]store.Policy) error) {
-	fake.deleteMutex.Lock()
-	defer fake.deleteMutex.Unlock()
-	fake.DeleteStub = stub
+func (fake *PolicyStore) ReplaceForSourceCalls(stub func(string, []store.Policy, []store.PendingPolicy, store.Actor, func([]store.Policy) error) error) {
+	fake.replaceForSourceMutex.Lock()
+	defer fake.replaceForSourceMutex.Unlock()
+	fake.ReplaceForSourceStub = stub
 }
 
-func (fake *PolicyStore) DeleteArgsForCall(i int) []store.Policy {
-	fake.deleteMutex.RLock()
-	defer fake.deleteMutex.RUnlock()
-	argsForCall := fake.deleteArgsForCall[i]
-	return argsForCall.arg1
+func (fake *PolicyStore) ReplaceForSourceArgsForCall(i int) (string, []store.Policy, []store.PendingPolicy, store.Actor, func([]store.Policy) error) {
+	fake.replaceForSourceMutex.RLock()
+	defer fake.replaceForSourceMutex.RUnlock()
+	argsForCall := fake.replaceForSourceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
-func (fake *PolicyStore) DeleteReturns(result1 error) {
-	fake.deleteMutex.Lock()
-	defer fake.deleteMutex.Unlock()
-	fake.DeleteStub = nil
-	fake.deleteReturns = struct {
+func (fake *PolicyStore) ReplaceForSourceReturns(result1 error) {
+	fake.replaceForSourceMutex.Lock()
+	defer fake.replaceForSourceMutex.Unlock()
+	fake.ReplaceForSourceStub = nil
+	fake.replaceForSourceReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *PolicyStore) DeleteReturnsOnCall(i int, result1 error) {
-	fake.deleteMutex.Lock()
-	defer fake.deleteMutex.Unlock()
-	fake.DeleteStub = nil
-	if fake.deleteReturnsOnCall == nil {
-		fake.deleteReturnsOnCall = make(map[int]struct {
+func (fake *PolicyStore) ReplaceForSourceReturnsOnCall(i int, result1 error) {
+	fake.replaceForSourceMutex.Lock()
+	defer fake.replaceForSourceMutex.Unlock()
+	fake.ReplaceForSourceStub = nil
+	if fake.replaceForSourceReturnsOnCall == nil {
+		fake.replaceForSourceReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.deleteReturnsOnCall[i] = struct {
+	fake.replaceForSourceReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
@@ -260,12 +761,24 @@ func (fake *PolicyStore) DeleteReturnsOnCall(i int, result1 error) {
 func (fake *PolicyStore) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.allMutex.RLock()
+	defer fake.allMutex.RUnlock()
+	fake.approvePendingMutex.RLock()
+	defer fake.approvePendingMutex.RUnlock()
 	fake.byGuidsMutex.RLock()
 	defer fake.byGuidsMutex.RUnlock()
-	fake.createMutex.RLock()
-	defer fake.createMutex.RUnlock()
-	fake.deleteMutex.RLock()
-	defer fake.deleteMutex.RUnlock()
+	fake.createWithEventMutex.RLock()
+	defer fake.createWithEventMutex.RUnlock()
+	fake.createWithPendingMutex.RLock()
+	defer fake.createWithPendingMutex.RUnlock()
+	fake.deleteWithEventMutex.RLock()
+	defer fake.deleteWithEventMutex.RUnlock()
+	fake.importWithEventMutex.RLock()
+	defer fake.importWithEventMutex.RUnlock()
+	fake.mergeWithEventMutex.RLock()
+	defer fake.mergeWithEventMutex.RUnlock()
+	fake.replaceForSourceMutex.RLock()
+	defer fake.replaceForSourceMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,187 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type QuarantineStore struct {
+	QuarantineStub        func(string, store.Actor) error
+	quarantineMutex       sync.RWMutex
+	quarantineArgsForCall []struct {
+		arg1 string
+		arg2 store.Actor
+	}
+	quarantineReturns struct {
+		result1 error
+	}
+	quarantineReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReleaseStub        func(string, store.Actor) error
+	releaseMutex       sync.RWMutex
+	releaseArgsForCall []struct {
+		arg1 string
+		arg2 store.Actor
+	}
+	releaseReturns struct {
+		result1 error
+	}
+	releaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *QuarantineStore) Quarantine(arg1 string, arg2 store.Actor) error {
+	fake.quarantineMutex.Lock()
+	ret, specificReturn := fake.quarantineReturnsOnCall[len(fake.quarantineArgsForCall)]
+	fake.quarantineArgsForCall = append(fake.quarantineArgsForCall, struct {
+		arg1 string
+		arg2 store.Actor
+	}{arg1, arg2})
+	stub := fake.QuarantineStub
+	fakeReturns := fake.quarantineReturns
+	fake.recordInvocation("Quarantine", []interface{}{arg1, arg2})
+	fake.quarantineMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *QuarantineStore) QuarantineCallCount() int {
+	fake.quarantineMutex.RLock()
+	defer fake.quarantineMutex.RUnlock()
+	return len(fake.quarantineArgsForCall)
+}
+
+func (fake *QuarantineStore) QuarantineCalls(stub func(string, store.Actor) error) {
+	fake.quarantineMutex.Lock()
+	defer fake.quarantineMutex.Unlock()
+	fake.QuarantineStub = stub
+}
+
+func (fake *QuarantineStore) QuarantineArgsForCall(i int) (string, store.Actor) {
+	fake.quarantineMutex.RLock()
+	defer fake.quarantineMutex.RUnlock()
+	argsForCall := fake.quarantineArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *QuarantineStore) QuarantineReturns(result1 error) {
+	fake.quarantineMutex.Lock()
+	defer fake.quarantineMutex.Unlock()
+	fake.QuarantineStub = nil
+	fake.quarantineReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *QuarantineStore) QuarantineReturnsOnCall(i int, result1 error) {
+	fake.quarantineMutex.Lock()
+	defer fake.quarantineMutex.Unlock()
+	fake.QuarantineStub = nil
+	if fake.quarantineReturnsOnCall == nil {
+		fake.quarantineReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.quarantineReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *QuarantineStore) Release(arg1 string, arg2 store.Actor) error {
+	fake.releaseMutex.Lock()
+	ret, specificReturn := fake.releaseReturnsOnCall[len(fake.releaseArgsForCall)]
+	fake.releaseArgsForCall = append(fake.releaseArgsForCall, struct {
+		arg1 string
+		arg2 store.Actor
+	}{arg1, arg2})
+	stub := fake.ReleaseStub
+	fakeReturns := fake.releaseReturns
+	fake.recordInvocation("Release", []interface{}{arg1, arg2})
+	fake.releaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *QuarantineStore) ReleaseCallCount() int {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return len(fake.releaseArgsForCall)
+}
+
+func (fake *QuarantineStore) ReleaseCalls(stub func(string, store.Actor) error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = stub
+}
+
+func (fake *QuarantineStore) ReleaseArgsForCall(i int) (string, store.Actor) {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	argsForCall := fake.releaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *QuarantineStore) ReleaseReturns(result1 error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = nil
+	fake.releaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *QuarantineStore) ReleaseReturnsOnCall(i int, result1 error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = nil
+	if fake.releaseReturnsOnCall == nil {
+		fake.releaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *QuarantineStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.quarantineMutex.RLock()
+	defer fake.quarantineMutex.RUnlock()
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *QuarantineStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -23,6 +23,35 @@ type QuotaGuard struct {
 		result1 bool
 		result2 error
 	}
+	CheckReconcileAccessStub        func([]string, []store.Policy) (bool, error)
+	checkReconcileAccessMutex       sync.RWMutex
+	checkReconcileAccessArgsForCall []struct {
+		arg1 []string
+		arg2 []store.Policy
+	}
+	checkReconcileAccessReturns struct {
+		result1 bool
+		result2 error
+	}
+	checkReconcileAccessReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	CheckReplaceAccessStub        func(string, []store.Policy, uaa_client.CheckTokenResponse) (bool, error)
+	checkReplaceAccessMutex       sync.RWMutex
+	checkReplaceAccessArgsForCall []struct {
+		arg1 string
+		arg2 []store.Policy
+		arg3 uaa_client.CheckTokenResponse
+	}
+	checkReplaceAccessReturns struct {
+		result1 bool
+		result2 error
+	}
+	checkReplaceAccessReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -97,11 +126,161 @@ func (fake *QuotaGuard) CheckAccessReturnsOnCall(i int, result1 bool, result2 er
 	}{result1, result2}
 }
 
+func (fake *QuotaGuard) CheckReconcileAccess(arg1 []string, arg2 []store.Policy) (bool, error) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	var arg2Copy []store.Policy
+	if arg2 != nil {
+		arg2Copy = make([]store.Policy, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.checkReconcileAccessMutex.Lock()
+	ret, specificReturn := fake.checkReconcileAccessReturnsOnCall[len(fake.checkReconcileAccessArgsForCall)]
+	fake.checkReconcileAccessArgsForCall = append(fake.checkReconcileAccessArgsForCall, struct {
+		arg1 []string
+		arg2 []store.Policy
+	}{arg1Copy, arg2Copy})
+	stub := fake.CheckReconcileAccessStub
+	fakeReturns := fake.checkReconcileAccessReturns
+	fake.recordInvocation("CheckReconcileAccess", []interface{}{arg1Copy, arg2Copy})
+	fake.checkReconcileAccessMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *QuotaGuard) CheckReconcileAccessCallCount() int {
+	fake.checkReconcileAccessMutex.RLock()
+	defer fake.checkReconcileAccessMutex.RUnlock()
+	return len(fake.checkReconcileAccessArgsForCall)
+}
+
+func (fake *QuotaGuard) CheckReconcileAccessCalls(stub func([]string, []store.Policy) (bool, error)) {
+	fake.checkReconcileAccessMutex.Lock()
+	defer fake.checkReconcileAccessMutex.Unlock()
+	fake.CheckReconcileAccessStub = stub
+}
+
+func (fake *QuotaGuard) CheckReconcileAccessArgsForCall(i int) ([]string, []store.Policy) {
+	fake.checkReconcileAccessMutex.RLock()
+	defer fake.checkReconcileAccessMutex.RUnlock()
+	argsForCall := fake.checkReconcileAccessArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *QuotaGuard) CheckReconcileAccessReturns(result1 bool, result2 error) {
+	fake.checkReconcileAccessMutex.Lock()
+	defer fake.checkReconcileAccessMutex.Unlock()
+	fake.CheckReconcileAccessStub = nil
+	fake.checkReconcileAccessReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QuotaGuard) CheckReconcileAccessReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.checkReconcileAccessMutex.Lock()
+	defer fake.checkReconcileAccessMutex.Unlock()
+	fake.CheckReconcileAccessStub = nil
+	if fake.checkReconcileAccessReturnsOnCall == nil {
+		fake.checkReconcileAccessReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.checkReconcileAccessReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QuotaGuard) CheckReplaceAccess(arg1 string, arg2 []store.Policy, arg3 uaa_client.CheckTokenResponse) (bool, error) {
+	var arg2Copy []store.Policy
+	if arg2 != nil {
+		arg2Copy = make([]store.Policy, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.checkReplaceAccessMutex.Lock()
+	ret, specificReturn := fake.checkReplaceAccessReturnsOnCall[len(fake.checkReplaceAccessArgsForCall)]
+	fake.checkReplaceAccessArgsForCall = append(fake.checkReplaceAccessArgsForCall, struct {
+		arg1 string
+		arg2 []store.Policy
+		arg3 uaa_client.CheckTokenResponse
+	}{arg1, arg2Copy, arg3})
+	stub := fake.CheckReplaceAccessStub
+	fakeReturns := fake.checkReplaceAccessReturns
+	fake.recordInvocation("CheckReplaceAccess", []interface{}{arg1, arg2Copy, arg3})
+	fake.checkReplaceAccessMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *QuotaGuard) CheckReplaceAccessCallCount() int {
+	fake.checkReplaceAccessMutex.RLock()
+	defer fake.checkReplaceAccessMutex.RUnlock()
+	return len(fake.checkReplaceAccessArgsForCall)
+}
+
+func (fake *QuotaGuard) CheckReplaceAccessCalls(stub func(string, []store.Policy, uaa_client.CheckTokenResponse) (bool, error)) {
+	fake.checkReplaceAccessMutex.Lock()
+	defer fake.checkReplaceAccessMutex.Unlock()
+	fake.CheckReplaceAccessStub = stub
+}
+
+func (fake *QuotaGuard) CheckReplaceAccessArgsForCall(i int) (string, []store.Policy, uaa_client.CheckTokenResponse) {
+	fake.checkReplaceAccessMutex.RLock()
+	defer fake.checkReplaceAccessMutex.RUnlock()
+	argsForCall := fake.checkReplaceAccessArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *QuotaGuard) CheckReplaceAccessReturns(result1 bool, result2 error) {
+	fake.checkReplaceAccessMutex.Lock()
+	defer fake.checkReplaceAccessMutex.Unlock()
+	fake.CheckReplaceAccessStub = nil
+	fake.checkReplaceAccessReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QuotaGuard) CheckReplaceAccessReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.checkReplaceAccessMutex.Lock()
+	defer fake.checkReplaceAccessMutex.Unlock()
+	fake.CheckReplaceAccessStub = nil
+	if fake.checkReplaceAccessReturnsOnCall == nil {
+		fake.checkReplaceAccessReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.checkReplaceAccessReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *QuotaGuard) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.checkAccessMutex.RLock()
 	defer fake.checkAccessMutex.RUnlock()
+	fake.checkReconcileAccessMutex.RLock()
+	defer fake.checkReconcileAccessMutex.RUnlock()
+	fake.checkReplaceAccessMutex.RLock()
+	defer fake.checkReplaceAccessMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,370 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type QuotaGuardStore struct {
+	ByGuidsStub        func([]string, []string, bool) ([]store.Policy, error)
+	byGuidsMutex       sync.RWMutex
+	byGuidsArgsForCall []struct {
+		arg1 []string
+		arg2 []string
+		arg3 bool
+	}
+	byGuidsReturns struct {
+		result1 []store.Policy
+		result2 error
+	}
+	byGuidsReturnsOnCall map[int]struct {
+		result1 []store.Policy
+		result2 error
+	}
+	PolicyCountsByScopeStub        func(string, []string, []string) (map[string]int, error)
+	policyCountsByScopeMutex       sync.RWMutex
+	policyCountsByScopeArgsForCall []struct {
+		arg1 string
+		arg2 []string
+		arg3 []string
+	}
+	policyCountsByScopeReturns struct {
+		result1 map[string]int
+		result2 error
+	}
+	policyCountsByScopeReturnsOnCall map[int]struct {
+		result1 map[string]int
+		result2 error
+	}
+	SetSourceScopesStub        func([]store.SourceScope) error
+	setSourceScopesMutex       sync.RWMutex
+	setSourceScopesArgsForCall []struct {
+		arg1 []store.SourceScope
+	}
+	setSourceScopesReturns struct {
+		result1 error
+	}
+	setSourceScopesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UnscopedSourcesStub        func() ([]store.Source, error)
+	unscopedSourcesMutex       sync.RWMutex
+	unscopedSourcesArgsForCall []struct {
+	}
+	unscopedSourcesReturns struct {
+		result1 []store.Source
+		result2 error
+	}
+	unscopedSourcesReturnsOnCall map[int]struct {
+		result1 []store.Source
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *QuotaGuardStore) ByGuids(arg1 []string, arg2 []string, arg3 bool) ([]store.Policy, error) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.byGuidsMutex.Lock()
+	ret, specificReturn := fake.byGuidsReturnsOnCall[len(fake.byGuidsArgsForCall)]
+	fake.byGuidsArgsForCall = append(fake.byGuidsArgsForCall, struct {
+		arg1 []string
+		arg2 []string
+		arg3 bool
+	}{arg1Copy, arg2Copy, arg3})
+	stub := fake.ByGuidsStub
+	fakeReturns := fake.byGuidsReturns
+	fake.recordInvocation("ByGuids", []interface{}{arg1Copy, arg2Copy, arg3})
+	fake.byGuidsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *QuotaGuardStore) ByGuidsCallCount() int {
+	fake.byGuidsMutex.RLock()
+	defer fake.byGuidsMutex.RUnlock()
+	return len(fake.byGuidsArgsForCall)
+}
+
+func (fake *QuotaGuardStore) ByGuidsCalls(stub func([]string, []string, bool) ([]store.Policy, error)) {
+	fake.byGuidsMutex.Lock()
+	defer fake.byGuidsMutex.Unlock()
+	fake.ByGuidsStub = stub
+}
+
+func (fake *QuotaGuardStore) ByGuidsArgsForCall(i int) ([]string, []string, bool) {
+	fake.byGuidsMutex.RLock()
+	defer fake.byGuidsMutex.RUnlock()
+	argsForCall := fake.byGuidsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *QuotaGuardStore) ByGuidsReturns(result1 []store.Policy, result2 error) {
+	fake.byGuidsMutex.Lock()
+	defer fake.byGuidsMutex.Unlock()
+	fake.ByGuidsStub = nil
+	fake.byGuidsReturns = struct {
+		result1 []store.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QuotaGuardStore) ByGuidsReturnsOnCall(i int, result1 []store.Policy, result2 error) {
+	fake.byGuidsMutex.Lock()
+	defer fake.byGuidsMutex.Unlock()
+	fake.ByGuidsStub = nil
+	if fake.byGuidsReturnsOnCall == nil {
+		fake.byGuidsReturnsOnCall = make(map[int]struct {
+			result1 []store.Policy
+			result2 error
+		})
+	}
+	fake.byGuidsReturnsOnCall[i] = struct {
+		result1 []store.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QuotaGuardStore) PolicyCountsByScope(arg1 string, arg2 []string, arg3 []string) (map[string]int, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.policyCountsByScopeMutex.Lock()
+	ret, specificReturn := fake.policyCountsByScopeReturnsOnCall[len(fake.policyCountsByScopeArgsForCall)]
+	fake.policyCountsByScopeArgsForCall = append(fake.policyCountsByScopeArgsForCall, struct {
+		arg1 string
+		arg2 []string
+		arg3 []string
+	}{arg1, arg2Copy, arg3Copy})
+	stub := fake.PolicyCountsByScopeStub
+	fakeReturns := fake.policyCountsByScopeReturns
+	fake.recordInvocation("PolicyCountsByScope", []interface{}{arg1, arg2Copy, arg3Copy})
+	fake.policyCountsByScopeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *QuotaGuardStore) PolicyCountsByScopeCallCount() int {
+	fake.policyCountsByScopeMutex.RLock()
+	defer fake.policyCountsByScopeMutex.RUnlock()
+	return len(fake.policyCountsByScopeArgsForCall)
+}
+
+func (fake *QuotaGuardStore) PolicyCountsByScopeCalls(stub func(string, []string, []string) (map[string]int, error)) {
+	fake.policyCountsByScopeMutex.Lock()
+	defer fake.policyCountsByScopeMutex.Unlock()
+	fake.PolicyCountsByScopeStub = stub
+}
+
+func (fake *QuotaGuardStore) PolicyCountsByScopeArgsForCall(i int) (string, []string, []string) {
+	fake.policyCountsByScopeMutex.RLock()
+	defer fake.policyCountsByScopeMutex.RUnlock()
+	argsForCall := fake.policyCountsByScopeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *QuotaGuardStore) PolicyCountsByScopeReturns(result1 map[string]int, result2 error) {
+	fake.policyCountsByScopeMutex.Lock()
+	defer fake.policyCountsByScopeMutex.Unlock()
+	fake.PolicyCountsByScopeStub = nil
+	fake.policyCountsByScopeReturns = struct {
+		result1 map[string]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QuotaGuardStore) PolicyCountsByScopeReturnsOnCall(i int, result1 map[string]int, result2 error) {
+	fake.policyCountsByScopeMutex.Lock()
+	defer fake.policyCountsByScopeMutex.Unlock()
+	fake.PolicyCountsByScopeStub = nil
+	if fake.policyCountsByScopeReturnsOnCall == nil {
+		fake.policyCountsByScopeReturnsOnCall = make(map[int]struct {
+			result1 map[string]int
+			result2 error
+		})
+	}
+	fake.policyCountsByScopeReturnsOnCall[i] = struct {
+		result1 map[string]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QuotaGuardStore) SetSourceScopes(arg1 []store.SourceScope) error {
+	var arg1Copy []store.SourceScope
+	if arg1 != nil {
+		arg1Copy = make([]store.SourceScope, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.setSourceScopesMutex.Lock()
+	ret, specificReturn := fake.setSourceScopesReturnsOnCall[len(fake.setSourceScopesArgsForCall)]
+	fake.setSourceScopesArgsForCall = append(fake.setSourceScopesArgsForCall, struct {
+		arg1 []store.SourceScope
+	}{arg1Copy})
+	stub := fake.SetSourceScopesStub
+	fakeReturns := fake.setSourceScopesReturns
+	fake.recordInvocation("SetSourceScopes", []interface{}{arg1Copy})
+	fake.setSourceScopesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *QuotaGuardStore) SetSourceScopesCallCount() int {
+	fake.setSourceScopesMutex.RLock()
+	defer fake.setSourceScopesMutex.RUnlock()
+	return len(fake.setSourceScopesArgsForCall)
+}
+
+func (fake *QuotaGuardStore) SetSourceScopesCalls(stub func([]store.SourceScope) error) {
+	fake.setSourceScopesMutex.Lock()
+	defer fake.setSourceScopesMutex.Unlock()
+	fake.SetSourceScopesStub = stub
+}
+
+func (fake *QuotaGuardStore) SetSourceScopesArgsForCall(i int) []store.SourceScope {
+	fake.setSourceScopesMutex.RLock()
+	defer fake.setSourceScopesMutex.RUnlock()
+	argsForCall := fake.setSourceScopesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *QuotaGuardStore) SetSourceScopesReturns(result1 error) {
+	fake.setSourceScopesMutex.Lock()
+	defer fake.setSourceScopesMutex.Unlock()
+	fake.SetSourceScopesStub = nil
+	fake.setSourceScopesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *QuotaGuardStore) SetSourceScopesReturnsOnCall(i int, result1 error) {
+	fake.setSourceScopesMutex.Lock()
+	defer fake.setSourceScopesMutex.Unlock()
+	fake.SetSourceScopesStub = nil
+	if fake.setSourceScopesReturnsOnCall == nil {
+		fake.setSourceScopesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setSourceScopesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *QuotaGuardStore) UnscopedSources() ([]store.Source, error) {
+	fake.unscopedSourcesMutex.Lock()
+	ret, specificReturn := fake.unscopedSourcesReturnsOnCall[len(fake.unscopedSourcesArgsForCall)]
+	fake.unscopedSourcesArgsForCall = append(fake.unscopedSourcesArgsForCall, struct {
+	}{})
+	stub := fake.UnscopedSourcesStub
+	fakeReturns := fake.unscopedSourcesReturns
+	fake.recordInvocation("UnscopedSources", []interface{}{})
+	fake.unscopedSourcesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *QuotaGuardStore) UnscopedSourcesCallCount() int {
+	fake.unscopedSourcesMutex.RLock()
+	defer fake.unscopedSourcesMutex.RUnlock()
+	return len(fake.unscopedSourcesArgsForCall)
+}
+
+func (fake *QuotaGuardStore) UnscopedSourcesCalls(stub func() ([]store.Source, error)) {
+	fake.unscopedSourcesMutex.Lock()
+	defer fake.unscopedSourcesMutex.Unlock()
+	fake.UnscopedSourcesStub = stub
+}
+
+func (fake *QuotaGuardStore) UnscopedSourcesReturns(result1 []store.Source, result2 error) {
+	fake.unscopedSourcesMutex.Lock()
+	defer fake.unscopedSourcesMutex.Unlock()
+	fake.UnscopedSourcesStub = nil
+	fake.unscopedSourcesReturns = struct {
+		result1 []store.Source
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QuotaGuardStore) UnscopedSourcesReturnsOnCall(i int, result1 []store.Source, result2 error) {
+	fake.unscopedSourcesMutex.Lock()
+	defer fake.unscopedSourcesMutex.Unlock()
+	fake.UnscopedSourcesStub = nil
+	if fake.unscopedSourcesReturnsOnCall == nil {
+		fake.unscopedSourcesReturnsOnCall = make(map[int]struct {
+			result1 []store.Source
+			result2 error
+		})
+	}
+	fake.unscopedSourcesReturnsOnCall[i] = struct {
+		result1 []store.Source
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QuotaGuardStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.byGuidsMutex.RLock()
+	defer fake.byGuidsMutex.RUnlock()
+	fake.policyCountsByScopeMutex.RLock()
+	defer fake.policyCountsByScopeMutex.RUnlock()
+	fake.setSourceScopesMutex.RLock()
+	defer fake.setSourceScopesMutex.RUnlock()
+	fake.unscopedSourcesMutex.RLock()
+	defer fake.unscopedSourcesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *QuotaGuardStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,288 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type SpacePoliciesStore struct {
+	ByGuidsStub        func([]string, []string, bool) ([]store.Policy, error)
+	byGuidsMutex       sync.RWMutex
+	byGuidsArgsForCall []struct {
+		arg1 []string
+		arg2 []string
+		arg3 bool
+	}
+	byGuidsReturns struct {
+		result1 []store.Policy
+		result2 error
+	}
+	byGuidsReturnsOnCall map[int]struct {
+		result1 []store.Policy
+		result2 error
+	}
+	LastUpdatedStub        func() (int, error)
+	lastUpdatedMutex       sync.RWMutex
+	lastUpdatedArgsForCall []struct {
+	}
+	lastUpdatedReturns struct {
+		result1 int
+		result2 error
+	}
+	lastUpdatedReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	ReconcileSourcesStub        func([]string, []store.Policy, int, store.Actor) error
+	reconcileSourcesMutex       sync.RWMutex
+	reconcileSourcesArgsForCall []struct {
+		arg1 []string
+		arg2 []store.Policy
+		arg3 int
+		arg4 store.Actor
+	}
+	reconcileSourcesReturns struct {
+		result1 error
+	}
+	reconcileSourcesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *SpacePoliciesStore) ByGuids(arg1 []string, arg2 []string, arg3 bool) ([]store.Policy, error) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.byGuidsMutex.Lock()
+	ret, specificReturn := fake.byGuidsReturnsOnCall[len(fake.byGuidsArgsForCall)]
+	fake.byGuidsArgsForCall = append(fake.byGuidsArgsForCall, struct {
+		arg1 []string
+		arg2 []string
+		arg3 bool
+	}{arg1Copy, arg2Copy, arg3})
+	stub := fake.ByGuidsStub
+	fakeReturns := fake.byGuidsReturns
+	fake.recordInvocation("ByGuids", []interface{}{arg1Copy, arg2Copy, arg3})
+	fake.byGuidsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SpacePoliciesStore) ByGuidsCallCount() int {
+	fake.byGuidsMutex.RLock()
+	defer fake.byGuidsMutex.RUnlock()
+	return len(fake.byGuidsArgsForCall)
+}
+
+func (fake *SpacePoliciesStore) ByGuidsCalls(stub func([]string, []string, bool) ([]store.Policy, error)) {
+	fake.byGuidsMutex.Lock()
+	defer fake.byGuidsMutex.Unlock()
+	fake.ByGuidsStub = stub
+}
+
+func (fake *SpacePoliciesStore) ByGuidsArgsForCall(i int) ([]string, []string, bool) {
+	fake.byGuidsMutex.RLock()
+	defer fake.byGuidsMutex.RUnlock()
+	argsForCall := fake.byGuidsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *SpacePoliciesStore) ByGuidsReturns(result1 []store.Policy, result2 error) {
+	fake.byGuidsMutex.Lock()
+	defer fake.byGuidsMutex.Unlock()
+	fake.ByGuidsStub = nil
+	fake.byGuidsReturns = struct {
+		result1 []store.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SpacePoliciesStore) ByGuidsReturnsOnCall(i int, result1 []store.Policy, result2 error) {
+	fake.byGuidsMutex.Lock()
+	defer fake.byGuidsMutex.Unlock()
+	fake.ByGuidsStub = nil
+	if fake.byGuidsReturnsOnCall == nil {
+		fake.byGuidsReturnsOnCall = make(map[int]struct {
+			result1 []store.Policy
+			result2 error
+		})
+	}
+	fake.byGuidsReturnsOnCall[i] = struct {
+		result1 []store.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SpacePoliciesStore) LastUpdated() (int, error) {
+	fake.lastUpdatedMutex.Lock()
+	ret, specificReturn := fake.lastUpdatedReturnsOnCall[len(fake.lastUpdatedArgsForCall)]
+	fake.lastUpdatedArgsForCall = append(fake.lastUpdatedArgsForCall, struct {
+	}{})
+	stub := fake.LastUpdatedStub
+	fakeReturns := fake.lastUpdatedReturns
+	fake.recordInvocation("LastUpdated", []interface{}{})
+	fake.lastUpdatedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SpacePoliciesStore) LastUpdatedCallCount() int {
+	fake.lastUpdatedMutex.RLock()
+	defer fake.lastUpdatedMutex.RUnlock()
+	return len(fake.lastUpdatedArgsForCall)
+}
+
+func (fake *SpacePoliciesStore) LastUpdatedCalls(stub func() (int, error)) {
+	fake.lastUpdatedMutex.Lock()
+	defer fake.lastUpdatedMutex.Unlock()
+	fake.LastUpdatedStub = stub
+}
+
+func (fake *SpacePoliciesStore) LastUpdatedReturns(result1 int, result2 error) {
+	fake.lastUpdatedMutex.Lock()
+	defer fake.lastUpdatedMutex.Unlock()
+	fake.LastUpdatedStub = nil
+	fake.lastUpdatedReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SpacePoliciesStore) LastUpdatedReturnsOnCall(i int, result1 int, result2 error) {
+	fake.lastUpdatedMutex.Lock()
+	defer fake.lastUpdatedMutex.Unlock()
+	fake.LastUpdatedStub = nil
+	if fake.lastUpdatedReturnsOnCall == nil {
+		fake.lastUpdatedReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.lastUpdatedReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SpacePoliciesStore) ReconcileSources(arg1 []string, arg2 []store.Policy, arg3 int, arg4 store.Actor) error {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	var arg2Copy []store.Policy
+	if arg2 != nil {
+		arg2Copy = make([]store.Policy, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.reconcileSourcesMutex.Lock()
+	ret, specificReturn := fake.reconcileSourcesReturnsOnCall[len(fake.reconcileSourcesArgsForCall)]
+	fake.reconcileSourcesArgsForCall = append(fake.reconcileSourcesArgsForCall, struct {
+		arg1 []string
+		arg2 []store.Policy
+		arg3 int
+		arg4 store.Actor
+	}{arg1Copy, arg2Copy, arg3, arg4})
+	stub := fake.ReconcileSourcesStub
+	fakeReturns := fake.reconcileSourcesReturns
+	fake.recordInvocation("ReconcileSources", []interface{}{arg1Copy, arg2Copy, arg3, arg4})
+	fake.reconcileSourcesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *SpacePoliciesStore) ReconcileSourcesCallCount() int {
+	fake.reconcileSourcesMutex.RLock()
+	defer fake.reconcileSourcesMutex.RUnlock()
+	return len(fake.reconcileSourcesArgsForCall)
+}
+
+func (fake *SpacePoliciesStore) ReconcileSourcesCalls(stub func([]string, []store.Policy, int, store.Actor) error) {
+	fake.reconcileSourcesMutex.Lock()
+	defer fake.reconcileSourcesMutex.Unlock()
+	fake.ReconcileSourcesStub = stub
+}
+
+func (fake *SpacePoliciesStore) ReconcileSourcesArgsForCall(i int) ([]string, []store.Policy, int, store.Actor) {
+	fake.reconcileSourcesMutex.RLock()
+	defer fake.reconcileSourcesMutex.RUnlock()
+	argsForCall := fake.reconcileSourcesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *SpacePoliciesStore) ReconcileSourcesReturns(result1 error) {
+	fake.reconcileSourcesMutex.Lock()
+	defer fake.reconcileSourcesMutex.Unlock()
+	fake.ReconcileSourcesStub = nil
+	fake.reconcileSourcesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *SpacePoliciesStore) ReconcileSourcesReturnsOnCall(i int, result1 error) {
+	fake.reconcileSourcesMutex.Lock()
+	defer fake.reconcileSourcesMutex.Unlock()
+	fake.ReconcileSourcesStub = nil
+	if fake.reconcileSourcesReturnsOnCall == nil {
+		fake.reconcileSourcesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.reconcileSourcesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *SpacePoliciesStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.byGuidsMutex.RLock()
+	defer fake.byGuidsMutex.RUnlock()
+	fake.lastUpdatedMutex.RLock()
+	defer fake.lastUpdatedMutex.RUnlock()
+	fake.reconcileSourcesMutex.RLock()
+	defer fake.reconcileSourcesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *SpacePoliciesStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
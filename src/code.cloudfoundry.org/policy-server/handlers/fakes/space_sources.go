@@ -0,0 +1,117 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+)
+
+type SpaceSources struct {
+	SourceGuidsStub        func(string) ([]string, bool, error)
+	sourceGuidsMutex       sync.RWMutex
+	sourceGuidsArgsForCall []struct {
+		arg1 string
+	}
+	sourceGuidsReturns struct {
+		result1 []string
+		result2 bool
+		result3 error
+	}
+	sourceGuidsReturnsOnCall map[int]struct {
+		result1 []string
+		result2 bool
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *SpaceSources) SourceGuids(arg1 string) ([]string, bool, error) {
+	fake.sourceGuidsMutex.Lock()
+	ret, specificReturn := fake.sourceGuidsReturnsOnCall[len(fake.sourceGuidsArgsForCall)]
+	fake.sourceGuidsArgsForCall = append(fake.sourceGuidsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SourceGuidsStub
+	fakeReturns := fake.sourceGuidsReturns
+	fake.recordInvocation("SourceGuids", []interface{}{arg1})
+	fake.sourceGuidsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *SpaceSources) SourceGuidsCallCount() int {
+	fake.sourceGuidsMutex.RLock()
+	defer fake.sourceGuidsMutex.RUnlock()
+	return len(fake.sourceGuidsArgsForCall)
+}
+
+func (fake *SpaceSources) SourceGuidsCalls(stub func(string) ([]string, bool, error)) {
+	fake.sourceGuidsMutex.Lock()
+	defer fake.sourceGuidsMutex.Unlock()
+	fake.SourceGuidsStub = stub
+}
+
+func (fake *SpaceSources) SourceGuidsArgsForCall(i int) string {
+	fake.sourceGuidsMutex.RLock()
+	defer fake.sourceGuidsMutex.RUnlock()
+	argsForCall := fake.sourceGuidsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *SpaceSources) SourceGuidsReturns(result1 []string, result2 bool, result3 error) {
+	fake.sourceGuidsMutex.Lock()
+	defer fake.sourceGuidsMutex.Unlock()
+	fake.SourceGuidsStub = nil
+	fake.sourceGuidsReturns = struct {
+		result1 []string
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *SpaceSources) SourceGuidsReturnsOnCall(i int, result1 []string, result2 bool, result3 error) {
+	fake.sourceGuidsMutex.Lock()
+	defer fake.sourceGuidsMutex.Unlock()
+	fake.SourceGuidsStub = nil
+	if fake.sourceGuidsReturnsOnCall == nil {
+		fake.sourceGuidsReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 bool
+			result3 error
+		})
+	}
+	fake.sourceGuidsReturnsOnCall[i] = struct {
+		result1 []string
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *SpaceSources) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.sourceGuidsMutex.RLock()
+	defer fake.sourceGuidsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *SpaceSources) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
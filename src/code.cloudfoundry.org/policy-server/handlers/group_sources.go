@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type groupMembersStore interface {
+	GroupMembers([]string) ([]store.GroupMember, error)
+}
+
+// expandGroupSources replaces each policy from a space or org with a policy
+// from each of its apps, as policy agents only know about apps. A space or org
+// without apps yet has no policies on the internal API.
+func expandGroupSources(membersStore groupMembersStore, policies []store.Policy) ([]store.Policy, error) {
+	groupGuids := []string{}
+	seen := map[string]bool{}
+	for _, policy := range policies {
+		if policy.Source.Type != "" && !seen[policy.Source.ID] {
+			seen[policy.Source.ID] = true
+			groupGuids = append(groupGuids, policy.Source.ID)
+		}
+	}
+	if len(groupGuids) == 0 {
+		return policies, nil
+	}
+
+	members, err := membersStore.GroupMembers(groupGuids)
+	if err != nil {
+		return nil, err
+	}
+	membersByGroup := map[string][]store.GroupMember{}
+	for _, member := range members {
+		membersByGroup[member.GroupGUID] = append(membersByGroup[member.GroupGUID], member)
+	}
+
+	expanded := []store.Policy{}
+	for _, policy := range policies {
+		if policy.Source.Type == "" {
+			expanded = append(expanded, policy)
+			continue
+		}
+		for _, member := range membersByGroup[policy.Source.ID] {
+			memberPolicy := policy
+			memberPolicy.Source = store.Source{ID: member.AppGUID, Tag: member.AppTag}
+			expanded = append(expanded, memberPolicy)
+		}
+	}
+	return expanded, nil
+}
+
+// policiesOfApps keeps the policies from or to any of the apps
+func policiesOfApps(policies []store.Policy, appGuids []string) []store.Policy {
+	apps := map[string]bool{}
+	for _, guid := range appGuids {
+		apps[guid] = true
+	}
+
+	result := []store.Policy{}
+	for _, policy := range policies {
+		if apps[policy.Source.ID] || apps[policy.Destination.ID] {
+			result = append(result, policy)
+		}
+	}
+	return result
+}
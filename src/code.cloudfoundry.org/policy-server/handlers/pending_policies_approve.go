@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type PendingPoliciesApprove struct {
+	Store         policyStore
+	PendingStore  store.PendingPoliciesStore
+	Consent       policyConsent
+	QuotaGuard    quotaGuard
+	RataAdapter   rataAdapter
+	ErrorResponse errorResponse
+}
+
+func NewPendingPoliciesApprove(store policyStore, pendingStore store.PendingPoliciesStore, consent policyConsent,
+	quotaGuard quotaGuard, rataAdapter rataAdapter, errorResponse errorResponse) *PendingPoliciesApprove {
+	return &PendingPoliciesApprove{
+		Store:         store,
+		PendingStore:  pendingStore,
+		Consent:       consent,
+		QuotaGuard:    quotaGuard,
+		RataAdapter:   rataAdapter,
+		ErrorResponse: errorResponse,
+	}
+}
+
+// ServeHTTP creates a pending policy on behalf of the subject who requested it.
+// It must be approved by someone else than that subject.
+func (h *PendingPoliciesApprove) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("approve-pending-policy")
+	tokenData := getTokenData(req)
+
+	pending, ok := findPendingPolicy(logger, w, req, h.PendingStore, h.RataAdapter, h.ErrorResponse)
+	if !ok {
+		return
+	}
+
+	approve, err := h.Consent.CanApprove(pending.DestinationSpaceGUID, tokenData)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "check consent failed")
+		return
+	}
+	if !approve || pending.RequestedBy == getActor(tokenData).Name {
+		err := errors.New("pending policy cannot be approved by the subject")
+		h.ErrorResponse.Forbidden(logger, w, err, err.Error())
+		return
+	}
+
+	policies := []store.Policy{pending.Policy}
+	authorized, err := h.QuotaGuard.CheckAccess(policies, tokenData)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "check quota failed")
+		return
+	}
+	if !authorized {
+		err := errors.New("policy quota exceeded")
+		h.ErrorResponse.Forbidden(logger, w, err, err.Error())
+		return
+	}
+
+	err = h.Store.ApprovePending(*pending, getActor(tokenData))
+	if err == store.ErrPendingPolicyNotFound {
+		h.ErrorResponse.NotFound(logger, w, err, err.Error())
+		return
+	}
+	var deniedErr *store.DeniedPoliciesError
+	if errors.As(err, &deniedErr) {
+		h.ErrorResponse.Conflict(logger, w, err, deniedReason)
+		return
+	}
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database create failed")
+		return
+	}
+
+	logger.Info("approved-pending-policy", lager.Data{
+		"id":          pending.ID,
+		"policy":      pending.Policy,
+		"requestedBy": pending.RequestedBy,
+		"userName":    tokenData.UserName,
+	})
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write([]byte(`{}`))
+}
+
+// findPendingPolicy responds with an error and returns false when the pending
+// policy of the request cannot be found
+func findPendingPolicy(logger lager.Logger, w http.ResponseWriter, req *http.Request, pendingStore store.PendingPoliciesStore,
+	rataAdapter rataAdapter, errorResponse errorResponse) (*store.PendingPolicy, bool) {
+	id, err := strconv.Atoi(rataAdapter.Param(req, "id"))
+	if err != nil {
+		errorResponse.BadRequest(logger, w, err, "invalid pending policy id")
+		return nil, false
+	}
+
+	pending, err := pendingStore.PendingPolicy(id)
+	if err != nil {
+		errorResponse.InternalServerError(logger, w, err, "database read failed")
+		return nil, false
+	}
+	if pending == nil {
+		err := errors.New("pending policy not found")
+		errorResponse.NotFound(logger, w, err, err.Error())
+		return nil, false
+	}
+	return pending, true
+}
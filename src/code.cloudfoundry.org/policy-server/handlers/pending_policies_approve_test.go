@@ -0,0 +1,283 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	storeFakes "code.cloudfoundry.org/policy-server/store/fakes"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pending policies approve handler", func() {
+	var (
+		id                string
+		request           *http.Request
+		handler           *handlers.PendingPoliciesApprove
+		resp              *httptest.ResponseRecorder
+		fakeStore         *fakes.PolicyStore
+		fakePendingStore  *storeFakes.PendingPoliciesStore
+		fakeConsent       *fakes.PolicyConsent
+		fakeQuotaGuard    *fakes.QuotaGuard
+		fakeRataAdapter   *fakes.RataAdapter
+		fakeErrorResponse *fakes.ErrorResponse
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		tokenData         uaa_client.CheckTokenResponse
+		pending           store.PendingPolicy
+	)
+
+	BeforeEach(func() {
+		id = "7"
+
+		var err error
+		request, err = http.NewRequest("POST", "/networking/v1/external/policies/pending/7/approve", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		pending = store.PendingPolicy{
+			ID: 7,
+			Policy: store.Policy{
+				Source:      store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{ID: "some-other-app-guid", Protocol: "tcp"},
+			},
+			DestinationSpaceGUID: "some-space-guid",
+			RequestedBy:          "some-developer",
+		}
+
+		fakeStore = &fakes.PolicyStore{}
+		fakePendingStore = &storeFakes.PendingPoliciesStore{}
+		fakePendingStore.PendingPolicyReturns(&pending, nil)
+		fakeConsent = &fakes.PolicyConsent{}
+		fakeConsent.CanApproveReturns(true, nil)
+		fakeQuotaGuard = &fakes.QuotaGuard{}
+		fakeQuotaGuard.CheckAccessReturns(true, nil)
+		fakeRataAdapter = &fakes.RataAdapter{}
+		fakeRataAdapter.ParamStub = func(req *http.Request, name string) string {
+			return id
+		}
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("approve-pending-policy")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		handler = handlers.NewPendingPoliciesApprove(fakeStore, fakePendingStore, fakeConsent,
+			fakeQuotaGuard, fakeRataAdapter, fakeErrorResponse)
+		tokenData = uaa_client.CheckTokenResponse{
+			Scope:    []string{"network.write"},
+			UserName: "some-approver",
+			ClientID: "some-client",
+		}
+		resp = httptest.NewRecorder()
+	})
+
+	It("creates the policy and deletes the pending policy", func() {
+		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+		_, name := fakeRataAdapter.ParamArgsForCall(0)
+		Expect(name).To(Equal("id"))
+		Expect(fakePendingStore.PendingPolicyArgsForCall(0)).To(Equal(7))
+
+		spaceGUID, token := fakeConsent.CanApproveArgsForCall(0)
+		Expect(spaceGUID).To(Equal("some-space-guid"))
+		Expect(token).To(Equal(tokenData))
+
+		quotaPolicies, _ := fakeQuotaGuard.CheckAccessArgsForCall(0)
+		Expect(quotaPolicies).To(Equal([]store.Policy{pending.Policy}))
+
+		Expect(fakeStore.ApprovePendingCallCount()).To(Equal(1))
+		approved, approver := fakeStore.ApprovePendingArgsForCall(0)
+		Expect(approved).To(Equal(pending))
+		Expect(approver).To(Equal(store.Actor{Name: "some-approver", ClientID: "some-client"}))
+
+		Expect(fakeStore.CreateWithEventCallCount()).To(Equal(0))
+		Expect(fakePendingStore.DeletePendingCallCount()).To(Equal(0))
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{}`))
+	})
+
+	Context("when the id is invalid", func() {
+		BeforeEach(func() {
+			id = "banana"
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakePendingStore.PendingPolicyCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+
+			l, w, _, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(description).To(Equal("invalid pending policy id"))
+		})
+	})
+
+	Context("when the pending policy does not exist", func() {
+		BeforeEach(func() {
+			fakePendingStore.PendingPolicyReturns(nil, nil)
+		})
+
+		It("calls the not found handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.NotFoundCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.NotFoundArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("pending policy not found"))
+			Expect(description).To(Equal("pending policy not found"))
+		})
+	})
+
+	Context("when reading the pending policy fails", func() {
+		BeforeEach(func() {
+			fakePendingStore.PendingPolicyReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database read failed"))
+		})
+	})
+
+	Context("when the subject may not approve the pending policy", func() {
+		BeforeEach(func() {
+			fakeConsent.CanApproveReturns(false, nil)
+		})
+
+		It("calls the forbidden handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.ApprovePendingCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.ForbiddenCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.ForbiddenArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("pending policy cannot be approved by the subject"))
+			Expect(description).To(Equal("pending policy cannot be approved by the subject"))
+		})
+	})
+
+	Context("when the subject requested the pending policy", func() {
+		BeforeEach(func() {
+			tokenData.UserName = "some-developer"
+		})
+
+		It("calls the forbidden handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.ApprovePendingCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.ForbiddenCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when checking consent fails", func() {
+		BeforeEach(func() {
+			fakeConsent.CanApproveReturns(false, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("check consent failed"))
+		})
+	})
+
+	Context("when the quota would be exceeded", func() {
+		BeforeEach(func() {
+			fakeQuotaGuard.CheckAccessReturns(false, nil)
+		})
+
+		It("calls the forbidden handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.ApprovePendingCallCount()).To(Equal(0))
+
+			_, _, err, description := fakeErrorResponse.ForbiddenArgsForCall(0)
+			Expect(err).To(MatchError("policy quota exceeded"))
+			Expect(description).To(Equal("policy quota exceeded"))
+		})
+	})
+
+	Context("when checking the quota fails", func() {
+		BeforeEach(func() {
+			fakeQuotaGuard.CheckAccessReturns(false, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("check quota failed"))
+		})
+	})
+
+	Context("when the pending policy was approved or removed in the meantime", func() {
+		BeforeEach(func() {
+			fakeStore.ApprovePendingReturns(store.ErrPendingPolicyNotFound)
+		})
+
+		It("calls the not found handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.NotFoundCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.NotFoundArgsForCall(0)
+			Expect(err).To(Equal(store.ErrPendingPolicyNotFound))
+			Expect(description).To(Equal("pending policy not found"))
+		})
+	})
+
+	Context("when the policy already exists as a deny policy", func() {
+		var deniedErr *store.DeniedPoliciesError
+
+		BeforeEach(func() {
+			deniedErr = &store.DeniedPoliciesError{Policies: []store.Policy{pending.Policy}}
+			fakeStore.ApprovePendingReturns(deniedErr)
+		})
+
+		It("calls the conflict handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.ConflictCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.ConflictArgsForCall(0)
+			Expect(err).To(Equal(deniedErr))
+			Expect(description).To(Equal("one or more policies already exist as deny policies, delete them to allow the traffic"))
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when approving the pending policy fails", func() {
+		BeforeEach(func() {
+			fakeStore.ApprovePendingReturns(errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database create failed"))
+		})
+	})
+})
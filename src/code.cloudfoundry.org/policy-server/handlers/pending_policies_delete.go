@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+)
+
+type PendingPoliciesDelete struct {
+	PendingStore  store.PendingPoliciesStore
+	Consent       policyConsent
+	RataAdapter   rataAdapter
+	ErrorResponse errorResponse
+}
+
+func NewPendingPoliciesDelete(pendingStore store.PendingPoliciesStore, consent policyConsent,
+	rataAdapter rataAdapter, errorResponse errorResponse) *PendingPoliciesDelete {
+	return &PendingPoliciesDelete{
+		PendingStore:  pendingStore,
+		Consent:       consent,
+		RataAdapter:   rataAdapter,
+		ErrorResponse: errorResponse,
+	}
+}
+
+// ServeHTTP rejects a pending policy, or withdraws it when the subject
+// requested it
+func (h *PendingPoliciesDelete) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("delete-pending-policy")
+	tokenData := getTokenData(req)
+
+	pending, ok := findPendingPolicy(logger, w, req, h.PendingStore, h.RataAdapter, h.ErrorResponse)
+	if !ok {
+		return
+	}
+
+	reject, err := canRejectPendingPolicy(h.Consent, pending, tokenData)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "check consent failed")
+		return
+	}
+	if !reject {
+		err := errors.New("pending policy cannot be rejected by the subject")
+		h.ErrorResponse.Forbidden(logger, w, err, err.Error())
+		return
+	}
+
+	err = h.PendingStore.DeletePending(pending.ID)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database delete failed")
+		return
+	}
+
+	logger.Info("deleted-pending-policy", lager.Data{
+		"id":          pending.ID,
+		"policy":      pending.Policy,
+		"requestedBy": pending.RequestedBy,
+		"userName":    tokenData.UserName,
+	})
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write([]byte(`{}`))
+}
+
+// canRejectPendingPolicy returns whether the subject may reject the pending
+// policy, which the subject who requested it may always do
+func canRejectPendingPolicy(consent policyConsent, pending *store.PendingPolicy, tokenData uaa_client.CheckTokenResponse) (bool, error) {
+	if pending.RequestedBy == getActor(tokenData).Name {
+		return true, nil
+	}
+	return consent.CanApprove(pending.DestinationSpaceGUID, tokenData)
+}
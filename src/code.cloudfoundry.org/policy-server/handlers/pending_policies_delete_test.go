@@ -0,0 +1,152 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	storeFakes "code.cloudfoundry.org/policy-server/store/fakes"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pending policies delete handler", func() {
+	var (
+		request           *http.Request
+		handler           *handlers.PendingPoliciesDelete
+		resp              *httptest.ResponseRecorder
+		fakePendingStore  *storeFakes.PendingPoliciesStore
+		fakeConsent       *fakes.PolicyConsent
+		fakeRataAdapter   *fakes.RataAdapter
+		fakeErrorResponse *fakes.ErrorResponse
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		tokenData         uaa_client.CheckTokenResponse
+	)
+
+	BeforeEach(func() {
+		var err error
+		request, err = http.NewRequest("DELETE", "/networking/v1/external/policies/pending/7", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		fakePendingStore = &storeFakes.PendingPoliciesStore{}
+		fakePendingStore.PendingPolicyReturns(&store.PendingPolicy{
+			ID:                   7,
+			DestinationSpaceGUID: "some-space-guid",
+			RequestedBy:          "some-developer",
+		}, nil)
+		fakeConsent = &fakes.PolicyConsent{}
+		fakeConsent.CanApproveReturns(true, nil)
+		fakeRataAdapter = &fakes.RataAdapter{}
+		fakeRataAdapter.ParamReturns("7")
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("delete-pending-policy")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		handler = handlers.NewPendingPoliciesDelete(fakePendingStore, fakeConsent, fakeRataAdapter, fakeErrorResponse)
+		tokenData = uaa_client.CheckTokenResponse{
+			Scope:    []string{"network.write"},
+			UserName: "some-approver",
+		}
+		resp = httptest.NewRecorder()
+	})
+
+	It("rejects the pending policy", func() {
+		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+		spaceGUID, token := fakeConsent.CanApproveArgsForCall(0)
+		Expect(spaceGUID).To(Equal("some-space-guid"))
+		Expect(token).To(Equal(tokenData))
+
+		Expect(fakePendingStore.DeletePendingCallCount()).To(Equal(1))
+		Expect(fakePendingStore.DeletePendingArgsForCall(0)).To(Equal(7))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{}`))
+	})
+
+	Context("when the subject requested the pending policy", func() {
+		BeforeEach(func() {
+			tokenData.UserName = "some-developer"
+			fakeConsent.CanApproveReturns(false, nil)
+		})
+
+		It("withdraws the pending policy", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeConsent.CanApproveCallCount()).To(Equal(0))
+			Expect(fakePendingStore.DeletePendingCallCount()).To(Equal(1))
+			Expect(resp.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("when the pending policy does not exist", func() {
+		BeforeEach(func() {
+			fakePendingStore.PendingPolicyReturns(nil, nil)
+		})
+
+		It("calls the not found handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakePendingStore.DeletePendingCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.NotFoundCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the subject may not reject the pending policy", func() {
+		BeforeEach(func() {
+			fakeConsent.CanApproveReturns(false, nil)
+		})
+
+		It("calls the forbidden handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakePendingStore.DeletePendingCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.ForbiddenCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.ForbiddenArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("pending policy cannot be rejected by the subject"))
+			Expect(description).To(Equal("pending policy cannot be rejected by the subject"))
+		})
+	})
+
+	Context("when checking consent fails", func() {
+		BeforeEach(func() {
+			fakeConsent.CanApproveReturns(false, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("check consent failed"))
+		})
+	})
+
+	Context("when deleting the pending policy fails", func() {
+		BeforeEach(func() {
+			fakePendingStore.DeletePendingReturns(errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database delete failed"))
+		})
+	})
+})
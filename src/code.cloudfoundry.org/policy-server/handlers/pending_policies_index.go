@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/cf-networking-helpers/marshal"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type PendingPoliciesIndex struct {
+	Store         store.PendingPoliciesStore
+	Consent       policyConsent
+	Marshaler     marshal.Marshaler
+	ErrorResponse errorResponse
+}
+
+func NewPendingPoliciesIndex(store store.PendingPoliciesStore, consent policyConsent,
+	marshaler marshal.Marshaler, errorResponse errorResponse) *PendingPoliciesIndex {
+	return &PendingPoliciesIndex{
+		Store:         store,
+		Consent:       consent,
+		Marshaler:     marshaler,
+		ErrorResponse: errorResponse,
+	}
+}
+
+// ServeHTTP lists the pending policies that the subject may approve or that
+// they requested
+func (h *PendingPoliciesIndex) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("index-pending-policies")
+	tokenData := getTokenData(req)
+
+	pending, err := h.Store.PendingPolicies()
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+		return
+	}
+
+	pending, err = h.Consent.Approvable(pending, tokenData)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "check consent failed")
+		return
+	}
+
+	responseBytes, err := h.Marshaler.Marshal(api.PendingPoliciesPayload{
+		TotalPendingPolicies: len(pending),
+		PendingPolicies:      api.MapStorePendingPolicies(pending),
+	})
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database marshalling failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write(responseBytes)
+}
@@ -0,0 +1,161 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	hfakes "code.cloudfoundry.org/cf-networking-helpers/fakes"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	storeFakes "code.cloudfoundry.org/policy-server/store/fakes"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pending policies index handler", func() {
+	var (
+		request           *http.Request
+		handler           *handlers.PendingPoliciesIndex
+		resp              *httptest.ResponseRecorder
+		fakeStore         *storeFakes.PendingPoliciesStore
+		fakeConsent       *fakes.PolicyConsent
+		fakeErrorResponse *fakes.ErrorResponse
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		marshaler         *hfakes.Marshaler
+		tokenData         uaa_client.CheckTokenResponse
+		pending           []store.PendingPolicy
+	)
+
+	BeforeEach(func() {
+		var err error
+		request, err = http.NewRequest("GET", "/networking/v1/external/policies/pending", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		marshaler = &hfakes.Marshaler{}
+		marshaler.MarshalStub = json.Marshal
+
+		pending = []store.PendingPolicy{
+			{
+				ID: 1,
+				Policy: store.Policy{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "tcp",
+						Ports:    store.Ports{Start: 8080, End: 8080},
+					},
+				},
+				DestinationSpaceGUID: "some-space-guid",
+				RequestedBy:          "some-developer",
+				CreatedAt:            time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+			{ID: 2, DestinationSpaceGUID: "another-space-guid"},
+		}
+		fakeStore = &storeFakes.PendingPoliciesStore{}
+		fakeStore.PendingPoliciesReturns(pending, nil)
+		fakeConsent = &fakes.PolicyConsent{}
+		fakeConsent.ApprovableReturns(pending[:1], nil)
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("index-pending-policies")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		handler = handlers.NewPendingPoliciesIndex(fakeStore, fakeConsent, marshaler, fakeErrorResponse)
+		tokenData = uaa_client.CheckTokenResponse{
+			Scope:    []string{"network.write"},
+			UserName: "some-approver",
+		}
+		resp = httptest.NewRecorder()
+	})
+
+	It("returns the pending policies that the subject may see", func() {
+		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+		Expect(fakeConsent.ApprovableCallCount()).To(Equal(1))
+		allPending, token := fakeConsent.ApprovableArgsForCall(0)
+		Expect(allPending).To(Equal(pending))
+		Expect(token).To(Equal(tokenData))
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{
+			"total_pending_policies": 1,
+			"pending_policies": [{
+				"id": 1,
+				"policy": {
+					"source": { "id": "some-app-guid" },
+					"destination": {
+						"id": "some-other-app-guid",
+						"protocol": "tcp",
+						"ports": { "start": 8080, "end": 8080 }
+					}
+				},
+				"destination_space_guid": "some-space-guid",
+				"requested_by": "some-developer",
+				"timestamp": "2026-01-02T03:04:05Z"
+			}]
+		}`))
+	})
+
+	Context("when the store throws an error", func() {
+		BeforeEach(func() {
+			fakeStore.PendingPoliciesReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database read failed"))
+		})
+	})
+
+	Context("when checking consent fails", func() {
+		BeforeEach(func() {
+			fakeConsent.ApprovableReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("check consent failed"))
+		})
+	})
+
+	Context("when marshalling fails", func() {
+		BeforeEach(func() {
+			marshaler.MarshalStub = nil
+			marshaler.MarshalReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database marshalling failed"))
+		})
+	})
+})
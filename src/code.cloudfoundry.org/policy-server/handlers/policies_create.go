@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 
+	"code.cloudfoundry.org/cf-networking-helpers/httperror"
 	"code.cloudfoundry.org/lager/v3"
 	"code.cloudfoundry.org/policy-server/api"
 	"code.cloudfoundry.org/policy-server/store"
@@ -21,30 +23,43 @@ type policyGuard interface {
 //counterfeiter:generate -o fakes/quota_guard.go --fake-name QuotaGuard . quotaGuard
 type quotaGuard interface {
 	CheckAccess(policies []store.Policy, tokenData uaa_client.CheckTokenResponse) (bool, error)
+	CheckReplaceAccess(sourceGuid string, policies []store.Policy, tokenData uaa_client.CheckTokenResponse) (bool, error)
+	CheckReconcileAccess(sourceGuids []string, policies []store.Policy) (bool, error)
 }
 
 //counterfeiter:generate -o fakes/policy_store.go --fake-name PolicyStore . policyStore
 type policyStore interface {
-	Create([]store.Policy) error
-	Delete([]store.Policy) error
+	CreateWithEvent(policies []store.Policy, actor store.Actor) error
+	DeleteWithEvent(policies []store.Policy, actor store.Actor) error
+	ReplaceForSource(sourceGuid string, policies []store.Policy, pending []store.PendingPolicy, actor store.Actor, checkExisting func([]store.Policy) error) error
+	MergeWithEvent(created []store.Policy, deleted []store.Policy, actor store.Actor) error
+	CreateWithPending(created []store.Policy, replaced []store.Policy, pending []store.PendingPolicy, actor store.Actor) error
+	ApprovePending(pending store.PendingPolicy, approver store.Actor) error
+	ImportWithEvent(created []store.Policy, updated []store.Policy, actor store.Actor) error
 	ByGuids(srcGuids []string, dstGuids []string, srcAndDst bool) ([]store.Policy, error)
+	All() ([]store.Policy, error)
 }
 
+const deniedReason = "one or more policies already exist as deny policies, delete them to allow the traffic"
+
 type PoliciesCreate struct {
 	Store         policyStore
 	Mapper        api.PolicyMapper
 	PolicyGuard   policyGuard
 	QuotaGuard    quotaGuard
+	Consent       policyConsent
 	ErrorResponse errorResponse
 }
 
 func NewPoliciesCreate(store policyStore, mapper api.PolicyMapper,
-	policyGuard policyGuard, quotaGuard quotaGuard, errorResponse errorResponse) *PoliciesCreate {
+	policyGuard policyGuard, quotaGuard quotaGuard, consent policyConsent,
+	errorResponse errorResponse) *PoliciesCreate {
 	return &PoliciesCreate{
 		Store:         store,
 		Mapper:        mapper,
 		PolicyGuard:   policyGuard,
 		QuotaGuard:    quotaGuard,
+		Consent:       consent,
 		ErrorResponse: errorResponse,
 	}
 }
@@ -54,15 +69,33 @@ func (h *PoliciesCreate) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	logger = logger.Session("create-policies")
 	tokenData := getTokenData(req)
 
+	dryRun, err := parseDryRun(req.URL.Query())
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+
+	overlaps, err := parseOverlaps(req.URL.Query())
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+
 	bodyBytes, err := io.ReadAll(req.Body)
 	if err != nil {
 		h.ErrorResponse.BadRequest(logger, w, err, "failed reading request body")
 		return
 	}
 
+	if dryRun {
+		h.serveDryRun(logger, w, bodyBytes, overlaps, tokenData)
+		return
+	}
+
 	policies, err := h.Mapper.AsStorePolicy(bodyBytes)
 	if err != nil {
-		h.ErrorResponse.BadRequest(logger, w, err, fmt.Sprintf("mapper: %s", err))
+		h.reject(logger, w, h.ErrorResponse.BadRequest, err, fmt.Sprintf("mapper: %s", err),
+			api.FailureInvalidPolicy, bodyBytes, tokenData)
 		return
 	}
 
@@ -73,7 +106,7 @@ func (h *PoliciesCreate) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	}
 	if !authorized {
 		err := errors.New("one or more applications cannot be found or accessed")
-		h.ErrorResponse.Forbidden(logger, w, err, err.Error())
+		h.reject(logger, w, h.ErrorResponse.Forbidden, err, err.Error(), api.FailureForbidden, bodyBytes, tokenData)
 		return
 	}
 
@@ -84,18 +117,196 @@ func (h *PoliciesCreate) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	}
 	if !authorized {
 		err := errors.New("policy quota exceeded")
-		h.ErrorResponse.Forbidden(logger, w, err, err.Error())
+		h.reject(logger, w, h.ErrorResponse.Forbidden, err, err.Error(), api.FailureQuotaExceeded, bodyBytes, tokenData)
+		return
+	}
+
+	policies, pending, err := h.Consent.PendingPolicies(policies, tokenData)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "check consent failed")
 		return
 	}
 
-	err = h.Store.Create(policies)
+	var replaced []store.Policy
+	if overlaps != overlapsAllow {
+		existing, err := existingOverlapPolicies(h.Store, policies)
+		if err != nil {
+			h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+			return
+		}
+
+		policyOverlaps := newPolicyOverlaps(policies, existing)
+		if len(policyOverlaps) > 0 && overlaps == overlapsReject {
+			err := errors.New(overlapReason)
+			h.reject(logger, w, h.ErrorResponse.BadRequest, overlapFailures(err, bodyBytes, policies, existing, policyOverlaps),
+				err.Error(), api.FailureOverlap, bodyBytes, tokenData)
+			return
+		}
+		if conflicts := metadataConflicts(policyOverlaps); len(conflicts) > 0 {
+			err := errors.New(metadataConflictReason)
+			h.reject(logger, w, h.ErrorResponse.BadRequest, overlapFailures(err, bodyBytes, policies, existing, conflicts),
+				err.Error(), api.FailureOverlap, bodyBytes, tokenData)
+			return
+		}
+		policies, replaced = mergePolicyOverlaps(policies, existing, policyOverlaps)
+	}
+
+	if len(pending) > 0 {
+		err = h.Store.CreateWithPending(policies, replaced, pending, getActor(tokenData))
+	} else if len(replaced) > 0 {
+		err = h.Store.MergeWithEvent(policies, replaced, getActor(tokenData))
+	} else {
+		err = h.Store.CreateWithEvent(policies, getActor(tokenData))
+	}
+	var deniedErr *store.DeniedPoliciesError
+	if errors.As(err, &deniedErr) {
+		h.ErrorResponse.Conflict(logger, w, deniedFailures(deniedErr, bodyBytes, h.Mapper), deniedReason)
+		return
+	}
 	if err != nil {
 		h.ErrorResponse.InternalServerError(logger, w, err, "database create failed")
 		return
 	}
 
 	logger.Info("created-policies", lager.Data{"policies": policies, "userName": tokenData.UserName})
-	w.WriteHeader(http.StatusOK)
+
+	status := http.StatusOK
+	if len(pending) > 0 {
+		logger.Info("created-pending-policies", lager.Data{"pending": pending, "userName": tokenData.UserName})
+		status = http.StatusAccepted
+	}
+
+	w.WriteHeader(status)
 	// #nosec G104 - ignore error writing http response to avoid spamming logs on a DoS
 	w.Write([]byte("{}"))
 }
+
+// reject responds with the error of a rejected request. The policies are then
+// checked on their own, and the metadata of the response lists the ones that
+// are rejected with their index in the request and the reason.
+func (h *PoliciesCreate) reject(logger lager.Logger, w http.ResponseWriter,
+	respond func(lager.Logger, http.ResponseWriter, error, string), err error, description string,
+	code string, bodyBytes []byte, tokenData uaa_client.CheckTokenResponse) {
+	if _, ok := err.(httperror.MetadataError); !ok {
+		failures, checkErr := h.policyFailures(bodyBytes, code, description, tokenData)
+		if checkErr != nil {
+			logger.Error("check-policy-failures", checkErr)
+		} else if len(failures) > 0 {
+			err = httperror.NewMetadataError(err, map[string]interface{}{"failures": failures})
+		}
+	}
+	respond(logger, w, err, description)
+}
+
+// policyFailures returns each policy of the request that fails on its own.
+// When none of them does, they only fail together, e.g. by exceeding a space
+// or org quota, and every policy is returned with the given code and reason.
+func (h *PoliciesCreate) policyFailures(bodyBytes []byte, code, reason string,
+	tokenData uaa_client.CheckTokenResponse) ([]api.PolicyFailure, error) {
+	rawPolicies, err := parseDryRunPolicies(bodyBytes)
+	if err != nil {
+		// the policies cannot be told apart
+		return nil, nil
+	}
+
+	policies, failures, err := checkDryRunPolicies(rawPolicies, h.Mapper, h.PolicyGuard, tokenData)
+	if err != nil {
+		return nil, err
+	}
+
+	quotaExceeded, err := checkDryRunQuotas(policies, h.QuotaGuard, tokenData)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range policies {
+		if quotaExceeded[p.policy.Source.ID] {
+			failures = append(failures, quotaExceededFailure(p))
+		}
+	}
+
+	if len(failures) == 0 {
+		for i, raw := range rawPolicies {
+			failures = append(failures, api.PolicyFailure{Index: i, Policy: raw, Code: code, Reason: reason})
+		}
+	}
+	sortPolicyFailures(failures)
+	return failures, nil
+}
+
+// serveDryRun runs every check of a create without writing anything, and
+// reports which policies would be created, which already exist and which
+// would fail
+func (h *PoliciesCreate) serveDryRun(logger lager.Logger, w http.ResponseWriter, bodyBytes []byte, overlaps string,
+	tokenData uaa_client.CheckTokenResponse) {
+	rawPolicies, err := parseDryRunPolicies(bodyBytes)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, fmt.Sprintf("mapper: %s", err))
+		return
+	}
+
+	policies, failures, err := checkDryRunPolicies(rawPolicies, h.Mapper, h.PolicyGuard, tokenData)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "check access failed")
+		return
+	}
+
+	quotaExceeded, err := checkDryRunQuotas(policies, h.QuotaGuard, tokenData)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "check quota failed")
+		return
+	}
+
+	missing, existing, denied, err := partitionExistingPolicies(policies, h.Store, true)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+		return
+	}
+	for _, p := range denied {
+		failures = append(failures, deniedFailure(p))
+	}
+
+	var wouldCreate []dryRunPolicy
+	for _, p := range missing {
+		if quotaExceeded[p.policy.Source.ID] {
+			failures = append(failures, quotaExceededFailure(p))
+			continue
+		}
+		wouldCreate = append(wouldCreate, p)
+	}
+
+	wouldCreate, wouldBePending, err := partitionPendingPolicies(wouldCreate, h.Consent, tokenData)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "check consent failed")
+		return
+	}
+
+	wouldCreate, overlapping, err := checkDryRunOverlaps(wouldCreate, h.Store, overlaps)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+		return
+	}
+	failures = append(failures, overlapping...)
+	sortPolicyFailures(failures)
+
+	bytes, err := json.Marshal(api.CreateDryRunPayload{
+		WouldCreate:    dryRunRawPolicies(wouldCreate),
+		WouldBePending: dryRunRawPolicies(wouldBePending),
+		AlreadyExist:   dryRunRawPolicies(existing),
+		WouldFail:      failures,
+	})
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "marshal response failed") // untested
+		return
+	}
+
+	logger.Info("dry-run-create-policies", lager.Data{
+		"would_create":     len(wouldCreate),
+		"would_be_pending": len(wouldBePending),
+		"already_exist":    len(existing),
+		"would_fail":       len(failures),
+		"userName":         tokenData.UserName,
+	})
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write(bytes)
+}
@@ -2,14 +2,18 @@ package handlers_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 
+	"code.cloudfoundry.org/cf-networking-helpers/httperror"
 	"code.cloudfoundry.org/cf-networking-helpers/testsupport"
 	"code.cloudfoundry.org/lager/v3"
 	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/api"
 	apifakes "code.cloudfoundry.org/policy-server/api/fakes"
 	"code.cloudfoundry.org/policy-server/handlers"
 	"code.cloudfoundry.org/policy-server/handlers/fakes"
@@ -30,6 +34,7 @@ var _ = Describe("PoliciesCreate", func() {
 		fakeMapper             *apifakes.PolicyMapper
 		fakePolicyGuard        *fakes.PolicyGuard
 		fakeQuotaGuard         *fakes.QuotaGuard
+		fakeConsent            *fakes.PolicyConsent
 		fakeErrorResponse      *fakes.ErrorResponse
 		logger                 *lagertest.TestLogger
 		expectedLogger         lager.Logger
@@ -47,6 +52,10 @@ var _ = Describe("PoliciesCreate", func() {
 		fakeMapper = &apifakes.PolicyMapper{}
 		fakePolicyGuard = &fakes.PolicyGuard{}
 		fakeQuotaGuard = &fakes.QuotaGuard{}
+		fakeConsent = &fakes.PolicyConsent{}
+		fakeConsent.PendingPoliciesStub = func(policies []store.Policy, _ uaa_client.CheckTokenResponse) ([]store.Policy, []store.PendingPolicy, error) {
+			return policies, nil, nil
+		}
 		logger = lagertest.NewTestLogger("test")
 		expectedLogger = lager.NewLogger("test").Session("create-policies")
 
@@ -59,11 +68,13 @@ var _ = Describe("PoliciesCreate", func() {
 			Mapper:        fakeMapper,
 			PolicyGuard:   fakePolicyGuard,
 			QuotaGuard:    fakeQuotaGuard,
+			Consent:       fakeConsent,
 			ErrorResponse: fakeErrorResponse,
 		}
 		tokenData = uaa_client.CheckTokenResponse{
 			Scope:    []string{"network.admin"},
 			UserName: "some_user",
+			ClientID: "some-client",
 		}
 
 		expectedPolicies = []store.Policy{
@@ -105,8 +116,10 @@ var _ = Describe("PoliciesCreate", func() {
 			policies, token := fakePolicyGuard.CheckAccessArgsForCall(0)
 			Expect(policies).To(Equal(expectedPolicies))
 			Expect(token).To(Equal(tokenData))
-			Expect(fakeStore.CreateCallCount()).To(Equal(1))
-			Expect(fakeStore.CreateArgsForCall(0)).To(Equal(expectedPolicies))
+			Expect(fakeStore.CreateWithEventCallCount()).To(Equal(1))
+			createdPolicies, actor := fakeStore.CreateWithEventArgsForCall(0)
+			Expect(createdPolicies).To(Equal(expectedPolicies))
+			Expect(actor).To(Equal(store.Actor{Name: "some_user", ClientID: "some-client"}))
 			Expect(resp.Code).To(Equal(http.StatusOK))
 			Expect(resp.Body.String()).To(MatchJSON("{}"))
 		}
@@ -167,6 +180,24 @@ var _ = Describe("PoliciesCreate", func() {
 		})
 	})
 
+	Context("when the token belongs to a client", func() {
+		BeforeEach(func() {
+			tokenData = uaa_client.CheckTokenResponse{
+				Scope:    []string{"network.admin"},
+				Subject:  "some-client",
+				ClientID: "some-client",
+			}
+		})
+
+		It("records the client as the actor", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.CreateWithEventCallCount()).To(Equal(1))
+			_, actor := fakeStore.CreateWithEventArgsForCall(0)
+			Expect(actor).To(Equal(store.Actor{Name: "some-client", ClientID: "some-client"}))
+		})
+	})
+
 	Context("when the token isn't on the request context", func() {
 		BeforeEach(func() {
 			tokenData = uaa_client.CheckTokenResponse{}
@@ -232,6 +263,165 @@ var _ = Describe("PoliciesCreate", func() {
 		})
 	})
 
+	Context("when some policies of the request are rejected", func() {
+		var policyFailures func(err error) interface{}
+
+		BeforeEach(func() {
+			var err error
+			requestBody = `{
+				"policies": [
+					{ "source": { "id": "new-app" } },
+					{ "source": { "id": "invalid-app" } },
+					{ "source": { "id": "forbidden-app" } }
+				]
+			}`
+			request, err = http.NewRequest("POST", "/networking/v1/external/policies", bytes.NewBuffer([]byte(requestBody)))
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeMapper.AsStorePolicyStub = func(body []byte) ([]store.Policy, error) {
+				var payload struct {
+					Policies []struct {
+						Source struct {
+							ID string `json:"id"`
+						} `json:"source"`
+					} `json:"policies"`
+				}
+				Expect(json.Unmarshal(body, &payload)).To(Succeed())
+
+				var policies []store.Policy
+				for _, p := range payload.Policies {
+					if p.Source.ID == "invalid-app" {
+						return nil, errors.New("validate policies: banana")
+					}
+					policies = append(policies, dryRunPolicy(p.Source.ID))
+				}
+				return policies, nil
+			}
+			fakePolicyGuard.CheckAccessStub = func(policies []store.Policy, _ uaa_client.CheckTokenResponse) (bool, error) {
+				for _, p := range policies {
+					if p.Source.ID == "forbidden-app" {
+						return false, nil
+					}
+				}
+				return true, nil
+			}
+
+			policyFailures = func(err error) interface{} {
+				metadataError, ok := err.(httperror.MetadataError)
+				Expect(ok).To(BeTrue())
+				return metadataError.Metadata()["failures"]
+			}
+		})
+
+		It("reports the index and reason of each rejected policy", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.CreateWithEventCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("validate policies: banana"))
+			Expect(description).To(Equal("mapper: validate policies: banana"))
+			Expect(policyFailures(err)).To(Equal([]api.PolicyFailure{
+				{
+					Index:  1,
+					Policy: json.RawMessage(`{ "source": { "id": "invalid-app" } }`),
+					Code:   "invalid_policy",
+					Reason: "validate policies: banana",
+				},
+				{
+					Index:  2,
+					Policy: json.RawMessage(`{ "source": { "id": "forbidden-app" } }`),
+					Code:   "forbidden",
+					Reason: "one or more applications cannot be found or accessed",
+				},
+			}))
+		})
+
+		Context("when the policies are valid but some cannot be accessed", func() {
+			BeforeEach(func() {
+				request.Body = io.NopCloser(bytes.NewBufferString(`{"policies": [
+					{ "source": { "id": "new-app" } },
+					{ "source": { "id": "forbidden-app" } }
+				]}`))
+			})
+
+			It("reports the policies that cannot be accessed", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeErrorResponse.ForbiddenCallCount()).To(Equal(1))
+
+				_, _, err, description := fakeErrorResponse.ForbiddenArgsForCall(0)
+				Expect(description).To(Equal("one or more applications cannot be found or accessed"))
+				Expect(policyFailures(err)).To(Equal([]api.PolicyFailure{{
+					Index:  1,
+					Policy: json.RawMessage(`{ "source": { "id": "forbidden-app" } }`),
+					Code:   "forbidden",
+					Reason: "one or more applications cannot be found or accessed",
+				}}))
+			})
+		})
+
+		Context("when the policies only exceed the quota together", func() {
+			BeforeEach(func() {
+				request.Body = io.NopCloser(bytes.NewBufferString(`{"policies": [
+					{ "source": { "id": "new-app" } },
+					{ "source": { "id": "other-app" } }
+				]}`))
+				fakeQuotaGuard.CheckAccessStub = func(policies []store.Policy, _ uaa_client.CheckTokenResponse) (bool, error) {
+					return len(policies) == 1, nil
+				}
+			})
+
+			It("reports every policy as exceeding the quota", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeErrorResponse.ForbiddenCallCount()).To(Equal(1))
+
+				_, _, err, description := fakeErrorResponse.ForbiddenArgsForCall(0)
+				Expect(description).To(Equal("policy quota exceeded"))
+				Expect(policyFailures(err)).To(Equal([]api.PolicyFailure{
+					{
+						Index:  0,
+						Policy: json.RawMessage(`{ "source": { "id": "new-app" } }`),
+						Code:   "quota_exceeded",
+						Reason: "policy quota exceeded",
+					},
+					{
+						Index:  1,
+						Policy: json.RawMessage(`{ "source": { "id": "other-app" } }`),
+						Code:   "quota_exceeded",
+						Reason: "policy quota exceeded",
+					},
+				}))
+			})
+		})
+
+		Context("when the policies cannot be checked on their own", func() {
+			BeforeEach(func() {
+				fakePolicyGuard.CheckAccessStub = nil
+				fakePolicyGuard.CheckAccessReturns(false, errors.New("banana"))
+			})
+
+			It("responds without the rejected policies and logs the error", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+
+				_, _, err, _ := fakeErrorResponse.BadRequestArgsForCall(0)
+				Expect(err).To(MatchError("validate policies: banana"))
+				_, ok := err.(httperror.MetadataError)
+				Expect(ok).To(BeFalse())
+
+				Expect(logger.Logs()).To(ContainElement(
+					LogsWith(lager.ERROR, "test.create-policies.check-policy-failures"),
+				))
+			})
+		})
+	})
+
 	Context("when the policy guard returns an error", func() {
 		BeforeEach(func() {
 			fakePolicyGuard.CheckAccessReturns(false, errors.New("banana"))
@@ -268,9 +458,90 @@ var _ = Describe("PoliciesCreate", func() {
 		})
 	})
 
+	Context("when some policies need the consent of their destination space", func() {
+		var pending []store.PendingPolicy
+
+		BeforeEach(func() {
+			pending = []store.PendingPolicy{{Policy: expectedPolicies[1], DestinationSpaceGUID: "some-space-guid"}}
+			fakeConsent.PendingPoliciesStub = nil
+			fakeConsent.PendingPoliciesReturns(expectedPolicies[:1], pending, nil)
+		})
+
+		It("creates the other policies and holds them until they are approved", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			policies, token := fakeConsent.PendingPoliciesArgsForCall(0)
+			Expect(policies).To(Equal(expectedPolicies))
+			Expect(token).To(Equal(tokenData))
+
+			Expect(fakeStore.CreateWithEventCallCount()).To(Equal(0))
+			Expect(fakeStore.CreateWithPendingCallCount()).To(Equal(1))
+			created, replaced, createdPending, actor := fakeStore.CreateWithPendingArgsForCall(0)
+			Expect(created).To(Equal(expectedPolicies[:1]))
+			Expect(replaced).To(BeEmpty())
+			Expect(createdPending).To(Equal(pending))
+			Expect(actor).To(Equal(store.Actor{Name: "some_user", ClientID: "some-client"}))
+
+			Expect(resp.Code).To(Equal(http.StatusAccepted))
+			Expect(resp.Body.String()).To(MatchJSON("{}"))
+		})
+
+		Context("when every policy needs consent", func() {
+			BeforeEach(func() {
+				fakeConsent.PendingPoliciesReturns([]store.Policy{}, pending, nil)
+			})
+
+			It("only holds the policies", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeStore.CreateWithEventCallCount()).To(Equal(0))
+				Expect(fakeStore.CreateWithPendingCallCount()).To(Equal(1))
+				created, _, createdPending, _ := fakeStore.CreateWithPendingArgsForCall(0)
+				Expect(created).To(BeEmpty())
+				Expect(createdPending).To(Equal(pending))
+				Expect(resp.Code).To(Equal(http.StatusAccepted))
+			})
+		})
+
+		Context("when holding the policies fails", func() {
+			BeforeEach(func() {
+				fakeStore.CreateWithPendingReturns(errors.New("banana"))
+			})
+
+			It("calls the internal server error handler", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				_, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+				Expect(w).To(Equal(resp))
+				Expect(err).To(MatchError("banana"))
+				Expect(description).To(Equal("database create failed"))
+			})
+		})
+	})
+
+	Context("when checking consent returns an error", func() {
+		BeforeEach(func() {
+			fakeConsent.PendingPoliciesStub = nil
+			fakeConsent.PendingPoliciesReturns(nil, nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.CreateWithEventCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("check consent failed"))
+		})
+	})
+
 	Context("when the store Create call returns an error", func() {
 		BeforeEach(func() {
-			fakeStore.CreateReturns(errors.New("banana"))
+			fakeStore.CreateWithEventReturns(errors.New("banana"))
 		})
 
 		It("calls the internal server error handler", func() {
@@ -286,6 +557,49 @@ var _ = Describe("PoliciesCreate", func() {
 		})
 	})
 
+	Context("when some policies already exist as deny policies", func() {
+		BeforeEach(func() {
+			var err error
+			requestBody = `{
+				"policies": [
+					{ "source": { "id": "new-app" } },
+					{ "source": { "id": "denied-app" } }
+				]
+			}`
+			request, err = http.NewRequest("POST", "/networking/v1/external/policies", bytes.NewBuffer([]byte(requestBody)))
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeStore.CreateWithEventReturns(&store.DeniedPoliciesError{Policies: []store.Policy{dryRunPolicy("denied-app")}})
+			fakeMapper.AsStorePolicyStub = func(body []byte) ([]store.Policy, error) {
+				if strings.Contains(string(body), "new-app") && strings.Contains(string(body), "denied-app") {
+					return []store.Policy{dryRunPolicy("new-app"), dryRunPolicy("denied-app")}, nil
+				}
+				return dryRunMapperStub(body)
+			}
+		})
+
+		It("calls the conflict handler with the denied policies", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.ConflictCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.ConflictArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(description).To(Equal("one or more policies already exist as deny policies, delete them to allow the traffic"))
+
+			metadataErr, ok := err.(httperror.MetadataError)
+			Expect(ok).To(BeTrue())
+			Expect(metadataErr.Metadata()).To(Equal(map[string]interface{}{"failures": []api.PolicyFailure{{
+				Index:  1,
+				Policy: json.RawMessage(`{ "source": { "id": "denied-app" } }`),
+				Code:   "denied_policy",
+				Reason: "one or more policies already exist as deny policies, delete them to allow the traffic",
+			}}}))
+		})
+	})
+
 	Context("when there are errors reading the body bytes", func() {
 		BeforeEach(func() {
 			request.Body = io.NopCloser(&testsupport.BadReader{})
@@ -303,4 +617,500 @@ var _ = Describe("PoliciesCreate", func() {
 			Expect(description).To(Equal("failed reading request body"))
 		})
 	})
+
+	Context("when dry_run is true", func() {
+		BeforeEach(func() {
+			var err error
+			requestBody = `{
+				"policies": [
+					{ "source": { "id": "new-app" } },
+					{ "source": { "id": "existing-app" } },
+					{ "source": { "id": "invalid-app" } },
+					{ "source": { "id": "forbidden-app" } }
+				]
+			}`
+			request, err = http.NewRequest("POST", "/networking/v1/external/policies?dry_run=true", bytes.NewBuffer([]byte(requestBody)))
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeMapper.AsStorePolicyStub = dryRunMapperStub
+			fakePolicyGuard.CheckAccessStub = func(policies []store.Policy, _ uaa_client.CheckTokenResponse) (bool, error) {
+				for _, p := range policies {
+					if p.Source.ID == "forbidden-app" {
+						return false, nil
+					}
+				}
+				return true, nil
+			}
+			fakeStore.ByGuidsReturns([]store.Policy{dryRunPolicy("existing-app")}, nil)
+		})
+
+		It("reports what would happen without creating anything", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.CreateWithEventCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Body.String()).To(MatchJSON(`{
+				"would_create": [{ "source": { "id": "new-app" } }],
+				"would_be_pending": [],
+				"already_exist": [{ "source": { "id": "existing-app" } }],
+				"would_fail": [
+					{ "index": 2, "policy": { "source": { "id": "invalid-app" } }, "code": "invalid_policy", "reason": "validate policies: banana" },
+					{ "index": 3, "policy": { "source": { "id": "forbidden-app" } }, "code": "forbidden", "reason": "one or more applications cannot be found or accessed" }
+				]
+			}`))
+
+			Expect(fakeMapper.AsStorePolicyCallCount()).To(Equal(4))
+			Expect(fakeMapper.AsStorePolicyArgsForCall(0)).To(MatchJSON(`{"policies": [{ "source": { "id": "new-app" } }]}`))
+
+			srcGuids, dstGuids, _ := fakeStore.ByGuidsArgsForCall(0)
+			Expect(srcGuids).To(Equal([]string{"new-app", "existing-app"}))
+			Expect(dstGuids).To(BeEmpty())
+
+			Expect(fakeQuotaGuard.CheckAccessCallCount()).To(Equal(2))
+			quotaPolicies, _ := fakeQuotaGuard.CheckAccessArgsForCall(1)
+			Expect(quotaPolicies).To(Equal([]store.Policy{dryRunPolicy("existing-app")}))
+		})
+
+		It("logs the result", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(logger.Logs()).To(HaveLen(1))
+			Expect(logger.Logs()[0]).To(SatisfyAll(
+				LogsWith(lager.INFO, "test.create-policies.dry-run-create-policies"),
+				HaveLogData(SatisfyAll(
+					HaveKeyWithValue("would_create", BeEquivalentTo(1)),
+					HaveKeyWithValue("would_be_pending", BeEquivalentTo(0)),
+					HaveKeyWithValue("already_exist", BeEquivalentTo(1)),
+					HaveKeyWithValue("would_fail", BeEquivalentTo(2)),
+					HaveKeyWithValue("userName", "some_user"),
+				)),
+			))
+		})
+
+		Context("when the quota of a source would be exceeded", func() {
+			BeforeEach(func() {
+				fakeQuotaGuard.CheckAccessStub = func(policies []store.Policy, _ uaa_client.CheckTokenResponse) (bool, error) {
+					return policies[0].Source.ID != "new-app", nil
+				}
+			})
+
+			It("reports the new policies of that source as failing", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(`{
+					"would_create": [],
+					"would_be_pending": [],
+					"already_exist": [{ "source": { "id": "existing-app" } }],
+					"would_fail": [
+						{ "index": 0, "policy": { "source": { "id": "new-app" } }, "code": "quota_exceeded", "reason": "policy quota exceeded" },
+						{ "index": 2, "policy": { "source": { "id": "invalid-app" } }, "code": "invalid_policy", "reason": "validate policies: banana" },
+						{ "index": 3, "policy": { "source": { "id": "forbidden-app" } }, "code": "forbidden", "reason": "one or more applications cannot be found or accessed" }
+					]
+				}`))
+			})
+		})
+
+		Context("when overlaps are rejected", func() {
+			BeforeEach(func() {
+				request.URL.RawQuery = "dry_run=true&overlaps=reject"
+				overlapping := dryRunPolicy("new-app")
+				overlapping.Destination.Ports = store.Ports{Start: 8081, End: 9000}
+				fakeStore.ByGuidsStub = func(srcGuids, dstGuids []string, inSourceAndDest bool) ([]store.Policy, error) {
+					if inSourceAndDest {
+						return []store.Policy{overlapping}, nil
+					}
+					return []store.Policy{dryRunPolicy("existing-app")}, nil
+				}
+			})
+
+			It("reports the new policies that overlap as failing", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(`{
+					"would_create": [],
+					"would_be_pending": [],
+					"already_exist": [{ "source": { "id": "existing-app" } }],
+					"would_fail": [
+						{ "index": 0, "policy": { "source": { "id": "new-app" } }, "code": "overlapping_policy", "reason": "one or more policies overlap with policies of the same source, destination and protocol" },
+						{ "index": 2, "policy": { "source": { "id": "invalid-app" } }, "code": "invalid_policy", "reason": "validate policies: banana" },
+						{ "index": 3, "policy": { "source": { "id": "forbidden-app" } }, "code": "forbidden", "reason": "one or more applications cannot be found or accessed" }
+					]
+				}`))
+			})
+
+			Context("when overlaps are merged", func() {
+				BeforeEach(func() {
+					request.URL.RawQuery = "dry_run=true&overlaps=merge"
+				})
+
+				It("reports the policies as they would be created", func() {
+					MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+					Expect(resp.Code).To(Equal(http.StatusOK))
+					Expect(resp.Body.String()).To(ContainSubstring(`"would_create":[{"source":{"id":"new-app"}}]`))
+				})
+			})
+		})
+
+		Context("when a policy already exists as a deny policy", func() {
+			BeforeEach(func() {
+				denied := dryRunPolicy("existing-app")
+				denied.Action = store.PolicyActionDeny
+				fakeStore.ByGuidsReturns([]store.Policy{denied}, nil)
+			})
+
+			It("reports the policy as failing", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(`{
+					"would_create": [{ "source": { "id": "new-app" } }],
+					"would_be_pending": [],
+					"already_exist": [],
+					"would_fail": [
+						{ "index": 1, "policy": { "source": { "id": "existing-app" } }, "code": "denied_policy", "reason": "one or more policies already exist as deny policies, delete them to allow the traffic" },
+						{ "index": 2, "policy": { "source": { "id": "invalid-app" } }, "code": "invalid_policy", "reason": "validate policies: banana" },
+						{ "index": 3, "policy": { "source": { "id": "forbidden-app" } }, "code": "forbidden", "reason": "one or more applications cannot be found or accessed" }
+					]
+				}`))
+			})
+		})
+
+		Context("when a new policy needs the consent of its destination space", func() {
+			BeforeEach(func() {
+				fakeConsent.PendingPoliciesStub = nil
+				fakeConsent.PendingPoliciesReturns([]store.Policy{}, []store.PendingPolicy{
+					{Policy: dryRunPolicy("new-app"), DestinationSpaceGUID: "some-space-guid"},
+				}, nil)
+			})
+
+			It("reports the policy as pending", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeConsent.PendingPoliciesCallCount()).To(Equal(1))
+				policies, token := fakeConsent.PendingPoliciesArgsForCall(0)
+				Expect(policies).To(Equal([]store.Policy{dryRunPolicy("new-app")}))
+				Expect(token).To(Equal(tokenData))
+
+				Expect(fakeStore.CreateWithPendingCallCount()).To(Equal(0))
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(`{
+					"would_create": [],
+					"would_be_pending": [{ "source": { "id": "new-app" } }],
+					"already_exist": [{ "source": { "id": "existing-app" } }],
+					"would_fail": [
+						{ "index": 2, "policy": { "source": { "id": "invalid-app" } }, "code": "invalid_policy", "reason": "validate policies: banana" },
+						{ "index": 3, "policy": { "source": { "id": "forbidden-app" } }, "code": "forbidden", "reason": "one or more applications cannot be found or accessed" }
+					]
+				}`))
+			})
+		})
+
+		Context("when checking consent returns an error", func() {
+			BeforeEach(func() {
+				fakeConsent.PendingPoliciesStub = nil
+				fakeConsent.PendingPoliciesReturns(nil, nil, errors.New("banana"))
+			})
+
+			It("calls the internal server error handler", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+				_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+				Expect(err).To(MatchError("banana"))
+				Expect(description).To(Equal("check consent failed"))
+			})
+		})
+
+		Context("when the request body has no policies", func() {
+			BeforeEach(func() {
+				request.Body = io.NopCloser(bytes.NewBufferString(`{"policies": []}`))
+			})
+
+			It("calls the bad request handler", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+				_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+				Expect(err).To(MatchError("missing policies"))
+				Expect(description).To(Equal("mapper: missing policies"))
+			})
+		})
+
+		Context("when the policy guard returns an error", func() {
+			BeforeEach(func() {
+				fakePolicyGuard.CheckAccessStub = nil
+				fakePolicyGuard.CheckAccessReturns(false, errors.New("banana"))
+			})
+
+			It("calls the internal server error handler", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+				_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+				Expect(err).To(MatchError("banana"))
+				Expect(description).To(Equal("check access failed"))
+			})
+		})
+
+		Context("when the quota guard returns an error", func() {
+			BeforeEach(func() {
+				fakeQuotaGuard.CheckAccessReturns(false, errors.New("banana"))
+			})
+
+			It("calls the internal server error handler", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+				_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+				Expect(err).To(MatchError("banana"))
+				Expect(description).To(Equal("check quota failed"))
+			})
+		})
+
+		Context("when reading the existing policies fails", func() {
+			BeforeEach(func() {
+				fakeStore.ByGuidsReturns(nil, errors.New("banana"))
+			})
+
+			It("calls the internal server error handler", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+				_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+				Expect(err).To(MatchError("banana"))
+				Expect(description).To(Equal("database read failed"))
+			})
+		})
+	})
+
+	Context("when overlaps are rejected or merged", func() {
+		var existingPolicy store.Policy
+
+		BeforeEach(func() {
+			var err error
+			requestBody = `{
+				"policies": [
+					{ "source": { "id": "some-app-guid" } },
+					{ "source": { "id": "another-app-guid" } }
+				]
+			}`
+			request, err = http.NewRequest("POST", "/networking/v1/external/policies?overlaps=reject", bytes.NewBuffer([]byte(requestBody)))
+			Expect(err).NotTo(HaveOccurred())
+
+			existingPolicy = store.Policy{
+				Source: store.Source{ID: "some-app-guid", Tag: "01"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Tag:      "02",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: 9000, End: 9500},
+				},
+			}
+			fakeStore.ByGuidsReturns([]store.Policy{existingPolicy}, nil)
+		})
+
+		It("reads the existing policies between the sources and destinations", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.ByGuidsCallCount()).To(Equal(1))
+			srcGuids, dstGuids, inSourceAndDest := fakeStore.ByGuidsArgsForCall(0)
+			Expect(srcGuids).To(Equal([]string{"some-app-guid", "another-app-guid"}))
+			Expect(dstGuids).To(Equal([]string{"some-other-app-guid", "some-other-app-guid"}))
+			Expect(inSourceAndDest).To(BeTrue())
+		})
+
+		Context("when overlaps is reject", func() {
+			It("reports the policies that overlap", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeStore.CreateWithEventCallCount()).To(Equal(0))
+				Expect(fakeStore.MergeWithEventCallCount()).To(Equal(0))
+				Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+
+				l, w, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+				Expect(l).To(Equal(expectedLogger))
+				Expect(w).To(Equal(resp))
+				Expect(err).To(MatchError("one or more policies overlap with policies of the same source, destination and protocol"))
+				Expect(description).To(Equal("one or more policies overlap with policies of the same source, destination and protocol"))
+
+				metadataError, ok := err.(httperror.MetadataError)
+				Expect(ok).To(BeTrue())
+				Expect(metadataError.Metadata()["failures"]).To(Equal([]api.PolicyFailure{{
+					Index:  0,
+					Policy: json.RawMessage(`{ "source": { "id": "some-app-guid" } }`),
+					Code:   "overlapping_policy",
+					Reason: "one or more policies overlap with policies of the same source, destination and protocol",
+				}}))
+			})
+
+			Context("when nothing overlaps", func() {
+				BeforeEach(func() {
+					fakeStore.ByGuidsReturns([]store.Policy{}, nil)
+				})
+
+				It("creates the policies", func() {
+					createPoliciesSucceeds()
+				})
+			})
+		})
+
+		Context("when overlaps is merge", func() {
+			BeforeEach(func() {
+				request.URL.RawQuery = "overlaps=merge"
+			})
+
+			It("replaces the overlapping policies by the merged policy", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeStore.CreateWithEventCallCount()).To(Equal(0))
+				Expect(fakeStore.MergeWithEventCallCount()).To(Equal(1))
+				created, deleted, actor := fakeStore.MergeWithEventArgsForCall(0)
+				Expect(created).To(Equal([]store.Policy{
+					expectedPolicies[1],
+					{
+						Source: store.Source{ID: "some-app-guid"},
+						Destination: store.Destination{
+							ID:       "some-other-app-guid",
+							Protocol: "tcp",
+							Ports:    store.Ports{Start: 8080, End: 9500},
+						},
+					},
+				}))
+				Expect(deleted).To(Equal([]store.Policy{{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "tcp",
+						Ports:    store.Ports{Start: 9000, End: 9500},
+					},
+				}}))
+				Expect(actor).To(Equal(store.Actor{Name: "some_user", ClientID: "some-client"}))
+				Expect(resp.Code).To(Equal(http.StatusOK))
+			})
+
+			Context("when the overlapping policies have different metadata", func() {
+				BeforeEach(func() {
+					expectedPolicies[0].Metadata = store.Metadata{Description: "new"}
+					existingPolicy.Metadata = store.Metadata{Description: "existing"}
+					fakeStore.ByGuidsReturns([]store.Policy{existingPolicy}, nil)
+				})
+
+				It("rejects the policies that cannot be merged", func() {
+					MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+					Expect(fakeStore.MergeWithEventCallCount()).To(Equal(0))
+					Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+
+					_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+					Expect(description).To(Equal("one or more policies overlap with policies that have different metadata and cannot be merged"))
+
+					metadataError, ok := err.(httperror.MetadataError)
+					Expect(ok).To(BeTrue())
+					Expect(metadataError.Metadata()["failures"]).To(Equal([]api.PolicyFailure{{
+						Index:  0,
+						Policy: json.RawMessage(`{ "source": { "id": "some-app-guid" } }`),
+						Code:   "overlapping_policy",
+						Reason: "one or more policies overlap with policies that have different metadata and cannot be merged",
+					}}))
+				})
+			})
+
+			Context("when the policies already exist", func() {
+				BeforeEach(func() {
+					fakeStore.ByGuidsReturns(expectedPolicies, nil)
+				})
+
+				It("creates them again without merging", func() {
+					createPoliciesSucceeds()
+					Expect(fakeStore.MergeWithEventCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when reading the existing policies fails", func() {
+			BeforeEach(func() {
+				fakeStore.ByGuidsReturns(nil, errors.New("banana"))
+			})
+
+			It("calls the internal server error handler", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeStore.CreateWithEventCallCount()).To(Equal(0))
+				Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+				l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+				Expect(l).To(Equal(expectedLogger))
+				Expect(w).To(Equal(resp))
+				Expect(err).To(MatchError("banana"))
+				Expect(description).To(Equal("database read failed"))
+			})
+		})
+	})
+
+	Context("when overlaps is not a known value", func() {
+		BeforeEach(func() {
+			request.URL.RawQuery = "overlaps=banana"
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(err).To(MatchError("invalid value for 'overlaps' parameter: must be allow, reject or merge"))
+			Expect(description).To(Equal("invalid value for 'overlaps' parameter: must be allow, reject or merge"))
+			Expect(fakeStore.CreateWithEventCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when dry_run is not a boolean", func() {
+		BeforeEach(func() {
+			request.URL.RawQuery = "dry_run=banana"
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			l, w, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("invalid value for 'dry_run' parameter: must be true or false"))
+			Expect(description).To(Equal("invalid value for 'dry_run' parameter: must be true or false"))
+			Expect(fakeStore.CreateWithEventCallCount()).To(Equal(0))
+		})
+	})
 })
+
+// dryRunMapperStub maps a single policy payload to a policy from its source
+// id, failing validation for the invalid-app source
+func dryRunMapperStub(body []byte) ([]store.Policy, error) {
+	var payload struct {
+		Policies []struct {
+			Source struct {
+				ID string `json:"id"`
+			} `json:"source"`
+		} `json:"policies"`
+	}
+	Expect(json.Unmarshal(body, &payload)).To(Succeed())
+	Expect(payload.Policies).To(HaveLen(1))
+
+	sourceID := payload.Policies[0].Source.ID
+	if sourceID == "invalid-app" {
+		return nil, errors.New("validate policies: banana")
+	}
+	return []store.Policy{dryRunPolicy(sourceID)}, nil
+}
+
+func dryRunPolicy(sourceID string) store.Policy {
+	return store.Policy{
+		Source: store.Source{ID: sourceID},
+		Destination: store.Destination{
+			ID:       "some-dst-app",
+			Protocol: "tcp",
+			Ports:    store.Ports{Start: 8080, End: 8080},
+		},
+	}
+}
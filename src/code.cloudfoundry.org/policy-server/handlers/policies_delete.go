@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -8,6 +9,8 @@ import (
 
 	"code.cloudfoundry.org/lager/v3"
 	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/uaa_client"
 )
 
 type PoliciesDelete struct {
@@ -27,24 +30,66 @@ func NewPoliciesDelete(store policyStore, mapper api.PolicyMapper,
 	}
 }
 
+// storedPolicyGuard checks access to the policies as they are stored, so that
+// a policy is deleted with the same access that creating it needed, whatever
+// action the request gives for it
+type storedPolicyGuard struct {
+	policyGuard
+	Store policyStore
+}
+
+func (g *storedPolicyGuard) CheckAccess(policies []store.Policy, tokenData uaa_client.CheckTokenResponse) (bool, error) {
+	var sourceGuids []string
+	for _, policy := range policies {
+		sourceGuids = append(sourceGuids, policy.Source.ID)
+	}
+	storedPolicies, err := g.Store.ByGuids(sourceGuids, []string{}, false)
+	if err != nil {
+		return false, fmt.Errorf("getting stored policies: %s", err)
+	}
+
+	checked := make([]store.Policy, len(policies))
+	for i, policy := range policies {
+		checked[i] = policy
+		for _, stored := range storedPolicies {
+			if stored.Equals(policy) {
+				checked[i].Action = stored.Action
+				break
+			}
+		}
+	}
+	return g.policyGuard.CheckAccess(checked, tokenData)
+}
+
 func (h *PoliciesDelete) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	logger := getLogger(req)
 	logger = logger.Session("delete-policies")
 	tokenData := getTokenData(req)
 
+	dryRun, err := parseDryRun(req.URL.Query())
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+
 	bodyBytes, err := io.ReadAll(req.Body)
 	if err != nil {
 		h.ErrorResponse.BadRequest(logger, w, err, "invalid request body")
 		return
 	}
 
+	if dryRun {
+		h.serveDryRun(logger, w, bodyBytes, tokenData)
+		return
+	}
+
 	policies, err := h.Mapper.AsStorePolicy(bodyBytes)
 	if err != nil {
 		h.ErrorResponse.BadRequest(logger, w, err, fmt.Sprintf("mapper: %s", err))
 		return
 	}
 
-	authorized, err := h.PolicyGuard.CheckAccess(policies, tokenData)
+	authorized, err := h.guard().CheckAccess(policies, tokenData)
 	if err != nil {
 		h.ErrorResponse.InternalServerError(logger, w, err, "check access failed")
 		return
@@ -55,7 +100,7 @@ func (h *PoliciesDelete) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	err = h.Store.Delete(policies)
+	err = h.Store.DeleteWithEvent(policies, getActor(tokenData))
 	if err != nil {
 		h.ErrorResponse.InternalServerError(logger, w, err, "database delete failed")
 		return
@@ -66,3 +111,51 @@ func (h *PoliciesDelete) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
 	w.Write([]byte(`{}`))
 }
+
+// serveDryRun runs every check of a delete without writing anything, and
+// reports which policies would be deleted, which do not exist and which would
+// fail
+func (h *PoliciesDelete) serveDryRun(logger lager.Logger, w http.ResponseWriter, bodyBytes []byte, tokenData uaa_client.CheckTokenResponse) {
+	rawPolicies, err := parseDryRunPolicies(bodyBytes)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, fmt.Sprintf("mapper: %s", err))
+		return
+	}
+
+	policies, failures, err := checkDryRunPolicies(rawPolicies, h.Mapper, h.guard(), tokenData)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "check access failed")
+		return
+	}
+	sortPolicyFailures(failures)
+
+	missing, existing, _, err := partitionExistingPolicies(policies, h.Store, false)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+		return
+	}
+
+	bytes, err := json.Marshal(api.DeleteDryRunPayload{
+		WouldDelete: dryRunRawPolicies(existing),
+		DoNotExist:  dryRunRawPolicies(missing),
+		WouldFail:   failures,
+	})
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "marshal response failed") // untested
+		return
+	}
+
+	logger.Info("dry-run-delete-policies", lager.Data{
+		"would_delete": len(existing),
+		"do_not_exist": len(missing),
+		"would_fail":   len(failures),
+		"userName":     tokenData.UserName,
+	})
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write(bytes)
+}
+
+func (h *PoliciesDelete) guard() policyGuard {
+	return &storedPolicyGuard{policyGuard: h.PolicyGuard, Store: h.Store}
+}
@@ -3,6 +3,7 @@ package handlers_test
 import (
 	"bytes"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 
@@ -77,6 +78,7 @@ var _ = Describe("PoliciesDelete", func() {
 		tokenData = uaa_client.CheckTokenResponse{
 			Scope:    []string{"network.admin"},
 			UserName: "some_user",
+			ClientID: "some-client",
 		}
 		fakeMapper.AsStorePolicyReturns(expectedPolicies, nil)
 		fakePolicyGuard.CheckAccessReturns(true, nil)
@@ -92,12 +94,50 @@ var _ = Describe("PoliciesDelete", func() {
 		policies, token := fakePolicyGuard.CheckAccessArgsForCall(0)
 		Expect(policies).To(Equal(expectedPolicies))
 		Expect(token).To(Equal(tokenData))
-		Expect(fakeStore.DeleteCallCount()).To(Equal(1))
-		Expect(fakeStore.DeleteArgsForCall(0)).To(Equal(expectedPolicies))
+		Expect(fakeStore.DeleteWithEventCallCount()).To(Equal(1))
+		deletedPolicies, actor := fakeStore.DeleteWithEventArgsForCall(0)
+		Expect(deletedPolicies).To(Equal(expectedPolicies))
+		Expect(actor).To(Equal(store.Actor{Name: "some_user", ClientID: "some-client"}))
 		Expect(resp.Code).To(Equal(http.StatusOK))
 		Expect(resp.Body.String()).To(MatchJSON("{}"))
 	})
 
+	Context("when the stored policy denies the traffic", func() {
+		BeforeEach(func() {
+			storedPolicy := expectedPolicies[0]
+			storedPolicy.Action = store.PolicyActionDeny
+			fakeStore.ByGuidsReturns([]store.Policy{storedPolicy}, nil)
+		})
+
+		It("checks access to the policy with the stored action", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			srcGuids, dstGuids, _ := fakeStore.ByGuidsArgsForCall(0)
+			Expect(srcGuids).To(Equal([]string{"some-app-guid"}))
+			Expect(dstGuids).To(BeEmpty())
+
+			policies, _ := fakePolicyGuard.CheckAccessArgsForCall(0)
+			Expect(policies).To(HaveLen(1))
+			Expect(policies[0].Action).To(Equal(store.PolicyActionDeny))
+			Expect(expectedPolicies[0].Action).To(BeEmpty())
+		})
+	})
+
+	Context("when reading the stored policies fails", func() {
+		BeforeEach(func() {
+			fakeStore.ByGuidsReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.DeleteWithEventCallCount()).To(Equal(0))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("getting stored policies: banana"))
+			Expect(description).To(Equal("check access failed"))
+		})
+	})
+
 	It("logs the policy with username and app guid", func() {
 		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
 
@@ -227,7 +267,7 @@ var _ = Describe("PoliciesDelete", func() {
 
 	Context("when deleting from the store fails", func() {
 		BeforeEach(func() {
-			fakeStore.DeleteReturns(errors.New("banana"))
+			fakeStore.DeleteWithEventReturns(errors.New("banana"))
 		})
 
 		It("calls the internal server error handler", func() {
@@ -242,4 +282,90 @@ var _ = Describe("PoliciesDelete", func() {
 			Expect(description).To(Equal("database delete failed"))
 		})
 	})
+
+	Context("when dry_run is true", func() {
+		BeforeEach(func() {
+			var err error
+			requestBody = `{
+				"policies": [
+					{ "source": { "id": "missing-app" } },
+					{ "source": { "id": "existing-app" } },
+					{ "source": { "id": "invalid-app" } }
+				]
+			}`
+			request, err = http.NewRequest("POST", "/networking/v1/external/policies/delete?dry_run=true", bytes.NewBuffer([]byte(requestBody)))
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeMapper.AsStorePolicyStub = dryRunMapperStub
+			fakeStore.ByGuidsReturns([]store.Policy{dryRunPolicy("existing-app")}, nil)
+		})
+
+		It("reports what would happen without deleting anything", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.DeleteWithEventCallCount()).To(Equal(0))
+			Expect(fakePolicyGuard.CheckAccessCallCount()).To(Equal(1))
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Body.String()).To(MatchJSON(`{
+				"would_delete": [{ "source": { "id": "existing-app" } }],
+				"do_not_exist": [{ "source": { "id": "missing-app" } }],
+				"would_fail": [
+					{ "index": 2, "policy": { "source": { "id": "invalid-app" } }, "code": "invalid_policy", "reason": "validate policies: banana" }
+				]
+			}`))
+		})
+
+		Context("when the subject may not delete some of the policies", func() {
+			BeforeEach(func() {
+				fakePolicyGuard.CheckAccessStub = func(policies []store.Policy, _ uaa_client.CheckTokenResponse) (bool, error) {
+					return len(policies) == 1 && policies[0].Source.ID == "existing-app", nil
+				}
+			})
+
+			It("checks each policy and reports the ones that would fail", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakePolicyGuard.CheckAccessCallCount()).To(Equal(3))
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(MatchJSON(`{
+					"would_delete": [{ "source": { "id": "existing-app" } }],
+					"do_not_exist": [],
+					"would_fail": [
+						{ "index": 0, "policy": { "source": { "id": "missing-app" } }, "code": "forbidden", "reason": "one or more applications cannot be found or accessed" },
+						{ "index": 2, "policy": { "source": { "id": "invalid-app" } }, "code": "invalid_policy", "reason": "validate policies: banana" }
+					]
+				}`))
+			})
+		})
+
+		Context("when the request body is not valid json", func() {
+			BeforeEach(func() {
+				request.Body = io.NopCloser(bytes.NewBufferString(`{`))
+			})
+
+			It("calls the bad request handler", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+				l, _, _, description := fakeErrorResponse.BadRequestArgsForCall(0)
+				Expect(l).To(Equal(expectedLogger))
+				Expect(description).To(HavePrefix("mapper: unmarshal json: "))
+			})
+		})
+
+		Context("when reading the existing policies fails", func() {
+			BeforeEach(func() {
+				fakeStore.ByGuidsReturns(nil, errors.New("banana"))
+			})
+
+			It("calls the internal server error handler", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+				_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+				Expect(err).To(MatchError("getting stored policies: banana"))
+				Expect(description).To(Equal("check access failed"))
+			})
+		})
+	})
 })
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"code.cloudfoundry.org/cf-networking-helpers/httperror"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+)
+
+// dryRunPolicy keeps a mapped policy together with the policy as it was
+// given in the request and its index there, which is what dry runs report back
+type dryRunPolicy struct {
+	index  int
+	raw    json.RawMessage
+	policy store.Policy
+}
+
+func parseDryRun(queryValues url.Values) (bool, error) {
+	value := queryValues.Get("dry_run")
+	if value == "" {
+		return false, nil
+	}
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("invalid value for 'dry_run' parameter: must be true or false")
+	}
+	return dryRun, nil
+}
+
+// parseDryRunPolicies splits the request body into its policies so that each
+// one can be mapped and checked on its own
+func parseDryRunPolicies(bodyBytes []byte) ([]json.RawMessage, error) {
+	var payload struct {
+		Policies []json.RawMessage `json:"policies"`
+	}
+	err := json.Unmarshal(bodyBytes, &payload)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal json: %s", err)
+	}
+	if len(payload.Policies) == 0 {
+		return nil, errors.New("missing policies")
+	}
+	return payload.Policies, nil
+}
+
+// checkDryRunPolicies maps each policy and checks that the subject may change
+// it. It returns the policies that pass and the reason every other one fails.
+func checkDryRunPolicies(rawPolicies []json.RawMessage, mapper api.PolicyMapper, policyGuard policyGuard,
+	tokenData uaa_client.CheckTokenResponse) ([]dryRunPolicy, []api.PolicyFailure, error) {
+	var mapped []dryRunPolicy
+	failures := []api.PolicyFailure{}
+	for i, raw := range rawPolicies {
+		policies, err := mapper.AsStorePolicy([]byte(`{"policies":[` + string(raw) + `]}`))
+		if err != nil {
+			failures = append(failures, api.PolicyFailure{
+				Index:  i,
+				Policy: raw,
+				Code:   api.FailureInvalidPolicy,
+				Reason: err.Error(),
+			})
+			continue
+		}
+		for _, policy := range policies {
+			mapped = append(mapped, dryRunPolicy{index: i, raw: raw, policy: policy})
+		}
+	}
+
+	if len(mapped) == 0 {
+		return mapped, failures, nil
+	}
+
+	authorized, err := policyGuard.CheckAccess(dryRunStorePolicies(mapped), tokenData)
+	if err != nil {
+		return nil, nil, err
+	}
+	if authorized {
+		return mapped, failures, nil
+	}
+
+	// only check the policies one by one when some of them are not allowed
+	var allowed []dryRunPolicy
+	for _, p := range mapped {
+		authorized, err := policyGuard.CheckAccess([]store.Policy{p.policy}, tokenData)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !authorized {
+			failures = append(failures, api.PolicyFailure{
+				Index:  p.index,
+				Policy: p.raw,
+				Code:   api.FailureForbidden,
+				Reason: "one or more applications cannot be found or accessed",
+			})
+			continue
+		}
+		allowed = append(allowed, p)
+	}
+	return allowed, failures, nil
+}
+
+// checkDryRunQuotas checks the quota per source with the existing policies
+// included, as it is when the policies are created, and returns the sources
+// whose quota would be exceeded
+func checkDryRunQuotas(policies []dryRunPolicy, quotaGuard quotaGuard, tokenData uaa_client.CheckTokenResponse) (map[string]bool, error) {
+	quotaExceeded := make(map[string]bool)
+	for _, sourcePolicies := range dryRunPoliciesBySource(policies) {
+		authorized, err := quotaGuard.CheckAccess(dryRunStorePolicies(sourcePolicies), tokenData)
+		if err != nil {
+			return nil, err
+		}
+		if !authorized {
+			quotaExceeded[sourcePolicies[0].policy.Source.ID] = true
+		}
+	}
+	return quotaExceeded, nil
+}
+
+func quotaExceededFailure(p dryRunPolicy) api.PolicyFailure {
+	return api.PolicyFailure{
+		Index:  p.index,
+		Policy: p.raw,
+		Code:   api.FailureQuotaExceeded,
+		Reason: "policy quota exceeded",
+	}
+}
+
+// sortPolicyFailures orders the failures as the policies are in the request
+func sortPolicyFailures(failures []api.PolicyFailure) {
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].Index < failures[j].Index
+	})
+}
+
+// partitionExistingPolicies splits the policies into those that are not
+// stored yet and those that are. With denyApart, the allow policies that are
+// stored as deny policies are returned on their own, as creating them would
+// not lift the deny.
+func partitionExistingPolicies(policies []dryRunPolicy, policyStore policyStore, denyApart bool) ([]dryRunPolicy, []dryRunPolicy, []dryRunPolicy, error) {
+	if len(policies) == 0 {
+		return nil, nil, nil, nil
+	}
+
+	var sourceGuids []string
+	for _, group := range dryRunPoliciesBySource(policies) {
+		sourceGuids = append(sourceGuids, group[0].policy.Source.ID)
+	}
+	storedPolicies, err := policyStore.ByGuids(sourceGuids, []string{}, false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var missing, existing, denied []dryRunPolicy
+	for _, p := range policies {
+		switch {
+		case !containsStorePolicy(storedPolicies, p.policy):
+			missing = append(missing, p)
+		case denyApart && !containsStorePolicyWithAction(storedPolicies, p.policy):
+			denied = append(denied, p)
+		default:
+			existing = append(existing, p)
+		}
+	}
+	return missing, existing, denied, nil
+}
+
+func containsStorePolicyWithAction(policies []store.Policy, policy store.Policy) bool {
+	for _, p := range policies {
+		if p.EqualsWithAction(policy) {
+			return true
+		}
+	}
+	return false
+}
+
+// deniedFailure is the failure of an allow policy that is stored as a deny
+// policy
+func deniedFailure(p dryRunPolicy) api.PolicyFailure {
+	return api.PolicyFailure{
+		Index:  p.index,
+		Policy: p.raw,
+		Code:   api.FailureDenied,
+		Reason: deniedReason,
+	}
+}
+
+// deniedFailures lists the policies of the request that the store rejects as
+// they exist as deny policies
+func deniedFailures(err *store.DeniedPoliciesError, bodyBytes []byte, mapper api.PolicyMapper) error {
+	rawPolicies, parseErr := parseDryRunPolicies(bodyBytes)
+	if parseErr != nil {
+		return err
+	}
+
+	failures := []api.PolicyFailure{}
+	for i, raw := range rawPolicies {
+		policies, mapErr := mapper.AsStorePolicy([]byte(`{"policies":[` + string(raw) + `]}`))
+		if mapErr != nil {
+			continue
+		}
+		for _, policy := range policies {
+			if containsStorePolicy(err.Policies, policy) {
+				failures = append(failures, deniedFailure(dryRunPolicy{index: i, raw: raw, policy: policy}))
+				break
+			}
+		}
+	}
+	return httperror.NewMetadataError(err, map[string]interface{}{"failures": failures})
+}
+
+// partitionPendingPolicies splits the policies into those that would be
+// created and those that would be held for the consent of their destination
+// space
+func partitionPendingPolicies(policies []dryRunPolicy, consent policyConsent,
+	tokenData uaa_client.CheckTokenResponse) ([]dryRunPolicy, []dryRunPolicy, error) {
+	if len(policies) == 0 {
+		return nil, nil, nil
+	}
+
+	var storePolicies []store.Policy
+	for _, p := range policies {
+		storePolicies = append(storePolicies, p.policy)
+	}
+	_, pending, err := consent.PendingPolicies(storePolicies, tokenData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var created, held []dryRunPolicy
+	for _, p := range policies {
+		if isPendingPolicy(pending, p.policy) {
+			held = append(held, p)
+		} else {
+			created = append(created, p)
+		}
+	}
+	return created, held, nil
+}
+
+func isPendingPolicy(pending []store.PendingPolicy, policy store.Policy) bool {
+	for _, p := range pending {
+		if p.Policy.Equals(policy) {
+			return true
+		}
+	}
+	return false
+}
+
+// dryRunPoliciesBySource groups the policies by source in the order in which
+// the sources first appear
+func dryRunPoliciesBySource(policies []dryRunPolicy) [][]dryRunPolicy {
+	var groups [][]dryRunPolicy
+	indexes := make(map[string]int)
+	for _, p := range policies {
+		i, ok := indexes[p.policy.Source.ID]
+		if !ok {
+			i = len(groups)
+			indexes[p.policy.Source.ID] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], p)
+	}
+	return groups
+}
+
+func dryRunStorePolicies(policies []dryRunPolicy) []store.Policy {
+	var storePolicies []store.Policy
+	for _, p := range policies {
+		storePolicies = append(storePolicies, p.policy)
+	}
+	return storePolicies
+}
+
+func dryRunRawPolicies(policies []dryRunPolicy) []json.RawMessage {
+	rawPolicies := []json.RawMessage{}
+	for _, p := range policies {
+		rawPolicies = append(rawPolicies, p.raw)
+	}
+	return rawPolicies
+}
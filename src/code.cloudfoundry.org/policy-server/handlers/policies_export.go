@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/cf-networking-helpers/marshal"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type PoliciesExport struct {
+	Store         policyStore
+	Resolver      policyManifestResolver
+	Marshaler     marshal.Marshaler
+	ErrorResponse errorResponse
+}
+
+func NewPoliciesExport(store policyStore, resolver policyManifestResolver,
+	marshaler marshal.Marshaler, errorResponse errorResponse) *PoliciesExport {
+	return &PoliciesExport{
+		Store:         store,
+		Resolver:      resolver,
+		Marshaler:     marshaler,
+		ErrorResponse: errorResponse,
+	}
+}
+
+func (h *PoliciesExport) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("export-policies")
+
+	var policies []store.Policy
+	var err error
+	ids := parseIds(req.URL.Query())
+	if len(ids) == 0 {
+		policies, err = h.Store.All()
+	} else {
+		policies, err = h.Store.ByGuids(ids, ids, false)
+	}
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+		return
+	}
+	policies = unexpiredPolicies(policies, time.Now())
+
+	manifestPolicies, unnamed, err := h.Resolver.ManifestPolicies(policies)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "resolving names failed")
+		return
+	}
+	if len(unnamed) > 0 {
+		// the apps, spaces or orgs of these policies are gone, so the policies
+		// cannot be imported again and are left for the cleanup endpoint
+		logger.Info("skipped-unnamed-policies", lager.Data{"policies": unnamed})
+	}
+
+	responseBytes, err := h.Marshaler.Marshal(api.PolicyManifest{Policies: manifestPolicies})
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database marshalling failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write(responseBytes)
+}
@@ -0,0 +1,183 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	hfakes "code.cloudfoundry.org/cf-networking-helpers/fakes"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policies export handler", func() {
+	var (
+		request           *http.Request
+		handler           *handlers.PoliciesExport
+		resp              *httptest.ResponseRecorder
+		fakeStore         *fakes.PolicyStore
+		fakeResolver      *fakes.PolicyManifestResolver
+		fakeErrorResponse *fakes.ErrorResponse
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		marshaler         *hfakes.Marshaler
+		token             uaa_client.CheckTokenResponse
+		policies          []store.Policy
+	)
+
+	BeforeEach(func() {
+		var err error
+		request, err = http.NewRequest("GET", "/networking/v1/external/policies/export", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		marshaler = &hfakes.Marshaler{}
+		marshaler.MarshalStub = json.Marshal
+
+		expired := time.Now().Add(-time.Hour)
+		policies = []store.Policy{
+			{
+				Source:      store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{ID: "some-other-app-guid", Protocol: "tcp", Ports: store.Ports{Start: 8080, End: 8080}},
+			},
+			{
+				Source:      store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{ID: "yet-another-app-guid", Protocol: "tcp", Ports: store.Ports{Start: 8080, End: 8080}},
+				ExpiresAt:   &expired,
+			},
+		}
+
+		fakeStore = &fakes.PolicyStore{}
+		fakeStore.AllReturns(policies, nil)
+		fakeResolver = &fakes.PolicyManifestResolver{}
+		fakeResolver.ManifestPoliciesReturns([]api.ManifestPolicy{{
+			Source: api.ManifestGroup{Org: "o", Space: "s", App: "a"},
+			Destination: api.ManifestDestination{
+				ManifestGroup: api.ManifestGroup{Org: "o", Space: "s", App: "b"},
+				Protocol:      "tcp",
+				Ports:         api.Ports{Start: 8080, End: 8080},
+			},
+		}}, nil, nil)
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		token = uaa_client.CheckTokenResponse{
+			Scope:    []string{"network.admin"},
+			UserName: "some-admin",
+		}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("export-policies")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		handler = handlers.NewPoliciesExport(fakeStore, fakeResolver, marshaler, fakeErrorResponse)
+		resp = httptest.NewRecorder()
+	})
+
+	It("returns the unexpired policies as a manifest", func() {
+		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+		Expect(fakeStore.AllCallCount()).To(Equal(1))
+		Expect(fakeResolver.ManifestPoliciesCallCount()).To(Equal(1))
+		Expect(fakeResolver.ManifestPoliciesArgsForCall(0)).To(Equal(policies[:1]))
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{
+			"policies": [
+				{ "source": { "org": "o", "space": "s", "app": "a" }, "destination": { "org": "o", "space": "s", "app": "b", "protocol": "tcp", "ports": { "start": 8080, "end": 8080 } } }
+			]
+		}`))
+	})
+
+	Context("when ids are given", func() {
+		BeforeEach(func() {
+			request.URL.RawQuery = "id=some-app-guid,some-other-app-guid"
+			fakeStore.ByGuidsReturns(policies, nil)
+		})
+
+		It("exports the policies of those apps", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeStore.AllCallCount()).To(Equal(0))
+			Expect(fakeStore.ByGuidsCallCount()).To(Equal(1))
+			srcGuids, dstGuids, srcAndDst := fakeStore.ByGuidsArgsForCall(0)
+			Expect(srcGuids).To(Equal([]string{"some-app-guid", "some-other-app-guid"}))
+			Expect(dstGuids).To(Equal([]string{"some-app-guid", "some-other-app-guid"}))
+			Expect(srcAndDst).To(BeFalse())
+			Expect(resp.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("when some policies cannot be named", func() {
+		BeforeEach(func() {
+			fakeResolver.ManifestPoliciesReturns([]api.ManifestPolicy{}, policies[:1], nil)
+		})
+
+		It("logs the skipped policies", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Body).To(MatchJSON(`{"policies": []}`))
+			Expect(logger.Logs()).To(ContainElement(
+				LogsWith(lager.INFO, "test.export-policies.skipped-unnamed-policies"),
+			))
+		})
+	})
+
+	Context("when getting the policies fails", func() {
+		BeforeEach(func() {
+			fakeStore.AllReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeResolver.ManifestPoliciesCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database read failed"))
+		})
+	})
+
+	Context("when resolving the names fails", func() {
+		BeforeEach(func() {
+			fakeResolver.ManifestPoliciesReturns(nil, nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("resolving names failed"))
+		})
+	})
+
+	Context("when marshalling fails", func() {
+		BeforeEach(func() {
+			marshaler.MarshalStub = func(interface{}) ([]byte, error) {
+				return nil, errors.New("banana")
+			}
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database marshalling failed"))
+		})
+	})
+})
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"code.cloudfoundry.org/cf-networking-helpers/marshal"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type PoliciesImport struct {
+	Store         policyStore
+	Mapper        api.PolicyMapper
+	Resolver      policyManifestResolver
+	QuotaGuard    quotaGuard
+	Marshaler     marshal.Marshaler
+	ErrorResponse errorResponse
+}
+
+func NewPoliciesImport(store policyStore, mapper api.PolicyMapper, resolver policyManifestResolver,
+	quotaGuard quotaGuard, marshaler marshal.Marshaler, errorResponse errorResponse) *PoliciesImport {
+	return &PoliciesImport{
+		Store:         store,
+		Mapper:        mapper,
+		Resolver:      resolver,
+		QuotaGuard:    quotaGuard,
+		Marshaler:     marshaler,
+		ErrorResponse: errorResponse,
+	}
+}
+
+func (h *PoliciesImport) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("import-policies")
+	tokenData := getTokenData(req)
+
+	dryRun, err := parseDryRun(req.URL.Query())
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+
+	bodyBytes, err := io.ReadAll(req.Body)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, "failed reading request body")
+		return
+	}
+
+	var manifest api.PolicyManifest
+	err = json.Unmarshal(bodyBytes, &manifest)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, fmt.Sprintf("unmarshal json: %s", err))
+		return
+	}
+	if len(manifest.Policies) == 0 {
+		err := errors.New("missing policies")
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+
+	apiPolicies, err := h.Resolver.Policies(manifest.Policies)
+	if err != nil {
+		if _, ok := err.(InvalidManifestError); ok {
+			h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+			return
+		}
+		h.ErrorResponse.InternalServerError(logger, w, err, "resolving names failed")
+		return
+	}
+
+	// the policies go through the mapper so that they are validated exactly
+	// like policies that are created through the api
+	policiesBytes, err := h.Marshaler.Marshal(api.PoliciesPayload{Policies: apiPolicies})
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "marshal policies failed")
+		return
+	}
+	policies, err := h.Mapper.AsStorePolicy(policiesBytes)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, fmt.Sprintf("mapper: %s", err))
+		return
+	}
+
+	existing, err := existingImportPolicies(h.Store, policies)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+		return
+	}
+
+	payload := api.PolicyImportPayload{
+		Create:    []api.ManifestPolicy{},
+		Update:    []api.ManifestPolicy{},
+		Unchanged: []api.ManifestPolicy{},
+	}
+	var created, updated, planned []store.Policy
+	for i, policy := range policies {
+		stored, found := findStorePolicy(existing, policy)
+		switch {
+		case containsPolicy(planned, policy):
+			// a policy that is given more than once is only imported once
+			payload.Unchanged = append(payload.Unchanged, manifest.Policies[i])
+		case !found:
+			created = append(created, policy)
+			payload.Create = append(payload.Create, manifest.Policies[i])
+		case !stored.AttributesEqual(policy):
+			updated = append(updated, policy)
+			payload.Update = append(payload.Update, manifest.Policies[i])
+		default:
+			payload.Unchanged = append(payload.Unchanged, manifest.Policies[i])
+		}
+		planned = append(planned, policy)
+	}
+
+	if len(created) > 0 {
+		authorized, err := h.QuotaGuard.CheckAccess(created, tokenData)
+		if err != nil {
+			h.ErrorResponse.InternalServerError(logger, w, err, "check quota failed")
+			return
+		}
+		if !authorized {
+			err := errors.New("policy quota exceeded")
+			h.ErrorResponse.Forbidden(logger, w, err, err.Error())
+			return
+		}
+	}
+
+	if !dryRun {
+		err = h.Store.ImportWithEvent(created, updated, getActor(tokenData))
+		if err != nil {
+			h.ErrorResponse.InternalServerError(logger, w, err, "database write failed")
+			return
+		}
+		logger.Info("imported-policies", lager.Data{"created": len(created), "updated": len(updated), "userName": tokenData.UserName})
+	}
+
+	responseBytes, err := h.Marshaler.Marshal(payload)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database marshalling failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write(responseBytes)
+}
+
+// existingImportPolicies returns the stored policies between the sources and
+// destinations of the imported policies. Expired policies are included, so
+// that importing a policy with a later expiry updates the stored one.
+func existingImportPolicies(policyStore policyStore, policies []store.Policy) ([]store.Policy, error) {
+	var srcGuids, dstGuids []string
+	for _, p := range policies {
+		srcGuids = append(srcGuids, p.Source.ID)
+		dstGuids = append(dstGuids, p.Destination.ID)
+	}
+	return policyStore.ByGuids(srcGuids, dstGuids, true)
+}
+
+func findStorePolicy(policies []store.Policy, policy store.Policy) (store.Policy, bool) {
+	for _, p := range policies {
+		if p.Equals(policy) {
+			return p, true
+		}
+	}
+	return store.Policy{}, false
+}
@@ -0,0 +1,370 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	hfakes "code.cloudfoundry.org/cf-networking-helpers/fakes"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/api"
+	apifakes "code.cloudfoundry.org/policy-server/api/fakes"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policies import handler", func() {
+	var (
+		requestBody       string
+		request           *http.Request
+		handler           *handlers.PoliciesImport
+		resp              *httptest.ResponseRecorder
+		fakeStore         *fakes.PolicyStore
+		fakeMapper        *apifakes.PolicyMapper
+		fakeResolver      *fakes.PolicyManifestResolver
+		fakeQuotaGuard    *fakes.QuotaGuard
+		fakeErrorResponse *fakes.ErrorResponse
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		marshaler         *hfakes.Marshaler
+		token             uaa_client.CheckTokenResponse
+		storePolicy       func(dstID string, description string) store.Policy
+	)
+
+	BeforeEach(func() {
+		requestBody = `{
+			"policies": [
+				{ "source": { "org": "o", "space": "s", "app": "a" }, "destination": { "org": "o", "space": "s", "app": "b", "protocol": "tcp", "ports": { "start": 8080, "end": 8080 } } },
+				{ "source": { "org": "o", "space": "s", "app": "a" }, "destination": { "org": "o", "space": "s", "app": "c", "protocol": "tcp", "ports": { "start": 8080, "end": 8080 } }, "description": "new" },
+				{ "source": { "org": "o", "space": "s", "app": "a" }, "destination": { "org": "o", "space": "s", "app": "d", "protocol": "tcp", "ports": { "start": 8080, "end": 8080 } } }
+			]
+		}`
+
+		marshaler = &hfakes.Marshaler{}
+		marshaler.MarshalStub = json.Marshal
+
+		storePolicy = func(dstID string, description string) store.Policy {
+			return store.Policy{
+				Source: store.Source{ID: "a-guid"},
+				Destination: store.Destination{
+					ID:       dstID,
+					Protocol: "tcp",
+					Port:     8080,
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+				Metadata: store.Metadata{Description: description},
+			}
+		}
+
+		fakeResolver = &fakes.PolicyManifestResolver{}
+		fakeResolver.PoliciesReturns([]api.Policy{{}, {}, {}}, nil)
+		fakeMapper = &apifakes.PolicyMapper{}
+		fakeMapper.AsStorePolicyReturns([]store.Policy{
+			storePolicy("b-guid", ""),
+			storePolicy("c-guid", "new"),
+			storePolicy("d-guid", ""),
+		}, nil)
+
+		fakeStore = &fakes.PolicyStore{}
+		existingPolicy := storePolicy("c-guid", "old")
+		existingPolicy.Source.Tag = "0001"
+		fakeStore.ByGuidsReturns([]store.Policy{storePolicy("b-guid", ""), existingPolicy}, nil)
+
+		fakeQuotaGuard = &fakes.QuotaGuard{}
+		fakeQuotaGuard.CheckAccessReturns(true, nil)
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		token = uaa_client.CheckTokenResponse{
+			Scope:    []string{"network.admin"},
+			UserName: "some-admin",
+		}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("import-policies")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		handler = handlers.NewPoliciesImport(fakeStore, fakeMapper, fakeResolver, fakeQuotaGuard, marshaler, fakeErrorResponse)
+		resp = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		request, err = http.NewRequest("POST", "/networking/v1/external/policies/import", bytes.NewBuffer([]byte(requestBody)))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("creates the new policies, updates the changed ones and returns the diff", func() {
+		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+		Expect(fakeResolver.PoliciesCallCount()).To(Equal(1))
+		Expect(fakeResolver.PoliciesArgsForCall(0)).To(HaveLen(3))
+		Expect(fakeMapper.AsStorePolicyCallCount()).To(Equal(1))
+		Expect(fakeMapper.AsStorePolicyArgsForCall(0)).To(MatchJSON(`{"total_policies": 0, "policies": [
+			{ "source": { "id": "" }, "destination": { "id": "", "protocol": "", "ports": { "start": 0, "end": 0 } } },
+			{ "source": { "id": "" }, "destination": { "id": "", "protocol": "", "ports": { "start": 0, "end": 0 } } },
+			{ "source": { "id": "" }, "destination": { "id": "", "protocol": "", "ports": { "start": 0, "end": 0 } } }
+		]}`))
+
+		srcGuids, dstGuids, srcAndDst := fakeStore.ByGuidsArgsForCall(0)
+		Expect(srcGuids).To(Equal([]string{"a-guid", "a-guid", "a-guid"}))
+		Expect(dstGuids).To(Equal([]string{"b-guid", "c-guid", "d-guid"}))
+		Expect(srcAndDst).To(BeTrue())
+
+		Expect(fakeQuotaGuard.CheckAccessCallCount()).To(Equal(1))
+		quotaPolicies, quotaToken := fakeQuotaGuard.CheckAccessArgsForCall(0)
+		Expect(quotaPolicies).To(Equal([]store.Policy{storePolicy("d-guid", "")}))
+		Expect(quotaToken).To(Equal(token))
+
+		Expect(fakeStore.ImportWithEventCallCount()).To(Equal(1))
+		created, updated, actor := fakeStore.ImportWithEventArgsForCall(0)
+		Expect(created).To(Equal([]store.Policy{storePolicy("d-guid", "")}))
+		Expect(updated).To(Equal([]store.Policy{storePolicy("c-guid", "new")}))
+		Expect(actor).To(Equal(store.Actor{Name: "some-admin"}))
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{
+			"create": [
+				{ "source": { "org": "o", "space": "s", "app": "a" }, "destination": { "org": "o", "space": "s", "app": "d", "protocol": "tcp", "ports": { "start": 8080, "end": 8080 } } }
+			],
+			"update": [
+				{ "source": { "org": "o", "space": "s", "app": "a" }, "destination": { "org": "o", "space": "s", "app": "c", "protocol": "tcp", "ports": { "start": 8080, "end": 8080 } }, "description": "new" }
+			],
+			"unchanged": [
+				{ "source": { "org": "o", "space": "s", "app": "a" }, "destination": { "org": "o", "space": "s", "app": "b", "protocol": "tcp", "ports": { "start": 8080, "end": 8080 } } }
+			]
+		}`))
+		Expect(logger.Logs()).To(ContainElement(SatisfyAll(
+			LogsWith(lager.INFO, "test.import-policies.imported-policies"),
+			HaveLogData(SatisfyAll(
+				HaveKeyWithValue("created", BeNumerically("==", 1)),
+				HaveKeyWithValue("updated", BeNumerically("==", 1)),
+				HaveKeyWithValue("userName", "some-admin"),
+			)),
+		)))
+	})
+
+	Context("when it is a dry run", func() {
+		JustBeforeEach(func() {
+			request.URL.RawQuery = "dry_run=true"
+		})
+
+		It("returns the diff without changing anything", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeQuotaGuard.CheckAccessCallCount()).To(Equal(1))
+			Expect(fakeStore.ImportWithEventCallCount()).To(Equal(0))
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			var payload api.PolicyImportPayload
+			Expect(json.Unmarshal(resp.Body.Bytes(), &payload)).To(Succeed())
+			Expect(payload.Create).To(HaveLen(1))
+			Expect(payload.Update).To(HaveLen(1))
+			Expect(payload.Unchanged).To(HaveLen(1))
+		})
+	})
+
+	Context("when every policy is unchanged", func() {
+		BeforeEach(func() {
+			fakeStore.ByGuidsReturns([]store.Policy{
+				storePolicy("b-guid", ""),
+				storePolicy("c-guid", "new"),
+				storePolicy("d-guid", ""),
+			}, nil)
+		})
+
+		It("does not check the quota and returns empty create and update lists", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeQuotaGuard.CheckAccessCallCount()).To(Equal(0))
+			Expect(fakeStore.ImportWithEventCallCount()).To(Equal(1))
+			created, updated, _ := fakeStore.ImportWithEventArgsForCall(0)
+			Expect(created).To(BeEmpty())
+			Expect(updated).To(BeEmpty())
+
+			var payload map[string][]interface{}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &payload)).To(Succeed())
+			Expect(payload["create"]).To(Equal([]interface{}{}))
+			Expect(payload["update"]).To(Equal([]interface{}{}))
+			Expect(payload["unchanged"]).To(HaveLen(3))
+		})
+	})
+
+	Context("when the manifest repeats a policy", func() {
+		BeforeEach(func() {
+			fakeMapper.AsStorePolicyReturns([]store.Policy{
+				storePolicy("d-guid", ""),
+				storePolicy("d-guid", ""),
+				storePolicy("d-guid", ""),
+			}, nil)
+		})
+
+		It("creates it once", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			created, _, _ := fakeStore.ImportWithEventArgsForCall(0)
+			Expect(created).To(Equal([]store.Policy{storePolicy("d-guid", "")}))
+		})
+	})
+
+	Context("when the dry_run parameter is invalid", func() {
+		JustBeforeEach(func() {
+			request.URL.RawQuery = "dry_run=maybe"
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(err).To(MatchError("invalid value for 'dry_run' parameter: must be true or false"))
+			Expect(description).To(Equal("invalid value for 'dry_run' parameter: must be true or false"))
+		})
+	})
+
+	Context("when the manifest is not valid json", func() {
+		BeforeEach(func() {
+			requestBody = "not-json"
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeResolver.PoliciesCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			l, w, _, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(description).To(HavePrefix("unmarshal json: "))
+		})
+	})
+
+	Context("when the manifest has no policies", func() {
+		BeforeEach(func() {
+			requestBody = `{"policies": []}`
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(err).To(MatchError("missing policies"))
+			Expect(description).To(Equal("missing policies"))
+		})
+	})
+
+	Context("when the names in the manifest cannot be resolved", func() {
+		BeforeEach(func() {
+			fakeResolver.PoliciesReturns(nil, handlers.InvalidManifestError{Problems: []string{"cannot find org 'o'"}})
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(err).To(MatchError("cannot find org 'o'"))
+			Expect(description).To(Equal("cannot find org 'o'"))
+		})
+	})
+
+	Context("when resolving the names fails", func() {
+		BeforeEach(func() {
+			fakeResolver.PoliciesReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("resolving names failed"))
+		})
+	})
+
+	Context("when the mapper fails", func() {
+		BeforeEach(func() {
+			fakeMapper.AsStorePolicyReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("mapper: banana"))
+		})
+	})
+
+	Context("when getting the existing policies fails", func() {
+		BeforeEach(func() {
+			fakeStore.ByGuidsReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database read failed"))
+		})
+	})
+
+	Context("when the quota is exceeded", func() {
+		BeforeEach(func() {
+			fakeQuotaGuard.CheckAccessReturns(false, nil)
+		})
+
+		It("calls the forbidden handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeStore.ImportWithEventCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.ForbiddenCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.ForbiddenArgsForCall(0)
+			Expect(err).To(MatchError("policy quota exceeded"))
+			Expect(description).To(Equal("policy quota exceeded"))
+		})
+	})
+
+	Context("when checking the quota fails", func() {
+		BeforeEach(func() {
+			fakeQuotaGuard.CheckAccessReturns(false, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("check quota failed"))
+		})
+	})
+
+	Context("when importing fails", func() {
+		BeforeEach(func() {
+			fakeStore.ImportWithEventReturns(errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database write failed"))
+		})
+	})
+})
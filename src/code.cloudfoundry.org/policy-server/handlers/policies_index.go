@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"net/url"
 	"strings"
@@ -43,18 +44,28 @@ func (h *PoliciesIndex) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	sourceIDs := parseSourceIds(queryValues)
 	destIDs := parseDestIds(queryValues)
 
+	page, err := parsePage(queryValues)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+
+	var labelSelector api.LabelSelector
+	if _, ok := queryValues["label_selector"]; ok {
+		labelSelector, err = api.ParseLabelSelector(queryValues.Get("label_selector"))
+		if err != nil {
+			h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+			return
+		}
+	}
+	paginated := page.Limit > 0 || page.From > 0
+
 	var storePolicies []store.Policy
-	var err error
-	if len(ids) > 0 {
-		storePolicies, err = h.Store.ByGuids(ids, ids, false)
-	} else if len(sourceIDs) > 0 && len(destIDs) > 0 {
-		storePolicies, err = h.Store.ByGuids(sourceIDs, destIDs, true)
-	} else if len(sourceIDs) > 0 {
-		storePolicies, err = h.Store.ByGuids(sourceIDs, []string{}, false)
-	} else if len(destIDs) > 0 {
-		storePolicies, err = h.Store.ByGuids([]string{}, destIDs, false)
+	var pagination store.Pagination
+	if paginated {
+		storePolicies, pagination, err = h.paginatedPolicies(ids, sourceIDs, destIDs, page)
 	} else {
-		storePolicies, err = h.Store.All()
+		storePolicies, err = h.policies(ids, sourceIDs, destIDs)
 	}
 
 	if err != nil {
@@ -68,12 +79,21 @@ func (h *PoliciesIndex) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if labelSelector != nil {
+		policies = filterByLabels(policies, labelSelector)
+	}
+
 	for i := range policies {
 		policies[i].Source.Tag = ""
 		policies[i].Destination.Tag = ""
 	}
 
-	bytes, err := h.Mapper.AsBytes(policies)
+	var bytes []byte
+	if paginated {
+		bytes, err = h.Mapper.AsBytesWithPagination(policies, pagination)
+	} else {
+		bytes, err = h.Mapper.AsBytes(policies)
+	}
 	if err != nil {
 		h.ErrorResponse.InternalServerError(logger, w, err, "map policy as bytes failed")
 		return
@@ -84,6 +104,54 @@ func (h *PoliciesIndex) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	w.Write(bytes)
 }
 
+func (h *PoliciesIndex) policies(ids, sourceIDs, destIDs []string) ([]store.Policy, error) {
+	if len(ids) > 0 {
+		return h.Store.ByGuids(ids, ids, false)
+	} else if len(sourceIDs) > 0 && len(destIDs) > 0 {
+		return h.Store.ByGuids(sourceIDs, destIDs, true)
+	} else if len(sourceIDs) > 0 {
+		return h.Store.ByGuids(sourceIDs, []string{}, false)
+	} else if len(destIDs) > 0 {
+		return h.Store.ByGuids([]string{}, destIDs, false)
+	}
+	return h.Store.All()
+}
+
+func (h *PoliciesIndex) paginatedPolicies(ids, sourceIDs, destIDs []string, page store.Page) ([]store.Policy, store.Pagination, error) {
+	if len(ids) > 0 {
+		return h.Store.ByGuidsPaginated(ids, ids, false, page)
+	} else if len(sourceIDs) > 0 && len(destIDs) > 0 {
+		return h.Store.ByGuidsPaginated(sourceIDs, destIDs, true, page)
+	} else if len(sourceIDs) > 0 {
+		return h.Store.ByGuidsPaginated(sourceIDs, []string{}, false, page)
+	} else if len(destIDs) > 0 {
+		return h.Store.ByGuidsPaginated([]string{}, destIDs, false, page)
+	}
+	return h.Store.AllPaginated(page)
+}
+
+func filterByLabels(policies []store.Policy, labelSelector api.LabelSelector) []store.Policy {
+	filtered := []store.Policy{}
+	for _, policy := range policies {
+		if labelSelector.Matches(policy.Metadata.Labels) {
+			filtered = append(filtered, policy)
+		}
+	}
+	return filtered
+}
+
+func parsePage(queryValues url.Values) (store.Page, error) {
+	limit, err := parseIntQueryValue(queryValues, "limit")
+	if err != nil || limit < 0 {
+		return store.Page{}, errors.New("invalid value for 'limit' parameter")
+	}
+	from, err := parseIntQueryValue(queryValues, "from")
+	if err != nil || from < 0 {
+		return store.Page{}, errors.New("invalid value for 'from' parameter")
+	}
+	return store.Page{Limit: limit, From: from}, nil
+}
+
 func parseSourceIds(queryValues url.Values) []string {
 	var ids []string
 	idList, ok := queryValues["source_id"]
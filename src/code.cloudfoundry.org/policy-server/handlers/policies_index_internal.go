@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"fmt"
+	"hash/fnv"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
+	"time"
 
 	"code.cloudfoundry.org/lager/v3"
 	"code.cloudfoundry.org/policy-server/api"
@@ -33,21 +37,36 @@ func (h *PoliciesIndexInternal) ServeHTTP(w http.ResponseWriter, req *http.Reque
 
 	queryValues := req.URL.Query()
 	ids := parseIds(queryValues)
+	features := parsePolicyFeatures(queryValues)
+	now := time.Now()
 
-	var policies []store.Policy
-	var err error
-	if len(ids) == 0 {
-		policies, err = h.Store.All()
-	} else {
-		policies, err = h.Store.ByGuids(ids, ids, false)
+	etag, err := h.policiesETag(ids, features, now)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+		return
+	}
+	w.Header().Set("ETag", etag)
+	if etagMatches(req.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
 	}
 
+	policies, err := h.policiesOf(ids)
 	if err != nil {
 		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
 		return
 	}
 
-	bytes, err := h.PolicyMapper.AsBytes(policies)
+	quarantined, err := h.Store.Quarantined()
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+		return
+	}
+	// expired policies are only deleted when the policy cleaner next runs, and
+	// must not override allow policies until then
+	policies = features.supportedStorePolicies(unexpiredPolicies(unquarantinedPolicies(policies, quarantined), now))
+
+	bytes, err := h.PolicyMapper.AsBytes(denyPoliciesFirst(policies))
 	if err != nil {
 		h.ErrorResponse.InternalServerError(logger, w, err, "map policies as bytes failed")
 		return
@@ -58,6 +77,113 @@ func (h *PoliciesIndexInternal) ServeHTTP(w http.ResponseWriter, req *http.Reque
 	w.Write(bytes)
 }
 
+// policiesOf lists the policies of the ids, or every policy when there are no
+// ids. Policies from spaces and orgs are listed as a policy from each of their
+// apps, so the spaces and orgs that the ids are in are read as well.
+func (h *PoliciesIndexInternal) policiesOf(ids []string) ([]store.Policy, error) {
+	if len(ids) == 0 {
+		policies, err := h.Store.All()
+		if err != nil {
+			return nil, err
+		}
+		return expandGroupSources(h.Store, policies)
+	}
+
+	memberGroups, err := h.Store.MemberGroups(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := h.Store.ByGuids(append(append([]string{}, ids...), memberGroups...), ids, false)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err = expandGroupSources(h.Store, policies)
+	if err != nil {
+		return nil, err
+	}
+	return policiesOfApps(policies, ids), nil
+}
+
+// policiesETag identifies the response for the given ids and features without
+// reading the policies. Every change to the policies or the quarantined apps updates last
+// updated, but expired policies are only filtered out when they are listed, so
+// the number of expired policies is part of the tag as well.
+func (h *PoliciesIndexInternal) policiesETag(ids []string, features policyFeatures, now time.Time) (string, error) {
+	lastUpdated, err := h.Store.LastUpdated()
+	if err != nil {
+		return "", err
+	}
+
+	expired, err := h.Store.ExpiredCount(now)
+	if err != nil {
+		return "", err
+	}
+
+	sortedIds := append([]string{}, ids...)
+	sort.Strings(sortedIds)
+	idsHash := fnv.New64a()
+	// #nosec G104 - writing to a hash never returns an error
+	idsHash.Write([]byte(strings.Join(sortedIds, ",") + ";" + features.String()))
+
+	return fmt.Sprintf(`"%d-%d-%x"`, lastUpdated, expired, idsHash.Sum64()), nil
+}
+
+// etagMatches checks an If-None-Match header against the etag, using the weak
+// comparison that RFC 7232 requires for it
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func unexpiredPolicies(policies []store.Policy, now time.Time) []store.Policy {
+	unexpired := []store.Policy{}
+	for _, policy := range policies {
+		if !policy.IsExpired(now) {
+			unexpired = append(unexpired, policy)
+		}
+	}
+	return unexpired
+}
+
+// unquarantinedPolicies drops the policies whose source or destination is a
+// quarantined app, which cuts the app off until it is released
+func unquarantinedPolicies(policies []store.Policy, quarantinedGuids []string) []store.Policy {
+	if len(quarantinedGuids) == 0 {
+		return policies
+	}
+
+	quarantined := map[string]bool{}
+	for _, guid := range quarantinedGuids {
+		quarantined[guid] = true
+	}
+
+	result := []store.Policy{}
+	for _, policy := range policies {
+		if quarantined[policy.Source.ID] || quarantined[policy.Destination.ID] {
+			continue
+		}
+		result = append(result, policy)
+	}
+	return result
+}
+
+// denyPoliciesFirst orders deny policies ahead of allow policies, which is the
+// order that policy agents apply them in. A deny policy takes precedence over
+// every allow policy that matches the same traffic.
+func denyPoliciesFirst(policies []store.Policy) []store.Policy {
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].IsDeny() && !policies[j].IsDeny()
+	})
+	return policies
+}
+
 func parseIds(queryValues url.Values) []string {
 	var ids []string
 	idList, ok := queryValues["id"]
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	"code.cloudfoundry.org/lager/v3"
 	"code.cloudfoundry.org/lager/v3/lagertest"
@@ -124,6 +125,517 @@ var _ = Describe("PoliciesIndexInternal", func() {
 		})
 	})
 
+	Context("when some policies have expired", func() {
+		var unexpiredPolicy store.Policy
+
+		BeforeEach(func() {
+			expired := time.Now().Add(-time.Minute)
+			notExpired := time.Now().Add(time.Hour)
+			unexpiredPolicy = store.Policy{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+				ExpiresAt: &notExpired,
+			}
+			fakeStore.AllReturns([]store.Policy{
+				unexpiredPolicy,
+				{
+					Source: store.Source{ID: "another-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "tcp",
+						Ports:    store.Ports{Start: 1234, End: 1234},
+					},
+					ExpiresAt: &expired,
+				},
+			}, nil)
+		})
+
+		It("does not return the expired policies", func() {
+			request, err := http.NewRequest("GET", "/networking/v0/internal/policies", nil)
+			Expect(err).NotTo(HaveOccurred())
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakePolicyMapper.AsBytesCallCount()).To(Equal(1))
+			Expect(fakePolicyMapper.AsBytesArgsForCall(0)).To(Equal([]store.Policy{unexpiredPolicy}))
+			Expect(resp.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("when some policies need features that the agent may not support", func() {
+		var tcpPolicy, icmpPolicy, allPolicy store.Policy
+
+		BeforeEach(func() {
+			tcpPolicy = store.Policy{
+				Source:      store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{ID: "some-other-app-guid", Protocol: "tcp", Ports: store.Ports{Start: 8080, End: 8080}},
+			}
+			icmpPolicy = store.Policy{
+				Source:      store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{ID: "some-other-app-guid", Protocol: "icmp", ICMPType: 8, ICMPCode: -1},
+			}
+			allPolicy = store.Policy{
+				Source:      store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{ID: "some-other-app-guid", Protocol: "all", Ports: store.Ports{Start: 1, End: 65535}},
+			}
+			fakeStore.AllReturns([]store.Policy{tcpPolicy, icmpPolicy, allPolicy}, nil)
+		})
+
+		It("leaves them out by default", func() {
+			request, err := http.NewRequest("GET", "/networking/v1/internal/policies", nil)
+			Expect(err).NotTo(HaveOccurred())
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakePolicyMapper.AsBytesArgsForCall(0)).To(Equal([]store.Policy{tcpPolicy}))
+		})
+
+		It("returns the policies of the features that the agent asks for", func() {
+			request, err := http.NewRequest("GET", "/networking/v1/internal/policies?features=icmp", nil)
+			Expect(err).NotTo(HaveOccurred())
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+			Expect(fakePolicyMapper.AsBytesArgsForCall(0)).To(Equal([]store.Policy{tcpPolicy, icmpPolicy}))
+
+			request, err = http.NewRequest("GET", "/networking/v1/internal/policies?features=icmp,all", nil)
+			Expect(err).NotTo(HaveOccurred())
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+			Expect(fakePolicyMapper.AsBytesArgsForCall(1)).To(Equal([]store.Policy{tcpPolicy, icmpPolicy, allPolicy}))
+		})
+	})
+
+	Context("when some policies deny traffic", func() {
+		var allowPolicy, denyPolicy store.Policy
+
+		BeforeEach(func() {
+			allowPolicy = store.Policy{
+				Source: store.Source{ID: "another-app-guid"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+			}
+			denyPolicy = store.Policy{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+				Action: store.PolicyActionDeny,
+			}
+			fakeStore.AllReturns([]store.Policy{allowPolicy, denyPolicy}, nil)
+		})
+
+		It("leaves them out when the agent does not support them", func() {
+			request, err := http.NewRequest("GET", "/networking/v1/internal/policies", nil)
+			Expect(err).NotTo(HaveOccurred())
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakePolicyMapper.AsBytesArgsForCall(0)).To(Equal([]store.Policy{allowPolicy}))
+		})
+
+		Context("when a deny policy overrides allow policies", func() {
+			var overriddenPolicy, allPolicy, otherPortPolicy store.Policy
+
+			BeforeEach(func() {
+				overriddenPolicy = store.Policy{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "tcp",
+						Ports:    store.Ports{Start: 8000, End: 9000},
+					},
+				}
+				allPolicy = store.Policy{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "all",
+						Ports:    store.Ports{Start: 1, End: 65535},
+					},
+				}
+				otherPortPolicy = store.Policy{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "tcp",
+						Ports:    store.Ports{Start: 9090, End: 9090},
+					},
+				}
+				fakeStore.AllReturns([]store.Policy{allowPolicy, denyPolicy, overriddenPolicy, allPolicy, otherPortPolicy}, nil)
+			})
+
+			It("leaves out the allow policies that it overrides when the agent does not support deny", func() {
+				request, err := http.NewRequest("GET", "/networking/v1/internal/policies?features=all", nil)
+				Expect(err).NotTo(HaveOccurred())
+				MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+				Expect(fakePolicyMapper.AsBytesArgsForCall(0)).To(Equal([]store.Policy{allowPolicy, otherPortPolicy}))
+			})
+
+			It("returns the allow policies when the agent supports deny", func() {
+				request, err := http.NewRequest("GET", "/networking/v1/internal/policies?features=all,deny", nil)
+				Expect(err).NotTo(HaveOccurred())
+				MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+				Expect(fakePolicyMapper.AsBytesArgsForCall(0)).To(Equal([]store.Policy{denyPolicy, allowPolicy, overriddenPolicy, allPolicy, otherPortPolicy}))
+			})
+
+			Context("when the deny policy has expired", func() {
+				BeforeEach(func() {
+					expired := time.Now().Add(-time.Minute)
+					denyPolicy.ExpiresAt = &expired
+					fakeStore.AllReturns([]store.Policy{allowPolicy, denyPolicy, overriddenPolicy, allPolicy, otherPortPolicy}, nil)
+				})
+
+				It("no longer leaves out the allow policies", func() {
+					request, err := http.NewRequest("GET", "/networking/v1/internal/policies?features=all", nil)
+					Expect(err).NotTo(HaveOccurred())
+					MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+					Expect(fakePolicyMapper.AsBytesArgsForCall(0)).To(Equal([]store.Policy{allowPolicy, overriddenPolicy, allPolicy, otherPortPolicy}))
+				})
+			})
+		})
+
+		It("returns the deny policies first", func() {
+			request, err := http.NewRequest("GET", "/networking/v1/internal/policies?features=deny", nil)
+			Expect(err).NotTo(HaveOccurred())
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakePolicyMapper.AsBytesCallCount()).To(Equal(1))
+			Expect(fakePolicyMapper.AsBytesArgsForCall(0)).To(Equal([]store.Policy{denyPolicy, allowPolicy}))
+			Expect(resp.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("when an app is quarantined", func() {
+		var otherPolicy store.Policy
+
+		BeforeEach(func() {
+			otherPolicy = store.Policy{
+				Source: store.Source{ID: "another-app-guid"},
+				Destination: store.Destination{
+					ID:       "yet-another-app-guid",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+			}
+			fakeStore.AllReturns([]store.Policy{
+				{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "another-app-guid",
+						Protocol: "tcp",
+						Ports:    store.Ports{Start: 8080, End: 8080},
+					},
+				},
+				otherPolicy,
+				{
+					Source: store.Source{ID: "yet-another-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-app-guid",
+						Protocol: "udp",
+						Ports:    store.Ports{Start: 53, End: 53},
+					},
+				},
+			}, nil)
+			fakeStore.QuarantinedReturns([]string{"some-app-guid"}, nil)
+		})
+
+		It("does not return the policies of the app", func() {
+			request, err := http.NewRequest("GET", "/networking/v1/internal/policies", nil)
+			Expect(err).NotTo(HaveOccurred())
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakePolicyMapper.AsBytesCallCount()).To(Equal(1))
+			Expect(fakePolicyMapper.AsBytesArgsForCall(0)).To(Equal([]store.Policy{otherPolicy}))
+			Expect(resp.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Describe("ETag", func() {
+		var etagFor = func(url string) string {
+			request, err := http.NewRequest("GET", url, nil)
+			Expect(err).NotTo(HaveOccurred())
+			resp := httptest.NewRecorder()
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			return resp.Header().Get("ETag")
+		}
+
+		BeforeEach(func() {
+			fakeStore.LastUpdatedReturns(1700000000123456000, nil)
+			fakeStore.ExpiredCountReturns(2, nil)
+		})
+
+		It("sets an etag derived from the last updated version", func() {
+			etag := etagFor("/networking/v1/internal/policies?id=some-app-guid,another-app-guid")
+			Expect(etag).To(MatchRegexp(`^"1700000000123456000-2-[0-9a-f]+"$`))
+
+			Expect(fakeStore.ExpiredCountCallCount()).To(Equal(1))
+			Expect(fakeStore.ExpiredCountArgsForCall(0)).To(BeTemporally("~", time.Now(), time.Second))
+		})
+
+		It("does not depend on the order of the ids", func() {
+			Expect(etagFor("/networking/v1/internal/policies?id=some-app-guid,another-app-guid")).To(
+				Equal(etagFor("/networking/v1/internal/policies?id=another-app-guid,some-app-guid")))
+		})
+
+		It("changes with the ids", func() {
+			Expect(etagFor("/networking/v1/internal/policies?id=some-app-guid")).NotTo(
+				Equal(etagFor("/networking/v1/internal/policies?id=another-app-guid")))
+			Expect(etagFor("/networking/v1/internal/policies?id=some-app-guid")).NotTo(
+				Equal(etagFor("/networking/v1/internal/policies")))
+		})
+
+		It("changes with the features", func() {
+			Expect(etagFor("/networking/v1/internal/policies?features=icmp")).NotTo(
+				Equal(etagFor("/networking/v1/internal/policies")))
+		})
+
+		It("changes when the policies are updated", func() {
+			etag := etagFor("/networking/v1/internal/policies")
+			fakeStore.LastUpdatedReturns(1700000000999999000, nil)
+			Expect(etagFor("/networking/v1/internal/policies")).NotTo(Equal(etag))
+		})
+
+		It("changes when a policy expires", func() {
+			etag := etagFor("/networking/v1/internal/policies")
+			fakeStore.ExpiredCountReturns(3, nil)
+			Expect(etagFor("/networking/v1/internal/policies")).NotTo(Equal(etag))
+		})
+
+		Context("when the request has a matching If-None-Match header", func() {
+			var etag string
+
+			BeforeEach(func() {
+				etag = etagFor("/networking/v1/internal/policies?id=some-app-guid")
+				fakeStore = &storeFakes.Store{}
+				fakeStore.LastUpdatedReturns(1700000000123456000, nil)
+				fakeStore.ExpiredCountReturns(2, nil)
+				handler.Store = fakeStore
+			})
+
+			It("returns not modified without reading the policies", func() {
+				request, err := http.NewRequest("GET", "/networking/v1/internal/policies?id=some-app-guid", nil)
+				Expect(err).NotTo(HaveOccurred())
+				request.Header.Set("If-None-Match", etag)
+				MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+				Expect(resp.Code).To(Equal(http.StatusNotModified))
+				Expect(resp.Header().Get("ETag")).To(Equal(etag))
+				Expect(resp.Body.Len()).To(Equal(0))
+				Expect(fakeStore.ByGuidsCallCount()).To(Equal(0))
+				Expect(fakeStore.QuarantinedCallCount()).To(Equal(0))
+			})
+
+			It("accepts weak and listed etags", func() {
+				request, err := http.NewRequest("GET", "/networking/v1/internal/policies?id=some-app-guid", nil)
+				Expect(err).NotTo(HaveOccurred())
+				request.Header.Set("If-None-Match", `"something-else", W/`+etag)
+				MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+				Expect(resp.Code).To(Equal(http.StatusNotModified))
+			})
+		})
+
+		Context("when the If-None-Match header does not match", func() {
+			It("returns the policies", func() {
+				request, err := http.NewRequest("GET", "/networking/v1/internal/policies", nil)
+				Expect(err).NotTo(HaveOccurred())
+				request.Header.Set("If-None-Match", `"1700000000000000000-2-0"`)
+				MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.Bytes()).To(Equal(expectedResponseBody))
+			})
+		})
+	})
+
+	Context("when getting last updated fails", func() {
+		BeforeEach(func() {
+			fakeStore.LastUpdatedReturns(0, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			request, err := http.NewRequest("GET", "/networking/v1/internal/policies", nil)
+			Expect(err).NotTo(HaveOccurred())
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeStore.AllCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database read failed"))
+		})
+	})
+
+	Context("when counting the expired policies fails", func() {
+		BeforeEach(func() {
+			fakeStore.ExpiredCountReturns(0, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			request, err := http.NewRequest("GET", "/networking/v1/internal/policies", nil)
+			Expect(err).NotTo(HaveOccurred())
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeStore.AllCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database read failed"))
+		})
+	})
+
+	Context("when listing the quarantined apps fails", func() {
+		BeforeEach(func() {
+			fakeStore.QuarantinedReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			request, err := http.NewRequest("GET", "/networking/v1/internal/policies", nil)
+			Expect(err).NotTo(HaveOccurred())
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database read failed"))
+		})
+	})
+
+	Context("when a policy is from a space", func() {
+		var spacePolicy, appPolicy store.Policy
+
+		BeforeEach(func() {
+			spacePolicy = store.Policy{
+				Source: store.Source{ID: "some-space-guid", Tag: "0005", Type: "space"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+			}
+			appPolicy = store.Policy{
+				Source: store.Source{ID: "another-app-guid"},
+				Destination: store.Destination{
+					ID:       "yet-another-app-guid",
+					Protocol: "udp",
+					Ports:    store.Ports{Start: 53, End: 53},
+				},
+			}
+			fakeStore.AllReturns([]store.Policy{spacePolicy, appPolicy}, nil)
+			fakeStore.ByGuidsReturns([]store.Policy{spacePolicy, appPolicy}, nil)
+			fakeStore.GroupMembersReturns([]store.GroupMember{
+				{GroupGUID: "some-space-guid", AppGUID: "some-app-guid", AppTag: "0001"},
+				{GroupGUID: "some-space-guid", AppGUID: "another-app-guid", AppTag: "0002"},
+			}, nil)
+			fakeStore.MemberGroupsReturns([]string{"some-space-guid"}, nil)
+		})
+
+		It("returns the policy from each app of the space", func() {
+			request, err := http.NewRequest("GET", "/networking/v1/internal/policies", nil)
+			Expect(err).NotTo(HaveOccurred())
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeStore.GroupMembersCallCount()).To(Equal(1))
+			Expect(fakeStore.GroupMembersArgsForCall(0)).To(Equal([]string{"some-space-guid"}))
+
+			fromSomeApp := spacePolicy
+			fromSomeApp.Source = store.Source{ID: "some-app-guid", Tag: "0001"}
+			fromAnotherApp := spacePolicy
+			fromAnotherApp.Source = store.Source{ID: "another-app-guid", Tag: "0002"}
+			Expect(fakePolicyMapper.AsBytesArgsForCall(0)).To(Equal([]store.Policy{fromSomeApp, fromAnotherApp, appPolicy}))
+		})
+
+		It("reads the policies of the spaces that the ids are in, and returns those of the ids", func() {
+			request, err := http.NewRequest("GET", "/networking/v1/internal/policies?id=some-app-guid", nil)
+			Expect(err).NotTo(HaveOccurred())
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeStore.MemberGroupsArgsForCall(0)).To(Equal([]string{"some-app-guid"}))
+			srcGuids, dstGuids, _ := fakeStore.ByGuidsArgsForCall(0)
+			Expect(srcGuids).To(Equal([]string{"some-app-guid", "some-space-guid"}))
+			Expect(dstGuids).To(Equal([]string{"some-app-guid"}))
+
+			fromSomeApp := spacePolicy
+			fromSomeApp.Source = store.Source{ID: "some-app-guid", Tag: "0001"}
+			Expect(fakePolicyMapper.AsBytesArgsForCall(0)).To(Equal([]store.Policy{fromSomeApp}))
+		})
+
+		It("returns the policy from every app of the space to a destination in the ids", func() {
+			request, err := http.NewRequest("GET", "/networking/v1/internal/policies?id=some-other-app-guid", nil)
+			Expect(err).NotTo(HaveOccurred())
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			fromSomeApp := spacePolicy
+			fromSomeApp.Source = store.Source{ID: "some-app-guid", Tag: "0001"}
+			fromAnotherApp := spacePolicy
+			fromAnotherApp.Source = store.Source{ID: "another-app-guid", Tag: "0002"}
+			Expect(fakePolicyMapper.AsBytesArgsForCall(0)).To(Equal([]store.Policy{fromSomeApp, fromAnotherApp}))
+		})
+
+		Context("when the space has no apps", func() {
+			BeforeEach(func() {
+				fakeStore.GroupMembersReturns([]store.GroupMember{}, nil)
+			})
+
+			It("leaves the policy out", func() {
+				request, err := http.NewRequest("GET", "/networking/v1/internal/policies", nil)
+				Expect(err).NotTo(HaveOccurred())
+				MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+				Expect(fakePolicyMapper.AsBytesArgsForCall(0)).To(Equal([]store.Policy{appPolicy}))
+			})
+		})
+
+		Context("when getting the apps of the space fails", func() {
+			BeforeEach(func() {
+				fakeStore.GroupMembersReturns(nil, errors.New("banana"))
+			})
+
+			It("calls the internal server error handler", func() {
+				request, err := http.NewRequest("GET", "/networking/v1/internal/policies", nil)
+				Expect(err).NotTo(HaveOccurred())
+				MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+				Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+				_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+				Expect(err).To(MatchError("banana"))
+				Expect(description).To(Equal("database read failed"))
+			})
+		})
+
+		Context("when getting the spaces of the ids fails", func() {
+			BeforeEach(func() {
+				fakeStore.MemberGroupsReturns(nil, errors.New("banana"))
+			})
+
+			It("calls the internal server error handler", func() {
+				request, err := http.NewRequest("GET", "/networking/v1/internal/policies?id=some-app-guid", nil)
+				Expect(err).NotTo(HaveOccurred())
+				MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+				Expect(fakeStore.ByGuidsCallCount()).To(Equal(0))
+				Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+				_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+				Expect(err).To(MatchError("banana"))
+				Expect(description).To(Equal("database read failed"))
+			})
+		})
+	})
+
 	Context("when rendering the policies as bytes fails", func() {
 		BeforeEach(func() {
 			fakePolicyMapper.AsBytesReturns(nil, errors.New("banana"))
@@ -411,4 +411,155 @@ var _ = Describe("Policies index handler", func() {
 			Expect(description).To(Equal("filter policies failed"))
 		})
 	})
+
+	Context("when a limit is provided as a query parameter", func() {
+		BeforeEach(func() {
+			var err error
+			request, err = http.NewRequest("GET", "/networking/v1/external/policies?limit=2&from=3", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeStore.AllPaginatedReturns(allPolicies[:2], store.Pagination{Next: 7}, nil)
+			fakeMapper.AsBytesWithPaginationReturns(expectedResponseBody, nil)
+		})
+
+		It("returns a page of policies along with the next cursor", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeStore.AllCallCount()).To(Equal(0))
+			Expect(fakeStore.AllPaginatedCallCount()).To(Equal(1))
+			Expect(fakeStore.AllPaginatedArgsForCall(0)).To(Equal(store.Page{Limit: 2, From: 3}))
+
+			Expect(fakePolicyFilter.FilterPoliciesCallCount()).To(Equal(1))
+			policies, _ := fakePolicyFilter.FilterPoliciesArgsForCall(0)
+			Expect(policies).To(Equal(allPolicies[:2]))
+
+			Expect(fakeMapper.AsBytesCallCount()).To(Equal(0))
+			Expect(fakeMapper.AsBytesWithPaginationCallCount()).To(Equal(1))
+			mappedPolicies, pagination := fakeMapper.AsBytesWithPaginationArgsForCall(0)
+			Expect(mappedPolicies).To(Equal(filteredPolicies))
+			Expect(pagination).To(Equal(store.Pagination{Next: 7}))
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Body.Bytes()).To(Equal(expectedResponseBody))
+		})
+
+		Context("when ids are also provided", func() {
+			BeforeEach(func() {
+				var err error
+				request, err = http.NewRequest("GET", "/networking/v1/external/policies?source_id=some-app-guid&limit=2", nil)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("paginates the policies returned by ByGuidsPaginated", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+				Expect(fakeStore.ByGuidsCallCount()).To(Equal(0))
+				Expect(fakeStore.ByGuidsPaginatedCallCount()).To(Equal(1))
+				srcGuids, destGuids, inSourceAndDest, page := fakeStore.ByGuidsPaginatedArgsForCall(0)
+				Expect(srcGuids).To(Equal([]string{"some-app-guid"}))
+				Expect(destGuids).To(BeEmpty())
+				Expect(inSourceAndDest).To(BeFalse())
+				Expect(page).To(Equal(store.Page{Limit: 2}))
+				Expect(resp.Code).To(Equal(http.StatusOK))
+			})
+		})
+
+		Context("when the store throws an error", func() {
+			BeforeEach(func() {
+				fakeStore.AllPaginatedReturns(nil, store.Pagination{}, errors.New("banana"))
+			})
+
+			It("calls the internal server error handler", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+				Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+				_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+				Expect(err).To(MatchError("banana"))
+				Expect(description).To(Equal("database read failed"))
+			})
+		})
+
+		Context("when rendering the policies as bytes fails", func() {
+			BeforeEach(func() {
+				fakeMapper.AsBytesWithPaginationReturns(nil, errors.New("banana"))
+			})
+
+			It("calls the internal server error handler", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+				Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+				_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+				Expect(err).To(MatchError("banana"))
+				Expect(description).To(Equal("map policy as bytes failed"))
+			})
+		})
+	})
+
+	Context("when a label_selector is provided as a query parameter", func() {
+		BeforeEach(func() {
+			var err error
+			request, err = http.NewRequest("GET", "/networking/v1/external/policies?label_selector=team%3Dorders", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			fakePolicyFilter.FilterPoliciesStub = func(policies []store.Policy, subjectToken uaa_client.CheckTokenResponse) ([]store.Policy, error) {
+				return policies, nil
+			}
+			allPolicies[0].Metadata.Labels = map[string]string{"team": "orders"}
+			allPolicies[1].Metadata.Labels = map[string]string{"team": "payments"}
+			fakeStore.AllReturns(allPolicies, nil)
+		})
+
+		It("returns only the policies with matching labels", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(fakeMapper.AsBytesCallCount()).To(Equal(1))
+			policies := fakeMapper.AsBytesArgsForCall(0)
+			Expect(policies).To(HaveLen(1))
+			Expect(policies[0].Source.ID).To(Equal("some-app-guid"))
+			Expect(policies[0].Metadata.Labels).To(Equal(map[string]string{"team": "orders"}))
+		})
+
+		Context("when the label_selector is invalid", func() {
+			BeforeEach(func() {
+				var err error
+				request, err = http.NewRequest("GET", "/networking/v1/external/policies?label_selector=", nil)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("calls the bad request handler", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+				Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+				l, w, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+				Expect(l).To(Equal(expectedLogger))
+				Expect(w).To(Equal(resp))
+				Expect(err).To(MatchError("label selector may not be empty"))
+				Expect(description).To(Equal("label selector may not be empty"))
+				Expect(fakeStore.AllCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	DescribeTable("when the pagination parameters are invalid",
+		func(query, expectedDescription string) {
+			var err error
+			request, err = http.NewRequest("GET", "/networking/v1/external/policies?"+query, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			l, w, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError(expectedDescription))
+			Expect(description).To(Equal(expectedDescription))
+			Expect(fakeStore.AllPaginatedCallCount()).To(Equal(0))
+		},
+		Entry("non-numeric limit", "limit=banana", "invalid value for 'limit' parameter"),
+		Entry("negative limit", "limit=-1", "invalid value for 'limit' parameter"),
+		Entry("non-numeric from", "from=banana", "invalid value for 'from' parameter"),
+		Entry("negative from", "limit=1&from=-1", "invalid value for 'from' parameter"),
+	)
 })
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/cf-networking-helpers/marshal"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type PoliciesOverlapsIndex struct {
+	Store         policyStore
+	Marshaler     marshal.Marshaler
+	ErrorResponse errorResponse
+}
+
+func NewPoliciesOverlapsIndex(store policyStore, marshaler marshal.Marshaler, errorResponse errorResponse) *PoliciesOverlapsIndex {
+	return &PoliciesOverlapsIndex{
+		Store:         store,
+		Marshaler:     marshaler,
+		ErrorResponse: errorResponse,
+	}
+}
+
+func (h *PoliciesOverlapsIndex) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("index-policy-overlaps")
+
+	overlaps, err := findPolicyOverlaps(h.Store, parseIds(req.URL.Query()))
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+		return
+	}
+
+	apiOverlaps := api.MapStorePolicyOverlaps(overlaps)
+	responseBytes, err := h.Marshaler.Marshal(api.PolicyOverlapsPayload{
+		TotalOverlaps: len(apiOverlaps),
+		Overlaps:      apiOverlaps,
+	})
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database marshalling failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write(responseBytes)
+}
+
+// findPolicyOverlaps returns the overlaps of the unexpired policies, of every
+// policy or only of those with one of the ids as source or destination
+func findPolicyOverlaps(policyStore policyStore, ids []string) ([]store.PolicyOverlap, error) {
+	var policies []store.Policy
+	var err error
+	if len(ids) == 0 {
+		policies, err = policyStore.All()
+	} else {
+		policies, err = policyStore.ByGuids(ids, ids, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	policies = unexpiredPolicies(policies, time.Now())
+	for i := range policies {
+		policies[i].Source.Tag = ""
+		policies[i].Destination.Tag = ""
+	}
+	return store.FindOverlaps(policies), nil
+}
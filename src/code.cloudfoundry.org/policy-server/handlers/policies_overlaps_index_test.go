@@ -0,0 +1,149 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	hfakes "code.cloudfoundry.org/cf-networking-helpers/fakes"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policies overlaps index handler", func() {
+	var (
+		request           *http.Request
+		handler           *handlers.PoliciesOverlapsIndex
+		resp              *httptest.ResponseRecorder
+		fakeStore         *fakes.PolicyStore
+		fakeErrorResponse *fakes.ErrorResponse
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		marshaler         *hfakes.Marshaler
+		tcpPolicy         func(start, end int) store.Policy
+	)
+
+	BeforeEach(func() {
+		var err error
+		request, err = http.NewRequest("GET", "/networking/v1/external/policies/overlaps", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		marshaler = &hfakes.Marshaler{}
+		marshaler.MarshalStub = json.Marshal
+
+		tcpPolicy = func(start, end int) store.Policy {
+			return store.Policy{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: start, End: end},
+				},
+			}
+		}
+
+		expired := tcpPolicy(9001, 9100)
+		expiresAt := time.Now().Add(-time.Hour)
+		expired.ExpiresAt = &expiresAt
+		tagged := tcpPolicy(8085, 9000)
+		tagged.Source.Tag = "01"
+		tagged.Destination.Tag = "02"
+
+		fakeStore = &fakes.PolicyStore{}
+		fakeStore.AllReturns([]store.Policy{tcpPolicy(8080, 8090), tagged, expired, tcpPolicy(10000, 10000)}, nil)
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("index-policy-overlaps")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		handler = handlers.NewPoliciesOverlapsIndex(fakeStore, marshaler, fakeErrorResponse)
+		resp = httptest.NewRecorder()
+	})
+
+	It("returns the overlapping unexpired policies without tags", func() {
+		expectedResponseJSON := `{
+			"total_overlaps": 1,
+			"overlaps": [{
+				"policies": [
+					{ "source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "protocol": "tcp", "ports": { "start": 8080, "end": 8090 } } },
+					{ "source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "protocol": "tcp", "ports": { "start": 8085, "end": 9000 } } }
+				],
+				"merged": { "source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "protocol": "tcp", "ports": { "start": 8080, "end": 9000 } } }
+			}]
+		}`
+		MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+		Expect(fakeStore.AllCallCount()).To(Equal(1))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(expectedResponseJSON))
+	})
+
+	Context("when ids are provided", func() {
+		BeforeEach(func() {
+			var err error
+			request, err = http.NewRequest("GET", "/networking/v1/external/policies/overlaps?id=app-a,app-b", nil)
+			Expect(err).NotTo(HaveOccurred())
+			fakeStore.ByGuidsReturns([]store.Policy{tcpPolicy(8080, 8090)}, nil)
+		})
+
+		It("only looks at the policies of those apps", func() {
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeStore.AllCallCount()).To(Equal(0))
+			Expect(fakeStore.ByGuidsCallCount()).To(Equal(1))
+			srcGuids, dstGuids, inSourceAndDest := fakeStore.ByGuidsArgsForCall(0)
+			Expect(srcGuids).To(Equal([]string{"app-a", "app-b"}))
+			Expect(dstGuids).To(Equal([]string{"app-a", "app-b"}))
+			Expect(inSourceAndDest).To(BeFalse())
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Body).To(MatchJSON(`{ "total_overlaps": 0, "overlaps": [] }`))
+		})
+	})
+
+	Context("when the store throws an error", func() {
+		BeforeEach(func() {
+			fakeStore.AllReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database read failed"))
+		})
+	})
+
+	Context("when the overlaps cannot be marshaled", func() {
+		BeforeEach(func() {
+			marshaler.MarshalStub = func(interface{}) ([]byte, error) {
+				return nil, errors.New("grapes")
+			}
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("grapes"))
+			Expect(description).To(Equal("database marshalling failed"))
+		})
+	})
+})
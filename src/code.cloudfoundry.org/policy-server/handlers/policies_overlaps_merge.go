@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/cf-networking-helpers/marshal"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type PoliciesOverlapsMerge struct {
+	Store         policyStore
+	Marshaler     marshal.Marshaler
+	ErrorResponse errorResponse
+}
+
+func NewPoliciesOverlapsMerge(store policyStore, marshaler marshal.Marshaler, errorResponse errorResponse) *PoliciesOverlapsMerge {
+	return &PoliciesOverlapsMerge{
+		Store:         store,
+		Marshaler:     marshaler,
+		ErrorResponse: errorResponse,
+	}
+}
+
+func (h *PoliciesOverlapsMerge) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("merge-policy-overlaps")
+	tokenData := getTokenData(req)
+
+	overlaps, err := findPolicyOverlaps(h.Store, parseIds(req.URL.Query()))
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+		return
+	}
+
+	// the policies of an overlap with different metadata are left as they are
+	var created, deleted []store.Policy
+	for _, overlap := range overlaps {
+		if overlap.MetadataConflicts() {
+			continue
+		}
+		created = append(created, overlap.Merged)
+		deleted = append(deleted, overlap.Replaced()...)
+	}
+
+	err = h.Store.MergeWithEvent(created, deleted, getActor(tokenData))
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database merge failed")
+		return
+	}
+
+	apiOverlaps := api.MapStorePolicyOverlaps(overlaps)
+	responseBytes, err := h.Marshaler.Marshal(api.PolicyOverlapsPayload{
+		TotalOverlaps: len(apiOverlaps),
+		Overlaps:      apiOverlaps,
+	})
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database marshalling failed")
+		return
+	}
+
+	logger.Info("merged-policy-overlaps", lager.Data{"overlaps": len(overlaps), "userName": tokenData.UserName})
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write(responseBytes)
+}
@@ -0,0 +1,166 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	hfakes "code.cloudfoundry.org/cf-networking-helpers/fakes"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policies overlaps merge handler", func() {
+	var (
+		request           *http.Request
+		handler           *handlers.PoliciesOverlapsMerge
+		resp              *httptest.ResponseRecorder
+		fakeStore         *fakes.PolicyStore
+		fakeErrorResponse *fakes.ErrorResponse
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		marshaler         *hfakes.Marshaler
+		token             uaa_client.CheckTokenResponse
+		tcpPolicy         func(start, end int) store.Policy
+	)
+
+	BeforeEach(func() {
+		var err error
+		request, err = http.NewRequest("POST", "/networking/v1/external/policies/overlaps/merge", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		marshaler = &hfakes.Marshaler{}
+		marshaler.MarshalStub = json.Marshal
+
+		tcpPolicy = func(start, end int) store.Policy {
+			return store.Policy{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: start, End: end},
+				},
+			}
+		}
+
+		fakeStore = &fakes.PolicyStore{}
+		fakeStore.AllReturns([]store.Policy{tcpPolicy(8080, 9000), tcpPolicy(8085, 8090), tcpPolicy(9001, 9001)}, nil)
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		token = uaa_client.CheckTokenResponse{
+			Scope:    []string{"network.admin"},
+			UserName: "some-admin",
+		}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("merge-policy-overlaps")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		handler = handlers.NewPoliciesOverlapsMerge(fakeStore, marshaler, fakeErrorResponse)
+		resp = httptest.NewRecorder()
+	})
+
+	It("merges the overlapping policies and returns the merged overlaps", func() {
+		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+		Expect(fakeStore.MergeWithEventCallCount()).To(Equal(1))
+		created, deleted, actor := fakeStore.MergeWithEventArgsForCall(0)
+		Expect(created).To(Equal([]store.Policy{tcpPolicy(8080, 9001)}))
+		Expect(deleted).To(ConsistOf(tcpPolicy(8080, 9000), tcpPolicy(8085, 8090), tcpPolicy(9001, 9001)))
+		Expect(actor).To(Equal(store.Actor{Name: "some-admin"}))
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{
+			"total_overlaps": 1,
+			"overlaps": [{
+				"policies": [
+					{ "source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "protocol": "tcp", "ports": { "start": 8080, "end": 9000 } } },
+					{ "source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "protocol": "tcp", "ports": { "start": 8085, "end": 8090 } } },
+					{ "source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "protocol": "tcp", "ports": { "start": 9001, "end": 9001 } } }
+				],
+				"merged": { "source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "protocol": "tcp", "ports": { "start": 8080, "end": 9001 } } }
+			}]
+		}`))
+		Expect(logger.Logs()).To(ContainElement(SatisfyAll(
+			LogsWith(lager.INFO, "test.merge-policy-overlaps.merged-policy-overlaps"),
+			HaveLogData(SatisfyAll(
+				HaveKeyWithValue("overlaps", BeNumerically("==", 1)),
+				HaveKeyWithValue("userName", "some-admin"),
+			)),
+		)))
+	})
+
+	Context("when the policies of an overlap have different metadata", func() {
+		BeforeEach(func() {
+			first := tcpPolicy(8080, 9000)
+			first.Metadata = store.Metadata{Description: "first"}
+			second := tcpPolicy(8085, 8090)
+			second.Metadata = store.Metadata{Description: "second"}
+			fakeStore.AllReturns([]store.Policy{first, second, tcpPolicy(9500, 9600), tcpPolicy(9601, 9700)}, nil)
+		})
+
+		It("leaves them as they are and merges the other overlaps", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeStore.MergeWithEventCallCount()).To(Equal(1))
+			created, deleted, _ := fakeStore.MergeWithEventArgsForCall(0)
+			Expect(created).To(Equal([]store.Policy{tcpPolicy(9500, 9700)}))
+			Expect(deleted).To(ConsistOf(tcpPolicy(9500, 9600), tcpPolicy(9601, 9700)))
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			var payload struct {
+				Overlaps []struct {
+					MetadataConflicts bool `json:"metadata_conflicts"`
+				} `json:"overlaps"`
+			}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &payload)).To(Succeed())
+			Expect(payload.Overlaps).To(HaveLen(2))
+			Expect(payload.Overlaps[0].MetadataConflicts).To(BeTrue())
+			Expect(payload.Overlaps[1].MetadataConflicts).To(BeFalse())
+		})
+	})
+
+	Context("when getting the policies fails", func() {
+		BeforeEach(func() {
+			fakeStore.AllReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeStore.MergeWithEventCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database read failed"))
+		})
+	})
+
+	Context("when merging fails", func() {
+		BeforeEach(func() {
+			fakeStore.MergeWithEventReturns(errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, token)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database merge failed"))
+		})
+	})
+})
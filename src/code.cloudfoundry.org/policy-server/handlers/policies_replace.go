@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+// replaceCheckError is returned by the access check of a replace, so that
+// the handler can tell a failed check from a failed write
+type replaceCheckError struct {
+	err         error
+	description string
+	forbidden   bool
+}
+
+func (e *replaceCheckError) Error() string {
+	return e.err.Error()
+}
+
+type PoliciesReplace struct {
+	Store         policyStore
+	Mapper        api.PolicyMapper
+	PolicyGuard   policyGuard
+	QuotaGuard    quotaGuard
+	Consent       policyConsent
+	RataAdapter   rataAdapter
+	ErrorResponse errorResponse
+}
+
+func NewPoliciesReplace(store policyStore, mapper api.PolicyMapper, policyGuard policyGuard,
+	quotaGuard quotaGuard, consent policyConsent, rataAdapter rataAdapter, errorResponse errorResponse) *PoliciesReplace {
+	return &PoliciesReplace{
+		Store:         store,
+		Mapper:        mapper,
+		PolicyGuard:   policyGuard,
+		QuotaGuard:    quotaGuard,
+		Consent:       consent,
+		RataAdapter:   rataAdapter,
+		ErrorResponse: errorResponse,
+	}
+}
+
+func (h *PoliciesReplace) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("replace-policies")
+	tokenData := getTokenData(req)
+	sourceGuid := h.RataAdapter.Param(req, "guid")
+
+	bodyBytes, err := io.ReadAll(req.Body)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, "failed reading request body")
+		return
+	}
+
+	var payload api.PoliciesPayload
+	err = json.Unmarshal(bodyBytes, &payload)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, fmt.Sprintf("mapper: unmarshal json: %s", err))
+		return
+	}
+	if payload.Policies == nil {
+		err := errors.New("missing policies")
+		h.ErrorResponse.BadRequest(logger, w, err, fmt.Sprintf("mapper: %s", err))
+		return
+	}
+
+	// an empty policy list removes every policy of the source app, which the
+	// mapper would otherwise reject as missing policies
+	policies := []store.Policy{}
+	if len(payload.Policies) > 0 {
+		policies, err = h.Mapper.AsStorePolicy(bodyBytes)
+		if err != nil {
+			h.ErrorResponse.BadRequest(logger, w, err, fmt.Sprintf("mapper: %s", err))
+			return
+		}
+	}
+
+	for _, policy := range policies {
+		if policy.Source.ID != sourceGuid {
+			err := fmt.Errorf("policy source id %s does not match app guid %s", policy.Source.ID, sourceGuid)
+			h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+			return
+		}
+	}
+
+	direct, pending, err := h.Consent.PendingPolicies(policies, tokenData)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "check consent failed")
+		return
+	}
+
+	var replaced []store.Policy
+	var held []store.PendingPolicy
+
+	// the access check runs against the existing policies read inside the
+	// replace transaction, so that policies created in the meantime cannot
+	// be removed without access to their destination
+	checkAccess := func(existingPolicies []store.Policy) error {
+		// the subject needs access to every app whose policies are created or
+		// removed, including the source app itself when both sets are empty
+		accessPolicies := append(append([]store.Policy{}, existingPolicies...), policies...)
+		if len(accessPolicies) == 0 {
+			accessPolicies = []store.Policy{{
+				Source:      store.Source{ID: sourceGuid},
+				Destination: store.Destination{ID: sourceGuid},
+			}}
+		}
+
+		authorized, err := h.PolicyGuard.CheckAccess(accessPolicies, tokenData)
+		if err != nil {
+			return &replaceCheckError{err: err, description: "check access failed"}
+		}
+		if !authorized {
+			return &replaceCheckError{err: errors.New("one or more applications cannot be found or accessed"), forbidden: true}
+		}
+
+		authorized, err = h.QuotaGuard.CheckReplaceAccess(sourceGuid, policies, tokenData)
+		if err != nil {
+			return &replaceCheckError{err: err, description: "check quota failed"}
+		}
+		if !authorized {
+			return &replaceCheckError{err: errors.New("policy quota exceeded"), forbidden: true}
+		}
+
+		// the store keeps the pending policies that already exist
+		replaced, held = direct, nil
+		for _, p := range pending {
+			if containsStorePolicy(existingPolicies, p.Policy) {
+				replaced = append(replaced, p.Policy)
+			} else {
+				held = append(held, p)
+			}
+		}
+		return nil
+	}
+
+	err = h.Store.ReplaceForSource(sourceGuid, direct, pending, getActor(tokenData), checkAccess)
+	var checkErr *replaceCheckError
+	if errors.As(err, &checkErr) {
+		if checkErr.forbidden {
+			h.ErrorResponse.Forbidden(logger, w, checkErr.err, checkErr.err.Error())
+		} else {
+			h.ErrorResponse.InternalServerError(logger, w, checkErr.err, checkErr.description)
+		}
+		return
+	}
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database replace failed")
+		return
+	}
+
+	logger.Info("replaced-policies", lager.Data{"app_guid": sourceGuid, "policies": policies, "userName": tokenData.UserName})
+
+	status := http.StatusOK
+	if len(held) > 0 {
+		logger.Info("created-pending-policies", lager.Data{"pending": held, "userName": tokenData.UserName})
+		status = http.StatusAccepted
+	}
+
+	bytes, err := h.Mapper.AsBytes(replaced)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "map policy as bytes failed")
+		return
+	}
+
+	w.WriteHeader(status)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write(bytes)
+}
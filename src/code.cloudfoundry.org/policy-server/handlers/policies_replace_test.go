@@ -0,0 +1,431 @@
+package handlers_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/cf-networking-helpers/testsupport"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	apifakes "code.cloudfoundry.org/policy-server/api/fakes"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PoliciesReplace", func() {
+	var (
+		requestBody       string
+		request           *http.Request
+		handler           *handlers.PoliciesReplace
+		resp              *httptest.ResponseRecorder
+		expectedPolicies  []store.Policy
+		existingPolicies  []store.Policy
+		fakeStore         *fakes.PolicyStore
+		fakeMapper        *apifakes.PolicyMapper
+		fakePolicyGuard   *fakes.PolicyGuard
+		fakeQuotaGuard    *fakes.QuotaGuard
+		fakeConsent       *fakes.PolicyConsent
+		fakeRataAdapter   *fakes.RataAdapter
+		fakeErrorResponse *fakes.ErrorResponse
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		tokenData         uaa_client.CheckTokenResponse
+	)
+
+	BeforeEach(func() {
+		requestBody = `{"policies": [{"source": {"id": "some-app-guid"}}]}`
+
+		fakeStore = &fakes.PolicyStore{}
+		fakeMapper = &apifakes.PolicyMapper{}
+		fakePolicyGuard = &fakes.PolicyGuard{}
+		fakeQuotaGuard = &fakes.QuotaGuard{}
+		fakeConsent = &fakes.PolicyConsent{}
+		fakeConsent.PendingPoliciesStub = func(policies []store.Policy, _ uaa_client.CheckTokenResponse) ([]store.Policy, []store.PendingPolicy, error) {
+			return policies, nil, nil
+		}
+		fakeRataAdapter = &fakes.RataAdapter{}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("replace-policies")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		handler = &handlers.PoliciesReplace{
+			Store:         fakeStore,
+			Mapper:        fakeMapper,
+			PolicyGuard:   fakePolicyGuard,
+			QuotaGuard:    fakeQuotaGuard,
+			Consent:       fakeConsent,
+			RataAdapter:   fakeRataAdapter,
+			ErrorResponse: fakeErrorResponse,
+		}
+		tokenData = uaa_client.CheckTokenResponse{
+			Scope:    []string{"network.write"},
+			UserName: "some_user",
+		}
+
+		expectedPolicies = []store.Policy{
+			{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Protocol: "tcp",
+					Ports: store.Ports{
+						Start: 8080,
+						End:   9090,
+					},
+				},
+			},
+		}
+		existingPolicies = []store.Policy{
+			{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "an-old-app-guid",
+					Protocol: "udp",
+					Port:     1234,
+					Ports: store.Ports{
+						Start: 1234,
+						End:   1234,
+					},
+				},
+			},
+		}
+
+		fakeRataAdapter.ParamReturns("some-app-guid")
+		fakeMapper.AsStorePolicyReturns(expectedPolicies, nil)
+		fakeMapper.AsBytesReturns([]byte("some-policies"), nil)
+		fakeStore.ReplaceForSourceStub = func(_ string, _ []store.Policy, _ []store.PendingPolicy, _ store.Actor, checkExisting func([]store.Policy) error) error {
+			return checkExisting(existingPolicies)
+		}
+		fakePolicyGuard.CheckAccessReturns(true, nil)
+		fakeQuotaGuard.CheckReplaceAccessReturns(true, nil)
+		resp = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		request, err = http.NewRequest("PUT", "/networking/v1/external/apps/some-app-guid/policies", bytes.NewBuffer([]byte(requestBody)))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("replaces the policies of the source app", func() {
+		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+		_, paramName := fakeRataAdapter.ParamArgsForCall(0)
+		Expect(paramName).To(Equal("guid"))
+
+		Expect(fakeMapper.AsStorePolicyCallCount()).To(Equal(1))
+		Expect(fakeMapper.AsStorePolicyArgsForCall(0)).To(Equal([]byte(requestBody)))
+
+		Expect(fakeStore.ByGuidsCallCount()).To(Equal(0))
+
+		Expect(fakePolicyGuard.CheckAccessCallCount()).To(Equal(1))
+		policies, token := fakePolicyGuard.CheckAccessArgsForCall(0)
+		Expect(policies).To(ConsistOf(append(existingPolicies, expectedPolicies...)))
+		Expect(token).To(Equal(tokenData))
+
+		Expect(fakeQuotaGuard.CheckReplaceAccessCallCount()).To(Equal(1))
+		sourceGuid, policies, token := fakeQuotaGuard.CheckReplaceAccessArgsForCall(0)
+		Expect(sourceGuid).To(Equal("some-app-guid"))
+		Expect(policies).To(Equal(expectedPolicies))
+		Expect(token).To(Equal(tokenData))
+
+		Expect(fakeStore.ReplaceForSourceCallCount()).To(Equal(1))
+		sourceGuid, policies, pending, actor, _ := fakeStore.ReplaceForSourceArgsForCall(0)
+		Expect(sourceGuid).To(Equal("some-app-guid"))
+		Expect(policies).To(Equal(expectedPolicies))
+		Expect(pending).To(BeEmpty())
+		Expect(actor).To(Equal(store.Actor{Name: "some_user"}))
+
+		Expect(fakeMapper.AsBytesArgsForCall(0)).To(Equal(expectedPolicies))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(Equal("some-policies"))
+	})
+
+	It("logs the replaced policies with username and app guid", func() {
+		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+		Expect(logger.Logs()).To(HaveLen(1))
+		Expect(logger.Logs()[0]).To(SatisfyAll(
+			LogsWith(lager.INFO, "test.replace-policies.replaced-policies"),
+			HaveLogData(SatisfyAll(
+				HaveLen(4),
+				HaveKeyWithValue("app_guid", "some-app-guid"),
+				HaveKeyWithValue("policies", HaveLen(1)),
+				HaveKeyWithValue("userName", "some_user"),
+			)),
+		))
+	})
+
+	Context("when the request contains an empty policy list", func() {
+		BeforeEach(func() {
+			requestBody = `{"policies": []}`
+		})
+
+		It("removes every policy of the source app without calling the mapper", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeMapper.AsStorePolicyCallCount()).To(Equal(0))
+			Expect(fakeStore.ReplaceForSourceCallCount()).To(Equal(1))
+			sourceGuid, policies, _, _, _ := fakeStore.ReplaceForSourceArgsForCall(0)
+			Expect(sourceGuid).To(Equal("some-app-guid"))
+			Expect(policies).To(BeEmpty())
+			Expect(resp.Code).To(Equal(http.StatusOK))
+		})
+
+		Context("when the source app has no policies", func() {
+			BeforeEach(func() {
+				existingPolicies = []store.Policy{}
+			})
+
+			It("checks access to the source app", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakePolicyGuard.CheckAccessCallCount()).To(Equal(1))
+				policies, _ := fakePolicyGuard.CheckAccessArgsForCall(0)
+				Expect(policies).To(Equal([]store.Policy{{
+					Source:      store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{ID: "some-app-guid"},
+				}}))
+			})
+		})
+	})
+
+	Context("when policies need the consent of their destination space", func() {
+		var pending []store.PendingPolicy
+
+		BeforeEach(func() {
+			pending = []store.PendingPolicy{{Policy: expectedPolicies[0], DestinationSpaceGUID: "some-space-guid"}}
+			fakeConsent.PendingPoliciesStub = nil
+			fakeConsent.PendingPoliciesReturns([]store.Policy{}, pending, nil)
+		})
+
+		It("holds them and responds with status 202", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeConsent.PendingPoliciesCallCount()).To(Equal(1))
+			consentPolicies, token := fakeConsent.PendingPoliciesArgsForCall(0)
+			Expect(consentPolicies).To(Equal(expectedPolicies))
+			Expect(token).To(Equal(tokenData))
+
+			_, policies, replacePending, _, _ := fakeStore.ReplaceForSourceArgsForCall(0)
+			Expect(policies).To(BeEmpty())
+			Expect(replacePending).To(Equal(pending))
+
+			policies, _ = fakePolicyGuard.CheckAccessArgsForCall(0)
+			Expect(policies).To(ConsistOf(append(existingPolicies, expectedPolicies...)))
+
+			Expect(fakeMapper.AsBytesArgsForCall(0)).To(BeEmpty())
+			Expect(resp.Code).To(Equal(http.StatusAccepted))
+			Expect(logger.Logs()).To(ContainElement(LogsWith(lager.INFO, "test.replace-policies.created-pending-policies")))
+		})
+
+		Context("when the held policies already exist", func() {
+			BeforeEach(func() {
+				existingPolicies = expectedPolicies
+			})
+
+			It("keeps them and responds with status 200", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeMapper.AsBytesArgsForCall(0)).To(Equal(expectedPolicies))
+				Expect(resp.Code).To(Equal(http.StatusOK))
+			})
+		})
+
+		Context("when checking consent fails", func() {
+			BeforeEach(func() {
+				fakeConsent.PendingPoliciesReturns(nil, nil, errors.New("banana"))
+			})
+
+			It("calls the internal server error handler", func() {
+				MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+				Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+				_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+				Expect(err).To(MatchError("banana"))
+				Expect(description).To(Equal("check consent failed"))
+				Expect(fakeStore.ReplaceForSourceCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("when the request does not contain a policy list", func() {
+		BeforeEach(func() {
+			requestBody = `{}`
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(err).To(MatchError("missing policies"))
+			Expect(description).To(Equal("mapper: missing policies"))
+			Expect(fakeStore.ReplaceForSourceCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the request body is not valid json", func() {
+		BeforeEach(func() {
+			requestBody = `not-json`
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			_, _, _, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(description).To(HavePrefix("mapper: unmarshal json:"))
+		})
+	})
+
+	Context("when a policy has a different source app", func() {
+		BeforeEach(func() {
+			expectedPolicies[0].Source.ID = "another-app-guid"
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			l, w, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("policy source id another-app-guid does not match app guid some-app-guid"))
+			Expect(description).To(Equal("policy source id another-app-guid does not match app guid some-app-guid"))
+			Expect(fakeStore.ReplaceForSourceCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the mapper fails to get store policies", func() {
+		BeforeEach(func() {
+			fakeMapper.AsStorePolicyReturns([]store.Policy{}, errors.New("banana"))
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			l, w, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("mapper: banana"))
+		})
+	})
+
+	Context("when the policy guard returns false", func() {
+		BeforeEach(func() {
+			fakePolicyGuard.CheckAccessReturns(false, nil)
+		})
+
+		It("calls the forbidden handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.ForbiddenCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.ForbiddenArgsForCall(0)
+			Expect(err).To(MatchError("one or more applications cannot be found or accessed"))
+			Expect(description).To(Equal("one or more applications cannot be found or accessed"))
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(0))
+			Expect(fakeQuotaGuard.CheckReplaceAccessCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the policy guard returns an error", func() {
+		BeforeEach(func() {
+			fakePolicyGuard.CheckAccessReturns(false, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("check access failed"))
+		})
+	})
+
+	Context("when the quota guard returns false", func() {
+		BeforeEach(func() {
+			fakeQuotaGuard.CheckReplaceAccessReturns(false, nil)
+		})
+
+		It("calls the forbidden handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.ForbiddenCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.ForbiddenArgsForCall(0)
+			Expect(err).To(MatchError("policy quota exceeded"))
+			Expect(description).To(Equal("policy quota exceeded"))
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the quota guard returns an error", func() {
+		BeforeEach(func() {
+			fakeQuotaGuard.CheckReplaceAccessReturns(false, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("check quota failed"))
+		})
+	})
+
+	Context("when the store replace call returns an error", func() {
+		BeforeEach(func() {
+			fakeStore.ReplaceForSourceReturns(errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database replace failed"))
+		})
+	})
+
+	Context("when mapping the policies as bytes fails", func() {
+		BeforeEach(func() {
+			fakeMapper.AsBytesReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("map policy as bytes failed"))
+		})
+	})
+
+	Context("when there are errors reading the body bytes", func() {
+		It("calls the bad request handler", func() {
+			request.Body = io.NopCloser(&testsupport.BadReader{})
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("failed reading request body"))
+		})
+	})
+})
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+//counterfeiter:generate -o fakes/policies_watch_store.go --fake-name PoliciesWatchStore . policiesWatchStore
+type policiesWatchStore interface {
+	All() ([]store.Policy, error)
+	GroupMembers([]string) ([]store.GroupMember, error)
+	Quarantined() ([]string, error)
+	LastUpdated() (int, error)
+	ExpiredCount(now time.Time) (int, error)
+}
+
+// PolicyChange is a change to the policies that the internal API lists.
+// Policies that are changed in place are removed and added again.
+type PolicyChange struct {
+	Revision int
+	Added    []api.Policy
+	Removed  []api.Policy
+}
+
+// PolicyWatch starts from every policy at Revision, or when it resumed from an
+// earlier revision, from the Backlog of changes since then. Changes receives
+// the changes that follow, and is closed when the watcher falls too far
+// behind, after which it has to watch again from the last revision it received.
+// Revisions are only known to the Instance of the hub that sent them.
+type PolicyWatch struct {
+	Instance string
+	Revision int
+	Resumed  bool
+	Policies []api.Policy
+	Backlog  []PolicyChange
+	Changes  chan PolicyChange
+}
+
+// PoliciesWatchHub keeps the policies that the internal API lists, and the
+// recent changes to them, so that watchers can be sent the changes instead of
+// listing every policy again. Poll is run periodically to pick up changes.
+// The policies are only kept while there are watchers: they are loaded by the
+// first watch and dropped by the first poll without watchers. Each hub numbers
+// the changes that it sees on its own, so watchers only resume on the Instance
+// that they watched before.
+type PoliciesWatchHub struct {
+	Instance     string
+	Logger       lager.Logger
+	Store        policiesWatchStore
+	Clock        clock.Clock
+	HistorySize  int
+	WatchBacklog int
+
+	// reloadMutex keeps reloads in order, so that changes are computed from
+	// the policies of the reload before
+	reloadMutex sync.Mutex
+	mutex       sync.Mutex
+	loaded      bool
+	lastUpdated int
+	expired     int
+	revision    int
+	policies    map[string]api.Policy
+	history     []PolicyChange
+	historyBase int
+	watches     map[*PolicyWatch]struct{}
+}
+
+func NewPoliciesWatchHub(logger lager.Logger, store policiesWatchStore, historySize, watchBacklog int) *PoliciesWatchHub {
+	return &PoliciesWatchHub{
+		Instance:     newWatchInstance(),
+		Logger:       logger,
+		Store:        store,
+		Clock:        clock.NewClock(),
+		HistorySize:  historySize,
+		WatchBacklog: watchBacklog,
+		watches:      map[*PolicyWatch]struct{}{},
+	}
+}
+
+// newWatchInstance returns a random id for the hub, or the current time when
+// there is no randomness
+func newWatchInstance() string {
+	id := make([]byte, 8)
+	_, err := rand.Read(id)
+	if err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(id)
+}
+
+// Poll reloads the policies when they may have changed and sends the changes
+// to the watchers. Without watchers, it drops the policies and the history
+// instead, and does not read the database. The revision of a change is the last
+// updated time of the policies, or the time that the latest policy expired at
+// if that is later. Polls on other policy servers may see several changes as
+// one, or a change with an earlier time, so revisions only order the changes
+// of this hub.
+func (h *PoliciesWatchHub) Poll() error {
+	h.mutex.Lock()
+	watching := len(h.watches) > 0
+	if !watching && h.loaded {
+		h.loaded = false
+		h.policies = nil
+		h.history = nil
+		h.Logger.Debug("unloaded-policies")
+	}
+	h.mutex.Unlock()
+	if !watching {
+		return nil
+	}
+	return h.reload()
+}
+
+func (h *PoliciesWatchHub) reload() error {
+	h.reloadMutex.Lock()
+	defer h.reloadMutex.Unlock()
+
+	now := h.Clock.Now()
+	lastUpdated, err := h.Store.LastUpdated()
+	if err != nil {
+		return err
+	}
+	expired, err := h.Store.ExpiredCount(now)
+	if err != nil {
+		return err
+	}
+
+	h.mutex.Lock()
+	unchanged := h.loaded && lastUpdated == h.lastUpdated && expired == h.expired
+	h.mutex.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	allPolicies, err := h.Store.All()
+	if err != nil {
+		return err
+	}
+	allPolicies, err = expandGroupSources(h.Store, allPolicies)
+	if err != nil {
+		return err
+	}
+	quarantined, err := h.Store.Quarantined()
+	if err != nil {
+		return err
+	}
+
+	revision := lastUpdated
+	for _, policy := range allPolicies {
+		if policy.IsExpired(now) && int(policy.ExpiresAt.UnixNano()) > revision {
+			revision = int(policy.ExpiresAt.UnixNano())
+		}
+	}
+
+	policies := map[string]api.Policy{}
+	for _, policy := range api.MapStorePolicies(unquarantinedPolicies(unexpiredPolicies(allPolicies, now), quarantined)) {
+		policies[policyKey(policy)] = policy
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.lastUpdated = lastUpdated
+	h.expired = expired
+	if !h.loaded {
+		h.loaded = true
+		h.revision = revision
+		h.historyBase = revision
+		h.policies = policies
+		return nil
+	}
+
+	change := PolicyChange{
+		Added:   policiesNotIn(policies, h.policies),
+		Removed: policiesNotIn(h.policies, policies),
+	}
+	if len(change.Added) == 0 && len(change.Removed) == 0 {
+		return nil
+	}
+
+	// the last updated time is set when a transaction starts, so a change
+	// can be seen with an earlier time than the one before it
+	if revision <= h.revision {
+		revision = h.revision + 1
+	}
+	change.Revision = revision
+	h.revision = revision
+	h.policies = policies
+
+	h.history = append(h.history, change)
+	if len(h.history) > h.HistorySize {
+		h.historyBase = h.history[0].Revision
+		h.history = h.history[1:]
+	}
+
+	for watch := range h.watches {
+		select {
+		case watch.Changes <- change:
+		default:
+			h.Logger.Info("dropping-slow-watch", lager.Data{"revision": revision})
+			delete(h.watches, watch)
+			close(watch.Changes)
+		}
+	}
+
+	h.Logger.Debug("policies-changed", lager.Data{"revision": revision, "added": len(change.Added), "removed": len(change.Removed)})
+	return nil
+}
+
+// Watch starts a watch from the given revision of the given instance. It
+// resumes from the changes since then when the revision is from this hub and
+// still in the history, and otherwise starts from every policy, to resync from.
+// The policies are loaded first when nobody watched before.
+func (h *PoliciesWatchHub) Watch(instance string, revision int, resume bool) (*PolicyWatch, error) {
+	watch := &PolicyWatch{
+		Instance: h.Instance,
+		Changes:  make(chan PolicyChange, h.WatchBacklog),
+	}
+
+	// the watch is registered before loading, so that a poll does not drop
+	// the policies in between
+	h.mutex.Lock()
+	h.watches[watch] = struct{}{}
+	loaded := h.loaded
+	h.mutex.Unlock()
+
+	if !loaded {
+		err := h.reload()
+		if err != nil {
+			h.Unwatch(watch)
+			return nil, err
+		}
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	// changes sent while loading are already part of the current revision
+	for len(watch.Changes) > 0 {
+		<-watch.Changes
+	}
+	watch.Revision = h.revision
+
+	if resume && instance == h.Instance {
+		watch.Backlog, watch.Resumed = h.changesSince(revision)
+		if watch.Resumed {
+			return watch, nil
+		}
+	}
+
+	watch.Policies = make([]api.Policy, 0, len(h.policies))
+	for _, policy := range h.policies {
+		watch.Policies = append(watch.Policies, policy)
+	}
+	sortPolicies(watch.Policies)
+	return watch, nil
+}
+
+// Unwatch stops sending changes to the watch
+func (h *PoliciesWatchHub) Unwatch(watch *PolicyWatch) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if _, ok := h.watches[watch]; ok {
+		delete(h.watches, watch)
+		close(watch.Changes)
+	}
+}
+
+func (h *PoliciesWatchHub) changesSince(revision int) ([]PolicyChange, bool) {
+	if revision == h.historyBase {
+		return append([]PolicyChange{}, h.history...), true
+	}
+	for i, change := range h.history {
+		if change.Revision == revision {
+			return append([]PolicyChange{}, h.history[i+1:]...), true
+		}
+	}
+	return nil, false
+}
+
+func policiesNotIn(policies, others map[string]api.Policy) []api.Policy {
+	result := []api.Policy{}
+	for key, policy := range policies {
+		if _, ok := others[key]; !ok {
+			result = append(result, policy)
+		}
+	}
+	sortPolicies(result)
+	return result
+}
+
+// sortPolicies orders deny policies first, and the rest by key so that every
+// watcher sees the same order
+func sortPolicies(policies []api.Policy) {
+	sort.Slice(policies, func(i, j int) bool {
+		iDeny := policies[i].Action == store.PolicyActionDeny
+		jDeny := policies[j].Action == store.PolicyActionDeny
+		if iDeny != jDeny {
+			return iDeny
+		}
+		return policyKey(policies[i]) < policyKey(policies[j])
+	})
+}
+
+// policyKey identifies a policy as the internal API lists it, so that any
+// change to it shows up as a change
+func policyKey(policy api.Policy) string {
+	// #nosec G104 - marshaling a policy never fails
+	key, _ := json.Marshal(policy)
+	return string(key)
+}
@@ -0,0 +1,389 @@
+package handlers_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PoliciesWatchHub", func() {
+	var (
+		hub       *handlers.PoliciesWatchHub
+		fakeStore *fakes.PoliciesWatchStore
+		fakeClock *fakeclock.FakeClock
+		now       time.Time
+
+		policyA  store.Policy
+		policyB  store.Policy
+		denyC    store.Policy
+		apiA     api.Policy
+		apiB     api.Policy
+		apiDenyC api.Policy
+	)
+
+	var tcpPolicy = func(source, destination string, port int) store.Policy {
+		return store.Policy{
+			Source:      store.Source{ID: source},
+			Destination: store.Destination{ID: destination, Protocol: "tcp", Ports: store.Ports{Start: port, End: port}},
+		}
+	}
+
+	var apiPolicy = func(policy store.Policy) api.Policy {
+		return api.MapStorePolicies([]store.Policy{policy})[0]
+	}
+
+	BeforeEach(func() {
+		now = time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		fakeClock = fakeclock.NewFakeClock(now)
+
+		policyA = tcpPolicy("app-a", "app-b", 8080)
+		policyB = tcpPolicy("app-b", "app-c", 9000)
+		denyC = tcpPolicy("app-c", "app-a", 22)
+		denyC.Action = store.PolicyActionDeny
+		apiA, apiB, apiDenyC = apiPolicy(policyA), apiPolicy(policyB), apiPolicy(denyC)
+
+		fakeStore = &fakes.PoliciesWatchStore{}
+		fakeStore.LastUpdatedReturns(1000, nil)
+		fakeStore.AllReturns([]store.Policy{policyA, policyB, denyC}, nil)
+		fakeStore.QuarantinedReturns([]string{}, nil)
+
+		hub = handlers.NewPoliciesWatchHub(lagertest.NewTestLogger("test"), fakeStore, 3, 10)
+		hub.Clock = fakeClock
+	})
+
+	It("has an instance of its own", func() {
+		another := handlers.NewPoliciesWatchHub(lagertest.NewTestLogger("test"), fakeStore, 3, 10)
+		Expect(hub.Instance).NotTo(BeEmpty())
+		Expect(hub.Instance).NotTo(Equal(another.Instance))
+	})
+
+	It("does not read the policies without watchers", func() {
+		Expect(hub.Poll()).To(Succeed())
+		Expect(fakeStore.LastUpdatedCallCount()).To(Equal(0))
+		Expect(fakeStore.AllCallCount()).To(Equal(0))
+	})
+
+	It("loads the policies on the first watch", func() {
+		watch, err := hub.Watch("", 0, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(watch.Policies).To(Equal([]api.Policy{apiDenyC, apiA, apiB}))
+		Expect(watch.Revision).To(Equal(1000))
+		Expect(fakeStore.AllCallCount()).To(Equal(1))
+	})
+
+	Context("once the policies are loaded", func() {
+		var watch *handlers.PolicyWatch
+
+		BeforeEach(func() {
+			var err error
+			watch, err = hub.Watch("", 0, false)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("syncs every policy with deny policies first", func() {
+			syncWatch, err := hub.Watch("", 0, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(syncWatch.Resumed).To(BeFalse())
+			Expect(syncWatch.Backlog).To(BeNil())
+			Expect(syncWatch.Policies).To(Equal([]api.Policy{apiDenyC, apiA, apiB}))
+			Expect(syncWatch.Revision).To(Equal(1000))
+		})
+
+		It("does not reload the policies when nothing has changed", func() {
+			Expect(hub.Poll()).To(Succeed())
+			Expect(fakeStore.AllCallCount()).To(Equal(1))
+			Expect(fakeStore.ExpiredCountArgsForCall(1)).To(Equal(now))
+		})
+
+		It("sends the added and removed policies to watchers", func() {
+			fakeStore.LastUpdatedReturns(2000, nil)
+			policyD := tcpPolicy("app-d", "app-a", 8080)
+			fakeStore.AllReturns([]store.Policy{policyA, denyC, policyD}, nil)
+			Expect(hub.Poll()).To(Succeed())
+
+			Expect(watch.Changes).To(Receive(Equal(handlers.PolicyChange{
+				Revision: 2000,
+				Added:    []api.Policy{apiPolicy(policyD)},
+				Removed:  []api.Policy{apiB},
+			})))
+		})
+
+		It("removes and adds policies that changed in place", func() {
+			fakeStore.LastUpdatedReturns(2000, nil)
+			described := policyA
+			described.Metadata.Description = "some description"
+			fakeStore.AllReturns([]store.Policy{described, policyB, denyC}, nil)
+			Expect(hub.Poll()).To(Succeed())
+
+			Expect(watch.Changes).To(Receive(Equal(handlers.PolicyChange{
+				Revision: 2000,
+				Added:    []api.Policy{apiPolicy(described)},
+				Removed:  []api.Policy{apiA},
+			})))
+		})
+
+		It("removes the policies of quarantined apps", func() {
+			fakeStore.LastUpdatedReturns(2000, nil)
+			fakeStore.QuarantinedReturns([]string{"app-c"}, nil)
+			Expect(hub.Poll()).To(Succeed())
+
+			Expect(watch.Changes).To(Receive(Equal(handlers.PolicyChange{
+				Revision: 2000,
+				Added:    []api.Policy{},
+				Removed:  []api.Policy{apiDenyC, apiB},
+			})))
+		})
+
+		It("sends the policies from the apps of a space when its apps change", func() {
+			spacePolicy := tcpPolicy("some-space", "app-a", 443)
+			spacePolicy.Source.Type = store.GroupTypeSpace
+			fakeStore.LastUpdatedReturns(2000, nil)
+			fakeStore.AllReturns([]store.Policy{policyA, policyB, denyC, spacePolicy}, nil)
+			fakeStore.GroupMembersReturns([]store.GroupMember{
+				{GroupGUID: "some-space", AppGUID: "app-d", AppTag: "0004"},
+			}, nil)
+			Expect(hub.Poll()).To(Succeed())
+
+			fromAppD := tcpPolicy("app-d", "app-a", 443)
+			fromAppD.Source.Tag = "0004"
+			Expect(fakeStore.GroupMembersArgsForCall(0)).To(Equal([]string{"some-space"}))
+			Expect(watch.Changes).To(Receive(Equal(handlers.PolicyChange{
+				Revision: 2000,
+				Added:    []api.Policy{apiPolicy(fromAppD)},
+				Removed:  []api.Policy{},
+			})))
+
+			fakeStore.LastUpdatedReturns(3000, nil)
+			fakeStore.GroupMembersReturns([]store.GroupMember{}, nil)
+			Expect(hub.Poll()).To(Succeed())
+
+			Expect(watch.Changes).To(Receive(Equal(handlers.PolicyChange{
+				Revision: 3000,
+				Added:    []api.Policy{},
+				Removed:  []api.Policy{apiPolicy(fromAppD)},
+			})))
+		})
+
+		It("does not send changes that are not visible", func() {
+			fakeStore.LastUpdatedReturns(2000, nil)
+			Expect(hub.Poll()).To(Succeed())
+
+			Expect(watch.Changes).NotTo(Receive())
+			syncWatch, err := hub.Watch("", 0, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(syncWatch.Revision).To(Equal(1000))
+		})
+
+		Context("when a policy expires", func() {
+			var expiresAt time.Time
+
+			BeforeEach(func() {
+				expiresAt = now.Add(time.Minute)
+				expiring := policyB
+				expiring.ExpiresAt = &expiresAt
+				fakeStore.LastUpdatedReturns(2000, nil)
+				fakeStore.AllReturns([]store.Policy{policyA, expiring, denyC}, nil)
+				Expect(hub.Poll()).To(Succeed())
+				Expect(watch.Changes).To(Receive())
+			})
+
+			It("removes it with the expiry as the revision", func() {
+				fakeClock.Increment(2 * time.Minute)
+				fakeStore.ExpiredCountReturns(1, nil)
+				Expect(hub.Poll()).To(Succeed())
+
+				var change handlers.PolicyChange
+				Expect(watch.Changes).To(Receive(&change))
+				Expect(change.Revision).To(Equal(int(expiresAt.UnixNano())))
+				Expect(change.Added).To(BeEmpty())
+				Expect(change.Removed).To(HaveLen(1))
+				Expect(change.Removed[0].Source.ID).To(Equal("app-b"))
+			})
+		})
+
+		It("keeps the revision increasing when last updated goes back", func() {
+			fakeStore.LastUpdatedReturns(999, nil)
+			fakeStore.AllReturns([]store.Policy{policyA, denyC}, nil)
+			Expect(hub.Poll()).To(Succeed())
+
+			var change handlers.PolicyChange
+			Expect(watch.Changes).To(Receive(&change))
+			Expect(change.Revision).To(Equal(1001))
+		})
+
+		Context("when there have been several changes", func() {
+			BeforeEach(func() {
+				for i, policies := range [][]store.Policy{
+					{policyA, policyB},
+					{policyA},
+					{},
+					{policyB},
+				} {
+					fakeStore.LastUpdatedReturns(2000+i, nil)
+					fakeStore.AllReturns(policies, nil)
+					Expect(hub.Poll()).To(Succeed())
+				}
+			})
+
+			It("resumes from a revision in the history", func() {
+				resumed, err := hub.Watch(hub.Instance, 2001, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resumed.Resumed).To(BeTrue())
+				Expect(resumed.Policies).To(BeNil())
+				Expect(resumed.Revision).To(Equal(2003))
+				Expect(resumed.Backlog).To(Equal([]handlers.PolicyChange{
+					{Revision: 2002, Added: []api.Policy{}, Removed: []api.Policy{apiA}},
+					{Revision: 2003, Added: []api.Policy{apiB}, Removed: []api.Policy{}},
+				}))
+			})
+
+			It("resumes from the latest revision without changes", func() {
+				resumed, err := hub.Watch(hub.Instance, 2003, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resumed.Resumed).To(BeTrue())
+				Expect(resumed.Policies).To(BeNil())
+				Expect(resumed.Backlog).To(BeEmpty())
+			})
+
+			It("syncs when the revision is no longer in the history", func() {
+				synced, err := hub.Watch(hub.Instance, 1000, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(synced.Resumed).To(BeFalse())
+				Expect(synced.Backlog).To(BeNil())
+				Expect(synced.Policies).To(Equal([]api.Policy{apiB}))
+				Expect(synced.Revision).To(Equal(2003))
+			})
+
+			It("syncs when the revision is unknown", func() {
+				synced, err := hub.Watch(hub.Instance, 12345, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(synced.Resumed).To(BeFalse())
+				Expect(synced.Policies).To(Equal([]api.Policy{apiB}))
+			})
+
+			It("syncs when the revision is from another instance", func() {
+				synced, err := hub.Watch("another-instance", 2001, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(synced.Resumed).To(BeFalse())
+				Expect(synced.Instance).To(Equal(hub.Instance))
+				Expect(synced.Policies).To(Equal([]api.Policy{apiB}))
+			})
+
+			It("resumes from the oldest revision that the history starts from", func() {
+				resumed, err := hub.Watch(hub.Instance, 2000, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resumed.Resumed).To(BeTrue())
+				Expect(resumed.Backlog).To(HaveLen(3))
+			})
+		})
+
+		It("stops sending changes once unwatched", func() {
+			hub.Unwatch(watch)
+			Expect(watch.Changes).To(BeClosed())
+
+			fakeStore.LastUpdatedReturns(2000, nil)
+			fakeStore.AllReturns([]store.Policy{}, nil)
+			Expect(hub.Poll()).To(Succeed())
+		})
+
+		It("drops the policies once the last watcher leaves", func() {
+			hub.Unwatch(watch)
+			Expect(hub.Poll()).To(Succeed())
+			Expect(fakeStore.LastUpdatedCallCount()).To(Equal(1))
+
+			fakeStore.AllReturns([]store.Policy{policyA}, nil)
+			watch, err := hub.Watch("", 0, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(watch.Policies).To(Equal([]api.Policy{apiA}))
+			Expect(fakeStore.AllCallCount()).To(Equal(2))
+		})
+
+		Context("when a watcher falls behind", func() {
+			BeforeEach(func() {
+				hub.WatchBacklog = 1
+				var err error
+				watch, err = hub.Watch("", 0, false)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("closes the watch", func() {
+				fakeStore.LastUpdatedReturns(2000, nil)
+				fakeStore.AllReturns([]store.Policy{policyA}, nil)
+				Expect(hub.Poll()).To(Succeed())
+				fakeStore.LastUpdatedReturns(3000, nil)
+				fakeStore.AllReturns([]store.Policy{}, nil)
+				Expect(hub.Poll()).To(Succeed())
+
+				Expect(watch.Changes).To(Receive())
+				Expect(watch.Changes).To(BeClosed())
+				hub.Unwatch(watch)
+			})
+		})
+	})
+
+	Context("when getting last updated fails", func() {
+		BeforeEach(func() {
+			fakeStore.LastUpdatedReturns(0, errors.New("banana"))
+		})
+
+		It("returns the error", func() {
+			_, err := hub.Watch("", 0, false)
+			Expect(err).To(MatchError("banana"))
+		})
+	})
+
+	Context("when counting the expired policies fails", func() {
+		BeforeEach(func() {
+			fakeStore.ExpiredCountReturns(0, errors.New("banana"))
+		})
+
+		It("returns the error", func() {
+			_, err := hub.Watch("", 0, false)
+			Expect(err).To(MatchError("banana"))
+		})
+	})
+
+	Context("when listing the policies fails", func() {
+		BeforeEach(func() {
+			fakeStore.AllReturns(nil, errors.New("banana"))
+		})
+
+		It("returns the error", func() {
+			_, err := hub.Watch("", 0, false)
+			Expect(err).To(MatchError("banana"))
+		})
+	})
+
+	Context("when listing the apps of the spaces and orgs fails", func() {
+		BeforeEach(func() {
+			spacePolicy := tcpPolicy("some-space", "app-a", 443)
+			spacePolicy.Source.Type = store.GroupTypeSpace
+			fakeStore.AllReturns([]store.Policy{spacePolicy}, nil)
+			fakeStore.GroupMembersReturns(nil, errors.New("banana"))
+		})
+
+		It("returns the error", func() {
+			_, err := hub.Watch("", 0, false)
+			Expect(err).To(MatchError("banana"))
+		})
+	})
+
+	Context("when listing the quarantined apps fails", func() {
+		BeforeEach(func() {
+			fakeStore.QuarantinedReturns(nil, errors.New("banana"))
+		})
+
+		It("returns the error", func() {
+			_, err := hub.Watch("", 0, false)
+			Expect(err).To(MatchError("banana"))
+		})
+	})
+})
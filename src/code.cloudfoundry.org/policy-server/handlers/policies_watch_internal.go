@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/cf-networking-helpers/marshal"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/api"
+)
+
+//counterfeiter:generate -o fakes/policies_watcher.go --fake-name PoliciesWatcher . policiesWatcher
+type policiesWatcher interface {
+	Watch(instance string, revision int, resume bool) (*PolicyWatch, error)
+	Unwatch(*PolicyWatch)
+}
+
+const (
+	watchEventSync   = "sync"
+	watchEventChange = "change"
+)
+
+type watchSyncEvent struct {
+	Revision int          `json:"revision"`
+	Policies []api.Policy `json:"policies"`
+}
+
+type watchChangeEvent struct {
+	Revision int          `json:"revision"`
+	Added    []api.Policy `json:"added"`
+	Removed  []api.Policy `json:"removed"`
+}
+
+// PoliciesWatchInternal streams the changes to the policies as server-sent
+// events. A watch starts with a sync event that has every policy, unless it
+// resumes from a revision that the server still has the changes since. Event
+// ids are the instance of the server and the revision, so that a client that
+// reconnects to another server syncs instead.
+type PoliciesWatchInternal struct {
+	Logger            lager.Logger
+	Watcher           policiesWatcher
+	Marshaler         marshal.Marshaler
+	ErrorResponse     errorResponse
+	HeartbeatInterval time.Duration
+}
+
+func NewPoliciesWatchInternal(logger lager.Logger, watcher policiesWatcher, marshaler marshal.Marshaler,
+	errorResponse errorResponse, heartbeatInterval time.Duration) *PoliciesWatchInternal {
+	return &PoliciesWatchInternal{
+		Logger:            logger,
+		Watcher:           watcher,
+		Marshaler:         marshaler,
+		ErrorResponse:     errorResponse,
+		HeartbeatInterval: heartbeatInterval,
+	}
+}
+
+func (h *PoliciesWatchInternal) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("watch-policies-internal")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.ErrorResponse.InternalServerError(logger, w, errors.New("response writer cannot flush"), "streaming unsupported")
+		return
+	}
+
+	ids := parseIds(req.URL.Query())
+	features := parsePolicyFeatures(req.URL.Query())
+	if !features[PolicyFeatureDeny] {
+		// changes to deny policies would also change which allow policies
+		// they override, which the changes do not carry
+		h.ErrorResponse.BadRequest(logger, w, errors.New("missing deny feature"), "watching policies requires the deny feature")
+		return
+	}
+	instance, revision, resume, err := parseWatchRevision(req)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, "invalid revision")
+		return
+	}
+
+	watch, err := h.Watcher.Watch(instance, revision, resume)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "watching policies failed")
+		return
+	}
+	defer h.Watcher.Unwatch(watch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	if !watch.Resumed {
+		logger.Debug("sync", lager.Data{"revision": watch.Revision})
+		err = h.writeEvent(w, watchEventSync, watch.Instance, watch.Revision, watchSyncEvent{
+			Revision: watch.Revision,
+			Policies: features.supportedPolicies(filterWatchedPolicies(watch.Policies, ids)),
+		})
+		if err != nil {
+			logger.Error("write-event-failed", err)
+			return
+		}
+	}
+	for _, change := range watch.Backlog {
+		err = h.writeChange(w, watch.Instance, change, ids, features)
+		if err != nil {
+			logger.Error("write-event-failed", err)
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case change, ok := <-watch.Changes:
+			if !ok {
+				// the watch fell behind, the client resumes from the last
+				// revision that it received
+				return
+			}
+			err = h.writeChange(w, watch.Instance, change, ids, features)
+		case <-heartbeat.C:
+			_, err = w.Write([]byte(": heartbeat\n\n"))
+		}
+		if err != nil {
+			logger.Error("write-event-failed", err)
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// writeChange writes the part of the change that matches the ids and the
+// features. A change that does not affect them is skipped.
+func (h *PoliciesWatchInternal) writeChange(w http.ResponseWriter, instance string, change PolicyChange, ids []string, features policyFeatures) error {
+	added := features.supportedPolicies(filterWatchedPolicies(change.Added, ids))
+	removed := features.supportedPolicies(filterWatchedPolicies(change.Removed, ids))
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+	return h.writeEvent(w, watchEventChange, instance, change.Revision, watchChangeEvent{
+		Revision: change.Revision,
+		Added:    added,
+		Removed:  removed,
+	})
+}
+
+func (h *PoliciesWatchInternal) writeEvent(w http.ResponseWriter, event, instance string, revision int, payload interface{}) error {
+	data, err := h.Marshaler.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal json: %s", err)
+	}
+	_, err = fmt.Fprintf(w, "id: %s-%d\nevent: %s\ndata: %s\n\n", instance, revision, event, data)
+	return err
+}
+
+// parseWatchRevision reads the event id to resume from, which the standard
+// Last-Event-ID header of server-sent events takes precedence for. A revision
+// without an instance is not known to any server, and is synced from.
+func parseWatchRevision(req *http.Request) (string, int, bool, error) {
+	value := req.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = req.URL.Query().Get("revision")
+	}
+	if value == "" {
+		return "", 0, false, nil
+	}
+	instance, revisionValue := "", value
+	if i := strings.LastIndex(value, "-"); i >= 0 {
+		instance, revisionValue = value[:i], value[i+1:]
+	}
+	revision, err := strconv.Atoi(revisionValue)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("revision must be an integer: %s", value)
+	}
+	return instance, revision, true, nil
+}
+
+func filterWatchedPolicies(policies []api.Policy, ids []string) []api.Policy {
+	if len(ids) == 0 {
+		return policies
+	}
+	filtered := []api.Policy{}
+	for _, policy := range policies {
+		if containsString(ids, policy.Source.ID) || containsString(ids, policy.Destination.ID) {
+			filtered = append(filtered, policy)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,309 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	hfakes "code.cloudfoundry.org/cf-networking-helpers/fakes"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("PoliciesWatchInternal", func() {
+	var (
+		handler           *handlers.PoliciesWatchInternal
+		request           *http.Request
+		resp              *httptest.ResponseRecorder
+		fakeWatcher       *fakes.PoliciesWatcher
+		fakeErrorResponse *fakes.ErrorResponse
+		marshaler         *hfakes.Marshaler
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		watch             *handlers.PolicyWatch
+		policyA           api.Policy
+		policyB           api.Policy
+	)
+
+	BeforeEach(func() {
+		policyA = api.Policy{
+			Source:      api.Source{ID: "app-a"},
+			Destination: api.Destination{ID: "app-b", Protocol: "tcp", Ports: api.Ports{Start: 8080, End: 8080}},
+		}
+		policyB = api.Policy{
+			Source:      api.Source{ID: "app-c"},
+			Destination: api.Destination{ID: "app-d", Protocol: "udp", Ports: api.Ports{Start: 53, End: 53}},
+		}
+
+		watch = &handlers.PolicyWatch{
+			Instance: "some-instance",
+			Revision: 1000,
+			Policies: []api.Policy{policyA, policyB},
+			Changes:  make(chan handlers.PolicyChange, 10),
+		}
+		fakeWatcher = &fakes.PoliciesWatcher{}
+		fakeWatcher.WatchReturns(watch, nil)
+
+		marshaler = &hfakes.Marshaler{}
+		marshaler.MarshalStub = json.Marshal
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("watch-policies-internal")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+
+		handler = handlers.NewPoliciesWatchInternal(logger, fakeWatcher, marshaler, fakeErrorResponse, time.Hour)
+		resp = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/networking/v1/internal/policies/watch?features=deny", nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("when the watch ends", func() {
+		BeforeEach(func() {
+			watch.Changes <- handlers.PolicyChange{Revision: 2000, Added: []api.Policy{}, Removed: []api.Policy{policyA}}
+			watch.Changes <- handlers.PolicyChange{Revision: 3000, Added: []api.Policy{policyA}, Removed: []api.Policy{}}
+			close(watch.Changes)
+		})
+
+		It("syncs every policy and then streams the changes", func() {
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			instance, revision, resume := fakeWatcher.WatchArgsForCall(0)
+			Expect(instance).To(BeEmpty())
+			Expect(revision).To(Equal(0))
+			Expect(resume).To(BeFalse())
+			Expect(fakeWatcher.UnwatchArgsForCall(0)).To(Equal(watch))
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Header().Get("Content-Type")).To(Equal("text/event-stream"))
+			Expect(resp.Header().Get("Cache-Control")).To(Equal("no-cache"))
+			Expect(resp.Flushed).To(BeTrue())
+			Expect(resp.Body.String()).To(Equal(
+				"id: some-instance-1000\nevent: sync\ndata: " +
+					`{"revision":1000,"policies":[` +
+					`{"source":{"id":"app-a"},"destination":{"id":"app-b","protocol":"tcp","ports":{"start":8080,"end":8080}}},` +
+					`{"source":{"id":"app-c"},"destination":{"id":"app-d","protocol":"udp","ports":{"start":53,"end":53}}}]}` + "\n\n" +
+					"id: some-instance-2000\nevent: change\ndata: " +
+					`{"revision":2000,"added":[],"removed":[{"source":{"id":"app-a"},"destination":{"id":"app-b","protocol":"tcp","ports":{"start":8080,"end":8080}}}]}` + "\n\n" +
+					"id: some-instance-3000\nevent: change\ndata: " +
+					`{"revision":3000,"added":[{"source":{"id":"app-a"},"destination":{"id":"app-b","protocol":"tcp","ports":{"start":8080,"end":8080}}}],"removed":[]}` + "\n\n",
+			))
+		})
+
+		Context("when ids are given", func() {
+			BeforeEach(func() {
+				var err error
+				request, err = http.NewRequest("GET", "/networking/v1/internal/policies/watch?features=deny&id=app-d", nil)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("only streams the policies of the ids", func() {
+				MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+				Expect(resp.Body.String()).To(Equal(
+					"id: some-instance-1000\nevent: sync\ndata: " +
+						`{"revision":1000,"policies":[` +
+						`{"source":{"id":"app-c"},"destination":{"id":"app-d","protocol":"udp","ports":{"start":53,"end":53}}}]}` + "\n\n",
+				))
+			})
+		})
+
+		Context("when a policy needs a feature", func() {
+			var icmpPolicy api.Policy
+
+			BeforeEach(func() {
+				icmpType, icmpCode := 8, 0
+				icmpPolicy = api.Policy{
+					Source:      api.Source{ID: "app-a"},
+					Destination: api.Destination{ID: "app-b", Protocol: "icmp", ICMPType: &icmpType, ICMPCode: &icmpCode},
+				}
+				watch.Policies = []api.Policy{icmpPolicy}
+			})
+
+			It("leaves the policy out when the agent does not support the feature", func() {
+				MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+				Expect(resp.Body.String()).To(HavePrefix(
+					"id: some-instance-1000\nevent: sync\ndata: " + `{"revision":1000,"policies":[]}` + "\n\n",
+				))
+			})
+
+			It("streams the policy when the agent supports the feature", func() {
+				var err error
+				request, err = http.NewRequest("GET", "/networking/v1/internal/policies/watch?features=deny,icmp", nil)
+				Expect(err).NotTo(HaveOccurred())
+				MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+				Expect(resp.Body.String()).To(HavePrefix(
+					"id: some-instance-1000\nevent: sync\ndata: " +
+						`{"revision":1000,"policies":[{"source":{"id":"app-a"},"destination":{"id":"app-b","protocol":"icmp","ports":{"start":0,"end":0},"icmp_type":8,"icmp_code":0}}]}` + "\n\n",
+				))
+			})
+		})
+
+		Context("when resuming from a revision", func() {
+			BeforeEach(func() {
+				watch.Resumed = true
+				watch.Policies = nil
+				watch.Backlog = []handlers.PolicyChange{
+					{Revision: 1000, Added: []api.Policy{policyB}, Removed: []api.Policy{}},
+				}
+				request.Header.Set("Last-Event-ID", "some-instance-900")
+			})
+
+			It("streams the backlog instead of syncing", func() {
+				MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+				instance, revision, resume := fakeWatcher.WatchArgsForCall(0)
+				Expect(instance).To(Equal("some-instance"))
+				Expect(revision).To(Equal(900))
+				Expect(resume).To(BeTrue())
+
+				Expect(resp.Body.String()).To(HavePrefix("id: some-instance-1000\nevent: change\ndata: " +
+					`{"revision":1000,"added":[{"source":{"id":"app-c"},"destination":{"id":"app-d","protocol":"udp","ports":{"start":53,"end":53}}}],"removed":[]}` + "\n\n" +
+					"id: some-instance-2000\nevent: change\n"))
+			})
+		})
+
+		Context("when the revision is a query parameter", func() {
+			BeforeEach(func() {
+				var err error
+				request, err = http.NewRequest("GET", "/networking/v1/internal/policies/watch?features=deny&revision=some-instance-900", nil)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("resumes from it", func() {
+				MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+				instance, revision, resume := fakeWatcher.WatchArgsForCall(0)
+				Expect(instance).To(Equal("some-instance"))
+				Expect(revision).To(Equal(900))
+				Expect(resume).To(BeTrue())
+			})
+		})
+
+		Context("when the revision has no instance", func() {
+			BeforeEach(func() {
+				request.Header.Set("Last-Event-ID", "900")
+			})
+
+			It("asks for a revision that no instance has", func() {
+				MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+				instance, revision, _ := fakeWatcher.WatchArgsForCall(0)
+				Expect(instance).To(BeEmpty())
+				Expect(revision).To(Equal(900))
+			})
+		})
+	})
+
+	Context("when the client disconnects", func() {
+		It("stops watching", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			request = request.WithContext(ctx)
+			cancel()
+
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeWatcher.UnwatchCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when there are no changes for a while", func() {
+		BeforeEach(func() {
+			handler.HeartbeatInterval = time.Millisecond
+		})
+
+		It("sends heartbeats", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			request = request.WithContext(ctx)
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(resp.Body.String()).To(ContainSubstring(": heartbeat\n\n"))
+		})
+	})
+
+	Context("when the agent does not support deny policies", func() {
+		BeforeEach(func() {
+			var err error
+			request, err = http.NewRequest("GET", "/networking/v1/internal/policies/watch?features=icmp", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeWatcher.WatchCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			l, w, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("missing deny feature"))
+			Expect(description).To(Equal("watching policies requires the deny feature"))
+		})
+	})
+
+	Context("when the revision is not a number", func() {
+		BeforeEach(func() {
+			request.Header.Set("Last-Event-ID", "banana")
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeWatcher.WatchCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			l, w, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("revision must be an integer: banana"))
+			Expect(description).To(Equal("invalid revision"))
+		})
+	})
+
+	Context("when watching fails", func() {
+		BeforeEach(func() {
+			fakeWatcher.WatchReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeWatcher.UnwatchCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("watching policies failed"))
+		})
+	})
+
+	Context("when marshaling an event fails", func() {
+		BeforeEach(func() {
+			marshaler.MarshalReturns(nil, errors.New("banana"))
+			marshaler.MarshalStub = nil
+		})
+
+		It("ends the stream", func() {
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(resp.Body.String()).To(BeEmpty())
+			Expect(fakeWatcher.UnwatchCallCount()).To(Equal(1))
+			Expect(logger).To(gbytes.Say("write-event-failed"))
+		})
+	})
+})
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/policy-server/cc_client"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/tracing"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+)
+
+//counterfeiter:generate -o fakes/policy_consent.go --fake-name PolicyConsent . policyConsent
+type policyConsent interface {
+	PendingPolicies(policies []store.Policy, tokenData uaa_client.CheckTokenResponse) ([]store.Policy, []store.PendingPolicy, error)
+	CanApprove(spaceGUID string, tokenData uaa_client.CheckTokenResponse) (bool, error)
+	Approvable(pending []store.PendingPolicy, tokenData uaa_client.CheckTokenResponse) ([]store.PendingPolicy, error)
+}
+
+// PolicyConsent holds policies to apps of another space than their source
+// until someone with the write permission in the destination space approves
+// them. Network admins do not need consent.
+type PolicyConsent struct {
+	CCClient  cc_client.CCClient
+	UAAClient uaa_client.UAAClient
+	Roles     Roles
+	Enabled   bool
+}
+
+func NewPolicyConsent(uaaClient uaa_client.UAAClient, ccClient cc_client.CCClient, roles Roles, enabled bool) *PolicyConsent {
+	return &PolicyConsent{
+		CCClient:  ccClient,
+		UAAClient: uaaClient,
+		Roles:     roles,
+		Enabled:   enabled,
+	}
+}
+
+// Traced returns a copy of the consent check whose clients record spans as children
+// of the span in the context
+func (c *PolicyConsent) Traced(ctx context.Context) interface{} {
+	return tracing.BindFields(ctx, c)
+}
+
+// PendingPolicies splits the policies into the ones that can be created
+// directly and the ones that must wait for consent. Policies to a space where
+// the subject may approve them are created directly.
+func (c *PolicyConsent) PendingPolicies(policies []store.Policy, subjectToken uaa_client.CheckTokenResponse) ([]store.Policy, []store.PendingPolicy, error) {
+	if !c.Enabled || isNetworkAdmin(subjectToken) || len(policies) == 0 {
+		return policies, nil, nil
+	}
+
+	token, err := c.UAAClient.GetToken()
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting token: %s", err)
+	}
+
+	appSpaces, err := c.CCClient.GetAppSpaces(token, uniqueAppGUIDs(policies))
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting app spaces: %s", err)
+	}
+
+	crossSpace := []string{}
+	for _, policy := range policies {
+		destinationSpace := appSpaces[policy.Destination.ID]
+		if needsConsent(policy, appSpaces) && !containsString(crossSpace, destinationSpace) {
+			crossSpace = append(crossSpace, destinationSpace)
+		}
+	}
+
+	// the subject would approve their own policies to the spaces where they
+	// may approve, so these are not held
+	approvable, err := c.approvableSpacesWithToken(token, crossSpace, subjectToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	direct := []store.Policy{}
+	pending := []store.PendingPolicy{}
+	for _, policy := range policies {
+		destinationSpace := appSpaces[policy.Destination.ID]
+		if !needsConsent(policy, appSpaces) || approvable[destinationSpace] {
+			direct = append(direct, policy)
+			continue
+		}
+		pending = append(pending, store.PendingPolicy{
+			Policy:               policy,
+			DestinationSpaceGUID: destinationSpace,
+		})
+	}
+	return direct, pending, nil
+}
+
+// needsConsent returns whether the policy is from an app to an app of another
+// space
+func needsConsent(policy store.Policy, appSpaces map[string]string) bool {
+	return policy.Source.Type == "" && appSpaces[policy.Source.ID] != appSpaces[policy.Destination.ID]
+}
+
+// CanApprove returns whether the subject may approve or reject the pending
+// policies to the apps of the space
+func (c *PolicyConsent) CanApprove(spaceGUID string, subjectToken uaa_client.CheckTokenResponse) (bool, error) {
+	approvable, err := c.approvableSpaces([]string{spaceGUID}, subjectToken)
+	if err != nil {
+		return false, err
+	}
+	return approvable[spaceGUID], nil
+}
+
+// Approvable returns the pending policies that the subject may approve or
+// that they requested
+func (c *PolicyConsent) Approvable(pending []store.PendingPolicy, subjectToken uaa_client.CheckTokenResponse) ([]store.PendingPolicy, error) {
+	spaceGUIDs := []string{}
+	for _, p := range pending {
+		if !containsString(spaceGUIDs, p.DestinationSpaceGUID) {
+			spaceGUIDs = append(spaceGUIDs, p.DestinationSpaceGUID)
+		}
+	}
+
+	approvable, err := c.approvableSpaces(spaceGUIDs, subjectToken)
+	if err != nil {
+		return nil, err
+	}
+
+	requester := getActor(subjectToken).Name
+	filtered := []store.PendingPolicy{}
+	for _, p := range pending {
+		if approvable[p.DestinationSpaceGUID] || p.RequestedBy == requester {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered, nil
+}
+
+func (c *PolicyConsent) approvableSpaces(spaceGUIDs []string, subjectToken uaa_client.CheckTokenResponse) (map[string]bool, error) {
+	approvable := map[string]bool{}
+	if isNetworkAdmin(subjectToken) {
+		for _, guid := range spaceGUIDs {
+			approvable[guid] = true
+		}
+		return approvable, nil
+	}
+	if len(spaceGUIDs) == 0 {
+		return approvable, nil
+	}
+
+	token, err := c.UAAClient.GetToken()
+	if err != nil {
+		return nil, fmt.Errorf("getting token: %s", err)
+	}
+	return c.approvableSpacesWithToken(token, spaceGUIDs, subjectToken)
+}
+
+func (c *PolicyConsent) approvableSpacesWithToken(token string, spaceGUIDs []string, subjectToken uaa_client.CheckTokenResponse) (map[string]bool, error) {
+	approvable := map[string]bool{}
+	if len(spaceGUIDs) == 0 {
+		return approvable, nil
+	}
+
+	subjectRoles, err := c.CCClient.GetSubjectRoles(token, subjectToken.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("getting subject roles: %s", err)
+	}
+
+	for _, guid := range spaceGUIDs {
+		space, err := c.CCClient.GetSpace(token, guid)
+		if err != nil {
+			return nil, fmt.Errorf("getting space with guid %s: %s", guid, err)
+		}
+		if space == nil {
+			continue
+		}
+		approvable[guid] = c.Roles.Grants(subjectRoles, guid, space.Entity.OrganizationGUID, PermissionWrite)
+	}
+	return approvable, nil
+}
@@ -0,0 +1,262 @@
+package handlers_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/policy-server/cc_client"
+	ccfakes "code.cloudfoundry.org/policy-server/cc_client/fakes"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+	uaafakes "code.cloudfoundry.org/policy-server/uaa_client/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PolicyConsent", func() {
+	var (
+		policyConsent *handlers.PolicyConsent
+		fakeCCClient  *ccfakes.CCClient
+		fakeUAAClient *uaafakes.UAAClient
+		tokenData     uaa_client.CheckTokenResponse
+		policies      []store.Policy
+	)
+
+	BeforeEach(func() {
+		fakeCCClient = &ccfakes.CCClient{}
+		fakeUAAClient = &uaafakes.UAAClient{}
+		policyConsent = handlers.NewPolicyConsent(fakeUAAClient, fakeCCClient, handlers.DefaultRoles(), true)
+
+		policies = []store.Policy{
+			{
+				Source:      store.Source{ID: "app-1"},
+				Destination: store.Destination{ID: "app-2"},
+			},
+			{
+				Source:      store.Source{ID: "app-1"},
+				Destination: store.Destination{ID: "app-3"},
+			},
+		}
+		tokenData = uaa_client.CheckTokenResponse{
+			Scope:    []string{"network.write"},
+			Subject:  "some-developer-guid",
+			UserName: "some-developer",
+		}
+
+		fakeUAAClient.GetTokenReturns("policy-server-token", nil)
+		fakeCCClient.GetAppSpacesReturns(map[string]string{
+			"app-1": "space-1",
+			"app-2": "space-1",
+			"app-3": "space-3",
+		}, nil)
+		fakeCCClient.GetSpaceStub = func(token, spaceGUID string) (*cc_client.SpaceResponse, error) {
+			if spaceGUID == "deleted-space" {
+				return nil, nil
+			}
+			return &cc_client.SpaceResponse{Entity: cc_client.SpaceEntity{OrganizationGUID: "org-1"}}, nil
+		}
+		fakeCCClient.GetSubjectRolesReturns([]cc_client.Role{
+			{Type: cc_client.RoleSpaceDeveloper, SpaceGUID: "space-2"},
+			{Type: cc_client.RoleSpaceAuditor, SpaceGUID: "space-3"},
+		}, nil)
+	})
+
+	Describe("PendingPolicies", func() {
+		It("holds the policies to apps of another space", func() {
+			direct, pending, err := policyConsent.PendingPolicies(policies, tokenData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(direct).To(Equal([]store.Policy{policies[0]}))
+			Expect(pending).To(Equal([]store.PendingPolicy{
+				{Policy: policies[1], DestinationSpaceGUID: "space-3"},
+			}))
+
+			Expect(fakeCCClient.GetAppSpacesCallCount()).To(Equal(1))
+			token, appGUIDs := fakeCCClient.GetAppSpacesArgsForCall(0)
+			Expect(token).To(Equal("policy-server-token"))
+			Expect(appGUIDs).To(ConsistOf("app-1", "app-2", "app-3"))
+
+			Expect(fakeUAAClient.GetTokenCallCount()).To(Equal(1))
+			Expect(fakeCCClient.GetSpaceCallCount()).To(Equal(1))
+			_, spaceGUID := fakeCCClient.GetSpaceArgsForCall(0)
+			Expect(spaceGUID).To(Equal("space-3"))
+		})
+
+		Context("when the subject may approve the policies to the destination space", func() {
+			BeforeEach(func() {
+				fakeCCClient.GetAppSpacesReturns(map[string]string{
+					"app-1": "space-1",
+					"app-2": "space-1",
+					"app-3": "space-2",
+				}, nil)
+			})
+
+			It("holds no policies", func() {
+				direct, pending, err := policyConsent.PendingPolicies(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(direct).To(Equal(policies))
+				Expect(pending).To(BeEmpty())
+			})
+		})
+
+		Context("when every policy is to an app of the same space", func() {
+			BeforeEach(func() {
+				policies = policies[:1]
+			})
+
+			It("does not check the roles of the subject", func() {
+				direct, pending, err := policyConsent.PendingPolicies(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(direct).To(Equal(policies))
+				Expect(pending).To(BeEmpty())
+				Expect(fakeCCClient.GetSubjectRolesCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when getting the subject roles fails", func() {
+			BeforeEach(func() {
+				fakeCCClient.GetSubjectRolesReturns(nil, errors.New("banana"))
+			})
+
+			It("returns an error", func() {
+				_, _, err := policyConsent.PendingPolicies(policies, tokenData)
+				Expect(err).To(MatchError("getting subject roles: banana"))
+			})
+		})
+
+		Context("when consent is disabled", func() {
+			BeforeEach(func() {
+				policyConsent.Enabled = false
+			})
+
+			It("holds no policies", func() {
+				direct, pending, err := policyConsent.PendingPolicies(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(direct).To(Equal(policies))
+				Expect(pending).To(BeEmpty())
+				Expect(fakeCCClient.GetAppSpacesCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the subject is a network admin", func() {
+			BeforeEach(func() {
+				tokenData.Scope = []string{"network.admin"}
+			})
+
+			It("holds no policies", func() {
+				direct, pending, err := policyConsent.PendingPolicies(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(direct).To(Equal(policies))
+				Expect(pending).To(BeEmpty())
+			})
+		})
+
+		Context("when getting the token fails", func() {
+			BeforeEach(func() {
+				fakeUAAClient.GetTokenReturns("", errors.New("banana"))
+			})
+
+			It("returns an error", func() {
+				_, _, err := policyConsent.PendingPolicies(policies, tokenData)
+				Expect(err).To(MatchError("getting token: banana"))
+			})
+		})
+
+		Context("when getting the app spaces fails", func() {
+			BeforeEach(func() {
+				fakeCCClient.GetAppSpacesReturns(nil, errors.New("banana"))
+			})
+
+			It("returns an error", func() {
+				_, _, err := policyConsent.PendingPolicies(policies, tokenData)
+				Expect(err).To(MatchError("getting app spaces: banana"))
+			})
+		})
+	})
+
+	Describe("CanApprove", func() {
+		It("returns whether the subject has the write permission in the space", func() {
+			approve, err := policyConsent.CanApprove("space-2", tokenData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(approve).To(BeTrue())
+
+			approve, err = policyConsent.CanApprove("space-3", tokenData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(approve).To(BeFalse())
+
+			token, subject := fakeCCClient.GetSubjectRolesArgsForCall(0)
+			Expect(token).To(Equal("policy-server-token"))
+			Expect(subject).To(Equal("some-developer-guid"))
+		})
+
+		Context("when the space does not exist", func() {
+			It("returns false", func() {
+				approve, err := policyConsent.CanApprove("deleted-space", tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(approve).To(BeFalse())
+			})
+		})
+
+		Context("when the subject is a network admin", func() {
+			BeforeEach(func() {
+				tokenData.Scope = []string{"network.admin"}
+			})
+
+			It("returns true without checking roles", func() {
+				approve, err := policyConsent.CanApprove("space-3", tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(approve).To(BeTrue())
+				Expect(fakeCCClient.GetSubjectRolesCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when getting the subject roles fails", func() {
+			BeforeEach(func() {
+				fakeCCClient.GetSubjectRolesReturns(nil, errors.New("banana"))
+			})
+
+			It("returns an error", func() {
+				_, err := policyConsent.CanApprove("space-2", tokenData)
+				Expect(err).To(MatchError("getting subject roles: banana"))
+			})
+		})
+
+		Context("when getting the space fails", func() {
+			BeforeEach(func() {
+				fakeCCClient.GetSpaceStub = nil
+				fakeCCClient.GetSpaceReturns(nil, errors.New("banana"))
+			})
+
+			It("returns an error", func() {
+				_, err := policyConsent.CanApprove("space-2", tokenData)
+				Expect(err).To(MatchError("getting space with guid space-2: banana"))
+			})
+		})
+	})
+
+	Describe("Approvable", func() {
+		It("returns the pending policies that the subject may approve or requested", func() {
+			pending := []store.PendingPolicy{
+				{ID: 1, DestinationSpaceGUID: "space-2", RequestedBy: "someone-else"},
+				{ID: 2, DestinationSpaceGUID: "space-3", RequestedBy: "someone-else"},
+				{ID: 3, DestinationSpaceGUID: "space-3", RequestedBy: "some-developer"},
+			}
+
+			approvable, err := policyConsent.Approvable(pending, tokenData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(approvable).To(Equal([]store.PendingPolicy{pending[0], pending[2]}))
+			Expect(fakeCCClient.GetSubjectRolesCallCount()).To(Equal(1))
+			Expect(fakeCCClient.GetSpaceCallCount()).To(Equal(2))
+		})
+
+		Context("when checking the spaces fails", func() {
+			BeforeEach(func() {
+				fakeUAAClient.GetTokenReturns("", errors.New("banana"))
+			})
+
+			It("returns an error", func() {
+				_, err := policyConsent.Approvable([]store.PendingPolicy{{DestinationSpaceGUID: "space-2"}}, tokenData)
+				Expect(err).To(MatchError("getting token: banana"))
+			})
+		})
+	})
+})
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/cf-networking-helpers/marshal"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type PolicyEventsIndex struct {
+	Store         store.PolicyEventsStore
+	Marshaler     marshal.Marshaler
+	ErrorResponse errorResponse
+}
+
+func NewPolicyEventsIndex(store store.PolicyEventsStore, marshaler marshal.Marshaler, errorResponse errorResponse) *PolicyEventsIndex {
+	return &PolicyEventsIndex{
+		Store:         store,
+		Marshaler:     marshaler,
+		ErrorResponse: errorResponse,
+	}
+}
+
+func (h *PolicyEventsIndex) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("index-policy-events")
+	queryValues := req.URL.Query()
+
+	since, err := parseTimeQueryValue(queryValues, "since")
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+	until, err := parseTimeQueryValue(queryValues, "until")
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+
+	page, err := parsePage(queryValues)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+
+	events, pagination, err := h.Store.Events(store.PolicyEventsFilter{
+		AppGuids: parseIds(queryValues),
+		Since:    since,
+		Until:    until,
+	}, page)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+		return
+	}
+
+	apiEvents := api.MapStorePolicyEvents(events)
+	responseBytes, err := h.Marshaler.Marshal(api.PolicyEventsPayload{
+		TotalEvents: len(apiEvents),
+		Next:        pagination.Next,
+		Events:      apiEvents,
+	})
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database marshalling failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write(responseBytes)
+}
+
+func parseTimeQueryValue(queryValues url.Values, name string) (time.Time, error) {
+	value := queryValues.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid value for '%s' parameter: must be an RFC3339 timestamp", name)
+	}
+	return t, nil
+}
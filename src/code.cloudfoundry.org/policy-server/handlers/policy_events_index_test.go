@@ -0,0 +1,228 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	hfakes "code.cloudfoundry.org/cf-networking-helpers/fakes"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	storeFakes "code.cloudfoundry.org/policy-server/store/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policy events index handler", func() {
+	var (
+		request           *http.Request
+		handler           *handlers.PolicyEventsIndex
+		resp              *httptest.ResponseRecorder
+		fakeStore         *storeFakes.PolicyEventsStore
+		fakeErrorResponse *fakes.ErrorResponse
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		marshaler         *hfakes.Marshaler
+	)
+
+	BeforeEach(func() {
+		var err error
+		request, err = http.NewRequest("GET", "/networking/v1/external/policies/events", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		marshaler = &hfakes.Marshaler{}
+		marshaler.MarshalStub = json.Marshal
+
+		fakeStore = &storeFakes.PolicyEventsStore{}
+		fakeStore.EventsReturns([]store.PolicyEvent{{
+			ID:       1,
+			Action:   store.PolicyEventCreate,
+			Actor:    "some-user",
+			ClientID: "some-client",
+			Policies: []store.Policy{{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+			}},
+			CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		}}, store.Pagination{}, nil)
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("index-policy-events")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		handler = handlers.NewPolicyEventsIndex(fakeStore, marshaler, fakeErrorResponse)
+		resp = httptest.NewRecorder()
+	})
+
+	It("returns the policy events", func() {
+		expectedResponseJSON := `{
+			"total_events": 1,
+			"events": [{
+				"id": 1,
+				"action": "create",
+				"actor": "some-user",
+				"client_id": "some-client",
+				"policies": [{
+					"source": { "id": "some-app-guid" },
+					"destination": {
+						"id": "some-other-app-guid",
+						"protocol": "tcp",
+						"ports": { "start": 8080, "end": 8080 }
+					}
+				}],
+				"timestamp": "2026-01-02T03:04:05Z"
+			}]
+		}`
+		MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+		Expect(fakeStore.EventsCallCount()).To(Equal(1))
+		filter, page := fakeStore.EventsArgsForCall(0)
+		Expect(filter).To(Equal(store.PolicyEventsFilter{}))
+		Expect(page).To(Equal(store.Page{}))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(expectedResponseJSON))
+	})
+
+	Context("when filters are provided", func() {
+		BeforeEach(func() {
+			var err error
+			request, err = http.NewRequest("GET", "/networking/v1/external/policies/events?id=app-a,app-b&since=2026-01-01T00:00:00Z&until=2026-01-31T12:00:00-05:00", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("passes them to the store", func() {
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(fakeStore.EventsCallCount()).To(Equal(1))
+			filter, _ := fakeStore.EventsArgsForCall(0)
+			Expect(filter.AppGuids).To(Equal([]string{"app-a", "app-b"}))
+			Expect(filter.Since).To(BeTemporally("==", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+			Expect(filter.Until).To(BeTemporally("==", time.Date(2026, 1, 31, 17, 0, 0, 0, time.UTC)))
+		})
+	})
+
+	Context("when a page is requested", func() {
+		BeforeEach(func() {
+			var err error
+			request, err = http.NewRequest("GET", "/networking/v1/external/policies/events?from=5&limit=1", nil)
+			Expect(err).NotTo(HaveOccurred())
+			fakeStore.EventsReturns([]store.PolicyEvent{{
+				ID:        5,
+				Action:    store.PolicyEventDelete,
+				Actor:     "some-user",
+				ClientID:  "some-client",
+				CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			}}, store.Pagination{Next: 7}, nil)
+		})
+
+		It("passes the page to the store and returns the next event id", func() {
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			_, page := fakeStore.EventsArgsForCall(0)
+			Expect(page).To(Equal(store.Page{From: 5, Limit: 1}))
+			Expect(resp.Body).To(MatchJSON(`{
+				"total_events": 1,
+				"next": 7,
+				"events": [{
+					"id": 5,
+					"action": "delete",
+					"actor": "some-user",
+					"client_id": "some-client",
+					"policies": [],
+					"timestamp": "2026-01-02T03:04:05Z"
+				}]
+			}`))
+		})
+	})
+
+	DescribeTable("when a page is invalid",
+		func(query, expectedDescription string) {
+			var err error
+			request, err = http.NewRequest("GET", "/networking/v1/external/policies/events?"+query, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeStore.EventsCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+
+			_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(err).To(MatchError(expectedDescription))
+			Expect(description).To(Equal(expectedDescription))
+		},
+		Entry("limit", "limit=-1", "invalid value for 'limit' parameter"),
+		Entry("from", "from=first", "invalid value for 'from' parameter"),
+	)
+
+	DescribeTable("when a time filter is invalid",
+		func(query, expectedDescription string) {
+			var err error
+			request, err = http.NewRequest("GET", "/networking/v1/external/policies/events?"+query, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeStore.EventsCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError(expectedDescription))
+			Expect(description).To(Equal(expectedDescription))
+		},
+		Entry("since", "since=yesterday", "invalid value for 'since' parameter: must be an RFC3339 timestamp"),
+		Entry("until", "until=2026-01-01", "invalid value for 'until' parameter: must be an RFC3339 timestamp"),
+	)
+
+	Context("when the store throws an error", func() {
+		BeforeEach(func() {
+			fakeStore.EventsReturns(nil, store.Pagination{}, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database read failed"))
+		})
+	})
+
+	Context("when the events cannot be marshaled", func() {
+		BeforeEach(func() {
+			marshaler.MarshalStub = func(interface{}) ([]byte, error) {
+				return nil, errors.New("grapes")
+			}
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLogger(handler.ServeHTTP, resp, request, logger)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("grapes"))
+			Expect(description).To(Equal("database marshalling failed"))
+		})
+	})
+})
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+// Features of policies that policy agents released before them do not
+// understand. Agents list the features that they support in the features query
+// parameter of the internal API, e.g. ?features=icmp,all,deny. Policies that need any
+// other feature are left out, as an older agent would apply them wrongly. For
+// agents without deny, the allow policies that a deny policy overrides are left
+// out as well, so that they do not allow the denied traffic.
+const (
+	PolicyFeatureICMP = "icmp"
+	PolicyFeatureAll  = "all"
+	PolicyFeatureDeny = "deny"
+)
+
+type policyFeatures map[string]bool
+
+func parsePolicyFeatures(queryValues url.Values) policyFeatures {
+	features := policyFeatures{}
+	for _, value := range queryValues["features"] {
+		for _, feature := range strings.Split(value, ",") {
+			if feature = strings.TrimSpace(feature); feature != "" {
+				features[feature] = true
+			}
+		}
+	}
+	return features
+}
+
+// String lists the features in a stable order, e.g. for an ETag
+func (f policyFeatures) String() string {
+	features := make([]string, 0, len(f))
+	for feature := range f {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+	return strings.Join(features, ",")
+}
+
+func (f policyFeatures) supports(protocol string, deny bool) bool {
+	if deny && !f[PolicyFeatureDeny] {
+		return false
+	}
+	switch protocol {
+	case "icmp":
+		return f[PolicyFeatureICMP]
+	case "all":
+		return f[PolicyFeatureAll]
+	default:
+		return true
+	}
+}
+
+func (f policyFeatures) supportedStorePolicies(policies []store.Policy) []store.Policy {
+	var denyPolicies []store.Policy
+	if !f[PolicyFeatureDeny] {
+		for _, policy := range policies {
+			if policy.IsDeny() {
+				denyPolicies = append(denyPolicies, policy)
+			}
+		}
+	}
+
+	supported := []store.Policy{}
+	for _, policy := range policies {
+		if f.supports(policy.Destination.Protocol, policy.IsDeny()) && !overridden(policy, denyPolicies) {
+			supported = append(supported, policy)
+		}
+	}
+	return supported
+}
+
+func overridden(policy store.Policy, denyPolicies []store.Policy) bool {
+	for _, deny := range denyPolicies {
+		if deny.Overrides(policy) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f policyFeatures) supportedPolicies(policies []api.Policy) []api.Policy {
+	supported := []api.Policy{}
+	for _, policy := range policies {
+		if f.supports(policy.Destination.Protocol, policy.Action == store.PolicyActionDeny) {
+			supported = append(supported, policy)
+		}
+	}
+	return supported
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 
 	"code.cloudfoundry.org/policy-server/cc_client"
 	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/tracing"
 	"code.cloudfoundry.org/policy-server/uaa_client"
 )
 
@@ -12,16 +14,26 @@ type PolicyFilter struct {
 	CCClient  cc_client.CCClient
 	UAAClient uaa_client.UAAClient
 	ChunkSize int
+	Roles     Roles
 }
 
-func NewPolicyFilter(uaaClient uaa_client.UAAClient, ccClient cc_client.CCClient, chunkSize int) *PolicyFilter {
+func NewPolicyFilter(uaaClient uaa_client.UAAClient, ccClient cc_client.CCClient, chunkSize int, roles Roles) *PolicyFilter {
 	return &PolicyFilter{
 		CCClient:  ccClient,
 		UAAClient: uaaClient,
 		ChunkSize: chunkSize,
+		Roles:     roles,
 	}
 }
 
+// Traced returns a copy of the filter whose clients record spans as children
+// of the span in the context
+func (f *PolicyFilter) Traced(ctx context.Context) interface{} {
+	return tracing.BindFields(ctx, f)
+}
+
+// FilterPolicies returns the policies between apps of spaces that the subject
+// has a role with the read permission in
 func (f *PolicyFilter) FilterPolicies(policies []store.Policy, subjectToken uaa_client.CheckTokenResponse) ([]store.Policy, error) {
 	for _, scope := range subjectToken.Scope {
 		if scope == "network.admin" {
@@ -48,9 +60,20 @@ func (f *PolicyFilter) FilterPolicies(policies []store.Policy, subjectToken uaa_
 
 	appSpaces := flatten(appSpacesList)
 
-	subjectSpaces, err := f.CCClient.GetSubjectSpaces(token, subjectToken.Subject)
+	subjectRoles, err := f.CCClient.GetSubjectRoles(token, subjectToken.Subject)
 	if err != nil {
-		return nil, fmt.Errorf("getting subject spaces: %s", err)
+		return nil, fmt.Errorf("getting subject roles: %s", err)
+	}
+
+	subjectSpaces, orgGUIDs := f.Roles.granted(subjectRoles, PermissionRead)
+	if len(orgGUIDs) > 0 {
+		orgSpaces, err := f.CCClient.GetSpaces(token, cc_client.ResourceFilter{ParentGUIDs: orgGUIDs})
+		if err != nil {
+			return nil, fmt.Errorf("getting org spaces: %s", err)
+		}
+		for _, space := range orgSpaces {
+			subjectSpaces[space.GUID] = struct{}{}
+		}
 	}
 
 	filtered := filter(policies, appSpaces, subjectSpaces)
@@ -88,7 +111,13 @@ func filter(policies []store.Policy, appSpaces map[string]string, subjectSpaces
 	filtered := []store.Policy{}
 
 	for _, policy := range policies {
-		_, sourceFound := subjectSpaces[appSpaces[policy.Source.ID]]
+		var sourceFound bool
+		switch policy.Source.Type {
+		case "":
+			_, sourceFound = subjectSpaces[appSpaces[policy.Source.ID]]
+		case store.GroupTypeSpace:
+			_, sourceFound = subjectSpaces[policy.Source.ID]
+		}
 		_, destFound := subjectSpaces[appSpaces[policy.Destination.ID]]
 		if sourceFound && destFound {
 			filtered = append(filtered, policy)
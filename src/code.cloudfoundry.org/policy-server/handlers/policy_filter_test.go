@@ -3,6 +3,7 @@ package handlers_test
 import (
 	"errors"
 
+	"code.cloudfoundry.org/policy-server/cc_client"
 	ccfakes "code.cloudfoundry.org/policy-server/cc_client/fakes"
 	"code.cloudfoundry.org/policy-server/handlers"
 	"code.cloudfoundry.org/policy-server/store"
@@ -28,6 +29,7 @@ var _ = Describe("PolicyFilter", func() {
 			CCClient:  fakeCCClient,
 			UAAClient: fakeUAAClient,
 			ChunkSize: 100,
+			Roles:     handlers.DefaultRoles(),
 		}
 		policies = []store.Policy{
 			{
@@ -60,15 +62,15 @@ var _ = Describe("PolicyFilter", func() {
 			"app-guid-4": "space-4",
 		}
 
-		subjectSpaces := map[string]struct{}{
-			"space-1": {},
-			"space-2": {},
-			"space-3": {},
+		subjectRoles := []cc_client.Role{
+			{Type: cc_client.RoleSpaceDeveloper, SpaceGUID: "space-1"},
+			{Type: cc_client.RoleSpaceAuditor, SpaceGUID: "space-2"},
+			{Type: cc_client.RoleSpaceManager, SpaceGUID: "space-3"},
 		}
 
 		fakeUAAClient.GetTokenReturns("policy-server-token", nil)
 		fakeCCClient.GetAppSpacesReturns(appSpaces, nil)
-		fakeCCClient.GetSubjectSpacesReturns(subjectSpaces, nil)
+		fakeCCClient.GetSubjectRolesReturns(subjectRoles, nil)
 	})
 
 	Describe("FilterPolicies", func() {
@@ -83,9 +85,9 @@ var _ = Describe("PolicyFilter", func() {
 			Expect(token).To(Equal("policy-server-token"))
 			Expect(appGUIDs).To(ConsistOf([]string{"app-guid-1", "app-guid-2", "app-guid-3", "app-guid-4"}))
 
-			Expect(fakeCCClient.GetSubjectSpacesCallCount()).To(Equal(1))
+			Expect(fakeCCClient.GetSubjectRolesCallCount()).To(Equal(1))
 
-			token, subjectId := fakeCCClient.GetSubjectSpacesArgsForCall(0)
+			token, subjectId := fakeCCClient.GetSubjectRolesArgsForCall(0)
 			Expect(token).To(Equal("policy-server-token"))
 			Expect(subjectId).To(Equal("some-developer-guid"))
 
@@ -121,9 +123,9 @@ var _ = Describe("PolicyFilter", func() {
 				Expect(token).To(Equal("policy-server-token"))
 				Expect(appGUIDs).To(ConsistOf([]string{"app-guid-1", "app-guid-2", "app-guid-3", "app-guid-4"}))
 
-				Expect(fakeCCClient.GetSubjectSpacesCallCount()).To(Equal(1))
+				Expect(fakeCCClient.GetSubjectRolesCallCount()).To(Equal(1))
 
-				token, subjectId := fakeCCClient.GetSubjectSpacesArgsForCall(0)
+				token, subjectId := fakeCCClient.GetSubjectRolesArgsForCall(0)
 				Expect(token).To(Equal("policy-server-token"))
 				Expect(subjectId).To(Equal("some-client-id"))
 
@@ -141,9 +143,89 @@ var _ = Describe("PolicyFilter", func() {
 			})
 		})
 
+		Context("when a policy source is a space or an org", func() {
+			BeforeEach(func() {
+				policies = append(policies, store.Policy{
+					Source:      store.Source{ID: "space-1", Type: store.GroupTypeSpace},
+					Destination: store.Destination{ID: "app-guid-2"},
+				}, store.Policy{
+					Source:      store.Source{ID: "space-4", Type: store.GroupTypeSpace},
+					Destination: store.Destination{ID: "app-guid-2"},
+				}, store.Policy{
+					Source:      store.Source{ID: "org-1", Type: store.GroupTypeOrg},
+					Destination: store.Destination{ID: "app-guid-2"},
+				})
+			})
+
+			It("only returns space policies for spaces the user can access and no org policies", func() {
+				filteredPolicies, err := policyFilter.FilterPolicies(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, appGUIDs := fakeCCClient.GetAppSpacesArgsForCall(0)
+				Expect(appGUIDs).To(ConsistOf([]string{"app-guid-1", "app-guid-2", "app-guid-3", "app-guid-4"}))
+
+				Expect(filteredPolicies).To(Equal([]store.Policy{
+					policies[0],
+					{
+						Source:      store.Source{ID: "space-1", Type: store.GroupTypeSpace},
+						Destination: store.Destination{ID: "app-guid-2"},
+					},
+				}))
+			})
+		})
+
+		Context("when a role does not grant the read permission", func() {
+			BeforeEach(func() {
+				roles, err := handlers.NewRoles(nil, map[string][]string{"space_auditor": {}})
+				Expect(err).NotTo(HaveOccurred())
+				policyFilter.Roles = roles
+			})
+
+			It("filters out the policies of its space", func() {
+				filteredPolicies, err := policyFilter.FilterPolicies(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(filteredPolicies).To(BeEmpty())
+			})
+		})
+
+		Context("when the subject has an org role", func() {
+			BeforeEach(func() {
+				fakeCCClient.GetSubjectRolesReturns([]cc_client.Role{
+					{Type: cc_client.RoleSpaceDeveloper, SpaceGUID: "space-1"},
+					{Type: cc_client.RoleOrgManager, OrgGUID: "org-1"},
+				}, nil)
+				fakeCCClient.GetSpacesReturns([]cc_client.Resource{
+					{GUID: "space-2", ParentGUID: "org-1"},
+				}, nil)
+			})
+
+			It("includes the policies of every space of the org", func() {
+				filteredPolicies, err := policyFilter.FilterPolicies(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(filteredPolicies).To(Equal([]store.Policy{policies[0]}))
+
+				Expect(fakeCCClient.GetSpacesCallCount()).To(Equal(1))
+				token, filter := fakeCCClient.GetSpacesArgsForCall(0)
+				Expect(token).To(Equal("policy-server-token"))
+				Expect(filter).To(Equal(cc_client.ResourceFilter{ParentGUIDs: []string{"org-1"}}))
+			})
+
+			Context("when getting the spaces of the org fails", func() {
+				BeforeEach(func() {
+					fakeCCClient.GetSpacesReturns(nil, errors.New("banana"))
+				})
+
+				It("returns a useful error", func() {
+					filtered, err := policyFilter.FilterPolicies(policies, tokenData)
+					Expect(err).To(MatchError("getting org spaces: banana"))
+					Expect(filtered).To(BeNil())
+				})
+			})
+		})
+
 		Context("when the filter results in zero policies", func() {
 			BeforeEach(func() {
-				fakeCCClient.GetSubjectSpacesReturns(map[string]struct{}{}, nil)
+				fakeCCClient.GetSubjectRolesReturns([]cc_client.Role{}, nil)
 			})
 
 			It("returns a non-null, but empty, slice of policies", func() {
@@ -165,7 +247,7 @@ var _ = Describe("PolicyFilter", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(fakeUAAClient.GetTokenCallCount()).To(Equal(0))
 				Expect(fakeCCClient.GetAppSpacesCallCount()).To(Equal(0))
-				Expect(fakeCCClient.GetSubjectSpacesCallCount()).To(Equal(0))
+				Expect(fakeCCClient.GetSubjectRolesCallCount()).To(Equal(0))
 				Expect(filtered).To(Equal(policies))
 			})
 		})
@@ -181,13 +263,13 @@ var _ = Describe("PolicyFilter", func() {
 			})
 		})
 
-		Context("when the getting the subject spaces fails", func() {
+		Context("when the getting the subject roles fails", func() {
 			BeforeEach(func() {
-				fakeCCClient.GetSubjectSpacesReturns(nil, errors.New("banana"))
+				fakeCCClient.GetSubjectRolesReturns(nil, errors.New("banana"))
 			})
 			It("returns a useful error", func() {
 				filtered, err := policyFilter.FilterPolicies(policies, tokenData)
-				Expect(err).To(MatchError("getting subject spaces: banana"))
+				Expect(err).To(MatchError("getting subject roles: banana"))
 				Expect(filtered).To(BeNil())
 			})
 		})
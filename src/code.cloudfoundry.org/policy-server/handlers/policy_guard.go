@@ -1,25 +1,38 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 
 	"code.cloudfoundry.org/policy-server/cc_client"
 	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/tracing"
 	"code.cloudfoundry.org/policy-server/uaa_client"
 )
 
 type PolicyGuard struct {
 	CCClient  cc_client.CCClient
 	UAAClient uaa_client.UAAClient
+	Roles     Roles
 }
 
-func NewPolicyGuard(uaaClient uaa_client.UAAClient, ccClient cc_client.CCClient) *PolicyGuard {
+func NewPolicyGuard(uaaClient uaa_client.UAAClient, ccClient cc_client.CCClient, roles Roles) *PolicyGuard {
 	return &PolicyGuard{
 		CCClient:  ccClient,
 		UAAClient: uaaClient,
+		Roles:     roles,
 	}
 }
 
+// Traced returns a copy of the guard whose clients record spans as children
+// of the span in the context
+func (g *PolicyGuard) Traced(ctx context.Context) interface{} {
+	return tracing.BindFields(ctx, g)
+}
+
+// CheckAccess returns whether the subject has a role that grants the write
+// permission in the spaces of every app of the policies. Deny policies need
+// network.admin.
 func (g *PolicyGuard) CheckAccess(policies []store.Policy, subjectToken uaa_client.CheckTokenResponse) (bool, error) {
 	for _, scope := range subjectToken.Scope {
 		if scope == "network.admin" {
@@ -27,6 +40,15 @@ func (g *PolicyGuard) CheckAccess(policies []store.Policy, subjectToken uaa_clie
 		}
 	}
 
+	// only network admins may allow traffic from every app of a space or org,
+	// and only they may deny traffic, so that the developers of an app cannot
+	// remove the deny policies that are set for it
+	for _, policy := range policies {
+		if policy.Source.Type != "" || policy.IsDeny() {
+			return false, nil
+		}
+	}
+
 	token, err := g.UAAClient.GetToken()
 	if err != nil {
 		return false, fmt.Errorf("getting token: %s", err)
@@ -36,6 +58,10 @@ func (g *PolicyGuard) CheckAccess(policies []store.Policy, subjectToken uaa_clie
 	if err != nil {
 		return false, fmt.Errorf("getting space guids: %s", err)
 	}
+	subjectRoles, err := g.CCClient.GetSubjectRoles(token, subjectToken.Subject)
+	if err != nil {
+		return false, fmt.Errorf("getting subject roles: %s", err)
+	}
 	for _, guid := range spaceGUIDs {
 		space, err := g.CCClient.GetSpace(token, guid)
 		if err != nil {
@@ -44,11 +70,7 @@ func (g *PolicyGuard) CheckAccess(policies []store.Policy, subjectToken uaa_clie
 		if space == nil {
 			return false, nil
 		}
-		subjectSpace, err := g.CCClient.GetSubjectSpace(token, subjectToken.Subject, *space)
-		if err != nil {
-			return false, fmt.Errorf("getting space with guid %s: %s", guid, err)
-		}
-		if subjectSpace == nil {
+		if !g.Roles.Grants(subjectRoles, guid, space.Entity.OrganizationGUID, PermissionWrite) {
 			return false, nil
 		}
 	}
@@ -68,7 +90,9 @@ func (g *PolicyGuard) IsNetworkAdmin(subjectToken uaa_client.CheckTokenResponse)
 func uniqueAppGUIDs(policies []store.Policy) []string {
 	var set = make(map[string]struct{})
 	for _, policy := range policies {
-		set[policy.Source.ID] = struct{}{}
+		if policy.Source.Type == "" {
+			set[policy.Source.ID] = struct{}{}
+		}
 		set[policy.Destination.ID] = struct{}{}
 	}
 	var appGUIDs = make([]string, 0, len(set))
@@ -32,6 +32,7 @@ var _ = Describe("PolicyGuard", func() {
 		policyGuard = &handlers.PolicyGuard{
 			CCClient:  fakeCCClient,
 			UAAClient: fakeUAAClient,
+			Roles:     handlers.DefaultRoles(),
 		}
 		policies = []store.Policy{
 			{
@@ -96,26 +97,11 @@ var _ = Describe("PolicyGuard", func() {
 				}
 			}
 		}
-		fakeCCClient.GetSubjectSpaceStub = func(token, subjectId string, space cc_client.SpaceResponse) (*cc_client.SpaceResource, error) {
-			switch space {
-			case space1:
-				{
-					return &cc_client.SpaceResource{Entity: space1.Entity}, nil
-				}
-			case space2:
-				{
-					return &cc_client.SpaceResource{Entity: space2.Entity}, nil
-				}
-			case space3:
-				{
-					return &cc_client.SpaceResource{Entity: space3.Entity}, nil
-				}
-			default:
-				{
-					return nil, errors.New("stub called with unexpected guid")
-				}
-			}
-		}
+		fakeCCClient.GetSubjectRolesReturns([]cc_client.Role{
+			{Type: cc_client.RoleSpaceDeveloper, SpaceGUID: "space-guid-1"},
+			{Type: cc_client.RoleSpaceDeveloper, SpaceGUID: "space-guid-2"},
+			{Type: cc_client.RoleSpaceDeveloper, SpaceGUID: "space-guid-3"},
+		}, nil)
 	})
 
 	Describe("IsNetworkAdmin", func() {
@@ -166,19 +152,10 @@ var _ = Describe("PolicyGuard", func() {
 			token, guid = fakeCCClient.GetSpaceArgsForCall(2)
 			Expect(token).To(Equal("policy-server-token"))
 			Expect(guid).To(Equal("space-guid-3"))
-			Expect(fakeCCClient.GetSubjectSpaceCallCount()).To(Equal(3))
-			token, subjectId, checkSubjectSpace := fakeCCClient.GetSubjectSpaceArgsForCall(0)
-			Expect(token).To(Equal("policy-server-token"))
-			Expect(subjectId).To(Equal("some-developer-guid"))
-			Expect(checkSubjectSpace).To(Equal(space1))
-			token, subjectId, checkSubjectSpace = fakeCCClient.GetSubjectSpaceArgsForCall(1)
+			Expect(fakeCCClient.GetSubjectRolesCallCount()).To(Equal(1))
+			token, subjectId := fakeCCClient.GetSubjectRolesArgsForCall(0)
 			Expect(token).To(Equal("policy-server-token"))
 			Expect(subjectId).To(Equal("some-developer-guid"))
-			Expect(checkSubjectSpace).To(Equal(space2))
-			token, subjectId, checkSubjectSpace = fakeCCClient.GetSubjectSpaceArgsForCall(2)
-			Expect(token).To(Equal("policy-server-token"))
-			Expect(subjectId).To(Equal("some-developer-guid"))
-			Expect(checkSubjectSpace).To(Equal(space3))
 			Expect(authorized).To(BeTrue())
 		})
 
@@ -208,19 +185,10 @@ var _ = Describe("PolicyGuard", func() {
 				token, guid = fakeCCClient.GetSpaceArgsForCall(2)
 				Expect(token).To(Equal("policy-server-token"))
 				Expect(guid).To(Equal("space-guid-3"))
-				Expect(fakeCCClient.GetSubjectSpaceCallCount()).To(Equal(3))
-				token, subjectId, checkSubjectSpace := fakeCCClient.GetSubjectSpaceArgsForCall(0)
-				Expect(token).To(Equal("policy-server-token"))
-				Expect(subjectId).To(Equal("some-client-id"))
-				Expect(checkSubjectSpace).To(Equal(space1))
-				token, subjectId, checkSubjectSpace = fakeCCClient.GetSubjectSpaceArgsForCall(1)
+				Expect(fakeCCClient.GetSubjectRolesCallCount()).To(Equal(1))
+				token, subjectId := fakeCCClient.GetSubjectRolesArgsForCall(0)
 				Expect(token).To(Equal("policy-server-token"))
 				Expect(subjectId).To(Equal("some-client-id"))
-				Expect(checkSubjectSpace).To(Equal(space2))
-				token, subjectId, checkSubjectSpace = fakeCCClient.GetSubjectSpaceArgsForCall(2)
-				Expect(token).To(Equal("policy-server-token"))
-				Expect(subjectId).To(Equal("some-client-id"))
-				Expect(checkSubjectSpace).To(Equal(space3))
 				Expect(authorized).To(BeTrue())
 			})
 		})
@@ -236,11 +204,63 @@ var _ = Describe("PolicyGuard", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(fakeUAAClient.GetTokenCallCount()).To(Equal(0))
 				Expect(fakeCCClient.GetSpaceCallCount()).To(Equal(0))
-				Expect(fakeCCClient.GetSubjectSpaceCallCount()).To(Equal(0))
+				Expect(fakeCCClient.GetSubjectRolesCallCount()).To(Equal(0))
 				Expect(authorized).To(BeTrue())
 			})
 		})
 
+		Context("when a policy source is a space or an org", func() {
+			BeforeEach(func() {
+				policies[1].Source = store.Source{ID: "some-space-guid", Type: store.GroupTypeSpace}
+			})
+
+			It("returns false without making extra calls to UAA or CC", func() {
+				authorized, err := policyGuard.CheckAccess(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeUAAClient.GetTokenCallCount()).To(Equal(0))
+				Expect(fakeCCClient.GetSpaceGUIDsCallCount()).To(Equal(0))
+				Expect(authorized).To(BeFalse())
+			})
+
+			Context("when the token has network.admin scope", func() {
+				BeforeEach(func() {
+					tokenData.Scope = []string{"network.admin"}
+				})
+
+				It("returns true", func() {
+					authorized, err := policyGuard.CheckAccess(policies, tokenData)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(authorized).To(BeTrue())
+				})
+			})
+		})
+
+		Context("when a policy denies traffic", func() {
+			BeforeEach(func() {
+				policies[1].Action = store.PolicyActionDeny
+			})
+
+			It("returns false without making extra calls to UAA or CC", func() {
+				authorized, err := policyGuard.CheckAccess(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeUAAClient.GetTokenCallCount()).To(Equal(0))
+				Expect(fakeCCClient.GetSpaceGUIDsCallCount()).To(Equal(0))
+				Expect(authorized).To(BeFalse())
+			})
+
+			Context("when the token has network.admin scope", func() {
+				BeforeEach(func() {
+					tokenData.Scope = []string{"network.admin"}
+				})
+
+				It("returns true", func() {
+					authorized, err := policyGuard.CheckAccess(policies, tokenData)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(authorized).To(BeTrue())
+				})
+			})
+		})
+
 		Context("when the getting one of the the spaces returns nil", func() {
 			BeforeEach(func() {
 				fakeCCClient.GetSpaceReturns(nil, nil)
@@ -252,15 +272,88 @@ var _ = Describe("PolicyGuard", func() {
 			})
 		})
 
-		Context("when the getting the subjects spaces returns nil", func() {
+		Context("when the subject only has a role without the write permission in one of the spaces", func() {
 			BeforeEach(func() {
-				fakeCCClient.GetSubjectSpaceReturns(nil, nil)
+				fakeCCClient.GetSubjectRolesReturns([]cc_client.Role{
+					{Type: cc_client.RoleSpaceDeveloper, SpaceGUID: "space-guid-1"},
+					{Type: cc_client.RoleSpaceAuditor, SpaceGUID: "space-guid-2"},
+					{Type: cc_client.RoleSpaceDeveloper, SpaceGUID: "space-guid-3"},
+				}, nil)
 			})
 			It("returns false", func() {
 				authorized, err := policyGuard.CheckAccess(policies, tokenData)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(authorized).To(BeFalse())
 			})
+
+			Context("when the roles are configured to grant it", func() {
+				BeforeEach(func() {
+					roles, err := handlers.NewRoles(nil, map[string][]string{"space_auditor": {"read", "write"}})
+					Expect(err).NotTo(HaveOccurred())
+					policyGuard.Roles = roles
+				})
+				It("returns true", func() {
+					authorized, err := policyGuard.CheckAccess(policies, tokenData)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(authorized).To(BeTrue())
+				})
+			})
+		})
+
+		Context("when the subject is a space manager but not a developer in one of the spaces", func() {
+			BeforeEach(func() {
+				fakeCCClient.GetSubjectRolesReturns([]cc_client.Role{
+					{Type: cc_client.RoleSpaceDeveloper, SpaceGUID: "space-guid-1"},
+					{Type: cc_client.RoleSpaceManager, SpaceGUID: "space-guid-2"},
+					{Type: cc_client.RoleSpaceDeveloper, SpaceGUID: "space-guid-3"},
+				}, nil)
+			})
+			It("returns false by default", func() {
+				authorized, err := policyGuard.CheckAccess(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(authorized).To(BeFalse())
+			})
+
+			Context("when space managers are granted the write permission", func() {
+				BeforeEach(func() {
+					roles, err := handlers.NewRoles(nil, map[string][]string{"space_manager": {"read", "write"}})
+					Expect(err).NotTo(HaveOccurred())
+					policyGuard.Roles = roles
+				})
+				It("returns true", func() {
+					authorized, err := policyGuard.CheckAccess(policies, tokenData)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(authorized).To(BeTrue())
+				})
+			})
+		})
+
+		Context("when the subject has an org role in the org of a space", func() {
+			BeforeEach(func() {
+				fakeCCClient.GetSubjectRolesReturns([]cc_client.Role{
+					{Type: cc_client.RoleSpaceDeveloper, SpaceGUID: "space-guid-1"},
+					{Type: cc_client.RoleOrgManager, OrgGUID: "org-guid-2"},
+					{Type: cc_client.RoleSpaceDeveloper, SpaceGUID: "space-guid-3"},
+				}, nil)
+			})
+			It("returns false when the org role does not grant the write permission", func() {
+				authorized, err := policyGuard.CheckAccess(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(authorized).To(BeFalse())
+			})
+
+			Context("when the org role grants the write permission", func() {
+				BeforeEach(func() {
+					roles, err := handlers.NewRoles(nil, map[string][]string{"org_manager": {"read", "write"}})
+					Expect(err).NotTo(HaveOccurred())
+					policyGuard.Roles = roles
+				})
+				It("returns true", func() {
+					authorized, err := policyGuard.CheckAccess(policies, tokenData)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(authorized).To(BeTrue())
+				})
+			})
 		})
 
 		Context("when the getting the policy server token fails", func() {
@@ -296,13 +389,13 @@ var _ = Describe("PolicyGuard", func() {
 			})
 		})
 
-		Context("when the getting the subjects spaces fails", func() {
+		Context("when the getting the subject roles fails", func() {
 			BeforeEach(func() {
-				fakeCCClient.GetSubjectSpaceReturns(nil, errors.New("banana"))
+				fakeCCClient.GetSubjectRolesReturns(nil, errors.New("banana"))
 			})
 			It("returns a useful error", func() {
 				authorized, err := policyGuard.CheckAccess(policies, tokenData)
-				Expect(err).To(MatchError("getting space with guid space-guid-1: banana"))
+				Expect(err).To(MatchError("getting subject roles: banana"))
 				Expect(authorized).To(BeFalse())
 			})
 		})
@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/cc_client"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/tracing"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+)
+
+// ccResourcesChunkSize is the number of apps, spaces or orgs that are looked
+// up in the Cloud-Controller at once when resolving a policy manifest
+const ccResourcesChunkSize = 100
+
+//counterfeiter:generate -o fakes/policy_manifest_resolver.go --fake-name PolicyManifestResolver . policyManifestResolver
+type policyManifestResolver interface {
+	ManifestPolicies(policies []store.Policy) ([]api.ManifestPolicy, []store.Policy, error)
+	Policies(manifestPolicies []api.ManifestPolicy) ([]api.Policy, error)
+}
+
+// InvalidManifestError lists the problems with the names of a policy
+// manifest, such as apps that cannot be found
+type InvalidManifestError struct {
+	Problems []string
+}
+
+func (e InvalidManifestError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// PolicyManifestResolver translates between the guids of the sources and
+// destinations of policies and the names of their apps, spaces and orgs
+type PolicyManifestResolver struct {
+	UAAClient uaa_client.UAAClient
+	CCClient  cc_client.CCClient
+}
+
+func NewPolicyManifestResolver(uaaClient uaa_client.UAAClient, ccClient cc_client.CCClient) *PolicyManifestResolver {
+	return &PolicyManifestResolver{
+		UAAClient: uaaClient,
+		CCClient:  ccClient,
+	}
+}
+
+// Traced returns a copy of the resolver whose clients record spans as children
+// of the span in the context
+func (r *PolicyManifestResolver) Traced(ctx context.Context) interface{} {
+	return tracing.BindFields(ctx, r)
+}
+
+type getResourcesFunc func(token string, filter cc_client.ResourceFilter) ([]cc_client.Resource, error)
+
+// ManifestPolicies names the sources and destinations of the policies.
+// Policies whose app, space or org no longer exists cannot be named, and are
+// returned separately.
+func (r *PolicyManifestResolver) ManifestPolicies(policies []store.Policy) ([]api.ManifestPolicy, []store.Policy, error) {
+	token, err := r.UAAClient.GetToken()
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting token: %s", err)
+	}
+
+	var appGUIDs, spaceGUIDs, orgGUIDs []string
+	for _, policy := range policies {
+		switch policy.Source.GroupType() {
+		case store.GroupTypeSpace:
+			spaceGUIDs = append(spaceGUIDs, policy.Source.ID)
+		case store.GroupTypeOrg:
+			orgGUIDs = append(orgGUIDs, policy.Source.ID)
+		default:
+			appGUIDs = append(appGUIDs, policy.Source.ID)
+		}
+		appGUIDs = append(appGUIDs, policy.Destination.ID)
+	}
+
+	apps, err := resourcesByGUID(token, r.CCClient.GetApps, appGUIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting apps: %s", err)
+	}
+	for _, app := range apps {
+		spaceGUIDs = append(spaceGUIDs, app.ParentGUID)
+	}
+
+	spaces, err := resourcesByGUID(token, r.CCClient.GetSpaces, spaceGUIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting spaces: %s", err)
+	}
+	for _, space := range spaces {
+		orgGUIDs = append(orgGUIDs, space.ParentGUID)
+	}
+
+	orgs, err := resourcesByGUID(token, r.CCClient.GetOrgs, orgGUIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting orgs: %s", err)
+	}
+
+	names := resourceNames{apps: apps, spaces: spaces, orgs: orgs}
+	manifestPolicies := []api.ManifestPolicy{}
+	var unnamed []store.Policy
+	for _, policy := range policies {
+		source, sourceFound := names.group(policy.Source.ID, policy.Source.GroupType())
+		destination, destinationFound := names.group(policy.Destination.ID, store.GroupTypeApp)
+		if !sourceFound || !destinationFound {
+			unnamed = append(unnamed, policy)
+			continue
+		}
+		manifestPolicies = append(manifestPolicies, api.MapStorePolicyManifest(policy, source, destination))
+	}
+	return manifestPolicies, unnamed, nil
+}
+
+// Policies resolves the names of the sources and destinations of the manifest
+// policies to guids. Names that cannot be resolved fail with an
+// InvalidManifestError.
+func (r *PolicyManifestResolver) Policies(manifestPolicies []api.ManifestPolicy) ([]api.Policy, error) {
+	token, err := r.UAAClient.GetToken()
+	if err != nil {
+		return nil, fmt.Errorf("getting token: %s", err)
+	}
+
+	var groups []api.ManifestGroup
+	for _, policy := range manifestPolicies {
+		groups = append(groups, policy.Source, policy.Destination.ManifestGroup)
+	}
+
+	orgNames := map[string][]string{}
+	for _, group := range groups {
+		orgNames[""] = append(orgNames[""], group.Org)
+	}
+	orgs, err := resourcesByName(token, r.CCClient.GetOrgs, orgNames)
+	if err != nil {
+		return nil, fmt.Errorf("getting orgs: %s", err)
+	}
+
+	spaceNames := map[string][]string{}
+	for _, group := range groups {
+		if orgGUID, ok := orgs[resourceKey{name: group.Org}]; ok && group.Space != "" {
+			spaceNames[orgGUID] = append(spaceNames[orgGUID], group.Space)
+		}
+	}
+	spaces, err := resourcesByName(token, r.CCClient.GetSpaces, spaceNames)
+	if err != nil {
+		return nil, fmt.Errorf("getting spaces: %s", err)
+	}
+
+	appNames := map[string][]string{}
+	for _, group := range groups {
+		orgGUID := orgs[resourceKey{name: group.Org}]
+		if spaceGUID, ok := spaces[resourceKey{parentGUID: orgGUID, name: group.Space}]; ok && group.App != "" {
+			appNames[spaceGUID] = append(appNames[spaceGUID], group.App)
+		}
+	}
+	apps, err := resourcesByName(token, r.CCClient.GetApps, appNames)
+	if err != nil {
+		return nil, fmt.Errorf("getting apps: %s", err)
+	}
+
+	guids := resourceGUIDs{apps: apps, spaces: spaces, orgs: orgs}
+	var problems []string
+	policies := []api.Policy{}
+	for _, manifestPolicy := range manifestPolicies {
+		sourceID, sourceType, sourceProblem := guids.resolve(manifestPolicy.Source)
+		destinationID, destinationType, destinationProblem := guids.resolve(manifestPolicy.Destination.ManifestGroup)
+		if destinationProblem == "" && destinationType != store.GroupTypeApp {
+			destinationProblem = fmt.Sprintf("destination %s is not an app", describeGroup(manifestPolicy.Destination.ManifestGroup))
+		}
+		for _, problem := range []string{sourceProblem, destinationProblem} {
+			if problem != "" && !containsString(problems, problem) {
+				problems = append(problems, problem)
+			}
+		}
+
+		source := api.Source{ID: sourceID}
+		if sourceType != store.GroupTypeApp {
+			source.Type = sourceType
+		}
+		policies = append(policies, manifestPolicy.AsPolicy(source, destinationID))
+	}
+	if len(problems) > 0 {
+		return nil, InvalidManifestError{Problems: problems}
+	}
+	return policies, nil
+}
+
+// resourcesByGUID looks up the apps, spaces or orgs with the given guids and
+// returns them by guid
+func resourcesByGUID(token string, getResources getResourcesFunc, guids []string) (map[string]cc_client.Resource, error) {
+	guids = uniqueStrings(guids)
+
+	resources := map[string]cc_client.Resource{}
+	for i := 0; i < len(guids); i += ccResourcesChunkSize {
+		last := i + ccResourcesChunkSize
+		if last > len(guids) {
+			last = len(guids)
+		}
+		chunk, err := getResources(token, cc_client.ResourceFilter{GUIDs: guids[i:last]})
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range chunk {
+			resources[resource.GUID] = resource
+		}
+	}
+	return resources, nil
+}
+
+// resourceKey identifies an app by its space and name, a space by its org and
+// name, or an org by its name alone
+type resourceKey struct {
+	parentGUID string
+	name       string
+}
+
+// resourcesByName looks up the apps, spaces or orgs with the given names,
+// which are keyed by the guid of the space or org that they are in, and
+// returns their guids
+func resourcesByName(token string, getResources getResourcesFunc, namesByParent map[string][]string) (map[resourceKey]string, error) {
+	var parentGUIDs []string
+	for parentGUID := range namesByParent {
+		parentGUIDs = append(parentGUIDs, parentGUID)
+	}
+	sort.Strings(parentGUIDs)
+
+	guids := map[resourceKey]string{}
+	for _, parentGUID := range parentGUIDs {
+		var filterParentGUIDs []string
+		if parentGUID != "" {
+			filterParentGUIDs = []string{parentGUID}
+		}
+
+		names := uniqueStrings(namesByParent[parentGUID])
+		for i := 0; i < len(names); i += ccResourcesChunkSize {
+			last := i + ccResourcesChunkSize
+			if last > len(names) {
+				last = len(names)
+			}
+			chunk, err := getResources(token, cc_client.ResourceFilter{Names: names[i:last], ParentGUIDs: filterParentGUIDs})
+			if err != nil {
+				return nil, err
+			}
+			for _, resource := range chunk {
+				guids[resourceKey{parentGUID: parentGUID, name: resource.Name}] = resource.GUID
+			}
+		}
+	}
+	return guids, nil
+}
+
+type resourceNames struct {
+	apps   map[string]cc_client.Resource
+	spaces map[string]cc_client.Resource
+	orgs   map[string]cc_client.Resource
+}
+
+// group names the app, space or org with the given guid, along with the space
+// and org that it is in
+func (n resourceNames) group(guid, groupType string) (api.ManifestGroup, bool) {
+	var group api.ManifestGroup
+	if groupType == store.GroupTypeApp {
+		app, ok := n.apps[guid]
+		if !ok {
+			return api.ManifestGroup{}, false
+		}
+		group.App = app.Name
+		guid = app.ParentGUID
+		groupType = store.GroupTypeSpace
+	}
+	if groupType == store.GroupTypeSpace {
+		space, ok := n.spaces[guid]
+		if !ok {
+			return api.ManifestGroup{}, false
+		}
+		group.Space = space.Name
+		guid = space.ParentGUID
+	}
+	org, ok := n.orgs[guid]
+	if !ok {
+		return api.ManifestGroup{}, false
+	}
+	group.Org = org.Name
+	return group, true
+}
+
+type resourceGUIDs struct {
+	apps   map[resourceKey]string
+	spaces map[resourceKey]string
+	orgs   map[resourceKey]string
+}
+
+// resolve returns the guid and group type of the named app, space or org, or
+// a description of the problem when it cannot be resolved
+func (g resourceGUIDs) resolve(group api.ManifestGroup) (string, string, string) {
+	if group.App != "" && group.Space == "" {
+		return "", "", fmt.Sprintf("app '%s' must be given with its space", group.App)
+	}
+
+	orgGUID, ok := g.orgs[resourceKey{name: group.Org}]
+	if !ok {
+		return "", "", fmt.Sprintf("cannot find org '%s'", group.Org)
+	}
+	if group.Space == "" {
+		return orgGUID, store.GroupTypeOrg, ""
+	}
+
+	spaceGUID, ok := g.spaces[resourceKey{parentGUID: orgGUID, name: group.Space}]
+	if !ok {
+		return "", "", fmt.Sprintf("cannot find space '%s' in org '%s'", group.Space, group.Org)
+	}
+	if group.App == "" {
+		return spaceGUID, store.GroupTypeSpace, ""
+	}
+
+	appGUID, ok := g.apps[resourceKey{parentGUID: spaceGUID, name: group.App}]
+	if !ok {
+		return "", "", fmt.Sprintf("cannot find app '%s' in space '%s' of org '%s'", group.App, group.Space, group.Org)
+	}
+	return appGUID, store.GroupTypeApp, ""
+}
+
+func describeGroup(group api.ManifestGroup) string {
+	if group.Space == "" {
+		return fmt.Sprintf("org '%s'", group.Org)
+	}
+	return fmt.Sprintf("space '%s' in org '%s'", group.Space, group.Org)
+}
+
+func uniqueStrings(values []string) []string {
+	unique := []string{}
+	for _, value := range values {
+		if !containsString(unique, value) {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,291 @@
+package handlers_test
+
+import (
+	"errors"
+	"fmt"
+
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/cc_client"
+	ccfakes "code.cloudfoundry.org/policy-server/cc_client/fakes"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/store"
+	uaafakes "code.cloudfoundry.org/policy-server/uaa_client/fakes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PolicyManifestResolver", func() {
+	var (
+		resolver      *handlers.PolicyManifestResolver
+		fakeUAAClient *uaafakes.UAAClient
+		fakeCCClient  *ccfakes.CCClient
+		apps          []cc_client.Resource
+		spaces        []cc_client.Resource
+		orgs          []cc_client.Resource
+	)
+
+	filterResources := func(resources []cc_client.Resource, filter cc_client.ResourceFilter) []cc_client.Resource {
+		matches := func(values []string, value string) bool {
+			for _, v := range values {
+				if v == value {
+					return true
+				}
+			}
+			return false
+		}
+		filtered := []cc_client.Resource{}
+		for _, resource := range resources {
+			if len(filter.GUIDs) > 0 && !matches(filter.GUIDs, resource.GUID) {
+				continue
+			}
+			if len(filter.Names) > 0 && !matches(filter.Names, resource.Name) {
+				continue
+			}
+			if len(filter.ParentGUIDs) > 0 && !matches(filter.ParentGUIDs, resource.ParentGUID) {
+				continue
+			}
+			filtered = append(filtered, resource)
+		}
+		return filtered
+	}
+
+	BeforeEach(func() {
+		fakeUAAClient = &uaafakes.UAAClient{}
+		fakeUAAClient.GetTokenReturns("policy-server-token", nil)
+		fakeCCClient = &ccfakes.CCClient{}
+		resolver = handlers.NewPolicyManifestResolver(fakeUAAClient, fakeCCClient)
+
+		orgs = []cc_client.Resource{
+			{GUID: "org-1-guid", Name: "org-1"},
+			{GUID: "org-2-guid", Name: "org-2"},
+		}
+		spaces = []cc_client.Resource{
+			{GUID: "space-1-guid", Name: "dev", ParentGUID: "org-1-guid"},
+			{GUID: "space-2-guid", Name: "dev", ParentGUID: "org-2-guid"},
+		}
+		apps = []cc_client.Resource{
+			{GUID: "app-1-guid", Name: "frontend", ParentGUID: "space-1-guid"},
+			{GUID: "app-2-guid", Name: "backend", ParentGUID: "space-1-guid"},
+			{GUID: "app-3-guid", Name: "backend", ParentGUID: "space-2-guid"},
+		}
+
+		fakeCCClient.GetAppsStub = func(token string, filter cc_client.ResourceFilter) ([]cc_client.Resource, error) {
+			return filterResources(apps, filter), nil
+		}
+		fakeCCClient.GetSpacesStub = func(token string, filter cc_client.ResourceFilter) ([]cc_client.Resource, error) {
+			return filterResources(spaces, filter), nil
+		}
+		fakeCCClient.GetOrgsStub = func(token string, filter cc_client.ResourceFilter) ([]cc_client.Resource, error) {
+			return filterResources(orgs, filter), nil
+		}
+	})
+
+	Describe("ManifestPolicies", func() {
+		var policies []store.Policy
+
+		BeforeEach(func() {
+			policies = []store.Policy{
+				{
+					Source:      store.Source{ID: "app-1-guid"},
+					Destination: store.Destination{ID: "app-2-guid", Protocol: "tcp", Port: 8080, Ports: store.Ports{Start: 8080, End: 8080}},
+				},
+				{
+					Source:      store.Source{ID: "space-2-guid", Type: "space"},
+					Destination: store.Destination{ID: "app-3-guid", Protocol: "udp", Ports: store.Ports{Start: 53, End: 53}},
+				},
+				{
+					Source:      store.Source{ID: "org-1-guid", Type: "org"},
+					Destination: store.Destination{ID: "app-3-guid", Protocol: "icmp", ICMPType: 8},
+					Action:      store.PolicyActionDeny,
+				},
+			}
+		})
+
+		It("names the sources and destinations of the policies", func() {
+			manifestPolicies, unnamed, err := resolver.ManifestPolicies(policies)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unnamed).To(BeEmpty())
+
+			Expect(manifestPolicies).To(HaveLen(3))
+			Expect(manifestPolicies[0].Source).To(Equal(api.ManifestGroup{Org: "org-1", Space: "dev", App: "frontend"}))
+			Expect(manifestPolicies[0].Destination.ManifestGroup).To(Equal(api.ManifestGroup{Org: "org-1", Space: "dev", App: "backend"}))
+			Expect(manifestPolicies[0].Destination.Protocol).To(Equal("tcp"))
+			Expect(manifestPolicies[1].Source).To(Equal(api.ManifestGroup{Org: "org-2", Space: "dev"}))
+			Expect(manifestPolicies[1].Destination.ManifestGroup).To(Equal(api.ManifestGroup{Org: "org-2", Space: "dev", App: "backend"}))
+			Expect(manifestPolicies[2].Source).To(Equal(api.ManifestGroup{Org: "org-1"}))
+			Expect(manifestPolicies[2].Action).To(Equal("deny"))
+
+			Expect(fakeUAAClient.GetTokenCallCount()).To(Equal(1))
+			token, filter := fakeCCClient.GetAppsArgsForCall(0)
+			Expect(token).To(Equal("policy-server-token"))
+			Expect(filter.GUIDs).To(ConsistOf("app-1-guid", "app-2-guid", "app-3-guid"))
+		})
+
+		Context("when an app no longer exists", func() {
+			BeforeEach(func() {
+				apps = apps[:2]
+			})
+
+			It("returns the policies that cannot be named separately", func() {
+				manifestPolicies, unnamed, err := resolver.ManifestPolicies(policies)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(manifestPolicies).To(HaveLen(1))
+				Expect(unnamed).To(Equal(policies[1:]))
+			})
+		})
+
+		Context("when there are more apps than fit in one request", func() {
+			BeforeEach(func() {
+				policies = nil
+				for i := 0; i < 150; i++ {
+					policies = append(policies, store.Policy{
+						Source:      store.Source{ID: fmt.Sprintf("app-guid-%d", i)},
+						Destination: store.Destination{ID: "app-1-guid"},
+					})
+				}
+			})
+
+			It("looks the apps up in chunks", func() {
+				_, _, err := resolver.ManifestPolicies(policies)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeCCClient.GetAppsCallCount()).To(Equal(2))
+				_, filter := fakeCCClient.GetAppsArgsForCall(0)
+				Expect(filter.GUIDs).To(HaveLen(100))
+				_, filter = fakeCCClient.GetAppsArgsForCall(1)
+				Expect(filter.GUIDs).To(HaveLen(51))
+			})
+		})
+
+		Context("when getting the token fails", func() {
+			BeforeEach(func() {
+				fakeUAAClient.GetTokenReturns("", errors.New("banana"))
+			})
+
+			It("returns an error", func() {
+				_, _, err := resolver.ManifestPolicies(policies)
+				Expect(err).To(MatchError("getting token: banana"))
+			})
+		})
+
+		Context("when getting the spaces fails", func() {
+			BeforeEach(func() {
+				fakeCCClient.GetSpacesStub = nil
+				fakeCCClient.GetSpacesReturns(nil, errors.New("banana"))
+			})
+
+			It("returns an error", func() {
+				_, _, err := resolver.ManifestPolicies(policies)
+				Expect(err).To(MatchError("getting spaces: banana"))
+			})
+		})
+	})
+
+	Describe("Policies", func() {
+		var manifestPolicies []api.ManifestPolicy
+
+		BeforeEach(func() {
+			manifestPolicies = []api.ManifestPolicy{
+				{
+					Source: api.ManifestGroup{Org: "org-1", Space: "dev", App: "frontend"},
+					Destination: api.ManifestDestination{
+						ManifestGroup: api.ManifestGroup{Org: "org-1", Space: "dev", App: "backend"},
+						Protocol:      "tcp",
+						Ports:         api.Ports{Start: 8080, End: 8080},
+					},
+					Description: "frontend to backend",
+				},
+				{
+					Source: api.ManifestGroup{Org: "org-2", Space: "dev"},
+					Destination: api.ManifestDestination{
+						ManifestGroup: api.ManifestGroup{Org: "org-2", Space: "dev", App: "backend"},
+						Protocol:      "udp",
+						Ports:         api.Ports{Start: 53, End: 53},
+					},
+				},
+				{
+					Source: api.ManifestGroup{Org: "org-1"},
+					Destination: api.ManifestDestination{
+						ManifestGroup: api.ManifestGroup{Org: "org-2", Space: "dev", App: "backend"},
+						Protocol:      "all",
+					},
+					Action: "deny",
+				},
+			}
+		})
+
+		It("resolves the names to guids", func() {
+			policies, err := resolver.Policies(manifestPolicies)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policies).To(Equal([]api.Policy{
+				{
+					Source:      api.Source{ID: "app-1-guid"},
+					Destination: api.Destination{ID: "app-2-guid", Protocol: "tcp", Ports: api.Ports{Start: 8080, End: 8080}},
+					Description: "frontend to backend",
+				},
+				{
+					Source:      api.Source{ID: "space-2-guid", Type: "space"},
+					Destination: api.Destination{ID: "app-3-guid", Protocol: "udp", Ports: api.Ports{Start: 53, End: 53}},
+				},
+				{
+					Source:      api.Source{ID: "org-1-guid", Type: "org"},
+					Destination: api.Destination{ID: "app-3-guid", Protocol: "all"},
+					Action:      "deny",
+				},
+			}))
+
+			Expect(fakeCCClient.GetOrgsCallCount()).To(Equal(1))
+			_, filter := fakeCCClient.GetOrgsArgsForCall(0)
+			Expect(filter).To(Equal(cc_client.ResourceFilter{Names: []string{"org-1", "org-2"}}))
+
+			Expect(fakeCCClient.GetSpacesCallCount()).To(Equal(2))
+			_, filter = fakeCCClient.GetSpacesArgsForCall(0)
+			Expect(filter).To(Equal(cc_client.ResourceFilter{Names: []string{"dev"}, ParentGUIDs: []string{"org-1-guid"}}))
+			_, filter = fakeCCClient.GetSpacesArgsForCall(1)
+			Expect(filter).To(Equal(cc_client.ResourceFilter{Names: []string{"dev"}, ParentGUIDs: []string{"org-2-guid"}}))
+		})
+
+		Context("when names cannot be resolved", func() {
+			BeforeEach(func() {
+				manifestPolicies[0].Source.App = "missing"
+				manifestPolicies[1].Source.Org = "missing-org"
+				manifestPolicies[2].Destination.App = ""
+			})
+
+			It("returns an invalid manifest error listing every problem", func() {
+				_, err := resolver.Policies(manifestPolicies)
+				Expect(err).To(Equal(handlers.InvalidManifestError{Problems: []string{
+					"cannot find app 'missing' in space 'dev' of org 'org-1'",
+					"cannot find org 'missing-org'",
+					"destination space 'dev' in org 'org-2' is not an app",
+				}}))
+				Expect(err).To(MatchError("cannot find app 'missing' in space 'dev' of org 'org-1'; " +
+					"cannot find org 'missing-org'; destination space 'dev' in org 'org-2' is not an app"))
+			})
+		})
+
+		Context("when an app is given without its space", func() {
+			BeforeEach(func() {
+				manifestPolicies[0].Source.Space = ""
+			})
+
+			It("returns an invalid manifest error", func() {
+				_, err := resolver.Policies(manifestPolicies)
+				Expect(err).To(MatchError("app 'frontend' must be given with its space"))
+			})
+		})
+
+		Context("when getting the apps fails", func() {
+			BeforeEach(func() {
+				fakeCCClient.GetAppsStub = nil
+				fakeCCClient.GetAppsReturns(nil, errors.New("banana"))
+			})
+
+			It("returns an error", func() {
+				_, err := resolver.Policies(manifestPolicies)
+				Expect(err).To(MatchError("getting apps: banana"))
+			})
+		})
+	})
+})
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/cf-networking-helpers/httperror"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+const (
+	overlapsAllow  = "allow"
+	overlapsReject = "reject"
+	overlapsMerge  = "merge"
+)
+
+const (
+	overlapReason          = "one or more policies overlap with policies of the same source, destination and protocol"
+	metadataConflictReason = "one or more policies overlap with policies that have different metadata and cannot be merged"
+)
+
+func parseOverlaps(queryValues url.Values) (string, error) {
+	value := queryValues.Get("overlaps")
+	switch value {
+	case "":
+		return overlapsAllow, nil
+	case overlapsAllow, overlapsReject, overlapsMerge:
+		return value, nil
+	default:
+		return "", fmt.Errorf("invalid value for 'overlaps' parameter: must be %s, %s or %s", overlapsAllow, overlapsReject, overlapsMerge)
+	}
+}
+
+// existingOverlapPolicies returns the unexpired policies between the sources
+// and destinations of the policies, without tags
+func existingOverlapPolicies(policyStore policyStore, policies []store.Policy) ([]store.Policy, error) {
+	var srcGuids, dstGuids []string
+	for _, p := range policies {
+		srcGuids = append(srcGuids, p.Source.ID)
+		dstGuids = append(dstGuids, p.Destination.ID)
+	}
+
+	existing, err := policyStore.ByGuids(srcGuids, dstGuids, true)
+	if err != nil {
+		return nil, err
+	}
+
+	existing = unexpiredPolicies(existing, time.Now())
+	for i := range existing {
+		existing[i].Source.Tag = ""
+		existing[i].Destination.Tag = ""
+	}
+	return existing, nil
+}
+
+// newPolicyOverlaps returns the overlaps that include at least one of the
+// policies that does not exist yet. Policies that already exist are not
+// overlaps, creating them again changes nothing.
+func newPolicyOverlaps(policies, existing []store.Policy) []store.PolicyOverlap {
+	combined := append([]store.Policy{}, existing...)
+	var added []store.Policy
+	for _, p := range policies {
+		if !containsPolicy(combined, p) {
+			combined = append(combined, p)
+			added = append(added, p)
+		}
+	}
+
+	var overlaps []store.PolicyOverlap
+	for _, overlap := range store.FindOverlaps(combined) {
+		for _, p := range overlap.Policies {
+			if containsPolicy(added, p) {
+				overlaps = append(overlaps, overlap)
+				break
+			}
+		}
+	}
+	return overlaps
+}
+
+// checkDryRunOverlaps checks the policies that would be created for overlaps
+// as a create does, and returns those that pass and the failures of the
+// others: every overlapping policy with reject, and the overlapping policies
+// that cannot be merged with merge
+func checkDryRunOverlaps(policies []dryRunPolicy, policyStore policyStore, overlaps string) ([]dryRunPolicy, []api.PolicyFailure, error) {
+	if overlaps == overlapsAllow || len(policies) == 0 {
+		return policies, nil, nil
+	}
+
+	storePolicies := dryRunStorePolicies(policies)
+	existing, err := existingOverlapPolicies(policyStore, storePolicies)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	policyOverlaps, reason := newPolicyOverlaps(storePolicies, existing), overlapReason
+	if overlaps == overlapsMerge {
+		policyOverlaps, reason = metadataConflicts(policyOverlaps), metadataConflictReason
+	}
+
+	var passed []dryRunPolicy
+	var failures []api.PolicyFailure
+	for _, p := range policies {
+		if overlapsContain(policyOverlaps, p.policy) {
+			failures = append(failures, api.PolicyFailure{
+				Index:  p.index,
+				Policy: p.raw,
+				Code:   api.FailureOverlap,
+				Reason: reason,
+			})
+			continue
+		}
+		passed = append(passed, p)
+	}
+	return passed, failures, nil
+}
+
+// metadataConflicts returns the overlaps whose policies have different
+// metadata, which cannot be merged
+func metadataConflicts(overlaps []store.PolicyOverlap) []store.PolicyOverlap {
+	var conflicts []store.PolicyOverlap
+	for _, overlap := range overlaps {
+		if overlap.MetadataConflicts() {
+			conflicts = append(conflicts, overlap)
+		}
+	}
+	return conflicts
+}
+
+// mergePolicyOverlaps returns the policies to create, where the policies of
+// each overlap are replaced by the merged policy, and the existing policies
+// that the merged policies replace
+func mergePolicyOverlaps(policies, existing []store.Policy, overlaps []store.PolicyOverlap) ([]store.Policy, []store.Policy) {
+	var created, replaced []store.Policy
+	for _, p := range policies {
+		if !overlapsContain(overlaps, p) {
+			created = append(created, p)
+		}
+	}
+	for _, overlap := range overlaps {
+		created = append(created, overlap.Merged)
+		for _, p := range overlap.Replaced() {
+			if containsPolicy(existing, p) {
+				replaced = append(replaced, p)
+			}
+		}
+	}
+	return created, replaced
+}
+
+// overlapFailures adds the policies of the request that do not exist yet and
+// overlap to the metadata of the error, with their index in the request
+func overlapFailures(err error, bodyBytes []byte, policies, existing []store.Policy, overlaps []store.PolicyOverlap) error {
+	rawPolicies, parseErr := parseDryRunPolicies(bodyBytes)
+	if parseErr != nil || len(rawPolicies) != len(policies) {
+		return err
+	}
+
+	failures := []api.PolicyFailure{}
+	for i, p := range policies {
+		if overlapsContain(overlaps, p) && !containsPolicy(existing, p) {
+			failures = append(failures, api.PolicyFailure{
+				Index:  i,
+				Policy: rawPolicies[i],
+				Code:   api.FailureOverlap,
+				Reason: err.Error(),
+			})
+		}
+	}
+	return httperror.NewMetadataError(err, map[string]interface{}{"failures": failures})
+}
+
+func overlapsContain(overlaps []store.PolicyOverlap, policy store.Policy) bool {
+	for _, overlap := range overlaps {
+		if containsPolicy(overlap.Policies, policy) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPolicy(policies []store.Policy, policy store.Policy) bool {
+	for _, p := range policies {
+		if p.Equals(policy) {
+			return true
+		}
+	}
+	return false
+}
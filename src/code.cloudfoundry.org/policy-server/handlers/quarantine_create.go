@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+//counterfeiter:generate -o fakes/quarantine_store.go --fake-name QuarantineStore . quarantineStore
+type quarantineStore interface {
+	Quarantine(appGuid string, actor store.Actor) error
+	Release(appGuid string, actor store.Actor) error
+}
+
+type QuarantineCreate struct {
+	Store         quarantineStore
+	RataAdapter   rataAdapter
+	ErrorResponse errorResponse
+}
+
+func NewQuarantineCreate(store quarantineStore, rataAdapter rataAdapter, errorResponse errorResponse) *QuarantineCreate {
+	return &QuarantineCreate{
+		Store:         store,
+		RataAdapter:   rataAdapter,
+		ErrorResponse: errorResponse,
+	}
+}
+
+func (h *QuarantineCreate) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("quarantine-app")
+	tokenData := getTokenData(req)
+
+	guid := h.RataAdapter.Param(req, "guid")
+	err := h.Store.Quarantine(guid, getActor(tokenData))
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database write failed")
+		return
+	}
+	logger.Info("quarantined-app", lager.Data{"app_guid": guid, "userName": tokenData.UserName})
+
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write([]byte(`{}`))
+}
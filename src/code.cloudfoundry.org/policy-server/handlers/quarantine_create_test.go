@@ -0,0 +1,87 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Quarantine create handler", func() {
+	var (
+		request           *http.Request
+		handler           *handlers.QuarantineCreate
+		resp              *httptest.ResponseRecorder
+		fakeStore         *fakes.QuarantineStore
+		fakeRataAdapter   *fakes.RataAdapter
+		fakeErrorResponse *fakes.ErrorResponse
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		tokenData         uaa_client.CheckTokenResponse
+	)
+
+	BeforeEach(func() {
+		var err error
+		request, err = http.NewRequest("POST", "/networking/v1/external/apps/some-app-guid/quarantine", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeStore = &fakes.QuarantineStore{}
+		fakeRataAdapter = &fakes.RataAdapter{}
+		fakeRataAdapter.ParamReturns("some-app-guid")
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("quarantine-app")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		handler = handlers.NewQuarantineCreate(fakeStore, fakeRataAdapter, fakeErrorResponse)
+		tokenData = uaa_client.CheckTokenResponse{
+			Scope:    []string{"network.admin"},
+			UserName: "some-admin",
+			ClientID: "some-client",
+		}
+		resp = httptest.NewRecorder()
+	})
+
+	It("quarantines the app on behalf of the user", func() {
+		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+		Expect(fakeRataAdapter.ParamCallCount()).To(Equal(1))
+		_, name := fakeRataAdapter.ParamArgsForCall(0)
+		Expect(name).To(Equal("guid"))
+
+		Expect(fakeStore.QuarantineCallCount()).To(Equal(1))
+		appGuid, actor := fakeStore.QuarantineArgsForCall(0)
+		Expect(appGuid).To(Equal("some-app-guid"))
+		Expect(actor).To(Equal(store.Actor{Name: "some-admin", ClientID: "some-client"}))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{}`))
+	})
+
+	Context("when the store throws an error", func() {
+		BeforeEach(func() {
+			fakeStore.QuarantineReturns(errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database write failed"))
+		})
+	})
+})
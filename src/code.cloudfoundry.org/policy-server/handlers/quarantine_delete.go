@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager/v3"
+)
+
+type QuarantineDelete struct {
+	Store         quarantineStore
+	RataAdapter   rataAdapter
+	ErrorResponse errorResponse
+}
+
+func NewQuarantineDelete(store quarantineStore, rataAdapter rataAdapter, errorResponse errorResponse) *QuarantineDelete {
+	return &QuarantineDelete{
+		Store:         store,
+		RataAdapter:   rataAdapter,
+		ErrorResponse: errorResponse,
+	}
+}
+
+func (h *QuarantineDelete) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("release-app")
+	tokenData := getTokenData(req)
+
+	guid := h.RataAdapter.Param(req, "guid")
+	err := h.Store.Release(guid, getActor(tokenData))
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database write failed")
+		return
+	}
+	logger.Info("released-app", lager.Data{"app_guid": guid, "userName": tokenData.UserName})
+
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write([]byte(`{}`))
+}
@@ -1,46 +1,361 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 
+	"code.cloudfoundry.org/policy-server/cc_client"
 	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/tracing"
 	"code.cloudfoundry.org/policy-server/uaa_client"
 )
 
+// ccAppSpacesChunkSize is the number of apps whose spaces are requested from
+// the Cloud-Controller at once when counting the policies of spaces and orgs
+const ccAppSpacesChunkSize = 100
+
+//counterfeiter:generate -o fakes/quota_guard_store.go --fake-name QuotaGuardStore . quotaGuardStore
+type quotaGuardStore interface {
+	ByGuids(srcGuids, destGuids []string, inSourceAndDest bool) ([]store.Policy, error)
+	UnscopedSources() ([]store.Source, error)
+	SetSourceScopes([]store.SourceScope) error
+	PolicyCountsByScope(scopeType string, guids, excludedSourceGuids []string) (map[string]int, error)
+}
+
 type QuotaGuard struct {
-	Store       policyStore
+	Store       quotaGuardStore
+	QuotasStore store.PolicyQuotasStore
+	UAAClient   uaa_client.UAAClient
+	CCClient    cc_client.CCClient
 	MaxPolicies int
 }
 
-func NewQuotaGuard(store policyStore, maxPolicies int) *QuotaGuard {
+func NewQuotaGuard(store quotaGuardStore, quotasStore store.PolicyQuotasStore, uaaClient uaa_client.UAAClient,
+	ccClient cc_client.CCClient, maxPolicies int) *QuotaGuard {
 	return &QuotaGuard{
 		Store:       store,
+		QuotasStore: quotasStore,
+		UAAClient:   uaaClient,
+		CCClient:    ccClient,
 		MaxPolicies: maxPolicies,
 	}
 }
 
+// Traced returns a copy of the guard whose clients and stores record spans as children
+// of the span in the context
+func (g *QuotaGuard) Traced(ctx context.Context) interface{} {
+	return tracing.BindFields(ctx, g)
+}
+
 func (g *QuotaGuard) CheckAccess(policies []store.Policy, subjectToken uaa_client.CheckTokenResponse) (bool, error) {
-	for _, scope := range subjectToken.Scope {
-		if scope == "network.admin" {
-			return true, nil
+	if !isNetworkAdmin(subjectToken) {
+		appGuids := uniqueAppGUIDs(policies)
+		toAddSourceCounts := sourceCounts(policies, appGuids)
+		sourcePolicies, err := g.Store.ByGuids(appGuids, []string{}, false)
+		if err != nil {
+			return false, fmt.Errorf("getting policies: %s", err)
+		}
+		currentAppCounts := sourceCounts(sourcePolicies, appGuids)
+		for _, appGuid := range appGuids {
+			if currentAppCounts[appGuid]+toAddSourceCounts[appGuid] > g.MaxPolicies {
+				return false, nil
+			}
+		}
+	}
+
+	return g.checkSpaceAndOrgQuotas(policies, nil)
+}
+
+// CheckReplaceAccess checks the quota for a request that replaces every
+// policy of the given source app, so existing policies are not counted.
+func (g *QuotaGuard) CheckReplaceAccess(sourceGuid string, policies []store.Policy, subjectToken uaa_client.CheckTokenResponse) (bool, error) {
+	if !isNetworkAdmin(subjectToken) && sourceCounts(policies, []string{sourceGuid})[sourceGuid] > g.MaxPolicies {
+		return false, nil
+	}
+
+	return g.checkSpaceAndOrgQuotas(policies, []string{sourceGuid})
+}
+
+// CheckReconcileAccess checks the quota for a request that replaces every
+// policy of the given sources. Only space and org quotas are checked, since
+// reconciling is limited to network admins.
+func (g *QuotaGuard) CheckReconcileAccess(sourceGuids []string, policies []store.Policy) (bool, error) {
+	return g.checkSpaceAndOrgQuotas(policies, sourceGuids)
+}
+
+// checkSpaceAndOrgQuotas checks the quotas of the spaces and orgs that the
+// sources of the policies are in. These quotas are set by network admins and
+// apply to them as well. The policies of replacedSourceGuids are not counted
+// because they are being replaced.
+func (g *QuotaGuard) checkSpaceAndOrgQuotas(policies []store.Policy, replacedSourceGuids []string) (bool, error) {
+	if len(policies) == 0 {
+		return true, nil
+	}
+
+	quotas, err := g.QuotasStore.Quotas()
+	if err != nil {
+		return false, fmt.Errorf("getting quotas: %s", err)
+	}
+	if len(quotas) == 0 {
+		return true, nil
+	}
+
+	token, err := g.UAAClient.GetToken()
+	if err != nil {
+		return false, fmt.Errorf("getting token: %s", err)
+	}
+
+	counter := &quotaScopeCounter{
+		ccClient:     g.CCClient,
+		token:        token,
+		spaceOrgGUID: make(map[string]string),
+	}
+
+	newPolicies, err := g.newPolicies(policies, replacedSourceGuids)
+	if err != nil {
+		return false, err
+	}
+	newCounts, err := counter.count(newPolicies, hasQuotaType(quotas, store.GroupTypeOrg))
+	if err != nil {
+		return false, err
+	}
+
+	// only count the existing policies when a quota applies to the new ones
+	var applicableQuotas []store.Quota
+	for _, quota := range quotas {
+		if newCounts[quotaScope{quota.Type, quota.GUID}] > 0 {
+			applicableQuotas = append(applicableQuotas, quota)
 		}
 	}
+	if len(applicableQuotas) == 0 {
+		return true, nil
+	}
 
-	appGuids := uniqueAppGUIDs(policies)
-	toAddSourceCounts := sourceCounts(policies, appGuids)
-	sourcePolicies, err := g.Store.ByGuids(appGuids, []string{}, false)
+	err = g.storeSourceScopes(counter)
 	if err != nil {
-		return false, fmt.Errorf("getting policies: %s", err)
+		return false, err
 	}
-	currentAppCounts := sourceCounts(sourcePolicies, appGuids)
-	for _, appGuid := range appGuids {
-		if currentAppCounts[appGuid]+toAddSourceCounts[appGuid] > g.MaxPolicies {
+
+	existingCounts := make(map[quotaScope]int)
+	for _, scopeType := range []string{store.GroupTypeSpace, store.GroupTypeOrg} {
+		var guids []string
+		for _, quota := range applicableQuotas {
+			if quota.Type == scopeType {
+				guids = append(guids, quota.GUID)
+			}
+		}
+		if len(guids) == 0 {
+			continue
+		}
+		counts, err := g.Store.PolicyCountsByScope(scopeType, guids, replacedSourceGuids)
+		if err != nil {
+			return false, fmt.Errorf("counting policies: %s", err)
+		}
+		for guid, count := range counts {
+			existingCounts[quotaScope{scopeType, guid}] = count
+		}
+	}
+
+	for _, quota := range applicableQuotas {
+		scope := quotaScope{quota.Type, quota.GUID}
+		if existingCounts[scope]+newCounts[scope] > quota.MaxPolicies {
 			return false, nil
 		}
 	}
 	return true, nil
 }
 
+// newPolicies leaves out the policies that already exist, so that they are
+// not counted twice. The policies of replaced sources are all new.
+func (g *QuotaGuard) newPolicies(policies []store.Policy, replacedSourceGuids []string) ([]store.Policy, error) {
+	var sourceGuids []string
+	for _, policy := range policies {
+		if !containsString(replacedSourceGuids, policy.Source.ID) && !containsString(sourceGuids, policy.Source.ID) {
+			sourceGuids = append(sourceGuids, policy.Source.ID)
+		}
+	}
+	if len(sourceGuids) == 0 {
+		return policies, nil
+	}
+
+	existingPolicies, err := g.Store.ByGuids(sourceGuids, []string{}, false)
+	if err != nil {
+		return nil, fmt.Errorf("getting policies: %s", err)
+	}
+	var newPolicies []store.Policy
+	for _, policy := range policies {
+		if containsString(replacedSourceGuids, policy.Source.ID) || !containsStorePolicy(existingPolicies, policy) {
+			newPolicies = append(newPolicies, policy)
+		}
+	}
+	return newPolicies, nil
+}
+
+// storeSourceScopes looks up the spaces and orgs of the sources of policies
+// that were created since their scopes were last stored, so that the
+// policies of every source are counted by the store
+func (g *QuotaGuard) storeSourceScopes(counter *quotaScopeCounter) error {
+	sources, err := g.Store.UnscopedSources()
+	if err != nil {
+		return fmt.Errorf("getting unscoped sources: %s", err)
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+
+	scopes, err := counter.scopes(sources, true)
+	if err != nil {
+		return err
+	}
+	var sourceScopes []store.SourceScope
+	for _, source := range sources {
+		sourceScopes = append(sourceScopes, scopes[source.ID])
+	}
+	err = g.Store.SetSourceScopes(sourceScopes)
+	if err != nil {
+		return fmt.Errorf("storing source scopes: %s", err)
+	}
+	return nil
+}
+
+type quotaScope struct {
+	scopeType string
+	guid      string
+}
+
+// quotaScopeCounter counts policies per space and org of their source. A
+// policy from an app counts towards the space of the app, and a policy from
+// an app or a space counts towards the org of the space.
+type quotaScopeCounter struct {
+	ccClient     cc_client.CCClient
+	token        string
+	spaceOrgGUID map[string]string
+}
+
+func (c *quotaScopeCounter) count(policies []store.Policy, countOrgs bool) (map[quotaScope]int, error) {
+	var sources []store.Source
+	for _, policy := range policies {
+		sources = append(sources, policy.Source)
+	}
+	scopes, err := c.scopes(sources, countOrgs)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[quotaScope]int)
+	for _, policy := range policies {
+		scope := scopes[policy.Source.ID]
+		if scope.SpaceGUID != "" {
+			counts[quotaScope{store.GroupTypeSpace, scope.SpaceGUID}]++
+		}
+		if scope.OrgGUID != "" {
+			counts[quotaScope{store.GroupTypeOrg, scope.OrgGUID}]++
+		}
+	}
+	return counts, nil
+}
+
+// scopes looks up the space and org of each source. The orgs of apps and
+// spaces are only looked up when withOrgs is set. The guids are left empty
+// for apps or spaces that no longer exist.
+func (c *quotaScopeCounter) scopes(sources []store.Source, withOrgs bool) (map[string]store.SourceScope, error) {
+	var appGuids []string
+	seen := make(map[string]struct{})
+	for _, source := range sources {
+		if source.Type != "" {
+			continue
+		}
+		if _, ok := seen[source.ID]; !ok {
+			seen[source.ID] = struct{}{}
+			appGuids = append(appGuids, source.ID)
+		}
+	}
+
+	appSpaces := make(map[string]string)
+	for i := 0; i < len(appGuids); i += ccAppSpacesChunkSize {
+		last := i + ccAppSpacesChunkSize
+		if last > len(appGuids) {
+			last = len(appGuids)
+		}
+		chunkSpaces, err := c.ccClient.GetAppSpaces(c.token, appGuids[i:last])
+		if err != nil {
+			return nil, fmt.Errorf("getting app spaces: %s", err)
+		}
+		for appGuid, spaceGuid := range chunkSpaces {
+			appSpaces[appGuid] = spaceGuid
+		}
+	}
+
+	scopes := make(map[string]store.SourceScope)
+	for _, source := range sources {
+		if _, ok := scopes[source.ID]; ok {
+			continue
+		}
+		scope := store.SourceScope{GUID: source.ID}
+		switch source.Type {
+		case "":
+			scope.SpaceGUID = appSpaces[source.ID]
+		case store.GroupTypeSpace:
+			scope.SpaceGUID = source.ID
+		case store.GroupTypeOrg:
+			scope.OrgGUID = source.ID
+		}
+		// an app that no longer exists has no space
+		if withOrgs && scope.SpaceGUID != "" {
+			orgGuid, err := c.orgGUID(scope.SpaceGUID)
+			if err != nil {
+				return nil, err
+			}
+			scope.OrgGUID = orgGuid
+		}
+		scopes[source.ID] = scope
+	}
+	return scopes, nil
+}
+
+func (c *quotaScopeCounter) orgGUID(spaceGuid string) (string, error) {
+	if orgGuid, ok := c.spaceOrgGUID[spaceGuid]; ok {
+		return orgGuid, nil
+	}
+	space, err := c.ccClient.GetSpace(c.token, spaceGuid)
+	if err != nil {
+		return "", fmt.Errorf("getting space with guid %s: %s", spaceGuid, err)
+	}
+	orgGuid := ""
+	if space != nil {
+		orgGuid = space.Entity.OrganizationGUID
+	}
+	c.spaceOrgGUID[spaceGuid] = orgGuid
+	return orgGuid, nil
+}
+
+func hasQuotaType(quotas []store.Quota, quotaType string) bool {
+	for _, quota := range quotas {
+		if quota.Type == quotaType {
+			return true
+		}
+	}
+	return false
+}
+
+func containsStorePolicy(policies []store.Policy, policy store.Policy) bool {
+	for _, p := range policies {
+		if p.Equals(policy) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNetworkAdmin(subjectToken uaa_client.CheckTokenResponse) bool {
+	for _, scope := range subjectToken.Scope {
+		if scope == "network.admin" {
+			return true
+		}
+	}
+	return false
+}
+
 func sourceCounts(policies []store.Policy, knownAppGuids []string) map[string]int {
 	var set = make(map[string]int)
 	for _, appGuid := range knownAppGuids {
@@ -3,10 +3,13 @@ package handlers_test
 import (
 	"errors"
 
+	"code.cloudfoundry.org/policy-server/cc_client"
+	ccfakes "code.cloudfoundry.org/policy-server/cc_client/fakes"
 	"code.cloudfoundry.org/policy-server/handlers"
 	"code.cloudfoundry.org/policy-server/store"
 	"code.cloudfoundry.org/policy-server/store/fakes"
 	"code.cloudfoundry.org/policy-server/uaa_client"
+	uaafakes "code.cloudfoundry.org/policy-server/uaa_client/fakes"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -14,15 +17,24 @@ import (
 
 var _ = Describe("QuotaGuard", func() {
 	var (
-		quotaGuard *handlers.QuotaGuard
-		fakeStore  *fakes.Store
-		policies   []store.Policy
-		tokenData  uaa_client.CheckTokenResponse
+		quotaGuard      *handlers.QuotaGuard
+		fakeStore       *fakes.Store
+		fakeQuotasStore *fakes.PolicyQuotasStore
+		fakeUAAClient   *uaafakes.UAAClient
+		fakeCCClient    *ccfakes.CCClient
+		policies        []store.Policy
+		tokenData       uaa_client.CheckTokenResponse
 	)
 	BeforeEach(func() {
 		fakeStore = &fakes.Store{}
+		fakeQuotasStore = &fakes.PolicyQuotasStore{}
+		fakeUAAClient = &uaafakes.UAAClient{}
+		fakeCCClient = &ccfakes.CCClient{}
 		quotaGuard = &handlers.QuotaGuard{
 			Store:       fakeStore,
+			QuotasStore: fakeQuotasStore,
+			UAAClient:   fakeUAAClient,
+			CCClient:    fakeCCClient,
 			MaxPolicies: 2,
 		}
 		tokenData = uaa_client.CheckTokenResponse{
@@ -53,6 +65,29 @@ var _ = Describe("QuotaGuard", func() {
 				Expect(authorized).To(BeTrue())
 			})
 		})
+		Context("when a policy allows all protocols", func() {
+			BeforeEach(func() {
+				fakeStore.ByGuidsReturns([]store.Policy{
+					{
+						Source:      store.Source{ID: "some-app-guid"},
+						Destination: store.Destination{ID: "some-other-guid", Protocol: "tcp"},
+					},
+				}, nil)
+				policies = []store.Policy{
+					{
+						Source:      store.Source{ID: "some-app-guid"},
+						Destination: store.Destination{ID: "yet-another-guid", Protocol: "all", Ports: store.Ports{Start: 1, End: 65535}},
+					},
+				}
+			})
+
+			It("counts it as one policy", func() {
+				authorized, err := quotaGuard.CheckAccess(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(authorized).To(BeTrue())
+			})
+		})
 		Context("when the additional policies exceed the quota", func() {
 			BeforeEach(func() {
 				fakeStore.ByGuidsReturns([]store.Policy{
@@ -107,4 +142,369 @@ var _ = Describe("QuotaGuard", func() {
 			Expect(authorized).To(BeTrue())
 		})
 	})
+
+	Describe("CheckReplaceAccess", func() {
+		BeforeEach(func() {
+			policies = policies[:2]
+			fakeStore.ByGuidsReturns([]store.Policy{
+				{
+					Source:      store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{ID: "existing-guid"},
+				},
+				{
+					Source:      store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{ID: "another-existing-guid"},
+				},
+			}, nil)
+		})
+
+		It("does not count the policies that will be replaced", func() {
+			authorized, err := quotaGuard.CheckReplaceAccess("some-app-guid", policies, tokenData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(authorized).To(BeTrue())
+			Expect(fakeStore.ByGuidsCallCount()).To(Equal(0))
+		})
+
+		Context("when the replacement policies exceed the quota", func() {
+			BeforeEach(func() {
+				policies = append(policies, store.Policy{
+					Source:      store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{ID: "one-too-many-guid"},
+				})
+			})
+
+			It("does not allow the replacement", func() {
+				authorized, err := quotaGuard.CheckReplaceAccess("some-app-guid", policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(authorized).To(BeFalse())
+			})
+
+			Context("when the subject is an admin", func() {
+				BeforeEach(func() {
+					tokenData.Scope = []string{"network.admin"}
+				})
+
+				It("allows the replacement", func() {
+					authorized, err := quotaGuard.CheckReplaceAccess("some-app-guid", policies, tokenData)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(authorized).To(BeTrue())
+				})
+			})
+		})
+	})
+
+	Describe("space and org quotas", func() {
+		var existingPolicies []store.Policy
+
+		BeforeEach(func() {
+			policies = []store.Policy{
+				{
+					Source:      store.Source{ID: "app-1"},
+					Destination: store.Destination{ID: "some-other-guid"},
+				},
+				{
+					Source:      store.Source{ID: "app-2"},
+					Destination: store.Destination{ID: "some-other-guid"},
+				},
+			}
+			existingPolicies = []store.Policy{
+				{
+					Source:      store.Source{ID: "app-3"},
+					Destination: store.Destination{ID: "some-other-guid"},
+				},
+			}
+			// every app is in space-1 of org-1
+			fakeStore.PolicyCountsByScopeStub = func(scopeType string, guids, excludedSourceGuids []string) (map[string]int, error) {
+				scopeGuid := map[string]string{"space": "space-1", "org": "org-1"}[scopeType]
+				excluded := map[string]bool{}
+				for _, guid := range excludedSourceGuids {
+					excluded[guid] = true
+				}
+				counts := map[string]int{}
+				for _, guid := range guids {
+					if guid != scopeGuid {
+						continue
+					}
+					for _, policy := range existingPolicies {
+						if !excluded[policy.Source.ID] {
+							counts[scopeGuid]++
+						}
+					}
+				}
+				return counts, nil
+			}
+			fakeUAAClient.GetTokenReturns("policy-server-token", nil)
+			fakeCCClient.GetAppSpacesStub = func(token string, appGUIDs []string) (map[string]string, error) {
+				spaces := map[string]string{}
+				for _, appGUID := range appGUIDs {
+					spaces[appGUID] = "space-1"
+				}
+				return spaces, nil
+			}
+			fakeCCClient.GetSpaceReturns(&cc_client.SpaceResponse{
+				Entity: cc_client.SpaceEntity{OrganizationGUID: "org-1"},
+			}, nil)
+		})
+
+		Context("when there are no quotas", func() {
+			It("does not look up the spaces of the apps", func() {
+				authorized, err := quotaGuard.CheckAccess(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(authorized).To(BeTrue())
+
+				Expect(fakeQuotasStore.QuotasCallCount()).To(Equal(1))
+				Expect(fakeUAAClient.GetTokenCallCount()).To(Equal(0))
+				Expect(fakeCCClient.GetAppSpacesCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the space quota is not exceeded", func() {
+			BeforeEach(func() {
+				fakeQuotasStore.QuotasReturns([]store.Quota{{Type: "space", GUID: "space-1", MaxPolicies: 3}}, nil)
+			})
+
+			It("allows policy creation", func() {
+				authorized, err := quotaGuard.CheckAccess(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(authorized).To(BeTrue())
+
+				Expect(fakeCCClient.GetAppSpacesCallCount()).To(Equal(1))
+				token, appGUIDs := fakeCCClient.GetAppSpacesArgsForCall(0)
+				Expect(token).To(Equal("policy-server-token"))
+				Expect(appGUIDs).To(ConsistOf("app-1", "app-2"))
+				Expect(fakeCCClient.GetSpaceCallCount()).To(Equal(0))
+			})
+
+			It("counts the existing policies of the space in the store", func() {
+				_, err := quotaGuard.CheckAccess(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeStore.AllCallCount()).To(Equal(0))
+				Expect(fakeStore.PolicyCountsByScopeCallCount()).To(Equal(1))
+				scopeType, guids, excludedSourceGuids := fakeStore.PolicyCountsByScopeArgsForCall(0)
+				Expect(scopeType).To(Equal("space"))
+				Expect(guids).To(Equal([]string{"space-1"}))
+				Expect(excludedSourceGuids).To(BeEmpty())
+			})
+
+			Context("when the scopes of some sources are not stored yet", func() {
+				BeforeEach(func() {
+					fakeStore.UnscopedSourcesReturns([]store.Source{
+						{ID: "app-3"},
+						{ID: "space-2", Type: "space"},
+						{ID: "org-2", Type: "org"},
+					}, nil)
+				})
+
+				It("looks them up and stores them", func() {
+					_, err := quotaGuard.CheckAccess(policies, tokenData)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeCCClient.GetAppSpacesCallCount()).To(Equal(2))
+					_, appGUIDs := fakeCCClient.GetAppSpacesArgsForCall(1)
+					Expect(appGUIDs).To(Equal([]string{"app-3"}))
+
+					Expect(fakeStore.SetSourceScopesCallCount()).To(Equal(1))
+					Expect(fakeStore.SetSourceScopesArgsForCall(0)).To(Equal([]store.SourceScope{
+						{GUID: "app-3", SpaceGUID: "space-1", OrgGUID: "org-1"},
+						{GUID: "space-2", SpaceGUID: "space-2", OrgGUID: "org-1"},
+						{GUID: "org-2", OrgGUID: "org-2"},
+					}))
+				})
+
+				Context("when getting them fails", func() {
+					BeforeEach(func() {
+						fakeStore.UnscopedSourcesReturns(nil, errors.New("banana"))
+					})
+
+					It("returns an error", func() {
+						_, err := quotaGuard.CheckAccess(policies, tokenData)
+						Expect(err).To(MatchError("getting unscoped sources: banana"))
+					})
+				})
+
+				Context("when storing them fails", func() {
+					BeforeEach(func() {
+						fakeStore.SetSourceScopesReturns(errors.New("banana"))
+					})
+
+					It("returns an error", func() {
+						_, err := quotaGuard.CheckAccess(policies, tokenData)
+						Expect(err).To(MatchError("storing source scopes: banana"))
+					})
+				})
+			})
+
+			Context("when the scopes of every source are stored", func() {
+				It("does not store them again", func() {
+					_, err := quotaGuard.CheckAccess(policies, tokenData)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeStore.SetSourceScopesCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when counting the policies fails", func() {
+				BeforeEach(func() {
+					fakeStore.PolicyCountsByScopeStub = nil
+					fakeStore.PolicyCountsByScopeReturns(nil, errors.New("banana"))
+				})
+
+				It("returns an error", func() {
+					_, err := quotaGuard.CheckAccess(policies, tokenData)
+					Expect(err).To(MatchError("counting policies: banana"))
+				})
+			})
+		})
+
+		Context("when the space quota is exceeded", func() {
+			BeforeEach(func() {
+				fakeQuotasStore.QuotasReturns([]store.Quota{{Type: "space", GUID: "space-1", MaxPolicies: 2}}, nil)
+			})
+
+			It("does not allow policy creation", func() {
+				authorized, err := quotaGuard.CheckAccess(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(authorized).To(BeFalse())
+			})
+
+			It("applies to admins as well", func() {
+				tokenData.Scope = []string{"network.admin"}
+				authorized, err := quotaGuard.CheckAccess(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(authorized).To(BeFalse())
+			})
+
+			Context("when the policies already exist", func() {
+				BeforeEach(func() {
+					existingPolicies = append(existingPolicies, policies...)
+					fakeStore.ByGuidsReturns(policies, nil)
+				})
+
+				It("does not count them twice", func() {
+					authorized, err := quotaGuard.CheckAccess(policies, tokenData)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(authorized).To(BeTrue())
+				})
+			})
+
+			Context("when the policies of the existing source are replaced", func() {
+				It("does not count the replaced policies", func() {
+					authorized, err := quotaGuard.CheckReplaceAccess("app-3", policies, tokenData)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(authorized).To(BeTrue())
+				})
+			})
+
+			Context("when the policies of the existing source are reconciled", func() {
+				It("does not count the replaced policies", func() {
+					authorized, err := quotaGuard.CheckReconcileAccess([]string{"space-1", "app-3"}, policies)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(authorized).To(BeTrue())
+				})
+
+				It("counts the policies of other sources", func() {
+					authorized, err := quotaGuard.CheckReconcileAccess([]string{"space-1", "app-1"}, policies)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(authorized).To(BeFalse())
+				})
+			})
+		})
+
+		Context("when the quota is for a different space", func() {
+			BeforeEach(func() {
+				fakeQuotasStore.QuotasReturns([]store.Quota{{Type: "space", GUID: "space-2", MaxPolicies: 0}}, nil)
+			})
+
+			It("allows policy creation", func() {
+				authorized, err := quotaGuard.CheckAccess(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(authorized).To(BeTrue())
+			})
+		})
+
+		Context("when the org quota is exceeded", func() {
+			BeforeEach(func() {
+				fakeQuotasStore.QuotasReturns([]store.Quota{{Type: "org", GUID: "org-1", MaxPolicies: 3}}, nil)
+				policies = append(policies, store.Policy{
+					Source:      store.Source{ID: "space-2", Type: "space"},
+					Destination: store.Destination{ID: "some-other-guid"},
+				})
+			})
+
+			It("does not allow policy creation", func() {
+				authorized, err := quotaGuard.CheckAccess(policies, tokenData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(authorized).To(BeFalse())
+
+				Expect(fakeCCClient.GetSpaceCallCount()).To(Equal(2))
+				_, spaceGUID := fakeCCClient.GetSpaceArgsForCall(0)
+				Expect(spaceGUID).To(Equal("space-1"))
+				_, spaceGUID = fakeCCClient.GetSpaceArgsForCall(1)
+				Expect(spaceGUID).To(Equal("space-2"))
+			})
+		})
+
+		Context("when getting the quotas fails", func() {
+			BeforeEach(func() {
+				fakeQuotasStore.QuotasReturns(nil, errors.New("banana"))
+			})
+
+			It("returns an error", func() {
+				_, err := quotaGuard.CheckAccess(policies, tokenData)
+				Expect(err).To(MatchError("getting quotas: banana"))
+			})
+		})
+
+		Context("when a quota exists", func() {
+			BeforeEach(func() {
+				fakeQuotasStore.QuotasReturns([]store.Quota{{Type: "org", GUID: "org-1", MaxPolicies: 3}}, nil)
+			})
+
+			Context("when getting the token fails", func() {
+				BeforeEach(func() {
+					fakeUAAClient.GetTokenReturns("", errors.New("banana"))
+				})
+
+				It("returns an error", func() {
+					_, err := quotaGuard.CheckAccess(policies, tokenData)
+					Expect(err).To(MatchError("getting token: banana"))
+				})
+			})
+
+			Context("when getting the existing policies of the sources fails", func() {
+				BeforeEach(func() {
+					fakeStore.ByGuidsReturns(nil, errors.New("banana"))
+				})
+
+				It("returns an error", func() {
+					_, err := quotaGuard.CheckAccess(policies, tokenData)
+					Expect(err).To(MatchError("getting policies: banana"))
+				})
+			})
+
+			Context("when getting the app spaces fails", func() {
+				BeforeEach(func() {
+					fakeCCClient.GetAppSpacesStub = nil
+					fakeCCClient.GetAppSpacesReturns(nil, errors.New("banana"))
+				})
+
+				It("returns an error", func() {
+					_, err := quotaGuard.CheckAccess(policies, tokenData)
+					Expect(err).To(MatchError("getting app spaces: banana"))
+				})
+			})
+
+			Context("when getting the space fails", func() {
+				BeforeEach(func() {
+					fakeCCClient.GetSpaceReturns(nil, errors.New("banana"))
+				})
+
+				It("returns an error", func() {
+					_, err := quotaGuard.CheckAccess(policies, tokenData)
+					Expect(err).To(MatchError("getting space with guid space-1: banana"))
+				})
+			})
+		})
+	})
 })
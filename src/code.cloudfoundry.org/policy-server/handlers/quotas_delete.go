@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type QuotasDelete struct {
+	Store         store.PolicyQuotasStore
+	RataAdapter   rataAdapter
+	ErrorResponse errorResponse
+}
+
+func NewQuotasDelete(store store.PolicyQuotasStore, rataAdapter rataAdapter, errorResponse errorResponse) *QuotasDelete {
+	return &QuotasDelete{
+		Store:         store,
+		RataAdapter:   rataAdapter,
+		ErrorResponse: errorResponse,
+	}
+}
+
+func (h *QuotasDelete) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("delete-quota")
+	tokenData := getTokenData(req)
+
+	quotaType := h.RataAdapter.Param(req, "type")
+	guid := h.RataAdapter.Param(req, "guid")
+	err := validateQuotaType(quotaType)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+
+	err = h.Store.DeleteQuota(quotaType, guid)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database write failed")
+		return
+	}
+	logger.Info("deleted-quota", lager.Data{"type": quotaType, "guid": guid, "userName": tokenData.UserName})
+
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write([]byte(`{}`))
+}
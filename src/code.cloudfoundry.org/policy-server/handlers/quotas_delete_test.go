@@ -0,0 +1,108 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	storeFakes "code.cloudfoundry.org/policy-server/store/fakes"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Quotas delete handler", func() {
+	var (
+		quotaType         string
+		request           *http.Request
+		handler           *handlers.QuotasDelete
+		resp              *httptest.ResponseRecorder
+		fakeStore         *storeFakes.PolicyQuotasStore
+		fakeRataAdapter   *fakes.RataAdapter
+		fakeErrorResponse *fakes.ErrorResponse
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		tokenData         uaa_client.CheckTokenResponse
+	)
+
+	BeforeEach(func() {
+		quotaType = "org"
+
+		var err error
+		request, err = http.NewRequest("DELETE", "/networking/v1/external/quotas/org/some-org-guid", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeStore = &storeFakes.PolicyQuotasStore{}
+		fakeRataAdapter = &fakes.RataAdapter{}
+		fakeRataAdapter.ParamStub = func(req *http.Request, name string) string {
+			if name == "type" {
+				return quotaType
+			}
+			return "some-org-guid"
+		}
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("delete-quota")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		handler = handlers.NewQuotasDelete(fakeStore, fakeRataAdapter, fakeErrorResponse)
+		tokenData = uaa_client.CheckTokenResponse{
+			Scope:    []string{"network.admin"},
+			UserName: "some-admin",
+		}
+		resp = httptest.NewRecorder()
+	})
+
+	It("deletes the quota", func() {
+		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+		Expect(fakeStore.DeleteQuotaCallCount()).To(Equal(1))
+		deletedType, deletedGuid := fakeStore.DeleteQuotaArgsForCall(0)
+		Expect(deletedType).To(Equal("org"))
+		Expect(deletedGuid).To(Equal("some-org-guid"))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{}`))
+	})
+
+	Context("when the type is invalid", func() {
+		BeforeEach(func() {
+			quotaType = "app"
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.DeleteQuotaCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+
+			l, w, _, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(description).To(Equal("invalid quota type 'app': must be space or org"))
+		})
+	})
+
+	Context("when the store throws an error", func() {
+		BeforeEach(func() {
+			fakeStore.DeleteQuotaReturns(errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database write failed"))
+		})
+	})
+})
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/cf-networking-helpers/marshal"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type QuotasIndex struct {
+	Store         store.PolicyQuotasStore
+	Marshaler     marshal.Marshaler
+	ErrorResponse errorResponse
+}
+
+func NewQuotasIndex(store store.PolicyQuotasStore, marshaler marshal.Marshaler, errorResponse errorResponse) *QuotasIndex {
+	return &QuotasIndex{
+		Store:         store,
+		Marshaler:     marshaler,
+		ErrorResponse: errorResponse,
+	}
+}
+
+func (h *QuotasIndex) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("index-quotas")
+	quotas, err := h.Store.Quotas()
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+		return
+	}
+
+	responseBytes, err := h.Marshaler.Marshal(api.QuotasPayload{Quotas: api.MapStoreQuotas(quotas)})
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database marshalling failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write(responseBytes)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"code.cloudfoundry.org/cf-networking-helpers/marshal"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type QuotasUpdate struct {
+	Store         store.PolicyQuotasStore
+	Marshaler     marshal.Marshaler
+	RataAdapter   rataAdapter
+	ErrorResponse errorResponse
+}
+
+func NewQuotasUpdate(store store.PolicyQuotasStore, marshaler marshal.Marshaler, rataAdapter rataAdapter,
+	errorResponse errorResponse) *QuotasUpdate {
+	return &QuotasUpdate{
+		Store:         store,
+		Marshaler:     marshaler,
+		RataAdapter:   rataAdapter,
+		ErrorResponse: errorResponse,
+	}
+}
+
+func (h *QuotasUpdate) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("update-quota")
+	tokenData := getTokenData(req)
+
+	quotaType := h.RataAdapter.Param(req, "type")
+	guid := h.RataAdapter.Param(req, "guid")
+	err := validateQuotaType(quotaType)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+
+	bodyBytes, err := io.ReadAll(req.Body)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, "failed reading request body")
+		return
+	}
+
+	var payload struct {
+		MaxPolicies *int `json:"max_policies"`
+	}
+	err = json.Unmarshal(bodyBytes, &payload)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, fmt.Sprintf("unmarshal json: %s", err))
+		return
+	}
+	if payload.MaxPolicies == nil {
+		err = fmt.Errorf("missing max_policies")
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+	if *payload.MaxPolicies < 0 {
+		err = fmt.Errorf("invalid max_policies %d: must be at least 0", *payload.MaxPolicies)
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+
+	quota := store.Quota{
+		Type:        quotaType,
+		GUID:        guid,
+		MaxPolicies: *payload.MaxPolicies,
+	}
+	err = h.Store.SetQuota(quota)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database write failed")
+		return
+	}
+	logger.Info("updated-quota", lager.Data{"quota": quota, "userName": tokenData.UserName})
+
+	responseBytes, err := h.Marshaler.Marshal(api.MapStoreQuota(quota))
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database marshalling failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write(responseBytes)
+}
+
+func validateQuotaType(quotaType string) error {
+	if quotaType != store.GroupTypeSpace && quotaType != store.GroupTypeOrg {
+		return fmt.Errorf("invalid quota type '%s': must be space or org", quotaType)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	hfakes "code.cloudfoundry.org/cf-networking-helpers/fakes"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	storeFakes "code.cloudfoundry.org/policy-server/store/fakes"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Quotas update handler", func() {
+	var (
+		requestBody       string
+		quotaType         string
+		request           *http.Request
+		handler           *handlers.QuotasUpdate
+		resp              *httptest.ResponseRecorder
+		fakeStore         *storeFakes.PolicyQuotasStore
+		fakeRataAdapter   *fakes.RataAdapter
+		fakeErrorResponse *fakes.ErrorResponse
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		marshaler         *hfakes.Marshaler
+		tokenData         uaa_client.CheckTokenResponse
+	)
+
+	BeforeEach(func() {
+		requestBody = `{"max_policies": 10}`
+		quotaType = "space"
+
+		marshaler = &hfakes.Marshaler{}
+		marshaler.MarshalStub = json.Marshal
+
+		fakeStore = &storeFakes.PolicyQuotasStore{}
+		fakeRataAdapter = &fakes.RataAdapter{}
+		fakeRataAdapter.ParamStub = func(req *http.Request, name string) string {
+			if name == "type" {
+				return quotaType
+			}
+			return "some-space-guid"
+		}
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("update-quota")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		handler = handlers.NewQuotasUpdate(fakeStore, marshaler, fakeRataAdapter, fakeErrorResponse)
+		tokenData = uaa_client.CheckTokenResponse{
+			Scope:    []string{"network.admin"},
+			UserName: "some-admin",
+		}
+		resp = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		request, err = http.NewRequest("PUT", "/networking/v1/external/quotas/space/some-space-guid", bytes.NewBuffer([]byte(requestBody)))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("sets the quota", func() {
+		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+		Expect(fakeStore.SetQuotaCallCount()).To(Equal(1))
+		Expect(fakeStore.SetQuotaArgsForCall(0)).To(Equal(store.Quota{
+			Type:        "space",
+			GUID:        "some-space-guid",
+			MaxPolicies: 10,
+		}))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{"type": "space", "guid": "some-space-guid", "max_policies": 10}`))
+	})
+
+	It("logs the quota and the user who set it", func() {
+		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+		Expect(logger.Logs()).To(HaveLen(1))
+		Expect(logger.Logs()[0]).To(SatisfyAll(
+			LogsWith(lager.INFO, "test.update-quota.updated-quota"),
+			HaveLogData(SatisfyAll(
+				HaveKeyWithValue("quota", SatisfyAll(
+					HaveKeyWithValue("Type", "space"),
+					HaveKeyWithValue("GUID", "some-space-guid"),
+					HaveKeyWithValue("MaxPolicies", BeEquivalentTo(10)),
+				)),
+				HaveKeyWithValue("userName", "some-admin"),
+			)),
+		))
+	})
+
+	Context("when max_policies is zero", func() {
+		BeforeEach(func() {
+			requestBody = `{"max_policies": 0}`
+		})
+
+		It("sets the quota", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.SetQuotaCallCount()).To(Equal(1))
+			Expect(fakeStore.SetQuotaArgsForCall(0).MaxPolicies).To(Equal(0))
+		})
+	})
+
+	DescribeTable("when the request is invalid",
+		func(typ, body, description string) {
+			quotaType = typ
+			request, _ = http.NewRequest("PUT", "/networking/v1/external/quotas", bytes.NewBuffer([]byte(body)))
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.SetQuotaCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+
+			l, w, _, desc := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(desc).To(Equal(description))
+		},
+		Entry("unknown type", "app", `{"max_policies": 10}`, "invalid quota type 'app': must be space or org"),
+		Entry("invalid json", "space", `{`, "unmarshal json: unexpected end of JSON input"),
+		Entry("missing max_policies", "space", `{}`, "missing max_policies"),
+		Entry("negative max_policies", "org", `{"max_policies": -1}`, "invalid max_policies -1: must be at least 0"),
+	)
+
+	Context("when the store throws an error", func() {
+		BeforeEach(func() {
+			fakeStore.SetQuotaReturns(errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			l, w, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database write failed"))
+		})
+	})
+
+	Context("when the quota cannot be marshaled", func() {
+		BeforeEach(func() {
+			marshaler.MarshalStub = func(interface{}) ([]byte, error) {
+				return nil, errors.New("grapes")
+			}
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("grapes"))
+			Expect(description).To(Equal("database marshalling failed"))
+		})
+	})
+})
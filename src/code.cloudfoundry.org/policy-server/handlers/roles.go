@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+
+	"code.cloudfoundry.org/policy-server/cc_client"
+)
+
+// Permission is something that a scope or a role allows its holders to do
+type Permission string
+
+const (
+	PermissionRead    Permission = "read"
+	PermissionWrite   Permission = "write"
+	PermissionCleanup Permission = "cleanup"
+	PermissionTags    Permission = "tags"
+)
+
+var permissions = []Permission{PermissionRead, PermissionWrite, PermissionCleanup, PermissionTags}
+
+var roleTypes = []string{
+	cc_client.RoleSpaceDeveloper,
+	cc_client.RoleSpaceManager,
+	cc_client.RoleSpaceAuditor,
+	cc_client.RoleOrgManager,
+}
+
+// Roles maps UAA scopes and Cloud-Controller roles to permissions. A scope
+// lets its holders call the endpoints that need one of its permissions, and
+// the roles then decide which apps the holders may use it on: the apps of the
+// spaces that they have a role with the permission in, or of every space of an
+// org that they have such a role in. network.admin is not limited by roles.
+// The cleanup and tags permissions are not about apps, so only scopes grant them.
+type Roles struct {
+	Scopes     map[string][]Permission
+	SpaceRoles map[string][]Permission
+}
+
+// DefaultRoles lets network.read, space managers and auditors, and org
+// managers list the policies of their apps. Only space developers with
+// network.write may change them, as before roles could be configured.
+func DefaultRoles() Roles {
+	return Roles{
+		Scopes: map[string][]Permission{
+			"network.admin": {PermissionRead, PermissionWrite, PermissionCleanup, PermissionTags},
+			"network.write": {PermissionRead, PermissionWrite},
+			"network.read":  {PermissionRead},
+		},
+		SpaceRoles: map[string][]Permission{
+			cc_client.RoleSpaceDeveloper: {PermissionRead, PermissionWrite},
+			cc_client.RoleSpaceManager:   {PermissionRead},
+			cc_client.RoleSpaceAuditor:   {PermissionRead},
+			cc_client.RoleOrgManager:     {PermissionRead},
+		},
+	}
+}
+
+// NewRoles overrides the default permissions of the given scopes and roles.
+// An empty list of permissions takes every permission away.
+func NewRoles(scopePermissions, rolePermissions map[string][]string) (Roles, error) {
+	roles := DefaultRoles()
+	for scope, names := range scopePermissions {
+		granted, err := parsePermissions(names)
+		if err != nil {
+			return Roles{}, fmt.Errorf("scope %s: %s", scope, err)
+		}
+		roles.Scopes[scope] = granted
+	}
+	for roleType, names := range rolePermissions {
+		if !containsString(roleTypes, roleType) {
+			return Roles{}, fmt.Errorf("unknown role %s", roleType)
+		}
+		granted, err := parsePermissions(names)
+		if err != nil {
+			return Roles{}, fmt.Errorf("role %s: %s", roleType, err)
+		}
+		for _, permission := range granted {
+			if permission == PermissionCleanup || permission == PermissionTags {
+				return Roles{}, fmt.Errorf("role %s: %s can only be granted to scopes", roleType, permission)
+			}
+		}
+		roles.SpaceRoles[roleType] = granted
+	}
+	return roles, nil
+}
+
+// ScopesWith returns the scopes that grant the permission, in order
+func (r Roles) ScopesWith(permission Permission) []string {
+	scopes := []string{}
+	for scope, granted := range r.Scopes {
+		if hasPermission(granted, permission) {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// Grants returns whether one of the subject roles grants the permission in
+// the space, which belongs to the org
+func (r Roles) Grants(subjectRoles []cc_client.Role, spaceGUID, orgGUID string, permission Permission) bool {
+	for _, role := range subjectRoles {
+		if role.SpaceGUID != spaceGUID && (role.SpaceGUID != "" || role.OrgGUID != orgGUID) {
+			continue
+		}
+		if hasPermission(r.SpaceRoles[role.Type], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// granted returns the spaces and the orgs that one of the subject roles grants
+// the permission in
+func (r Roles) granted(subjectRoles []cc_client.Role, permission Permission) (map[string]struct{}, []string) {
+	spaceGUIDs := map[string]struct{}{}
+	orgGUIDs := []string{}
+	for _, role := range subjectRoles {
+		if !hasPermission(r.SpaceRoles[role.Type], permission) {
+			continue
+		}
+		if role.SpaceGUID != "" {
+			spaceGUIDs[role.SpaceGUID] = struct{}{}
+		} else if role.OrgGUID != "" && !containsString(orgGUIDs, role.OrgGUID) {
+			orgGUIDs = append(orgGUIDs, role.OrgGUID)
+		}
+	}
+	return spaceGUIDs, orgGUIDs
+}
+
+func parsePermissions(names []string) ([]Permission, error) {
+	parsed := []Permission{}
+	for _, name := range names {
+		if !hasPermission(permissions, Permission(name)) {
+			return nil, fmt.Errorf("unknown permission %s", name)
+		}
+		parsed = append(parsed, Permission(name))
+	}
+	return parsed, nil
+}
+
+func hasPermission(granted []Permission, permission Permission) bool {
+	for _, p := range granted {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
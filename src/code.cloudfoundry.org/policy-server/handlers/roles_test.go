@@ -0,0 +1,106 @@
+package handlers_test
+
+import (
+	"code.cloudfoundry.org/policy-server/cc_client"
+	"code.cloudfoundry.org/policy-server/handlers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Roles", func() {
+	Describe("NewRoles", func() {
+		It("overrides the default permissions of the given scopes and roles", func() {
+			roles, err := handlers.NewRoles(
+				map[string][]string{"network.write": {"read"}, "network.cleaner": {"cleanup"}},
+				map[string][]string{"space_manager": {}},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			defaults := handlers.DefaultRoles()
+			Expect(roles.Scopes).To(Equal(map[string][]handlers.Permission{
+				"network.admin":   defaults.Scopes["network.admin"],
+				"network.write":   {handlers.PermissionRead},
+				"network.read":    {handlers.PermissionRead},
+				"network.cleaner": {handlers.PermissionCleanup},
+			}))
+			Expect(roles.SpaceRoles[cc_client.RoleSpaceManager]).To(BeEmpty())
+			Expect(roles.SpaceRoles[cc_client.RoleSpaceDeveloper]).To(Equal(defaults.SpaceRoles[cc_client.RoleSpaceDeveloper]))
+		})
+
+		It("only lets space developers change policies by default", func() {
+			roles := handlers.DefaultRoles()
+			Expect(roles.Scopes["network.read"]).To(Equal([]handlers.Permission{handlers.PermissionRead}))
+			Expect(roles.SpaceRoles).To(Equal(map[string][]handlers.Permission{
+				cc_client.RoleSpaceDeveloper: {handlers.PermissionRead, handlers.PermissionWrite},
+				cc_client.RoleSpaceManager:   {handlers.PermissionRead},
+				cc_client.RoleSpaceAuditor:   {handlers.PermissionRead},
+				cc_client.RoleOrgManager:     {handlers.PermissionRead},
+			}))
+		})
+
+		It("does not change the defaults", func() {
+			_, err := handlers.NewRoles(map[string][]string{"network.write": {}}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handlers.DefaultRoles().Scopes["network.write"]).To(ConsistOf(handlers.PermissionRead, handlers.PermissionWrite))
+		})
+
+		Context("when a permission is unknown", func() {
+			It("returns an error", func() {
+				_, err := handlers.NewRoles(map[string][]string{"network.write": {"banana"}}, nil)
+				Expect(err).To(MatchError("scope network.write: unknown permission banana"))
+
+				_, err = handlers.NewRoles(nil, map[string][]string{"space_auditor": {"banana"}})
+				Expect(err).To(MatchError("role space_auditor: unknown permission banana"))
+			})
+		})
+
+		Context("when a role is unknown", func() {
+			It("returns an error", func() {
+				_, err := handlers.NewRoles(nil, map[string][]string{"org_auditor": {"read"}})
+				Expect(err).To(MatchError("unknown role org_auditor"))
+			})
+		})
+
+		Context("when a role is granted a permission that only scopes can have", func() {
+			It("returns an error", func() {
+				_, err := handlers.NewRoles(nil, map[string][]string{"space_manager": {"read", "tags"}})
+				Expect(err).To(MatchError("role space_manager: tags can only be granted to scopes"))
+			})
+		})
+	})
+
+	Describe("ScopesWith", func() {
+		It("returns the scopes that grant the permission in order", func() {
+			roles := handlers.DefaultRoles()
+			Expect(roles.ScopesWith(handlers.PermissionRead)).To(Equal([]string{"network.admin", "network.read", "network.write"}))
+			Expect(roles.ScopesWith(handlers.PermissionWrite)).To(Equal([]string{"network.admin", "network.write"}))
+			Expect(roles.ScopesWith(handlers.PermissionTags)).To(Equal([]string{"network.admin"}))
+		})
+	})
+
+	Describe("Grants", func() {
+		var subjectRoles []cc_client.Role
+
+		BeforeEach(func() {
+			subjectRoles = []cc_client.Role{
+				{Type: cc_client.RoleSpaceAuditor, SpaceGUID: "space-1"},
+				{Type: cc_client.RoleSpaceDeveloper, SpaceGUID: "space-2"},
+				{Type: cc_client.RoleOrgManager, OrgGUID: "org-1"},
+			}
+		})
+
+		It("grants the permissions of the roles in their spaces", func() {
+			roles := handlers.DefaultRoles()
+			Expect(roles.Grants(subjectRoles, "space-1", "org-2", handlers.PermissionRead)).To(BeTrue())
+			Expect(roles.Grants(subjectRoles, "space-1", "org-2", handlers.PermissionWrite)).To(BeFalse())
+			Expect(roles.Grants(subjectRoles, "space-2", "org-2", handlers.PermissionWrite)).To(BeTrue())
+			Expect(roles.Grants(subjectRoles, "space-3", "org-2", handlers.PermissionRead)).To(BeFalse())
+		})
+
+		It("grants the permissions of org roles in every space of their org", func() {
+			roles := handlers.DefaultRoles()
+			Expect(roles.Grants(subjectRoles, "space-3", "org-1", handlers.PermissionRead)).To(BeTrue())
+			Expect(roles.Grants(subjectRoles, "space-3", "org-1", handlers.PermissionWrite)).To(BeFalse())
+		})
+	})
+})
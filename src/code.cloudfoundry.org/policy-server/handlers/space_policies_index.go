@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/cf-networking-helpers/marshal"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+//counterfeiter:generate -o fakes/space_policies_store.go --fake-name SpacePoliciesStore . spacePoliciesStore
+type spacePoliciesStore interface {
+	ByGuids(srcGuids []string, dstGuids []string, srcAndDst bool) ([]store.Policy, error)
+	LastUpdated() (int, error)
+	ReconcileSources(sourceGuids []string, policies []store.Policy, lastUpdated int, actor store.Actor) error
+}
+
+type SpacePoliciesIndex struct {
+	Store         spacePoliciesStore
+	SpaceSources  spaceSources
+	RataAdapter   rataAdapter
+	Marshaler     marshal.Marshaler
+	ErrorResponse errorResponse
+}
+
+func NewSpacePoliciesIndex(store spacePoliciesStore, spaceSources spaceSources, rataAdapter rataAdapter,
+	marshaler marshal.Marshaler, errorResponse errorResponse) *SpacePoliciesIndex {
+	return &SpacePoliciesIndex{
+		Store:         store,
+		SpaceSources:  spaceSources,
+		RataAdapter:   rataAdapter,
+		Marshaler:     marshaler,
+		ErrorResponse: errorResponse,
+	}
+}
+
+func (h *SpacePoliciesIndex) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("index-space-policies")
+
+	spaceGuid := h.RataAdapter.Param(req, "guid")
+	sourceGuids, found, err := h.SpaceSources.SourceGuids(spaceGuid)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "getting space apps failed")
+		return
+	}
+	if !found {
+		err := errors.New("space not found")
+		h.ErrorResponse.NotFound(logger, w, err, err.Error())
+		return
+	}
+
+	payload, err := spacePolicies(h.Store, sourceGuids)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+		return
+	}
+
+	responseBytes, err := h.Marshaler.Marshal(payload)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database marshalling failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write(responseBytes)
+}
+
+// spacePolicies returns the policies of the sources of a space. The last
+// updated version is read first, so that a change made while the policies are
+// read makes a reconcile based on them fail instead of undoing the change.
+func spacePolicies(policyStore spacePoliciesStore, sourceGuids []string) (api.SpacePoliciesPayload, error) {
+	lastUpdated, err := policyStore.LastUpdated()
+	if err != nil {
+		return api.SpacePoliciesPayload{}, err
+	}
+
+	policies, err := policyStore.ByGuids(sourceGuids, []string{}, false)
+	if err != nil {
+		return api.SpacePoliciesPayload{}, err
+	}
+
+	apiPolicies := api.MapStorePolicies(policies)
+	return api.SpacePoliciesPayload{
+		LastUpdated:   strconv.Itoa(lastUpdated),
+		TotalPolicies: len(apiPolicies),
+		Policies:      apiPolicies,
+	}, nil
+}
@@ -0,0 +1,153 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	hfakes "code.cloudfoundry.org/cf-networking-helpers/fakes"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Space policies index handler", func() {
+	var (
+		request           *http.Request
+		handler           *handlers.SpacePoliciesIndex
+		resp              *httptest.ResponseRecorder
+		fakeStore         *fakes.SpacePoliciesStore
+		fakeSpaceSources  *fakes.SpaceSources
+		fakeRataAdapter   *fakes.RataAdapter
+		fakeErrorResponse *fakes.ErrorResponse
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		marshaler         *hfakes.Marshaler
+		tokenData         uaa_client.CheckTokenResponse
+	)
+
+	BeforeEach(func() {
+		var err error
+		request, err = http.NewRequest("GET", "/networking/v1/external/spaces/some-space-guid/policies", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		marshaler = &hfakes.Marshaler{}
+		marshaler.MarshalStub = json.Marshal
+
+		fakeStore = &fakes.SpacePoliciesStore{}
+		fakeStore.LastUpdatedReturns(1700000000123456000, nil)
+		fakeStore.ByGuidsReturns([]store.Policy{{
+			Source:      store.Source{ID: "some-space-guid", Type: "space"},
+			Destination: store.Destination{ID: "some-other-app-guid", Protocol: "tcp", Ports: store.Ports{Start: 8080, End: 8080}},
+		}}, nil)
+		fakeSpaceSources = &fakes.SpaceSources{}
+		fakeSpaceSources.SourceGuidsReturns([]string{"some-space-guid", "some-app-guid"}, true, nil)
+		fakeRataAdapter = &fakes.RataAdapter{}
+		fakeRataAdapter.ParamReturns("some-space-guid")
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("index-space-policies")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		handler = handlers.NewSpacePoliciesIndex(fakeStore, fakeSpaceSources, fakeRataAdapter, marshaler, fakeErrorResponse)
+		tokenData = uaa_client.CheckTokenResponse{
+			Scope:    []string{"network.admin"},
+			UserName: "some-admin",
+		}
+		resp = httptest.NewRecorder()
+	})
+
+	It("returns the policies of the space with the last updated version", func() {
+		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+		_, name := fakeRataAdapter.ParamArgsForCall(0)
+		Expect(name).To(Equal("guid"))
+		Expect(fakeSpaceSources.SourceGuidsArgsForCall(0)).To(Equal("some-space-guid"))
+
+		srcGuids, dstGuids, srcAndDst := fakeStore.ByGuidsArgsForCall(0)
+		Expect(srcGuids).To(Equal([]string{"some-space-guid", "some-app-guid"}))
+		Expect(dstGuids).To(BeEmpty())
+		Expect(srcAndDst).To(BeFalse())
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{
+			"last_updated": "1700000000123456000",
+			"total_policies": 1,
+			"policies": [
+				{ "source": { "id": "some-space-guid", "type": "space" }, "destination": { "id": "some-other-app-guid", "protocol": "tcp", "ports": { "start": 8080, "end": 8080 } } }
+			]
+		}`))
+	})
+
+	Context("when the space does not exist", func() {
+		BeforeEach(func() {
+			fakeSpaceSources.SourceGuidsReturns(nil, false, nil)
+		})
+
+		It("calls the not found handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.ByGuidsCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.NotFoundCallCount()).To(Equal(1))
+			l, w, err, description := fakeErrorResponse.NotFoundArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(MatchError("space not found"))
+			Expect(description).To(Equal("space not found"))
+		})
+	})
+
+	Context("when getting the apps of the space fails", func() {
+		BeforeEach(func() {
+			fakeSpaceSources.SourceGuidsReturns(nil, false, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("getting space apps failed"))
+		})
+	})
+
+	Context("when getting last updated fails", func() {
+		BeforeEach(func() {
+			fakeStore.LastUpdatedReturns(0, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.ByGuidsCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database read failed"))
+		})
+	})
+
+	Context("when getting the policies fails", func() {
+		BeforeEach(func() {
+			fakeStore.ByGuidsReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database read failed"))
+		})
+	})
+})
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/cf-networking-helpers/marshal"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type SpacePoliciesReconcile struct {
+	Store         spacePoliciesStore
+	Mapper        api.PolicyMapper
+	SpaceSources  spaceSources
+	QuotaGuard    quotaGuard
+	RataAdapter   rataAdapter
+	Marshaler     marshal.Marshaler
+	ErrorResponse errorResponse
+}
+
+func NewSpacePoliciesReconcile(store spacePoliciesStore, mapper api.PolicyMapper, spaceSources spaceSources,
+	quotaGuard quotaGuard, rataAdapter rataAdapter, marshaler marshal.Marshaler, errorResponse errorResponse) *SpacePoliciesReconcile {
+	return &SpacePoliciesReconcile{
+		Store:         store,
+		Mapper:        mapper,
+		SpaceSources:  spaceSources,
+		QuotaGuard:    quotaGuard,
+		RataAdapter:   rataAdapter,
+		Marshaler:     marshaler,
+		ErrorResponse: errorResponse,
+	}
+}
+
+func (h *SpacePoliciesReconcile) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := getLogger(req)
+	logger = logger.Session("reconcile-space-policies")
+	tokenData := getTokenData(req)
+	spaceGuid := h.RataAdapter.Param(req, "guid")
+
+	bodyBytes, err := io.ReadAll(req.Body)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, "failed reading request body")
+		return
+	}
+
+	var payload struct {
+		LastUpdated string            `json:"last_updated"`
+		Policies    []json.RawMessage `json:"policies"`
+	}
+	err = json.Unmarshal(bodyBytes, &payload)
+	if err != nil {
+		h.ErrorResponse.BadRequest(logger, w, err, fmt.Sprintf("unmarshal json: %s", err))
+		return
+	}
+	if payload.LastUpdated == "" {
+		err := errors.New("missing last_updated")
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+	lastUpdated, err := strconv.Atoi(payload.LastUpdated)
+	if err != nil {
+		err := errors.New("invalid last_updated")
+		h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+		return
+	}
+	if payload.Policies == nil {
+		err := errors.New("missing policies")
+		h.ErrorResponse.BadRequest(logger, w, err, fmt.Sprintf("mapper: %s", err))
+		return
+	}
+
+	// an empty policy list removes every policy of the space, which the
+	// mapper would otherwise reject as missing policies
+	policies := []store.Policy{}
+	if len(payload.Policies) > 0 {
+		policies, err = h.Mapper.AsStorePolicy(bodyBytes)
+		if err != nil {
+			h.ErrorResponse.BadRequest(logger, w, err, fmt.Sprintf("mapper: %s", err))
+			return
+		}
+	}
+
+	sourceGuids, found, err := h.SpaceSources.SourceGuids(spaceGuid)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "getting space apps failed")
+		return
+	}
+	if !found {
+		err := errors.New("space not found")
+		h.ErrorResponse.NotFound(logger, w, err, err.Error())
+		return
+	}
+
+	for _, policy := range policies {
+		if !isSpaceSource(policy.Source, spaceGuid, sourceGuids) {
+			err := fmt.Errorf("policy source %s is not the space %s or one of its apps", policy.Source.ID, spaceGuid)
+			h.ErrorResponse.BadRequest(logger, w, err, err.Error())
+			return
+		}
+	}
+
+	authorized, err := h.QuotaGuard.CheckReconcileAccess(sourceGuids, policies)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "check quota failed")
+		return
+	}
+	if !authorized {
+		err := errors.New("policy quota exceeded")
+		h.ErrorResponse.Forbidden(logger, w, err, err.Error())
+		return
+	}
+
+	err = h.Store.ReconcileSources(sourceGuids, policies, lastUpdated, getActor(tokenData))
+	if err == store.ErrPoliciesChanged {
+		h.ErrorResponse.Conflict(logger, w, err, "policies have changed since last_updated, get the policies of the space and try again")
+		return
+	}
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database write failed")
+		return
+	}
+
+	logger.Info("reconciled-space-policies", lager.Data{"space_guid": spaceGuid, "policies": len(policies), "userName": tokenData.UserName})
+
+	response, err := spacePolicies(h.Store, sourceGuids)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database read failed")
+		return
+	}
+
+	responseBytes, err := h.Marshaler.Marshal(response)
+	if err != nil {
+		h.ErrorResponse.InternalServerError(logger, w, err, "database marshalling failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+	w.Write(responseBytes)
+}
+
+// isSpaceSource checks that the source is the space itself, or one of its apps
+func isSpaceSource(source store.Source, spaceGuid string, sourceGuids []string) bool {
+	if source.ID == spaceGuid {
+		return source.GroupType() == store.GroupTypeSpace
+	}
+	return source.GroupType() == store.GroupTypeApp && containsString(sourceGuids, source.ID)
+}
@@ -0,0 +1,363 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	hfakes "code.cloudfoundry.org/cf-networking-helpers/fakes"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	apifakes "code.cloudfoundry.org/policy-server/api/fakes"
+	"code.cloudfoundry.org/policy-server/handlers"
+	"code.cloudfoundry.org/policy-server/handlers/fakes"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Space policies reconcile handler", func() {
+	var (
+		requestBody       string
+		request           *http.Request
+		handler           *handlers.SpacePoliciesReconcile
+		resp              *httptest.ResponseRecorder
+		fakeStore         *fakes.SpacePoliciesStore
+		fakeMapper        *apifakes.PolicyMapper
+		fakeSpaceSources  *fakes.SpaceSources
+		fakeQuotaGuard    *fakes.QuotaGuard
+		fakeRataAdapter   *fakes.RataAdapter
+		fakeErrorResponse *fakes.ErrorResponse
+		logger            *lagertest.TestLogger
+		expectedLogger    lager.Logger
+		marshaler         *hfakes.Marshaler
+		tokenData         uaa_client.CheckTokenResponse
+		policies          []store.Policy
+	)
+
+	BeforeEach(func() {
+		requestBody = `{
+			"last_updated": "1700000000123456000",
+			"policies": [
+				{ "source": { "id": "some-space-guid", "type": "space" }, "destination": { "id": "some-other-app-guid", "protocol": "tcp", "ports": { "start": 8080, "end": 8080 } } },
+				{ "source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "protocol": "udp", "ports": { "start": 53, "end": 53 } } }
+			]
+		}`
+
+		marshaler = &hfakes.Marshaler{}
+		marshaler.MarshalStub = json.Marshal
+
+		policies = []store.Policy{
+			{
+				Source:      store.Source{ID: "some-space-guid", Type: "space"},
+				Destination: store.Destination{ID: "some-other-app-guid", Protocol: "tcp", Ports: store.Ports{Start: 8080, End: 8080}},
+			},
+			{
+				Source:      store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{ID: "some-other-app-guid", Protocol: "udp", Ports: store.Ports{Start: 53, End: 53}},
+			},
+		}
+		fakeMapper = &apifakes.PolicyMapper{}
+		fakeMapper.AsStorePolicyReturns(policies, nil)
+
+		fakeStore = &fakes.SpacePoliciesStore{}
+		fakeStore.LastUpdatedReturns(1700000000999999000, nil)
+		fakeStore.ByGuidsReturns(policies, nil)
+		fakeSpaceSources = &fakes.SpaceSources{}
+		fakeSpaceSources.SourceGuidsReturns([]string{"some-space-guid", "some-app-guid"}, true, nil)
+		fakeQuotaGuard = &fakes.QuotaGuard{}
+		fakeQuotaGuard.CheckReconcileAccessReturns(true, nil)
+		fakeRataAdapter = &fakes.RataAdapter{}
+		fakeRataAdapter.ParamReturns("some-space-guid")
+		fakeErrorResponse = &fakes.ErrorResponse{}
+		logger = lagertest.NewTestLogger("test")
+		expectedLogger = lager.NewLogger("test").Session("reconcile-space-policies")
+
+		testSink := lagertest.NewTestSink()
+		expectedLogger.RegisterSink(testSink)
+		expectedLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		handler = handlers.NewSpacePoliciesReconcile(fakeStore, fakeMapper, fakeSpaceSources, fakeQuotaGuard,
+			fakeRataAdapter, marshaler, fakeErrorResponse)
+		tokenData = uaa_client.CheckTokenResponse{
+			Scope:    []string{"network.admin"},
+			UserName: "some-admin",
+		}
+		resp = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		request, err = http.NewRequest("PUT", "/networking/v1/external/spaces/some-space-guid/policies", bytes.NewBuffer([]byte(requestBody)))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("reconciles the policies of the space and returns the new state", func() {
+		MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+		Expect(fakeMapper.AsStorePolicyArgsForCall(0)).To(MatchJSON(requestBody))
+
+		sourceGuids, quotaPolicies := fakeQuotaGuard.CheckReconcileAccessArgsForCall(0)
+		Expect(sourceGuids).To(Equal([]string{"some-space-guid", "some-app-guid"}))
+		Expect(quotaPolicies).To(Equal(policies))
+
+		Expect(fakeStore.ReconcileSourcesCallCount()).To(Equal(1))
+		sourceGuids, reconciled, lastUpdated, actor := fakeStore.ReconcileSourcesArgsForCall(0)
+		Expect(sourceGuids).To(Equal([]string{"some-space-guid", "some-app-guid"}))
+		Expect(reconciled).To(Equal(policies))
+		Expect(lastUpdated).To(Equal(1700000000123456000))
+		Expect(actor).To(Equal(store.Actor{Name: "some-admin"}))
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{
+			"last_updated": "1700000000999999000",
+			"total_policies": 2,
+			"policies": [
+				{ "source": { "id": "some-space-guid", "type": "space" }, "destination": { "id": "some-other-app-guid", "protocol": "tcp", "ports": { "start": 8080, "end": 8080 } } },
+				{ "source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "protocol": "udp", "ports": { "start": 53, "end": 53 } } }
+			]
+		}`))
+		Expect(logger.Logs()).To(ContainElement(SatisfyAll(
+			LogsWith(lager.INFO, "test.reconcile-space-policies.reconciled-space-policies"),
+			HaveLogData(SatisfyAll(
+				HaveKeyWithValue("space_guid", "some-space-guid"),
+				HaveKeyWithValue("policies", BeNumerically("==", 2)),
+				HaveKeyWithValue("userName", "some-admin"),
+			)),
+		)))
+	})
+
+	Context("when the policy list is empty", func() {
+		BeforeEach(func() {
+			requestBody = `{"last_updated": "1700000000123456000", "policies": []}`
+		})
+
+		It("removes every policy of the space", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeMapper.AsStorePolicyCallCount()).To(Equal(0))
+			_, reconciled, _, _ := fakeStore.ReconcileSourcesArgsForCall(0)
+			Expect(reconciled).To(BeEmpty())
+			Expect(resp.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("when the policies have changed since last updated", func() {
+		BeforeEach(func() {
+			fakeStore.ReconcileSourcesReturns(store.ErrPoliciesChanged)
+		})
+
+		It("calls the conflict handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.ConflictCallCount()).To(Equal(1))
+			l, w, err, description := fakeErrorResponse.ConflictArgsForCall(0)
+			Expect(l).To(Equal(expectedLogger))
+			Expect(w).To(Equal(resp))
+			Expect(err).To(Equal(store.ErrPoliciesChanged))
+			Expect(description).To(Equal("policies have changed since last_updated, get the policies of the space and try again"))
+		})
+	})
+
+	Context("when a policy source is not the space or one of its apps", func() {
+		BeforeEach(func() {
+			fakeMapper.AsStorePolicyReturns([]store.Policy{{
+				Source:      store.Source{ID: "some-app-in-another-space"},
+				Destination: store.Destination{ID: "some-other-app-guid"},
+			}}, nil)
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.ReconcileSourcesCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(err).To(MatchError("policy source some-app-in-another-space is not the space some-space-guid or one of its apps"))
+			Expect(description).To(Equal("policy source some-app-in-another-space is not the space some-space-guid or one of its apps"))
+		})
+	})
+
+	Context("when the space guid is given as an app source", func() {
+		BeforeEach(func() {
+			fakeMapper.AsStorePolicyReturns([]store.Policy{{
+				Source:      store.Source{ID: "some-space-guid"},
+				Destination: store.Destination{ID: "some-other-app-guid"},
+			}}, nil)
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.ReconcileSourcesCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when last_updated is missing", func() {
+		BeforeEach(func() {
+			requestBody = `{"policies": []}`
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(err).To(MatchError("missing last_updated"))
+			Expect(description).To(Equal("missing last_updated"))
+		})
+	})
+
+	Context("when last_updated is not a number", func() {
+		BeforeEach(func() {
+			requestBody = `{"last_updated": "yesterday", "policies": []}`
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(err).To(MatchError("invalid last_updated"))
+			Expect(description).To(Equal("invalid last_updated"))
+		})
+	})
+
+	Context("when the policies are missing", func() {
+		BeforeEach(func() {
+			requestBody = `{"last_updated": "1700000000123456000"}`
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(err).To(MatchError("missing policies"))
+			Expect(description).To(Equal("mapper: missing policies"))
+		})
+	})
+
+	Context("when the body is not valid json", func() {
+		BeforeEach(func() {
+			requestBody = "not-json"
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			_, _, _, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(description).To(HavePrefix("unmarshal json: "))
+		})
+	})
+
+	Context("when the mapper fails", func() {
+		BeforeEach(func() {
+			fakeMapper.AsStorePolicyReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the bad request handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.BadRequestCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.BadRequestArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("mapper: banana"))
+		})
+	})
+
+	Context("when the space does not exist", func() {
+		BeforeEach(func() {
+			fakeSpaceSources.SourceGuidsReturns(nil, false, nil)
+		})
+
+		It("calls the not found handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.NotFoundCallCount()).To(Equal(1))
+			_, _, err, _ := fakeErrorResponse.NotFoundArgsForCall(0)
+			Expect(err).To(MatchError("space not found"))
+		})
+	})
+
+	Context("when getting the apps of the space fails", func() {
+		BeforeEach(func() {
+			fakeSpaceSources.SourceGuidsReturns(nil, false, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("getting space apps failed"))
+		})
+	})
+
+	Context("when the quota is exceeded", func() {
+		BeforeEach(func() {
+			fakeQuotaGuard.CheckReconcileAccessReturns(false, nil)
+		})
+
+		It("calls the forbidden handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.ReconcileSourcesCallCount()).To(Equal(0))
+			Expect(fakeErrorResponse.ForbiddenCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.ForbiddenArgsForCall(0)
+			Expect(err).To(MatchError("policy quota exceeded"))
+			Expect(description).To(Equal("policy quota exceeded"))
+		})
+	})
+
+	Context("when checking the quota fails", func() {
+		BeforeEach(func() {
+			fakeQuotaGuard.CheckReconcileAccessReturns(false, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("check quota failed"))
+		})
+	})
+
+	Context("when reconciling fails", func() {
+		BeforeEach(func() {
+			fakeStore.ReconcileSourcesReturns(errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database write failed"))
+		})
+	})
+
+	Context("when reading the new state fails", func() {
+		BeforeEach(func() {
+			fakeStore.ByGuidsReturns(nil, errors.New("banana"))
+		})
+
+		It("calls the internal server error handler", func() {
+			MakeRequestWithLoggerAndAuth(handler.ServeHTTP, resp, request, logger, tokenData)
+
+			Expect(fakeStore.ReconcileSourcesCallCount()).To(Equal(1))
+			Expect(fakeErrorResponse.InternalServerErrorCallCount()).To(Equal(1))
+			_, _, err, description := fakeErrorResponse.InternalServerErrorArgsForCall(0)
+			Expect(err).To(MatchError("banana"))
+			Expect(description).To(Equal("database read failed"))
+		})
+	})
+})
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/policy-server/cc_client"
+	"code.cloudfoundry.org/policy-server/tracing"
+	"code.cloudfoundry.org/policy-server/uaa_client"
+)
+
+//counterfeiter:generate -o fakes/space_sources.go --fake-name SpaceSources . spaceSources
+type spaceSources interface {
+	SourceGuids(spaceGuid string) ([]string, bool, error)
+}
+
+// SpaceSources finds the sources whose policies make up the policies of a
+// space, which are the space itself and its apps
+type SpaceSources struct {
+	UAAClient uaa_client.UAAClient
+	CCClient  cc_client.CCClient
+}
+
+func NewSpaceSources(uaaClient uaa_client.UAAClient, ccClient cc_client.CCClient) *SpaceSources {
+	return &SpaceSources{
+		UAAClient: uaaClient,
+		CCClient:  ccClient,
+	}
+}
+
+// Traced returns a copy of the space sources whose clients record spans as children
+// of the span in the context
+func (s *SpaceSources) Traced(ctx context.Context) interface{} {
+	return tracing.BindFields(ctx, s)
+}
+
+// SourceGuids returns the guid of the space followed by the guids of its
+// apps, and false when the space does not exist
+func (s *SpaceSources) SourceGuids(spaceGuid string) ([]string, bool, error) {
+	token, err := s.UAAClient.GetToken()
+	if err != nil {
+		return nil, false, fmt.Errorf("getting token: %s", err)
+	}
+
+	liveSpaceGUIDs, err := s.CCClient.GetLiveSpaceGUIDs(token, []string{spaceGuid})
+	if err != nil {
+		return nil, false, fmt.Errorf("getting space: %s", err)
+	}
+	if _, ok := liveSpaceGUIDs[spaceGuid]; !ok {
+		return nil, false, nil
+	}
+
+	apps, err := s.CCClient.GetApps(token, cc_client.ResourceFilter{ParentGUIDs: []string{spaceGuid}})
+	if err != nil {
+		return nil, false, fmt.Errorf("getting apps: %s", err)
+	}
+
+	sourceGuids := []string{spaceGuid}
+	for _, app := range apps {
+		sourceGuids = append(sourceGuids, app.GUID)
+	}
+	return sourceGuids, true, nil
+}
@@ -0,0 +1,95 @@
+package handlers_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/policy-server/cc_client"
+	ccfakes "code.cloudfoundry.org/policy-server/cc_client/fakes"
+	"code.cloudfoundry.org/policy-server/handlers"
+	uaafakes "code.cloudfoundry.org/policy-server/uaa_client/fakes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SpaceSources", func() {
+	var (
+		spaceSources  *handlers.SpaceSources
+		fakeUAAClient *uaafakes.UAAClient
+		fakeCCClient  *ccfakes.CCClient
+	)
+
+	BeforeEach(func() {
+		fakeUAAClient = &uaafakes.UAAClient{}
+		fakeUAAClient.GetTokenReturns("policy-server-token", nil)
+		fakeCCClient = &ccfakes.CCClient{}
+		fakeCCClient.GetLiveSpaceGUIDsReturns(map[string]struct{}{"some-space-guid": {}}, nil)
+		fakeCCClient.GetAppsReturns([]cc_client.Resource{
+			{GUID: "app-1-guid", Name: "app-1", ParentGUID: "some-space-guid"},
+			{GUID: "app-2-guid", Name: "app-2", ParentGUID: "some-space-guid"},
+		}, nil)
+		spaceSources = handlers.NewSpaceSources(fakeUAAClient, fakeCCClient)
+	})
+
+	It("returns the space and its apps", func() {
+		sourceGuids, found, err := spaceSources.SourceGuids("some-space-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(sourceGuids).To(Equal([]string{"some-space-guid", "app-1-guid", "app-2-guid"}))
+
+		token, spaceGUIDs := fakeCCClient.GetLiveSpaceGUIDsArgsForCall(0)
+		Expect(token).To(Equal("policy-server-token"))
+		Expect(spaceGUIDs).To(Equal([]string{"some-space-guid"}))
+
+		token, filter := fakeCCClient.GetAppsArgsForCall(0)
+		Expect(token).To(Equal("policy-server-token"))
+		Expect(filter).To(Equal(cc_client.ResourceFilter{ParentGUIDs: []string{"some-space-guid"}}))
+	})
+
+	Context("when the space does not exist", func() {
+		BeforeEach(func() {
+			fakeCCClient.GetLiveSpaceGUIDsReturns(map[string]struct{}{}, nil)
+		})
+
+		It("returns not found", func() {
+			sourceGuids, found, err := spaceSources.SourceGuids("some-space-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+			Expect(sourceGuids).To(BeNil())
+			Expect(fakeCCClient.GetAppsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when getting the token fails", func() {
+		BeforeEach(func() {
+			fakeUAAClient.GetTokenReturns("", errors.New("banana"))
+		})
+
+		It("returns an error", func() {
+			_, _, err := spaceSources.SourceGuids("some-space-guid")
+			Expect(err).To(MatchError("getting token: banana"))
+		})
+	})
+
+	Context("when getting the space fails", func() {
+		BeforeEach(func() {
+			fakeCCClient.GetLiveSpaceGUIDsReturns(nil, errors.New("banana"))
+		})
+
+		It("returns an error", func() {
+			_, _, err := spaceSources.SourceGuids("some-space-guid")
+			Expect(err).To(MatchError("getting space: banana"))
+		})
+	})
+
+	Context("when getting the apps fails", func() {
+		BeforeEach(func() {
+			fakeCCClient.GetAppsReturns(nil, errors.New("banana"))
+		})
+
+		It("returns an error", func() {
+			_, _, err := spaceSources.SourceGuids("some-space-guid")
+			Expect(err).To(MatchError("getting apps: banana"))
+		})
+	})
+})
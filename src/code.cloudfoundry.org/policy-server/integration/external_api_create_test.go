@@ -107,9 +107,19 @@ var _ = Describe("External API Adding Policies", func() {
 		v0RequestMissingProtocol := `{ "policies": [ {"source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "port": 8080 } } ] }`
 		v0Response := `{ "total_policies": 1, "policies": [ { "source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "protocol": "tcp", "port": 8080 } } ]}`
 
-		missingStartPortResponse := `{ "error": "mapper: validate policies: missing start port" }`
-		missingPortResponse := `{ "error": "mapper: validate policies: missing port" }`
-		invalidProtocolResponse := `{ "error": "mapper: validate policies: invalid destination protocol, specify either udp or tcp" }`
+		rejectedResponse := func(policy, reason string) string {
+			return fmt.Sprintf(`{
+				"error": "mapper: %[2]s",
+				"metadata": { "failures": [ { "index": 0, "policy": %[1]s, "code": "invalid_policy", "reason": "%[2]s" } ] }
+			}`, policy, reason)
+		}
+		v0Policy := `{"source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "protocol": "tcp", "port": 8080 } }`
+		v1Policy := `{"source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "protocol": "tcp", "ports": { "start": 8090, "end": 8090 } } }`
+		v0PolicyMissingProtocol := `{"source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "port": 8080 } }`
+		v1PolicyMissingProtocol := `{"source": { "id": "some-app-guid" }, "destination": { "id": "some-other-app-guid", "ports": { "start": 8080, "end": 8080 } } }`
+
+		invalidProtocol := "validate policies: invalid destination protocol, specify either udp, tcp, icmp or all"
+		v0InvalidProtocol := "validate policies: invalid destination protocol, specify either udp or tcp"
 
 		DescribeTable("adding policies succeeds", addPoliciesSucceeds,
 			Entry("v1", "v1", v1Request, v1Response),
@@ -117,11 +127,11 @@ var _ = Describe("External API Adding Policies", func() {
 		)
 
 		DescribeTable("failure cases", addPoliciesFails,
-			Entry("v1: missing ports", "v1", v0Request, missingStartPortResponse),
-			Entry("v1: missing protocol", "v1", v1RequestMissingProtocol, invalidProtocolResponse),
+			Entry("v1: missing ports", "v1", v0Request, rejectedResponse(v0Policy, "validate policies: missing start port")),
+			Entry("v1: missing protocol", "v1", v1RequestMissingProtocol, rejectedResponse(v1PolicyMissingProtocol, invalidProtocol)),
 
-			Entry("v0: missing port", "v0", v1Request, missingPortResponse),
-			Entry("v0: missing protocol", "v0", v0RequestMissingProtocol, invalidProtocolResponse),
+			Entry("v0: missing port", "v0", v1Request, rejectedResponse(v1Policy, "validate policies: missing port")),
+			Entry("v0: missing protocol", "v0", v0RequestMissingProtocol, rejectedResponse(v0PolicyMissingProtocol, v0InvalidProtocol)),
 		)
 	})
 })
@@ -152,7 +152,8 @@ var _ = Describe("External API Deleting Policies", func() {
 		missingStartPortResponse := `{ "error": "mapper: validate policies: missing start port" }`
 
 		missingPortResponse := `{ "error": "mapper: validate policies: missing port" }`
-		invalidProtocolResponse := `{ "error": "mapper: validate policies: invalid destination protocol, specify either udp or tcp" }`
+		invalidProtocolResponse := `{ "error": "mapper: validate policies: invalid destination protocol, specify either udp, tcp, icmp or all" }`
+		v0InvalidProtocolResponse := `{ "error": "mapper: validate policies: invalid destination protocol, specify either udp or tcp" }`
 
 		DescribeTable("deleting policies succeeds", deletePoliciesSucceeds,
 			Entry("v1", "v1", v1Request, v1Response),
@@ -166,7 +167,7 @@ var _ = Describe("External API Deleting Policies", func() {
 			Entry("v1: missing protocol", "v1", v1RequestMissingProtocol, invalidProtocolResponse),
 
 			Entry("v0: missing port", "v0", v1Request, missingPortResponse),
-			Entry("v0: missing protocol", "v0", v0RequestMissingProtocol, invalidProtocolResponse),
+			Entry("v0: missing protocol", "v0", v0RequestMissingProtocol, v0InvalidProtocolResponse),
 		)
 	})
 })
@@ -98,6 +98,13 @@ var MockCCServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWrite
 		}
 	}
 
+	if r.URL.Path == "/v3/roles" && r.URL.Query().Get("user_guids") == "some-user-or-client-id" {
+		w.WriteHeader(http.StatusOK)
+		// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
+		w.Write([]byte(fixtures.SubjectRoles))
+		return
+	}
+
 	if r.URL.Path == "/v2/users/some-user-or-client-id/spaces" {
 		w.WriteHeader(http.StatusOK)
 		// #nosec G104 - ignore errors writing http responses to avoid spamming logs during a DoS
@@ -205,6 +212,7 @@ func DefaultTestConfigWithCCServer(dbConfig db.Config, metronAddress string, fix
 		Database:                        dbConfig,
 		MetronAddress:                   metronAddress,
 		CleanupInterval:                 60,
+		GroupMembersUpdateInterval:      60,
 		CCAppRequestChunkSize:           100,
 		MaxPolicies:                     2,
 		EnableSpaceDeveloperSelfService: false,
@@ -0,0 +1,114 @@
+package integration_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"code.cloudfoundry.org/cf-networking-helpers/db"
+	"code.cloudfoundry.org/cf-networking-helpers/testsupport"
+	"code.cloudfoundry.org/cf-networking-helpers/testsupport/metrics"
+	"code.cloudfoundry.org/cf-networking-helpers/testsupport/ports"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/config"
+	"code.cloudfoundry.org/policy-server/integration/helpers"
+	"code.cloudfoundry.org/policy-server/psclient"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("Policy server client", func() {
+	var (
+		sessions          []*gexec.Session
+		policyServerConfs []config.Config
+		dbConf            db.Config
+		fakeMetron        metrics.FakeMetron
+		client            *psclient.Client
+		policies          []api.Policy
+	)
+
+	BeforeEach(func() {
+		fakeMetron = metrics.NewFakeMetron()
+
+		dbConf = testsupport.GetDBConfig()
+		dbConf.DatabaseName = fmt.Sprintf("psclient_test_node_%d", ports.PickAPort())
+
+		template, _, _ := helpers.DefaultTestConfig(dbConf, fakeMetron.Address(), "fixtures")
+		policyServerConfs = configurePolicyServers(template, 1)
+		sessions = startPolicyServers(policyServerConfs)
+		conf := policyServerConfs[0]
+
+		client = psclient.NewClient(lagertest.NewTestLogger("psclient"), http.DefaultClient,
+			fmt.Sprintf("http://%s:%d", conf.ListenHost, conf.ListenPort))
+		client.RetryInterval = 0
+
+		policies = []api.Policy{
+			{
+				Source:      api.Source{ID: "some-app-guid"},
+				Destination: api.Destination{ID: "some-other-app-guid", Protocol: "tcp", Ports: api.Ports{Start: 8090, End: 8090}},
+			},
+			{
+				Source:      api.Source{ID: "another-app-guid"},
+				Destination: api.Destination{ID: "some-app-guid", Protocol: "udp", Ports: api.Ports{Start: 6666, End: 6666}},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		stopPolicyServers(sessions, policyServerConfs)
+
+		Expect(fakeMetron.Close()).To(Succeed())
+	})
+
+	It("creates, lists and deletes policies", func() {
+		Expect(client.CreatePolicies("valid-token", policies...)).To(Succeed())
+
+		listed, err := client.ListPolicies("valid-token", psclient.ListPoliciesOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listed).To(ConsistOf(policies))
+
+		By("filtering the policies")
+		listed, err = client.ListPolicies("valid-token", psclient.ListPoliciesOptions{SourceIDs: []string{"another-app-guid"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listed).To(Equal(policies[1:]))
+
+		By("listing the tags")
+		tags, err := client.ListTags("valid-token")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tags).To(HaveLen(3))
+
+		By("deleting a policy")
+		Expect(client.DeletePolicies("valid-token", policies[0])).To(Succeed())
+
+		listed, err = client.ListPolicies("valid-token", psclient.ListPoliciesOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listed).To(Equal(policies[1:]))
+	})
+
+	It("returns the subject of the token", func() {
+		subject, err := client.WhoAmI("valid-token")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subject).To(Equal("some-user"))
+	})
+
+	It("cleans up policies", func() {
+		Expect(client.CreatePolicies("valid-token", policies...)).To(Succeed())
+
+		_, err := client.CleanupPolicies("valid-token")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("returns typed errors", func() {
+		err := client.CreatePolicies("valid-token", api.Policy{
+			Source:      api.Source{ID: "some-app-guid"},
+			Destination: api.Destination{ID: "some-other-app-guid", Protocol: "banana", Ports: api.Ports{Start: 8090, End: 8090}},
+		})
+
+		var psErr *psclient.Error
+		Expect(errors.As(err, &psErr)).To(BeTrue())
+		Expect(psErr.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+})
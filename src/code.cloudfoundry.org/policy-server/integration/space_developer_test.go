@@ -106,7 +106,15 @@ var _ = Describe("External API Space Developer", func() {
 						Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
 						responseString, err := io.ReadAll(resp.Body)
 						Expect(err).NotTo(HaveOccurred())
-						Expect(responseString).To(MatchJSON(`{ "error": "one or more applications cannot be found or accessed"}`))
+						Expect(responseString).To(MatchJSON(`{
+							"error": "one or more applications cannot be found or accessed",
+							"metadata": { "failures": [ {
+								"index": 0,
+								"policy": {"source": { "id": "some-app-guid" }, "destination": { "id": "app-guid-not-in-my-spaces", "protocol": "tcp", "ports": { "start": 8090, "end": 8090 } } },
+								"code": "forbidden",
+								"reason": "one or more applications cannot be found or accessed"
+							} ] }
+						}`))
 					})
 				})
 			})
@@ -147,7 +155,15 @@ var _ = Describe("External API Space Developer", func() {
 						Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
 						responseString, err := io.ReadAll(resp.Body)
 						Expect(err).NotTo(HaveOccurred())
-						Expect(responseString).To(MatchJSON(`{ "error": "one or more applications cannot be found or accessed"}`))
+						Expect(responseString).To(MatchJSON(`{
+							"error": "one or more applications cannot be found or accessed",
+							"metadata": { "failures": [ {
+								"index": 0,
+								"policy": {"source": { "id": "some-app-guid" }, "destination": { "id": "app-guid-not-in-my-spaces", "protocol": "tcp", "ports": { "start": 8090, "end": 8090 } } },
+								"code": "forbidden",
+								"reason": "one or more applications cannot be found or accessed"
+							} ] }
+						}`))
 					})
 				})
 			})
@@ -196,7 +212,15 @@ var _ = Describe("External API Space Developer", func() {
 				Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
 				responseString, err := io.ReadAll(resp.Body)
 				Expect(err).NotTo(HaveOccurred())
-				Expect(responseString).To(MatchJSON(`{"error": "policy quota exceeded"}`))
+				Expect(responseString).To(MatchJSON(`{
+					"error": "policy quota exceeded",
+					"metadata": { "failures": [ {
+						"index": 0,
+						"policy": {"source": { "id": "some-app-guid" }, "destination": { "id": "yet-another-other-app-guid", "protocol": "tcp", "ports": { "start": 9000, "end": 9000 } } },
+						"code": "quota_exceeded",
+						"reason": "policy quota exceeded"
+					} ] }
+				}`))
 
 				By("deleting a policy")
 				body = `{ "policies": [
@@ -0,0 +1,131 @@
+package internal_psclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/cf-networking-helpers/json_client"
+	"code.cloudfoundry.org/cf-networking-helpers/mutualtls"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/api"
+)
+
+const (
+	DefaultSecurityGroupsPerPage = 5000
+	DefaultTimeout               = 10 * time.Second
+)
+
+// Client calls the internal API of the policy server, which policy agents use
+// to read the policies and security groups that they enforce
+type Client struct {
+	JsonClient json_client.JsonClient
+	// SecurityGroupsPerPage is how many security groups are requested at a
+	// time
+	SecurityGroupsPerPage int
+}
+
+type tagRequest struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+func NewClient(logger lager.Logger, httpClient json_client.HttpClient, baseURL string) *Client {
+	return &Client{
+		JsonClient:            json_client.New(logger, httpClient, baseURL),
+		SecurityGroupsPerPage: DefaultSecurityGroupsPerPage,
+	}
+}
+
+// NewMutualTLSClient returns a client that authenticates to the policy
+// server with the client certificate and key, and trusts the server
+// certificate when the CA signed it
+func NewMutualTLSClient(logger lager.Logger, baseURL, certFile, keyFile, caCertFile string) (*Client, error) {
+	tlsConfig, err := mutualtls.NewClientTLSConfig(certFile, keyFile, caCertFile)
+	if err != nil {
+		return nil, fmt.Errorf("creating tls config: %s", err)
+	}
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		Timeout:   DefaultTimeout,
+	}
+	return NewClient(logger, httpClient, baseURL), nil
+}
+
+// GetPolicies returns the policies whose source or destination has one of
+// the ids, or every policy when no id is given
+func (c *Client) GetPolicies(ids ...string) ([]api.Policy, error) {
+	route := "/networking/v1/internal/policies"
+	if len(ids) > 0 {
+		route += "?" + url.Values{"id": {strings.Join(ids, ",")}}.Encode()
+	}
+
+	var payload api.PoliciesPayload
+	err := c.JsonClient.Do("GET", route, nil, &payload, "")
+	if err != nil {
+		return nil, fmt.Errorf("getting policies: %w", err)
+	}
+	return payload.Policies, nil
+}
+
+// GetPoliciesLastUpdated returns the time, in seconds since the epoch, of the
+// last change to the policies
+func (c *Client) GetPoliciesLastUpdated() (int, error) {
+	var lastUpdated int
+	err := c.JsonClient.Do("GET", "/networking/v1/internal/policies_last_updated", nil, &lastUpdated, "")
+	if err != nil {
+		return 0, fmt.Errorf("getting policies last updated: %w", err)
+	}
+	return lastUpdated, nil
+}
+
+// GetSecurityGroups returns the security groups that apply to the spaces, or
+// every security group when no space is given. It requests one page after
+// the other until the policy server has no next page.
+func (c *Client) GetSecurityGroups(spaceGUIDs ...string) ([]api.SecurityGroup, error) {
+	securityGroups := []api.SecurityGroup{}
+	from := 0
+	for {
+		values := url.Values{}
+		if len(spaceGUIDs) > 0 {
+			values.Set("space_guids", strings.Join(spaceGUIDs, ","))
+		}
+		if c.SecurityGroupsPerPage > 0 {
+			values.Set("limit", fmt.Sprintf("%d", c.SecurityGroupsPerPage))
+		}
+		if from > 0 {
+			values.Set("from", fmt.Sprintf("%d", from))
+		}
+
+		route := "/networking/v1/internal/security_groups"
+		if len(values) > 0 {
+			route += "?" + values.Encode()
+		}
+
+		var payload api.AsgsPayload
+		err := c.JsonClient.Do("GET", route, nil, &payload, "")
+		if err != nil {
+			return nil, fmt.Errorf("getting security groups: %w", err)
+		}
+		securityGroups = append(securityGroups, payload.SecurityGroups...)
+
+		if payload.Next == 0 || payload.Next <= from {
+			return securityGroups, nil
+		}
+		from = payload.Next
+	}
+}
+
+// CreateOrGetTag returns the tag of the group with the id, and creates one
+// when the group has none yet
+func (c *Client) CreateOrGetTag(id, groupType string) (api.Tag, error) {
+	var tag api.Tag
+	err := c.JsonClient.Do("PUT", "/networking/v1/internal/tags", tagRequest{ID: id, Type: groupType}, &tag, "")
+	if err != nil {
+		return api.Tag{}, fmt.Errorf("creating tag: %w", err)
+	}
+	return tag, nil
+}
@@ -0,0 +1,193 @@
+package internal_psclient_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/cf-networking-helpers/fakes"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/internal_psclient"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		jsonClient *fakes.JSONClient
+		client     *internal_psclient.Client
+	)
+
+	respondWith := func(bodies ...string) {
+		jsonClient.DoStub = func(method, route string, reqData, respData interface{}, token string) error {
+			body := bodies[0]
+			if len(bodies) > 1 {
+				bodies = bodies[1:]
+			}
+			return json.Unmarshal([]byte(body), respData)
+		}
+	}
+
+	BeforeEach(func() {
+		jsonClient = &fakes.JSONClient{}
+		client = &internal_psclient.Client{
+			JsonClient:            jsonClient,
+			SecurityGroupsPerPage: 2,
+		}
+	})
+
+	Describe("NewMutualTLSClient", func() {
+		Context("when the certificates cannot be loaded", func() {
+			It("returns an error", func() {
+				missing := filepath.Join(os.TempDir(), "does-not-exist")
+				_, err := internal_psclient.NewMutualTLSClient(lagertest.NewTestLogger("test"), "https://some-url", missing, missing, missing)
+				Expect(err).To(MatchError(ContainSubstring("creating tls config:")))
+			})
+		})
+	})
+
+	Describe("GetPolicies", func() {
+		BeforeEach(func() {
+			respondWith(`{"total_policies": 1, "policies": [{"source": {"id": "some-app-guid", "tag": "01"}, "destination": {"id": "some-other-app-guid", "tag": "02", "protocol": "tcp", "ports": {"start": 8080, "end": 8080}}}]}`)
+		})
+
+		It("gets every policy", func() {
+			policies, err := client.GetPolicies()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policies).To(Equal([]api.Policy{{
+				Source: api.Source{ID: "some-app-guid", Tag: "01"},
+				Destination: api.Destination{
+					ID:       "some-other-app-guid",
+					Tag:      "02",
+					Protocol: "tcp",
+					Ports:    api.Ports{Start: 8080, End: 8080},
+				},
+			}}))
+
+			method, route, reqData, _, token := jsonClient.DoArgsForCall(0)
+			Expect(method).To(Equal("GET"))
+			Expect(route).To(Equal("/networking/v1/internal/policies"))
+			Expect(reqData).To(BeNil())
+			Expect(token).To(BeEmpty())
+		})
+
+		Context("when ids are given", func() {
+			It("gets the policies of the ids", func() {
+				_, err := client.GetPolicies("some-app-guid", "some-other-app-guid")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, route, _, _, _ := jsonClient.DoArgsForCall(0)
+				Expect(route).To(Equal("/networking/v1/internal/policies?id=some-app-guid%2Csome-other-app-guid"))
+			})
+		})
+
+		Context("when the request fails", func() {
+			BeforeEach(func() {
+				jsonClient.DoReturns(errors.New("banana"))
+			})
+
+			It("returns an error", func() {
+				_, err := client.GetPolicies()
+				Expect(err).To(MatchError("getting policies: banana"))
+			})
+		})
+	})
+
+	Describe("GetPoliciesLastUpdated", func() {
+		It("returns the timestamp", func() {
+			respondWith(`1234`)
+
+			lastUpdated, err := client.GetPoliciesLastUpdated()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lastUpdated).To(Equal(1234))
+
+			method, route, _, _, _ := jsonClient.DoArgsForCall(0)
+			Expect(method).To(Equal("GET"))
+			Expect(route).To(Equal("/networking/v1/internal/policies_last_updated"))
+		})
+
+		Context("when the request fails", func() {
+			BeforeEach(func() {
+				jsonClient.DoReturns(errors.New("banana"))
+			})
+
+			It("returns an error", func() {
+				_, err := client.GetPoliciesLastUpdated()
+				Expect(err).To(MatchError("getting policies last updated: banana"))
+			})
+		})
+	})
+
+	Describe("GetSecurityGroups", func() {
+		BeforeEach(func() {
+			respondWith(
+				`{"next": 3, "security_groups": [{"guid": "sg-1"}, {"guid": "sg-2"}]}`,
+				`{"next": 0, "security_groups": [{"guid": "sg-3"}]}`,
+			)
+		})
+
+		It("gets every page", func() {
+			securityGroups, err := client.GetSecurityGroups("space-a", "space-b")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(securityGroups).To(Equal([]api.SecurityGroup{{Guid: "sg-1"}, {Guid: "sg-2"}, {Guid: "sg-3"}}))
+
+			Expect(jsonClient.DoCallCount()).To(Equal(2))
+			method, route, _, _, _ := jsonClient.DoArgsForCall(0)
+			Expect(method).To(Equal("GET"))
+			Expect(route).To(Equal("/networking/v1/internal/security_groups?limit=2&space_guids=space-a%2Cspace-b"))
+			_, route, _, _, _ = jsonClient.DoArgsForCall(1)
+			Expect(route).To(Equal("/networking/v1/internal/security_groups?from=3&limit=2&space_guids=space-a%2Cspace-b"))
+		})
+
+		Context("when no space is given", func() {
+			It("gets every security group", func() {
+				_, err := client.GetSecurityGroups()
+				Expect(err).NotTo(HaveOccurred())
+
+				_, route, _, _, _ := jsonClient.DoArgsForCall(0)
+				Expect(route).To(Equal("/networking/v1/internal/security_groups?limit=2"))
+			})
+		})
+
+		Context("when the request fails", func() {
+			BeforeEach(func() {
+				jsonClient.DoReturns(errors.New("banana"))
+			})
+
+			It("returns an error", func() {
+				_, err := client.GetSecurityGroups()
+				Expect(err).To(MatchError("getting security groups: banana"))
+			})
+		})
+	})
+
+	Describe("CreateOrGetTag", func() {
+		It("puts the tag", func() {
+			respondWith(`{"id": "some-group-guid", "type": "app", "tag": "0003"}`)
+
+			tag, err := client.CreateOrGetTag("some-group-guid", "app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tag).To(Equal(api.Tag{ID: "some-group-guid", Type: "app", Tag: "0003"}))
+
+			method, route, reqData, _, _ := jsonClient.DoArgsForCall(0)
+			Expect(method).To(Equal("PUT"))
+			Expect(route).To(Equal("/networking/v1/internal/tags"))
+			reqJSON, err := json.Marshal(reqData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reqJSON).To(MatchJSON(`{"id": "some-group-guid", "type": "app"}`))
+		})
+
+		Context("when the request fails", func() {
+			BeforeEach(func() {
+				jsonClient.DoReturns(errors.New("banana"))
+			})
+
+			It("returns an error", func() {
+				_, err := client.CreateOrGetTag("some-group-guid", "app")
+				Expect(err).To(MatchError("creating tag: banana"))
+			})
+		})
+	})
+})
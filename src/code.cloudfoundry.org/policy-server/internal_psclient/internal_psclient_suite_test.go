@@ -0,0 +1,13 @@
+package internal_psclient_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestInternalPsclient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "InternalPsclient Suite")
+}
@@ -0,0 +1,56 @@
+package internal_psclient
+
+import (
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/cf-networking-helpers/poller"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/api"
+)
+
+// PoliciesPoller checks on every cycle when the policies last changed, and
+// only then gets them and passes them to OnChange
+type PoliciesPoller struct {
+	Client      *Client
+	OnChange    func(policies []api.Policy) error
+	lastUpdated int
+}
+
+// NewPoliciesPoller returns a runner that calls onChange with every policy
+// right away, and again whenever the policies change
+func NewPoliciesPoller(logger lager.Logger, client *Client, interval time.Duration, onChange func([]api.Policy) error) *poller.Poller {
+	p := &PoliciesPoller{
+		Client:   client,
+		OnChange: onChange,
+	}
+	return &poller.Poller{
+		Logger:                 logger.Session("policies-poller"),
+		PollInterval:           interval,
+		RunBeforeFirstInterval: true,
+		SingleCycleFunc:        p.Poll,
+	}
+}
+
+// Poll gets the policies and passes them to OnChange when they changed since
+// the last successful call
+func (p *PoliciesPoller) Poll() error {
+	lastUpdated, err := p.Client.GetPoliciesLastUpdated()
+	if err != nil {
+		return err
+	}
+	if p.lastUpdated != 0 && lastUpdated == p.lastUpdated {
+		return nil
+	}
+
+	policies, err := p.Client.GetPolicies()
+	if err != nil {
+		return err
+	}
+
+	if err := p.OnChange(policies); err != nil {
+		return fmt.Errorf("handling policies: %w", err)
+	}
+	p.lastUpdated = lastUpdated
+	return nil
+}
@@ -0,0 +1,84 @@
+package internal_psclient_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"code.cloudfoundry.org/cf-networking-helpers/fakes"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/internal_psclient"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PoliciesPoller", func() {
+	var (
+		jsonClient  *fakes.JSONClient
+		poller      *internal_psclient.PoliciesPoller
+		lastUpdated string
+		received    [][]api.Policy
+		onChangeErr error
+	)
+
+	BeforeEach(func() {
+		jsonClient = &fakes.JSONClient{}
+		lastUpdated = "100"
+		received = nil
+		onChangeErr = nil
+		jsonClient.DoStub = func(method, route string, reqData, respData interface{}, token string) error {
+			if strings.HasSuffix(route, "/policies_last_updated") {
+				return json.Unmarshal([]byte(lastUpdated), respData)
+			}
+			return json.Unmarshal([]byte(`{"policies": [{"source": {"id": "some-app-guid"}}]}`), respData)
+		}
+
+		poller = &internal_psclient.PoliciesPoller{
+			Client: &internal_psclient.Client{JsonClient: jsonClient},
+			OnChange: func(policies []api.Policy) error {
+				received = append(received, policies)
+				return onChangeErr
+			},
+		}
+	})
+
+	It("passes the policies on only when they changed", func() {
+		Expect(poller.Poll()).To(Succeed())
+		Expect(received).To(HaveLen(1))
+		Expect(received[0]).To(Equal([]api.Policy{{Source: api.Source{ID: "some-app-guid"}}}))
+
+		Expect(poller.Poll()).To(Succeed())
+		Expect(received).To(HaveLen(1))
+		Expect(jsonClient.DoCallCount()).To(Equal(3))
+
+		lastUpdated = "200"
+		Expect(poller.Poll()).To(Succeed())
+		Expect(received).To(HaveLen(2))
+	})
+
+	Context("when getting the last updated timestamp fails", func() {
+		BeforeEach(func() {
+			jsonClient.DoStub = nil
+			jsonClient.DoReturns(errors.New("banana"))
+		})
+
+		It("returns an error", func() {
+			Expect(poller.Poll()).To(MatchError("getting policies last updated: banana"))
+			Expect(received).To(BeEmpty())
+		})
+	})
+
+	Context("when handling the policies fails", func() {
+		BeforeEach(func() {
+			onChangeErr = errors.New("banana")
+		})
+
+		It("returns an error and passes them on again in the next cycle", func() {
+			Expect(poller.Poll()).To(MatchError("handling policies: banana"))
+
+			onChangeErr = nil
+			Expect(poller.Poll()).To(Succeed())
+			Expect(received).To(HaveLen(2))
+		})
+	})
+})
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"code.cloudfoundry.org/cf-networking-helpers/json_client"
 	"code.cloudfoundry.org/lager/v3"
@@ -12,6 +13,10 @@ import (
 
 type Client struct {
 	JsonClient json_client.JsonClient
+	// Retries is how many times a request is retried when the policy server
+	// cannot be reached or responds with a server error
+	Retries       int
+	RetryInterval time.Duration
 }
 
 type IPRange struct {
@@ -46,7 +51,9 @@ type ListDestinationsOptions struct {
 
 func NewClient(logger lager.Logger, httpClient json_client.HttpClient, baseURL string) *Client {
 	return &Client{
-		JsonClient: json_client.New(logger, httpClient, baseURL),
+		JsonClient:    json_client.New(logger, httpClient, baseURL),
+		Retries:       DefaultRetries,
+		RetryInterval: DefaultRetryInterval,
 	}
 }
 
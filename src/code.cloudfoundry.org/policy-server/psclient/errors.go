@@ -0,0 +1,82 @@
+package psclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/cf-networking-helpers/json_client"
+)
+
+const (
+	DefaultRetries       = 3
+	DefaultRetryInterval = time.Second
+)
+
+// Error is returned when the policy server responds to a request with an
+// error status
+type Error struct {
+	Method     string
+	Route      string
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s: http status %d: %s", e.Method, e.Route, e.StatusCode, e.Message)
+}
+
+// IsNotFound returns whether the error is a 404 response of the policy server
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsForbidden returns whether the error is a 403 response of the policy
+// server, e.g. because the subject has no access to an app of a policy
+func IsForbidden(err error) bool {
+	return hasStatusCode(err, http.StatusForbidden)
+}
+
+// IsUnauthorized returns whether the error is a 401 response of the policy
+// server, e.g. because the token is invalid or has expired
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized)
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	var psErr *Error
+	return errors.As(err, &psErr) && psErr.StatusCode == statusCode
+}
+
+// do sends the request, and sends it again up to Retries times when it fails
+// with an error that may go away: the policy server cannot be reached, or
+// responds with a server error or too many requests. Every policy server
+// endpoint that the client calls is idempotent.
+func (c *Client) do(method, route string, reqData, respData interface{}, token string) error {
+	var err error
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(c.RetryInterval)
+		}
+
+		err = c.JsonClient.Do(method, route, reqData, respData, "Bearer "+token)
+		if err == nil {
+			return nil
+		}
+
+		var codeErr *json_client.HttpResponseCodeError
+		if errors.As(err, &codeErr) {
+			err = &Error{
+				Method:     method,
+				Route:      route,
+				StatusCode: codeErr.StatusCode,
+				Message:    codeErr.Message,
+			}
+			if codeErr.StatusCode < http.StatusInternalServerError && codeErr.StatusCode != http.StatusTooManyRequests {
+				return err
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+package psclient
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"code.cloudfoundry.org/policy-server/api"
+)
+
+type PoliciesList struct {
+	Policies []api.Policy `json:"policies"`
+}
+
+type TagsList struct {
+	Tags []api.Tag `json:"tags"`
+}
+
+type WhoAmI struct {
+	Subject string `json:"subject"`
+}
+
+// ListPoliciesOptions filters the listed policies. IDs matches policies with
+// any of the ids as source or destination, and takes precedence over the
+// source and destination ids.
+type ListPoliciesOptions struct {
+	IDs           []string
+	SourceIDs     []string
+	DestIDs       []string
+	LabelSelector string
+}
+
+func (c *Client) CreatePolicies(token string, policies ...api.Policy) error {
+	err := c.do("POST", "/networking/v1/external/policies", PoliciesList{
+		Policies: policies,
+	}, nil, token)
+	if err != nil {
+		return fmt.Errorf("creating policies: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) DeletePolicies(token string, policies ...api.Policy) error {
+	err := c.do("POST", "/networking/v1/external/policies/delete", PoliciesList{
+		Policies: policies,
+	}, nil, token)
+	if err != nil {
+		return fmt.Errorf("deleting policies: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) ListPolicies(token string, options ListPoliciesOptions) ([]api.Policy, error) {
+	query := url.Values{}
+	if len(options.IDs) > 0 {
+		query.Set("id", strings.Join(options.IDs, ","))
+	}
+	if len(options.SourceIDs) > 0 {
+		query.Set("source_id", strings.Join(options.SourceIDs, ","))
+	}
+	if len(options.DestIDs) > 0 {
+		query.Set("dest_id", strings.Join(options.DestIDs, ","))
+	}
+	if options.LabelSelector != "" {
+		query.Set("label_selector", options.LabelSelector)
+	}
+
+	route := "/networking/v1/external/policies"
+	if len(query) > 0 {
+		route += "?" + query.Encode()
+	}
+
+	var response api.PoliciesPayload
+	err := c.do("GET", route, nil, &response, token)
+	if err != nil {
+		return nil, fmt.Errorf("listing policies: %w", err)
+	}
+	return response.Policies, nil
+}
+
+// CleanupPolicies deletes the policies of apps that no longer exist, and
+// returns them
+func (c *Client) CleanupPolicies(token string) ([]api.Policy, error) {
+	var response api.PoliciesPayload
+	err := c.do("POST", "/networking/v1/external/policies/cleanup", nil, &response, token)
+	if err != nil {
+		return nil, fmt.Errorf("cleaning up policies: %w", err)
+	}
+	return response.Policies, nil
+}
+
+func (c *Client) ListTags(token string) ([]api.Tag, error) {
+	var response TagsList
+	err := c.do("GET", "/networking/v1/external/tags", nil, &response, token)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags: %w", err)
+	}
+	return response.Tags, nil
+}
+
+// WhoAmI returns the user name of the subject of the token, or the client
+// subject when the token has no user name
+func (c *Client) WhoAmI(token string) (string, error) {
+	var response WhoAmI
+	err := c.do("GET", "/networking/v1/external/whoami", nil, &response, token)
+	if err != nil {
+		return "", fmt.Errorf("who am i: %w", err)
+	}
+	return response.Subject, nil
+}
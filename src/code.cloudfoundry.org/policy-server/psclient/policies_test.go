@@ -0,0 +1,241 @@
+package psclient_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/cf-networking-helpers/fakes"
+	"code.cloudfoundry.org/cf-networking-helpers/json_client"
+	"code.cloudfoundry.org/policy-server/api"
+	"code.cloudfoundry.org/policy-server/psclient"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policies", func() {
+	var (
+		jsonClient *fakes.JSONClient
+		client     *psclient.Client
+		token      string
+		policies   []api.Policy
+	)
+
+	respondWith := func(body string) {
+		jsonClient.DoStub = func(method, route string, reqData, respData interface{}, token string) error {
+			return json.Unmarshal([]byte(body), respData)
+		}
+	}
+
+	BeforeEach(func() {
+		jsonClient = &fakes.JSONClient{}
+		client = &psclient.Client{
+			JsonClient: jsonClient,
+			Retries:    2,
+		}
+		token = "some-token"
+		policies = []api.Policy{{
+			Source: api.Source{ID: "some-app-guid"},
+			Destination: api.Destination{
+				ID:       "some-other-app-guid",
+				Protocol: "tcp",
+				Ports:    api.Ports{Start: 8080, End: 8080},
+			},
+		}}
+	})
+
+	Describe("CreatePolicies", func() {
+		It("posts the policies", func() {
+			err := client.CreatePolicies(token, policies...)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(jsonClient.DoCallCount()).To(Equal(1))
+			method, route, reqData, respData, passedToken := jsonClient.DoArgsForCall(0)
+			Expect(method).To(Equal("POST"))
+			Expect(route).To(Equal("/networking/v1/external/policies"))
+			Expect(reqData).To(Equal(psclient.PoliciesList{Policies: policies}))
+			Expect(respData).To(BeNil())
+			Expect(passedToken).To(Equal("Bearer some-token"))
+		})
+
+		Context("when the policy server responds with an error", func() {
+			BeforeEach(func() {
+				jsonClient.DoReturns(&json_client.HttpResponseCodeError{
+					StatusCode: http.StatusForbidden,
+					Message:    "one or more applications cannot be found or accessed",
+				})
+			})
+
+			It("returns a typed error without retrying", func() {
+				err := client.CreatePolicies(token, policies...)
+				Expect(err).To(MatchError("creating policies: POST /networking/v1/external/policies: http status 403: one or more applications cannot be found or accessed"))
+				Expect(psclient.IsForbidden(err)).To(BeTrue())
+				Expect(psclient.IsNotFound(err)).To(BeFalse())
+
+				var psErr *psclient.Error
+				Expect(errors.As(err, &psErr)).To(BeTrue())
+				Expect(psErr.StatusCode).To(Equal(http.StatusForbidden))
+				Expect(jsonClient.DoCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Describe("DeletePolicies", func() {
+		It("posts the policies to delete", func() {
+			err := client.DeletePolicies(token, policies...)
+			Expect(err).NotTo(HaveOccurred())
+
+			method, route, reqData, _, passedToken := jsonClient.DoArgsForCall(0)
+			Expect(method).To(Equal("POST"))
+			Expect(route).To(Equal("/networking/v1/external/policies/delete"))
+			Expect(reqData).To(Equal(psclient.PoliciesList{Policies: policies}))
+			Expect(passedToken).To(Equal("Bearer some-token"))
+		})
+
+		It("returns an error when the json client do fails", func() {
+			jsonClient.DoReturns(errors.New("banana"))
+			err := client.DeletePolicies(token, policies...)
+			Expect(err).To(MatchError("deleting policies: banana"))
+		})
+	})
+
+	Describe("ListPolicies", func() {
+		BeforeEach(func() {
+			respondWith(`{
+				"total_policies": 1,
+				"policies": [{
+					"source": { "id": "some-app-guid" },
+					"destination": { "id": "some-other-app-guid", "protocol": "tcp", "ports": { "start": 8080, "end": 8080 } }
+				}]
+			}`)
+		})
+
+		It("returns the policies", func() {
+			returnedPolicies, err := client.ListPolicies(token, psclient.ListPoliciesOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(returnedPolicies).To(Equal(policies))
+
+			method, route, reqData, _, passedToken := jsonClient.DoArgsForCall(0)
+			Expect(method).To(Equal("GET"))
+			Expect(route).To(Equal("/networking/v1/external/policies"))
+			Expect(reqData).To(BeNil())
+			Expect(passedToken).To(Equal("Bearer some-token"))
+		})
+
+		It("filters the policies", func() {
+			_, err := client.ListPolicies(token, psclient.ListPoliciesOptions{
+				IDs:           []string{"app-1", "app-2"},
+				SourceIDs:     []string{"app-3"},
+				DestIDs:       []string{"app-4"},
+				LabelSelector: "team=a",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, route, _, _, _ := jsonClient.DoArgsForCall(0)
+			Expect(route).To(Equal("/networking/v1/external/policies?dest_id=app-4&id=app-1%2Capp-2&label_selector=team%3Da&source_id=app-3"))
+		})
+
+		Context("when the policy server responds with a server error", func() {
+			BeforeEach(func() {
+				jsonClient.DoStub = nil
+				jsonClient.DoReturnsOnCall(0, &json_client.HttpResponseCodeError{StatusCode: http.StatusInternalServerError, Message: "database read failed"})
+				jsonClient.DoReturnsOnCall(1, errors.New("http client do: connection refused"))
+				jsonClient.DoReturnsOnCall(2, nil)
+			})
+
+			It("retries the request", func() {
+				_, err := client.ListPolicies(token, psclient.ListPoliciesOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(jsonClient.DoCallCount()).To(Equal(3))
+			})
+
+			Context("when every attempt fails", func() {
+				BeforeEach(func() {
+					jsonClient.DoReturnsOnCall(2, &json_client.HttpResponseCodeError{StatusCode: http.StatusServiceUnavailable, Message: "banana"})
+				})
+
+				It("returns the last error", func() {
+					_, err := client.ListPolicies(token, psclient.ListPoliciesOptions{})
+					Expect(err).To(MatchError("listing policies: GET /networking/v1/external/policies: http status 503: banana"))
+					Expect(jsonClient.DoCallCount()).To(Equal(3))
+				})
+			})
+		})
+	})
+
+	Describe("CleanupPolicies", func() {
+		BeforeEach(func() {
+			respondWith(`{
+				"total_policies": 1,
+				"policies": [{
+					"source": { "id": "some-app-guid" },
+					"destination": { "id": "some-other-app-guid", "protocol": "tcp", "ports": { "start": 8080, "end": 8080 } }
+				}]
+			}`)
+		})
+
+		It("returns the deleted policies", func() {
+			deleted, err := client.CleanupPolicies(token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(policies))
+
+			method, route, _, _, _ := jsonClient.DoArgsForCall(0)
+			Expect(method).To(Equal("POST"))
+			Expect(route).To(Equal("/networking/v1/external/policies/cleanup"))
+		})
+
+		It("returns an error when the json client do fails", func() {
+			jsonClient.DoStub = nil
+			jsonClient.DoReturns(&json_client.HttpResponseCodeError{StatusCode: http.StatusUnauthorized, Message: "missing authorization header"})
+			_, err := client.CleanupPolicies(token)
+			Expect(err).To(MatchError(ContainSubstring("cleaning up policies:")))
+			Expect(psclient.IsUnauthorized(err)).To(BeTrue())
+		})
+	})
+
+	Describe("ListTags", func() {
+		BeforeEach(func() {
+			respondWith(`{ "tags": [{ "id": "some-app-guid", "tag": "0001", "type": "app" }] }`)
+		})
+
+		It("returns the tags", func() {
+			tags, err := client.ListTags(token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tags).To(Equal([]api.Tag{{ID: "some-app-guid", Tag: "0001", Type: "app"}}))
+
+			method, route, _, _, _ := jsonClient.DoArgsForCall(0)
+			Expect(method).To(Equal("GET"))
+			Expect(route).To(Equal("/networking/v1/external/tags"))
+		})
+
+		It("returns an error when the json client do fails", func() {
+			jsonClient.DoStub = nil
+			jsonClient.DoReturns(errors.New("banana"))
+			_, err := client.ListTags(token)
+			Expect(err).To(MatchError("listing tags: banana"))
+		})
+	})
+
+	Describe("WhoAmI", func() {
+		BeforeEach(func() {
+			respondWith(`{ "subject": "some-user" }`)
+		})
+
+		It("returns the subject", func() {
+			subject, err := client.WhoAmI(token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subject).To(Equal("some-user"))
+
+			method, route, _, _, _ := jsonClient.DoArgsForCall(0)
+			Expect(method).To(Equal("GET"))
+			Expect(route).To(Equal("/networking/v1/external/whoami"))
+		})
+
+		It("returns an error when the json client do fails", func() {
+			jsonClient.DoStub = nil
+			jsonClient.DoReturns(errors.New("banana"))
+			_, err := client.WhoAmI(token)
+			Expect(err).To(MatchError("who am i: banana"))
+		})
+	})
+})
@@ -0,0 +1,177 @@
+package server_metrics
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"code.cloudfoundry.org/cf-networking-helpers/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the duration
+// histograms
+var DefaultBuckets = prometheus.DefBuckets
+
+const (
+	requestTimeSuffix  = "RequestTime"
+	requestCountSuffix = "RequestCount"
+	storePrefix        = "Store"
+	successTimeSuffix  = "SuccessTime"
+	errorTimeSuffix    = "ErrorTime"
+)
+
+// PrometheusRegistry keeps the durations and counters that the jobs send
+// to dropsonde, and serves them with the values of its sources in the
+// Prometheus text format.
+//
+// Durations of requests and store operations are kept as histograms with the
+// route or the operation as label. Counters of requests are left out, as the
+// histograms already count them.
+type PrometheusRegistry struct {
+	Namespace string
+	Buckets   []float64
+
+	mutex      sync.Mutex
+	registry   *prometheus.Registry
+	handler    http.Handler
+	histograms map[string]*prometheus.HistogramVec
+	events     *prometheus.CounterVec
+	sources    []metrics.MetricSource
+}
+
+func NewPrometheusRegistry(namespace string) *PrometheusRegistry {
+	r := &PrometheusRegistry{
+		Namespace:  namespace,
+		Buckets:    DefaultBuckets,
+		registry:   prometheus.NewRegistry(),
+		histograms: map[string]*prometheus.HistogramVec{},
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "events_total",
+			Help:      "Number of times each event happened.",
+		}, []string{"name"}),
+	}
+	r.registry.MustRegister(r.events, sourceCollector{registry: r})
+	r.handler = promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+	return r
+}
+
+// AddSources adds metric sources whose values are read on every scrape and
+// served as gauges
+func (r *PrometheusRegistry) AddSources(sources ...metrics.MetricSource) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.sources = append(r.sources, sources...)
+}
+
+func (r *PrometheusRegistry) SendDuration(name string, duration time.Duration) {
+	switch {
+	case strings.HasSuffix(name, requestTimeSuffix):
+		r.histogram("http_request_duration_seconds", "Duration of the requests to each route.", "route").
+			WithLabelValues(strings.TrimSuffix(name, requestTimeSuffix)).Observe(duration.Seconds())
+	case strings.HasPrefix(name, storePrefix) && strings.HasSuffix(name, successTimeSuffix):
+		r.histogram("store_operation_duration_seconds", "Duration of the store operations.", "operation", "result").
+			WithLabelValues(strings.TrimSuffix(strings.TrimPrefix(name, storePrefix), successTimeSuffix), "success").
+			Observe(duration.Seconds())
+	case strings.HasPrefix(name, storePrefix) && strings.HasSuffix(name, errorTimeSuffix):
+		r.histogram("store_operation_duration_seconds", "Duration of the store operations.", "operation", "result").
+			WithLabelValues(strings.TrimSuffix(strings.TrimPrefix(name, storePrefix), errorTimeSuffix), "error").
+			Observe(duration.Seconds())
+	default:
+		r.histogram("duration_seconds", "Duration of other operations.", "name").
+			WithLabelValues(name).Observe(duration.Seconds())
+	}
+}
+
+func (r *PrometheusRegistry) IncrementCounter(name string) {
+	if strings.HasSuffix(name, requestCountSuffix) {
+		return
+	}
+	r.events.WithLabelValues(name).Inc()
+}
+
+func (r *PrometheusRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.handler.ServeHTTP(w, req)
+}
+
+// MultiSender sends durations and counters to each of its senders
+type MultiSender []metricsSender
+
+func (m MultiSender) SendDuration(name string, duration time.Duration) {
+	for _, sender := range m {
+		sender.SendDuration(name, duration)
+	}
+}
+
+func (m MultiSender) IncrementCounter(name string) {
+	for _, sender := range m {
+		sender.IncrementCounter(name)
+	}
+}
+
+// histogram returns the histogram of the name, which is registered with the
+// buckets of the registry the first time it is used
+func (r *PrometheusRegistry) histogram(name, help string, labelNames ...string) *prometheus.HistogramVec {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	histogram, ok := r.histograms[name]
+	if !ok {
+		histogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: r.Namespace,
+			Name:      name,
+			Help:      help,
+			Buckets:   r.Buckets,
+		}, labelNames)
+		r.registry.MustRegister(histogram)
+		r.histograms[name] = histogram
+	}
+	return histogram
+}
+
+// sourceCollector reads the metric sources of the registry on every scrape.
+// The sources are only known then, so it describes no metrics up front.
+type sourceCollector struct {
+	registry *PrometheusRegistry
+}
+
+func (c sourceCollector) Describe(chan<- *prometheus.Desc) {}
+
+func (c sourceCollector) Collect(ch chan<- prometheus.Metric) {
+	c.registry.mutex.Lock()
+	sources := append([]metrics.MetricSource{}, c.registry.sources...)
+	c.registry.mutex.Unlock()
+
+	for _, source := range sources {
+		value, err := source.Getter()
+		if err != nil {
+			continue
+		}
+		desc := prometheus.NewDesc(prometheus.BuildFQName(c.registry.Namespace, "", snakeCase(source.Name)),
+			"Value of the "+source.Name+" metric.", nil, nil)
+		metric, err := prometheus.NewConstMetric(desc, prometheus.GaugeValue, value)
+		if err != nil {
+			continue
+		}
+		ch <- metric
+	}
+}
+
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
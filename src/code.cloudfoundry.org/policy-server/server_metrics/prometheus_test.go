@@ -0,0 +1,112 @@
+package server_metrics_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/cf-networking-helpers/metrics"
+	"code.cloudfoundry.org/policy-server/server_metrics"
+	"code.cloudfoundry.org/policy-server/server_metrics/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PrometheusRegistry", func() {
+	var registry *server_metrics.PrometheusRegistry
+
+	scrape := func() string {
+		resp := httptest.NewRecorder()
+		registry.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		return resp.Body.String()
+	}
+
+	BeforeEach(func() {
+		registry = server_metrics.NewPrometheusRegistry("policy_server")
+		registry.Buckets = []float64{0.1, 1}
+	})
+
+	It("keeps request durations as histograms per route", func() {
+		registry.SendDuration("CreatePoliciesRequestTime", 50*time.Millisecond)
+		registry.SendDuration("CreatePoliciesRequestTime", 2*time.Second)
+		registry.IncrementCounter("CreatePoliciesRequestCount")
+
+		Expect(scrape()).To(Equal(`# HELP policy_server_http_request_duration_seconds Duration of the requests to each route.
+# TYPE policy_server_http_request_duration_seconds histogram
+policy_server_http_request_duration_seconds_bucket{route="CreatePolicies",le="0.1"} 1
+policy_server_http_request_duration_seconds_bucket{route="CreatePolicies",le="1"} 1
+policy_server_http_request_duration_seconds_bucket{route="CreatePolicies",le="+Inf"} 2
+policy_server_http_request_duration_seconds_sum{route="CreatePolicies"} 2.05
+policy_server_http_request_duration_seconds_count{route="CreatePolicies"} 2
+`))
+	})
+
+	It("keeps store durations as histograms per operation and result", func() {
+		registry.SendDuration("StoreAllSuccessTime", 500*time.Millisecond)
+		registry.SendDuration("StoreAllErrorTime", 500*time.Millisecond)
+
+		output := scrape()
+		Expect(output).To(ContainSubstring(`policy_server_store_operation_duration_seconds_count{operation="All",result="error"} 1`))
+		Expect(output).To(ContainSubstring(`policy_server_store_operation_duration_seconds_count{operation="All",result="success"} 1`))
+		Expect(output).To(ContainSubstring(`policy_server_store_operation_duration_seconds_bucket{operation="All",result="success",le="1"} 1`))
+	})
+
+	It("keeps other durations and counters by name", func() {
+		registry.SendDuration("asgSyncDuration", time.Second)
+		registry.IncrementCounter("StoreAllError")
+		registry.IncrementCounter("StoreAllError")
+
+		output := scrape()
+		Expect(output).To(ContainSubstring(`policy_server_duration_seconds_count{name="asgSyncDuration"} 1`))
+		Expect(output).To(ContainSubstring("# TYPE policy_server_events_total counter\npolicy_server_events_total{name=\"StoreAllError\"} 2\n"))
+	})
+
+	It("serves the values of its sources as gauges", func() {
+		registry.AddSources(
+			metrics.MetricSource{Name: "totalPolicies", Getter: func() (float64, error) { return 42, nil }},
+			metrics.MetricSource{Name: "DBOpenConnections", Getter: func() (float64, error) { return 3, nil }},
+			metrics.MetricSource{Name: "broken", Getter: func() (float64, error) { return 0, errors.New("banana") }},
+		)
+
+		output := scrape()
+		Expect(output).To(ContainSubstring("# TYPE policy_server_total_policies gauge\npolicy_server_total_policies 42\n"))
+		Expect(output).To(ContainSubstring("policy_server_db_open_connections 3\n"))
+		Expect(output).NotTo(ContainSubstring("broken"))
+	})
+
+	It("escapes label values by the rules of the text format", func() {
+		registry.IncrementCounter("quote\"backslash\\newline\ntab\tünïcode")
+
+		Expect(scrape()).To(ContainSubstring("policy_server_events_total{name=\"quote\\\"backslash\\\\newline\\ntab\tünïcode\"} 1\n"))
+	})
+
+	It("serves the metrics over http", func() {
+		registry.IncrementCounter("some-event")
+
+		resp := httptest.NewRecorder()
+		registry.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(HavePrefix("text/plain; version=0.0.4; charset=utf-8"))
+		Expect(resp.Body.String()).To(ContainSubstring(`policy_server_events_total{name="some-event"} 1`))
+	})
+})
+
+var _ = Describe("MultiSender", func() {
+	It("sends to every sender", func() {
+		first := &fakes.MetricsSender{}
+		second := &fakes.MetricsSender{}
+		sender := server_metrics.MultiSender{first, second}
+
+		sender.SendDuration("some-duration", time.Second)
+		sender.IncrementCounter("some-counter")
+
+		for _, fake := range []*fakes.MetricsSender{first, second} {
+			name, duration := fake.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("some-duration"))
+			Expect(duration).To(Equal(time.Second))
+			Expect(fake.IncrementCounterArgsForCall(0)).To(Equal("some-counter"))
+		}
+	})
+})
@@ -3,6 +3,9 @@ package server_metrics
 //go:generate counterfeiter -generate
 
 import (
+	"database/sql"
+	"time"
+
 	"code.cloudfoundry.org/cf-networking-helpers/metrics"
 	"code.cloudfoundry.org/policy-server/store"
 )
@@ -22,3 +25,35 @@ func NewTotalPoliciesSource(lister listStore) metrics.MetricSource {
 		},
 	}
 }
+
+//counterfeiter:generate -o fakes/metrics_sender.go --fake-name MetricsSender . metricsSender
+type metricsSender interface {
+	SendDuration(string, time.Duration)
+	IncrementCounter(string)
+}
+
+//counterfeiter:generate -o fakes/db_stats.go --fake-name DBStats . dbStats
+type dbStats interface {
+	Stats() sql.DBStats
+}
+
+// NewDBStatsSources returns sources of the connection pool stats. Unlike the
+// DB monitor sources, reading them does not reset any value.
+func NewDBStatsSources(db dbStats) []metrics.MetricSource {
+	stat := func(name string, value func(sql.DBStats) float64) metrics.MetricSource {
+		return metrics.MetricSource{
+			Name: name,
+			Getter: func() (float64, error) {
+				return value(db.Stats()), nil
+			},
+		}
+	}
+	return []metrics.MetricSource{
+		stat("DBMaxOpenConnections", func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }),
+		stat("DBOpenConnections", func(s sql.DBStats) float64 { return float64(s.OpenConnections) }),
+		stat("DBConnectionsInUse", func(s sql.DBStats) float64 { return float64(s.InUse) }),
+		stat("DBConnectionsIdle", func(s sql.DBStats) float64 { return float64(s.Idle) }),
+		stat("DBConnectionsWaitCount", func(s sql.DBStats) float64 { return float64(s.WaitCount) }),
+		stat("DBConnectionsWaitSeconds", func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }),
+	}
+}
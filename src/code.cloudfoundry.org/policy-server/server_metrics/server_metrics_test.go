@@ -1,6 +1,9 @@
 package server_metrics_test
 
 import (
+	"database/sql"
+	"time"
+
 	"code.cloudfoundry.org/policy-server/server_metrics"
 	"code.cloudfoundry.org/policy-server/server_metrics/fakes"
 	"code.cloudfoundry.org/policy-server/store"
@@ -55,3 +58,32 @@ var _ = Describe("NewTotalPoliciesSource", func() {
 		})
 	})
 })
+
+var _ = Describe("NewDBStatsSources", func() {
+	It("returns the connection pool stats", func() {
+		fakeDB := &fakes.DBStats{}
+		fakeDB.StatsReturns(sql.DBStats{
+			MaxOpenConnections: 10,
+			OpenConnections:    4,
+			InUse:              3,
+			Idle:               1,
+			WaitCount:          7,
+			WaitDuration:       2 * time.Second,
+		})
+
+		values := map[string]float64{}
+		for _, source := range server_metrics.NewDBStatsSources(fakeDB) {
+			value, err := source.Getter()
+			Expect(err).NotTo(HaveOccurred())
+			values[source.Name] = value
+		}
+		Expect(values).To(Equal(map[string]float64{
+			"DBMaxOpenConnections":     10,
+			"DBOpenConnections":        4,
+			"DBConnectionsInUse":       3,
+			"DBConnectionsIdle":        1,
+			"DBConnectionsWaitCount":   7,
+			"DBConnectionsWaitSeconds": 2,
+		}))
+	})
+})
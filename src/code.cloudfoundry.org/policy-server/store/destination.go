@@ -4,45 +4,49 @@ import "code.cloudfoundry.org/cf-networking-helpers/db"
 
 //counterfeiter:generate -o fakes/destination_repo.go --fake-name DestinationRepo . DestinationRepo
 type DestinationRepo interface {
-	Create(db.Transaction, int, int, int, int, string) (int, error)
+	Create(db.Transaction, int, int, int, int, string, int, int) (int, error)
 	Delete(db.Transaction, int) error
-	GetID(db.Transaction, int, int, int, int, string) (int, error)
+	GetID(db.Transaction, int, int, int, int, string, int, int) (int, error)
 	CountWhereGroupID(db.Transaction, int) (int, error)
 }
 
 type DestinationTable struct {
 }
 
-func (d *DestinationTable) Create(tx db.Transaction, destinationGroupId, port, startPort, endPort int, protocol string) (int, error) {
+func (d *DestinationTable) Create(tx db.Transaction, destinationGroupId, port, startPort, endPort int, protocol string, icmpType, icmpCode int) (int, error) {
 	dualStatement := ""
 	if tx.DriverName() == "mysql" {
 		dualStatement = " FROM DUAL "
 	}
 
 	_, err := tx.Exec(tx.Rebind(`
-		INSERT INTO destinations (group_id, port, start_port, end_port, protocol)
-		SELECT ?, ?, ?, ?, ? `+dualStatement+`
+		INSERT INTO destinations (group_id, port, start_port, end_port, protocol, icmp_type, icmp_code)
+		SELECT ?, ?, ?, ?, ?, ?, ? `+dualStatement+`
 		WHERE
 		NOT EXISTS (
 			SELECT *
 			FROM destinations
-			WHERE group_id = ? AND port = ? AND start_port = ? AND end_port = ? AND protocol = ?
+			WHERE group_id = ? AND port = ? AND start_port = ? AND end_port = ? AND protocol = ? AND icmp_type = ? AND icmp_code = ?
 		)`),
 		destinationGroupId,
 		port,
 		startPort,
 		endPort,
 		protocol,
+		icmpType,
+		icmpCode,
 		destinationGroupId,
 		port,
 		startPort,
 		endPort,
 		protocol,
+		icmpType,
+		icmpCode,
 	)
 	if err != nil {
 		return -1, err
 	}
-	id, err := d.GetID(tx, destinationGroupId, port, startPort, endPort, protocol)
+	id, err := d.GetID(tx, destinationGroupId, port, startPort, endPort, protocol, icmpType, icmpCode)
 	return id, err
 }
 
@@ -54,7 +58,7 @@ func (d *DestinationTable) Delete(tx db.Transaction, id int) error {
 	return err
 }
 
-func (d *DestinationTable) GetID(tx db.Transaction, destinationGroupId, port, startPort, endPort int, protocol string) (int, error) {
+func (d *DestinationTable) GetID(tx db.Transaction, destinationGroupId, port, startPort, endPort int, protocol string, icmpType, icmpCode int) (int, error) {
 	var id int
 	lockStatement := " FOR UPDATE "
 	if tx.DriverName() == "mysql" {
@@ -62,12 +66,14 @@ func (d *DestinationTable) GetID(tx db.Transaction, destinationGroupId, port, st
 	}
 	err := tx.QueryRow(tx.Rebind(`
 		SELECT id FROM destinations
-		WHERE group_id = ? AND port = ? AND start_port = ? AND end_port = ? AND protocol = ? `+lockStatement),
+		WHERE group_id = ? AND port = ? AND start_port = ? AND end_port = ? AND protocol = ? AND icmp_type = ? AND icmp_code = ? `+lockStatement),
 		destinationGroupId,
 		port,
 		startPort,
 		endPort,
 		protocol,
+		icmpType,
+		icmpCode,
 	).Scan(&id)
 	return id, err
 }
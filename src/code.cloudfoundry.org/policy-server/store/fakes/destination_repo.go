@@ -23,7 +23,7 @@ type DestinationRepo struct {
 		result1 int
 		result2 error
 	}
-	CreateStub        func(db.Transaction, int, int, int, int, string) (int, error)
+	CreateStub        func(db.Transaction, int, int, int, int, string, int, int) (int, error)
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
 		arg1 db.Transaction
@@ -32,6 +32,8 @@ type DestinationRepo struct {
 		arg4 int
 		arg5 int
 		arg6 string
+		arg7 int
+		arg8 int
 	}
 	createReturns struct {
 		result1 int
@@ -53,7 +55,7 @@ type DestinationRepo struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
-	GetIDStub        func(db.Transaction, int, int, int, int, string) (int, error)
+	GetIDStub        func(db.Transaction, int, int, int, int, string, int, int) (int, error)
 	getIDMutex       sync.RWMutex
 	getIDArgsForCall []struct {
 		arg1 db.Transaction
@@ -62,6 +64,8 @@ type DestinationRepo struct {
 		arg4 int
 		arg5 int
 		arg6 string
+		arg7 int
+		arg8 int
 	}
 	getIDReturns struct {
 		result1 int
@@ -140,7 +144,7 @@ func (fake *DestinationRepo) CountWhereGroupIDReturnsOnCall(i int, result1 int,
 	}{result1, result2}
 }
 
-func (fake *DestinationRepo) Create(arg1 db.Transaction, arg2 int, arg3 int, arg4 int, arg5 int, arg6 string) (int, error) {
+func (fake *DestinationRepo) Create(arg1 db.Transaction, arg2 int, arg3 int, arg4 int, arg5 int, arg6 string, arg7 int, arg8 int) (int, error) {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
 	fake.createArgsForCall = append(fake.createArgsForCall, struct {
@@ -150,13 +154,15 @@ func (fake *DestinationRepo) Create(arg1 db.Transaction, arg2 int, arg3 int, arg
 		arg4 int
 		arg5 int
 		arg6 string
-	}{arg1, arg2, arg3, arg4, arg5, arg6})
+		arg7 int
+		arg8 int
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
 	stub := fake.CreateStub
 	fakeReturns := fake.createReturns
-	fake.recordInvocation("Create", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.recordInvocation("Create", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
 	fake.createMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -170,17 +176,17 @@ func (fake *DestinationRepo) CreateCallCount() int {
 	return len(fake.createArgsForCall)
 }
 
-func (fake *DestinationRepo) CreateCalls(stub func(db.Transaction, int, int, int, int, string) (int, error)) {
+func (fake *DestinationRepo) CreateCalls(stub func(db.Transaction, int, int, int, int, string, int, int) (int, error)) {
 	fake.createMutex.Lock()
 	defer fake.createMutex.Unlock()
 	fake.CreateStub = stub
 }
 
-func (fake *DestinationRepo) CreateArgsForCall(i int) (db.Transaction, int, int, int, int, string) {
+func (fake *DestinationRepo) CreateArgsForCall(i int) (db.Transaction, int, int, int, int, string, int, int) {
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
 	argsForCall := fake.createArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
 }
 
 func (fake *DestinationRepo) CreateReturns(result1 int, result2 error) {
@@ -271,7 +277,7 @@ func (fake *DestinationRepo) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *DestinationRepo) GetID(arg1 db.Transaction, arg2 int, arg3 int, arg4 int, arg5 int, arg6 string) (int, error) {
+func (fake *DestinationRepo) GetID(arg1 db.Transaction, arg2 int, arg3 int, arg4 int, arg5 int, arg6 string, arg7 int, arg8 int) (int, error) {
 	fake.getIDMutex.Lock()
 	ret, specificReturn := fake.getIDReturnsOnCall[len(fake.getIDArgsForCall)]
 	fake.getIDArgsForCall = append(fake.getIDArgsForCall, struct {
@@ -281,13 +287,15 @@ func (fake *DestinationRepo) GetID(arg1 db.Transaction, arg2 int, arg3 int, arg4
 		arg4 int
 		arg5 int
 		arg6 string
-	}{arg1, arg2, arg3, arg4, arg5, arg6})
+		arg7 int
+		arg8 int
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
 	stub := fake.GetIDStub
 	fakeReturns := fake.getIDReturns
-	fake.recordInvocation("GetID", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.recordInvocation("GetID", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
 	fake.getIDMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -301,17 +309,17 @@ func (fake *DestinationRepo) GetIDCallCount() int {
 	return len(fake.getIDArgsForCall)
 }
 
-func (fake *DestinationRepo) GetIDCalls(stub func(db.Transaction, int, int, int, int, string) (int, error)) {
+func (fake *DestinationRepo) GetIDCalls(stub func(db.Transaction, int, int, int, int, string, int, int) (int, error)) {
 	fake.getIDMutex.Lock()
 	defer fake.getIDMutex.Unlock()
 	fake.GetIDStub = stub
 }
 
-func (fake *DestinationRepo) GetIDArgsForCall(i int) (db.Transaction, int, int, int, int, string) {
+func (fake *DestinationRepo) GetIDArgsForCall(i int) (db.Transaction, int, int, int, int, string, int, int) {
 	fake.getIDMutex.RLock()
 	defer fake.getIDMutex.RUnlock()
 	argsForCall := fake.getIDArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
 }
 
 func (fake *DestinationRepo) GetIDReturns(result1 int, result2 error) {
@@ -9,6 +9,20 @@ import (
 )
 
 type GroupRepo struct {
+	CountWhereMemberIDStub        func(db.Transaction, int) (int, error)
+	countWhereMemberIDMutex       sync.RWMutex
+	countWhereMemberIDArgsForCall []struct {
+		arg1 db.Transaction
+		arg2 int
+	}
+	countWhereMemberIDReturns struct {
+		result1 int
+		result2 error
+	}
+	countWhereMemberIDReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
 	CreateStub        func(db.Transaction, string, string) (int, error)
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
@@ -36,6 +50,20 @@ type GroupRepo struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteMembersStub        func(db.Transaction, int) ([]int, error)
+	deleteMembersMutex       sync.RWMutex
+	deleteMembersArgsForCall []struct {
+		arg1 db.Transaction
+		arg2 int
+	}
+	deleteMembersReturns struct {
+		result1 []int
+		result2 error
+	}
+	deleteMembersReturnsOnCall map[int]struct {
+		result1 []int
+		result2 error
+	}
 	GetIDStub        func(db.Transaction, string) (int, error)
 	getIDMutex       sync.RWMutex
 	getIDArgsForCall []struct {
@@ -50,10 +78,90 @@ type GroupRepo struct {
 		result1 int
 		result2 error
 	}
+	SetQuarantinedStub        func(db.Transaction, int, bool) (bool, error)
+	setQuarantinedMutex       sync.RWMutex
+	setQuarantinedArgsForCall []struct {
+		arg1 db.Transaction
+		arg2 int
+		arg3 bool
+	}
+	setQuarantinedReturns struct {
+		result1 bool
+		result2 error
+	}
+	setQuarantinedReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *GroupRepo) CountWhereMemberID(arg1 db.Transaction, arg2 int) (int, error) {
+	fake.countWhereMemberIDMutex.Lock()
+	ret, specificReturn := fake.countWhereMemberIDReturnsOnCall[len(fake.countWhereMemberIDArgsForCall)]
+	fake.countWhereMemberIDArgsForCall = append(fake.countWhereMemberIDArgsForCall, struct {
+		arg1 db.Transaction
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.CountWhereMemberIDStub
+	fakeReturns := fake.countWhereMemberIDReturns
+	fake.recordInvocation("CountWhereMemberID", []interface{}{arg1, arg2})
+	fake.countWhereMemberIDMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *GroupRepo) CountWhereMemberIDCallCount() int {
+	fake.countWhereMemberIDMutex.RLock()
+	defer fake.countWhereMemberIDMutex.RUnlock()
+	return len(fake.countWhereMemberIDArgsForCall)
+}
+
+func (fake *GroupRepo) CountWhereMemberIDCalls(stub func(db.Transaction, int) (int, error)) {
+	fake.countWhereMemberIDMutex.Lock()
+	defer fake.countWhereMemberIDMutex.Unlock()
+	fake.CountWhereMemberIDStub = stub
+}
+
+func (fake *GroupRepo) CountWhereMemberIDArgsForCall(i int) (db.Transaction, int) {
+	fake.countWhereMemberIDMutex.RLock()
+	defer fake.countWhereMemberIDMutex.RUnlock()
+	argsForCall := fake.countWhereMemberIDArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *GroupRepo) CountWhereMemberIDReturns(result1 int, result2 error) {
+	fake.countWhereMemberIDMutex.Lock()
+	defer fake.countWhereMemberIDMutex.Unlock()
+	fake.CountWhereMemberIDStub = nil
+	fake.countWhereMemberIDReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *GroupRepo) CountWhereMemberIDReturnsOnCall(i int, result1 int, result2 error) {
+	fake.countWhereMemberIDMutex.Lock()
+	defer fake.countWhereMemberIDMutex.Unlock()
+	fake.CountWhereMemberIDStub = nil
+	if fake.countWhereMemberIDReturnsOnCall == nil {
+		fake.countWhereMemberIDReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.countWhereMemberIDReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *GroupRepo) Create(arg1 db.Transaction, arg2 string, arg3 string) (int, error) {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
@@ -182,6 +290,71 @@ func (fake *GroupRepo) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *GroupRepo) DeleteMembers(arg1 db.Transaction, arg2 int) ([]int, error) {
+	fake.deleteMembersMutex.Lock()
+	ret, specificReturn := fake.deleteMembersReturnsOnCall[len(fake.deleteMembersArgsForCall)]
+	fake.deleteMembersArgsForCall = append(fake.deleteMembersArgsForCall, struct {
+		arg1 db.Transaction
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.DeleteMembersStub
+	fakeReturns := fake.deleteMembersReturns
+	fake.recordInvocation("DeleteMembers", []interface{}{arg1, arg2})
+	fake.deleteMembersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *GroupRepo) DeleteMembersCallCount() int {
+	fake.deleteMembersMutex.RLock()
+	defer fake.deleteMembersMutex.RUnlock()
+	return len(fake.deleteMembersArgsForCall)
+}
+
+func (fake *GroupRepo) DeleteMembersCalls(stub func(db.Transaction, int) ([]int, error)) {
+	fake.deleteMembersMutex.Lock()
+	defer fake.deleteMembersMutex.Unlock()
+	fake.DeleteMembersStub = stub
+}
+
+func (fake *GroupRepo) DeleteMembersArgsForCall(i int) (db.Transaction, int) {
+	fake.deleteMembersMutex.RLock()
+	defer fake.deleteMembersMutex.RUnlock()
+	argsForCall := fake.deleteMembersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *GroupRepo) DeleteMembersReturns(result1 []int, result2 error) {
+	fake.deleteMembersMutex.Lock()
+	defer fake.deleteMembersMutex.Unlock()
+	fake.DeleteMembersStub = nil
+	fake.deleteMembersReturns = struct {
+		result1 []int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *GroupRepo) DeleteMembersReturnsOnCall(i int, result1 []int, result2 error) {
+	fake.deleteMembersMutex.Lock()
+	defer fake.deleteMembersMutex.Unlock()
+	fake.DeleteMembersStub = nil
+	if fake.deleteMembersReturnsOnCall == nil {
+		fake.deleteMembersReturnsOnCall = make(map[int]struct {
+			result1 []int
+			result2 error
+		})
+	}
+	fake.deleteMembersReturnsOnCall[i] = struct {
+		result1 []int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *GroupRepo) GetID(arg1 db.Transaction, arg2 string) (int, error) {
 	fake.getIDMutex.Lock()
 	ret, specificReturn := fake.getIDReturnsOnCall[len(fake.getIDArgsForCall)]
@@ -247,15 +420,87 @@ func (fake *GroupRepo) GetIDReturnsOnCall(i int, result1 int, result2 error) {
 	}{result1, result2}
 }
 
+func (fake *GroupRepo) SetQuarantined(arg1 db.Transaction, arg2 int, arg3 bool) (bool, error) {
+	fake.setQuarantinedMutex.Lock()
+	ret, specificReturn := fake.setQuarantinedReturnsOnCall[len(fake.setQuarantinedArgsForCall)]
+	fake.setQuarantinedArgsForCall = append(fake.setQuarantinedArgsForCall, struct {
+		arg1 db.Transaction
+		arg2 int
+		arg3 bool
+	}{arg1, arg2, arg3})
+	stub := fake.SetQuarantinedStub
+	fakeReturns := fake.setQuarantinedReturns
+	fake.recordInvocation("SetQuarantined", []interface{}{arg1, arg2, arg3})
+	fake.setQuarantinedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *GroupRepo) SetQuarantinedCallCount() int {
+	fake.setQuarantinedMutex.RLock()
+	defer fake.setQuarantinedMutex.RUnlock()
+	return len(fake.setQuarantinedArgsForCall)
+}
+
+func (fake *GroupRepo) SetQuarantinedCalls(stub func(db.Transaction, int, bool) (bool, error)) {
+	fake.setQuarantinedMutex.Lock()
+	defer fake.setQuarantinedMutex.Unlock()
+	fake.SetQuarantinedStub = stub
+}
+
+func (fake *GroupRepo) SetQuarantinedArgsForCall(i int) (db.Transaction, int, bool) {
+	fake.setQuarantinedMutex.RLock()
+	defer fake.setQuarantinedMutex.RUnlock()
+	argsForCall := fake.setQuarantinedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *GroupRepo) SetQuarantinedReturns(result1 bool, result2 error) {
+	fake.setQuarantinedMutex.Lock()
+	defer fake.setQuarantinedMutex.Unlock()
+	fake.SetQuarantinedStub = nil
+	fake.setQuarantinedReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *GroupRepo) SetQuarantinedReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.setQuarantinedMutex.Lock()
+	defer fake.setQuarantinedMutex.Unlock()
+	fake.SetQuarantinedStub = nil
+	if fake.setQuarantinedReturnsOnCall == nil {
+		fake.setQuarantinedReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.setQuarantinedReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *GroupRepo) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.countWhereMemberIDMutex.RLock()
+	defer fake.countWhereMemberIDMutex.RUnlock()
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
+	fake.deleteMembersMutex.RLock()
+	defer fake.deleteMembersMutex.RUnlock()
 	fake.getIDMutex.RLock()
 	defer fake.getIDMutex.RUnlock()
+	fake.setQuarantinedMutex.RLock()
+	defer fake.setQuarantinedMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,341 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type PendingPoliciesStore struct {
+	CreatePendingStub        func([]store.PendingPolicy, store.Actor) error
+	createPendingMutex       sync.RWMutex
+	createPendingArgsForCall []struct {
+		arg1 []store.PendingPolicy
+		arg2 store.Actor
+	}
+	createPendingReturns struct {
+		result1 error
+	}
+	createPendingReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeletePendingStub        func(int) error
+	deletePendingMutex       sync.RWMutex
+	deletePendingArgsForCall []struct {
+		arg1 int
+	}
+	deletePendingReturns struct {
+		result1 error
+	}
+	deletePendingReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PendingPoliciesStub        func() ([]store.PendingPolicy, error)
+	pendingPoliciesMutex       sync.RWMutex
+	pendingPoliciesArgsForCall []struct {
+	}
+	pendingPoliciesReturns struct {
+		result1 []store.PendingPolicy
+		result2 error
+	}
+	pendingPoliciesReturnsOnCall map[int]struct {
+		result1 []store.PendingPolicy
+		result2 error
+	}
+	PendingPolicyStub        func(int) (*store.PendingPolicy, error)
+	pendingPolicyMutex       sync.RWMutex
+	pendingPolicyArgsForCall []struct {
+		arg1 int
+	}
+	pendingPolicyReturns struct {
+		result1 *store.PendingPolicy
+		result2 error
+	}
+	pendingPolicyReturnsOnCall map[int]struct {
+		result1 *store.PendingPolicy
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PendingPoliciesStore) CreatePending(arg1 []store.PendingPolicy, arg2 store.Actor) error {
+	var arg1Copy []store.PendingPolicy
+	if arg1 != nil {
+		arg1Copy = make([]store.PendingPolicy, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.createPendingMutex.Lock()
+	ret, specificReturn := fake.createPendingReturnsOnCall[len(fake.createPendingArgsForCall)]
+	fake.createPendingArgsForCall = append(fake.createPendingArgsForCall, struct {
+		arg1 []store.PendingPolicy
+		arg2 store.Actor
+	}{arg1Copy, arg2})
+	stub := fake.CreatePendingStub
+	fakeReturns := fake.createPendingReturns
+	fake.recordInvocation("CreatePending", []interface{}{arg1Copy, arg2})
+	fake.createPendingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *PendingPoliciesStore) CreatePendingCallCount() int {
+	fake.createPendingMutex.RLock()
+	defer fake.createPendingMutex.RUnlock()
+	return len(fake.createPendingArgsForCall)
+}
+
+func (fake *PendingPoliciesStore) CreatePendingCalls(stub func([]store.PendingPolicy, store.Actor) error) {
+	fake.createPendingMutex.Lock()
+	defer fake.createPendingMutex.Unlock()
+	fake.CreatePendingStub = stub
+}
+
+func (fake *PendingPoliciesStore) CreatePendingArgsForCall(i int) ([]store.PendingPolicy, store.Actor) {
+	fake.createPendingMutex.RLock()
+	defer fake.createPendingMutex.RUnlock()
+	argsForCall := fake.createPendingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PendingPoliciesStore) CreatePendingReturns(result1 error) {
+	fake.createPendingMutex.Lock()
+	defer fake.createPendingMutex.Unlock()
+	fake.CreatePendingStub = nil
+	fake.createPendingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PendingPoliciesStore) CreatePendingReturnsOnCall(i int, result1 error) {
+	fake.createPendingMutex.Lock()
+	defer fake.createPendingMutex.Unlock()
+	fake.CreatePendingStub = nil
+	if fake.createPendingReturnsOnCall == nil {
+		fake.createPendingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createPendingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PendingPoliciesStore) DeletePending(arg1 int) error {
+	fake.deletePendingMutex.Lock()
+	ret, specificReturn := fake.deletePendingReturnsOnCall[len(fake.deletePendingArgsForCall)]
+	fake.deletePendingArgsForCall = append(fake.deletePendingArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.DeletePendingStub
+	fakeReturns := fake.deletePendingReturns
+	fake.recordInvocation("DeletePending", []interface{}{arg1})
+	fake.deletePendingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *PendingPoliciesStore) DeletePendingCallCount() int {
+	fake.deletePendingMutex.RLock()
+	defer fake.deletePendingMutex.RUnlock()
+	return len(fake.deletePendingArgsForCall)
+}
+
+func (fake *PendingPoliciesStore) DeletePendingCalls(stub func(int) error) {
+	fake.deletePendingMutex.Lock()
+	defer fake.deletePendingMutex.Unlock()
+	fake.DeletePendingStub = stub
+}
+
+func (fake *PendingPoliciesStore) DeletePendingArgsForCall(i int) int {
+	fake.deletePendingMutex.RLock()
+	defer fake.deletePendingMutex.RUnlock()
+	argsForCall := fake.deletePendingArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PendingPoliciesStore) DeletePendingReturns(result1 error) {
+	fake.deletePendingMutex.Lock()
+	defer fake.deletePendingMutex.Unlock()
+	fake.DeletePendingStub = nil
+	fake.deletePendingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PendingPoliciesStore) DeletePendingReturnsOnCall(i int, result1 error) {
+	fake.deletePendingMutex.Lock()
+	defer fake.deletePendingMutex.Unlock()
+	fake.DeletePendingStub = nil
+	if fake.deletePendingReturnsOnCall == nil {
+		fake.deletePendingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deletePendingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PendingPoliciesStore) PendingPolicies() ([]store.PendingPolicy, error) {
+	fake.pendingPoliciesMutex.Lock()
+	ret, specificReturn := fake.pendingPoliciesReturnsOnCall[len(fake.pendingPoliciesArgsForCall)]
+	fake.pendingPoliciesArgsForCall = append(fake.pendingPoliciesArgsForCall, struct {
+	}{})
+	stub := fake.PendingPoliciesStub
+	fakeReturns := fake.pendingPoliciesReturns
+	fake.recordInvocation("PendingPolicies", []interface{}{})
+	fake.pendingPoliciesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PendingPoliciesStore) PendingPoliciesCallCount() int {
+	fake.pendingPoliciesMutex.RLock()
+	defer fake.pendingPoliciesMutex.RUnlock()
+	return len(fake.pendingPoliciesArgsForCall)
+}
+
+func (fake *PendingPoliciesStore) PendingPoliciesCalls(stub func() ([]store.PendingPolicy, error)) {
+	fake.pendingPoliciesMutex.Lock()
+	defer fake.pendingPoliciesMutex.Unlock()
+	fake.PendingPoliciesStub = stub
+}
+
+func (fake *PendingPoliciesStore) PendingPoliciesReturns(result1 []store.PendingPolicy, result2 error) {
+	fake.pendingPoliciesMutex.Lock()
+	defer fake.pendingPoliciesMutex.Unlock()
+	fake.PendingPoliciesStub = nil
+	fake.pendingPoliciesReturns = struct {
+		result1 []store.PendingPolicy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PendingPoliciesStore) PendingPoliciesReturnsOnCall(i int, result1 []store.PendingPolicy, result2 error) {
+	fake.pendingPoliciesMutex.Lock()
+	defer fake.pendingPoliciesMutex.Unlock()
+	fake.PendingPoliciesStub = nil
+	if fake.pendingPoliciesReturnsOnCall == nil {
+		fake.pendingPoliciesReturnsOnCall = make(map[int]struct {
+			result1 []store.PendingPolicy
+			result2 error
+		})
+	}
+	fake.pendingPoliciesReturnsOnCall[i] = struct {
+		result1 []store.PendingPolicy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PendingPoliciesStore) PendingPolicy(arg1 int) (*store.PendingPolicy, error) {
+	fake.pendingPolicyMutex.Lock()
+	ret, specificReturn := fake.pendingPolicyReturnsOnCall[len(fake.pendingPolicyArgsForCall)]
+	fake.pendingPolicyArgsForCall = append(fake.pendingPolicyArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.PendingPolicyStub
+	fakeReturns := fake.pendingPolicyReturns
+	fake.recordInvocation("PendingPolicy", []interface{}{arg1})
+	fake.pendingPolicyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PendingPoliciesStore) PendingPolicyCallCount() int {
+	fake.pendingPolicyMutex.RLock()
+	defer fake.pendingPolicyMutex.RUnlock()
+	return len(fake.pendingPolicyArgsForCall)
+}
+
+func (fake *PendingPoliciesStore) PendingPolicyCalls(stub func(int) (*store.PendingPolicy, error)) {
+	fake.pendingPolicyMutex.Lock()
+	defer fake.pendingPolicyMutex.Unlock()
+	fake.PendingPolicyStub = stub
+}
+
+func (fake *PendingPoliciesStore) PendingPolicyArgsForCall(i int) int {
+	fake.pendingPolicyMutex.RLock()
+	defer fake.pendingPolicyMutex.RUnlock()
+	argsForCall := fake.pendingPolicyArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PendingPoliciesStore) PendingPolicyReturns(result1 *store.PendingPolicy, result2 error) {
+	fake.pendingPolicyMutex.Lock()
+	defer fake.pendingPolicyMutex.Unlock()
+	fake.PendingPolicyStub = nil
+	fake.pendingPolicyReturns = struct {
+		result1 *store.PendingPolicy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PendingPoliciesStore) PendingPolicyReturnsOnCall(i int, result1 *store.PendingPolicy, result2 error) {
+	fake.pendingPolicyMutex.Lock()
+	defer fake.pendingPolicyMutex.Unlock()
+	fake.PendingPolicyStub = nil
+	if fake.pendingPolicyReturnsOnCall == nil {
+		fake.pendingPolicyReturnsOnCall = make(map[int]struct {
+			result1 *store.PendingPolicy
+			result2 error
+		})
+	}
+	fake.pendingPolicyReturnsOnCall[i] = struct {
+		result1 *store.PendingPolicy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PendingPoliciesStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createPendingMutex.RLock()
+	defer fake.createPendingMutex.RUnlock()
+	fake.deletePendingMutex.RLock()
+	defer fake.deletePendingMutex.RUnlock()
+	fake.pendingPoliciesMutex.RLock()
+	defer fake.pendingPoliciesMutex.RUnlock()
+	fake.pendingPolicyMutex.RLock()
+	defer fake.pendingPolicyMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PendingPoliciesStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ store.PendingPoliciesStore = new(PendingPoliciesStore)
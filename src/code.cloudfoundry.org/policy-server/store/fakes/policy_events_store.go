@@ -0,0 +1,203 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type PolicyEventsStore struct {
+	DeleteEventsBeforeStub        func(time.Time) (int, error)
+	deleteEventsBeforeMutex       sync.RWMutex
+	deleteEventsBeforeArgsForCall []struct {
+		arg1 time.Time
+	}
+	deleteEventsBeforeReturns struct {
+		result1 int
+		result2 error
+	}
+	deleteEventsBeforeReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	EventsStub        func(store.PolicyEventsFilter, store.Page) ([]store.PolicyEvent, store.Pagination, error)
+	eventsMutex       sync.RWMutex
+	eventsArgsForCall []struct {
+		arg1 store.PolicyEventsFilter
+		arg2 store.Page
+	}
+	eventsReturns struct {
+		result1 []store.PolicyEvent
+		result2 store.Pagination
+		result3 error
+	}
+	eventsReturnsOnCall map[int]struct {
+		result1 []store.PolicyEvent
+		result2 store.Pagination
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PolicyEventsStore) DeleteEventsBefore(arg1 time.Time) (int, error) {
+	fake.deleteEventsBeforeMutex.Lock()
+	ret, specificReturn := fake.deleteEventsBeforeReturnsOnCall[len(fake.deleteEventsBeforeArgsForCall)]
+	fake.deleteEventsBeforeArgsForCall = append(fake.deleteEventsBeforeArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	stub := fake.DeleteEventsBeforeStub
+	fakeReturns := fake.deleteEventsBeforeReturns
+	fake.recordInvocation("DeleteEventsBefore", []interface{}{arg1})
+	fake.deleteEventsBeforeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PolicyEventsStore) DeleteEventsBeforeCallCount() int {
+	fake.deleteEventsBeforeMutex.RLock()
+	defer fake.deleteEventsBeforeMutex.RUnlock()
+	return len(fake.deleteEventsBeforeArgsForCall)
+}
+
+func (fake *PolicyEventsStore) DeleteEventsBeforeCalls(stub func(time.Time) (int, error)) {
+	fake.deleteEventsBeforeMutex.Lock()
+	defer fake.deleteEventsBeforeMutex.Unlock()
+	fake.DeleteEventsBeforeStub = stub
+}
+
+func (fake *PolicyEventsStore) DeleteEventsBeforeArgsForCall(i int) time.Time {
+	fake.deleteEventsBeforeMutex.RLock()
+	defer fake.deleteEventsBeforeMutex.RUnlock()
+	argsForCall := fake.deleteEventsBeforeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PolicyEventsStore) DeleteEventsBeforeReturns(result1 int, result2 error) {
+	fake.deleteEventsBeforeMutex.Lock()
+	defer fake.deleteEventsBeforeMutex.Unlock()
+	fake.DeleteEventsBeforeStub = nil
+	fake.deleteEventsBeforeReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyEventsStore) DeleteEventsBeforeReturnsOnCall(i int, result1 int, result2 error) {
+	fake.deleteEventsBeforeMutex.Lock()
+	defer fake.deleteEventsBeforeMutex.Unlock()
+	fake.DeleteEventsBeforeStub = nil
+	if fake.deleteEventsBeforeReturnsOnCall == nil {
+		fake.deleteEventsBeforeReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.deleteEventsBeforeReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyEventsStore) Events(arg1 store.PolicyEventsFilter, arg2 store.Page) ([]store.PolicyEvent, store.Pagination, error) {
+	fake.eventsMutex.Lock()
+	ret, specificReturn := fake.eventsReturnsOnCall[len(fake.eventsArgsForCall)]
+	fake.eventsArgsForCall = append(fake.eventsArgsForCall, struct {
+		arg1 store.PolicyEventsFilter
+		arg2 store.Page
+	}{arg1, arg2})
+	stub := fake.EventsStub
+	fakeReturns := fake.eventsReturns
+	fake.recordInvocation("Events", []interface{}{arg1, arg2})
+	fake.eventsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *PolicyEventsStore) EventsCallCount() int {
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	return len(fake.eventsArgsForCall)
+}
+
+func (fake *PolicyEventsStore) EventsCalls(stub func(store.PolicyEventsFilter, store.Page) ([]store.PolicyEvent, store.Pagination, error)) {
+	fake.eventsMutex.Lock()
+	defer fake.eventsMutex.Unlock()
+	fake.EventsStub = stub
+}
+
+func (fake *PolicyEventsStore) EventsArgsForCall(i int) (store.PolicyEventsFilter, store.Page) {
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	argsForCall := fake.eventsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PolicyEventsStore) EventsReturns(result1 []store.PolicyEvent, result2 store.Pagination, result3 error) {
+	fake.eventsMutex.Lock()
+	defer fake.eventsMutex.Unlock()
+	fake.EventsStub = nil
+	fake.eventsReturns = struct {
+		result1 []store.PolicyEvent
+		result2 store.Pagination
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *PolicyEventsStore) EventsReturnsOnCall(i int, result1 []store.PolicyEvent, result2 store.Pagination, result3 error) {
+	fake.eventsMutex.Lock()
+	defer fake.eventsMutex.Unlock()
+	fake.EventsStub = nil
+	if fake.eventsReturnsOnCall == nil {
+		fake.eventsReturnsOnCall = make(map[int]struct {
+			result1 []store.PolicyEvent
+			result2 store.Pagination
+			result3 error
+		})
+	}
+	fake.eventsReturnsOnCall[i] = struct {
+		result1 []store.PolicyEvent
+		result2 store.Pagination
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *PolicyEventsStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.deleteEventsBeforeMutex.RLock()
+	defer fake.deleteEventsBeforeMutex.RUnlock()
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PolicyEventsStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ store.PolicyEventsStore = new(PolicyEventsStore)
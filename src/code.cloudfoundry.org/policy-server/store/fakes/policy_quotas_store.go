@@ -0,0 +1,257 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/policy-server/store"
+)
+
+type PolicyQuotasStore struct {
+	DeleteQuotaStub        func(string, string) error
+	deleteQuotaMutex       sync.RWMutex
+	deleteQuotaArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	deleteQuotaReturns struct {
+		result1 error
+	}
+	deleteQuotaReturnsOnCall map[int]struct {
+		result1 error
+	}
+	QuotasStub        func() ([]store.Quota, error)
+	quotasMutex       sync.RWMutex
+	quotasArgsForCall []struct {
+	}
+	quotasReturns struct {
+		result1 []store.Quota
+		result2 error
+	}
+	quotasReturnsOnCall map[int]struct {
+		result1 []store.Quota
+		result2 error
+	}
+	SetQuotaStub        func(store.Quota) error
+	setQuotaMutex       sync.RWMutex
+	setQuotaArgsForCall []struct {
+		arg1 store.Quota
+	}
+	setQuotaReturns struct {
+		result1 error
+	}
+	setQuotaReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PolicyQuotasStore) DeleteQuota(arg1 string, arg2 string) error {
+	fake.deleteQuotaMutex.Lock()
+	ret, specificReturn := fake.deleteQuotaReturnsOnCall[len(fake.deleteQuotaArgsForCall)]
+	fake.deleteQuotaArgsForCall = append(fake.deleteQuotaArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DeleteQuotaStub
+	fakeReturns := fake.deleteQuotaReturns
+	fake.recordInvocation("DeleteQuota", []interface{}{arg1, arg2})
+	fake.deleteQuotaMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *PolicyQuotasStore) DeleteQuotaCallCount() int {
+	fake.deleteQuotaMutex.RLock()
+	defer fake.deleteQuotaMutex.RUnlock()
+	return len(fake.deleteQuotaArgsForCall)
+}
+
+func (fake *PolicyQuotasStore) DeleteQuotaCalls(stub func(string, string) error) {
+	fake.deleteQuotaMutex.Lock()
+	defer fake.deleteQuotaMutex.Unlock()
+	fake.DeleteQuotaStub = stub
+}
+
+func (fake *PolicyQuotasStore) DeleteQuotaArgsForCall(i int) (string, string) {
+	fake.deleteQuotaMutex.RLock()
+	defer fake.deleteQuotaMutex.RUnlock()
+	argsForCall := fake.deleteQuotaArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PolicyQuotasStore) DeleteQuotaReturns(result1 error) {
+	fake.deleteQuotaMutex.Lock()
+	defer fake.deleteQuotaMutex.Unlock()
+	fake.DeleteQuotaStub = nil
+	fake.deleteQuotaReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PolicyQuotasStore) DeleteQuotaReturnsOnCall(i int, result1 error) {
+	fake.deleteQuotaMutex.Lock()
+	defer fake.deleteQuotaMutex.Unlock()
+	fake.DeleteQuotaStub = nil
+	if fake.deleteQuotaReturnsOnCall == nil {
+		fake.deleteQuotaReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteQuotaReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PolicyQuotasStore) Quotas() ([]store.Quota, error) {
+	fake.quotasMutex.Lock()
+	ret, specificReturn := fake.quotasReturnsOnCall[len(fake.quotasArgsForCall)]
+	fake.quotasArgsForCall = append(fake.quotasArgsForCall, struct {
+	}{})
+	stub := fake.QuotasStub
+	fakeReturns := fake.quotasReturns
+	fake.recordInvocation("Quotas", []interface{}{})
+	fake.quotasMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PolicyQuotasStore) QuotasCallCount() int {
+	fake.quotasMutex.RLock()
+	defer fake.quotasMutex.RUnlock()
+	return len(fake.quotasArgsForCall)
+}
+
+func (fake *PolicyQuotasStore) QuotasCalls(stub func() ([]store.Quota, error)) {
+	fake.quotasMutex.Lock()
+	defer fake.quotasMutex.Unlock()
+	fake.QuotasStub = stub
+}
+
+func (fake *PolicyQuotasStore) QuotasReturns(result1 []store.Quota, result2 error) {
+	fake.quotasMutex.Lock()
+	defer fake.quotasMutex.Unlock()
+	fake.QuotasStub = nil
+	fake.quotasReturns = struct {
+		result1 []store.Quota
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyQuotasStore) QuotasReturnsOnCall(i int, result1 []store.Quota, result2 error) {
+	fake.quotasMutex.Lock()
+	defer fake.quotasMutex.Unlock()
+	fake.QuotasStub = nil
+	if fake.quotasReturnsOnCall == nil {
+		fake.quotasReturnsOnCall = make(map[int]struct {
+			result1 []store.Quota
+			result2 error
+		})
+	}
+	fake.quotasReturnsOnCall[i] = struct {
+		result1 []store.Quota
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyQuotasStore) SetQuota(arg1 store.Quota) error {
+	fake.setQuotaMutex.Lock()
+	ret, specificReturn := fake.setQuotaReturnsOnCall[len(fake.setQuotaArgsForCall)]
+	fake.setQuotaArgsForCall = append(fake.setQuotaArgsForCall, struct {
+		arg1 store.Quota
+	}{arg1})
+	stub := fake.SetQuotaStub
+	fakeReturns := fake.setQuotaReturns
+	fake.recordInvocation("SetQuota", []interface{}{arg1})
+	fake.setQuotaMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *PolicyQuotasStore) SetQuotaCallCount() int {
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	return len(fake.setQuotaArgsForCall)
+}
+
+func (fake *PolicyQuotasStore) SetQuotaCalls(stub func(store.Quota) error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = stub
+}
+
+func (fake *PolicyQuotasStore) SetQuotaArgsForCall(i int) store.Quota {
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	argsForCall := fake.setQuotaArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PolicyQuotasStore) SetQuotaReturns(result1 error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = nil
+	fake.setQuotaReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PolicyQuotasStore) SetQuotaReturnsOnCall(i int, result1 error) {
+	fake.setQuotaMutex.Lock()
+	defer fake.setQuotaMutex.Unlock()
+	fake.SetQuotaStub = nil
+	if fake.setQuotaReturnsOnCall == nil {
+		fake.setQuotaReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setQuotaReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PolicyQuotasStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.deleteQuotaMutex.RLock()
+	defer fake.deleteQuotaMutex.RUnlock()
+	fake.quotasMutex.RLock()
+	defer fake.quotasMutex.RUnlock()
+	fake.setQuotaMutex.RLock()
+	defer fake.setQuotaMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PolicyQuotasStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ store.PolicyQuotasStore = new(PolicyQuotasStore)
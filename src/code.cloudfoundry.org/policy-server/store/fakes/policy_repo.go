@@ -9,6 +9,21 @@ import (
 )
 
 type PolicyRepo struct {
+	ActionStub        func(db.Transaction, int, int) (string, error)
+	actionMutex       sync.RWMutex
+	actionArgsForCall []struct {
+		arg1 db.Transaction
+		arg2 int
+		arg3 int
+	}
+	actionReturns struct {
+		result1 string
+		result2 error
+	}
+	actionReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	CountWhereDestinationIDStub        func(db.Transaction, int) (int, error)
 	countWhereDestinationIDMutex       sync.RWMutex
 	countWhereDestinationIDArgsForCall []struct {
@@ -37,7 +52,7 @@ type PolicyRepo struct {
 		result1 int
 		result2 error
 	}
-	CreateStub        func(db.Transaction, int, int) error
+	CreateStub        func(db.Transaction, int, int) (bool, error)
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
 		arg1 db.Transaction
@@ -45,10 +60,12 @@ type PolicyRepo struct {
 		arg3 int
 	}
 	createReturns struct {
-		result1 error
+		result1 bool
+		result2 error
 	}
 	createReturnsOnCall map[int]struct {
-		result1 error
+		result1 bool
+		result2 error
 	}
 	DeleteStub        func(db.Transaction, int, int) error
 	deleteMutex       sync.RWMutex
@@ -67,6 +84,72 @@ type PolicyRepo struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *PolicyRepo) Action(arg1 db.Transaction, arg2 int, arg3 int) (string, error) {
+	fake.actionMutex.Lock()
+	ret, specificReturn := fake.actionReturnsOnCall[len(fake.actionArgsForCall)]
+	fake.actionArgsForCall = append(fake.actionArgsForCall, struct {
+		arg1 db.Transaction
+		arg2 int
+		arg3 int
+	}{arg1, arg2, arg3})
+	stub := fake.ActionStub
+	fakeReturns := fake.actionReturns
+	fake.recordInvocation("Action", []interface{}{arg1, arg2, arg3})
+	fake.actionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PolicyRepo) ActionCallCount() int {
+	fake.actionMutex.RLock()
+	defer fake.actionMutex.RUnlock()
+	return len(fake.actionArgsForCall)
+}
+
+func (fake *PolicyRepo) ActionCalls(stub func(db.Transaction, int, int) (string, error)) {
+	fake.actionMutex.Lock()
+	defer fake.actionMutex.Unlock()
+	fake.ActionStub = stub
+}
+
+func (fake *PolicyRepo) ActionArgsForCall(i int) (db.Transaction, int, int) {
+	fake.actionMutex.RLock()
+	defer fake.actionMutex.RUnlock()
+	argsForCall := fake.actionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *PolicyRepo) ActionReturns(result1 string, result2 error) {
+	fake.actionMutex.Lock()
+	defer fake.actionMutex.Unlock()
+	fake.ActionStub = nil
+	fake.actionReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PolicyRepo) ActionReturnsOnCall(i int, result1 string, result2 error) {
+	fake.actionMutex.Lock()
+	defer fake.actionMutex.Unlock()
+	fake.ActionStub = nil
+	if fake.actionReturnsOnCall == nil {
+		fake.actionReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.actionReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *PolicyRepo) CountWhereDestinationID(arg1 db.Transaction, arg2 int) (int, error) {
 	fake.countWhereDestinationIDMutex.Lock()
 	ret, specificReturn := fake.countWhereDestinationIDReturnsOnCall[len(fake.countWhereDestinationIDArgsForCall)]
@@ -197,7 +280,7 @@ func (fake *PolicyRepo) CountWhereGroupIDReturnsOnCall(i int, result1 int, resul
 	}{result1, result2}
 }
 
-func (fake *PolicyRepo) Create(arg1 db.Transaction, arg2 int, arg3 int) error {
+func (fake *PolicyRepo) Create(arg1 db.Transaction, arg2 int, arg3 int) (bool, error) {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
 	fake.createArgsForCall = append(fake.createArgsForCall, struct {
@@ -213,9 +296,9 @@ func (fake *PolicyRepo) Create(arg1 db.Transaction, arg2 int, arg3 int) error {
 		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *PolicyRepo) CreateCallCount() int {
@@ -224,7 +307,7 @@ func (fake *PolicyRepo) CreateCallCount() int {
 	return len(fake.createArgsForCall)
 }
 
-func (fake *PolicyRepo) CreateCalls(stub func(db.Transaction, int, int) error) {
+func (fake *PolicyRepo) CreateCalls(stub func(db.Transaction, int, int) (bool, error)) {
 	fake.createMutex.Lock()
 	defer fake.createMutex.Unlock()
 	fake.CreateStub = stub
@@ -237,27 +320,30 @@ func (fake *PolicyRepo) CreateArgsForCall(i int) (db.Transaction, int, int) {
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *PolicyRepo) CreateReturns(result1 error) {
+func (fake *PolicyRepo) CreateReturns(result1 bool, result2 error) {
 	fake.createMutex.Lock()
 	defer fake.createMutex.Unlock()
 	fake.CreateStub = nil
 	fake.createReturns = struct {
-		result1 error
-	}{result1}
+		result1 bool
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *PolicyRepo) CreateReturnsOnCall(i int, result1 error) {
+func (fake *PolicyRepo) CreateReturnsOnCall(i int, result1 bool, result2 error) {
 	fake.createMutex.Lock()
 	defer fake.createMutex.Unlock()
 	fake.CreateStub = nil
 	if fake.createReturnsOnCall == nil {
 		fake.createReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 bool
+			result2 error
 		})
 	}
 	fake.createReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 bool
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *PolicyRepo) Delete(arg1 db.Transaction, arg2 int, arg3 int) error {
@@ -326,6 +412,8 @@ func (fake *PolicyRepo) DeleteReturnsOnCall(i int, result1 error) {
 func (fake *PolicyRepo) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.actionMutex.RLock()
+	defer fake.actionMutex.RUnlock()
 	fake.countWhereDestinationIDMutex.RLock()
 	defer fake.countWhereDestinationIDMutex.RUnlock()
 	fake.countWhereGroupIDMutex.RLock()
@@ -3,6 +3,7 @@ package fakes
 
 import (
 	"sync"
+	"time"
 
 	"code.cloudfoundry.org/policy-server/store"
 )
@@ -20,6 +21,33 @@ type Store struct {
 		result1 []store.Policy
 		result2 error
 	}
+	AllPaginatedStub        func(store.Page) ([]store.Policy, store.Pagination, error)
+	allPaginatedMutex       sync.RWMutex
+	allPaginatedArgsForCall []struct {
+		arg1 store.Page
+	}
+	allPaginatedReturns struct {
+		result1 []store.Policy
+		result2 store.Pagination
+		result3 error
+	}
+	allPaginatedReturnsOnCall map[int]struct {
+		result1 []store.Policy
+		result2 store.Pagination
+		result3 error
+	}
+	ApprovePendingStub        func(store.PendingPolicy, store.Actor) error
+	approvePendingMutex       sync.RWMutex
+	approvePendingArgsForCall []struct {
+		arg1 store.PendingPolicy
+		arg2 store.Actor
+	}
+	approvePendingReturns struct {
+		result1 error
+	}
+	approvePendingReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ByGuidsStub        func([]string, []string, bool) ([]store.Policy, error)
 	byGuidsMutex       sync.RWMutex
 	byGuidsArgsForCall []struct {
@@ -35,6 +63,24 @@ type Store struct {
 		result1 []store.Policy
 		result2 error
 	}
+	ByGuidsPaginatedStub        func([]string, []string, bool, store.Page) ([]store.Policy, store.Pagination, error)
+	byGuidsPaginatedMutex       sync.RWMutex
+	byGuidsPaginatedArgsForCall []struct {
+		arg1 []string
+		arg2 []string
+		arg3 bool
+		arg4 store.Page
+	}
+	byGuidsPaginatedReturns struct {
+		result1 []store.Policy
+		result2 store.Pagination
+		result3 error
+	}
+	byGuidsPaginatedReturnsOnCall map[int]struct {
+		result1 []store.Policy
+		result2 store.Pagination
+		result3 error
+	}
 	CheckDatabaseStub        func() error
 	checkDatabaseMutex       sync.RWMutex
 	checkDatabaseArgsForCall []struct {
@@ -56,6 +102,32 @@ type Store struct {
 	createReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CreateWithEventStub        func([]store.Policy, store.Actor) error
+	createWithEventMutex       sync.RWMutex
+	createWithEventArgsForCall []struct {
+		arg1 []store.Policy
+		arg2 store.Actor
+	}
+	createWithEventReturns struct {
+		result1 error
+	}
+	createWithEventReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateWithPendingStub        func([]store.Policy, []store.Policy, []store.PendingPolicy, store.Actor) error
+	createWithPendingMutex       sync.RWMutex
+	createWithPendingArgsForCall []struct {
+		arg1 []store.Policy
+		arg2 []store.Policy
+		arg3 []store.PendingPolicy
+		arg4 store.Actor
+	}
+	createWithPendingReturns struct {
+		result1 error
+	}
+	createWithPendingReturnsOnCall map[int]struct {
+		result1 error
+	}
 	DeleteStub        func([]store.Policy) error
 	deleteMutex       sync.RWMutex
 	deleteArgsForCall []struct {
@@ -67,6 +139,71 @@ type Store struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteExpiredStub        func(time.Time, store.Actor) ([]store.Policy, error)
+	deleteExpiredMutex       sync.RWMutex
+	deleteExpiredArgsForCall []struct {
+		arg1 time.Time
+		arg2 store.Actor
+	}
+	deleteExpiredReturns struct {
+		result1 []store.Policy
+		result2 error
+	}
+	deleteExpiredReturnsOnCall map[int]struct {
+		result1 []store.Policy
+		result2 error
+	}
+	DeleteWithEventStub        func([]store.Policy, store.Actor) error
+	deleteWithEventMutex       sync.RWMutex
+	deleteWithEventArgsForCall []struct {
+		arg1 []store.Policy
+		arg2 store.Actor
+	}
+	deleteWithEventReturns struct {
+		result1 error
+	}
+	deleteWithEventReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ExpiredCountStub        func(time.Time) (int, error)
+	expiredCountMutex       sync.RWMutex
+	expiredCountArgsForCall []struct {
+		arg1 time.Time
+	}
+	expiredCountReturns struct {
+		result1 int
+		result2 error
+	}
+	expiredCountReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	GroupMembersStub        func([]string) ([]store.GroupMember, error)
+	groupMembersMutex       sync.RWMutex
+	groupMembersArgsForCall []struct {
+		arg1 []string
+	}
+	groupMembersReturns struct {
+		result1 []store.GroupMember
+		result2 error
+	}
+	groupMembersReturnsOnCall map[int]struct {
+		result1 []store.GroupMember
+		result2 error
+	}
+	ImportWithEventStub        func([]store.Policy, []store.Policy, store.Actor) error
+	importWithEventMutex       sync.RWMutex
+	importWithEventArgsForCall []struct {
+		arg1 []store.Policy
+		arg2 []store.Policy
+		arg3 store.Actor
+	}
+	importWithEventReturns struct {
+		result1 error
+	}
+	importWithEventReturnsOnCall map[int]struct {
+		result1 error
+	}
 	LastUpdatedStub        func() (int, error)
 	lastUpdatedMutex       sync.RWMutex
 	lastUpdatedArgsForCall []struct {
@@ -79,6 +216,146 @@ type Store struct {
 		result1 int
 		result2 error
 	}
+	MemberGroupsStub        func([]string) ([]string, error)
+	memberGroupsMutex       sync.RWMutex
+	memberGroupsArgsForCall []struct {
+		arg1 []string
+	}
+	memberGroupsReturns struct {
+		result1 []string
+		result2 error
+	}
+	memberGroupsReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	MergeWithEventStub        func([]store.Policy, []store.Policy, store.Actor) error
+	mergeWithEventMutex       sync.RWMutex
+	mergeWithEventArgsForCall []struct {
+		arg1 []store.Policy
+		arg2 []store.Policy
+		arg3 store.Actor
+	}
+	mergeWithEventReturns struct {
+		result1 error
+	}
+	mergeWithEventReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PolicyCountsByScopeStub        func(string, []string, []string) (map[string]int, error)
+	policyCountsByScopeMutex       sync.RWMutex
+	policyCountsByScopeArgsForCall []struct {
+		arg1 string
+		arg2 []string
+		arg3 []string
+	}
+	policyCountsByScopeReturns struct {
+		result1 map[string]int
+		result2 error
+	}
+	policyCountsByScopeReturnsOnCall map[int]struct {
+		result1 map[string]int
+		result2 error
+	}
+	QuarantineStub        func(string, store.Actor) error
+	quarantineMutex       sync.RWMutex
+	quarantineArgsForCall []struct {
+		arg1 string
+		arg2 store.Actor
+	}
+	quarantineReturns struct {
+		result1 error
+	}
+	quarantineReturnsOnCall map[int]struct {
+		result1 error
+	}
+	QuarantinedStub        func() ([]string, error)
+	quarantinedMutex       sync.RWMutex
+	quarantinedArgsForCall []struct {
+	}
+	quarantinedReturns struct {
+		result1 []string
+		result2 error
+	}
+	quarantinedReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	ReconcileSourcesStub        func([]string, []store.Policy, int, store.Actor) error
+	reconcileSourcesMutex       sync.RWMutex
+	reconcileSourcesArgsForCall []struct {
+		arg1 []string
+		arg2 []store.Policy
+		arg3 int
+		arg4 store.Actor
+	}
+	reconcileSourcesReturns struct {
+		result1 error
+	}
+	reconcileSourcesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReleaseStub        func(string, store.Actor) error
+	releaseMutex       sync.RWMutex
+	releaseArgsForCall []struct {
+		arg1 string
+		arg2 store.Actor
+	}
+	releaseReturns struct {
+		result1 error
+	}
+	releaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReplaceForSourceStub        func(string, []store.Policy, []store.PendingPolicy, store.Actor, func([]store.Policy) error) error
+	replaceForSourceMutex       sync.RWMutex
+	replaceForSourceArgsForCall []struct {
+		arg1 string
+		arg2 []store.Policy
+		arg3 []store.PendingPolicy
+		arg4 store.Actor
+		arg5 func([]store.Policy) error
+	}
+	replaceForSourceReturns struct {
+		result1 error
+	}
+	replaceForSourceReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetGroupMembersStub        func(map[string][]string) error
+	setGroupMembersMutex       sync.RWMutex
+	setGroupMembersArgsForCall []struct {
+		arg1 map[string][]string
+	}
+	setGroupMembersReturns struct {
+		result1 error
+	}
+	setGroupMembersReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetSourceScopesStub        func([]store.SourceScope) error
+	setSourceScopesMutex       sync.RWMutex
+	setSourceScopesArgsForCall []struct {
+		arg1 []store.SourceScope
+	}
+	setSourceScopesReturns struct {
+		result1 error
+	}
+	setSourceScopesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UnscopedSourcesStub        func() ([]store.Source, error)
+	unscopedSourcesMutex       sync.RWMutex
+	unscopedSourcesArgsForCall []struct {
+	}
+	unscopedSourcesReturns struct {
+		result1 []store.Source
+		result2 error
+	}
+	unscopedSourcesReturnsOnCall map[int]struct {
+		result1 []store.Source
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -139,6 +416,135 @@ func (fake *Store) AllReturnsOnCall(i int, result1 []store.Policy, result2 error
 	}{result1, result2}
 }
 
+func (fake *Store) AllPaginated(arg1 store.Page) ([]store.Policy, store.Pagination, error) {
+	fake.allPaginatedMutex.Lock()
+	ret, specificReturn := fake.allPaginatedReturnsOnCall[len(fake.allPaginatedArgsForCall)]
+	fake.allPaginatedArgsForCall = append(fake.allPaginatedArgsForCall, struct {
+		arg1 store.Page
+	}{arg1})
+	stub := fake.AllPaginatedStub
+	fakeReturns := fake.allPaginatedReturns
+	fake.recordInvocation("AllPaginated", []interface{}{arg1})
+	fake.allPaginatedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *Store) AllPaginatedCallCount() int {
+	fake.allPaginatedMutex.RLock()
+	defer fake.allPaginatedMutex.RUnlock()
+	return len(fake.allPaginatedArgsForCall)
+}
+
+func (fake *Store) AllPaginatedCalls(stub func(store.Page) ([]store.Policy, store.Pagination, error)) {
+	fake.allPaginatedMutex.Lock()
+	defer fake.allPaginatedMutex.Unlock()
+	fake.AllPaginatedStub = stub
+}
+
+func (fake *Store) AllPaginatedArgsForCall(i int) store.Page {
+	fake.allPaginatedMutex.RLock()
+	defer fake.allPaginatedMutex.RUnlock()
+	argsForCall := fake.allPaginatedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Store) AllPaginatedReturns(result1 []store.Policy, result2 store.Pagination, result3 error) {
+	fake.allPaginatedMutex.Lock()
+	defer fake.allPaginatedMutex.Unlock()
+	fake.AllPaginatedStub = nil
+	fake.allPaginatedReturns = struct {
+		result1 []store.Policy
+		result2 store.Pagination
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *Store) AllPaginatedReturnsOnCall(i int, result1 []store.Policy, result2 store.Pagination, result3 error) {
+	fake.allPaginatedMutex.Lock()
+	defer fake.allPaginatedMutex.Unlock()
+	fake.AllPaginatedStub = nil
+	if fake.allPaginatedReturnsOnCall == nil {
+		fake.allPaginatedReturnsOnCall = make(map[int]struct {
+			result1 []store.Policy
+			result2 store.Pagination
+			result3 error
+		})
+	}
+	fake.allPaginatedReturnsOnCall[i] = struct {
+		result1 []store.Policy
+		result2 store.Pagination
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *Store) ApprovePending(arg1 store.PendingPolicy, arg2 store.Actor) error {
+	fake.approvePendingMutex.Lock()
+	ret, specificReturn := fake.approvePendingReturnsOnCall[len(fake.approvePendingArgsForCall)]
+	fake.approvePendingArgsForCall = append(fake.approvePendingArgsForCall, struct {
+		arg1 store.PendingPolicy
+		arg2 store.Actor
+	}{arg1, arg2})
+	stub := fake.ApprovePendingStub
+	fakeReturns := fake.approvePendingReturns
+	fake.recordInvocation("ApprovePending", []interface{}{arg1, arg2})
+	fake.approvePendingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Store) ApprovePendingCallCount() int {
+	fake.approvePendingMutex.RLock()
+	defer fake.approvePendingMutex.RUnlock()
+	return len(fake.approvePendingArgsForCall)
+}
+
+func (fake *Store) ApprovePendingCalls(stub func(store.PendingPolicy, store.Actor) error) {
+	fake.approvePendingMutex.Lock()
+	defer fake.approvePendingMutex.Unlock()
+	fake.ApprovePendingStub = stub
+}
+
+func (fake *Store) ApprovePendingArgsForCall(i int) (store.PendingPolicy, store.Actor) {
+	fake.approvePendingMutex.RLock()
+	defer fake.approvePendingMutex.RUnlock()
+	argsForCall := fake.approvePendingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Store) ApprovePendingReturns(result1 error) {
+	fake.approvePendingMutex.Lock()
+	defer fake.approvePendingMutex.Unlock()
+	fake.ApprovePendingStub = nil
+	fake.approvePendingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) ApprovePendingReturnsOnCall(i int, result1 error) {
+	fake.approvePendingMutex.Lock()
+	defer fake.approvePendingMutex.Unlock()
+	fake.ApprovePendingStub = nil
+	if fake.approvePendingReturnsOnCall == nil {
+		fake.approvePendingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.approvePendingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Store) ByGuids(arg1 []string, arg2 []string, arg3 bool) ([]store.Policy, error) {
 	var arg1Copy []string
 	if arg1 != nil {
@@ -215,6 +621,86 @@ func (fake *Store) ByGuidsReturnsOnCall(i int, result1 []store.Policy, result2 e
 	}{result1, result2}
 }
 
+func (fake *Store) ByGuidsPaginated(arg1 []string, arg2 []string, arg3 bool, arg4 store.Page) ([]store.Policy, store.Pagination, error) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.byGuidsPaginatedMutex.Lock()
+	ret, specificReturn := fake.byGuidsPaginatedReturnsOnCall[len(fake.byGuidsPaginatedArgsForCall)]
+	fake.byGuidsPaginatedArgsForCall = append(fake.byGuidsPaginatedArgsForCall, struct {
+		arg1 []string
+		arg2 []string
+		arg3 bool
+		arg4 store.Page
+	}{arg1Copy, arg2Copy, arg3, arg4})
+	stub := fake.ByGuidsPaginatedStub
+	fakeReturns := fake.byGuidsPaginatedReturns
+	fake.recordInvocation("ByGuidsPaginated", []interface{}{arg1Copy, arg2Copy, arg3, arg4})
+	fake.byGuidsPaginatedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *Store) ByGuidsPaginatedCallCount() int {
+	fake.byGuidsPaginatedMutex.RLock()
+	defer fake.byGuidsPaginatedMutex.RUnlock()
+	return len(fake.byGuidsPaginatedArgsForCall)
+}
+
+func (fake *Store) ByGuidsPaginatedCalls(stub func([]string, []string, bool, store.Page) ([]store.Policy, store.Pagination, error)) {
+	fake.byGuidsPaginatedMutex.Lock()
+	defer fake.byGuidsPaginatedMutex.Unlock()
+	fake.ByGuidsPaginatedStub = stub
+}
+
+func (fake *Store) ByGuidsPaginatedArgsForCall(i int) ([]string, []string, bool, store.Page) {
+	fake.byGuidsPaginatedMutex.RLock()
+	defer fake.byGuidsPaginatedMutex.RUnlock()
+	argsForCall := fake.byGuidsPaginatedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *Store) ByGuidsPaginatedReturns(result1 []store.Policy, result2 store.Pagination, result3 error) {
+	fake.byGuidsPaginatedMutex.Lock()
+	defer fake.byGuidsPaginatedMutex.Unlock()
+	fake.ByGuidsPaginatedStub = nil
+	fake.byGuidsPaginatedReturns = struct {
+		result1 []store.Policy
+		result2 store.Pagination
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *Store) ByGuidsPaginatedReturnsOnCall(i int, result1 []store.Policy, result2 store.Pagination, result3 error) {
+	fake.byGuidsPaginatedMutex.Lock()
+	defer fake.byGuidsPaginatedMutex.Unlock()
+	fake.ByGuidsPaginatedStub = nil
+	if fake.byGuidsPaginatedReturnsOnCall == nil {
+		fake.byGuidsPaginatedReturnsOnCall = make(map[int]struct {
+			result1 []store.Policy
+			result2 store.Pagination
+			result3 error
+		})
+	}
+	fake.byGuidsPaginatedReturnsOnCall[i] = struct {
+		result1 []store.Policy
+		result2 store.Pagination
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *Store) CheckDatabase() error {
 	fake.checkDatabaseMutex.Lock()
 	ret, specificReturn := fake.checkDatabaseReturnsOnCall[len(fake.checkDatabaseArgsForCall)]
@@ -334,23 +820,24 @@ func (fake *Store) CreateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *Store) Delete(arg1 []store.Policy) error {
+func (fake *Store) CreateWithEvent(arg1 []store.Policy, arg2 store.Actor) error {
 	var arg1Copy []store.Policy
 	if arg1 != nil {
 		arg1Copy = make([]store.Policy, len(arg1))
 		copy(arg1Copy, arg1)
 	}
-	fake.deleteMutex.Lock()
-	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
-	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+	fake.createWithEventMutex.Lock()
+	ret, specificReturn := fake.createWithEventReturnsOnCall[len(fake.createWithEventArgsForCall)]
+	fake.createWithEventArgsForCall = append(fake.createWithEventArgsForCall, struct {
 		arg1 []store.Policy
-	}{arg1Copy})
-	stub := fake.DeleteStub
-	fakeReturns := fake.deleteReturns
-	fake.recordInvocation("Delete", []interface{}{arg1Copy})
-	fake.deleteMutex.Unlock()
+		arg2 store.Actor
+	}{arg1Copy, arg2})
+	stub := fake.CreateWithEventStub
+	fakeReturns := fake.createWithEventReturns
+	fake.recordInvocation("CreateWithEvent", []interface{}{arg1Copy, arg2})
+	fake.createWithEventMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -358,119 +845,1374 @@ func (fake *Store) Delete(arg1 []store.Policy) error {
 	return fakeReturns.result1
 }
 
-func (fake *Store) DeleteCallCount() int {
-	fake.deleteMutex.RLock()
-	defer fake.deleteMutex.RUnlock()
-	return len(fake.deleteArgsForCall)
+func (fake *Store) CreateWithEventCallCount() int {
+	fake.createWithEventMutex.RLock()
+	defer fake.createWithEventMutex.RUnlock()
+	return len(fake.createWithEventArgsForCall)
 }
 
-func (fake *Store) DeleteCalls(stub func([]store.Policy) error) {
-	fake.deleteMutex.Lock()
-	defer fake.deleteMutex.Unlock()
-	fake.DeleteStub = stub
+func (fake *Store) CreateWithEventCalls(stub func([]store.Policy, store.Actor) error) {
+	fake.createWithEventMutex.Lock()
+	defer fake.createWithEventMutex.Unlock()
+	fake.CreateWithEventStub = stub
 }
 
-func (fake *Store) DeleteArgsForCall(i int) []store.Policy {
-	fake.deleteMutex.RLock()
-	defer fake.deleteMutex.RUnlock()
-	argsForCall := fake.deleteArgsForCall[i]
-	return argsForCall.arg1
+func (fake *Store) CreateWithEventArgsForCall(i int) ([]store.Policy, store.Actor) {
+	fake.createWithEventMutex.RLock()
+	defer fake.createWithEventMutex.RUnlock()
+	argsForCall := fake.createWithEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *Store) DeleteReturns(result1 error) {
-	fake.deleteMutex.Lock()
-	defer fake.deleteMutex.Unlock()
-	fake.DeleteStub = nil
-	fake.deleteReturns = struct {
+func (fake *Store) CreateWithEventReturns(result1 error) {
+	fake.createWithEventMutex.Lock()
+	defer fake.createWithEventMutex.Unlock()
+	fake.CreateWithEventStub = nil
+	fake.createWithEventReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *Store) DeleteReturnsOnCall(i int, result1 error) {
-	fake.deleteMutex.Lock()
-	defer fake.deleteMutex.Unlock()
-	fake.DeleteStub = nil
-	if fake.deleteReturnsOnCall == nil {
-		fake.deleteReturnsOnCall = make(map[int]struct {
+func (fake *Store) CreateWithEventReturnsOnCall(i int, result1 error) {
+	fake.createWithEventMutex.Lock()
+	defer fake.createWithEventMutex.Unlock()
+	fake.CreateWithEventStub = nil
+	if fake.createWithEventReturnsOnCall == nil {
+		fake.createWithEventReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.deleteReturnsOnCall[i] = struct {
+	fake.createWithEventReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *Store) LastUpdated() (int, error) {
-	fake.lastUpdatedMutex.Lock()
-	ret, specificReturn := fake.lastUpdatedReturnsOnCall[len(fake.lastUpdatedArgsForCall)]
-	fake.lastUpdatedArgsForCall = append(fake.lastUpdatedArgsForCall, struct {
-	}{})
-	stub := fake.LastUpdatedStub
-	fakeReturns := fake.lastUpdatedReturns
-	fake.recordInvocation("LastUpdated", []interface{}{})
-	fake.lastUpdatedMutex.Unlock()
-	if stub != nil {
-		return stub()
-	}
-	if specificReturn {
-		return ret.result1, ret.result2
+func (fake *Store) CreateWithPending(arg1 []store.Policy, arg2 []store.Policy, arg3 []store.PendingPolicy, arg4 store.Actor) error {
+	var arg1Copy []store.Policy
+	if arg1 != nil {
+		arg1Copy = make([]store.Policy, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	var arg2Copy []store.Policy
+	if arg2 != nil {
+		arg2Copy = make([]store.Policy, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	var arg3Copy []store.PendingPolicy
+	if arg3 != nil {
+		arg3Copy = make([]store.PendingPolicy, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.createWithPendingMutex.Lock()
+	ret, specificReturn := fake.createWithPendingReturnsOnCall[len(fake.createWithPendingArgsForCall)]
+	fake.createWithPendingArgsForCall = append(fake.createWithPendingArgsForCall, struct {
+		arg1 []store.Policy
+		arg2 []store.Policy
+		arg3 []store.PendingPolicy
+		arg4 store.Actor
+	}{arg1Copy, arg2Copy, arg3Copy, arg4})
+	stub := fake.CreateWithPendingStub
+	fakeReturns := fake.createWithPendingReturns
+	fake.recordInvocation("CreateWithPending", []interface{}{arg1Copy, arg2Copy, arg3Copy, arg4})
+	fake.createWithPendingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Store) CreateWithPendingCallCount() int {
+	fake.createWithPendingMutex.RLock()
+	defer fake.createWithPendingMutex.RUnlock()
+	return len(fake.createWithPendingArgsForCall)
+}
+
+func (fake *Store) CreateWithPendingCalls(stub func([]store.Policy, []store.Policy, []store.PendingPolicy, store.Actor) error) {
+	fake.createWithPendingMutex.Lock()
+	defer fake.createWithPendingMutex.Unlock()
+	fake.CreateWithPendingStub = stub
+}
+
+func (fake *Store) CreateWithPendingArgsForCall(i int) ([]store.Policy, []store.Policy, []store.PendingPolicy, store.Actor) {
+	fake.createWithPendingMutex.RLock()
+	defer fake.createWithPendingMutex.RUnlock()
+	argsForCall := fake.createWithPendingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *Store) CreateWithPendingReturns(result1 error) {
+	fake.createWithPendingMutex.Lock()
+	defer fake.createWithPendingMutex.Unlock()
+	fake.CreateWithPendingStub = nil
+	fake.createWithPendingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) CreateWithPendingReturnsOnCall(i int, result1 error) {
+	fake.createWithPendingMutex.Lock()
+	defer fake.createWithPendingMutex.Unlock()
+	fake.CreateWithPendingStub = nil
+	if fake.createWithPendingReturnsOnCall == nil {
+		fake.createWithPendingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createWithPendingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) Delete(arg1 []store.Policy) error {
+	var arg1Copy []store.Policy
+	if arg1 != nil {
+		arg1Copy = make([]store.Policy, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 []store.Policy
+	}{arg1Copy})
+	stub := fake.DeleteStub
+	fakeReturns := fake.deleteReturns
+	fake.recordInvocation("Delete", []interface{}{arg1Copy})
+	fake.deleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Store) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *Store) DeleteCalls(stub func([]store.Policy) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *Store) DeleteArgsForCall(i int) []store.Policy {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Store) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) DeleteExpired(arg1 time.Time, arg2 store.Actor) ([]store.Policy, error) {
+	fake.deleteExpiredMutex.Lock()
+	ret, specificReturn := fake.deleteExpiredReturnsOnCall[len(fake.deleteExpiredArgsForCall)]
+	fake.deleteExpiredArgsForCall = append(fake.deleteExpiredArgsForCall, struct {
+		arg1 time.Time
+		arg2 store.Actor
+	}{arg1, arg2})
+	stub := fake.DeleteExpiredStub
+	fakeReturns := fake.deleteExpiredReturns
+	fake.recordInvocation("DeleteExpired", []interface{}{arg1, arg2})
+	fake.deleteExpiredMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
 	}
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *Store) LastUpdatedCallCount() int {
-	fake.lastUpdatedMutex.RLock()
-	defer fake.lastUpdatedMutex.RUnlock()
-	return len(fake.lastUpdatedArgsForCall)
+func (fake *Store) DeleteExpiredCallCount() int {
+	fake.deleteExpiredMutex.RLock()
+	defer fake.deleteExpiredMutex.RUnlock()
+	return len(fake.deleteExpiredArgsForCall)
 }
 
-func (fake *Store) LastUpdatedCalls(stub func() (int, error)) {
-	fake.lastUpdatedMutex.Lock()
-	defer fake.lastUpdatedMutex.Unlock()
-	fake.LastUpdatedStub = stub
+func (fake *Store) DeleteExpiredCalls(stub func(time.Time, store.Actor) ([]store.Policy, error)) {
+	fake.deleteExpiredMutex.Lock()
+	defer fake.deleteExpiredMutex.Unlock()
+	fake.DeleteExpiredStub = stub
 }
 
-func (fake *Store) LastUpdatedReturns(result1 int, result2 error) {
-	fake.lastUpdatedMutex.Lock()
-	defer fake.lastUpdatedMutex.Unlock()
-	fake.LastUpdatedStub = nil
-	fake.lastUpdatedReturns = struct {
+func (fake *Store) DeleteExpiredArgsForCall(i int) (time.Time, store.Actor) {
+	fake.deleteExpiredMutex.RLock()
+	defer fake.deleteExpiredMutex.RUnlock()
+	argsForCall := fake.deleteExpiredArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Store) DeleteExpiredReturns(result1 []store.Policy, result2 error) {
+	fake.deleteExpiredMutex.Lock()
+	defer fake.deleteExpiredMutex.Unlock()
+	fake.DeleteExpiredStub = nil
+	fake.deleteExpiredReturns = struct {
+		result1 []store.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Store) DeleteExpiredReturnsOnCall(i int, result1 []store.Policy, result2 error) {
+	fake.deleteExpiredMutex.Lock()
+	defer fake.deleteExpiredMutex.Unlock()
+	fake.DeleteExpiredStub = nil
+	if fake.deleteExpiredReturnsOnCall == nil {
+		fake.deleteExpiredReturnsOnCall = make(map[int]struct {
+			result1 []store.Policy
+			result2 error
+		})
+	}
+	fake.deleteExpiredReturnsOnCall[i] = struct {
+		result1 []store.Policy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Store) DeleteWithEvent(arg1 []store.Policy, arg2 store.Actor) error {
+	var arg1Copy []store.Policy
+	if arg1 != nil {
+		arg1Copy = make([]store.Policy, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.deleteWithEventMutex.Lock()
+	ret, specificReturn := fake.deleteWithEventReturnsOnCall[len(fake.deleteWithEventArgsForCall)]
+	fake.deleteWithEventArgsForCall = append(fake.deleteWithEventArgsForCall, struct {
+		arg1 []store.Policy
+		arg2 store.Actor
+	}{arg1Copy, arg2})
+	stub := fake.DeleteWithEventStub
+	fakeReturns := fake.deleteWithEventReturns
+	fake.recordInvocation("DeleteWithEvent", []interface{}{arg1Copy, arg2})
+	fake.deleteWithEventMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Store) DeleteWithEventCallCount() int {
+	fake.deleteWithEventMutex.RLock()
+	defer fake.deleteWithEventMutex.RUnlock()
+	return len(fake.deleteWithEventArgsForCall)
+}
+
+func (fake *Store) DeleteWithEventCalls(stub func([]store.Policy, store.Actor) error) {
+	fake.deleteWithEventMutex.Lock()
+	defer fake.deleteWithEventMutex.Unlock()
+	fake.DeleteWithEventStub = stub
+}
+
+func (fake *Store) DeleteWithEventArgsForCall(i int) ([]store.Policy, store.Actor) {
+	fake.deleteWithEventMutex.RLock()
+	defer fake.deleteWithEventMutex.RUnlock()
+	argsForCall := fake.deleteWithEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Store) DeleteWithEventReturns(result1 error) {
+	fake.deleteWithEventMutex.Lock()
+	defer fake.deleteWithEventMutex.Unlock()
+	fake.DeleteWithEventStub = nil
+	fake.deleteWithEventReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) DeleteWithEventReturnsOnCall(i int, result1 error) {
+	fake.deleteWithEventMutex.Lock()
+	defer fake.deleteWithEventMutex.Unlock()
+	fake.DeleteWithEventStub = nil
+	if fake.deleteWithEventReturnsOnCall == nil {
+		fake.deleteWithEventReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteWithEventReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) ExpiredCount(arg1 time.Time) (int, error) {
+	fake.expiredCountMutex.Lock()
+	ret, specificReturn := fake.expiredCountReturnsOnCall[len(fake.expiredCountArgsForCall)]
+	fake.expiredCountArgsForCall = append(fake.expiredCountArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	stub := fake.ExpiredCountStub
+	fakeReturns := fake.expiredCountReturns
+	fake.recordInvocation("ExpiredCount", []interface{}{arg1})
+	fake.expiredCountMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Store) ExpiredCountCallCount() int {
+	fake.expiredCountMutex.RLock()
+	defer fake.expiredCountMutex.RUnlock()
+	return len(fake.expiredCountArgsForCall)
+}
+
+func (fake *Store) ExpiredCountCalls(stub func(time.Time) (int, error)) {
+	fake.expiredCountMutex.Lock()
+	defer fake.expiredCountMutex.Unlock()
+	fake.ExpiredCountStub = stub
+}
+
+func (fake *Store) ExpiredCountArgsForCall(i int) time.Time {
+	fake.expiredCountMutex.RLock()
+	defer fake.expiredCountMutex.RUnlock()
+	argsForCall := fake.expiredCountArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Store) ExpiredCountReturns(result1 int, result2 error) {
+	fake.expiredCountMutex.Lock()
+	defer fake.expiredCountMutex.Unlock()
+	fake.ExpiredCountStub = nil
+	fake.expiredCountReturns = struct {
 		result1 int
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *Store) LastUpdatedReturnsOnCall(i int, result1 int, result2 error) {
-	fake.lastUpdatedMutex.Lock()
-	defer fake.lastUpdatedMutex.Unlock()
-	fake.LastUpdatedStub = nil
-	if fake.lastUpdatedReturnsOnCall == nil {
-		fake.lastUpdatedReturnsOnCall = make(map[int]struct {
+func (fake *Store) ExpiredCountReturnsOnCall(i int, result1 int, result2 error) {
+	fake.expiredCountMutex.Lock()
+	defer fake.expiredCountMutex.Unlock()
+	fake.ExpiredCountStub = nil
+	if fake.expiredCountReturnsOnCall == nil {
+		fake.expiredCountReturnsOnCall = make(map[int]struct {
 			result1 int
 			result2 error
 		})
 	}
-	fake.lastUpdatedReturnsOnCall[i] = struct {
+	fake.expiredCountReturnsOnCall[i] = struct {
 		result1 int
 		result2 error
 	}{result1, result2}
 }
 
+func (fake *Store) GroupMembers(arg1 []string) ([]store.GroupMember, error) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.groupMembersMutex.Lock()
+	ret, specificReturn := fake.groupMembersReturnsOnCall[len(fake.groupMembersArgsForCall)]
+	fake.groupMembersArgsForCall = append(fake.groupMembersArgsForCall, struct {
+		arg1 []string
+	}{arg1Copy})
+	stub := fake.GroupMembersStub
+	fakeReturns := fake.groupMembersReturns
+	fake.recordInvocation("GroupMembers", []interface{}{arg1Copy})
+	fake.groupMembersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Store) GroupMembersCallCount() int {
+	fake.groupMembersMutex.RLock()
+	defer fake.groupMembersMutex.RUnlock()
+	return len(fake.groupMembersArgsForCall)
+}
+
+func (fake *Store) GroupMembersCalls(stub func([]string) ([]store.GroupMember, error)) {
+	fake.groupMembersMutex.Lock()
+	defer fake.groupMembersMutex.Unlock()
+	fake.GroupMembersStub = stub
+}
+
+func (fake *Store) GroupMembersArgsForCall(i int) []string {
+	fake.groupMembersMutex.RLock()
+	defer fake.groupMembersMutex.RUnlock()
+	argsForCall := fake.groupMembersArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Store) GroupMembersReturns(result1 []store.GroupMember, result2 error) {
+	fake.groupMembersMutex.Lock()
+	defer fake.groupMembersMutex.Unlock()
+	fake.GroupMembersStub = nil
+	fake.groupMembersReturns = struct {
+		result1 []store.GroupMember
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Store) GroupMembersReturnsOnCall(i int, result1 []store.GroupMember, result2 error) {
+	fake.groupMembersMutex.Lock()
+	defer fake.groupMembersMutex.Unlock()
+	fake.GroupMembersStub = nil
+	if fake.groupMembersReturnsOnCall == nil {
+		fake.groupMembersReturnsOnCall = make(map[int]struct {
+			result1 []store.GroupMember
+			result2 error
+		})
+	}
+	fake.groupMembersReturnsOnCall[i] = struct {
+		result1 []store.GroupMember
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Store) ImportWithEvent(arg1 []store.Policy, arg2 []store.Policy, arg3 store.Actor) error {
+	var arg1Copy []store.Policy
+	if arg1 != nil {
+		arg1Copy = make([]store.Policy, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	var arg2Copy []store.Policy
+	if arg2 != nil {
+		arg2Copy = make([]store.Policy, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.importWithEventMutex.Lock()
+	ret, specificReturn := fake.importWithEventReturnsOnCall[len(fake.importWithEventArgsForCall)]
+	fake.importWithEventArgsForCall = append(fake.importWithEventArgsForCall, struct {
+		arg1 []store.Policy
+		arg2 []store.Policy
+		arg3 store.Actor
+	}{arg1Copy, arg2Copy, arg3})
+	stub := fake.ImportWithEventStub
+	fakeReturns := fake.importWithEventReturns
+	fake.recordInvocation("ImportWithEvent", []interface{}{arg1Copy, arg2Copy, arg3})
+	fake.importWithEventMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Store) ImportWithEventCallCount() int {
+	fake.importWithEventMutex.RLock()
+	defer fake.importWithEventMutex.RUnlock()
+	return len(fake.importWithEventArgsForCall)
+}
+
+func (fake *Store) ImportWithEventCalls(stub func([]store.Policy, []store.Policy, store.Actor) error) {
+	fake.importWithEventMutex.Lock()
+	defer fake.importWithEventMutex.Unlock()
+	fake.ImportWithEventStub = stub
+}
+
+func (fake *Store) ImportWithEventArgsForCall(i int) ([]store.Policy, []store.Policy, store.Actor) {
+	fake.importWithEventMutex.RLock()
+	defer fake.importWithEventMutex.RUnlock()
+	argsForCall := fake.importWithEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *Store) ImportWithEventReturns(result1 error) {
+	fake.importWithEventMutex.Lock()
+	defer fake.importWithEventMutex.Unlock()
+	fake.ImportWithEventStub = nil
+	fake.importWithEventReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) ImportWithEventReturnsOnCall(i int, result1 error) {
+	fake.importWithEventMutex.Lock()
+	defer fake.importWithEventMutex.Unlock()
+	fake.ImportWithEventStub = nil
+	if fake.importWithEventReturnsOnCall == nil {
+		fake.importWithEventReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.importWithEventReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) LastUpdated() (int, error) {
+	fake.lastUpdatedMutex.Lock()
+	ret, specificReturn := fake.lastUpdatedReturnsOnCall[len(fake.lastUpdatedArgsForCall)]
+	fake.lastUpdatedArgsForCall = append(fake.lastUpdatedArgsForCall, struct {
+	}{})
+	stub := fake.LastUpdatedStub
+	fakeReturns := fake.lastUpdatedReturns
+	fake.recordInvocation("LastUpdated", []interface{}{})
+	fake.lastUpdatedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Store) LastUpdatedCallCount() int {
+	fake.lastUpdatedMutex.RLock()
+	defer fake.lastUpdatedMutex.RUnlock()
+	return len(fake.lastUpdatedArgsForCall)
+}
+
+func (fake *Store) LastUpdatedCalls(stub func() (int, error)) {
+	fake.lastUpdatedMutex.Lock()
+	defer fake.lastUpdatedMutex.Unlock()
+	fake.LastUpdatedStub = stub
+}
+
+func (fake *Store) LastUpdatedReturns(result1 int, result2 error) {
+	fake.lastUpdatedMutex.Lock()
+	defer fake.lastUpdatedMutex.Unlock()
+	fake.LastUpdatedStub = nil
+	fake.lastUpdatedReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Store) LastUpdatedReturnsOnCall(i int, result1 int, result2 error) {
+	fake.lastUpdatedMutex.Lock()
+	defer fake.lastUpdatedMutex.Unlock()
+	fake.LastUpdatedStub = nil
+	if fake.lastUpdatedReturnsOnCall == nil {
+		fake.lastUpdatedReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.lastUpdatedReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Store) MemberGroups(arg1 []string) ([]string, error) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.memberGroupsMutex.Lock()
+	ret, specificReturn := fake.memberGroupsReturnsOnCall[len(fake.memberGroupsArgsForCall)]
+	fake.memberGroupsArgsForCall = append(fake.memberGroupsArgsForCall, struct {
+		arg1 []string
+	}{arg1Copy})
+	stub := fake.MemberGroupsStub
+	fakeReturns := fake.memberGroupsReturns
+	fake.recordInvocation("MemberGroups", []interface{}{arg1Copy})
+	fake.memberGroupsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Store) MemberGroupsCallCount() int {
+	fake.memberGroupsMutex.RLock()
+	defer fake.memberGroupsMutex.RUnlock()
+	return len(fake.memberGroupsArgsForCall)
+}
+
+func (fake *Store) MemberGroupsCalls(stub func([]string) ([]string, error)) {
+	fake.memberGroupsMutex.Lock()
+	defer fake.memberGroupsMutex.Unlock()
+	fake.MemberGroupsStub = stub
+}
+
+func (fake *Store) MemberGroupsArgsForCall(i int) []string {
+	fake.memberGroupsMutex.RLock()
+	defer fake.memberGroupsMutex.RUnlock()
+	argsForCall := fake.memberGroupsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Store) MemberGroupsReturns(result1 []string, result2 error) {
+	fake.memberGroupsMutex.Lock()
+	defer fake.memberGroupsMutex.Unlock()
+	fake.MemberGroupsStub = nil
+	fake.memberGroupsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Store) MemberGroupsReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.memberGroupsMutex.Lock()
+	defer fake.memberGroupsMutex.Unlock()
+	fake.MemberGroupsStub = nil
+	if fake.memberGroupsReturnsOnCall == nil {
+		fake.memberGroupsReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.memberGroupsReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Store) MergeWithEvent(arg1 []store.Policy, arg2 []store.Policy, arg3 store.Actor) error {
+	var arg1Copy []store.Policy
+	if arg1 != nil {
+		arg1Copy = make([]store.Policy, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	var arg2Copy []store.Policy
+	if arg2 != nil {
+		arg2Copy = make([]store.Policy, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.mergeWithEventMutex.Lock()
+	ret, specificReturn := fake.mergeWithEventReturnsOnCall[len(fake.mergeWithEventArgsForCall)]
+	fake.mergeWithEventArgsForCall = append(fake.mergeWithEventArgsForCall, struct {
+		arg1 []store.Policy
+		arg2 []store.Policy
+		arg3 store.Actor
+	}{arg1Copy, arg2Copy, arg3})
+	stub := fake.MergeWithEventStub
+	fakeReturns := fake.mergeWithEventReturns
+	fake.recordInvocation("MergeWithEvent", []interface{}{arg1Copy, arg2Copy, arg3})
+	fake.mergeWithEventMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Store) MergeWithEventCallCount() int {
+	fake.mergeWithEventMutex.RLock()
+	defer fake.mergeWithEventMutex.RUnlock()
+	return len(fake.mergeWithEventArgsForCall)
+}
+
+func (fake *Store) MergeWithEventCalls(stub func([]store.Policy, []store.Policy, store.Actor) error) {
+	fake.mergeWithEventMutex.Lock()
+	defer fake.mergeWithEventMutex.Unlock()
+	fake.MergeWithEventStub = stub
+}
+
+func (fake *Store) MergeWithEventArgsForCall(i int) ([]store.Policy, []store.Policy, store.Actor) {
+	fake.mergeWithEventMutex.RLock()
+	defer fake.mergeWithEventMutex.RUnlock()
+	argsForCall := fake.mergeWithEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *Store) MergeWithEventReturns(result1 error) {
+	fake.mergeWithEventMutex.Lock()
+	defer fake.mergeWithEventMutex.Unlock()
+	fake.MergeWithEventStub = nil
+	fake.mergeWithEventReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) MergeWithEventReturnsOnCall(i int, result1 error) {
+	fake.mergeWithEventMutex.Lock()
+	defer fake.mergeWithEventMutex.Unlock()
+	fake.MergeWithEventStub = nil
+	if fake.mergeWithEventReturnsOnCall == nil {
+		fake.mergeWithEventReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.mergeWithEventReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) PolicyCountsByScope(arg1 string, arg2 []string, arg3 []string) (map[string]int, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.policyCountsByScopeMutex.Lock()
+	ret, specificReturn := fake.policyCountsByScopeReturnsOnCall[len(fake.policyCountsByScopeArgsForCall)]
+	fake.policyCountsByScopeArgsForCall = append(fake.policyCountsByScopeArgsForCall, struct {
+		arg1 string
+		arg2 []string
+		arg3 []string
+	}{arg1, arg2Copy, arg3Copy})
+	stub := fake.PolicyCountsByScopeStub
+	fakeReturns := fake.policyCountsByScopeReturns
+	fake.recordInvocation("PolicyCountsByScope", []interface{}{arg1, arg2Copy, arg3Copy})
+	fake.policyCountsByScopeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Store) PolicyCountsByScopeCallCount() int {
+	fake.policyCountsByScopeMutex.RLock()
+	defer fake.policyCountsByScopeMutex.RUnlock()
+	return len(fake.policyCountsByScopeArgsForCall)
+}
+
+func (fake *Store) PolicyCountsByScopeCalls(stub func(string, []string, []string) (map[string]int, error)) {
+	fake.policyCountsByScopeMutex.Lock()
+	defer fake.policyCountsByScopeMutex.Unlock()
+	fake.PolicyCountsByScopeStub = stub
+}
+
+func (fake *Store) PolicyCountsByScopeArgsForCall(i int) (string, []string, []string) {
+	fake.policyCountsByScopeMutex.RLock()
+	defer fake.policyCountsByScopeMutex.RUnlock()
+	argsForCall := fake.policyCountsByScopeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *Store) PolicyCountsByScopeReturns(result1 map[string]int, result2 error) {
+	fake.policyCountsByScopeMutex.Lock()
+	defer fake.policyCountsByScopeMutex.Unlock()
+	fake.PolicyCountsByScopeStub = nil
+	fake.policyCountsByScopeReturns = struct {
+		result1 map[string]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Store) PolicyCountsByScopeReturnsOnCall(i int, result1 map[string]int, result2 error) {
+	fake.policyCountsByScopeMutex.Lock()
+	defer fake.policyCountsByScopeMutex.Unlock()
+	fake.PolicyCountsByScopeStub = nil
+	if fake.policyCountsByScopeReturnsOnCall == nil {
+		fake.policyCountsByScopeReturnsOnCall = make(map[int]struct {
+			result1 map[string]int
+			result2 error
+		})
+	}
+	fake.policyCountsByScopeReturnsOnCall[i] = struct {
+		result1 map[string]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Store) Quarantine(arg1 string, arg2 store.Actor) error {
+	fake.quarantineMutex.Lock()
+	ret, specificReturn := fake.quarantineReturnsOnCall[len(fake.quarantineArgsForCall)]
+	fake.quarantineArgsForCall = append(fake.quarantineArgsForCall, struct {
+		arg1 string
+		arg2 store.Actor
+	}{arg1, arg2})
+	stub := fake.QuarantineStub
+	fakeReturns := fake.quarantineReturns
+	fake.recordInvocation("Quarantine", []interface{}{arg1, arg2})
+	fake.quarantineMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Store) QuarantineCallCount() int {
+	fake.quarantineMutex.RLock()
+	defer fake.quarantineMutex.RUnlock()
+	return len(fake.quarantineArgsForCall)
+}
+
+func (fake *Store) QuarantineCalls(stub func(string, store.Actor) error) {
+	fake.quarantineMutex.Lock()
+	defer fake.quarantineMutex.Unlock()
+	fake.QuarantineStub = stub
+}
+
+func (fake *Store) QuarantineArgsForCall(i int) (string, store.Actor) {
+	fake.quarantineMutex.RLock()
+	defer fake.quarantineMutex.RUnlock()
+	argsForCall := fake.quarantineArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Store) QuarantineReturns(result1 error) {
+	fake.quarantineMutex.Lock()
+	defer fake.quarantineMutex.Unlock()
+	fake.QuarantineStub = nil
+	fake.quarantineReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) QuarantineReturnsOnCall(i int, result1 error) {
+	fake.quarantineMutex.Lock()
+	defer fake.quarantineMutex.Unlock()
+	fake.QuarantineStub = nil
+	if fake.quarantineReturnsOnCall == nil {
+		fake.quarantineReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.quarantineReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) Quarantined() ([]string, error) {
+	fake.quarantinedMutex.Lock()
+	ret, specificReturn := fake.quarantinedReturnsOnCall[len(fake.quarantinedArgsForCall)]
+	fake.quarantinedArgsForCall = append(fake.quarantinedArgsForCall, struct {
+	}{})
+	stub := fake.QuarantinedStub
+	fakeReturns := fake.quarantinedReturns
+	fake.recordInvocation("Quarantined", []interface{}{})
+	fake.quarantinedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Store) QuarantinedCallCount() int {
+	fake.quarantinedMutex.RLock()
+	defer fake.quarantinedMutex.RUnlock()
+	return len(fake.quarantinedArgsForCall)
+}
+
+func (fake *Store) QuarantinedCalls(stub func() ([]string, error)) {
+	fake.quarantinedMutex.Lock()
+	defer fake.quarantinedMutex.Unlock()
+	fake.QuarantinedStub = stub
+}
+
+func (fake *Store) QuarantinedReturns(result1 []string, result2 error) {
+	fake.quarantinedMutex.Lock()
+	defer fake.quarantinedMutex.Unlock()
+	fake.QuarantinedStub = nil
+	fake.quarantinedReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Store) QuarantinedReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.quarantinedMutex.Lock()
+	defer fake.quarantinedMutex.Unlock()
+	fake.QuarantinedStub = nil
+	if fake.quarantinedReturnsOnCall == nil {
+		fake.quarantinedReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.quarantinedReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Store) ReconcileSources(arg1 []string, arg2 []store.Policy, arg3 int, arg4 store.Actor) error {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	var arg2Copy []store.Policy
+	if arg2 != nil {
+		arg2Copy = make([]store.Policy, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.reconcileSourcesMutex.Lock()
+	ret, specificReturn := fake.reconcileSourcesReturnsOnCall[len(fake.reconcileSourcesArgsForCall)]
+	fake.reconcileSourcesArgsForCall = append(fake.reconcileSourcesArgsForCall, struct {
+		arg1 []string
+		arg2 []store.Policy
+		arg3 int
+		arg4 store.Actor
+	}{arg1Copy, arg2Copy, arg3, arg4})
+	stub := fake.ReconcileSourcesStub
+	fakeReturns := fake.reconcileSourcesReturns
+	fake.recordInvocation("ReconcileSources", []interface{}{arg1Copy, arg2Copy, arg3, arg4})
+	fake.reconcileSourcesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Store) ReconcileSourcesCallCount() int {
+	fake.reconcileSourcesMutex.RLock()
+	defer fake.reconcileSourcesMutex.RUnlock()
+	return len(fake.reconcileSourcesArgsForCall)
+}
+
+func (fake *Store) ReconcileSourcesCalls(stub func([]string, []store.Policy, int, store.Actor) error) {
+	fake.reconcileSourcesMutex.Lock()
+	defer fake.reconcileSourcesMutex.Unlock()
+	fake.ReconcileSourcesStub = stub
+}
+
+func (fake *Store) ReconcileSourcesArgsForCall(i int) ([]string, []store.Policy, int, store.Actor) {
+	fake.reconcileSourcesMutex.RLock()
+	defer fake.reconcileSourcesMutex.RUnlock()
+	argsForCall := fake.reconcileSourcesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *Store) ReconcileSourcesReturns(result1 error) {
+	fake.reconcileSourcesMutex.Lock()
+	defer fake.reconcileSourcesMutex.Unlock()
+	fake.ReconcileSourcesStub = nil
+	fake.reconcileSourcesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) ReconcileSourcesReturnsOnCall(i int, result1 error) {
+	fake.reconcileSourcesMutex.Lock()
+	defer fake.reconcileSourcesMutex.Unlock()
+	fake.ReconcileSourcesStub = nil
+	if fake.reconcileSourcesReturnsOnCall == nil {
+		fake.reconcileSourcesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.reconcileSourcesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) Release(arg1 string, arg2 store.Actor) error {
+	fake.releaseMutex.Lock()
+	ret, specificReturn := fake.releaseReturnsOnCall[len(fake.releaseArgsForCall)]
+	fake.releaseArgsForCall = append(fake.releaseArgsForCall, struct {
+		arg1 string
+		arg2 store.Actor
+	}{arg1, arg2})
+	stub := fake.ReleaseStub
+	fakeReturns := fake.releaseReturns
+	fake.recordInvocation("Release", []interface{}{arg1, arg2})
+	fake.releaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Store) ReleaseCallCount() int {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return len(fake.releaseArgsForCall)
+}
+
+func (fake *Store) ReleaseCalls(stub func(string, store.Actor) error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = stub
+}
+
+func (fake *Store) ReleaseArgsForCall(i int) (string, store.Actor) {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	argsForCall := fake.releaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Store) ReleaseReturns(result1 error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = nil
+	fake.releaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) ReleaseReturnsOnCall(i int, result1 error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = nil
+	if fake.releaseReturnsOnCall == nil {
+		fake.releaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) ReplaceForSource(arg1 string, arg2 []store.Policy, arg3 []store.PendingPolicy, arg4 store.Actor, arg5 func([]store.Policy) error) error {
+	var arg2Copy []store.Policy
+	if arg2 != nil {
+		arg2Copy = make([]store.Policy, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	var arg3Copy []store.PendingPolicy
+	if arg3 != nil {
+		arg3Copy = make([]store.PendingPolicy, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.replaceForSourceMutex.Lock()
+	ret, specificReturn := fake.replaceForSourceReturnsOnCall[len(fake.replaceForSourceArgsForCall)]
+	fake.replaceForSourceArgsForCall = append(fake.replaceForSourceArgsForCall, struct {
+		arg1 string
+		arg2 []store.Policy
+		arg3 []store.PendingPolicy
+		arg4 store.Actor
+		arg5 func([]store.Policy) error
+	}{arg1, arg2Copy, arg3Copy, arg4, arg5})
+	stub := fake.ReplaceForSourceStub
+	fakeReturns := fake.replaceForSourceReturns
+	fake.recordInvocation("ReplaceForSource", []interface{}{arg1, arg2Copy, arg3Copy, arg4, arg5})
+	fake.replaceForSourceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Store) ReplaceForSourceCallCount() int {
+	fake.replaceForSourceMutex.RLock()
+	defer fake.replaceForSourceMutex.RUnlock()
+	return len(fake.replaceForSourceArgsForCall)
+}
+
+func (fake *Store) ReplaceForSourceCalls(stub func(string, []store.Policy, []store.PendingPolicy, store.Actor, func([]store.Policy) error) error) {
+	fake.replaceForSourceMutex.Lock()
+	defer fake.replaceForSourceMutex.Unlock()
+	fake.ReplaceForSourceStub = stub
+}
+
+func (fake *Store) ReplaceForSourceArgsForCall(i int) (string, []store.Policy, []store.PendingPolicy, store.Actor, func([]store.Policy) error) {
+	fake.replaceForSourceMutex.RLock()
+	defer fake.replaceForSourceMutex.RUnlock()
+	argsForCall := fake.replaceForSourceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *Store) ReplaceForSourceReturns(result1 error) {
+	fake.replaceForSourceMutex.Lock()
+	defer fake.replaceForSourceMutex.Unlock()
+	fake.ReplaceForSourceStub = nil
+	fake.replaceForSourceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) ReplaceForSourceReturnsOnCall(i int, result1 error) {
+	fake.replaceForSourceMutex.Lock()
+	defer fake.replaceForSourceMutex.Unlock()
+	fake.ReplaceForSourceStub = nil
+	if fake.replaceForSourceReturnsOnCall == nil {
+		fake.replaceForSourceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.replaceForSourceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) SetGroupMembers(arg1 map[string][]string) error {
+	fake.setGroupMembersMutex.Lock()
+	ret, specificReturn := fake.setGroupMembersReturnsOnCall[len(fake.setGroupMembersArgsForCall)]
+	fake.setGroupMembersArgsForCall = append(fake.setGroupMembersArgsForCall, struct {
+		arg1 map[string][]string
+	}{arg1})
+	stub := fake.SetGroupMembersStub
+	fakeReturns := fake.setGroupMembersReturns
+	fake.recordInvocation("SetGroupMembers", []interface{}{arg1})
+	fake.setGroupMembersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Store) SetGroupMembersCallCount() int {
+	fake.setGroupMembersMutex.RLock()
+	defer fake.setGroupMembersMutex.RUnlock()
+	return len(fake.setGroupMembersArgsForCall)
+}
+
+func (fake *Store) SetGroupMembersCalls(stub func(map[string][]string) error) {
+	fake.setGroupMembersMutex.Lock()
+	defer fake.setGroupMembersMutex.Unlock()
+	fake.SetGroupMembersStub = stub
+}
+
+func (fake *Store) SetGroupMembersArgsForCall(i int) map[string][]string {
+	fake.setGroupMembersMutex.RLock()
+	defer fake.setGroupMembersMutex.RUnlock()
+	argsForCall := fake.setGroupMembersArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Store) SetGroupMembersReturns(result1 error) {
+	fake.setGroupMembersMutex.Lock()
+	defer fake.setGroupMembersMutex.Unlock()
+	fake.SetGroupMembersStub = nil
+	fake.setGroupMembersReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) SetGroupMembersReturnsOnCall(i int, result1 error) {
+	fake.setGroupMembersMutex.Lock()
+	defer fake.setGroupMembersMutex.Unlock()
+	fake.SetGroupMembersStub = nil
+	if fake.setGroupMembersReturnsOnCall == nil {
+		fake.setGroupMembersReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setGroupMembersReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) SetSourceScopes(arg1 []store.SourceScope) error {
+	var arg1Copy []store.SourceScope
+	if arg1 != nil {
+		arg1Copy = make([]store.SourceScope, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.setSourceScopesMutex.Lock()
+	ret, specificReturn := fake.setSourceScopesReturnsOnCall[len(fake.setSourceScopesArgsForCall)]
+	fake.setSourceScopesArgsForCall = append(fake.setSourceScopesArgsForCall, struct {
+		arg1 []store.SourceScope
+	}{arg1Copy})
+	stub := fake.SetSourceScopesStub
+	fakeReturns := fake.setSourceScopesReturns
+	fake.recordInvocation("SetSourceScopes", []interface{}{arg1Copy})
+	fake.setSourceScopesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Store) SetSourceScopesCallCount() int {
+	fake.setSourceScopesMutex.RLock()
+	defer fake.setSourceScopesMutex.RUnlock()
+	return len(fake.setSourceScopesArgsForCall)
+}
+
+func (fake *Store) SetSourceScopesCalls(stub func([]store.SourceScope) error) {
+	fake.setSourceScopesMutex.Lock()
+	defer fake.setSourceScopesMutex.Unlock()
+	fake.SetSourceScopesStub = stub
+}
+
+func (fake *Store) SetSourceScopesArgsForCall(i int) []store.SourceScope {
+	fake.setSourceScopesMutex.RLock()
+	defer fake.setSourceScopesMutex.RUnlock()
+	argsForCall := fake.setSourceScopesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Store) SetSourceScopesReturns(result1 error) {
+	fake.setSourceScopesMutex.Lock()
+	defer fake.setSourceScopesMutex.Unlock()
+	fake.SetSourceScopesStub = nil
+	fake.setSourceScopesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) SetSourceScopesReturnsOnCall(i int, result1 error) {
+	fake.setSourceScopesMutex.Lock()
+	defer fake.setSourceScopesMutex.Unlock()
+	fake.SetSourceScopesStub = nil
+	if fake.setSourceScopesReturnsOnCall == nil {
+		fake.setSourceScopesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setSourceScopesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Store) UnscopedSources() ([]store.Source, error) {
+	fake.unscopedSourcesMutex.Lock()
+	ret, specificReturn := fake.unscopedSourcesReturnsOnCall[len(fake.unscopedSourcesArgsForCall)]
+	fake.unscopedSourcesArgsForCall = append(fake.unscopedSourcesArgsForCall, struct {
+	}{})
+	stub := fake.UnscopedSourcesStub
+	fakeReturns := fake.unscopedSourcesReturns
+	fake.recordInvocation("UnscopedSources", []interface{}{})
+	fake.unscopedSourcesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Store) UnscopedSourcesCallCount() int {
+	fake.unscopedSourcesMutex.RLock()
+	defer fake.unscopedSourcesMutex.RUnlock()
+	return len(fake.unscopedSourcesArgsForCall)
+}
+
+func (fake *Store) UnscopedSourcesCalls(stub func() ([]store.Source, error)) {
+	fake.unscopedSourcesMutex.Lock()
+	defer fake.unscopedSourcesMutex.Unlock()
+	fake.UnscopedSourcesStub = stub
+}
+
+func (fake *Store) UnscopedSourcesReturns(result1 []store.Source, result2 error) {
+	fake.unscopedSourcesMutex.Lock()
+	defer fake.unscopedSourcesMutex.Unlock()
+	fake.UnscopedSourcesStub = nil
+	fake.unscopedSourcesReturns = struct {
+		result1 []store.Source
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Store) UnscopedSourcesReturnsOnCall(i int, result1 []store.Source, result2 error) {
+	fake.unscopedSourcesMutex.Lock()
+	defer fake.unscopedSourcesMutex.Unlock()
+	fake.UnscopedSourcesStub = nil
+	if fake.unscopedSourcesReturnsOnCall == nil {
+		fake.unscopedSourcesReturnsOnCall = make(map[int]struct {
+			result1 []store.Source
+			result2 error
+		})
+	}
+	fake.unscopedSourcesReturnsOnCall[i] = struct {
+		result1 []store.Source
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Store) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.allMutex.RLock()
 	defer fake.allMutex.RUnlock()
+	fake.allPaginatedMutex.RLock()
+	defer fake.allPaginatedMutex.RUnlock()
+	fake.approvePendingMutex.RLock()
+	defer fake.approvePendingMutex.RUnlock()
 	fake.byGuidsMutex.RLock()
 	defer fake.byGuidsMutex.RUnlock()
+	fake.byGuidsPaginatedMutex.RLock()
+	defer fake.byGuidsPaginatedMutex.RUnlock()
 	fake.checkDatabaseMutex.RLock()
 	defer fake.checkDatabaseMutex.RUnlock()
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
+	fake.createWithEventMutex.RLock()
+	defer fake.createWithEventMutex.RUnlock()
+	fake.createWithPendingMutex.RLock()
+	defer fake.createWithPendingMutex.RUnlock()
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
+	fake.deleteExpiredMutex.RLock()
+	defer fake.deleteExpiredMutex.RUnlock()
+	fake.deleteWithEventMutex.RLock()
+	defer fake.deleteWithEventMutex.RUnlock()
+	fake.expiredCountMutex.RLock()
+	defer fake.expiredCountMutex.RUnlock()
+	fake.groupMembersMutex.RLock()
+	defer fake.groupMembersMutex.RUnlock()
+	fake.importWithEventMutex.RLock()
+	defer fake.importWithEventMutex.RUnlock()
 	fake.lastUpdatedMutex.RLock()
 	defer fake.lastUpdatedMutex.RUnlock()
+	fake.memberGroupsMutex.RLock()
+	defer fake.memberGroupsMutex.RUnlock()
+	fake.mergeWithEventMutex.RLock()
+	defer fake.mergeWithEventMutex.RUnlock()
+	fake.policyCountsByScopeMutex.RLock()
+	defer fake.policyCountsByScopeMutex.RUnlock()
+	fake.quarantineMutex.RLock()
+	defer fake.quarantineMutex.RUnlock()
+	fake.quarantinedMutex.RLock()
+	defer fake.quarantinedMutex.RUnlock()
+	fake.reconcileSourcesMutex.RLock()
+	defer fake.reconcileSourcesMutex.RUnlock()
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	fake.replaceForSourceMutex.RLock()
+	defer fake.replaceForSourceMutex.RUnlock()
+	fake.setGroupMembersMutex.RLock()
+	defer fake.setGroupMembersMutex.RUnlock()
+	fake.setSourceScopesMutex.RLock()
+	defer fake.setSourceScopesMutex.RUnlock()
+	fake.unscopedSourcesMutex.RLock()
+	defer fake.unscopedSourcesMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -12,13 +12,19 @@ type GroupRepo interface {
 	Create(db.Transaction, string, string) (int, error)
 	Delete(db.Transaction, int) error
 	GetID(db.Transaction, string) (int, error)
+	SetQuarantined(db.Transaction, int, bool) (bool, error)
+	CountWhereMemberID(db.Transaction, int) (int, error)
+	DeleteMembers(db.Transaction, int) ([]int, error)
 }
 
 type GroupTable struct {
 }
 
+// Create returns the row of the group, and takes a free row for it when there
+// is none. Guids are unique across types, so a group that exists with another
+// type is an error.
 func (g *GroupTable) Create(tx db.Transaction, guid, groupType string) (int, error) {
-	id, err := g.findRowByGUID(tx, guid, groupType)
+	id, existingType, err := g.findRowByGUID(tx, guid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			id, err = g.firstBlankRow(tx)
@@ -34,20 +40,23 @@ func (g *GroupTable) Create(tx db.Transaction, guid, groupType string) (int, err
 		}
 		return -1, err
 	}
+	if existingType != groupType {
+		return -1, fmt.Errorf("group %s already exists with type %s", guid, existingType)
+	}
 	return id, nil
 }
 
-func (g *GroupTable) findRowByGUID(tx db.Transaction, guid, groupType string) (int, error) {
+func (g *GroupTable) findRowByGUID(tx db.Transaction, guid string) (int, string, error) {
 	var id int
+	var groupType sql.NullString
 	err := tx.QueryRow(
 		tx.Rebind(`
-		SELECT id FROM "groups"
-		WHERE guid = ? AND type = ?
+		SELECT id, type FROM "groups"
+		WHERE guid = ?
 		`),
 		guid,
-		groupType,
-	).Scan(&id)
-	return id, err
+	).Scan(&id, &groupType)
+	return id, groupType.String, err
 }
 
 func (g *GroupTable) firstBlankRow(tx db.Transaction) (int, error) {
@@ -75,14 +84,34 @@ func (g *GroupTable) updateRow(tx db.Transaction, id int, guid, groupType string
 	return err
 }
 
+// Delete frees the row of a group for reuse. The rows of quarantined groups
+// are kept, so that the quarantine also applies to policies created later.
 func (g *GroupTable) Delete(tx db.Transaction, id int) error {
 	_, err := tx.Exec(
-		tx.Rebind(`UPDATE "groups" SET guid = NULL, type = NULL WHERE id = ?`),
+		tx.Rebind(`UPDATE "groups" SET guid = NULL, type = NULL, space_guid = NULL, org_guid = NULL WHERE id = ? AND quarantined = false`),
 		id,
 	)
 	return err
 }
 
+// SetQuarantined reports whether the flag of the group changed
+func (g *GroupTable) SetQuarantined(tx db.Transaction, id int, quarantined bool) (bool, error) {
+	result, err := tx.Exec(
+		tx.Rebind(`UPDATE "groups" SET quarantined = ? WHERE id = ? AND quarantined <> ?`),
+		quarantined,
+		id,
+		quarantined,
+	)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err // untested
+	}
+	return rowsAffected > 0, nil
+}
+
 func (g *GroupTable) GetID(tx db.Transaction, guid string) (int, error) {
 	var id int
 	err := tx.QueryRow(
@@ -92,3 +121,47 @@ func (g *GroupTable) GetID(tx db.Transaction, guid string) (int, error) {
 
 	return id, err
 }
+
+// CountWhereMemberID counts the spaces and orgs that the group is a member of
+func (g *GroupTable) CountWhereMemberID(tx db.Transaction, memberID int) (int, error) {
+	var count int
+	err := tx.QueryRow(
+		tx.Rebind(`SELECT COUNT(*) FROM group_members WHERE member_group_id = ?`),
+		memberID,
+	).Scan(&count)
+	return count, err
+}
+
+// DeleteMembers removes the members of the group, and returns their ids so
+// that the rows of members that are no longer referenced can be freed
+func (g *GroupTable) DeleteMembers(tx db.Transaction, id int) ([]int, error) {
+	rows, err := tx.Queryx(
+		tx.Rebind(`SELECT member_group_id FROM group_members WHERE group_id = ?`),
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() // untested
+
+	var memberIDs []int
+	for rows.Next() {
+		var memberID int
+		err = rows.Scan(&memberID)
+		if err != nil {
+			return nil, err
+		}
+		memberIDs = append(memberIDs, memberID)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err // untested
+	}
+	rows.Close()
+
+	_, err = tx.Exec(tx.Rebind(`DELETE FROM group_members WHERE group_id = ?`), id)
+	if err != nil {
+		return nil, err
+	}
+	return memberIDs, nil
+}
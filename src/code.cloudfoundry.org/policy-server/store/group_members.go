@@ -0,0 +1,216 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	"code.cloudfoundry.org/cf-networking-helpers/db"
+	"code.cloudfoundry.org/policy-server/store/helpers"
+)
+
+// GroupMember is an app of a space or org that is the source of policies.
+// Policy agents only know about apps, so the internal API lists a policy from
+// each member instead of the policy from the space or org.
+type GroupMember struct {
+	GroupGUID string
+	AppGUID   string
+	AppTag    string
+}
+
+const groupMembersSelect = `
+		SELECT grp.guid, member_grp.guid, member_grp.id
+		FROM group_members
+		JOIN "groups" AS grp ON (grp.id = group_members.group_id)
+		JOIN "groups" AS member_grp ON (member_grp.id = group_members.member_group_id)`
+
+// GroupMembers returns the apps of the given spaces and orgs
+func (s *store) GroupMembers(groupGuids []string) ([]GroupMember, error) {
+	members := []GroupMember{}
+	if len(groupGuids) == 0 {
+		return members, nil
+	}
+
+	query := groupMembersSelect + fmt.Sprintf(` WHERE grp.guid IN (%s) ORDER BY grp.guid, member_grp.guid`,
+		helpers.QuestionMarks(len(groupGuids)))
+	rows, err := s.conn.Query(s.conn.Rebind(query), stringsAsInterfaces(groupGuids)...)
+	if err != nil {
+		return nil, fmt.Errorf("selecting group members: %s", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var member GroupMember
+		var memberTag int
+		err := rows.Scan(&member.GroupGUID, &member.AppGUID, &memberTag)
+		if err != nil {
+			return nil, fmt.Errorf("scanning group member result: %s", err)
+		}
+		member.AppTag = s.tagIntToString(memberTag)
+		members = append(members, member)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("selecting group members, getting next row: %s", err) // untested
+	}
+	return members, nil
+}
+
+// MemberGroups returns the spaces and orgs that any of the apps is a member of
+func (s *store) MemberGroups(appGuids []string) ([]string, error) {
+	guids := []string{}
+	if len(appGuids) == 0 {
+		return guids, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT DISTINCT grp.guid
+		FROM group_members
+		JOIN "groups" AS grp ON (grp.id = group_members.group_id)
+		JOIN "groups" AS member_grp ON (member_grp.id = group_members.member_group_id)
+		WHERE member_grp.guid IN (%s)
+		ORDER BY grp.guid`, helpers.QuestionMarks(len(appGuids)))
+	rows, err := s.conn.Query(s.conn.Rebind(query), stringsAsInterfaces(appGuids)...)
+	if err != nil {
+		return nil, fmt.Errorf("selecting member groups: %s", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var guid string
+		err := rows.Scan(&guid)
+		if err != nil {
+			return nil, fmt.Errorf("scanning member group result: %s", err)
+		}
+		guids = append(guids, guid)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("selecting member groups, getting next row: %s", err) // untested
+	}
+	return guids, nil
+}
+
+type groupMemberRow struct {
+	id       int
+	memberID int
+}
+
+// SetGroupMembers replaces the apps of every space and org that is the source
+// of policies. Spaces and orgs that are left out lose their apps, and those
+// that no longer have policies are skipped. Last updated only changes when the
+// members do, so that policy agents pick up the change.
+func (s *store) SetGroupMembers(members map[string][]string) error {
+	tx, err := s.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("create transaction: %s", err)
+	}
+
+	// lock the policies info first, as every other policy change does
+	var lastUpdated sql.NullTime
+	err = tx.QueryRow(`SELECT last_updated FROM policies_info FOR UPDATE`).Scan(&lastUpdated)
+	if err != nil {
+		return rollback(tx, fmt.Errorf("locking policies info: %s", err))
+	}
+
+	current, err := groupMemberRowsWithTx(tx)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	changed := false
+	for groupGuid, appGuids := range members {
+		groupID, err := s.group.GetID(tx, groupGuid)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return rollback(tx, fmt.Errorf("getting group id: %s", err))
+		}
+
+		for _, appGuid := range appGuids {
+			if _, ok := current[groupGuid][appGuid]; ok {
+				continue
+			}
+			memberID, err := s.group.Create(tx, appGuid, GroupTypeApp)
+			if err != nil {
+				return rollback(tx, fmt.Errorf("creating group: %s", err))
+			}
+			_, err = tx.Exec(tx.Rebind(`INSERT INTO group_members (group_id, member_group_id) VALUES (?, ?)`),
+				groupID, memberID)
+			if err != nil {
+				return rollback(tx, fmt.Errorf("inserting group member: %s", err))
+			}
+			changed = true
+		}
+	}
+
+	for groupGuid, rows := range current {
+		keep := map[string]bool{}
+		for _, appGuid := range members[groupGuid] {
+			keep[appGuid] = true
+		}
+		for appGuid, row := range rows {
+			if keep[appGuid] {
+				continue
+			}
+			_, err = tx.Exec(tx.Rebind(`DELETE FROM group_members WHERE id = ?`), row.id)
+			if err != nil {
+				return rollback(tx, fmt.Errorf("deleting group member: %s", err))
+			}
+			err = s.deleteGroupRowIfLast(tx, row.memberID)
+			if err != nil {
+				return rollback(tx, fmt.Errorf("deleting group row: %s", err))
+			}
+			changed = true
+		}
+	}
+
+	if !changed {
+		return rollback(tx, nil)
+	}
+
+	err = s.updateLastUpdated(tx)
+	if err != nil {
+		return rollback(tx, err)
+	}
+	return commit(tx)
+}
+
+func groupMemberRowsWithTx(tx db.Transaction) (map[string]map[string]groupMemberRow, error) {
+	rows, err := tx.Queryx(`
+		SELECT group_members.id, grp.guid, member_grp.guid, member_grp.id
+		FROM group_members
+		JOIN "groups" AS grp ON (grp.id = group_members.group_id)
+		JOIN "groups" AS member_grp ON (member_grp.id = group_members.member_group_id)`)
+	if err != nil {
+		return nil, fmt.Errorf("selecting group members: %s", err)
+	}
+	defer rows.Close() // untested
+
+	current := map[string]map[string]groupMemberRow{}
+	for rows.Next() {
+		var groupGuid, appGuid string
+		var row groupMemberRow
+		err = rows.Scan(&row.id, &groupGuid, &appGuid, &row.memberID)
+		if err != nil {
+			return nil, fmt.Errorf("scanning group member result: %s", err)
+		}
+		if current[groupGuid] == nil {
+			current[groupGuid] = map[string]groupMemberRow{}
+		}
+		current[groupGuid][appGuid] = row
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("selecting group members, getting next row: %s", err) // untested
+	}
+	return current, nil
+}
+
+func stringsAsInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
@@ -1,7 +1,11 @@
 package store
 
 import (
+	"context"
 	"time"
+
+	"code.cloudfoundry.org/policy-server/tracing"
+	"go.opentelemetry.io/otel/trace"
 )
 
 //counterfeiter:generate -o fakes/metrics_sender.go --fake-name MetricsSender . metricsSender
@@ -13,13 +17,32 @@ type metricsSender interface {
 type MetricsWrapper struct {
 	Store         Store
 	TagStore      TagStore
+	EventsStore   PolicyEventsStore
+	QuotasStore   PolicyQuotasStore
+	PendingStore  PendingPoliciesStore
 	MetricsSender metricsSender
+	Context       context.Context
+}
+
+// Traced returns a copy of the wrapper that records a span for every store
+// operation, as child of the span in the context
+func (mw *MetricsWrapper) Traced(ctx context.Context) interface{} {
+	traced := *mw
+	traced.Context = ctx
+	return &traced
+}
+
+func (mw *MetricsWrapper) startSpan(operation string) trace.Span {
+	_, span := tracing.StartChild(mw.Context, "store "+operation, trace.SpanKindClient)
+	return span
 }
 
 func (mw *MetricsWrapper) Create(policies []Policy) error {
 	startTime := time.Now()
+	span := mw.startSpan("Create")
 	err := mw.Store.Create(policies)
 	createTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
 	if err != nil {
 		mw.MetricsSender.IncrementCounter("StoreCreateError")
 		mw.MetricsSender.SendDuration("StoreCreateErrorTime", createTimeDuration)
@@ -31,8 +54,18 @@ func (mw *MetricsWrapper) Create(policies []Policy) error {
 
 func (mw *MetricsWrapper) All() ([]Policy, error) {
 	startTime := time.Now()
-	policies, err := mw.Store.All()
+	span := mw.startSpan("All")
+	var policies []Policy
+	var err error
+	if cache, ok := mw.Store.(cachingStore); ok {
+		var hit bool
+		policies, hit, err = cache.CachedAll()
+		mw.sendPolicyCacheMetric(hit, err)
+	} else {
+		policies, err = mw.Store.All()
+	}
 	allTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
 	if err != nil {
 		mw.MetricsSender.IncrementCounter("StoreAllError")
 		mw.MetricsSender.SendDuration("StoreAllErrorTime", allTimeDuration)
@@ -44,8 +77,10 @@ func (mw *MetricsWrapper) All() ([]Policy, error) {
 
 func (mw *MetricsWrapper) Delete(policies []Policy) error {
 	startTime := time.Now()
+	span := mw.startSpan("Delete")
 	err := mw.Store.Delete(policies)
 	deleteTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
 	if err != nil {
 		mw.MetricsSender.IncrementCounter("StoreDeleteError")
 		mw.MetricsSender.SendDuration("StoreDeleteErrorTime", deleteTimeDuration)
@@ -55,10 +90,147 @@ func (mw *MetricsWrapper) Delete(policies []Policy) error {
 	return err
 }
 
+func (mw *MetricsWrapper) CreateWithEvent(policies []Policy, actor Actor) error {
+	startTime := time.Now()
+	span := mw.startSpan("CreateWithEvent")
+	err := mw.Store.CreateWithEvent(policies, actor)
+	createTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreCreateWithEventError")
+		mw.MetricsSender.SendDuration("StoreCreateWithEventErrorTime", createTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreCreateWithEventSuccessTime", createTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) DeleteWithEvent(policies []Policy, actor Actor) error {
+	startTime := time.Now()
+	span := mw.startSpan("DeleteWithEvent")
+	err := mw.Store.DeleteWithEvent(policies, actor)
+	deleteTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreDeleteWithEventError")
+		mw.MetricsSender.SendDuration("StoreDeleteWithEventErrorTime", deleteTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreDeleteWithEventSuccessTime", deleteTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) ReplaceForSource(sourceGuid string, policies []Policy, pending []PendingPolicy, actor Actor, checkExisting func([]Policy) error) error {
+	startTime := time.Now()
+	span := mw.startSpan("ReplaceForSource")
+	err := mw.Store.ReplaceForSource(sourceGuid, policies, pending, actor, checkExisting)
+	replaceTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreReplaceForSourceError")
+		mw.MetricsSender.SendDuration("StoreReplaceForSourceErrorTime", replaceTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreReplaceForSourceSuccessTime", replaceTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) ReconcileSources(sourceGuids []string, policies []Policy, lastUpdated int, actor Actor) error {
+	startTime := time.Now()
+	span := mw.startSpan("ReconcileSources")
+	err := mw.Store.ReconcileSources(sourceGuids, policies, lastUpdated, actor)
+	reconcileTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreReconcileSourcesError")
+		mw.MetricsSender.SendDuration("StoreReconcileSourcesErrorTime", reconcileTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreReconcileSourcesSuccessTime", reconcileTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) MergeWithEvent(created, deleted []Policy, actor Actor) error {
+	startTime := time.Now()
+	span := mw.startSpan("MergeWithEvent")
+	err := mw.Store.MergeWithEvent(created, deleted, actor)
+	mergeTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreMergeWithEventError")
+		mw.MetricsSender.SendDuration("StoreMergeWithEventErrorTime", mergeTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreMergeWithEventSuccessTime", mergeTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) ImportWithEvent(created, updated []Policy, actor Actor) error {
+	startTime := time.Now()
+	span := mw.startSpan("ImportWithEvent")
+	err := mw.Store.ImportWithEvent(created, updated, actor)
+	importTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreImportWithEventError")
+		mw.MetricsSender.SendDuration("StoreImportWithEventErrorTime", importTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreImportWithEventSuccessTime", importTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) Quarantine(appGuid string, actor Actor) error {
+	startTime := time.Now()
+	span := mw.startSpan("Quarantine")
+	err := mw.Store.Quarantine(appGuid, actor)
+	quarantineTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreQuarantineError")
+		mw.MetricsSender.SendDuration("StoreQuarantineErrorTime", quarantineTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreQuarantineSuccessTime", quarantineTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) Release(appGuid string, actor Actor) error {
+	startTime := time.Now()
+	span := mw.startSpan("Release")
+	err := mw.Store.Release(appGuid, actor)
+	releaseTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreReleaseError")
+		mw.MetricsSender.SendDuration("StoreReleaseErrorTime", releaseTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreReleaseSuccessTime", releaseTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) Quarantined() ([]string, error) {
+	startTime := time.Now()
+	span := mw.startSpan("Quarantined")
+	guids, err := mw.Store.Quarantined()
+	quarantinedTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreQuarantinedError")
+		mw.MetricsSender.SendDuration("StoreQuarantinedErrorTime", quarantinedTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreQuarantinedSuccessTime", quarantinedTimeDuration)
+	}
+	return guids, err
+}
+
 func (mw *MetricsWrapper) LastUpdated() (int, error) {
 	startTime := time.Now()
+	span := mw.startSpan("LastUpdated")
 	timestamp, err := mw.Store.LastUpdated()
 	lastUpdatedTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
 	if err != nil {
 		mw.MetricsSender.IncrementCounter("StoreLastUpdatedError")
 		mw.MetricsSender.SendDuration("StoreLastUpdatedErrorTime", lastUpdatedTimeDuration)
@@ -68,10 +240,132 @@ func (mw *MetricsWrapper) LastUpdated() (int, error) {
 	return timestamp, err
 }
 
+func (mw *MetricsWrapper) ExpiredCount(now time.Time) (int, error) {
+	startTime := time.Now()
+	span := mw.startSpan("ExpiredCount")
+	count, err := mw.Store.ExpiredCount(now)
+	expiredCountTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreExpiredCountError")
+		mw.MetricsSender.SendDuration("StoreExpiredCountErrorTime", expiredCountTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreExpiredCountSuccessTime", expiredCountTimeDuration)
+	}
+	return count, err
+}
+
+func (mw *MetricsWrapper) DeleteExpired(now time.Time, actor Actor) ([]Policy, error) {
+	startTime := time.Now()
+	span := mw.startSpan("DeleteExpired")
+	policies, err := mw.Store.DeleteExpired(now, actor)
+	deleteExpiredTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreDeleteExpiredError")
+		mw.MetricsSender.SendDuration("StoreDeleteExpiredErrorTime", deleteExpiredTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreDeleteExpiredSuccessTime", deleteExpiredTimeDuration)
+	}
+	return policies, err
+}
+
+func (mw *MetricsWrapper) GroupMembers(groupGuids []string) ([]GroupMember, error) {
+	startTime := time.Now()
+	span := mw.startSpan("GroupMembers")
+	members, err := mw.Store.GroupMembers(groupGuids)
+	groupMembersTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreGroupMembersError")
+		mw.MetricsSender.SendDuration("StoreGroupMembersErrorTime", groupMembersTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreGroupMembersSuccessTime", groupMembersTimeDuration)
+	}
+	return members, err
+}
+
+func (mw *MetricsWrapper) MemberGroups(appGuids []string) ([]string, error) {
+	startTime := time.Now()
+	span := mw.startSpan("MemberGroups")
+	guids, err := mw.Store.MemberGroups(appGuids)
+	memberGroupsTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreMemberGroupsError")
+		mw.MetricsSender.SendDuration("StoreMemberGroupsErrorTime", memberGroupsTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreMemberGroupsSuccessTime", memberGroupsTimeDuration)
+	}
+	return guids, err
+}
+
+func (mw *MetricsWrapper) SetGroupMembers(members map[string][]string) error {
+	startTime := time.Now()
+	span := mw.startSpan("SetGroupMembers")
+	err := mw.Store.SetGroupMembers(members)
+	setGroupMembersTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreSetGroupMembersError")
+		mw.MetricsSender.SendDuration("StoreSetGroupMembersErrorTime", setGroupMembersTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreSetGroupMembersSuccessTime", setGroupMembersTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) UnscopedSources() ([]Source, error) {
+	startTime := time.Now()
+	span := mw.startSpan("UnscopedSources")
+	sources, err := mw.Store.UnscopedSources()
+	unscopedSourcesTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreUnscopedSourcesError")
+		mw.MetricsSender.SendDuration("StoreUnscopedSourcesErrorTime", unscopedSourcesTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreUnscopedSourcesSuccessTime", unscopedSourcesTimeDuration)
+	}
+	return sources, err
+}
+
+func (mw *MetricsWrapper) SetSourceScopes(scopes []SourceScope) error {
+	startTime := time.Now()
+	span := mw.startSpan("SetSourceScopes")
+	err := mw.Store.SetSourceScopes(scopes)
+	setSourceScopesTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreSetSourceScopesError")
+		mw.MetricsSender.SendDuration("StoreSetSourceScopesErrorTime", setSourceScopesTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreSetSourceScopesSuccessTime", setSourceScopesTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) PolicyCountsByScope(scopeType string, guids, excludedSourceGuids []string) (map[string]int, error) {
+	startTime := time.Now()
+	span := mw.startSpan("PolicyCountsByScope")
+	counts, err := mw.Store.PolicyCountsByScope(scopeType, guids, excludedSourceGuids)
+	policyCountsByScopeTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StorePolicyCountsByScopeError")
+		mw.MetricsSender.SendDuration("StorePolicyCountsByScopeErrorTime", policyCountsByScopeTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StorePolicyCountsByScopeSuccessTime", policyCountsByScopeTimeDuration)
+	}
+	return counts, err
+}
+
 func (mw *MetricsWrapper) Tags() ([]Tag, error) {
 	startTime := time.Now()
+	span := mw.startSpan("Tags")
 	tags, err := mw.TagStore.Tags()
 	tagsTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
 	if err != nil {
 		mw.MetricsSender.IncrementCounter("StoreTagsError")
 		mw.MetricsSender.SendDuration("StoreTagsErrorTime", tagsTimeDuration)
@@ -83,8 +377,10 @@ func (mw *MetricsWrapper) Tags() ([]Tag, error) {
 
 func (mw *MetricsWrapper) CreateTag(groupGuid, groupType string) (Tag, error) {
 	startTime := time.Now()
+	span := mw.startSpan("CreateTag")
 	tag, err := mw.TagStore.CreateTag(groupGuid, groupType)
 	tagsTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
 	if err != nil {
 		mw.MetricsSender.IncrementCounter("StoreCreateTagError")
 		mw.MetricsSender.SendDuration("StoreCreateTagErrorTime", tagsTimeDuration)
@@ -96,8 +392,18 @@ func (mw *MetricsWrapper) CreateTag(groupGuid, groupType string) (Tag, error) {
 
 func (mw *MetricsWrapper) ByGuids(srcGuids, dstGuids []string, inSourceAndDest bool) ([]Policy, error) {
 	startTime := time.Now()
-	policies, err := mw.Store.ByGuids(srcGuids, dstGuids, inSourceAndDest)
+	span := mw.startSpan("ByGuids")
+	var policies []Policy
+	var err error
+	if cache, ok := mw.Store.(cachingStore); ok {
+		var hit bool
+		policies, hit, err = cache.CachedByGuids(srcGuids, dstGuids, inSourceAndDest)
+		mw.sendPolicyCacheMetric(hit, err)
+	} else {
+		policies, err = mw.Store.ByGuids(srcGuids, dstGuids, inSourceAndDest)
+	}
 	byGuidsTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
 	if err != nil {
 		mw.MetricsSender.IncrementCounter("StoreByGuidsError")
 		mw.MetricsSender.SendDuration("StoreByGuidsErrorTime", byGuidsTimeDuration)
@@ -107,10 +413,53 @@ func (mw *MetricsWrapper) ByGuids(srcGuids, dstGuids []string, inSourceAndDest b
 	return policies, err
 }
 
+func (mw *MetricsWrapper) sendPolicyCacheMetric(hit bool, err error) {
+	if err != nil {
+		return
+	}
+	if hit {
+		mw.MetricsSender.IncrementCounter("StorePolicyCacheHit")
+	} else {
+		mw.MetricsSender.IncrementCounter("StorePolicyCacheMiss")
+	}
+}
+
+func (mw *MetricsWrapper) AllPaginated(page Page) ([]Policy, Pagination, error) {
+	startTime := time.Now()
+	span := mw.startSpan("AllPaginated")
+	policies, pagination, err := mw.Store.AllPaginated(page)
+	allPaginatedTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreAllPaginatedError")
+		mw.MetricsSender.SendDuration("StoreAllPaginatedErrorTime", allPaginatedTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreAllPaginatedSuccessTime", allPaginatedTimeDuration)
+	}
+	return policies, pagination, err
+}
+
+func (mw *MetricsWrapper) ByGuidsPaginated(srcGuids, dstGuids []string, inSourceAndDest bool, page Page) ([]Policy, Pagination, error) {
+	startTime := time.Now()
+	span := mw.startSpan("ByGuidsPaginated")
+	policies, pagination, err := mw.Store.ByGuidsPaginated(srcGuids, dstGuids, inSourceAndDest, page)
+	byGuidsPaginatedTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreByGuidsPaginatedError")
+		mw.MetricsSender.SendDuration("StoreByGuidsPaginatedErrorTime", byGuidsPaginatedTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreByGuidsPaginatedSuccessTime", byGuidsPaginatedTimeDuration)
+	}
+	return policies, pagination, err
+}
+
 func (mw *MetricsWrapper) CheckDatabase() error {
 	startTime := time.Now()
+	span := mw.startSpan("CheckDatabase")
 	err := mw.Store.CheckDatabase()
 	duration := time.Since(startTime)
+	tracing.End(span, err)
 	if err != nil {
 		mw.MetricsSender.IncrementCounter("StoreCheckDatabaseError")
 		mw.MetricsSender.SendDuration("StoreCheckDatabaseErrorTime", duration)
@@ -119,3 +468,168 @@ func (mw *MetricsWrapper) CheckDatabase() error {
 	}
 	return err
 }
+
+func (mw *MetricsWrapper) Events(filter PolicyEventsFilter, page Page) ([]PolicyEvent, Pagination, error) {
+	startTime := time.Now()
+	span := mw.startSpan("Events")
+	events, pagination, err := mw.EventsStore.Events(filter, page)
+	eventsTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreEventsError")
+		mw.MetricsSender.SendDuration("StoreEventsErrorTime", eventsTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreEventsSuccessTime", eventsTimeDuration)
+	}
+	return events, pagination, err
+}
+
+func (mw *MetricsWrapper) DeleteEventsBefore(before time.Time) (int, error) {
+	startTime := time.Now()
+	span := mw.startSpan("DeleteEventsBefore")
+	deleted, err := mw.EventsStore.DeleteEventsBefore(before)
+	deleteEventsTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreDeleteEventsBeforeError")
+		mw.MetricsSender.SendDuration("StoreDeleteEventsBeforeErrorTime", deleteEventsTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreDeleteEventsBeforeSuccessTime", deleteEventsTimeDuration)
+	}
+	return deleted, err
+}
+
+func (mw *MetricsWrapper) Quotas() ([]Quota, error) {
+	startTime := time.Now()
+	span := mw.startSpan("Quotas")
+	quotas, err := mw.QuotasStore.Quotas()
+	quotasTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreQuotasError")
+		mw.MetricsSender.SendDuration("StoreQuotasErrorTime", quotasTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreQuotasSuccessTime", quotasTimeDuration)
+	}
+	return quotas, err
+}
+
+func (mw *MetricsWrapper) SetQuota(quota Quota) error {
+	startTime := time.Now()
+	span := mw.startSpan("SetQuota")
+	err := mw.QuotasStore.SetQuota(quota)
+	setQuotaTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreSetQuotaError")
+		mw.MetricsSender.SendDuration("StoreSetQuotaErrorTime", setQuotaTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreSetQuotaSuccessTime", setQuotaTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) DeleteQuota(quotaType, guid string) error {
+	startTime := time.Now()
+	span := mw.startSpan("DeleteQuota")
+	err := mw.QuotasStore.DeleteQuota(quotaType, guid)
+	deleteQuotaTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreDeleteQuotaError")
+		mw.MetricsSender.SendDuration("StoreDeleteQuotaErrorTime", deleteQuotaTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreDeleteQuotaSuccessTime", deleteQuotaTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) CreateWithPending(created, replaced []Policy, pending []PendingPolicy, actor Actor) error {
+	startTime := time.Now()
+	span := mw.startSpan("CreateWithPending")
+	err := mw.Store.CreateWithPending(created, replaced, pending, actor)
+	createWithPendingTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreCreateWithPendingError")
+		mw.MetricsSender.SendDuration("StoreCreateWithPendingErrorTime", createWithPendingTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreCreateWithPendingSuccessTime", createWithPendingTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) ApprovePending(pending PendingPolicy, approver Actor) error {
+	startTime := time.Now()
+	span := mw.startSpan("ApprovePending")
+	err := mw.Store.ApprovePending(pending, approver)
+	approveTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreApprovePendingError")
+		mw.MetricsSender.SendDuration("StoreApprovePendingErrorTime", approveTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreApprovePendingSuccessTime", approveTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) CreatePending(pending []PendingPolicy, actor Actor) error {
+	startTime := time.Now()
+	span := mw.startSpan("CreatePending")
+	err := mw.PendingStore.CreatePending(pending, actor)
+	createPendingTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreCreatePendingError")
+		mw.MetricsSender.SendDuration("StoreCreatePendingErrorTime", createPendingTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreCreatePendingSuccessTime", createPendingTimeDuration)
+	}
+	return err
+}
+
+func (mw *MetricsWrapper) PendingPolicies() ([]PendingPolicy, error) {
+	startTime := time.Now()
+	span := mw.startSpan("PendingPolicies")
+	pending, err := mw.PendingStore.PendingPolicies()
+	pendingPoliciesTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StorePendingPoliciesError")
+		mw.MetricsSender.SendDuration("StorePendingPoliciesErrorTime", pendingPoliciesTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StorePendingPoliciesSuccessTime", pendingPoliciesTimeDuration)
+	}
+	return pending, err
+}
+
+func (mw *MetricsWrapper) PendingPolicy(id int) (*PendingPolicy, error) {
+	startTime := time.Now()
+	span := mw.startSpan("PendingPolicy")
+	pending, err := mw.PendingStore.PendingPolicy(id)
+	pendingPolicyTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StorePendingPolicyError")
+		mw.MetricsSender.SendDuration("StorePendingPolicyErrorTime", pendingPolicyTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StorePendingPolicySuccessTime", pendingPolicyTimeDuration)
+	}
+	return pending, err
+}
+
+func (mw *MetricsWrapper) DeletePending(id int) error {
+	startTime := time.Now()
+	span := mw.startSpan("DeletePending")
+	err := mw.PendingStore.DeletePending(id)
+	deletePendingTimeDuration := time.Since(startTime)
+	tracing.End(span, err)
+	if err != nil {
+		mw.MetricsSender.IncrementCounter("StoreDeletePendingError")
+		mw.MetricsSender.SendDuration("StoreDeletePendingErrorTime", deletePendingTimeDuration)
+	} else {
+		mw.MetricsSender.SendDuration("StoreDeletePendingSuccessTime", deletePendingTimeDuration)
+	}
+	return err
+}
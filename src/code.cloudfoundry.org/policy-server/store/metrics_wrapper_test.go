@@ -1,10 +1,16 @@
 package store_test
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"code.cloudfoundry.org/policy-server/store"
 	"code.cloudfoundry.org/policy-server/store/fakes"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -14,21 +20,31 @@ var _ = Describe("MetricsWrapper", func() {
 	var (
 		metricsWrapper    *store.MetricsWrapper
 		policies          []store.Policy
+		actor             store.Actor
 		tags              []store.Tag
 		srcGuids          []string
 		destGuids         []string
 		fakeMetricsSender *fakes.MetricsSender
 		fakeStore         *fakes.Store
 		fakeTagStore      *fakes.TagStore
+		fakeEventsStore   *fakes.PolicyEventsStore
+		fakeQuotasStore   *fakes.PolicyQuotasStore
+		fakePendingStore  *fakes.PendingPoliciesStore
 	)
 
 	BeforeEach(func() {
 		fakeStore = &fakes.Store{}
 		fakeTagStore = &fakes.TagStore{}
+		fakeEventsStore = &fakes.PolicyEventsStore{}
+		fakeQuotasStore = &fakes.PolicyQuotasStore{}
+		fakePendingStore = &fakes.PendingPoliciesStore{}
 		fakeMetricsSender = &fakes.MetricsSender{}
 		metricsWrapper = &store.MetricsWrapper{
 			Store:         fakeStore,
 			TagStore:      fakeTagStore,
+			EventsStore:   fakeEventsStore,
+			QuotasStore:   fakeQuotasStore,
+			PendingStore:  fakePendingStore,
 			MetricsSender: fakeMetricsSender,
 		}
 		policies = []store.Policy{{
@@ -47,6 +63,7 @@ var _ = Describe("MetricsWrapper", func() {
 			Tag: "0002",
 		}}
 		srcGuids = []string{"some-app-guid"}
+		actor = store.Actor{Name: "some-user", ClientID: "some-client"}
 		destGuids = []string{"some-other-app-guid"}
 	})
 
@@ -85,293 +102,1695 @@ var _ = Describe("MetricsWrapper", func() {
 				Expect(name).To(Equal("StoreCreateErrorTime"))
 			})
 		})
+
+		Context("when the wrapper is traced", func() {
+			var (
+				recorder *tracetest.SpanRecorder
+				parent   trace.Span
+				traced   *store.MetricsWrapper
+			)
+
+			BeforeEach(func() {
+				recorder = tracetest.NewSpanRecorder()
+				provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+				var ctx context.Context
+				ctx, parent = provider.Tracer("test").Start(context.Background(), "parent")
+				traced = metricsWrapper.Traced(ctx).(*store.MetricsWrapper)
+			})
+
+			It("records a span as child of the span in the context", func() {
+				fakeStore.CreateReturns(errors.New("banana"))
+				err := traced.Create(policies)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(recorder.Ended()).To(HaveLen(1))
+				span := recorder.Ended()[0]
+				Expect(span.Name()).To(Equal("store Create"))
+				Expect(span.SpanKind()).To(Equal(trace.SpanKindClient))
+				Expect(span.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+				Expect(span.Status().Code).To(Equal(codes.Error))
+			})
+
+			It("records no span when it is not traced", func() {
+				Expect(metricsWrapper.Create(policies)).To(Succeed())
+				Expect(recorder.Ended()).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("CreateWithEvent", func() {
+		It("calls CreateWithEvent on the Store", func() {
+			err := metricsWrapper.CreateWithEvent(policies, actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.CreateWithEventCallCount()).To(Equal(1))
+			passedPolicies, passedActor := fakeStore.CreateWithEventArgsForCall(0)
+			Expect(passedPolicies).To(Equal(policies))
+			Expect(passedActor).To(Equal(actor))
+		})
+
+		It("emits a metric", func() {
+			err := metricsWrapper.CreateWithEvent(policies, actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreCreateWithEventSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.CreateWithEventReturns(errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				err := metricsWrapper.CreateWithEvent(policies, actor)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreCreateWithEventError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreCreateWithEventErrorTime"))
+			})
+		})
+	})
+
+	Describe("DeleteWithEvent", func() {
+		It("calls DeleteWithEvent on the Store", func() {
+			err := metricsWrapper.DeleteWithEvent(policies, actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.DeleteWithEventCallCount()).To(Equal(1))
+			passedPolicies, passedActor := fakeStore.DeleteWithEventArgsForCall(0)
+			Expect(passedPolicies).To(Equal(policies))
+			Expect(passedActor).To(Equal(actor))
+		})
+
+		It("emits a metric", func() {
+			err := metricsWrapper.DeleteWithEvent(policies, actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreDeleteWithEventSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.DeleteWithEventReturns(errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				err := metricsWrapper.DeleteWithEvent(policies, actor)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreDeleteWithEventError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreDeleteWithEventErrorTime"))
+			})
+		})
+	})
+
+	Describe("ReplaceForSource", func() {
+		It("calls ReplaceForSource on the Store", func() {
+			err := metricsWrapper.ReplaceForSource("some-app-guid", policies, nil, actor, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.ReplaceForSourceCallCount()).To(Equal(1))
+			sourceGuid, passedPolicies, _, passedActor, _ := fakeStore.ReplaceForSourceArgsForCall(0)
+			Expect(sourceGuid).To(Equal("some-app-guid"))
+			Expect(passedPolicies).To(Equal(policies))
+			Expect(passedActor).To(Equal(actor))
+		})
+
+		It("emits a metric", func() {
+			err := metricsWrapper.ReplaceForSource("some-app-guid", policies, nil, actor, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreReplaceForSourceSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.ReplaceForSourceReturns(errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				err := metricsWrapper.ReplaceForSource("some-app-guid", policies, nil, actor, nil)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreReplaceForSourceError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreReplaceForSourceErrorTime"))
+			})
+		})
+	})
+
+	Describe("MergeWithEvent", func() {
+		It("calls MergeWithEvent on the Store", func() {
+			err := metricsWrapper.MergeWithEvent(policies[:1], policies[1:], actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.MergeWithEventCallCount()).To(Equal(1))
+			created, deleted, passedActor := fakeStore.MergeWithEventArgsForCall(0)
+			Expect(created).To(Equal(policies[:1]))
+			Expect(deleted).To(Equal(policies[1:]))
+			Expect(passedActor).To(Equal(actor))
+		})
+
+		It("emits a metric", func() {
+			err := metricsWrapper.MergeWithEvent(policies[:1], policies[1:], actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreMergeWithEventSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.MergeWithEventReturns(errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				err := metricsWrapper.MergeWithEvent(policies[:1], policies[1:], actor)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreMergeWithEventError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreMergeWithEventErrorTime"))
+			})
+		})
+	})
+
+	Describe("ReconcileSources", func() {
+		It("calls ReconcileSources on the Store", func() {
+			err := metricsWrapper.ReconcileSources([]string{"some-space-guid"}, policies, 42, actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.ReconcileSourcesCallCount()).To(Equal(1))
+			sourceGuids, passedPolicies, lastUpdated, passedActor := fakeStore.ReconcileSourcesArgsForCall(0)
+			Expect(sourceGuids).To(Equal([]string{"some-space-guid"}))
+			Expect(passedPolicies).To(Equal(policies))
+			Expect(lastUpdated).To(Equal(42))
+			Expect(passedActor).To(Equal(actor))
+		})
+
+		It("emits a metric", func() {
+			err := metricsWrapper.ReconcileSources([]string{"some-space-guid"}, policies, 42, actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreReconcileSourcesSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.ReconcileSourcesReturns(errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				err := metricsWrapper.ReconcileSources([]string{"some-space-guid"}, policies, 42, actor)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreReconcileSourcesError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreReconcileSourcesErrorTime"))
+			})
+		})
+	})
+
+	Describe("ImportWithEvent", func() {
+		It("calls ImportWithEvent on the Store", func() {
+			err := metricsWrapper.ImportWithEvent(policies[:1], policies[1:], actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.ImportWithEventCallCount()).To(Equal(1))
+			created, updated, passedActor := fakeStore.ImportWithEventArgsForCall(0)
+			Expect(created).To(Equal(policies[:1]))
+			Expect(updated).To(Equal(policies[1:]))
+			Expect(passedActor).To(Equal(actor))
+		})
+
+		It("emits a metric", func() {
+			err := metricsWrapper.ImportWithEvent(policies[:1], policies[1:], actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreImportWithEventSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.ImportWithEventReturns(errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				err := metricsWrapper.ImportWithEvent(policies[:1], policies[1:], actor)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreImportWithEventError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreImportWithEventErrorTime"))
+			})
+		})
+	})
+
+	Describe("Quarantine", func() {
+		It("calls Quarantine on the Store", func() {
+			err := metricsWrapper.Quarantine("some-app-guid", actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.QuarantineCallCount()).To(Equal(1))
+			appGuid, passedActor := fakeStore.QuarantineArgsForCall(0)
+			Expect(appGuid).To(Equal("some-app-guid"))
+			Expect(passedActor).To(Equal(actor))
+		})
+
+		It("emits a metric", func() {
+			err := metricsWrapper.Quarantine("some-app-guid", actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreQuarantineSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.QuarantineReturns(errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				err := metricsWrapper.Quarantine("some-app-guid", actor)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreQuarantineError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreQuarantineErrorTime"))
+			})
+		})
+	})
+
+	Describe("Release", func() {
+		It("calls Release on the Store", func() {
+			err := metricsWrapper.Release("some-app-guid", actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.ReleaseCallCount()).To(Equal(1))
+			appGuid, passedActor := fakeStore.ReleaseArgsForCall(0)
+			Expect(appGuid).To(Equal("some-app-guid"))
+			Expect(passedActor).To(Equal(actor))
+		})
+
+		It("emits a metric", func() {
+			err := metricsWrapper.Release("some-app-guid", actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreReleaseSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.ReleaseReturns(errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				err := metricsWrapper.Release("some-app-guid", actor)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreReleaseError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreReleaseErrorTime"))
+			})
+		})
+	})
+
+	Describe("GroupMembers", func() {
+		BeforeEach(func() {
+			fakeStore.GroupMembersReturns([]store.GroupMember{{GroupGUID: "some-space-guid", AppGUID: "some-app-guid", AppTag: "0001"}}, nil)
+		})
+
+		It("returns the result of GroupMembers on the Store", func() {
+			members, err := metricsWrapper.GroupMembers([]string{"some-space-guid"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(Equal([]store.GroupMember{{GroupGUID: "some-space-guid", AppGUID: "some-app-guid", AppTag: "0001"}}))
+
+			Expect(fakeStore.GroupMembersCallCount()).To(Equal(1))
+			Expect(fakeStore.GroupMembersArgsForCall(0)).To(Equal([]string{"some-space-guid"}))
+		})
+
+		It("emits a metric", func() {
+			_, err := metricsWrapper.GroupMembers([]string{"some-space-guid"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreGroupMembersSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.GroupMembersReturns(nil, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, err := metricsWrapper.GroupMembers([]string{"some-space-guid"})
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreGroupMembersError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreGroupMembersErrorTime"))
+			})
+		})
+	})
+
+	Describe("MemberGroups", func() {
+		BeforeEach(func() {
+			fakeStore.MemberGroupsReturns([]string{"some-space-guid"}, nil)
+		})
+
+		It("returns the result of MemberGroups on the Store", func() {
+			guids, err := metricsWrapper.MemberGroups([]string{"some-app-guid"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(guids).To(Equal([]string{"some-space-guid"}))
+
+			Expect(fakeStore.MemberGroupsCallCount()).To(Equal(1))
+			Expect(fakeStore.MemberGroupsArgsForCall(0)).To(Equal([]string{"some-app-guid"}))
+		})
+
+		It("emits a metric", func() {
+			_, err := metricsWrapper.MemberGroups([]string{"some-app-guid"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreMemberGroupsSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.MemberGroupsReturns(nil, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, err := metricsWrapper.MemberGroups([]string{"some-app-guid"})
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreMemberGroupsError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreMemberGroupsErrorTime"))
+			})
+		})
+	})
+
+	Describe("SetGroupMembers", func() {
+		var members map[string][]string
+
+		BeforeEach(func() {
+			members = map[string][]string{"some-space-guid": {"some-app-guid"}}
+		})
+
+		It("calls SetGroupMembers on the Store", func() {
+			err := metricsWrapper.SetGroupMembers(members)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.SetGroupMembersCallCount()).To(Equal(1))
+			Expect(fakeStore.SetGroupMembersArgsForCall(0)).To(Equal(members))
+		})
+
+		It("emits a metric", func() {
+			err := metricsWrapper.SetGroupMembers(members)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreSetGroupMembersSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.SetGroupMembersReturns(errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				err := metricsWrapper.SetGroupMembers(members)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreSetGroupMembersError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreSetGroupMembersErrorTime"))
+			})
+		})
+	})
+
+	Describe("UnscopedSources", func() {
+		BeforeEach(func() {
+			fakeStore.UnscopedSourcesReturns([]store.Source{{ID: "some-app-guid"}}, nil)
+		})
+
+		It("returns the result of UnscopedSources on the Store", func() {
+			sources, err := metricsWrapper.UnscopedSources()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sources).To(Equal([]store.Source{{ID: "some-app-guid"}}))
+
+			Expect(fakeStore.UnscopedSourcesCallCount()).To(Equal(1))
+		})
+
+		It("emits a metric", func() {
+			_, err := metricsWrapper.UnscopedSources()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreUnscopedSourcesSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.UnscopedSourcesReturns(nil, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, err := metricsWrapper.UnscopedSources()
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreUnscopedSourcesError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreUnscopedSourcesErrorTime"))
+			})
+		})
+	})
+
+	Describe("SetSourceScopes", func() {
+		var scopes []store.SourceScope
+
+		BeforeEach(func() {
+			scopes = []store.SourceScope{{GUID: "some-app-guid", SpaceGUID: "some-space-guid", OrgGUID: "some-org-guid"}}
+		})
+
+		It("calls SetSourceScopes on the Store", func() {
+			err := metricsWrapper.SetSourceScopes(scopes)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.SetSourceScopesCallCount()).To(Equal(1))
+			Expect(fakeStore.SetSourceScopesArgsForCall(0)).To(Equal(scopes))
+		})
+
+		It("emits a metric", func() {
+			err := metricsWrapper.SetSourceScopes(scopes)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreSetSourceScopesSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.SetSourceScopesReturns(errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				err := metricsWrapper.SetSourceScopes(scopes)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreSetSourceScopesError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreSetSourceScopesErrorTime"))
+			})
+		})
+	})
+
+	Describe("PolicyCountsByScope", func() {
+		BeforeEach(func() {
+			fakeStore.PolicyCountsByScopeReturns(map[string]int{"some-space-guid": 2}, nil)
+		})
+
+		It("returns the result of PolicyCountsByScope on the Store", func() {
+			counts, err := metricsWrapper.PolicyCountsByScope("space", []string{"some-space-guid"}, []string{"some-app-guid"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(Equal(map[string]int{"some-space-guid": 2}))
+
+			Expect(fakeStore.PolicyCountsByScopeCallCount()).To(Equal(1))
+			scopeType, guids, excluded := fakeStore.PolicyCountsByScopeArgsForCall(0)
+			Expect(scopeType).To(Equal("space"))
+			Expect(guids).To(Equal([]string{"some-space-guid"}))
+			Expect(excluded).To(Equal([]string{"some-app-guid"}))
+		})
+
+		It("emits a metric", func() {
+			_, err := metricsWrapper.PolicyCountsByScope("space", []string{"some-space-guid"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StorePolicyCountsByScopeSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.PolicyCountsByScopeReturns(nil, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, err := metricsWrapper.PolicyCountsByScope("space", []string{"some-space-guid"}, nil)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StorePolicyCountsByScopeError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StorePolicyCountsByScopeErrorTime"))
+			})
+		})
+	})
+
+	Describe("Quarantined", func() {
+		BeforeEach(func() {
+			fakeStore.QuarantinedReturns([]string{"some-app-guid"}, nil)
+		})
+
+		It("returns the result of Quarantined on the Store", func() {
+			guids, err := metricsWrapper.Quarantined()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(guids).To(Equal([]string{"some-app-guid"}))
+
+			Expect(fakeStore.QuarantinedCallCount()).To(Equal(1))
+		})
+
+		It("emits a metric", func() {
+			_, err := metricsWrapper.Quarantined()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreQuarantinedSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.QuarantinedReturns(nil, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, err := metricsWrapper.Quarantined()
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreQuarantinedError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreQuarantinedErrorTime"))
+			})
+		})
+	})
+
+	Describe("CreateTag", func() {
+		var (
+			tag store.Tag
+		)
+		BeforeEach(func() {
+			tag = store.Tag{
+				ID:   "guid",
+				Tag:  "tag",
+				Type: "type",
+			}
+			fakeTagStore.CreateTagReturns(tag, nil)
+		})
+
+		It("calls CreateTag on the Store", func() {
+			tag, err := metricsWrapper.CreateTag("guid", "type")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tag).To(Equal(tag))
+
+			Expect(fakeTagStore.CreateTagCallCount()).To(Equal(1))
+			groupGuid, groupType := fakeTagStore.CreateTagArgsForCall(0)
+			Expect(groupGuid).To(Equal("guid"))
+			Expect(groupType).To(Equal("type"))
+		})
+
+		It("emits a metric", func() {
+			_, err := metricsWrapper.CreateTag("guid", "type")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreCreateTagSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeTagStore.CreateTagReturns(store.Tag{}, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, err := metricsWrapper.CreateTag("guid", "type")
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreCreateTagError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreCreateTagErrorTime"))
+			})
+		})
+	})
+
+	Describe("All", func() {
+		BeforeEach(func() {
+			fakeStore.AllReturns(policies, nil)
+		})
+		It("returns the result of All on the Store", func() {
+			returnedPolicies, err := metricsWrapper.All()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(returnedPolicies).To(Equal(policies))
+
+			Expect(fakeStore.AllCallCount()).To(Equal(1))
+		})
+
+		It("emits a metric", func() {
+			_, err := metricsWrapper.All()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreAllSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.AllReturns(nil, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, err := metricsWrapper.All()
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreAllError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreAllErrorTime"))
+
+			})
+		})
+
+		Context("when the store caches policies", func() {
+			var fakeCachingStore *fakes.CachingStore
+
+			BeforeEach(func() {
+				fakeCachingStore = &fakes.CachingStore{}
+				fakeCachingStore.CachedAllReturns(policies, true, nil)
+				metricsWrapper.Store = fakeCachingStore
+			})
+
+			It("returns the cached policies and emits a cache hit metric", func() {
+				returnedPolicies, err := metricsWrapper.All()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(returnedPolicies).To(Equal(policies))
+				Expect(fakeCachingStore.CachedAllCallCount()).To(Equal(1))
+				Expect(fakeCachingStore.AllCallCount()).To(Equal(0))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StorePolicyCacheHit"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreAllSuccessTime"))
+			})
+
+			Context("when the policies were not cached", func() {
+				BeforeEach(func() {
+					fakeCachingStore.CachedAllReturns(policies, false, nil)
+				})
+
+				It("emits a cache miss metric", func() {
+					_, err := metricsWrapper.All()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+					Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StorePolicyCacheMiss"))
+				})
+			})
+
+			Context("when there is an error", func() {
+				BeforeEach(func() {
+					fakeCachingStore.CachedAllReturns(nil, false, errors.New("banana"))
+				})
+
+				It("only emits the error metrics", func() {
+					_, err := metricsWrapper.All()
+					Expect(err).To(MatchError("banana"))
+
+					Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+					Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreAllError"))
+				})
+			})
+		})
+	})
+
+	Describe("ByGuids", func() {
+		BeforeEach(func() {
+			fakeStore.ByGuidsReturns(policies, nil)
+		})
+		It("returns the result of ByGuids on the Store", func() {
+			returnedPolicies, err := metricsWrapper.ByGuids(srcGuids, destGuids, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(returnedPolicies).To(Equal(policies))
+
+			Expect(fakeStore.ByGuidsCallCount()).To(Equal(1))
+			returnedSrcGuids, returnedDestGuids, inSourceAndDest := fakeStore.ByGuidsArgsForCall(0)
+			Expect(returnedSrcGuids).To(Equal(srcGuids))
+			Expect(returnedDestGuids).To(Equal(destGuids))
+			Expect(inSourceAndDest).To(BeTrue())
+		})
+
+		It("emits a metric", func() {
+			_, err := metricsWrapper.ByGuids(srcGuids, destGuids, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreByGuidsSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.ByGuidsReturns(nil, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, err := metricsWrapper.ByGuids(srcGuids, destGuids, true)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreByGuidsError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreByGuidsErrorTime"))
+
+			})
+		})
+
+		Context("when the store caches policies", func() {
+			var fakeCachingStore *fakes.CachingStore
+
+			BeforeEach(func() {
+				fakeCachingStore = &fakes.CachingStore{}
+				fakeCachingStore.CachedByGuidsReturns(policies, true, nil)
+				metricsWrapper.Store = fakeCachingStore
+			})
+
+			It("returns the cached policies and emits a cache hit metric", func() {
+				returnedPolicies, err := metricsWrapper.ByGuids(srcGuids, destGuids, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(returnedPolicies).To(Equal(policies))
+				Expect(fakeCachingStore.ByGuidsCallCount()).To(Equal(0))
+
+				Expect(fakeCachingStore.CachedByGuidsCallCount()).To(Equal(1))
+				returnedSrcGuids, returnedDestGuids, inSourceAndDest := fakeCachingStore.CachedByGuidsArgsForCall(0)
+				Expect(returnedSrcGuids).To(Equal(srcGuids))
+				Expect(returnedDestGuids).To(Equal(destGuids))
+				Expect(inSourceAndDest).To(BeTrue())
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StorePolicyCacheHit"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreByGuidsSuccessTime"))
+			})
+
+			Context("when the policies were not cached", func() {
+				BeforeEach(func() {
+					fakeCachingStore.CachedByGuidsReturns(policies, false, nil)
+				})
+
+				It("emits a cache miss metric", func() {
+					_, err := metricsWrapper.ByGuids(srcGuids, destGuids, true)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+					Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StorePolicyCacheMiss"))
+				})
+			})
+
+			Context("when there is an error", func() {
+				BeforeEach(func() {
+					fakeCachingStore.CachedByGuidsReturns(nil, false, errors.New("banana"))
+				})
+
+				It("only emits the error metrics", func() {
+					_, err := metricsWrapper.ByGuids(srcGuids, destGuids, true)
+					Expect(err).To(MatchError("banana"))
+
+					Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+					Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreByGuidsError"))
+				})
+			})
+		})
+	})
+
+	Describe("AllPaginated", func() {
+		BeforeEach(func() {
+			fakeStore.AllPaginatedReturns(policies, store.Pagination{Next: 5}, nil)
+		})
+		It("returns the result of AllPaginated on the Store", func() {
+			returnedPolicies, pagination, err := metricsWrapper.AllPaginated(store.Page{Limit: 2, From: 3})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(returnedPolicies).To(Equal(policies))
+			Expect(pagination).To(Equal(store.Pagination{Next: 5}))
+
+			Expect(fakeStore.AllPaginatedCallCount()).To(Equal(1))
+			Expect(fakeStore.AllPaginatedArgsForCall(0)).To(Equal(store.Page{Limit: 2, From: 3}))
+		})
+
+		It("emits a metric", func() {
+			_, _, err := metricsWrapper.AllPaginated(store.Page{Limit: 2})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreAllPaginatedSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.AllPaginatedReturns(nil, store.Pagination{}, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, _, err := metricsWrapper.AllPaginated(store.Page{Limit: 2})
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreAllPaginatedError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreAllPaginatedErrorTime"))
+			})
+		})
+	})
+
+	Describe("ByGuidsPaginated", func() {
+		BeforeEach(func() {
+			fakeStore.ByGuidsPaginatedReturns(policies, store.Pagination{Next: 5}, nil)
+		})
+		It("returns the result of ByGuidsPaginated on the Store", func() {
+			returnedPolicies, pagination, err := metricsWrapper.ByGuidsPaginated(srcGuids, destGuids, true, store.Page{Limit: 2})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(returnedPolicies).To(Equal(policies))
+			Expect(pagination).To(Equal(store.Pagination{Next: 5}))
+
+			Expect(fakeStore.ByGuidsPaginatedCallCount()).To(Equal(1))
+			returnedSrcGuids, returnedDestGuids, inSourceAndDest, page := fakeStore.ByGuidsPaginatedArgsForCall(0)
+			Expect(returnedSrcGuids).To(Equal(srcGuids))
+			Expect(returnedDestGuids).To(Equal(destGuids))
+			Expect(inSourceAndDest).To(BeTrue())
+			Expect(page).To(Equal(store.Page{Limit: 2}))
+		})
+
+		It("emits a metric", func() {
+			_, _, err := metricsWrapper.ByGuidsPaginated(srcGuids, destGuids, true, store.Page{Limit: 2})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreByGuidsPaginatedSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.ByGuidsPaginatedReturns(nil, store.Pagination{}, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, _, err := metricsWrapper.ByGuidsPaginated(srcGuids, destGuids, true, store.Page{Limit: 2})
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreByGuidsPaginatedError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreByGuidsPaginatedErrorTime"))
+			})
+		})
+	})
+
+	Describe("CheckDatabase", func() {
+		It("calls CheckDatabase on the Store", func() {
+			err := metricsWrapper.CheckDatabase()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.CheckDatabaseCallCount()).To(Equal(1))
+		})
+
+		It("emits a metric", func() {
+			err := metricsWrapper.CheckDatabase()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreCheckDatabaseSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.CheckDatabaseReturns(errors.New("huckleberry"))
+			})
+			It("emits an error metric", func() {
+				err := metricsWrapper.CheckDatabase()
+				Expect(err).To(MatchError("huckleberry"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreCheckDatabaseError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreCheckDatabaseErrorTime"))
+			})
+		})
+	})
+
+	Describe("Delete", func() {
+		It("calls Delete on the Store", func() {
+			err := metricsWrapper.Delete(policies)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.DeleteCallCount()).To(Equal(1))
+			passedPolicies := fakeStore.DeleteArgsForCall(0)
+			Expect(passedPolicies).To(Equal(policies))
+		})
+
+		It("emits a metric", func() {
+			err := metricsWrapper.Delete(policies)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreDeleteSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.DeleteReturns(errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				err := metricsWrapper.Delete(policies)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreDeleteError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreDeleteErrorTime"))
+			})
+		})
+	})
+
+	Describe("LastUpdated", func() {
+		BeforeEach(func() {
+			fakeStore.LastUpdatedReturns(12345, nil)
+		})
+
+		It("calls LastUpdated on the Store", func() {
+			timestamp, err := metricsWrapper.LastUpdated()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(timestamp).To(Equal(12345))
+
+			Expect(fakeStore.LastUpdatedCallCount()).To(Equal(1))
+		})
+
+		It("emits a metric", func() {
+			_, err := metricsWrapper.LastUpdated()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreLastUpdatedSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.LastUpdatedReturns(0, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, err := metricsWrapper.LastUpdated()
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreLastUpdatedError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreLastUpdatedErrorTime"))
+			})
+		})
+	})
+
+	Describe("ExpiredCount", func() {
+		var now time.Time
+
+		BeforeEach(func() {
+			now = time.Now()
+			fakeStore.ExpiredCountReturns(3, nil)
+		})
+
+		It("calls ExpiredCount on the Store", func() {
+			count, err := metricsWrapper.ExpiredCount(now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(3))
+
+			Expect(fakeStore.ExpiredCountCallCount()).To(Equal(1))
+			Expect(fakeStore.ExpiredCountArgsForCall(0)).To(Equal(now))
+		})
+
+		It("emits a metric", func() {
+			_, err := metricsWrapper.ExpiredCount(now)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreExpiredCountSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.ExpiredCountReturns(0, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, err := metricsWrapper.ExpiredCount(now)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreExpiredCountError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreExpiredCountErrorTime"))
+			})
+		})
+	})
+
+	Describe("DeleteExpired", func() {
+		var now time.Time
+
+		BeforeEach(func() {
+			now = time.Now()
+			fakeStore.DeleteExpiredReturns(policies, nil)
+		})
+
+		It("calls DeleteExpired on the Store", func() {
+			deleted, err := metricsWrapper.DeleteExpired(now, store.SystemActor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(policies))
+
+			Expect(fakeStore.DeleteExpiredCallCount()).To(Equal(1))
+			passedNow, passedActor := fakeStore.DeleteExpiredArgsForCall(0)
+			Expect(passedNow).To(Equal(now))
+			Expect(passedActor).To(Equal(store.SystemActor))
+		})
+
+		It("emits a metric", func() {
+			_, err := metricsWrapper.DeleteExpired(now, store.SystemActor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreDeleteExpiredSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeStore.DeleteExpiredReturns(nil, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, err := metricsWrapper.DeleteExpired(now, store.SystemActor)
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreDeleteExpiredError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreDeleteExpiredErrorTime"))
+			})
+		})
+	})
+
+	Describe("Tags", func() {
+		BeforeEach(func() {
+			fakeTagStore.TagsReturns(tags, nil)
+		})
+		It("calls Tags on the Store", func() {
+			returnedTags, err := metricsWrapper.Tags()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(returnedTags).To(Equal(tags))
+
+			Expect(fakeTagStore.TagsCallCount()).To(Equal(1))
+		})
+
+		It("emits a metric", func() {
+			_, err := metricsWrapper.Tags()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreTagsSuccessTime"))
+
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeTagStore.TagsReturns(nil, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, err := metricsWrapper.Tags()
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreTagsError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreTagsErrorTime"))
+
+			})
+		})
 	})
 
-	Describe("CreateTag", func() {
+	Describe("Events", func() {
 		var (
-			tag store.Tag
+			filter store.PolicyEventsFilter
+			page   store.Page
+			events []store.PolicyEvent
 		)
+
 		BeforeEach(func() {
-			tag = store.Tag{
-				ID:   "guid",
-				Tag:  "tag",
-				Type: "type",
-			}
-			fakeTagStore.CreateTagReturns(tag, nil)
+			filter = store.PolicyEventsFilter{AppGuids: srcGuids}
+			page = store.Page{From: 2, Limit: 1}
+			events = []store.PolicyEvent{{
+				ID:       1,
+				Action:   store.PolicyEventCreate,
+				Actor:    actor.Name,
+				Policies: policies,
+			}}
+			fakeEventsStore.EventsReturns(events, store.Pagination{Next: 3}, nil)
 		})
 
-		It("calls CreateTag on the Store", func() {
-			tag, err := metricsWrapper.CreateTag("guid", "type")
+		It("calls Events on the EventsStore", func() {
+			returnedEvents, pagination, err := metricsWrapper.Events(filter, page)
 			Expect(err).NotTo(HaveOccurred())
+			Expect(returnedEvents).To(Equal(events))
+			Expect(pagination).To(Equal(store.Pagination{Next: 3}))
 
-			Expect(tag).To(Equal(tag))
-
-			Expect(fakeTagStore.CreateTagCallCount()).To(Equal(1))
-			groupGuid, groupType := fakeTagStore.CreateTagArgsForCall(0)
-			Expect(groupGuid).To(Equal("guid"))
-			Expect(groupType).To(Equal("type"))
+			Expect(fakeEventsStore.EventsCallCount()).To(Equal(1))
+			passedFilter, passedPage := fakeEventsStore.EventsArgsForCall(0)
+			Expect(passedFilter).To(Equal(filter))
+			Expect(passedPage).To(Equal(page))
 		})
 
 		It("emits a metric", func() {
-			_, err := metricsWrapper.CreateTag("guid", "type")
+			_, _, err := metricsWrapper.Events(filter, page)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
 			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
-			Expect(name).To(Equal("StoreCreateTagSuccessTime"))
+			Expect(name).To(Equal("StoreEventsSuccessTime"))
 		})
 
 		Context("when there is an error", func() {
 			BeforeEach(func() {
-				fakeTagStore.CreateTagReturns(store.Tag{}, errors.New("banana"))
+				fakeEventsStore.EventsReturns(nil, store.Pagination{}, errors.New("banana"))
 			})
 			It("emits an error metric", func() {
-				_, err := metricsWrapper.CreateTag("guid", "type")
+				_, _, err := metricsWrapper.Events(filter, page)
 				Expect(err).To(MatchError("banana"))
 
 				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
-				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreCreateTagError"))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreEventsError"))
 
 				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
 				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
-				Expect(name).To(Equal("StoreCreateTagErrorTime"))
+				Expect(name).To(Equal("StoreEventsErrorTime"))
 			})
 		})
 	})
 
-	Describe("All", func() {
+	Describe("DeleteEventsBefore", func() {
+		var before time.Time
+
 		BeforeEach(func() {
-			fakeStore.AllReturns(policies, nil)
+			before = time.Now().Add(-time.Hour)
+			fakeEventsStore.DeleteEventsBeforeReturns(4, nil)
 		})
-		It("returns the result of All on the Store", func() {
-			returnedPolicies, err := metricsWrapper.All()
+
+		It("calls DeleteEventsBefore on the EventsStore", func() {
+			deleted, err := metricsWrapper.DeleteEventsBefore(before)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(returnedPolicies).To(Equal(policies))
+			Expect(deleted).To(Equal(4))
 
-			Expect(fakeStore.AllCallCount()).To(Equal(1))
+			Expect(fakeEventsStore.DeleteEventsBeforeCallCount()).To(Equal(1))
+			Expect(fakeEventsStore.DeleteEventsBeforeArgsForCall(0)).To(Equal(before))
 		})
 
 		It("emits a metric", func() {
-			_, err := metricsWrapper.All()
+			_, err := metricsWrapper.DeleteEventsBefore(before)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
 			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
-			Expect(name).To(Equal("StoreAllSuccessTime"))
+			Expect(name).To(Equal("StoreDeleteEventsBeforeSuccessTime"))
 		})
 
 		Context("when there is an error", func() {
 			BeforeEach(func() {
-				fakeStore.AllReturns(nil, errors.New("banana"))
+				fakeEventsStore.DeleteEventsBeforeReturns(0, errors.New("banana"))
 			})
 			It("emits an error metric", func() {
-				_, err := metricsWrapper.All()
+				_, err := metricsWrapper.DeleteEventsBefore(before)
 				Expect(err).To(MatchError("banana"))
 
 				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
-				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreAllError"))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreDeleteEventsBeforeError"))
 
 				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
 				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
-				Expect(name).To(Equal("StoreAllErrorTime"))
+				Expect(name).To(Equal("StoreDeleteEventsBeforeErrorTime"))
+			})
+		})
+	})
+
+	Describe("Quotas", func() {
+		var quotas []store.Quota
+
+		BeforeEach(func() {
+			quotas = []store.Quota{{Type: "space", GUID: "some-space-guid", MaxPolicies: 10}}
+			fakeQuotasStore.QuotasReturns(quotas, nil)
+		})
+
+		It("calls Quotas on the QuotasStore", func() {
+			returnedQuotas, err := metricsWrapper.Quotas()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(returnedQuotas).To(Equal(quotas))
+
+			Expect(fakeQuotasStore.QuotasCallCount()).To(Equal(1))
+		})
+
+		It("emits a metric", func() {
+			_, err := metricsWrapper.Quotas()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreQuotasSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeQuotasStore.QuotasReturns(nil, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, err := metricsWrapper.Quotas()
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreQuotasError"))
 
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreQuotasErrorTime"))
 			})
 		})
 	})
 
-	Describe("ByGuids", func() {
+	Describe("SetQuota", func() {
+		var quota store.Quota
+
 		BeforeEach(func() {
-			fakeStore.ByGuidsReturns(policies, nil)
+			quota = store.Quota{Type: "org", GUID: "some-org-guid", MaxPolicies: 10}
 		})
-		It("returns the result of ByGuids on the Store", func() {
-			returnedPolicies, err := metricsWrapper.ByGuids(srcGuids, destGuids, true)
+
+		It("calls SetQuota on the QuotasStore", func() {
+			err := metricsWrapper.SetQuota(quota)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(returnedPolicies).To(Equal(policies))
 
-			Expect(fakeStore.ByGuidsCallCount()).To(Equal(1))
-			returnedSrcGuids, returnedDestGuids, inSourceAndDest := fakeStore.ByGuidsArgsForCall(0)
-			Expect(returnedSrcGuids).To(Equal(srcGuids))
-			Expect(returnedDestGuids).To(Equal(destGuids))
-			Expect(inSourceAndDest).To(BeTrue())
+			Expect(fakeQuotasStore.SetQuotaCallCount()).To(Equal(1))
+			Expect(fakeQuotasStore.SetQuotaArgsForCall(0)).To(Equal(quota))
 		})
 
 		It("emits a metric", func() {
-			_, err := metricsWrapper.ByGuids(srcGuids, destGuids, true)
+			err := metricsWrapper.SetQuota(quota)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
 			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
-			Expect(name).To(Equal("StoreByGuidsSuccessTime"))
+			Expect(name).To(Equal("StoreSetQuotaSuccessTime"))
 		})
 
 		Context("when there is an error", func() {
 			BeforeEach(func() {
-				fakeStore.ByGuidsReturns(nil, errors.New("banana"))
+				fakeQuotasStore.SetQuotaReturns(errors.New("banana"))
 			})
 			It("emits an error metric", func() {
-				_, err := metricsWrapper.ByGuids(srcGuids, destGuids, true)
+				err := metricsWrapper.SetQuota(quota)
 				Expect(err).To(MatchError("banana"))
 
 				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
-				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreByGuidsError"))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreSetQuotaError"))
 
 				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
 				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
-				Expect(name).To(Equal("StoreByGuidsErrorTime"))
+				Expect(name).To(Equal("StoreSetQuotaErrorTime"))
+			})
+		})
+	})
+
+	Describe("DeleteQuota", func() {
+		It("calls DeleteQuota on the QuotasStore", func() {
+			err := metricsWrapper.DeleteQuota("org", "some-org-guid")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeQuotasStore.DeleteQuotaCallCount()).To(Equal(1))
+			quotaType, guid := fakeQuotasStore.DeleteQuotaArgsForCall(0)
+			Expect(quotaType).To(Equal("org"))
+			Expect(guid).To(Equal("some-org-guid"))
+		})
 
+		It("emits a metric", func() {
+			err := metricsWrapper.DeleteQuota("org", "some-org-guid")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreDeleteQuotaSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakeQuotasStore.DeleteQuotaReturns(errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				err := metricsWrapper.DeleteQuota("org", "some-org-guid")
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreDeleteQuotaError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreDeleteQuotaErrorTime"))
 			})
 		})
 	})
 
-	Describe("CheckDatabase", func() {
-		It("calls CheckDatabase on the Store", func() {
-			err := metricsWrapper.CheckDatabase()
+	Describe("CreateWithPending", func() {
+		var pending []store.PendingPolicy
+
+		BeforeEach(func() {
+			pending = []store.PendingPolicy{{Policy: policies[0], DestinationSpaceGUID: "some-space-guid"}}
+		})
+
+		It("calls CreateWithPending on the Store", func() {
+			err := metricsWrapper.CreateWithPending(policies, []store.Policy{}, pending, store.Actor{Name: "some-user"})
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(fakeStore.CheckDatabaseCallCount()).To(Equal(1))
+			Expect(fakeStore.CreateWithPendingCallCount()).To(Equal(1))
+			created, replaced, returnedPending, actor := fakeStore.CreateWithPendingArgsForCall(0)
+			Expect(created).To(Equal(policies))
+			Expect(replaced).To(BeEmpty())
+			Expect(returnedPending).To(Equal(pending))
+			Expect(actor).To(Equal(store.Actor{Name: "some-user"}))
 		})
 
 		It("emits a metric", func() {
-			err := metricsWrapper.CheckDatabase()
+			err := metricsWrapper.CreateWithPending(policies, nil, pending, store.Actor{})
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
 			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
-			Expect(name).To(Equal("StoreCheckDatabaseSuccessTime"))
+			Expect(name).To(Equal("StoreCreateWithPendingSuccessTime"))
 		})
 
 		Context("when there is an error", func() {
 			BeforeEach(func() {
-				fakeStore.CheckDatabaseReturns(errors.New("huckleberry"))
+				fakeStore.CreateWithPendingReturns(errors.New("banana"))
 			})
 			It("emits an error metric", func() {
-				err := metricsWrapper.CheckDatabase()
-				Expect(err).To(MatchError("huckleberry"))
+				err := metricsWrapper.CreateWithPending(policies, nil, pending, store.Actor{})
+				Expect(err).To(MatchError("banana"))
 
 				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
-				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreCheckDatabaseError"))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreCreateWithPendingError"))
 
 				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
 				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
-				Expect(name).To(Equal("StoreCheckDatabaseErrorTime"))
+				Expect(name).To(Equal("StoreCreateWithPendingErrorTime"))
 			})
 		})
 	})
 
-	Describe("Delete", func() {
-		It("calls Delete on the Store", func() {
-			err := metricsWrapper.Delete(policies)
+	Describe("ApprovePending", func() {
+		var pending store.PendingPolicy
+
+		BeforeEach(func() {
+			pending = store.PendingPolicy{ID: 3, Policy: policies[0], RequestedBy: "some-user"}
+		})
+
+		It("calls ApprovePending on the Store", func() {
+			err := metricsWrapper.ApprovePending(pending, store.Actor{Name: "some-approver"})
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(fakeStore.DeleteCallCount()).To(Equal(1))
-			passedPolicies := fakeStore.DeleteArgsForCall(0)
-			Expect(passedPolicies).To(Equal(policies))
+			Expect(fakeStore.ApprovePendingCallCount()).To(Equal(1))
+			approved, approver := fakeStore.ApprovePendingArgsForCall(0)
+			Expect(approved).To(Equal(pending))
+			Expect(approver).To(Equal(store.Actor{Name: "some-approver"}))
 		})
 
 		It("emits a metric", func() {
-			err := metricsWrapper.Delete(policies)
+			err := metricsWrapper.ApprovePending(pending, store.Actor{})
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
 			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
-			Expect(name).To(Equal("StoreDeleteSuccessTime"))
+			Expect(name).To(Equal("StoreApprovePendingSuccessTime"))
 		})
 
 		Context("when there is an error", func() {
 			BeforeEach(func() {
-				fakeStore.DeleteReturns(errors.New("banana"))
+				fakeStore.ApprovePendingReturns(errors.New("banana"))
 			})
 			It("emits an error metric", func() {
-				err := metricsWrapper.Delete(policies)
+				err := metricsWrapper.ApprovePending(pending, store.Actor{})
 				Expect(err).To(MatchError("banana"))
 
 				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
-				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreDeleteError"))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreApprovePendingError"))
 
 				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
 				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
-				Expect(name).To(Equal("StoreDeleteErrorTime"))
+				Expect(name).To(Equal("StoreApprovePendingErrorTime"))
 			})
 		})
 	})
 
-	Describe("LastUpdated", func() {
+	Describe("CreatePending", func() {
+		var pending []store.PendingPolicy
+
 		BeforeEach(func() {
-			fakeStore.LastUpdatedReturns(12345, nil)
+			pending = []store.PendingPolicy{{Policy: policies[0], DestinationSpaceGUID: "some-space-guid"}}
 		})
 
-		It("calls LastUpdated on the Store", func() {
-			timestamp, err := metricsWrapper.LastUpdated()
+		It("calls CreatePending on the PendingStore", func() {
+			err := metricsWrapper.CreatePending(pending, store.Actor{Name: "some-user"})
 			Expect(err).NotTo(HaveOccurred())
-			Expect(timestamp).To(Equal(12345))
 
-			Expect(fakeStore.LastUpdatedCallCount()).To(Equal(1))
+			Expect(fakePendingStore.CreatePendingCallCount()).To(Equal(1))
+			returnedPending, actor := fakePendingStore.CreatePendingArgsForCall(0)
+			Expect(returnedPending).To(Equal(pending))
+			Expect(actor).To(Equal(store.Actor{Name: "some-user"}))
 		})
 
 		It("emits a metric", func() {
-			_, err := metricsWrapper.LastUpdated()
+			err := metricsWrapper.CreatePending(pending, store.Actor{})
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
 			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
-			Expect(name).To(Equal("StoreLastUpdatedSuccessTime"))
+			Expect(name).To(Equal("StoreCreatePendingSuccessTime"))
 		})
 
 		Context("when there is an error", func() {
 			BeforeEach(func() {
-				fakeStore.LastUpdatedReturns(0, errors.New("banana"))
+				fakePendingStore.CreatePendingReturns(errors.New("banana"))
 			})
 			It("emits an error metric", func() {
-				_, err := metricsWrapper.LastUpdated()
+				err := metricsWrapper.CreatePending(pending, store.Actor{})
 				Expect(err).To(MatchError("banana"))
 
 				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
-				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreLastUpdatedError"))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreCreatePendingError"))
 
 				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
 				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
-				Expect(name).To(Equal("StoreLastUpdatedErrorTime"))
+				Expect(name).To(Equal("StoreCreatePendingErrorTime"))
 			})
 		})
 	})
 
-	Describe("Tags", func() {
+	Describe("PendingPolicies", func() {
+		var pending []store.PendingPolicy
+
 		BeforeEach(func() {
-			fakeTagStore.TagsReturns(tags, nil)
+			pending = []store.PendingPolicy{{ID: 1, Policy: policies[0]}}
+			fakePendingStore.PendingPoliciesReturns(pending, nil)
 		})
-		It("calls Tags on the Store", func() {
-			returnedTags, err := metricsWrapper.Tags()
+
+		It("returns the result of PendingPolicies on the PendingStore", func() {
+			returnedPending, err := metricsWrapper.PendingPolicies()
 			Expect(err).NotTo(HaveOccurred())
-			Expect(returnedTags).To(Equal(tags))
+			Expect(returnedPending).To(Equal(pending))
 
-			Expect(fakeTagStore.TagsCallCount()).To(Equal(1))
+			Expect(fakePendingStore.PendingPoliciesCallCount()).To(Equal(1))
 		})
 
 		It("emits a metric", func() {
-			_, err := metricsWrapper.Tags()
+			_, err := metricsWrapper.PendingPolicies()
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
 			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
-			Expect(name).To(Equal("StoreTagsSuccessTime"))
+			Expect(name).To(Equal("StorePendingPoliciesSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakePendingStore.PendingPoliciesReturns(nil, errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				_, err := metricsWrapper.PendingPolicies()
+				Expect(err).To(MatchError("banana"))
+
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StorePendingPoliciesError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StorePendingPoliciesErrorTime"))
+			})
+		})
+	})
+
+	Describe("PendingPolicy", func() {
+		var pending *store.PendingPolicy
+
+		BeforeEach(func() {
+			pending = &store.PendingPolicy{ID: 1, Policy: policies[0]}
+			fakePendingStore.PendingPolicyReturns(pending, nil)
+		})
+
+		It("returns the result of PendingPolicy on the PendingStore", func() {
+			returnedPending, err := metricsWrapper.PendingPolicy(1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(returnedPending).To(Equal(pending))
+
+			Expect(fakePendingStore.PendingPolicyCallCount()).To(Equal(1))
+			Expect(fakePendingStore.PendingPolicyArgsForCall(0)).To(Equal(1))
+		})
+
+		It("emits a metric", func() {
+			_, err := metricsWrapper.PendingPolicy(1)
+			Expect(err).NotTo(HaveOccurred())
 
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StorePendingPolicySuccessTime"))
 		})
 
 		Context("when there is an error", func() {
 			BeforeEach(func() {
-				fakeTagStore.TagsReturns(nil, errors.New("banana"))
+				fakePendingStore.PendingPolicyReturns(nil, errors.New("banana"))
 			})
 			It("emits an error metric", func() {
-				_, err := metricsWrapper.Tags()
+				_, err := metricsWrapper.PendingPolicy(1)
 				Expect(err).To(MatchError("banana"))
 
 				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
-				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreTagsError"))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StorePendingPolicyError"))
 
 				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
 				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
-				Expect(name).To(Equal("StoreTagsErrorTime"))
+				Expect(name).To(Equal("StorePendingPolicyErrorTime"))
+			})
+		})
+	})
+
+	Describe("DeletePending", func() {
+		It("calls DeletePending on the PendingStore", func() {
+			err := metricsWrapper.DeletePending(1)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePendingStore.DeletePendingCallCount()).To(Equal(1))
+			Expect(fakePendingStore.DeletePendingArgsForCall(0)).To(Equal(1))
+		})
+
+		It("emits a metric", func() {
+			err := metricsWrapper.DeletePending(1)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+			name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+			Expect(name).To(Equal("StoreDeletePendingSuccessTime"))
+		})
+
+		Context("when there is an error", func() {
+			BeforeEach(func() {
+				fakePendingStore.DeletePendingReturns(errors.New("banana"))
+			})
+			It("emits an error metric", func() {
+				err := metricsWrapper.DeletePending(1)
+				Expect(err).To(MatchError("banana"))
 
+				Expect(fakeMetricsSender.IncrementCounterCallCount()).To(Equal(1))
+				Expect(fakeMetricsSender.IncrementCounterArgsForCall(0)).To(Equal("StoreDeletePendingError"))
+
+				Expect(fakeMetricsSender.SendDurationCallCount()).To(Equal(1))
+				name, _ := fakeMetricsSender.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("StoreDeletePendingErrorTime"))
 			})
 		})
 	})
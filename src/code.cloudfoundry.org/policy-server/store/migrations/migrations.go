@@ -424,4 +424,56 @@ var MigrationsToPerform = PolicyServerMigrations{
 		Id: "79",
 		Up: migration_v0079,
 	},
+	PolicyServerMigration{
+		Id: "80",
+		Up: migration_v0080,
+	},
+	PolicyServerMigration{
+		Id: "81",
+		Up: migration_v0081,
+	},
+	PolicyServerMigration{
+		Id: "82",
+		Up: migration_v0082,
+	},
+	PolicyServerMigration{
+		Id: "83",
+		Up: migration_v0083,
+	},
+	PolicyServerMigration{
+		Id: "84",
+		Up: migration_v0084,
+	},
+	PolicyServerMigration{
+		Id: "85",
+		Up: migration_v0085,
+	},
+	PolicyServerMigration{
+		Id: "86",
+		Up: migration_v0086,
+	},
+	PolicyServerMigration{
+		Id: "87",
+		Up: migration_v0087,
+	},
+	PolicyServerMigration{
+		Id: "88",
+		Up: migration_v0088,
+	},
+	PolicyServerMigration{
+		Id: "89",
+		Up: migration_v0089,
+	},
+	PolicyServerMigration{
+		Id: "90",
+		Up: migration_v0090,
+	},
+	PolicyServerMigration{
+		Id: "91",
+		Up: migration_v0091,
+	},
+	PolicyServerMigration{
+		Id: "92",
+		Up: migration_v0092,
+	},
 }
@@ -1991,6 +1991,285 @@ var _ = Describe("migrations", func() {
 			})
 		})
 
+		Describe("V80 - V81 - add policy_events table", func() {
+			It("should migrate", func() {
+				By("performing migration")
+				migrateTo("81")
+
+				By("inserting a policy event")
+				_, err := realDb.Exec(`
+					INSERT INTO policy_events
+					(action, actor, client_id, policies, app_guids)
+					VALUES ('create', 'some-user', 'some-client', '[]', '["app-a", "app-b"]')`)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("defaulting the created_at timestamp")
+				var createdAt time.Time
+				err = realDb.QueryRow(`SELECT created_at FROM policy_events`).Scan(&createdAt)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(createdAt).NotTo(BeZero())
+			})
+		})
+
+		Describe("V82 - add group_members table", func() {
+			It("should migrate", func() {
+				By("performing migration")
+				migrateTo("82")
+
+				_, err := realDb.Exec(`INSERT INTO "groups" (guid, type) VALUES ('some-space-guid', 'space'), ('some-app-guid', 'app')`)
+				Expect(err).NotTo(HaveOccurred())
+
+				var spaceID, appID int
+				err = realDb.QueryRow(`SELECT id FROM "groups" WHERE guid = 'some-space-guid'`).Scan(&spaceID)
+				Expect(err).NotTo(HaveOccurred())
+				err = realDb.QueryRow(`SELECT id FROM "groups" WHERE guid = 'some-app-guid'`).Scan(&appID)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("inserting a group member")
+				_, err = realDb.Exec(realDb.Rebind(`INSERT INTO group_members (group_id, member_group_id) VALUES (?, ?)`), spaceID, appID)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("not allowing the same member twice")
+				_, err = realDb.Exec(realDb.Rebind(`INSERT INTO group_members (group_id, member_group_id) VALUES (?, ?)`), spaceID, appID)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Describe("V83 - add policy_metadata table", func() {
+			It("should migrate", func() {
+				By("performing migration")
+				migrateTo("83")
+
+				By("inserting a policy with metadata")
+				_, err := realDb.Exec(`INSERT INTO policies (group_id, destination_id) VALUES (NULL, NULL)`)
+				Expect(err).NotTo(HaveOccurred())
+
+				var policyID int
+				err = realDb.QueryRow(`SELECT id FROM policies`).Scan(&policyID)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = realDb.Exec(realDb.Rebind(`
+					INSERT INTO policy_metadata
+					(policy_id, description, labels, annotations)
+					VALUES (?, 'some-description', '{"team": "a"}', '{}')`), policyID)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("deleting the metadata with the policy")
+				_, err = realDb.Exec(realDb.Rebind(`DELETE FROM policies WHERE id = ?`), policyID)
+				Expect(err).NotTo(HaveOccurred())
+
+				var count int
+				err = realDb.QueryRow(`SELECT COUNT(*) FROM policy_metadata`).Scan(&count)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(Equal(0))
+			})
+		})
+
+		Describe("V84 - add expires_at to policies", func() {
+			It("should migrate", func() {
+				By("performing migration")
+				migrateTo("84")
+
+				By("defaulting expires_at to null")
+				_, err := realDb.Exec(`INSERT INTO policies (group_id, destination_id) VALUES (NULL, NULL)`)
+				Expect(err).NotTo(HaveOccurred())
+
+				var expiresAt sql.NullTime
+				err = realDb.QueryRow(`SELECT expires_at FROM policies`).Scan(&expiresAt)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(expiresAt.Valid).To(BeFalse())
+
+				By("storing an expiry")
+				expiry := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+				_, err = realDb.Exec(realDb.Rebind(`UPDATE policies SET expires_at = ?`), expiry)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = realDb.QueryRow(`SELECT expires_at FROM policies`).Scan(&expiresAt)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(expiresAt.Time.Equal(expiry)).To(BeTrue())
+			})
+		})
+
+		Describe("V85 - add policy_quotas table", func() {
+			It("should migrate", func() {
+				By("performing migration")
+				migrateTo("85")
+
+				By("inserting a quota")
+				_, err := realDb.Exec(`INSERT INTO policy_quotas (type, guid, max_policies) VALUES ('space', 'some-space-guid', 10)`)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("allowing one quota per type and guid")
+				_, err = realDb.Exec(`INSERT INTO policy_quotas (type, guid, max_policies) VALUES ('org', 'some-space-guid', 10)`)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = realDb.Exec(`INSERT INTO policy_quotas (type, guid, max_policies) VALUES ('space', 'some-space-guid', 20)`)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Describe("V86 - add icmp type and code to destinations", func() {
+			It("should migrate", func() {
+				By("performing migration")
+				migrateTo("86")
+
+				_, err := realDb.Exec(`insert into "groups" (guid) values ('some-guid')`)
+				Expect(err).NotTo(HaveOccurred())
+
+				var groupID int
+				err = realDb.QueryRow(`SELECT id FROM "groups" WHERE guid = 'some-guid'`).Scan(&groupID)
+				Expect(err).NotTo(HaveOccurred())
+
+				insertDestination := func(protocol string, port, icmpType, icmpCode int) error {
+					_, err := realDb.Exec(realDb.Rebind(`
+						INSERT INTO destinations (group_id, port, start_port, end_port, protocol, icmp_type, icmp_code)
+						VALUES (?, ?, ?, ?, ?, ?, ?)`), groupID, port, port, port, protocol, icmpType, icmpCode)
+					return err
+				}
+
+				By("defaulting the icmp type and code to 0")
+				_, err = realDb.Exec(realDb.Rebind(`INSERT INTO destinations (group_id, port, start_port, end_port, protocol) VALUES (?, 8080, 8080, 8080, 'tcp')`), groupID)
+				Expect(err).NotTo(HaveOccurred())
+
+				var icmpType, icmpCode int
+				err = realDb.QueryRow(`SELECT icmp_type, icmp_code FROM destinations`).Scan(&icmpType, &icmpCode)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(icmpType).To(Equal(0))
+				Expect(icmpCode).To(Equal(0))
+
+				By("allowing icmp destinations that only differ by type")
+				Expect(insertDestination("icmp", 0, 8, 0)).To(Succeed())
+				Expect(insertDestination("icmp", 0, 0, 0)).To(Succeed())
+
+				By("rejecting duplicate destinations")
+				Expect(insertDestination("icmp", 0, 8, 0)).NotTo(Succeed())
+			})
+		})
+
+		Describe("V87 - add action to policies", func() {
+			It("should migrate", func() {
+				By("performing migration")
+				migrateTo("87")
+
+				_, err := realDb.Exec(`insert into "groups" (guid) values ('some-guid')`)
+				Expect(err).NotTo(HaveOccurred())
+
+				var groupID int
+				err = realDb.QueryRow(`SELECT id FROM "groups" WHERE guid = 'some-guid'`).Scan(&groupID)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = realDb.Exec(realDb.Rebind(`INSERT INTO destinations (group_id, port, start_port, end_port, protocol) VALUES (?, 8080, 8080, 8080, 'tcp')`), groupID)
+				Expect(err).NotTo(HaveOccurred())
+
+				var destinationID int
+				err = realDb.QueryRow(`SELECT id FROM destinations`).Scan(&destinationID)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("defaulting the action to allow")
+				_, err = realDb.Exec(realDb.Rebind(`INSERT INTO policies (group_id, destination_id) VALUES (?, ?)`), groupID, destinationID)
+				Expect(err).NotTo(HaveOccurred())
+
+				var action string
+				err = realDb.QueryRow(`SELECT action FROM policies`).Scan(&action)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(action).To(Equal("allow"))
+			})
+		})
+
+		Describe("V88 - add quarantined to groups", func() {
+			It("should migrate", func() {
+				By("performing migration")
+				migrateTo("88")
+
+				By("defaulting quarantined to false")
+				_, err := realDb.Exec(`insert into "groups" (guid) values ('some-guid')`)
+				Expect(err).NotTo(HaveOccurred())
+
+				var quarantined bool
+				err = realDb.QueryRow(`SELECT quarantined FROM "groups" WHERE guid = 'some-guid'`).Scan(&quarantined)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(quarantined).To(BeFalse())
+
+				_, err = realDb.Exec(`UPDATE "groups" SET quarantined = true WHERE guid = 'some-guid'`)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = realDb.QueryRow(`SELECT quarantined FROM "groups" WHERE guid = 'some-guid'`).Scan(&quarantined)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(quarantined).To(BeTrue())
+			})
+		})
+
+		Describe("V89 - add pending_policies table", func() {
+			It("should migrate", func() {
+				By("performing migration")
+				migrateTo("89")
+
+				By("inserting a pending policy")
+				_, err := realDb.Exec(`INSERT INTO pending_policies (policy, destination_space_guid, requested_by, client_id) VALUES ('{}', 'some-space-guid', 'some-user', 'some-client')`)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("generating ids and creation times")
+				var id int
+				var createdAt time.Time
+				err = realDb.QueryRow(`SELECT id, created_at FROM pending_policies`).Scan(&id, &createdAt)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(id).To(BeNumerically(">", 0))
+				Expect(createdAt).NotTo(BeZero())
+			})
+		})
+
+		Describe("V90 - add space and org guids to groups", func() {
+			It("should migrate", func() {
+				By("performing migration")
+				migrateTo("90")
+
+				_, err := realDb.Exec(`INSERT INTO "groups" (guid, type, space_guid, org_guid) VALUES ('some-app-guid', 'app', 'some-space-guid', 'some-org-guid')`)
+				Expect(err).NotTo(HaveOccurred())
+
+				var spaceGuid, orgGuid string
+				err = realDb.QueryRow(`SELECT space_guid, org_guid FROM "groups" WHERE guid = 'some-app-guid'`).Scan(&spaceGuid, &orgGuid)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spaceGuid).To(Equal("some-space-guid"))
+				Expect(orgGuid).To(Equal("some-org-guid"))
+			})
+		})
+
+		Describe("V91 - add index on the expiry of policies", func() {
+			It("should migrate", func() {
+				By("performing migration")
+				migrateTo("91")
+
+				var count int
+				switch realDb.DriverName() {
+				case "mysql":
+					err := realDb.QueryRow(`SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = 'policies' AND index_name = 'idx_policies_expires_at'`).Scan(&count)
+					Expect(err).NotTo(HaveOccurred())
+				case "postgres":
+					err := realDb.QueryRow(`SELECT COUNT(*) FROM pg_indexes WHERE tablename = 'policies' AND indexname = 'idx_policies_expires_at'`).Scan(&count)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(count).To(Equal(1))
+			})
+		})
+
+		Describe("V92 - add the approver to policy events", func() {
+			It("should migrate", func() {
+				By("performing migration")
+				migrateTo("92")
+
+				_, err := realDb.Exec(`
+					INSERT INTO policy_events
+					(action, actor, client_id, policies, app_guids)
+					VALUES ('create', 'some-user', 'some-client', '[]', '[]')`)
+				Expect(err).NotTo(HaveOccurred())
+
+				var approvedBy string
+				err = realDb.QueryRow(`SELECT approved_by FROM policy_events`).Scan(&approvedBy)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(approvedBy).To(BeEmpty())
+			})
+		})
+
 		Context("when migrating in parallel", func() {
 			Context("mysql", func() {
 				BeforeEach(func() {
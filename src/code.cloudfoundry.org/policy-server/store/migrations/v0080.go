@@ -0,0 +1,29 @@
+package migrations
+
+// Adding policy events table to keep an audit log of
+// who created and deleted policies
+
+var migration_v0080 = map[string][]string{
+	"mysql": {
+		`CREATE TABLE IF NOT EXISTS policy_events (
+			id bigint NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			action varchar(255) NOT NULL,
+			actor varchar(255) NOT NULL,
+			client_id varchar(255) NOT NULL,
+			policies mediumtext NOT NULL,
+			app_guids json NOT NULL,
+			created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+		);`,
+	},
+	"postgres": {
+		`CREATE TABLE IF NOT EXISTS policy_events (
+			id BIGSERIAL PRIMARY KEY,
+			action varchar(255) NOT NULL,
+			actor varchar(255) NOT NULL,
+			client_id varchar(255) NOT NULL,
+			policies text NOT NULL,
+			app_guids jsonb NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);`,
+	},
+}
@@ -0,0 +1,12 @@
+package migrations
+
+// Adding an index to query policy events by time
+
+var migration_v0081 = map[string][]string{
+	"mysql": {
+		`CREATE INDEX idx_policy_events_created_at ON policy_events (created_at);`,
+	},
+	"postgres": {
+		`CREATE INDEX idx_policy_events_created_at ON policy_events (created_at);`,
+	},
+}
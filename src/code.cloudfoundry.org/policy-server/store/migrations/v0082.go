@@ -0,0 +1,27 @@
+package migrations
+
+// Adding group members table for the apps of the spaces and orgs that are the
+// sources of policies, so that the internal API can list a policy for each app
+
+var migration_v0082 = map[string][]string{
+	"mysql": {
+		`CREATE TABLE IF NOT EXISTS group_members (
+			id int NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			group_id int NOT NULL,
+			member_group_id int NOT NULL,
+			UNIQUE KEY unique_group_member (group_id, member_group_id),
+			KEY idx_group_members_member_group_id (member_group_id),
+			FOREIGN KEY (group_id) REFERENCES "groups"(id),
+			FOREIGN KEY (member_group_id) REFERENCES "groups"(id)
+		);`,
+	},
+	"postgres": {
+		`CREATE TABLE IF NOT EXISTS group_members (
+			id SERIAL PRIMARY KEY,
+			group_id int NOT NULL REFERENCES "groups"(id),
+			member_group_id int NOT NULL REFERENCES "groups"(id),
+			CONSTRAINT unique_group_member UNIQUE (group_id, member_group_id)
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_group_members_member_group_id ON group_members (member_group_id);`,
+	},
+}
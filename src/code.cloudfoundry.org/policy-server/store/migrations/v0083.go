@@ -0,0 +1,26 @@
+package migrations
+
+// Adding policy metadata table for descriptions, labels and annotations
+
+var migration_v0083 = map[string][]string{
+	"mysql": {
+		`CREATE TABLE IF NOT EXISTS policy_metadata (
+			policy_id int NOT NULL PRIMARY KEY,
+			description varchar(255) NOT NULL DEFAULT '',
+			labels mediumtext NOT NULL,
+			annotations mediumtext NOT NULL,
+			CONSTRAINT policy_metadata_policy_id_fk
+				FOREIGN KEY (policy_id)
+				REFERENCES policies(id)
+				ON DELETE CASCADE
+		);`,
+	},
+	"postgres": {
+		`CREATE TABLE IF NOT EXISTS policy_metadata (
+			policy_id int NOT NULL PRIMARY KEY REFERENCES policies(id) ON DELETE CASCADE,
+			description varchar(255) NOT NULL DEFAULT '',
+			labels text NOT NULL,
+			annotations text NOT NULL
+		);`,
+	},
+}
@@ -0,0 +1,13 @@
+package migrations
+
+// Adding an expiry to policies so that temporary access is removed by the
+// policy cleaner
+
+var migration_v0084 = map[string][]string{
+	"mysql": {
+		`ALTER TABLE policies ADD COLUMN expires_at TIMESTAMP NULL DEFAULT NULL;`,
+	},
+	"postgres": {
+		`ALTER TABLE policies ADD COLUMN expires_at TIMESTAMP NULL DEFAULT NULL;`,
+	},
+}
@@ -0,0 +1,22 @@
+package migrations
+
+// Adding policy quotas table to limit the number of policies per space and org
+
+var migration_v0085 = map[string][]string{
+	"mysql": {
+		`CREATE TABLE IF NOT EXISTS policy_quotas (
+			type varchar(255) NOT NULL,
+			guid varchar(255) NOT NULL,
+			max_policies int NOT NULL,
+			PRIMARY KEY (type, guid)
+		);`,
+	},
+	"postgres": {
+		`CREATE TABLE IF NOT EXISTS policy_quotas (
+			type varchar(255) NOT NULL,
+			guid varchar(255) NOT NULL,
+			max_policies int NOT NULL,
+			PRIMARY KEY (type, guid)
+		);`,
+	},
+}
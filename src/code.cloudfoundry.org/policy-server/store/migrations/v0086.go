@@ -0,0 +1,22 @@
+package migrations
+
+// Adding icmp type and code to destinations so that policies can allow icmp.
+// They are part of the unique key, as an app may be the destination of icmp
+// policies for several types.
+
+var migration_v0086 = map[string][]string{
+	"mysql": {
+		`ALTER TABLE destinations ADD COLUMN icmp_type int NOT NULL DEFAULT 0;`,
+		`ALTER TABLE destinations ADD COLUMN icmp_code int NOT NULL DEFAULT 0;`,
+		`ALTER TABLE destinations
+		DROP INDEX unique_destination,
+		ADD UNIQUE KEY unique_destination (group_id, start_port, end_port, protocol, icmp_type, icmp_code);`,
+	},
+	"postgres": {
+		`ALTER TABLE destinations ADD COLUMN icmp_type int NOT NULL DEFAULT 0;`,
+		`ALTER TABLE destinations ADD COLUMN icmp_code int NOT NULL DEFAULT 0;`,
+		`ALTER TABLE destinations
+		DROP CONSTRAINT unique_destination,
+		ADD CONSTRAINT unique_destination UNIQUE (group_id, start_port, end_port, protocol, icmp_type, icmp_code);`,
+	},
+}
@@ -0,0 +1,13 @@
+package migrations
+
+// Adding an action to policies so that deny policies can override allow
+// policies
+
+var migration_v0087 = map[string][]string{
+	"mysql": {
+		`ALTER TABLE policies ADD COLUMN action varchar(16) NOT NULL DEFAULT 'allow';`,
+	},
+	"postgres": {
+		`ALTER TABLE policies ADD COLUMN action varchar(16) NOT NULL DEFAULT 'allow';`,
+	},
+}
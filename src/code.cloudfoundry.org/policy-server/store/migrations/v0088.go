@@ -0,0 +1,13 @@
+package migrations
+
+// Adding a quarantined flag to groups so that all policies of an app can be
+// suppressed without deleting them
+
+var migration_v0088 = map[string][]string{
+	"mysql": {
+		`ALTER TABLE "groups" ADD COLUMN quarantined bool NOT NULL DEFAULT false;`,
+	},
+	"postgres": {
+		`ALTER TABLE groups ADD COLUMN quarantined bool NOT NULL DEFAULT false;`,
+	},
+}
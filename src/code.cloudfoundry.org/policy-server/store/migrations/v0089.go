@@ -0,0 +1,27 @@
+package migrations
+
+// Adding pending policies table for policies to apps of other spaces that
+// wait for a developer of the destination space to approve them
+
+var migration_v0089 = map[string][]string{
+	"mysql": {
+		`CREATE TABLE IF NOT EXISTS pending_policies (
+			id bigint NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			policy mediumtext NOT NULL,
+			destination_space_guid varchar(255) NOT NULL,
+			requested_by varchar(255) NOT NULL,
+			client_id varchar(255) NOT NULL,
+			created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+		);`,
+	},
+	"postgres": {
+		`CREATE TABLE IF NOT EXISTS pending_policies (
+			id BIGSERIAL PRIMARY KEY,
+			policy text NOT NULL,
+			destination_space_guid varchar(255) NOT NULL,
+			requested_by varchar(255) NOT NULL,
+			client_id varchar(255) NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);`,
+	},
+}
@@ -0,0 +1,20 @@
+package migrations
+
+// Adding the space and org of the sources of policies to groups, so that the
+// policies of a space or org can be counted for its quota without asking the
+// Cloud-Controller for the space of every app
+
+var migration_v0090 = map[string][]string{
+	"mysql": {
+		`ALTER TABLE "groups" ADD COLUMN space_guid varchar(255);`,
+		`ALTER TABLE "groups" ADD COLUMN org_guid varchar(255);`,
+		`CREATE INDEX idx_groups_space_guid ON "groups" (space_guid);`,
+		`CREATE INDEX idx_groups_org_guid ON "groups" (org_guid);`,
+	},
+	"postgres": {
+		`ALTER TABLE groups ADD COLUMN space_guid varchar(255);`,
+		`ALTER TABLE groups ADD COLUMN org_guid varchar(255);`,
+		`CREATE INDEX IF NOT EXISTS idx_groups_space_guid ON groups (space_guid);`,
+		`CREATE INDEX IF NOT EXISTS idx_groups_org_guid ON groups (org_guid);`,
+	},
+}
@@ -0,0 +1,13 @@
+package migrations
+
+// Adding an index on the expiry of policies, so that counting the expired
+// policies on every poll of the internal API does not scan every policy
+
+var migration_v0091 = map[string][]string{
+	"mysql": {
+		`CREATE INDEX idx_policies_expires_at ON policies (expires_at);`,
+	},
+	"postgres": {
+		`CREATE INDEX IF NOT EXISTS idx_policies_expires_at ON policies (expires_at);`,
+	},
+}
@@ -0,0 +1,13 @@
+package migrations
+
+// Adding the approver to policy events, so that the audit log of a pending
+// policy shows both who requested it and who approved it
+
+var migration_v0092 = map[string][]string{
+	"mysql": {
+		`ALTER TABLE policy_events ADD COLUMN approved_by varchar(255) NOT NULL DEFAULT '';`,
+	},
+	"postgres": {
+		`ALTER TABLE policy_events ADD COLUMN approved_by varchar(255) NOT NULL DEFAULT '';`,
+	},
+}
@@ -1,25 +1,164 @@
 package store
 
+import "time"
+
+const (
+	GroupTypeApp   = "app"
+	GroupTypeSpace = "space"
+	GroupTypeOrg   = "org"
+)
+
+const (
+	PolicyActionAllow = "allow"
+	PolicyActionDeny  = "deny"
+)
+
 type PolicyCollection struct {
 	Policies []Policy
 }
 
+// Policy is the traffic allowed from a source to a destination. Action is
+// empty for allow policies, and "deny" for policies that override them.
 type Policy struct {
 	Source      Source
 	Destination Destination
+	Metadata    Metadata
+	ExpiresAt   *time.Time
+	Action      string
+}
+
+// Equals compares policies ignoring tags, which are only assigned once a
+// policy has been stored, and metadata, expiry and action, which are
+// attributes of the policy that can be changed.
+func (p Policy) Equals(other Policy) bool {
+	return p.Source.ID == other.Source.ID &&
+		p.Source.GroupType() == other.Source.GroupType() &&
+		p.Destination.ID == other.Destination.ID &&
+		p.Destination.Protocol == other.Destination.Protocol &&
+		p.Destination.Port == other.Destination.Port &&
+		p.Destination.Ports == other.Destination.Ports &&
+		p.Destination.ICMPType == other.Destination.ICMPType &&
+		p.Destination.ICMPCode == other.Destination.ICMPCode
+}
+
+// EqualsWithAction compares policies like Equals, and also tells allow and
+// deny policies apart.
+func (p Policy) EqualsWithAction(other Policy) bool {
+	return p.Equals(other) && p.IsDeny() == other.IsDeny()
+}
+
+// AttributesEqual compares the metadata, expiry and action of policies.
+func (p Policy) AttributesEqual(other Policy) bool {
+	return p.Metadata.Equals(other.Metadata) &&
+		expiriesEqual(p.ExpiresAt, other.ExpiresAt) &&
+		p.Action == other.Action
+}
+
+// IsDeny returns true if the policy denies the traffic that it matches.
+func (p Policy) IsDeny() bool {
+	return p.Action == PolicyActionDeny
+}
+
+// Overrides returns true if the policy is a deny policy that blocks some of
+// the traffic that the other policy allows.
+func (p Policy) Overrides(other Policy) bool {
+	return p.IsDeny() && !other.IsDeny() &&
+		p.Source.ID == other.Source.ID &&
+		p.Source.GroupType() == other.Source.GroupType() &&
+		p.Destination.ID == other.Destination.ID &&
+		p.Destination.trafficOverlaps(other.Destination)
+}
+
+// IsExpired returns true if the policy has an expiry that is not after now.
+func (p Policy) IsExpired(now time.Time) bool {
+	return p.ExpiresAt != nil && !p.ExpiresAt.After(now)
+}
+
+func expiriesEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
 }
 
+// Source is the app, space or org that a policy allows traffic from. Type is
+// empty for apps so that app policies look the same as they always have.
 type Source struct {
-	ID  string
-	Tag string
+	ID   string
+	Tag  string
+	Type string
+}
+
+// GroupType returns the type of the group that the source is stored as.
+func (s Source) GroupType() string {
+	if s.Type == "" {
+		return GroupTypeApp
+	}
+	return s.Type
 }
 
+// Destination is the app and the traffic that a policy allows. ICMPType and
+// ICMPCode are only set for icmp, where -1 allows any type or code. A policy
+// for all protocols covers ports 1-65535.
 type Destination struct {
 	ID       string
 	Tag      string
 	Protocol string
 	Port     int
 	Ports    Ports
+	ICMPType int
+	ICMPCode int
+}
+
+// trafficOverlaps returns true if some traffic matches both destinations,
+// ignoring the apps. An ICMP type or code of -1 matches any.
+func (d Destination) trafficOverlaps(other Destination) bool {
+	if d.Protocol == "all" || other.Protocol == "all" {
+		return true
+	}
+	if d.Protocol != other.Protocol {
+		return false
+	}
+	if d.Protocol == "icmp" {
+		return icmpValuesOverlap(d.ICMPType, other.ICMPType) && icmpValuesOverlap(d.ICMPCode, other.ICMPCode)
+	}
+	return d.Ports.Start <= other.Ports.End && other.Ports.Start <= d.Ports.End
+}
+
+func icmpValuesOverlap(a, b int) bool {
+	return a == -1 || b == -1 || a == b
+}
+
+// Metadata describes a policy. Labels and annotations follow the Cloud
+// Controller v3 metadata conventions.
+type Metadata struct {
+	Description string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+func (m Metadata) IsEmpty() bool {
+	return m.Description == "" && len(m.Labels) == 0 && len(m.Annotations) == 0
+}
+
+// Equals compares metadata treating nil and empty label and annotation maps
+// as the same.
+func (m Metadata) Equals(other Metadata) bool {
+	return m.Description == other.Description &&
+		stringMapsEqual(m.Labels, other.Labels) &&
+		stringMapsEqual(m.Annotations, other.Annotations)
+}
+
+func stringMapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
 }
 
 type Ports struct {
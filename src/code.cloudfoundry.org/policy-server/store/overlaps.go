@@ -0,0 +1,142 @@
+package store
+
+import (
+	"sort"
+	"time"
+)
+
+// PolicyOverlap is a set of tcp or udp policies between the same source and
+// destination whose port ranges overlap or are adjacent, and the single policy
+// that covers all of them.
+type PolicyOverlap struct {
+	Policies []Policy
+	Merged   Policy
+}
+
+// MetadataConflicts returns true if policies of the overlap have different
+// metadata. The merged policy cannot keep all of it, so such an overlap is
+// not merged.
+func (o PolicyOverlap) MetadataConflicts() bool {
+	for _, p := range o.Policies {
+		if !p.Metadata.IsEmpty() && !p.Metadata.Equals(o.Merged.Metadata) {
+			return true
+		}
+	}
+	return false
+}
+
+// Replaced returns the policies of the overlap that the merged policy
+// replaces.
+func (o PolicyOverlap) Replaced() []Policy {
+	return policiesNotIn(o.Policies, []Policy{o.Merged})
+}
+
+type overlapKey struct {
+	sourceID      string
+	sourceType    string
+	destinationID string
+	protocol      string
+	action        string
+}
+
+// FindOverlaps groups the tcp and udp policies by source, destination, protocol
+// and action, and returns every set of policies whose port ranges overlap or are
+// adjacent. The merged policy keeps the metadata of the policies that have any,
+// and expires with the last of the policies.
+func FindOverlaps(policies []Policy) []PolicyOverlap {
+	var keys []overlapKey
+	groups := map[overlapKey][]Policy{}
+	for _, p := range policies {
+		if p.Destination.Protocol != "tcp" && p.Destination.Protocol != "udp" {
+			continue
+		}
+		key := overlapKey{
+			sourceID:      p.Source.ID,
+			sourceType:    p.Source.GroupType(),
+			destinationID: p.Destination.ID,
+			protocol:      p.Destination.Protocol,
+			action:        p.Action,
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], p)
+	}
+
+	overlaps := []PolicyOverlap{}
+	for _, key := range keys {
+		group := groups[key]
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].Destination.Ports.Start != group[j].Destination.Ports.Start {
+				return group[i].Destination.Ports.Start < group[j].Destination.Ports.Start
+			}
+			return group[i].Destination.Ports.End < group[j].Destination.Ports.End
+		})
+
+		var current []Policy
+		end := 0
+		for _, p := range group {
+			if len(current) > 0 && p.Destination.Ports.Start <= end+1 {
+				current = append(current, p)
+				if p.Destination.Ports.End > end {
+					end = p.Destination.Ports.End
+				}
+				continue
+			}
+			if len(current) > 1 {
+				overlaps = append(overlaps, newPolicyOverlap(current, end))
+			}
+			current = []Policy{p}
+			end = p.Destination.Ports.End
+		}
+		if len(current) > 1 {
+			overlaps = append(overlaps, newPolicyOverlap(current, end))
+		}
+	}
+	return overlaps
+}
+
+func newPolicyOverlap(policies []Policy, end int) PolicyOverlap {
+	first := policies[0]
+	start := first.Destination.Ports.Start
+	port := 0
+	if start == end {
+		port = start
+	}
+
+	var metadata Metadata
+	for _, p := range policies {
+		if !p.Metadata.IsEmpty() {
+			metadata = p.Metadata
+			break
+		}
+	}
+
+	var expiresAt *time.Time
+	for i, p := range policies {
+		if p.ExpiresAt == nil {
+			expiresAt = nil
+			break
+		}
+		if i == 0 || p.ExpiresAt.After(*expiresAt) {
+			expiresAt = p.ExpiresAt
+		}
+	}
+
+	return PolicyOverlap{
+		Policies: policies,
+		Merged: Policy{
+			Source: first.Source,
+			Destination: Destination{
+				ID:       first.Destination.ID,
+				Tag:      first.Destination.Tag,
+				Protocol: first.Destination.Protocol,
+				Port:     port,
+				Ports:    Ports{Start: start, End: end},
+			},
+			Metadata:  metadata,
+			ExpiresAt: expiresAt,
+			Action:    first.Action,
+		},
+	}
+}
@@ -0,0 +1,138 @@
+package store_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/policy-server/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FindOverlaps", func() {
+	var policy func(srcID, dstID, protocol string, start, end int) store.Policy
+
+	BeforeEach(func() {
+		policy = func(srcID, dstID, protocol string, start, end int) store.Policy {
+			return store.Policy{
+				Source: store.Source{ID: srcID},
+				Destination: store.Destination{
+					ID:       dstID,
+					Protocol: protocol,
+					Ports:    store.Ports{Start: start, End: end},
+				},
+			}
+		}
+	})
+
+	It("finds overlapping and adjacent port ranges and merges them", func() {
+		policies := []store.Policy{
+			policy("some-app", "other-app", "tcp", 8085, 9000),
+			policy("some-app", "other-app", "tcp", 8080, 8090),
+			policy("some-app", "other-app", "tcp", 9001, 9001),
+			policy("some-app", "other-app", "tcp", 9100, 9200),
+		}
+
+		overlaps := store.FindOverlaps(policies)
+		Expect(overlaps).To(Equal([]store.PolicyOverlap{{
+			Policies: []store.Policy{
+				policy("some-app", "other-app", "tcp", 8080, 8090),
+				policy("some-app", "other-app", "tcp", 8085, 9000),
+				policy("some-app", "other-app", "tcp", 9001, 9001),
+			},
+			Merged: policy("some-app", "other-app", "tcp", 8080, 9001),
+		}}))
+	})
+
+	It("does not merge policies of different sources, destinations, protocols or actions", func() {
+		policies := []store.Policy{
+			policy("some-app", "other-app", "tcp", 8080, 8090),
+			policy("some-app", "other-app", "udp", 8085, 9000),
+			policy("some-app", "yet-another-app", "tcp", 8085, 9000),
+			policy("other-app", "other-app", "tcp", 8085, 9000),
+		}
+		spacePolicy := policy("some-app", "other-app", "tcp", 8085, 9000)
+		spacePolicy.Source.Type = "space"
+		denyPolicy := policy("some-app", "other-app", "tcp", 8085, 9000)
+		denyPolicy.Action = store.PolicyActionDeny
+		policies = append(policies, spacePolicy, denyPolicy)
+
+		Expect(store.FindOverlaps(policies)).To(BeEmpty())
+	})
+
+	It("ignores icmp policies and policies for all protocols", func() {
+		policies := []store.Policy{
+			policy("some-app", "other-app", "icmp", 0, 0),
+			policy("some-app", "other-app", "icmp", 0, 0),
+			policy("some-app", "other-app", "all", 1, 65535),
+			policy("some-app", "other-app", "all", 1, 65535),
+		}
+
+		Expect(store.FindOverlaps(policies)).To(BeEmpty())
+	})
+
+	It("sets the single port when the merged range has one port", func() {
+		policies := []store.Policy{
+			policy("some-app", "other-app", "tcp", 8080, 8080),
+			policy("some-app", "other-app", "tcp", 8080, 8080),
+		}
+
+		overlaps := store.FindOverlaps(policies)
+		Expect(overlaps).To(HaveLen(1))
+		Expect(overlaps[0].Merged.Destination.Port).To(Equal(8080))
+	})
+
+	It("keeps the metadata of the policies that have any and the last expiry", func() {
+		earlier := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		later := time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)
+		first := policy("some-app", "other-app", "tcp", 8080, 8090)
+		first.ExpiresAt = &earlier
+		second := policy("some-app", "other-app", "tcp", 8091, 9000)
+		second.Metadata = store.Metadata{Description: "second"}
+		second.ExpiresAt = &later
+
+		overlaps := store.FindOverlaps([]store.Policy{second, first})
+		Expect(overlaps).To(HaveLen(1))
+		Expect(overlaps[0].Merged.Metadata).To(Equal(store.Metadata{Description: "second"}))
+		Expect(overlaps[0].Merged.ExpiresAt).To(Equal(&later))
+		Expect(overlaps[0].MetadataConflicts()).To(BeFalse())
+	})
+
+	Context("when the policies have different metadata", func() {
+		It("reports that the metadata conflicts", func() {
+			first := policy("some-app", "other-app", "tcp", 8080, 8090)
+			first.Metadata = store.Metadata{Description: "first"}
+			second := policy("some-app", "other-app", "tcp", 8091, 9000)
+			second.Metadata = store.Metadata{Description: "second"}
+
+			overlaps := store.FindOverlaps([]store.Policy{second, first})
+			Expect(overlaps).To(HaveLen(1))
+			Expect(overlaps[0].MetadataConflicts()).To(BeTrue())
+		})
+	})
+
+	Context("when one of the policies never expires", func() {
+		It("does not expire the merged policy", func() {
+			expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+			first := policy("some-app", "other-app", "tcp", 8080, 8090)
+			first.ExpiresAt = &expiry
+			second := policy("some-app", "other-app", "tcp", 8085, 9000)
+
+			overlaps := store.FindOverlaps([]store.Policy{first, second})
+			Expect(overlaps).To(HaveLen(1))
+			Expect(overlaps[0].Merged.ExpiresAt).To(BeNil())
+		})
+	})
+
+	Describe("Replaced", func() {
+		It("returns the policies other than the merged one", func() {
+			overlaps := store.FindOverlaps([]store.Policy{
+				policy("some-app", "other-app", "tcp", 8080, 9000),
+				policy("some-app", "other-app", "tcp", 8085, 8090),
+			})
+			Expect(overlaps).To(HaveLen(1))
+			Expect(overlaps[0].Replaced()).To(Equal([]store.Policy{
+				policy("some-app", "other-app", "tcp", 8085, 8090),
+			}))
+		})
+	})
+})
@@ -0,0 +1,161 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/cf-networking-helpers/db"
+)
+
+// PendingPolicy is a policy to an app of another space than its source, which
+// is only created when someone who may change the policies of the destination
+// space approves it
+type PendingPolicy struct {
+	ID                   int
+	Policy               Policy
+	DestinationSpaceGUID string
+	RequestedBy          string
+	ClientID             string
+	CreatedAt            time.Time
+}
+
+//counterfeiter:generate -o fakes/pending_policies_store.go --fake-name PendingPoliciesStore . PendingPoliciesStore
+type PendingPoliciesStore interface {
+	CreatePending([]PendingPolicy, Actor) error
+	PendingPolicies() ([]PendingPolicy, error)
+	PendingPolicy(id int) (*PendingPolicy, error)
+	DeletePending(id int) error
+}
+
+type PendingStore struct {
+	Conn Database
+}
+
+func (ps *PendingStore) CreatePending(pending []PendingPolicy, actor Actor) error {
+	if len(pending) == 0 {
+		return nil
+	}
+	tx, err := ps.Conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("create transaction: %s", err)
+	}
+
+	err = createPendingWithTx(tx, pending, actor)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit transaction: %s", err)
+	}
+	return nil
+}
+
+func createPendingWithTx(tx db.Transaction, pending []PendingPolicy, actor Actor) error {
+	for _, p := range pending {
+		policy, err := json.Marshal(p.Policy)
+		if err != nil {
+			return fmt.Errorf("marshaling pending policy: %s", err) // untested
+		}
+		_, err = tx.Exec(tx.Rebind(`
+			INSERT INTO pending_policies (policy, destination_space_guid, requested_by, client_id)
+			VALUES (?, ?, ?, ?)`),
+			string(policy), p.DestinationSpaceGUID, actor.Name, actor.ClientID)
+		if err != nil {
+			return fmt.Errorf("inserting pending policy: %s", err)
+		}
+	}
+	return nil
+}
+
+func (ps *PendingStore) PendingPolicies() ([]PendingPolicy, error) {
+	rows, err := ps.Conn.Query(`
+		SELECT id, policy, destination_space_guid, requested_by, client_id, created_at
+		FROM pending_policies
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("selecting pending policies: %s", err)
+	}
+	defer rows.Close()
+
+	pending := []PendingPolicy{}
+	for rows.Next() {
+		p, err := scanPendingPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, p)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("selecting pending policies, getting next row: %s", err) // untested
+	}
+	return pending, nil
+}
+
+// PendingPolicy returns nil when there is no pending policy with the id
+func (ps *PendingStore) PendingPolicy(id int) (*PendingPolicy, error) {
+	row := ps.Conn.QueryRow(ps.Conn.Rebind(`
+		SELECT id, policy, destination_space_guid, requested_by, client_id, created_at
+		FROM pending_policies
+		WHERE id = ?`), id)
+
+	p, err := scanPendingPolicy(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (ps *PendingStore) DeletePending(id int) error {
+	_, err := ps.Conn.Exec(ps.Conn.Rebind(`DELETE FROM pending_policies WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("deleting pending policy: %s", err)
+	}
+	return nil
+}
+
+// deletePendingWithTx deletes the pending policy, and returns sql.ErrNoRows
+// when it does not exist
+func deletePendingWithTx(tx db.Transaction, id int) error {
+	result, err := tx.Exec(tx.Rebind(`DELETE FROM pending_policies WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("deleting pending policy: %s", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("deleting pending policy: %s", err) // untested
+	}
+	if deleted == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+type pendingPolicyScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPendingPolicy(row pendingPolicyScanner) (PendingPolicy, error) {
+	var p PendingPolicy
+	var policy string
+	err := row.Scan(&p.ID, &policy, &p.DestinationSpaceGUID, &p.RequestedBy, &p.ClientID, &p.CreatedAt)
+	if err == sql.ErrNoRows {
+		return PendingPolicy{}, err
+	}
+	if err != nil {
+		return PendingPolicy{}, fmt.Errorf("scanning pending policy result: %s", err)
+	}
+
+	err = json.Unmarshal([]byte(policy), &p.Policy)
+	if err != nil {
+		return PendingPolicy{}, fmt.Errorf("unmarshaling pending policy: %s", err)
+	}
+	return p, nil
+}
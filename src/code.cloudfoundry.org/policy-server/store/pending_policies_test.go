@@ -0,0 +1,105 @@
+package store_test
+
+import (
+	"fmt"
+	"time"
+
+	dbHelper "code.cloudfoundry.org/cf-networking-helpers/db"
+	"code.cloudfoundry.org/cf-networking-helpers/testsupport"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/store"
+	testhelpers "code.cloudfoundry.org/test-helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PendingStore", func() {
+	var (
+		pendingStore *store.PendingStore
+		dbConf       dbHelper.Config
+		realDb       *dbHelper.ConnWrapper
+		policy       store.Policy
+	)
+
+	BeforeEach(func() {
+		dbConf = testsupport.GetDBConfig()
+		dbConf.DatabaseName = fmt.Sprintf("pending_policies_test_%d", time.Now().UnixNano())
+		dbConf.Timeout = 30
+		testhelpers.CreateDatabase(dbConf)
+
+		logger := lager.NewLogger("Pending Policies Test")
+
+		var err error
+		realDb, err = dbHelper.NewConnectionPool(dbConf, 200, 200, 5*time.Minute, "Pending Policies Test", "Pending Policies Test", logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		migrateAndPopulateTags(realDb, 1)
+		pendingStore = &store.PendingStore{Conn: realDb}
+
+		policy = store.Policy{
+			Source: store.Source{ID: "some-app-guid"},
+			Destination: store.Destination{
+				ID:       "some-other-app-guid",
+				Protocol: "tcp",
+				Ports:    store.Ports{Start: 8080, End: 8080},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(realDb.Close()).To(Succeed())
+		testhelpers.RemoveDatabase(dbConf)
+	})
+
+	It("returns no pending policies when none are created", func() {
+		pending, err := pendingStore.PendingPolicies()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(BeEmpty())
+	})
+
+	It("creates, gets and deletes pending policies", func() {
+		err := pendingStore.CreatePending([]store.PendingPolicy{
+			{Policy: policy, DestinationSpaceGUID: "some-space-guid"},
+		}, store.Actor{Name: "some-user", ClientID: "some-client"})
+		Expect(err).NotTo(HaveOccurred())
+
+		pending, err := pendingStore.PendingPolicies()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(HaveLen(1))
+		Expect(pending[0].Policy).To(Equal(policy))
+		Expect(pending[0].DestinationSpaceGUID).To(Equal("some-space-guid"))
+		Expect(pending[0].RequestedBy).To(Equal("some-user"))
+		Expect(pending[0].ClientID).To(Equal("some-client"))
+		Expect(pending[0].CreatedAt).NotTo(BeZero())
+
+		By("getting a pending policy by id")
+		onePending, err := pendingStore.PendingPolicy(pending[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*onePending).To(Equal(pending[0]))
+
+		By("deleting a pending policy")
+		Expect(pendingStore.DeletePending(pending[0].ID)).To(Succeed())
+
+		onePending, err = pendingStore.PendingPolicy(pending[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(onePending).To(BeNil())
+	})
+
+	Context("when the pending_policies table does not exist", func() {
+		BeforeEach(func() {
+			_, err := realDb.Exec(`DROP TABLE pending_policies`)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns sensible errors", func() {
+			_, err := pendingStore.PendingPolicies()
+			Expect(err).To(MatchError(ContainSubstring("selecting pending policies:")))
+
+			err = pendingStore.CreatePending([]store.PendingPolicy{{Policy: policy}}, store.Actor{})
+			Expect(err).To(MatchError(ContainSubstring("inserting pending policy:")))
+
+			err = pendingStore.DeletePending(1)
+			Expect(err).To(MatchError(ContainSubstring("deleting pending policy:")))
+		})
+	})
+})
@@ -1,27 +1,31 @@
 package store
 
 import (
+	"database/sql"
+
 	"code.cloudfoundry.org/cf-networking-helpers/db"
 )
 
 //counterfeiter:generate -o fakes/policy_repo.go --fake-name PolicyRepo . PolicyRepo
 type PolicyRepo interface {
-	Create(db.Transaction, int, int) error
+	Create(db.Transaction, int, int) (bool, error)
 	Delete(db.Transaction, int, int) error
 	CountWhereGroupID(db.Transaction, int) (int, error)
 	CountWhereDestinationID(db.Transaction, int) (int, error)
+	Action(db.Transaction, int, int) (string, error)
 }
 
 type PolicyTable struct {
 }
 
-func (p *PolicyTable) Create(tx db.Transaction, sourceGroupId int, destinationId int) error {
+// Create inserts the policy unless it exists, and returns whether it did
+func (p *PolicyTable) Create(tx db.Transaction, sourceGroupId int, destinationId int) (bool, error) {
 	dualStatement := ""
 	if tx.DriverName() == "mysql" {
 		dualStatement = " FROM DUAL "
 	}
 
-	_, err := tx.Exec(tx.Rebind(`
+	result, err := tx.Exec(tx.Rebind(`
 		INSERT INTO policies (group_id, destination_id)
 		SELECT ?, ? `+dualStatement+`
 		WHERE
@@ -35,15 +39,30 @@ func (p *PolicyTable) Create(tx db.Transaction, sourceGroupId int, destinationId
 		sourceGroupId,
 		destinationId,
 	)
-	return err
+	if err != nil {
+		return false, err
+	}
+	created, err := result.RowsAffected()
+	return created > 0, err
 }
 
+// Delete deletes the policy, and returns sql.ErrNoRows when it does not exist
 func (p *PolicyTable) Delete(tx db.Transaction, sourceGroupId int, destinationId int) error {
-	_, err := tx.Exec(tx.Rebind(`DELETE FROM policies WHERE group_id = ? AND destination_id = ?`),
+	result, err := tx.Exec(tx.Rebind(`DELETE FROM policies WHERE group_id = ? AND destination_id = ?`),
 		sourceGroupId,
 		destinationId,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
 func (p *PolicyTable) CountWhereGroupID(tx db.Transaction, sourceGroupId int) (int, error) {
@@ -65,3 +84,16 @@ func (p *PolicyTable) CountWhereDestinationID(tx db.Transaction, destinationId i
 
 	return count, err
 }
+
+// Action returns the action of the policy, with the allow action read back as
+// empty
+func (p *PolicyTable) Action(tx db.Transaction, sourceGroupId int, destinationId int) (string, error) {
+	var action string
+	err := tx.QueryRow(
+		tx.Rebind(`SELECT action FROM policies WHERE group_id = ? AND destination_id = ?`),
+		sourceGroupId,
+		destinationId,
+	).Scan(&action)
+
+	return scanAction(action), err
+}
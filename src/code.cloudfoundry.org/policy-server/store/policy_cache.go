@@ -0,0 +1,142 @@
+package store
+
+import (
+	"sort"
+	"sync"
+)
+
+// cachingStore is a store that can tell whether the policies that it returns
+// were cached, which the metrics wrapper reports as cache hits and misses
+//
+//counterfeiter:generate -o fakes/caching_store.go --fake-name CachingStore . cachingStore
+type cachingStore interface {
+	Store
+	CachedAll() ([]Policy, bool, error)
+	CachedByGuids([]string, []string, bool) ([]Policy, bool, error)
+}
+
+// PolicyCache serves All and ByGuids from a copy of every policy that is kept
+// in memory, and reloaded from the store when the last updated time of the
+// policies has changed. Everything else goes to the store.
+type PolicyCache struct {
+	Store
+
+	mutex         sync.Mutex
+	loaded        bool
+	lastUpdated   int
+	policies      []Policy
+	bySource      map[string][]int
+	byDestination map[string][]int
+}
+
+func NewPolicyCache(store Store) *PolicyCache {
+	return &PolicyCache{Store: store}
+}
+
+func (c *PolicyCache) All() ([]Policy, error) {
+	policies, _, err := c.CachedAll()
+	return policies, err
+}
+
+func (c *PolicyCache) ByGuids(srcGuids, destGuids []string, inSourceAndDest bool) ([]Policy, error) {
+	policies, _, err := c.CachedByGuids(srcGuids, destGuids, inSourceAndDest)
+	return policies, err
+}
+
+// CachedAll returns every policy, and whether they were already cached
+func (c *PolicyCache) CachedAll() ([]Policy, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	hit, err := c.refresh()
+	if err != nil {
+		return nil, false, err
+	}
+	return append([]Policy{}, c.policies...), hit, nil
+}
+
+// CachedByGuids returns the policies with the given sources or destinations,
+// or both if inSourceAndDest is set, like ByGuids does, and whether they were
+// already cached
+func (c *PolicyCache) CachedByGuids(srcGuids, destGuids []string, inSourceAndDest bool) ([]Policy, bool, error) {
+	if len(srcGuids) == 0 && len(destGuids) == 0 {
+		return []Policy{}, true, nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	hit, err := c.refresh()
+	if err != nil {
+		return nil, false, err
+	}
+
+	bothRequired := inSourceAndDest && len(srcGuids) > 0 && len(destGuids) > 0
+	matches := map[int]int{}
+	for _, guid := range uniqueGuids(srcGuids) {
+		for _, i := range c.bySource[guid] {
+			matches[i]++
+		}
+	}
+	for _, guid := range uniqueGuids(destGuids) {
+		for _, i := range c.byDestination[guid] {
+			matches[i]++
+		}
+	}
+
+	// policies are returned in the order that the store listed them in
+	indexes := []int{}
+	for i, count := range matches {
+		if !bothRequired || count == 2 {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.Ints(indexes)
+
+	policies := make([]Policy, len(indexes))
+	for j, i := range indexes {
+		policies[j] = c.policies[i]
+	}
+	return policies, hit, nil
+}
+
+// refresh reloads the policies when they have changed since they were loaded,
+// and returns true when they had not. The last updated time is read first, so
+// that a change made while the policies are loaded causes another reload.
+func (c *PolicyCache) refresh() (bool, error) {
+	lastUpdated, err := c.Store.LastUpdated()
+	if err != nil {
+		return false, err
+	}
+	if c.loaded && lastUpdated == c.lastUpdated {
+		return true, nil
+	}
+
+	policies, err := c.Store.All()
+	if err != nil {
+		return false, err
+	}
+
+	c.bySource = map[string][]int{}
+	c.byDestination = map[string][]int{}
+	for i, policy := range policies {
+		c.bySource[policy.Source.ID] = append(c.bySource[policy.Source.ID], i)
+		c.byDestination[policy.Destination.ID] = append(c.byDestination[policy.Destination.ID], i)
+	}
+	c.policies = policies
+	c.lastUpdated = lastUpdated
+	c.loaded = true
+	return false, nil
+}
+
+func uniqueGuids(guids []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, guid := range guids {
+		if !seen[guid] {
+			seen[guid] = true
+			unique = append(unique, guid)
+		}
+	}
+	return unique
+}
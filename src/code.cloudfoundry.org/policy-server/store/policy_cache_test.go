@@ -0,0 +1,165 @@
+package store_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/store/fakes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PolicyCache", func() {
+	var (
+		policyCache *store.PolicyCache
+		fakeStore   *fakes.Store
+		policies    []store.Policy
+	)
+
+	newPolicy := func(source, destination string) store.Policy {
+		return store.Policy{
+			Source: store.Source{ID: source},
+			Destination: store.Destination{
+				ID:       destination,
+				Protocol: "tcp",
+				Port:     8080,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		policies = []store.Policy{
+			newPolicy("app-a", "app-b"),
+			newPolicy("app-b", "app-c"),
+			newPolicy("app-c", "app-a"),
+			newPolicy("app-a", "app-c"),
+		}
+		fakeStore = &fakes.Store{}
+		fakeStore.AllReturns(policies, nil)
+		fakeStore.LastUpdatedReturns(100, nil)
+		policyCache = store.NewPolicyCache(fakeStore)
+	})
+
+	Describe("CachedAll", func() {
+		It("loads the policies once until they are updated", func() {
+			returnedPolicies, hit, err := policyCache.CachedAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hit).To(BeFalse())
+			Expect(returnedPolicies).To(Equal(policies))
+
+			returnedPolicies, hit, err = policyCache.CachedAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hit).To(BeTrue())
+			Expect(returnedPolicies).To(Equal(policies))
+			Expect(fakeStore.AllCallCount()).To(Equal(1))
+
+			fakeStore.LastUpdatedReturns(200, nil)
+			fakeStore.AllReturns(policies[:1], nil)
+
+			returnedPolicies, hit, err = policyCache.CachedAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hit).To(BeFalse())
+			Expect(returnedPolicies).To(Equal(policies[:1]))
+			Expect(fakeStore.AllCallCount()).To(Equal(2))
+		})
+
+		It("returns a copy of the cached policies", func() {
+			returnedPolicies, _, err := policyCache.CachedAll()
+			Expect(err).NotTo(HaveOccurred())
+			returnedPolicies[0] = newPolicy("app-x", "app-y")
+
+			returnedPolicies, _, err = policyCache.CachedAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(returnedPolicies[0]).To(Equal(newPolicy("app-a", "app-b")))
+		})
+
+		Context("when getting the last updated time fails", func() {
+			BeforeEach(func() {
+				fakeStore.LastUpdatedReturns(0, errors.New("banana"))
+			})
+
+			It("returns the error", func() {
+				_, _, err := policyCache.CachedAll()
+				Expect(err).To(MatchError("banana"))
+				Expect(fakeStore.AllCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when loading the policies fails", func() {
+			BeforeEach(func() {
+				fakeStore.AllReturns(nil, errors.New("banana"))
+			})
+
+			It("returns the error and loads them again next time", func() {
+				_, _, err := policyCache.CachedAll()
+				Expect(err).To(MatchError("banana"))
+
+				fakeStore.AllReturns(policies, nil)
+				returnedPolicies, hit, err := policyCache.CachedAll()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hit).To(BeFalse())
+				Expect(returnedPolicies).To(Equal(policies))
+			})
+		})
+	})
+
+	Describe("CachedByGuids", func() {
+		It("returns the policies with any of the sources or destinations in store order", func() {
+			returnedPolicies, hit, err := policyCache.CachedByGuids([]string{"app-b"}, []string{"app-a"}, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hit).To(BeFalse())
+			Expect(returnedPolicies).To(Equal([]store.Policy{policies[1], policies[2]}))
+
+			returnedPolicies, hit, err = policyCache.CachedByGuids([]string{"app-a", "app-a"}, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hit).To(BeTrue())
+			Expect(returnedPolicies).To(Equal([]store.Policy{policies[0], policies[3]}))
+			Expect(fakeStore.AllCallCount()).To(Equal(1))
+			Expect(fakeStore.ByGuidsCallCount()).To(Equal(0))
+		})
+
+		Context("when the policies must match in source and destination", func() {
+			It("returns the policies with one of the sources and one of the destinations", func() {
+				returnedPolicies, _, err := policyCache.CachedByGuids([]string{"app-a"}, []string{"app-c", "app-a"}, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(returnedPolicies).To(Equal([]store.Policy{policies[3]}))
+			})
+
+			It("returns the policies of either list when only one is given", func() {
+				returnedPolicies, _, err := policyCache.CachedByGuids(nil, []string{"app-c"}, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(returnedPolicies).To(Equal([]store.Policy{policies[1], policies[3]}))
+			})
+		})
+
+		Context("when no guids are given", func() {
+			It("returns no policies without reading the store", func() {
+				returnedPolicies, _, err := policyCache.CachedByGuids(nil, []string{}, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(returnedPolicies).To(BeEmpty())
+				Expect(fakeStore.LastUpdatedCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when loading the policies fails", func() {
+			BeforeEach(func() {
+				fakeStore.AllReturns(nil, errors.New("banana"))
+			})
+
+			It("returns the error", func() {
+				_, _, err := policyCache.CachedByGuids([]string{"app-a"}, nil, false)
+				Expect(err).To(MatchError("banana"))
+			})
+		})
+	})
+
+	Describe("ByGuids", func() {
+		It("is served from the cache", func() {
+			returnedPolicies, err := policyCache.ByGuids([]string{"app-c"}, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(returnedPolicies).To(Equal([]store.Policy{policies[2]}))
+			Expect(fakeStore.ByGuidsCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,310 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/cf-networking-helpers/db"
+	"code.cloudfoundry.org/policy-server/store/helpers"
+)
+
+const (
+	PolicyEventCreate     = "create"
+	PolicyEventUpdate     = "update"
+	PolicyEventDelete     = "delete"
+	PolicyEventQuarantine = "quarantine"
+	PolicyEventRelease    = "release"
+)
+
+// Actor identifies who changed a set of policies. Name is the user name for
+// user tokens and the subject for client credentials tokens. ApprovedBy is the
+// name of who approved the change when it was requested as a pending policy.
+type Actor struct {
+	Name       string
+	ClientID   string
+	ApprovedBy string
+}
+
+// SystemActor is the actor of the changes that the policy server makes on its
+// own, such as cleaning up the policies of apps that no longer exist.
+var SystemActor = Actor{Name: "system"}
+
+type PolicyEvent struct {
+	ID         int
+	Action     string
+	Actor      string
+	ClientID   string
+	ApprovedBy string
+	Policies   []Policy
+	CreatedAt  time.Time
+}
+
+type PolicyEventsFilter struct {
+	AppGuids []string
+	Since    time.Time
+	Until    time.Time
+}
+
+//counterfeiter:generate -o fakes/policy_events_store.go --fake-name PolicyEventsStore . PolicyEventsStore
+type PolicyEventsStore interface {
+	Events(PolicyEventsFilter, Page) ([]PolicyEvent, Pagination, error)
+	DeleteEventsBefore(time.Time) (int, error)
+}
+
+type EventsStore struct {
+	Conn Database
+}
+
+// Events returns the events that match the filter, oldest first. When the page
+// has a limit, Next is the id of the first event of the following page.
+func (es *EventsStore) Events(filter PolicyEventsFilter, page Page) ([]PolicyEvent, Pagination, error) {
+	query := `
+		SELECT
+			id,
+			action,
+			actor,
+			client_id,
+			approved_by,
+			policies,
+			created_at
+		FROM policy_events`
+
+	var wheres []string
+	var whereBindings []interface{}
+	if len(filter.AppGuids) > 0 {
+		wheres = append(wheres, "("+es.jsonOverlapsSQL("app_guids", filter.AppGuids)+")")
+		for _, guid := range filter.AppGuids {
+			whereBindings = append(whereBindings, guid)
+		}
+	}
+	if !filter.Since.IsZero() {
+		wheres = append(wheres, "created_at >= %")
+		whereBindings = append(whereBindings, filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		wheres = append(wheres, "created_at <= %")
+		whereBindings = append(whereBindings, filter.Until.UTC())
+	}
+	if page.From > 0 {
+		wheres = append(wheres, "id >= %")
+		whereBindings = append(whereBindings, page.From)
+	}
+
+	if len(wheres) > 0 {
+		query = query + " WHERE " + strings.Join(wheres, " AND ")
+	}
+	query = query + " ORDER BY id"
+	if page.Limit > 0 {
+		// we don't use a placeholder because limit is an integer and it is safe to interpolate it
+		query = fmt.Sprintf("%s LIMIT %d", query, page.Limit+1)
+	}
+
+	rows, err := es.Conn.Query(helpers.RebindForSQLDialectAndMark(query, es.Conn.DriverName(), "%"), whereBindings...)
+	if err != nil {
+		return nil, Pagination{}, fmt.Errorf("selecting policy events: %s", err)
+	}
+	defer rows.Close()
+
+	events := []PolicyEvent{}
+	for rows.Next() {
+		var event PolicyEvent
+		var policies string
+		err := rows.Scan(&event.ID, &event.Action, &event.Actor, &event.ClientID, &event.ApprovedBy, &policies, &event.CreatedAt)
+		if err != nil {
+			return nil, Pagination{}, fmt.Errorf("scanning policy event result: %s", err)
+		}
+
+		event.Policies, err = unmarshalPolicyEventPayload(policies)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		events = append(events, event)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, Pagination{}, fmt.Errorf("selecting policy events, getting next row: %s", err) // untested
+	}
+
+	if page.Limit > 0 && len(events) > page.Limit {
+		return events[:page.Limit], Pagination{Next: events[page.Limit].ID}, nil
+	}
+	return events, Pagination{}, nil
+}
+
+// DeleteEventsBefore deletes the events recorded before the given time, and
+// returns how many it deleted
+func (es *EventsStore) DeleteEventsBefore(before time.Time) (int, error) {
+	result, err := es.Conn.Exec(helpers.RebindForSQLDialect(`DELETE FROM policy_events WHERE created_at < ?`, es.Conn.DriverName()),
+		before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("deleting policy events: %s", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("deleting policy events: %s", err) // untested
+	}
+	return int(deleted), nil
+}
+
+func (es *EventsStore) jsonOverlapsSQL(columnName string, filterValues []string) string {
+	switch es.Conn.DriverName() {
+	case helpers.MySQL:
+		clauses := []string{}
+		for range filterValues {
+			clauses = append(clauses, fmt.Sprintf(`json_contains(%s, json_quote(%%))`, columnName))
+		}
+		return strings.Join(clauses, " OR ")
+	case helpers.Postgres:
+		filterList := helpers.MarksWithSeparator(len(filterValues), "%", ", ")
+		return fmt.Sprintf(`%s ?| array[%s]`, columnName, filterList)
+	default:
+		return ""
+	}
+}
+
+func createPolicyEvent(tx db.Transaction, action string, actor Actor, policies []Policy) error {
+	if len(policies) == 0 {
+		return nil
+	}
+	return insertPolicyEvent(tx, action, actor, policies, nil)
+}
+
+// createAppEvent records an event for an app, along with the policies of the
+// app that it affects, which may be none
+func createAppEvent(tx db.Transaction, action string, actor Actor, appGuid string, policies []Policy) error {
+	return insertPolicyEvent(tx, action, actor, policies, []string{appGuid})
+}
+
+func insertPolicyEvent(tx db.Transaction, action string, actor Actor, policies []Policy, appGuids []string) error {
+	appGuidSet := map[string]struct{}{}
+	for _, guid := range appGuids {
+		appGuidSet[guid] = struct{}{}
+	}
+	for _, policy := range policies {
+		for _, guid := range []string{policy.Source.ID, policy.Destination.ID} {
+			if _, ok := appGuidSet[guid]; !ok {
+				appGuidSet[guid] = struct{}{}
+				appGuids = append(appGuids, guid)
+			}
+		}
+	}
+
+	policiesJSON, err := marshalPolicyEventPayload(policies)
+	if err != nil {
+		return fmt.Errorf("marshaling policy event policies: %s", err) // untested
+	}
+	appGuidsJSON, err := json.Marshal(appGuids)
+	if err != nil {
+		return fmt.Errorf("marshaling policy event app guids: %s", err) // untested
+	}
+
+	_, err = tx.Exec(tx.Rebind(`
+		INSERT INTO policy_events (action, actor, client_id, approved_by, policies, app_guids)
+		VALUES (?, ?, ?, ?, ?, ?)`),
+		action, actor.Name, actor.ClientID, actor.ApprovedBy, string(policiesJSON), string(appGuidsJSON),
+	)
+	if err != nil {
+		return fmt.Errorf("creating policy event: %s", err)
+	}
+	return nil
+}
+
+// policyEventPayloadVersion is the version of the format that the policies of
+// an event are stored in. It changes whenever the format does, so that the
+// events recorded before can still be read.
+const policyEventPayloadVersion = 1
+
+type policyEventPayload struct {
+	Version  int                 `json:"version"`
+	Policies []policyEventPolicy `json:"policies"`
+}
+
+// policyEventPolicy is a policy as it is stored in the history. Tags are left
+// out, as they are freed and reused once a policy is gone.
+type policyEventPolicy struct {
+	Source      policyEventSource      `json:"source"`
+	Destination policyEventDestination `json:"destination"`
+	Description string                 `json:"description,omitempty"`
+	Labels      map[string]string      `json:"labels,omitempty"`
+	Annotations map[string]string      `json:"annotations,omitempty"`
+	ExpiresAt   *time.Time             `json:"expires_at,omitempty"`
+	Action      string                 `json:"action,omitempty"`
+}
+
+type policyEventSource struct {
+	ID   string `json:"id"`
+	Type string `json:"type,omitempty"`
+}
+
+type policyEventDestination struct {
+	ID        string `json:"id"`
+	Protocol  string `json:"protocol,omitempty"`
+	Port      int    `json:"port,omitempty"`
+	StartPort int    `json:"start_port,omitempty"`
+	EndPort   int    `json:"end_port,omitempty"`
+	ICMPType  int    `json:"icmp_type"`
+	ICMPCode  int    `json:"icmp_code"`
+}
+
+func marshalPolicyEventPayload(policies []Policy) ([]byte, error) {
+	payload := policyEventPayload{
+		Version:  policyEventPayloadVersion,
+		Policies: make([]policyEventPolicy, len(policies)),
+	}
+	for i, p := range policies {
+		payload.Policies[i] = policyEventPolicy{
+			Source: policyEventSource{ID: p.Source.ID, Type: p.Source.Type},
+			Destination: policyEventDestination{
+				ID:        p.Destination.ID,
+				Protocol:  p.Destination.Protocol,
+				Port:      p.Destination.Port,
+				StartPort: p.Destination.Ports.Start,
+				EndPort:   p.Destination.Ports.End,
+				ICMPType:  p.Destination.ICMPType,
+				ICMPCode:  p.Destination.ICMPCode,
+			},
+			Description: p.Metadata.Description,
+			Labels:      p.Metadata.Labels,
+			Annotations: p.Metadata.Annotations,
+			ExpiresAt:   p.ExpiresAt,
+			Action:      p.Action,
+		}
+	}
+	return json.Marshal(payload)
+}
+
+func unmarshalPolicyEventPayload(payloadJSON string) ([]Policy, error) {
+	var payload policyEventPayload
+	err := json.Unmarshal([]byte(payloadJSON), &payload)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling policy event policies: %s", err)
+	}
+	if payload.Version != policyEventPayloadVersion {
+		return nil, fmt.Errorf("unmarshaling policy event policies: unknown version %d", payload.Version)
+	}
+
+	policies := make([]Policy, len(payload.Policies))
+	for i, p := range payload.Policies {
+		policies[i] = Policy{
+			Source: Source{ID: p.Source.ID, Type: p.Source.Type},
+			Destination: Destination{
+				ID:       p.Destination.ID,
+				Protocol: p.Destination.Protocol,
+				Port:     p.Destination.Port,
+				Ports:    Ports{Start: p.Destination.StartPort, End: p.Destination.EndPort},
+				ICMPType: p.Destination.ICMPType,
+				ICMPCode: p.Destination.ICMPCode,
+			},
+			Metadata: Metadata{
+				Description: p.Description,
+				Labels:      p.Labels,
+				Annotations: p.Annotations,
+			},
+			ExpiresAt: p.ExpiresAt,
+			Action:    p.Action,
+		}
+	}
+	return policies, nil
+}
@@ -0,0 +1,341 @@
+package store_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	dbHelper "code.cloudfoundry.org/cf-networking-helpers/db"
+	dbfakes "code.cloudfoundry.org/cf-networking-helpers/db/fakes"
+	"code.cloudfoundry.org/cf-networking-helpers/testsupport"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/store"
+	"code.cloudfoundry.org/policy-server/store/fakes"
+	testhelpers "code.cloudfoundry.org/test-helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PolicyEvents", func() {
+	var (
+		dataStore   store.Store
+		eventsStore *store.EventsStore
+		dbConf      dbHelper.Config
+		realDb      *dbHelper.ConnWrapper
+		actor       store.Actor
+		policies    []store.Policy
+	)
+
+	BeforeEach(func() {
+		dbConf = testsupport.GetDBConfig()
+		dbConf.DatabaseName = fmt.Sprintf("policy_events_test_%d", time.Now().UnixNano())
+		dbConf.Timeout = 30
+		testhelpers.CreateDatabase(dbConf)
+
+		logger := lager.NewLogger("Policy Events Test")
+
+		var err error
+		realDb, err = dbHelper.NewConnectionPool(dbConf, 200, 200, 5*time.Minute, "Policy Events Test", "Policy Events Test", logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		migrateAndPopulateTags(realDb, 1)
+		dataStore = store.New(realDb, &store.GroupTable{}, &store.DestinationTable{}, &store.PolicyTable{}, 1)
+		eventsStore = &store.EventsStore{Conn: realDb}
+
+		actor = store.Actor{Name: "some-user", ClientID: "some-client"}
+		policies = []store.Policy{
+			{
+				Source: store.Source{ID: "app-a"},
+				Destination: store.Destination{
+					ID:       "app-b",
+					Protocol: "tcp",
+					Port:     8080,
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+			},
+			{
+				Source: store.Source{ID: "app-c"},
+				Destination: store.Destination{
+					ID:       "app-d",
+					Protocol: "udp",
+					Ports:    store.Ports{Start: 5000, End: 6000},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(realDb.Close()).To(Succeed())
+		testhelpers.RemoveDatabase(dbConf)
+	})
+
+	Describe("CreateWithEvent", func() {
+		It("creates the policies and records a create event", func() {
+			err := dataStore.CreateWithEvent(policies, actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			storedPolicies, err := dataStore.All()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(storedPolicies).To(HaveLen(2))
+
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Action).To(Equal(store.PolicyEventCreate))
+			Expect(events[0].Actor).To(Equal("some-user"))
+			Expect(events[0].ClientID).To(Equal("some-client"))
+			Expect(events[0].Policies).To(Equal(policies))
+			Expect(events[0].CreatedAt).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+
+		It("stores the policies of the event in a versioned format", func() {
+			expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+			policies[0].Metadata = store.Metadata{Description: "some-description", Labels: map[string]string{"team": "a"}}
+			policies[0].ExpiresAt = &expiresAt
+			policies[1].Action = store.PolicyActionDeny
+			err := dataStore.CreateWithEvent(policies, actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			var payload string
+			err = realDb.QueryRow(`SELECT policies FROM policy_events`).Scan(&payload)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(payload).To(MatchJSON(`{
+				"version": 1,
+				"policies": [
+					{
+						"source": {"id": "app-a"},
+						"destination": {"id": "app-b", "protocol": "tcp", "port": 8080, "start_port": 8080, "end_port": 8080, "icmp_type": 0, "icmp_code": 0},
+						"description": "some-description",
+						"labels": {"team": "a"},
+						"expires_at": "2030-01-02T03:04:05Z"
+					},
+					{
+						"source": {"id": "app-c"},
+						"destination": {"id": "app-d", "protocol": "udp", "start_port": 5000, "end_port": 6000, "icmp_type": 0, "icmp_code": 0},
+						"action": "deny"
+					}
+				]
+			}`))
+		})
+
+		It("only records the policies that did not exist yet", func() {
+			err := dataStore.Create(policies[:1])
+			Expect(err).NotTo(HaveOccurred())
+
+			err = dataStore.CreateWithEvent(policies, actor)
+			Expect(err).NotTo(HaveOccurred())
+			err = dataStore.CreateWithEvent(policies, actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Policies).To(Equal(policies[1:]))
+		})
+
+		It("does not record events for policies created without an actor", func() {
+			err := dataStore.Create(policies)
+			Expect(err).NotTo(HaveOccurred())
+
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(BeEmpty())
+		})
+
+		Context("when recording the event fails", func() {
+			var (
+				mockDb *fakes.Db
+				tx     *dbfakes.Transaction
+			)
+
+			BeforeEach(func() {
+				mockDb = &fakes.Db{}
+				tx = &dbfakes.Transaction{}
+				mockDb.DriverNameReturns(realDb.DriverName())
+				mockDb.BeginxReturns(tx, nil)
+				tx.RebindStub = realDb.Rebind
+				tx.ExecStub = func(query string, args ...interface{}) (sql.Result, error) {
+					if strings.Contains(query, "policy_events") {
+						return nil, errors.New("some-insert-error")
+					}
+					return nil, nil
+				}
+
+				fakePolicy := &fakes.PolicyRepo{}
+				fakePolicy.CreateReturns(true, nil)
+				dataStore = store.New(mockDb, &fakes.GroupRepo{}, &fakes.DestinationRepo{}, fakePolicy, 1)
+			})
+
+			It("rolls back the transaction", func() {
+				err := dataStore.CreateWithEvent(policies, actor)
+				Expect(err).To(MatchError("creating policy event: some-insert-error"))
+				Expect(tx.RollbackCallCount()).To(Equal(1))
+				Expect(tx.CommitCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("DeleteWithEvent", func() {
+		BeforeEach(func() {
+			err := dataStore.Create(policies)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("deletes the policies and records a delete event", func() {
+			err := dataStore.DeleteWithEvent(policies[:1], actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			storedPolicies, err := dataStore.All()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(storedPolicies).To(HaveLen(1))
+
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Action).To(Equal(store.PolicyEventDelete))
+			Expect(events[0].Policies).To(Equal(policies[:1]))
+		})
+
+		It("only records the policies that existed", func() {
+			err := dataStore.DeleteWithEvent(policies[:1], actor)
+			Expect(err).NotTo(HaveOccurred())
+			err = dataStore.DeleteWithEvent(policies, actor)
+			Expect(err).NotTo(HaveOccurred())
+			err = dataStore.DeleteWithEvent(policies, actor)
+			Expect(err).NotTo(HaveOccurred())
+
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+			Expect(events[0].Policies).To(Equal(policies[:1]))
+			Expect(events[1].Policies).To(Equal(policies[1:]))
+		})
+	})
+
+	Describe("Events", func() {
+		BeforeEach(func() {
+			err := dataStore.CreateWithEvent(policies[:1], actor)
+			Expect(err).NotTo(HaveOccurred())
+			err = dataStore.CreateWithEvent(policies[1:], actor)
+			Expect(err).NotTo(HaveOccurred())
+			err = dataStore.DeleteWithEvent(policies[:1], store.Actor{Name: "another-user"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns every event in the order it was recorded", func() {
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(3))
+			Expect(events[0].Policies).To(Equal(policies[:1]))
+			Expect(events[1].Policies).To(Equal(policies[1:]))
+			Expect(events[2].Action).To(Equal(store.PolicyEventDelete))
+			Expect(events[2].Actor).To(Equal("another-user"))
+		})
+
+		Context("when app guids are provided", func() {
+			It("returns events whose policies reference any of the apps", func() {
+				events, _, err := eventsStore.Events(store.PolicyEventsFilter{AppGuids: []string{"app-b"}}, store.Page{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(events).To(HaveLen(2))
+				Expect(events[0].Action).To(Equal(store.PolicyEventCreate))
+				Expect(events[1].Action).To(Equal(store.PolicyEventDelete))
+
+				events, _, err = eventsStore.Events(store.PolicyEventsFilter{AppGuids: []string{"app-c", "app-unknown"}}, store.Page{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(events).To(HaveLen(1))
+				Expect(events[0].Policies).To(Equal(policies[1:]))
+			})
+		})
+
+		Context("when a time range is provided", func() {
+			It("returns events recorded within the range", func() {
+				events, _, err := eventsStore.Events(store.PolicyEventsFilter{Since: time.Now().Add(time.Hour)}, store.Page{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(events).To(BeEmpty())
+
+				events, _, err = eventsStore.Events(store.PolicyEventsFilter{
+					Since: time.Now().Add(-time.Hour),
+					Until: time.Now().Add(time.Hour),
+				}, store.Page{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(events).To(HaveLen(3))
+			})
+		})
+
+		Context("when a page is provided", func() {
+			It("returns the events of the page and the id of the next event", func() {
+				events, pagination, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{Limit: 2})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(events).To(HaveLen(2))
+				Expect(pagination.Next).NotTo(BeZero())
+
+				nextEvents, pagination, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{From: pagination.Next, Limit: 2})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nextEvents).To(HaveLen(1))
+				Expect(nextEvents[0].ID).To(BeNumerically(">", events[1].ID))
+				Expect(nextEvents[0].Action).To(Equal(store.PolicyEventDelete))
+				Expect(pagination).To(Equal(store.Pagination{}))
+			})
+		})
+
+		Context("when an event is stored in an unknown version", func() {
+			It("returns a sensible error", func() {
+				_, err := realDb.Exec(realDb.Rebind(`INSERT INTO policy_events (action, actor, client_id, policies, app_guids) VALUES (?, ?, ?, ?, ?)`),
+					store.PolicyEventCreate, "some-user", "", `{"version": 2, "policies": []}`, `[]`)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, err = eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+				Expect(err).To(MatchError("unmarshaling policy event policies: unknown version 2"))
+			})
+		})
+
+		Context("when the query fails", func() {
+			It("returns a sensible error", func() {
+				mockDb := &fakes.Db{}
+				mockDb.DriverNameReturns(realDb.DriverName())
+				mockDb.QueryReturns(nil, errors.New("some query error"))
+				eventsStore = &store.EventsStore{Conn: mockDb}
+
+				_, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+				Expect(err).To(MatchError("selecting policy events: some query error"))
+			})
+		})
+	})
+
+	Describe("DeleteEventsBefore", func() {
+		BeforeEach(func() {
+			err := dataStore.CreateWithEvent(policies[:1], actor)
+			Expect(err).NotTo(HaveOccurred())
+			err = dataStore.CreateWithEvent(policies[1:], actor)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("deletes the events recorded before the time", func() {
+			deleted, err := eventsStore.DeleteEventsBefore(time.Now().Add(-time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(0))
+
+			deleted, err = eventsStore.DeleteEventsBefore(time.Now().Add(time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(2))
+
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(BeEmpty())
+		})
+
+		Context("when the delete fails", func() {
+			It("returns a sensible error", func() {
+				mockDb := &fakes.Db{}
+				mockDb.DriverNameReturns(realDb.DriverName())
+				mockDb.ExecReturns(nil, errors.New("some delete error"))
+				eventsStore = &store.EventsStore{Conn: mockDb}
+
+				_, err := eventsStore.DeleteEventsBefore(time.Now())
+				Expect(err).To(MatchError("deleting policy events: some delete error"))
+			})
+		})
+	})
+})
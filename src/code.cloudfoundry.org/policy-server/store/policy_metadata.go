@@ -0,0 +1,73 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"code.cloudfoundry.org/cf-networking-helpers/db"
+)
+
+// setPolicyMetadata replaces the metadata of the policy between the source
+// group and the destination. Empty metadata removes it.
+func setPolicyMetadata(tx db.Transaction, sourceGroupID, destinationID int, metadata Metadata) error {
+	var policyID int
+	err := tx.QueryRow(
+		tx.Rebind(`SELECT id FROM policies WHERE group_id = ? AND destination_id = ?`),
+		sourceGroupID,
+		destinationID,
+	).Scan(&policyID)
+	if err != nil {
+		return fmt.Errorf("getting policy id: %s", err)
+	}
+
+	_, err = tx.Exec(tx.Rebind(`DELETE FROM policy_metadata WHERE policy_id = ?`), policyID)
+	if err != nil {
+		return fmt.Errorf("deleting policy metadata: %s", err)
+	}
+
+	if metadata.IsEmpty() {
+		return nil
+	}
+
+	labels, err := marshalStringMap(metadata.Labels)
+	if err != nil {
+		return fmt.Errorf("marshaling policy labels: %s", err) // untested
+	}
+	annotations, err := marshalStringMap(metadata.Annotations)
+	if err != nil {
+		return fmt.Errorf("marshaling policy annotations: %s", err) // untested
+	}
+
+	_, err = tx.Exec(tx.Rebind(`
+		INSERT INTO policy_metadata (policy_id, description, labels, annotations)
+		VALUES (?, ?, ?, ?)`),
+		policyID,
+		metadata.Description,
+		labels,
+		annotations,
+	)
+	if err != nil {
+		return fmt.Errorf("creating policy metadata: %s", err)
+	}
+	return nil
+}
+
+func marshalStringMap(m map[string]string) (string, error) {
+	if m == nil {
+		m = map[string]string{}
+	}
+	bytes, err := json.Marshal(m)
+	return string(bytes), err
+}
+
+func unmarshalStringMap(s string) (map[string]string, error) {
+	var m map[string]string
+	err := json.Unmarshal([]byte(s), &m)
+	if err != nil {
+		return nil, err
+	}
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
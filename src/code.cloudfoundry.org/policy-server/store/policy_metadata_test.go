@@ -0,0 +1,138 @@
+package store_test
+
+import (
+	"fmt"
+	"time"
+
+	dbHelper "code.cloudfoundry.org/cf-networking-helpers/db"
+	"code.cloudfoundry.org/cf-networking-helpers/testsupport"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/store"
+	testhelpers "code.cloudfoundry.org/test-helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PolicyMetadata", func() {
+	var (
+		dataStore store.Store
+		dbConf    dbHelper.Config
+		realDb    *dbHelper.ConnWrapper
+		policies  []store.Policy
+	)
+
+	BeforeEach(func() {
+		dbConf = testsupport.GetDBConfig()
+		dbConf.DatabaseName = fmt.Sprintf("policy_metadata_test_%d", time.Now().UnixNano())
+		dbConf.Timeout = 30
+		testhelpers.CreateDatabase(dbConf)
+
+		logger := lager.NewLogger("Policy Metadata Test")
+
+		var err error
+		realDb, err = dbHelper.NewConnectionPool(dbConf, 200, 200, 5*time.Minute, "Policy Metadata Test", "Policy Metadata Test", logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		migrateAndPopulateTags(realDb, 1)
+		dataStore = store.New(realDb, &store.GroupTable{}, &store.DestinationTable{}, &store.PolicyTable{}, 1)
+
+		policies = []store.Policy{
+			{
+				Source: store.Source{ID: "app-a"},
+				Destination: store.Destination{
+					ID:       "app-b",
+					Protocol: "tcp",
+					Port:     8080,
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+				Metadata: store.Metadata{
+					Description: "frontend calls the orders api",
+					Labels:      map[string]string{"team": "orders"},
+					Annotations: map[string]string{"ticket": "NET-123"},
+				},
+			},
+			{
+				Source: store.Source{ID: "app-c"},
+				Destination: store.Destination{
+					ID:       "app-b",
+					Protocol: "tcp",
+					Port:     8080,
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+			},
+		}
+
+		err = dataStore.Create(policies)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(realDb.Close()).To(Succeed())
+		testhelpers.RemoveDatabase(dbConf)
+	})
+
+	It("returns the metadata with the policies", func() {
+		storedPolicies, err := dataStore.ByGuids([]string{"app-a", "app-c"}, nil, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(storedPolicies).To(HaveLen(2))
+
+		for _, p := range storedPolicies {
+			switch p.Source.ID {
+			case "app-a":
+				Expect(p.Metadata).To(Equal(policies[0].Metadata))
+			case "app-c":
+				Expect(p.Metadata).To(Equal(store.Metadata{}))
+			}
+		}
+	})
+
+	Context("when an existing policy is created again with metadata", func() {
+		It("replaces the metadata", func() {
+			policy := policies[0]
+			policy.Metadata = store.Metadata{Labels: map[string]string{"team": "payments"}}
+
+			err := dataStore.Create([]store.Policy{policy})
+			Expect(err).NotTo(HaveOccurred())
+
+			storedPolicies, err := dataStore.ByGuids([]string{"app-a"}, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(storedPolicies).To(HaveLen(1))
+			Expect(storedPolicies[0].Metadata).To(Equal(store.Metadata{Labels: map[string]string{"team": "payments"}}))
+		})
+	})
+
+	Context("when the policy is deleted", func() {
+		It("deletes the metadata", func() {
+			err := dataStore.Delete(policies[:1])
+			Expect(err).NotTo(HaveOccurred())
+
+			var count int
+			err = realDb.QueryRow(`SELECT COUNT(*) FROM policy_metadata`).Scan(&count)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(0))
+		})
+	})
+
+	Context("when the policies of a source are replaced", func() {
+		It("updates the metadata of the policies that are kept", func() {
+			policy := policies[0]
+			policy.Metadata = store.Metadata{Description: "still needed"}
+
+			err := dataStore.ReplaceForSource("app-a", []store.Policy{policy}, nil, store.Actor{Name: "some-user"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			storedPolicies, err := dataStore.ByGuids([]string{"app-a"}, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(storedPolicies).To(HaveLen(1))
+			Expect(storedPolicies[0].Metadata).To(Equal(store.Metadata{Description: "still needed"}))
+
+			policy.Metadata = store.Metadata{}
+			err = dataStore.ReplaceForSource("app-a", []store.Policy{policy}, nil, store.Actor{Name: "some-user"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			storedPolicies, err = dataStore.ByGuids([]string{"app-a"}, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(storedPolicies[0].Metadata).To(Equal(store.Metadata{}))
+		})
+	})
+})
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/policy-server/store/helpers"
+)
+
+// Quota limits the number of policies whose source is in a space or org. The
+// policies of a space count towards both the space and its org.
+type Quota struct {
+	Type        string
+	GUID        string
+	MaxPolicies int
+}
+
+//counterfeiter:generate -o fakes/policy_quotas_store.go --fake-name PolicyQuotasStore . PolicyQuotasStore
+type PolicyQuotasStore interface {
+	Quotas() ([]Quota, error)
+	SetQuota(Quota) error
+	DeleteQuota(quotaType, guid string) error
+}
+
+type QuotasStore struct {
+	Conn Database
+}
+
+func (qs *QuotasStore) Quotas() ([]Quota, error) {
+	rows, err := qs.Conn.Query(`SELECT type, guid, max_policies FROM policy_quotas ORDER BY type, guid`)
+	if err != nil {
+		return nil, fmt.Errorf("selecting policy quotas: %s", err)
+	}
+	defer rows.Close()
+
+	quotas := []Quota{}
+	for rows.Next() {
+		var quota Quota
+		err := rows.Scan(&quota.Type, &quota.GUID, &quota.MaxPolicies)
+		if err != nil {
+			return nil, fmt.Errorf("scanning policy quota result: %s", err)
+		}
+		quotas = append(quotas, quota)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("selecting policy quotas, getting next row: %s", err) // untested
+	}
+	return quotas, nil
+}
+
+func (qs *QuotasStore) SetQuota(quota Quota) error {
+	query := fmt.Sprintf(`
+		INSERT INTO policy_quotas (type, guid, max_policies)
+		VALUES (?, ?, ?)
+		%s max_policies = ?`, qs.onConflictUpdateSQL())
+
+	_, err := qs.Conn.Exec(qs.Conn.Rebind(query), quota.Type, quota.GUID, quota.MaxPolicies, quota.MaxPolicies)
+	if err != nil {
+		return fmt.Errorf("setting policy quota: %s", err)
+	}
+	return nil
+}
+
+func (qs *QuotasStore) DeleteQuota(quotaType, guid string) error {
+	_, err := qs.Conn.Exec(qs.Conn.Rebind(`DELETE FROM policy_quotas WHERE type = ? AND guid = ?`), quotaType, guid)
+	if err != nil {
+		return fmt.Errorf("deleting policy quota: %s", err)
+	}
+	return nil
+}
+
+func (qs *QuotasStore) onConflictUpdateSQL() string {
+	switch qs.Conn.DriverName() {
+	case helpers.MySQL:
+		return "ON DUPLICATE KEY UPDATE"
+	case helpers.Postgres:
+		return "ON CONFLICT (type, guid) DO UPDATE SET"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,97 @@
+package store_test
+
+import (
+	"fmt"
+	"time"
+
+	dbHelper "code.cloudfoundry.org/cf-networking-helpers/db"
+	"code.cloudfoundry.org/cf-networking-helpers/testsupport"
+	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/store"
+	testhelpers "code.cloudfoundry.org/test-helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QuotasStore", func() {
+	var (
+		quotasStore *store.QuotasStore
+		dbConf      dbHelper.Config
+		realDb      *dbHelper.ConnWrapper
+	)
+
+	BeforeEach(func() {
+		dbConf = testsupport.GetDBConfig()
+		dbConf.DatabaseName = fmt.Sprintf("policy_quotas_test_%d", time.Now().UnixNano())
+		dbConf.Timeout = 30
+		testhelpers.CreateDatabase(dbConf)
+
+		logger := lager.NewLogger("Policy Quotas Test")
+
+		var err error
+		realDb, err = dbHelper.NewConnectionPool(dbConf, 200, 200, 5*time.Minute, "Policy Quotas Test", "Policy Quotas Test", logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		migrateAndPopulateTags(realDb, 1)
+		quotasStore = &store.QuotasStore{Conn: realDb}
+	})
+
+	AfterEach(func() {
+		Expect(realDb.Close()).To(Succeed())
+		testhelpers.RemoveDatabase(dbConf)
+	})
+
+	It("returns no quotas when none are set", func() {
+		quotas, err := quotasStore.Quotas()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quotas).To(BeEmpty())
+	})
+
+	It("sets, updates and deletes quotas", func() {
+		Expect(quotasStore.SetQuota(store.Quota{Type: "space", GUID: "some-space-guid", MaxPolicies: 10})).To(Succeed())
+		Expect(quotasStore.SetQuota(store.Quota{Type: "org", GUID: "some-org-guid", MaxPolicies: 100})).To(Succeed())
+
+		quotas, err := quotasStore.Quotas()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quotas).To(Equal([]store.Quota{
+			{Type: "org", GUID: "some-org-guid", MaxPolicies: 100},
+			{Type: "space", GUID: "some-space-guid", MaxPolicies: 10},
+		}))
+
+		By("updating an existing quota")
+		Expect(quotasStore.SetQuota(store.Quota{Type: "space", GUID: "some-space-guid", MaxPolicies: 20})).To(Succeed())
+
+		quotas, err = quotasStore.Quotas()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quotas).To(ContainElement(store.Quota{Type: "space", GUID: "some-space-guid", MaxPolicies: 20}))
+		Expect(quotas).To(HaveLen(2))
+
+		By("deleting a quota")
+		Expect(quotasStore.DeleteQuota("org", "some-org-guid")).To(Succeed())
+
+		quotas, err = quotasStore.Quotas()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quotas).To(Equal([]store.Quota{{Type: "space", GUID: "some-space-guid", MaxPolicies: 20}}))
+
+		By("ignoring quotas that do not exist")
+		Expect(quotasStore.DeleteQuota("org", "some-org-guid")).To(Succeed())
+	})
+
+	Context("when the policy_quotas table does not exist", func() {
+		BeforeEach(func() {
+			_, err := realDb.Exec(`DROP TABLE policy_quotas`)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns sensible errors", func() {
+			_, err := quotasStore.Quotas()
+			Expect(err).To(MatchError(ContainSubstring("selecting policy quotas:")))
+
+			err = quotasStore.SetQuota(store.Quota{Type: "space", GUID: "some-space-guid", MaxPolicies: 10})
+			Expect(err).To(MatchError(ContainSubstring("setting policy quota:")))
+
+			err = quotasStore.DeleteQuota("space", "some-space-guid")
+			Expect(err).To(MatchError(ContainSubstring("deleting policy quota:")))
+		})
+	})
+})
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"code.cloudfoundry.org/policy-server/store/helpers"
+)
+
+// SourceScope is the space and org that the policies of a source count
+// towards for quotas. Apps and spaces cannot move, so it is stored on the row
+// of the source once, and kept until the row is freed. The guids are empty
+// when the source no longer exists.
+type SourceScope struct {
+	GUID      string
+	SpaceGUID string
+	OrgGUID   string
+}
+
+// UnscopedSources returns the sources of policies whose scope is not stored yet
+func (s *store) UnscopedSources() ([]Source, error) {
+	rows, err := s.conn.Query(`
+		SELECT DISTINCT src_grp.guid, src_grp.type
+		FROM policies
+		JOIN "groups" AS src_grp ON (src_grp.id = policies.group_id)
+		WHERE src_grp.org_guid IS NULL
+		ORDER BY src_grp.guid`)
+	if err != nil {
+		return nil, fmt.Errorf("selecting unscoped sources: %s", err)
+	}
+	defer rows.Close()
+
+	sources := []Source{}
+	for rows.Next() {
+		var source Source
+		err := rows.Scan(&source.ID, &source.Type)
+		if err != nil {
+			return nil, fmt.Errorf("scanning unscoped source result: %s", err)
+		}
+		if source.Type == GroupTypeApp {
+			source.Type = ""
+		}
+		sources = append(sources, source)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("selecting unscoped sources, getting next row: %s", err) // untested
+	}
+	return sources, nil
+}
+
+// SetSourceScopes stores the scopes of the sources. Sources that are no
+// longer stored are skipped. The policies do not change, so last updated is
+// left as is.
+func (s *store) SetSourceScopes(scopes []SourceScope) error {
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	tx, err := s.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("create transaction: %s", err)
+	}
+
+	for _, scope := range scopes {
+		_, err = tx.Exec(tx.Rebind(`UPDATE "groups" SET space_guid = ?, org_guid = ? WHERE guid = ?`),
+			scope.SpaceGUID, scope.OrgGUID, scope.GUID)
+		if err != nil {
+			return rollback(tx, fmt.Errorf("updating source scope: %s", err))
+		}
+	}
+	return commit(tx)
+}
+
+// PolicyCountsByScope counts the policies whose sources are in each of the
+// spaces or orgs, depending on the scope type. The policies of the excluded
+// sources are not counted.
+func (s *store) PolicyCountsByScope(scopeType string, guids, excludedSourceGuids []string) (map[string]int, error) {
+	counts := map[string]int{}
+	if len(guids) == 0 {
+		return counts, nil
+	}
+
+	var column string
+	switch scopeType {
+	case GroupTypeSpace:
+		column = "src_grp.space_guid"
+	case GroupTypeOrg:
+		column = "src_grp.org_guid"
+	default:
+		return nil, fmt.Errorf("invalid scope type: %s", scopeType)
+	}
+
+	wheres := []string{fmt.Sprintf("%s IN (%s)", column, helpers.QuestionMarks(len(guids)))}
+	bindings := stringsAsInterfaces(guids)
+	if len(excludedSourceGuids) > 0 {
+		wheres = append(wheres, fmt.Sprintf("src_grp.guid NOT IN (%s)", helpers.QuestionMarks(len(excludedSourceGuids))))
+		bindings = append(bindings, stringsAsInterfaces(excludedSourceGuids)...)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, COUNT(*)
+		FROM policies
+		JOIN "groups" AS src_grp ON (src_grp.id = policies.group_id)
+		WHERE %s
+		GROUP BY %s`, column, strings.Join(wheres, " AND "), column)
+	rows, err := s.conn.Query(s.conn.Rebind(query), bindings...)
+	if err != nil {
+		return nil, fmt.Errorf("counting policies by scope: %s", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var guid string
+		var count int
+		err := rows.Scan(&guid, &count)
+		if err != nil {
+			return nil, fmt.Errorf("scanning policy count result: %s", err)
+		}
+		counts[guid] = count
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("counting policies by scope, getting next row: %s", err) // untested
+	}
+	return counts, nil
+}
@@ -4,6 +4,7 @@ package store
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -19,13 +20,52 @@ type Migrator interface {
 	PerformMigrations(driverName string, migrationDb migrations.MigrationDb, maxNumMigrations int) (int, error)
 }
 
+// ErrPoliciesChanged is returned when policies are reconciled against a
+// LastUpdated value that is no longer current
+var ErrPoliciesChanged = errors.New("policies have changed since last_updated")
+
+// ErrPendingPolicyNotFound is returned when a pending policy is approved that
+// has been approved, rejected or withdrawn in the meantime
+var ErrPendingPolicyNotFound = errors.New("pending policy not found")
+
+// DeniedPoliciesError is returned when allow policies are created that
+// already exist as deny policies, as creating them would not lift the deny
+type DeniedPoliciesError struct {
+	Policies []Policy
+}
+
+func (e *DeniedPoliciesError) Error() string {
+	return fmt.Sprintf("%d policies already exist as deny policies", len(e.Policies))
+}
+
 //counterfeiter:generate -o fakes/store.go --fake-name Store . Store
 type Store interface {
 	Create([]Policy) error
 	All() ([]Policy, error)
 	Delete([]Policy) error
+	CreateWithEvent([]Policy, Actor) error
+	DeleteWithEvent([]Policy, Actor) error
+	ReplaceForSource(string, []Policy, []PendingPolicy, Actor, func([]Policy) error) error
+	ReconcileSources([]string, []Policy, int, Actor) error
+	MergeWithEvent([]Policy, []Policy, Actor) error
+	CreateWithPending([]Policy, []Policy, []PendingPolicy, Actor) error
+	ApprovePending(PendingPolicy, Actor) error
+	ImportWithEvent([]Policy, []Policy, Actor) error
+	Quarantine(string, Actor) error
+	Release(string, Actor) error
+	Quarantined() ([]string, error)
 	LastUpdated() (int, error)
+	ExpiredCount(now time.Time) (int, error)
+	DeleteExpired(now time.Time, actor Actor) ([]Policy, error)
+	GroupMembers([]string) ([]GroupMember, error)
+	MemberGroups([]string) ([]string, error)
+	SetGroupMembers(map[string][]string) error
+	UnscopedSources() ([]Source, error)
+	SetSourceScopes([]SourceScope) error
+	PolicyCountsByScope(scopeType string, guids, excludedSourceGuids []string) (map[string]int, error)
 	ByGuids([]string, []string, bool) ([]Policy, error)
+	AllPaginated(Page) ([]Policy, Pagination, error)
+	ByGuidsPaginated([]string, []string, bool, Page) ([]Policy, Pagination, error)
 	CheckDatabase() error
 }
 
@@ -43,6 +83,31 @@ type Database interface {
 	Rebind(string) string
 }
 
+const policiesSelect = `
+		select
+			policies.id,
+			src_grp.guid,
+			src_grp.id,
+			src_grp.type,
+			dst_grp.guid,
+			dst_grp.id,
+			destinations.port,
+			destinations.start_port,
+			destinations.end_port,
+			destinations.protocol,
+			destinations.icmp_type,
+			destinations.icmp_code,
+			policy_metadata.description,
+			policy_metadata.labels,
+			policy_metadata.annotations,
+			policies.expires_at,
+			policies.action
+		from policies
+		left outer join "groups" as src_grp on (policies.group_id = src_grp.id)
+		left outer join destinations on (destinations.id = policies.destination_id)
+		left outer join "groups" as dst_grp on (destinations.group_id = dst_grp.id)
+		left outer join policy_metadata on (policy_metadata.policy_id = policies.id)`
+
 type store struct {
 	conn        Database
 	group       GroupRepo
@@ -62,19 +127,475 @@ func New(dbConnectionPool Database, g GroupRepo, d DestinationRepo, p PolicyRepo
 }
 
 func (s *store) Create(policies []Policy) error {
+	return s.create(policies, nil)
+}
+
+// CreateWithEvent creates the policies and records a policy event for the
+// actor in the same transaction.
+func (s *store) CreateWithEvent(policies []Policy, actor Actor) error {
+	return s.create(policies, &actor)
+}
+
+func (s *store) create(policies []Policy, actor *Actor) error {
+	if len(policies) == 0 {
+		return nil
+	}
+	tx, err := s.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("create transaction: %s", err)
+	}
+	err = s.updateLastUpdated(tx)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	created, err := s.createWithTx(tx, policies)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	if actor != nil {
+		err = createPolicyEvent(tx, PolicyEventCreate, *actor, created)
+		if err != nil {
+			return rollback(tx, err)
+		}
+	}
+
+	return commit(tx)
+}
+
+func (s *store) Delete(policies []Policy) error {
+	return s.delete(policies, nil)
+}
+
+// DeleteWithEvent deletes the policies and records a policy event for the
+// actor in the same transaction.
+func (s *store) DeleteWithEvent(policies []Policy, actor Actor) error {
+	return s.delete(policies, &actor)
+}
+
+func (s *store) delete(policies []Policy, actor *Actor) error {
 	if len(policies) == 0 {
 		return nil
 	}
-	tx, err := s.conn.Beginx()
+	tx, err := s.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("create transaction: %s", err)
+	}
+	err = s.updateLastUpdated(tx)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	deleted, err := s.deleteWithTx(tx, policies)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	if actor != nil {
+		err = createPolicyEvent(tx, PolicyEventDelete, *actor, deleted)
+		if err != nil {
+			return rollback(tx, err)
+		}
+	}
+
+	return commit(tx)
+}
+
+// ReplaceForSource replaces every policy of the source app with the given
+// policies in one transaction. The existing policies are read with FOR UPDATE
+// and passed to checkExisting before anything is written, so that the access
+// check sees the policies that are actually removed. Nothing is written when
+// checkExisting returns an error. Pending policies that already exist are kept,
+// and the others are held until they are approved.
+func (s *store) ReplaceForSource(sourceGuid string, policies []Policy, pending []PendingPolicy, actor Actor, checkExisting func([]Policy) error) error {
+	tx, err := s.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("create transaction: %s", err)
+	}
+	err = s.updateLastUpdated(tx)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	existingPolicies, err := s.bySourceForUpdateWithTx(tx, sourceGuid)
+	if err != nil {
+		return rollback(tx, err)
+	}
+	if checkExisting != nil {
+		err = checkExisting(existingPolicies)
+		if err != nil {
+			return rollback(tx, err)
+		}
+	}
+
+	// a policy that already exists was consented to before, so it is kept as
+	// it is instead of being held again
+	policies = append([]Policy{}, policies...)
+	var held []PendingPolicy
+	for _, p := range pending {
+		if kept, ok := findPolicy(existingPolicies, p.Policy); ok {
+			policies = append(policies, kept)
+			continue
+		}
+		held = append(held, p)
+	}
+
+	createdPolicies := policiesNotIn(policies, existingPolicies)
+	deletedPolicies := policiesNotIn(existingPolicies, policies)
+
+	// create before deleting so that groups still referenced by the new
+	// policies keep their tags
+	createdPolicies, err = s.createWithTx(tx, createdPolicies)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	deletedPolicies, err = s.deleteWithTx(tx, deletedPolicies)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = s.updateKeptPoliciesWithTx(tx, policiesWithChangedAttributes(policies, existingPolicies))
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = createPolicyEvent(tx, PolicyEventCreate, actor, createdPolicies)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = createPolicyEvent(tx, PolicyEventDelete, actor, deletedPolicies)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = createPendingWithTx(tx, held, actor)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	return commit(tx)
+}
+
+// ReconcileSources replaces every policy of the given sources with the given
+// policies in one transaction. It fails with ErrPoliciesChanged when any
+// policy has changed since lastUpdated, so that concurrent reconciles cannot
+// overwrite each other. Nothing is written when the policies are already in
+// the desired state, which keeps lastUpdated current.
+func (s *store) ReconcileSources(sourceGuids []string, policies []Policy, lastUpdated int, actor Actor) error {
+	tx, err := s.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("create transaction: %s", err)
+	}
+
+	// lock the row until the transaction ends, so that no other change can
+	// happen between the check and the update
+	var timestamp time.Time
+	err = tx.QueryRow(`SELECT last_updated FROM policies_info LIMIT 1 FOR UPDATE`).Scan(&timestamp)
+	if err != nil {
+		return rollback(tx, fmt.Errorf("getting last updated: %s", err))
+	}
+	if int(timestamp.UnixNano()) != lastUpdated {
+		return rollback(tx, ErrPoliciesChanged)
+	}
+
+	var existingPolicies []Policy
+	for _, sourceGuid := range sourceGuids {
+		sourcePolicies, err := s.bySourceWithTx(tx, sourceGuid)
+		if err != nil {
+			return rollback(tx, err)
+		}
+		existingPolicies = append(existingPolicies, sourcePolicies...)
+	}
+	createdPolicies := policiesNotIn(policies, existingPolicies)
+	deletedPolicies := policiesNotIn(existingPolicies, policies)
+	updatedPolicies := policiesWithChangedAttributes(policies, existingPolicies)
+	if len(createdPolicies) == 0 && len(deletedPolicies) == 0 && len(updatedPolicies) == 0 {
+		return rollback(tx, nil)
+	}
+
+	err = s.updateLastUpdated(tx)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	// create before deleting so that groups still referenced by the new
+	// policies keep their tags
+	createdPolicies, err = s.createWithTx(tx, createdPolicies)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	deletedPolicies, err = s.deleteWithTx(tx, deletedPolicies)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = s.updateKeptPoliciesWithTx(tx, updatedPolicies)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = createPolicyEvent(tx, PolicyEventCreate, actor, createdPolicies)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = createPolicyEvent(tx, PolicyEventDelete, actor, deletedPolicies)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = createPolicyEvent(tx, PolicyEventUpdate, actor, updatedPolicies)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	return commit(tx)
+}
+
+// MergeWithEvent creates the merged policies and deletes the policies that
+// they replace, recording policy events for the actor in the same transaction.
+func (s *store) MergeWithEvent(created, deleted []Policy, actor Actor) error {
+	if len(created) == 0 && len(deleted) == 0 {
+		return nil
+	}
+	tx, err := s.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("create transaction: %s", err)
+	}
+	err = s.updateLastUpdated(tx)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	// create before deleting so that groups still referenced by the merged
+	// policies keep their tags
+	created, err = s.createWithTx(tx, created)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	deleted, err = s.deleteWithTx(tx, deleted)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = createPolicyEvent(tx, PolicyEventCreate, actor, created)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = createPolicyEvent(tx, PolicyEventDelete, actor, deleted)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	return commit(tx)
+}
+
+// CreateWithPending creates the policies, deletes the policies that they
+// replace, and holds the pending policies until they are approved, in the same
+// transaction, so that a request is never stored in part.
+func (s *store) CreateWithPending(created, replaced []Policy, pending []PendingPolicy, actor Actor) error {
+	tx, err := s.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("create transaction: %s", err)
+	}
+
+	if len(created) > 0 || len(replaced) > 0 {
+		err = s.updateLastUpdated(tx)
+		if err != nil {
+			return rollback(tx, err)
+		}
+
+		created, err = s.createWithTx(tx, created)
+		if err != nil {
+			return rollback(tx, err)
+		}
+
+		replaced, err = s.deleteWithTx(tx, replaced)
+		if err != nil {
+			return rollback(tx, err)
+		}
+
+		err = createPolicyEvent(tx, PolicyEventCreate, actor, created)
+		if err != nil {
+			return rollback(tx, err)
+		}
+
+		err = createPolicyEvent(tx, PolicyEventDelete, actor, replaced)
+		if err != nil {
+			return rollback(tx, err)
+		}
+	}
+
+	err = createPendingWithTx(tx, pending, actor)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	return commit(tx)
+}
+
+// ApprovePending creates the pending policy and deletes it from the pending
+// policies in one transaction. The policy event records the requester of the
+// pending policy as the actor, and the approver as who approved it.
+func (s *store) ApprovePending(pending PendingPolicy, approver Actor) error {
+	tx, err := s.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("create transaction: %s", err)
+	}
+
+	err = deletePendingWithTx(tx, pending.ID)
+	if err == sql.ErrNoRows {
+		return rollback(tx, ErrPendingPolicyNotFound)
+	}
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = s.updateLastUpdated(tx)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	created, err := s.createWithTx(tx, []Policy{pending.Policy})
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	actor := Actor{Name: pending.RequestedBy, ClientID: pending.ClientID, ApprovedBy: approver.Name}
+	err = createPolicyEvent(tx, PolicyEventCreate, actor, created)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	return commit(tx)
+}
+
+// ImportWithEvent creates the new policies and replaces the metadata, expiry
+// and action of the updated ones, recording policy events for the actor in the
+// same transaction.
+func (s *store) ImportWithEvent(created, updated []Policy, actor Actor) error {
+	if len(created) == 0 && len(updated) == 0 {
+		return nil
+	}
+	tx, err := s.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("create transaction: %s", err)
+	}
+	err = s.updateLastUpdated(tx)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	created, err = s.createWithTx(tx, created)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = s.updateKeptPoliciesWithTx(tx, updated)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = createPolicyEvent(tx, PolicyEventCreate, actor, created)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = createPolicyEvent(tx, PolicyEventUpdate, actor, updated)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	return commit(tx)
+}
+
+// Quarantine flags the group of an app so that its policies are suppressed,
+// and records a policy event with the policies of the app. The group is
+// created when the app has no policies yet.
+func (s *store) Quarantine(appGuid string, actor Actor) error {
+	tx, err := s.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("create transaction: %s", err)
+	}
+	err = s.updateLastUpdated(tx)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	groupID, err := s.group.Create(tx, appGuid, GroupTypeApp)
+	if err != nil {
+		return rollback(tx, fmt.Errorf("creating group: %s", err))
+	}
+
+	changed, err := s.group.SetQuarantined(tx, groupID, true)
+	if err != nil {
+		return rollback(tx, fmt.Errorf("quarantining group: %s", err))
+	}
+	if !changed {
+		// the app is already quarantined
+		return rollback(tx, nil)
+	}
+
+	policies, err := s.byAppWithTx(tx, appGuid)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	err = createAppEvent(tx, PolicyEventQuarantine, actor, appGuid, policies)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	return commit(tx)
+}
+
+// Release lifts the quarantine of an app, and records a policy event with the
+// policies of the app. The group is freed when the app has no policies.
+func (s *store) Release(appGuid string, actor Actor) error {
+	tx, err := s.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("create transaction: %s", err)
+	}
+	err = s.updateLastUpdated(tx)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	groupID, err := s.group.GetID(tx, appGuid)
+	if err == sql.ErrNoRows {
+		// the app is not quarantined
+		return rollback(tx, nil)
+	}
+	if err != nil {
+		return rollback(tx, fmt.Errorf("getting group id: %s", err))
+	}
+
+	changed, err := s.group.SetQuarantined(tx, groupID, false)
+	if err != nil {
+		return rollback(tx, fmt.Errorf("releasing group: %s", err))
+	}
+	if !changed {
+		// the app is not quarantined
+		return rollback(tx, nil)
+	}
+
+	err = s.deleteGroupRowIfLast(tx, groupID)
 	if err != nil {
-		return fmt.Errorf("create transaction: %s", err)
+		return rollback(tx, fmt.Errorf("deleting group row: %s", err))
 	}
-	err = s.updateLastUpdated(tx)
+
+	policies, err := s.byAppWithTx(tx, appGuid)
 	if err != nil {
 		return rollback(tx, err)
 	}
 
-	err = s.createWithTx(tx, policies)
+	err = createAppEvent(tx, PolicyEventRelease, actor, appGuid, policies)
 	if err != nil {
 		return rollback(tx, err)
 	}
@@ -82,25 +603,28 @@ func (s *store) Create(policies []Policy) error {
 	return commit(tx)
 }
 
-func (s *store) Delete(policies []Policy) error {
-	if len(policies) == 0 {
-		return nil
-	}
-	tx, err := s.conn.Beginx()
-	if err != nil {
-		return fmt.Errorf("create transaction: %s", err)
-	}
-	err = s.updateLastUpdated(tx)
+// Quarantined returns the guids of the quarantined apps
+func (s *store) Quarantined() ([]string, error) {
+	rows, err := s.conn.Query(`SELECT guid FROM "groups" WHERE quarantined = true ORDER BY guid`)
 	if err != nil {
-		return rollback(tx, err)
+		return nil, fmt.Errorf("selecting quarantined groups: %s", err)
 	}
+	defer rows.Close()
 
-	err = s.deleteWithTx(tx, policies)
+	guids := []string{}
+	for rows.Next() {
+		var guid string
+		err := rows.Scan(&guid)
+		if err != nil {
+			return nil, fmt.Errorf("scanning quarantined group result: %s", err)
+		}
+		guids = append(guids, guid)
+	}
+	err = rows.Err()
 	if err != nil {
-		return rollback(tx, err)
+		return nil, fmt.Errorf("selecting quarantined groups, getting next row: %s", err) // untested
 	}
-
-	return commit(tx)
+	return guids, nil
 }
 
 func (s *store) LastUpdated() (int, error) {
@@ -112,21 +636,73 @@ func (s *store) LastUpdated() (int, error) {
 	return int(timestamp.UnixNano()), err
 }
 
+// ExpiredCount returns the number of policies that have expired by now but
+// have not been deleted by the policy cleaner yet. It only reads the index on
+// expires_at, which has few expired entries as the cleaner deletes them.
+func (s *store) ExpiredCount(now time.Time) (int, error) {
+	var count int
+	query := helpers.RebindForSQLDialect(`SELECT COUNT(*) FROM policies WHERE expires_at <= ?`, s.conn.DriverName())
+	err := s.conn.QueryRow(query, now).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("counting expired policies: %s", err)
+	}
+	return count, nil
+}
+
+// DeleteExpired deletes the policies that have expired by now, and returns
+// them. They are selected and deleted in one transaction, so that a policy
+// whose expiry is extended in the meantime is kept.
+func (s *store) DeleteExpired(now time.Time, actor Actor) ([]Policy, error) {
+	tx, err := s.conn.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("create transaction: %s", err)
+	}
+	err = s.updateLastUpdated(tx)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+
+	expired, err := s.expiredWithTx(tx, now)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+	if len(expired) == 0 {
+		return nil, rollback(tx, nil)
+	}
+
+	deleted, err := s.deleteWithTx(tx, expired)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+
+	err = createPolicyEvent(tx, PolicyEventDelete, actor, deleted)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+
+	return deleted, commit(tx)
+}
+
 func (s *store) CheckDatabase() error {
 	var result int
 	return s.conn.QueryRow("SELECT 1").Scan(&result)
 }
 
-func (s *store) createWithTx(tx db.Transaction, policies []Policy) error {
+// createWithTx creates the policies, and returns those that did not exist
+// yet. The metadata and expiry of the policies that exist are replaced when
+// they are given. It fails with a DeniedPoliciesError when allow policies
+// exist as deny policies.
+func (s *store) createWithTx(tx db.Transaction, policies []Policy) ([]Policy, error) {
+	var created, denied []Policy
 	for _, policy := range policies {
-		sourceGroupId, err := s.group.Create(tx, policy.Source.ID, "app")
+		sourceGroupId, err := s.group.Create(tx, policy.Source.ID, policy.Source.GroupType())
 		if err != nil {
-			return fmt.Errorf("creating group: %s", err)
+			return nil, fmt.Errorf("creating group: %s", err)
 		}
 
-		destinationGroupId, err := s.group.Create(tx, policy.Destination.ID, "app")
+		destinationGroupId, err := s.group.Create(tx, policy.Destination.ID, GroupTypeApp)
 		if err != nil {
-			return fmt.Errorf("creating group: %s", err)
+			return nil, fmt.Errorf("creating group: %s", err)
 		}
 
 		destinationId, err := s.destination.Create(
@@ -136,27 +712,67 @@ func (s *store) createWithTx(tx db.Transaction, policies []Policy) error {
 			policy.Destination.Ports.Start,
 			policy.Destination.Ports.End,
 			policy.Destination.Protocol,
+			policy.Destination.ICMPType,
+			policy.Destination.ICMPCode,
 		)
 		if err != nil {
-			return fmt.Errorf("creating destination: %s", err)
+			return nil, fmt.Errorf("creating destination: %s", err)
 		}
 
-		err = s.policy.Create(tx, sourceGroupId, destinationId)
+		inserted, err := s.policy.Create(tx, sourceGroupId, destinationId)
 		if err != nil {
-			return fmt.Errorf("creating policy: %s", err)
+			return nil, fmt.Errorf("creating policy: %s", err)
+		}
+		if inserted {
+			created = append(created, policy)
+		} else if !policy.IsDeny() {
+			action, err := s.policy.Action(tx, sourceGroupId, destinationId)
+			if err != nil {
+				return nil, fmt.Errorf("getting policy action: %s", err)
+			}
+			if action == PolicyActionDeny {
+				denied = append(denied, policy)
+				continue
+			}
+		}
+
+		if !policy.Metadata.IsEmpty() {
+			err = setPolicyMetadata(tx, sourceGroupId, destinationId, policy.Metadata)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if policy.ExpiresAt != nil {
+			err = setPolicyExpiry(tx, sourceGroupId, destinationId, policy.ExpiresAt)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if policy.IsDeny() {
+			err = setPolicyAction(tx, sourceGroupId, destinationId, policy.Action)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
-	return nil
+	if len(denied) > 0 {
+		return nil, &DeniedPoliciesError{Policies: denied}
+	}
+	return created, nil
 }
 
-func (s *store) deleteWithTx(tx db.Transaction, policies []Policy) error {
+// deleteWithTx deletes the policies, and returns those that existed
+func (s *store) deleteWithTx(tx db.Transaction, policies []Policy) ([]Policy, error) {
+	var deleted []Policy
 	for _, p := range policies {
 		sourceGroupID, err := s.group.GetID(tx, p.Source.ID)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				continue
 			} else {
-				return fmt.Errorf("getting source id: %s", err)
+				return nil, fmt.Errorf("getting source id: %s", err)
 			}
 		}
 
@@ -165,7 +781,7 @@ func (s *store) deleteWithTx(tx db.Transaction, policies []Policy) error {
 			if err == sql.ErrNoRows {
 				continue
 			} else {
-				return fmt.Errorf("getting destination group id: %s", err)
+				return nil, fmt.Errorf("getting destination group id: %s", err)
 			}
 		}
 
@@ -176,12 +792,14 @@ func (s *store) deleteWithTx(tx db.Transaction, policies []Policy) error {
 			p.Destination.Ports.Start,
 			p.Destination.Ports.End,
 			p.Destination.Protocol,
+			p.Destination.ICMPType,
+			p.Destination.ICMPCode,
 		)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				continue
 			} else {
-				return fmt.Errorf("getting destination id: %s", err)
+				return nil, fmt.Errorf("getting destination id: %s", err)
 			}
 		}
 
@@ -190,29 +808,76 @@ func (s *store) deleteWithTx(tx db.Transaction, policies []Policy) error {
 			if err == sql.ErrNoRows {
 				continue
 			} else {
-				return fmt.Errorf("deleting policy: %s", err)
+				return nil, fmt.Errorf("deleting policy: %s", err)
 			}
 		}
+		deleted = append(deleted, p)
 
 		destIDCount, err := s.policy.CountWhereDestinationID(tx, destID)
 		if err != nil {
-			return fmt.Errorf("counting destination id: %s", err)
+			return nil, fmt.Errorf("counting destination id: %s", err)
 		}
 		if destIDCount == 0 {
 			err = s.destination.Delete(tx, destID)
 			if err != nil {
-				return fmt.Errorf("deleting destination: %s", err)
+				return nil, fmt.Errorf("deleting destination: %s", err)
 			}
 		}
 
 		err = s.deleteGroupRowIfLast(tx, sourceGroupID)
 		if err != nil {
-			return fmt.Errorf("deleting group row: %s", err)
+			return nil, fmt.Errorf("deleting group row: %s", err)
 		}
 
 		err = s.deleteGroupRowIfLast(tx, destGroupID)
 		if err != nil {
-			return fmt.Errorf("deleting group row: %s", err)
+			return nil, fmt.Errorf("deleting group row: %s", err)
+		}
+	}
+	return deleted, nil
+}
+
+// updateKeptPoliciesWithTx replaces the metadata, expiry and action of
+// policies that already exist
+func (s *store) updateKeptPoliciesWithTx(tx db.Transaction, policies []Policy) error {
+	for _, p := range policies {
+		sourceGroupID, err := s.group.GetID(tx, p.Source.ID)
+		if err != nil {
+			return fmt.Errorf("getting source id: %s", err)
+		}
+
+		destGroupID, err := s.group.GetID(tx, p.Destination.ID)
+		if err != nil {
+			return fmt.Errorf("getting destination group id: %s", err)
+		}
+
+		destID, err := s.destination.GetID(
+			tx,
+			destGroupID,
+			p.Destination.Port,
+			p.Destination.Ports.Start,
+			p.Destination.Ports.End,
+			p.Destination.Protocol,
+			p.Destination.ICMPType,
+			p.Destination.ICMPCode,
+		)
+		if err != nil {
+			return fmt.Errorf("getting destination id: %s", err)
+		}
+
+		err = setPolicyMetadata(tx, sourceGroupID, destID, p.Metadata)
+		if err != nil {
+			return err
+		}
+
+		err = setPolicyExpiry(tx, sourceGroupID, destID, p.ExpiresAt)
+		if err != nil {
+			return err
+		}
+
+		err = setPolicyAction(tx, sourceGroupID, destID, p.Action)
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -229,18 +894,89 @@ func (s *store) deleteGroupRowIfLast(tx db.Transaction, groupId int) error {
 		return err
 	}
 
-	if policiesGroupIDCount == 0 && destinationsGroupIDCount == 0 {
+	memberGroupIDCount, err := s.group.CountWhereMemberID(tx, groupId)
+	if err != nil {
+		return err
+	}
+
+	if policiesGroupIDCount == 0 && destinationsGroupIDCount == 0 && memberGroupIDCount == 0 {
+		memberIDs, err := s.group.DeleteMembers(tx, groupId)
+		if err != nil {
+			return err
+		}
+
 		err = s.group.Delete(tx, groupId)
 		if err != nil {
 			return err
 		}
+
+		// the apps of a space or org keep their rows only while they are
+		// referenced by something else
+		for _, memberID := range memberIDs {
+			err = s.deleteGroupRowIfLast(tx, memberID)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+func (s *store) bySourceWithTx(tx db.Transaction, sourceGuid string) ([]Policy, error) {
+	rows, err := tx.Queryx(tx.Rebind(policiesSelect+` where src_grp.guid = ?`), sourceGuid)
+	if err != nil {
+		return nil, fmt.Errorf("listing source policies: %s", err)
+	}
+
+	defer rows.Close() // untested
+	policies, _, err := s.scanPolicies(rows.Rows)
+	return policies, err
+}
+
+// bySourceForUpdateWithTx lists the policies of the source app and locks
+// their rows until the transaction ends
+func (s *store) bySourceForUpdateWithTx(tx db.Transaction, sourceGuid string) ([]Policy, error) {
+	// postgres cannot lock the nullable side of the outer joins
+	forUpdate := ` FOR UPDATE`
+	if tx.DriverName() == "postgres" {
+		forUpdate = ` FOR UPDATE OF policies`
+	}
+
+	rows, err := tx.Queryx(tx.Rebind(policiesSelect+` where src_grp.guid = ?`+forUpdate), sourceGuid)
+	if err != nil {
+		return nil, fmt.Errorf("listing source policies: %s", err)
+	}
+
+	defer rows.Close() // untested
+	policies, _, err := s.scanPolicies(rows.Rows)
+	return policies, err
+}
+
+func (s *store) expiredWithTx(tx db.Transaction, now time.Time) ([]Policy, error) {
+	rows, err := tx.Queryx(tx.Rebind(policiesSelect+` where policies.expires_at <= ?`), now)
+	if err != nil {
+		return nil, fmt.Errorf("listing expired policies: %s", err)
+	}
+
+	defer rows.Close() // untested
+	policies, _, err := s.scanPolicies(rows.Rows)
+	return policies, err
+}
+
+func (s *store) byAppWithTx(tx db.Transaction, appGuid string) ([]Policy, error) {
+	where, whereBindings := byGuidsWhere([]string{appGuid}, []string{appGuid}, false)
+	rows, err := tx.Queryx(tx.Rebind(policiesSelect+` where `+where), whereBindings...)
+	if err != nil {
+		return nil, fmt.Errorf("listing app policies: %s", err)
+	}
+
+	defer rows.Close() // untested
+	policies, _, err := s.scanPolicies(rows.Rows)
+	return policies, err
+}
+
 func (s *store) policiesQuery(query string, args ...interface{}) ([]Policy, error) {
-	var policies []Policy
 	rebindedQuery := helpers.RebindForSQLDialect(query, s.conn.DriverName())
 
 	rows, err := s.conn.Query(rebindedQuery, args...)
@@ -249,28 +985,60 @@ func (s *store) policiesQuery(query string, args ...interface{}) ([]Policy, erro
 	}
 
 	defer rows.Close() // untested
+	policies, _, err := s.scanPolicies(rows)
+	return policies, err
+}
+
+// scanPolicies returns the policies along with the ids of their rows, which
+// are used as pagination cursors
+func (s *store) scanPolicies(rows *sql.Rows) ([]Policy, []int, error) {
+	var policies []Policy
+	var ids []int
 	for rows.Next() {
 		var sourceId, destinationId, protocol string
-		var port, startPort, endPort, sourceTag, destinationTag int
-		err = rows.Scan(
+		var action string
+		var sourceType, description, labels, annotations sql.NullString
+		var expiresAt sql.NullTime
+		var id, port, startPort, endPort, icmpType, icmpCode, sourceTag, destinationTag int
+		err := rows.Scan(
+			&id,
 			&sourceId,
 			&sourceTag,
+			&sourceType,
 			&destinationId,
 			&destinationTag,
 			&port,
 			&startPort,
 			&endPort,
 			&protocol,
+			&icmpType,
+			&icmpCode,
+			&description,
+			&labels,
+			&annotations,
+			&expiresAt,
+			&action,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("listing all: %s", err)
+			return nil, nil, fmt.Errorf("listing all: %s", err)
+		}
+
+		metadata, err := scanMetadata(description, labels, annotations)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		source := Source{
+			ID:  sourceId,
+			Tag: s.tagIntToString(sourceTag),
+		}
+		if sourceType.Valid && sourceType.String != GroupTypeApp {
+			source.Type = sourceType.String
 		}
 
+		ids = append(ids, id)
 		policies = append(policies, Policy{
-			Source: Source{
-				ID:  sourceId,
-				Tag: s.tagIntToString(sourceTag),
-			},
+			Source: source,
 			Destination: Destination{
 				ID:       destinationId,
 				Tag:      s.tagIntToString(destinationTag),
@@ -280,23 +1048,89 @@ func (s *store) policiesQuery(query string, args ...interface{}) ([]Policy, erro
 					Start: startPort,
 					End:   endPort,
 				},
+				ICMPType: icmpType,
+				ICMPCode: icmpCode,
 			},
+			Metadata:  metadata,
+			ExpiresAt: scanExpiry(expiresAt),
+			Action:    scanAction(action),
 		})
 	}
-	err = rows.Err()
+	err := rows.Err()
 	if err != nil {
-		return nil, fmt.Errorf("listing all, getting next row: %s", err) // untested
+		return nil, nil, fmt.Errorf("listing all, getting next row: %s", err) // untested
 	}
-	return policies, nil
+	return policies, ids, nil
 }
 
 func (s *store) ByGuids(srcGuids, destGuids []string, inSourceAndDest bool) ([]Policy, error) {
-	numSourceGuids := len(srcGuids)
-	numDestinationGuids := len(destGuids)
-	if numSourceGuids == 0 && numDestinationGuids == 0 {
+	if len(srcGuids) == 0 && len(destGuids) == 0 {
 		return []Policy{}, nil
 	}
 
+	where, whereBindings := byGuidsWhere(srcGuids, destGuids, inSourceAndDest)
+	return s.policiesQuery(policiesSelect+" where "+where+";", whereBindings...)
+}
+
+func (s *store) All() ([]Policy, error) {
+	return s.policiesQuery(policiesSelect + ";")
+}
+
+func (s *store) ByGuidsPaginated(srcGuids, destGuids []string, inSourceAndDest bool, page Page) ([]Policy, Pagination, error) {
+	if len(srcGuids) == 0 && len(destGuids) == 0 {
+		return []Policy{}, Pagination{}, nil
+	}
+
+	where, whereBindings := byGuidsWhere(srcGuids, destGuids, inSourceAndDest)
+	return s.paginatedPoliciesQuery("("+where+")", whereBindings, page)
+}
+
+func (s *store) AllPaginated(page Page) ([]Policy, Pagination, error) {
+	return s.paginatedPoliciesQuery("", nil, page)
+}
+
+func (s *store) paginatedPoliciesQuery(where string, whereBindings []interface{}, page Page) ([]Policy, Pagination, error) {
+	var wheres []string
+	if where != "" {
+		wheres = append(wheres, where)
+	}
+	if page.From > 0 {
+		wheres = append(wheres, "policies.id >= ?")
+		whereBindings = append(whereBindings, page.From)
+	}
+
+	query := policiesSelect
+	if len(wheres) > 0 {
+		query += " where " + strings.Join(wheres, " AND ")
+	}
+	query += " order by policies.id"
+
+	if page.Limit > 0 {
+		// we don't use a placeholder because limit is an integer and it is safe to interpolate it
+		query = fmt.Sprintf("%s limit %d", query, page.Limit+1)
+	}
+
+	rows, err := s.conn.Query(helpers.RebindForSQLDialect(query+";", s.conn.DriverName()), whereBindings...)
+	if err != nil {
+		return nil, Pagination{}, fmt.Errorf("listing all: %s", err)
+	}
+
+	defer rows.Close() // untested
+	policies, ids, err := s.scanPolicies(rows)
+	if err != nil {
+		return nil, Pagination{}, err
+	}
+
+	if page.Limit > 0 && len(policies) > page.Limit {
+		return policies[:page.Limit], Pagination{Next: ids[page.Limit]}, nil
+	}
+	return policies, Pagination{}, nil
+}
+
+func byGuidsWhere(srcGuids, destGuids []string, inSourceAndDest bool) (string, []interface{}) {
+	numSourceGuids := len(srcGuids)
+	numDestinationGuids := len(destGuids)
+
 	var wheres []string
 	if numSourceGuids > 0 {
 		wheres = append(wheres, fmt.Sprintf("src_grp.guid in (%s)", helpers.QuestionMarks(numSourceGuids)))
@@ -306,29 +1140,10 @@ func (s *store) ByGuids(srcGuids, destGuids []string, inSourceAndDest bool) ([]P
 		wheres = append(wheres, fmt.Sprintf("dst_grp.guid in (%s)", helpers.QuestionMarks(numDestinationGuids)))
 	}
 
-	query := `
-		select
-			src_grp.guid,
-			src_grp.id,
-			dst_grp.guid,
-			dst_grp.id,
-			destinations.port,
-			destinations.start_port,
-			destinations.end_port,
-			destinations.protocol
-		from policies
-		left outer join "groups" as src_grp on (policies.group_id = src_grp.id)
-		left outer join destinations on (destinations.id = policies.destination_id)
-		left outer join "groups" as dst_grp on (destinations.group_id = dst_grp.id)`
-
-	if len(wheres) > 0 {
-		andOr := " OR "
-		if inSourceAndDest {
-			andOr = " AND "
-		}
-		query += " where " + strings.Join(wheres, andOr)
+	andOr := " OR "
+	if inSourceAndDest {
+		andOr = " AND "
 	}
-	query += ";"
 
 	whereBindings := make([]interface{}, numSourceGuids+numDestinationGuids)
 	for i := 0; i < len(whereBindings); i++ {
@@ -339,24 +1154,34 @@ func (s *store) ByGuids(srcGuids, destGuids []string, inSourceAndDest bool) ([]P
 		}
 	}
 
-	return s.policiesQuery(query, whereBindings...)
+	return strings.Join(wheres, andOr), whereBindings
 }
 
-func (s *store) All() ([]Policy, error) {
-	return s.policiesQuery(`
-		select
-			src_grp.guid,
-			src_grp.id,
-			dst_grp.guid,
-			dst_grp.id,
-			destinations.port,
-			destinations.start_port,
-			destinations.end_port,
-			destinations.protocol
-		from policies
-		left outer join "groups" as src_grp on (policies.group_id = src_grp.id)
-		left outer join destinations on (destinations.id = policies.destination_id)
-		left outer join "groups" as dst_grp on (destinations.group_id = dst_grp.id);`)
+// findPolicy returns the policy of the list that equals the given policy
+func findPolicy(policies []Policy, policy Policy) (Policy, bool) {
+	for _, p := range policies {
+		if p.Equals(policy) {
+			return p, true
+		}
+	}
+	return Policy{}, false
+}
+
+func policiesNotIn(policies, keep []Policy) []Policy {
+	var result []Policy
+	for _, p := range policies {
+		found := false
+		for _, k := range keep {
+			if p.Equals(k) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, p)
+		}
+	}
+	return result
 }
 
 func (s *store) tagIntToString(tag int) string {
@@ -367,3 +1192,91 @@ func (s *store) updateLastUpdated(tx db.Transaction) error {
 	_, err := tx.Exec(`UPDATE policies_info SET last_updated=CURRENT_TIMESTAMP(6)`)
 	return err
 }
+
+// policiesWithChangedAttributes returns the policies that already exist but
+// whose metadata, expiry or action differs from the existing policy
+func policiesWithChangedAttributes(policies, existing []Policy) []Policy {
+	var result []Policy
+	for _, p := range policies {
+		for _, e := range existing {
+			if p.Equals(e) && !p.AttributesEqual(e) {
+				result = append(result, p)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// setPolicyExpiry sets the expiry of the policy between the source group and
+// the destination. A nil expiry means the policy never expires.
+func setPolicyExpiry(tx db.Transaction, sourceGroupID, destinationID int, expiresAt *time.Time) error {
+	var expiry interface{}
+	if expiresAt != nil {
+		expiry = expiresAt.UTC()
+	}
+
+	_, err := tx.Exec(tx.Rebind(`UPDATE policies SET expires_at = ? WHERE group_id = ? AND destination_id = ?`),
+		expiry,
+		sourceGroupID,
+		destinationID,
+	)
+	if err != nil {
+		return fmt.Errorf("setting policy expiry: %s", err)
+	}
+	return nil
+}
+
+// setPolicyAction sets the action of the policy between the source group and
+// the destination. An empty action allows the traffic.
+func setPolicyAction(tx db.Transaction, sourceGroupID, destinationID int, action string) error {
+	if action == "" {
+		action = PolicyActionAllow
+	}
+
+	_, err := tx.Exec(tx.Rebind(`UPDATE policies SET action = ? WHERE group_id = ? AND destination_id = ?`),
+		action,
+		sourceGroupID,
+		destinationID,
+	)
+	if err != nil {
+		return fmt.Errorf("setting policy action: %s", err)
+	}
+	return nil
+}
+
+// scanAction drops the allow action so that allow policies read back the same
+// as they are created.
+func scanAction(action string) string {
+	if action == PolicyActionAllow {
+		return ""
+	}
+	return action
+}
+
+func scanExpiry(expiresAt sql.NullTime) *time.Time {
+	if !expiresAt.Valid {
+		return nil
+	}
+	expiry := expiresAt.Time.UTC()
+	return &expiry
+}
+
+func scanMetadata(description, labels, annotations sql.NullString) (Metadata, error) {
+	var metadata Metadata
+	if !description.Valid {
+		return metadata, nil
+	}
+	metadata.Description = description.String
+
+	var err error
+	metadata.Labels, err = unmarshalStringMap(labels.String)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("unmarshaling policy labels: %s", err)
+	}
+	metadata.Annotations, err = unmarshalStringMap(annotations.String)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("unmarshaling policy annotations: %s", err)
+	}
+	return metadata, nil
+}
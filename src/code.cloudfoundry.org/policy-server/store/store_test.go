@@ -195,6 +195,222 @@ var _ = Describe("Store", func() {
 			Expect(lastUpdatedNew).To(BeNumerically(">", lastUpdatedOriginal))
 		})
 
+		Context("when the source is a space or an org", func() {
+			It("saves the source group with its type", func() {
+				policies := []store.Policy{{
+					Source: store.Source{ID: "some-space-guid", Type: store.GroupTypeSpace},
+					Destination: store.Destination{
+						ID:       "some-app-guid",
+						Protocol: "tcp",
+						Port:     8080,
+						Ports:    store.Ports{Start: 8080, End: 8080},
+					},
+				}, {
+					Source: store.Source{ID: "some-org-guid", Type: store.GroupTypeOrg},
+					Destination: store.Destination{
+						ID:       "some-app-guid",
+						Protocol: "tcp",
+						Port:     8080,
+						Ports:    store.Ports{Start: 8080, End: 8080},
+					},
+				}}
+
+				err := dataStore.Create(policies)
+				Expect(err).NotTo(HaveOccurred())
+
+				tags, err := tagDataStore.Tags()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tags).To(ConsistOf([]store.Tag{
+					{ID: "some-space-guid", Tag: "01", Type: "space"},
+					{ID: "some-app-guid", Tag: "02", Type: "app"},
+					{ID: "some-org-guid", Tag: "03", Type: "org"},
+				}))
+
+				p, err := dataStore.ByGuids([]string{"some-space-guid", "some-org-guid"}, nil, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(p).To(ConsistOf(
+					store.Policy{
+						Source: store.Source{ID: "some-space-guid", Tag: "01", Type: "space"},
+						Destination: store.Destination{
+							ID:       "some-app-guid",
+							Tag:      "02",
+							Protocol: "tcp",
+							Port:     8080,
+							Ports:    store.Ports{Start: 8080, End: 8080},
+						},
+					},
+					store.Policy{
+						Source: store.Source{ID: "some-org-guid", Tag: "03", Type: "org"},
+						Destination: store.Destination{
+							ID:       "some-app-guid",
+							Tag:      "02",
+							Protocol: "tcp",
+							Port:     8080,
+							Ports:    store.Ports{Start: 8080, End: 8080},
+						},
+					},
+				))
+
+				err = dataStore.Delete(policies[:1])
+				Expect(err).NotTo(HaveOccurred())
+
+				p, err = dataStore.All()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(p).To(HaveLen(1))
+				Expect(p[0].Source.Type).To(Equal("org"))
+			})
+		})
+
+		Context("when a policy has an expiry", func() {
+			var policies []store.Policy
+
+			BeforeEach(func() {
+				expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+				policies = []store.Policy{{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "tcp",
+						Port:     8080,
+						Ports:    store.Ports{Start: 8080, End: 8080},
+					},
+					ExpiresAt: &expiresAt,
+				}, {
+					Source: store.Source{ID: "another-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "tcp",
+						Port:     8080,
+						Ports:    store.Ports{Start: 8080, End: 8080},
+					},
+				}}
+
+				err := dataStore.Create(policies)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("saves the expiry", func() {
+				p, err := dataStore.ByGuids([]string{"some-app-guid"}, nil, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(p).To(HaveLen(1))
+				Expect(p[0].ExpiresAt).NotTo(BeNil())
+				Expect(p[0].ExpiresAt.Equal(*policies[0].ExpiresAt)).To(BeTrue())
+
+				p, err = dataStore.ByGuids([]string{"another-app-guid"}, nil, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(p).To(HaveLen(1))
+				Expect(p[0].ExpiresAt).To(BeNil())
+			})
+
+			Context("when the policy is created again with a new expiry", func() {
+				It("replaces the expiry", func() {
+					newExpiresAt := time.Date(2031, 1, 2, 3, 4, 5, 0, time.UTC)
+					policy := policies[0]
+					policy.ExpiresAt = &newExpiresAt
+
+					err := dataStore.Create([]store.Policy{policy})
+					Expect(err).NotTo(HaveOccurred())
+
+					p, err := dataStore.ByGuids([]string{"some-app-guid"}, nil, false)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(p).To(HaveLen(1))
+					Expect(p[0].ExpiresAt.Equal(newExpiresAt)).To(BeTrue())
+				})
+			})
+		})
+
+		Context("when a policy denies traffic", func() {
+			var deny store.Policy
+
+			BeforeEach(func() {
+				deny = store.Policy{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "tcp",
+						Port:     8080,
+						Ports:    store.Ports{Start: 8080, End: 8080},
+					},
+					Action: store.PolicyActionDeny,
+				}
+
+				err := dataStore.Create([]store.Policy{deny})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("saves the action", func() {
+				p, err := dataStore.ByGuids([]string{"some-app-guid"}, nil, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(p).To(HaveLen(1))
+				Expect(p[0].Action).To(Equal("deny"))
+			})
+
+			Context("when the policy is created again as an allow policy", func() {
+				It("fails with the denied policies and keeps denying the traffic", func() {
+					allow := deny
+					allow.Action = ""
+
+					err := dataStore.Create([]store.Policy{allow})
+					Expect(err).To(MatchError(&store.DeniedPoliciesError{Policies: []store.Policy{allow}}))
+
+					p, err := dataStore.ByGuids([]string{"some-app-guid"}, nil, false)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(p).To(HaveLen(1))
+					Expect(p[0].Action).To(Equal("deny"))
+				})
+			})
+		})
+
+		Context("when policies allow icmp", func() {
+			It("saves a destination for each type and code", func() {
+				policies := []store.Policy{{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "icmp",
+						ICMPType: 8,
+						ICMPCode: 0,
+					},
+				}, {
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "icmp",
+						ICMPType: -1,
+						ICMPCode: -1,
+					},
+				}}
+
+				err := dataStore.Create(policies)
+				Expect(err).NotTo(HaveOccurred())
+
+				p, err := dataStore.ByGuids([]string{"some-app-guid"}, nil, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(p).To(HaveLen(2))
+				Expect([]store.Destination{p[0].Destination, p[1].Destination}).To(ConsistOf(
+					SatisfyAll(
+						HaveField("Protocol", "icmp"),
+						HaveField("ICMPType", 8),
+						HaveField("ICMPCode", 0),
+					),
+					SatisfyAll(
+						HaveField("Protocol", "icmp"),
+						HaveField("ICMPType", -1),
+						HaveField("ICMPCode", -1),
+					),
+				))
+
+				By("deleting only the policy with the given type and code")
+				err = dataStore.Delete(policies[:1])
+				Expect(err).NotTo(HaveOccurred())
+
+				p, err = dataStore.ByGuids([]string{"some-app-guid"}, nil, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(p).To(HaveLen(1))
+				Expect(p[0].Destination.ICMPType).To(Equal(-1))
+			})
+		})
+
 		Context("when 0 policies passed in", func() {
 			It("does not update last updated", func() {
 				lastUpdatedOriginal, err := dataStore.LastUpdated()
@@ -489,7 +705,7 @@ var _ = Describe("Store", func() {
 
 			BeforeEach(func() {
 				fakePolicy = &fakes.PolicyRepo{}
-				fakePolicy.CreateReturns(errors.New("some-insert-error"))
+				fakePolicy.CreateReturns(false, errors.New("some-insert-error"))
 
 				migrateAndPopulateTags(realDb, 2)
 				dataStore = store.New(realDb, group, destination, fakePolicy, 2)
@@ -732,6 +948,59 @@ var _ = Describe("Store", func() {
 			})
 		})
 
+		Context("when a page is requested", func() {
+			It("returns pages of matching policies ordered by id", func() {
+				guids := []string{"app-guid-00", "app-guid-01", "app-guid-02"}
+				policies, pagination, err := dataStore.ByGuidsPaginated(guids, guids, false, store.Page{Limit: 2})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies).To(Equal([]store.Policy{allPolicies[0], allPolicies[1]}))
+				Expect(pagination.Next).NotTo(BeZero())
+
+				policies, pagination, err = dataStore.ByGuidsPaginated(guids, guids, false, store.Page{Limit: 2, From: pagination.Next})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies).To(Equal([]store.Policy{allPolicies[2]}))
+				Expect(pagination).To(Equal(store.Pagination{Next: 0}))
+			})
+
+			It("pages through every policy with AllPaginated", func() {
+				policies, pagination, err := dataStore.AllPaginated(store.Page{Limit: 3})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies).To(Equal(allPolicies[:3]))
+
+				policies, pagination, err = dataStore.AllPaginated(store.Page{Limit: 3, From: pagination.Next})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies).To(Equal(allPolicies[3:]))
+				Expect(pagination).To(Equal(store.Pagination{Next: 0}))
+			})
+
+			Context("when empty args is provided", func() {
+				BeforeEach(func() {
+					dataStore = store.New(mockDb, group, destination, policy, 1)
+				})
+
+				It("returns an empty slice without querying", func() {
+					policies, pagination, err := dataStore.ByGuidsPaginated(nil, nil, false, store.Page{Limit: 2})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(policies).To(BeEmpty())
+					Expect(pagination).To(Equal(store.Pagination{}))
+					Expect(mockDb.QueryCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the db operation fails", func() {
+				BeforeEach(func() {
+					mockDb.QueryReturns(nil, errors.New("some query error"))
+				})
+
+				It("should return a sensible error", func() {
+					dataStore = store.New(mockDb, group, destination, policy, 2)
+
+					_, _, err = dataStore.AllPaginated(store.Page{Limit: 1})
+					Expect(err).To(MatchError("listing all: some query error"))
+				})
+			})
+		})
+
 		Context("when the db operation fails", func() {
 			BeforeEach(func() {
 				mockDb.QueryReturns(nil, errors.New("some query error"))
@@ -841,6 +1110,1197 @@ var _ = Describe("Store", func() {
 		})
 	})
 
+	Describe("ExpiredCount()", func() {
+		BeforeEach(func() {
+			migrateAndPopulateTags(realDb, 1)
+			dataStore = store.New(realDb, group, destination, policy, 1)
+
+			expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+			err := dataStore.Create([]store.Policy{
+				{
+					Source:      store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{ID: "some-other-app-guid", Protocol: "tcp", Port: 8080, Ports: store.Ports{Start: 8080, End: 8080}},
+					ExpiresAt:   &expiresAt,
+				},
+				{
+					Source:      store.Source{ID: "another-app-guid"},
+					Destination: store.Destination{ID: "some-other-app-guid", Protocol: "tcp", Port: 8080, Ports: store.Ports{Start: 8080, End: 8080}},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("counts the policies that have expired by the given time", func() {
+			count, err := dataStore.ExpiredCount(time.Date(2030, 1, 2, 3, 4, 4, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(0))
+
+			count, err = dataStore.ExpiredCount(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+		})
+	})
+
+	Describe("DeleteExpired()", func() {
+		var expiresAt time.Time
+
+		BeforeEach(func() {
+			migrateAndPopulateTags(realDb, 1)
+			dataStore = store.New(realDb, group, destination, policy, 1)
+
+			expiresAt = time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+			err := dataStore.Create([]store.Policy{
+				{
+					Source:      store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{ID: "some-other-app-guid", Protocol: "tcp", Port: 8080, Ports: store.Ports{Start: 8080, End: 8080}},
+					ExpiresAt:   &expiresAt,
+				},
+				{
+					Source:      store.Source{ID: "another-app-guid"},
+					Destination: store.Destination{ID: "some-other-app-guid", Protocol: "tcp", Port: 8080, Ports: store.Ports{Start: 8080, End: 8080}},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("deletes and returns the policies that have expired by the given time", func() {
+			deleted, err := dataStore.DeleteExpired(time.Date(2030, 1, 2, 3, 4, 4, 0, time.UTC), store.SystemActor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeEmpty())
+
+			deleted, err = dataStore.DeleteExpired(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC), store.SystemActor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(HaveLen(1))
+			Expect(deleted[0].Source.ID).To(Equal("some-app-guid"))
+
+			policies, err := dataStore.All()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policies).To(HaveLen(1))
+			Expect(policies[0].Source.ID).To(Equal("another-app-guid"))
+		})
+
+		It("records a delete event for the actor", func() {
+			_, err := dataStore.DeleteExpired(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC), store.SystemActor)
+			Expect(err).NotTo(HaveOccurred())
+
+			eventsStore := &store.EventsStore{Conn: realDb}
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+
+			Expect(events[1].Action).To(Equal(store.PolicyEventDelete))
+			Expect(events[1].Actor).To(Equal(store.SystemActor.Name))
+			Expect(events[1].Policies).To(HaveLen(1))
+			Expect(events[1].Policies[0].Source.ID).To(Equal("some-app-guid"))
+		})
+
+		Context("when the expiry was extended", func() {
+			It("keeps the policy", func() {
+				newExpiresAt := time.Date(2031, 1, 2, 3, 4, 5, 0, time.UTC)
+				err := dataStore.Create([]store.Policy{{
+					Source:      store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{ID: "some-other-app-guid", Protocol: "tcp", Port: 8080, Ports: store.Ports{Start: 8080, End: 8080}},
+					ExpiresAt:   &newExpiresAt,
+				}})
+				Expect(err).NotTo(HaveOccurred())
+
+				deleted, err := dataStore.DeleteExpired(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC), store.SystemActor)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deleted).To(BeEmpty())
+			})
+		})
+
+		Context("when a transaction begin fails", func() {
+			BeforeEach(func() {
+				mockDb.BeginxReturns(nil, errors.New("some-db-error"))
+				dataStore = store.New(mockDb, group, destination, policy, 1)
+			})
+
+			It("returns an error", func() {
+				_, err := dataStore.DeleteExpired(time.Now(), store.SystemActor)
+				Expect(err).To(MatchError("create transaction: some-db-error"))
+			})
+		})
+	})
+
+	Describe("ReplaceForSource", func() {
+		BeforeEach(func() {
+			tagLength = 1
+			migrateAndPopulateTags(realDb, tagLength)
+			dataStore = store.New(realDb, group, destination, policy, tagLength)
+			tagDataStore = store.NewTagStore(realDb, group, tagLength)
+
+			policies := []store.Policy{
+				{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "tcp",
+						Port:     8080,
+					},
+				},
+				{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "yet-another-app-guid",
+						Protocol: "udp",
+						Port:     5555,
+					},
+				},
+				{
+					Source: store.Source{ID: "another-app-guid"},
+					Destination: store.Destination{
+						ID:       "yet-another-app-guid",
+						Protocol: "tcp",
+						Port:     9999,
+					},
+				},
+			}
+
+			err := dataStore.Create(policies)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("replaces the policies of the source app and leaves other apps untouched", func() {
+			err := dataStore.ReplaceForSource("some-app-guid", []store.Policy{
+				{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "tcp",
+						Port:     8080,
+					},
+				},
+				{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "a-new-app-guid",
+						Protocol: "tcp",
+						Port:     7777,
+					},
+				},
+			}, nil, store.Actor{Name: "some-user"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			policies, err := dataStore.All()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policies).To(ConsistOf(
+				store.Policy{
+					Source: store.Source{ID: "some-app-guid", Tag: "01"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "tcp",
+						Port:     8080,
+						Tag:      "02",
+					},
+				},
+				store.Policy{
+					Source: store.Source{ID: "some-app-guid", Tag: "01"},
+					Destination: store.Destination{
+						ID:       "a-new-app-guid",
+						Protocol: "tcp",
+						Port:     7777,
+						Tag:      "05",
+					},
+				},
+				store.Policy{
+					Source: store.Source{ID: "another-app-guid", Tag: "04"},
+					Destination: store.Destination{
+						ID:       "yet-another-app-guid",
+						Protocol: "tcp",
+						Port:     9999,
+						Tag:      "03",
+					},
+				},
+			))
+		})
+
+		It("records the created and deleted policies as policy events", func() {
+			err := dataStore.ReplaceForSource("some-app-guid", []store.Policy{
+				{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "tcp",
+						Port:     8080,
+					},
+				},
+				{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "a-new-app-guid",
+						Protocol: "tcp",
+						Port:     7777,
+					},
+				},
+			}, nil, store.Actor{Name: "some-user", ClientID: "some-client"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			eventsStore := &store.EventsStore{Conn: realDb}
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+
+			Expect(events[0].Action).To(Equal(store.PolicyEventCreate))
+			Expect(events[0].Actor).To(Equal("some-user"))
+			Expect(events[0].ClientID).To(Equal("some-client"))
+			Expect(events[0].Policies).To(Equal([]store.Policy{{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "a-new-app-guid",
+					Protocol: "tcp",
+					Port:     7777,
+				},
+			}}))
+
+			Expect(events[1].Action).To(Equal(store.PolicyEventDelete))
+			Expect(events[1].Policies).To(Equal([]store.Policy{{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "yet-another-app-guid",
+					Protocol: "udp",
+					Port:     5555,
+				},
+			}}))
+		})
+
+		It("updates last updated field", func() {
+			lastUpdatedOriginal, err := dataStore.LastUpdated()
+			Expect(err).NotTo(HaveOccurred())
+			time.Sleep(1 * time.Second)
+
+			err = dataStore.ReplaceForSource("some-app-guid", []store.Policy{}, nil, store.Actor{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			lastUpdatedNew, err := dataStore.LastUpdated()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lastUpdatedNew).To(BeNumerically(">", lastUpdatedOriginal))
+		})
+
+		It("updates the expiry of the policies that are kept", func() {
+			expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+			kept := store.Policy{
+				Source: store.Source{ID: "another-app-guid"},
+				Destination: store.Destination{
+					ID:       "yet-another-app-guid",
+					Protocol: "tcp",
+					Port:     9999,
+				},
+				ExpiresAt: &expiresAt,
+			}
+
+			err := dataStore.ReplaceForSource("another-app-guid", []store.Policy{kept}, nil, store.Actor{Name: "some-user"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			p, err := dataStore.ByGuids([]string{"another-app-guid"}, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(HaveLen(1))
+			Expect(p[0].ExpiresAt).NotTo(BeNil())
+			Expect(p[0].ExpiresAt.Equal(expiresAt)).To(BeTrue())
+
+			kept.ExpiresAt = nil
+			err = dataStore.ReplaceForSource("another-app-guid", []store.Policy{kept}, nil, store.Actor{Name: "some-user"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			p, err = dataStore.ByGuids([]string{"another-app-guid"}, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(HaveLen(1))
+			Expect(p[0].ExpiresAt).To(BeNil())
+		})
+
+		It("passes the existing policies of the source app to the check", func() {
+			var checked []store.Policy
+			err := dataStore.ReplaceForSource("another-app-guid", []store.Policy{}, nil, store.Actor{}, func(existing []store.Policy) error {
+				checked = existing
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(checked).To(ConsistOf(store.Policy{
+				Source: store.Source{ID: "another-app-guid", Tag: "04"},
+				Destination: store.Destination{
+					ID:       "yet-another-app-guid",
+					Protocol: "tcp",
+					Port:     9999,
+					Tag:      "03",
+				},
+			}))
+		})
+
+		Context("when there are pending policies", func() {
+			var held, existing store.Policy
+
+			BeforeEach(func() {
+				held = store.Policy{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "another-space-app-guid",
+						Protocol: "tcp",
+						Port:     7777,
+					},
+				}
+				existing = store.Policy{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "yet-another-app-guid",
+						Protocol: "udp",
+						Port:     5555,
+					},
+				}
+			})
+
+			It("holds the new ones and keeps the existing ones", func() {
+				err := dataStore.ReplaceForSource("some-app-guid", []store.Policy{}, []store.PendingPolicy{
+					{Policy: held, DestinationSpaceGUID: "some-space-guid"},
+					{Policy: existing, DestinationSpaceGUID: "some-space-guid"},
+				}, store.Actor{Name: "some-user"}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				policies, err := dataStore.ByGuids([]string{"some-app-guid"}, []string{}, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies).To(HaveLen(1))
+				Expect(policies[0].Destination.ID).To(Equal("yet-another-app-guid"))
+
+				pendingStore := &store.PendingStore{Conn: realDb}
+				pending, err := pendingStore.PendingPolicies()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pending).To(HaveLen(1))
+				Expect(pending[0].Policy).To(Equal(held))
+				Expect(pending[0].RequestedBy).To(Equal("some-user"))
+			})
+		})
+
+		Context("when the check fails", func() {
+			It("returns the error and changes nothing", func() {
+				err := dataStore.ReplaceForSource("some-app-guid", []store.Policy{}, nil, store.Actor{}, func([]store.Policy) error {
+					return errors.New("banana")
+				})
+				Expect(err).To(MatchError("banana"))
+
+				policies, err := dataStore.ByGuids([]string{"some-app-guid"}, []string{}, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies).To(HaveLen(2))
+			})
+		})
+
+		Context("when the new policy list is empty", func() {
+			It("deletes every policy of the source app and frees unreferenced tags", func() {
+				err := dataStore.ReplaceForSource("some-app-guid", []store.Policy{}, nil, store.Actor{}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				policies, err := dataStore.ByGuids([]string{"some-app-guid"}, []string{}, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies).To(BeEmpty())
+
+				tags, err := tagDataStore.Tags()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tags).NotTo(ContainElement(HaveField("ID", "some-app-guid")))
+				Expect(tags).NotTo(ContainElement(HaveField("ID", "some-other-app-guid")))
+			})
+		})
+
+		Context("when an error occurs", func() {
+			Context("when a transaction begin fails", func() {
+				BeforeEach(func() {
+					mockDb.BeginxReturns(nil, errors.New("some-db-error"))
+					dataStore = store.New(mockDb, group, destination, policy, 1)
+				})
+
+				It("returns an error", func() {
+					err := dataStore.ReplaceForSource("some-app-guid", []store.Policy{}, nil, store.Actor{}, nil)
+					Expect(err).To(MatchError("create transaction: some-db-error"))
+				})
+			})
+
+			Context("when listing the existing policies fails", func() {
+				BeforeEach(func() {
+					tx.QueryxReturns(nil, errors.New("some-query-error"))
+					dataStore = store.New(mockDb, group, destination, policy, 1)
+				})
+
+				It("rolls back the transaction", func() {
+					err := dataStore.ReplaceForSource("some-app-guid", []store.Policy{}, nil, store.Actor{}, nil)
+					Expect(err).To(MatchError("listing source policies: some-query-error"))
+					Expect(tx.RollbackCallCount()).To(Equal(1))
+				})
+			})
+		})
+	})
+
+	Describe("MergeWithEvent", func() {
+		var overlapping []store.Policy
+
+		BeforeEach(func() {
+			tagLength = 1
+			migrateAndPopulateTags(realDb, tagLength)
+			dataStore = store.New(realDb, group, destination, policy, tagLength)
+
+			overlapping = []store.Policy{
+				{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "tcp",
+						Ports:    store.Ports{Start: 8080, End: 8090},
+					},
+				},
+				{
+					Source: store.Source{ID: "some-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-other-app-guid",
+						Protocol: "tcp",
+						Ports:    store.Ports{Start: 8085, End: 9000},
+					},
+				},
+			}
+
+			err := dataStore.Create(overlapping)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("creates the merged policies and deletes the ones they replace", func() {
+			overlaps := store.FindOverlaps(overlapping)
+			Expect(overlaps).To(HaveLen(1))
+
+			err := dataStore.MergeWithEvent([]store.Policy{overlaps[0].Merged}, overlaps[0].Replaced(),
+				store.Actor{Name: "some-user", ClientID: "some-client"})
+			Expect(err).NotTo(HaveOccurred())
+
+			policies, err := dataStore.All()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policies).To(ConsistOf(store.Policy{
+				Source: store.Source{ID: "some-app-guid", Tag: "01"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Tag:      "02",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: 8080, End: 9000},
+				},
+			}))
+
+			eventsStore := &store.EventsStore{Conn: realDb}
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+			Expect(events[0].Action).To(Equal(store.PolicyEventCreate))
+			Expect(events[0].Actor).To(Equal("some-user"))
+			Expect(events[1].Action).To(Equal(store.PolicyEventDelete))
+			Expect(events[1].Policies).To(HaveLen(2))
+		})
+
+		Context("when there is nothing to merge", func() {
+			It("does not update last updated", func() {
+				lastUpdatedOriginal, err := dataStore.LastUpdated()
+				Expect(err).NotTo(HaveOccurred())
+
+				err = dataStore.MergeWithEvent(nil, nil, store.Actor{})
+				Expect(err).NotTo(HaveOccurred())
+
+				lastUpdatedNew, err := dataStore.LastUpdated()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lastUpdatedNew).To(Equal(lastUpdatedOriginal))
+			})
+		})
+	})
+
+	Describe("CreateWithPending", func() {
+		var created, held store.Policy
+
+		BeforeEach(func() {
+			tagLength = 1
+			migrateAndPopulateTags(realDb, tagLength)
+			dataStore = store.New(realDb, group, destination, policy, tagLength)
+
+			created = store.Policy{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+			}
+			held = store.Policy{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "another-space-app-guid",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+			}
+		})
+
+		It("creates the policies and holds the pending policies", func() {
+			err := dataStore.CreateWithPending([]store.Policy{created}, nil,
+				[]store.PendingPolicy{{Policy: held, DestinationSpaceGUID: "some-space-guid"}},
+				store.Actor{Name: "some-user", ClientID: "some-client"})
+			Expect(err).NotTo(HaveOccurred())
+
+			policies, err := dataStore.All()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policies).To(HaveLen(1))
+			Expect(policies[0].Destination.ID).To(Equal("some-other-app-guid"))
+
+			pendingStore := &store.PendingStore{Conn: realDb}
+			pending, err := pendingStore.PendingPolicies()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pending).To(HaveLen(1))
+			Expect(pending[0].Policy).To(Equal(held))
+			Expect(pending[0].RequestedBy).To(Equal("some-user"))
+		})
+
+		Context("when holding the pending policies fails", func() {
+			BeforeEach(func() {
+				_, err := realDb.Exec("DROP TABLE pending_policies")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("does not create the policies either", func() {
+				err := dataStore.CreateWithPending([]store.Policy{created}, nil,
+					[]store.PendingPolicy{{Policy: held, DestinationSpaceGUID: "some-space-guid"}}, store.Actor{})
+				Expect(err).To(HaveOccurred())
+
+				policies, err := dataStore.All()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("ApprovePending", func() {
+		var (
+			pendingStore *store.PendingStore
+			pending      store.PendingPolicy
+		)
+
+		BeforeEach(func() {
+			tagLength = 1
+			migrateAndPopulateTags(realDb, tagLength)
+			dataStore = store.New(realDb, group, destination, policy, tagLength)
+			pendingStore = &store.PendingStore{Conn: realDb}
+
+			held := store.Policy{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "another-space-app-guid",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+			}
+			err := pendingStore.CreatePending([]store.PendingPolicy{{Policy: held, DestinationSpaceGUID: "some-space-guid"}},
+				store.Actor{Name: "some-user", ClientID: "some-client"})
+			Expect(err).NotTo(HaveOccurred())
+
+			allPending, err := pendingStore.PendingPolicies()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allPending).To(HaveLen(1))
+			pending = allPending[0]
+		})
+
+		It("creates the policy and deletes the pending policy", func() {
+			err := dataStore.ApprovePending(pending, store.Actor{Name: "some-approver"})
+			Expect(err).NotTo(HaveOccurred())
+
+			policies, err := dataStore.All()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policies).To(HaveLen(1))
+			Expect(policies[0].Destination.ID).To(Equal("another-space-app-guid"))
+
+			allPending, err := pendingStore.PendingPolicies()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allPending).To(BeEmpty())
+		})
+
+		It("records the requester as the actor and the approver of the policy event", func() {
+			err := dataStore.ApprovePending(pending, store.Actor{Name: "some-approver"})
+			Expect(err).NotTo(HaveOccurred())
+
+			eventsStore := &store.EventsStore{Conn: realDb}
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Action).To(Equal(store.PolicyEventCreate))
+			Expect(events[0].Actor).To(Equal("some-user"))
+			Expect(events[0].ClientID).To(Equal("some-client"))
+			Expect(events[0].ApprovedBy).To(Equal("some-approver"))
+		})
+
+		Context("when the pending policy no longer exists", func() {
+			BeforeEach(func() {
+				err := pendingStore.DeletePending(pending.ID)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns ErrPendingPolicyNotFound and creates nothing", func() {
+				err := dataStore.ApprovePending(pending, store.Actor{Name: "some-approver"})
+				Expect(err).To(Equal(store.ErrPendingPolicyNotFound))
+
+				policies, err := dataStore.All()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies).To(BeEmpty())
+			})
+		})
+
+		Context("when the policy already exists as a deny policy", func() {
+			BeforeEach(func() {
+				denied := pending.Policy
+				denied.Action = store.PolicyActionDeny
+				err := dataStore.Create([]store.Policy{denied})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns a DeniedPoliciesError and keeps the pending policy", func() {
+				err := dataStore.ApprovePending(pending, store.Actor{Name: "some-approver"})
+				var deniedErr *store.DeniedPoliciesError
+				Expect(errors.As(err, &deniedErr)).To(BeTrue())
+
+				allPending, err := pendingStore.PendingPolicies()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(allPending).To(HaveLen(1))
+			})
+		})
+	})
+
+	Describe("ImportWithEvent", func() {
+		var existing store.Policy
+
+		BeforeEach(func() {
+			tagLength = 1
+			migrateAndPopulateTags(realDb, tagLength)
+			dataStore = store.New(realDb, group, destination, policy, tagLength)
+
+			existing = store.Policy{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Protocol: "tcp",
+					Port:     8080,
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+				Metadata: store.Metadata{Description: "old"},
+			}
+			err := dataStore.Create([]store.Policy{existing})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("creates the new policies and updates the attributes of the existing ones", func() {
+			created := store.Policy{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "yet-another-app-guid",
+					Protocol: "udp",
+					Port:     53,
+					Ports:    store.Ports{Start: 53, End: 53},
+				},
+			}
+			updated := existing
+			updated.Metadata = store.Metadata{}
+			updated.Action = store.PolicyActionDeny
+
+			err := dataStore.ImportWithEvent([]store.Policy{created}, []store.Policy{updated},
+				store.Actor{Name: "some-user"})
+			Expect(err).NotTo(HaveOccurred())
+
+			policies, err := dataStore.All()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policies).To(HaveLen(2))
+			for _, p := range policies {
+				if p.Destination.ID == "some-other-app-guid" {
+					Expect(p.Metadata.IsEmpty()).To(BeTrue())
+					Expect(p.IsDeny()).To(BeTrue())
+				}
+			}
+
+			eventsStore := &store.EventsStore{Conn: realDb}
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+			Expect(events[0].Action).To(Equal(store.PolicyEventCreate))
+			Expect(events[1].Action).To(Equal(store.PolicyEventUpdate))
+			Expect(events[1].Actor).To(Equal("some-user"))
+		})
+
+		Context("when there is nothing to import", func() {
+			It("does not update last updated", func() {
+				lastUpdatedOriginal, err := dataStore.LastUpdated()
+				Expect(err).NotTo(HaveOccurred())
+
+				err = dataStore.ImportWithEvent(nil, nil, store.Actor{})
+				Expect(err).NotTo(HaveOccurred())
+
+				lastUpdatedNew, err := dataStore.LastUpdated()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lastUpdatedNew).To(Equal(lastUpdatedOriginal))
+			})
+		})
+	})
+
+	Describe("ReconcileSources", func() {
+		var (
+			appPolicy   store.Policy
+			spacePolicy store.Policy
+			otherPolicy store.Policy
+			lastUpdated int
+		)
+
+		BeforeEach(func() {
+			tagLength = 1
+			migrateAndPopulateTags(realDb, tagLength)
+			dataStore = store.New(realDb, group, destination, policy, tagLength)
+
+			appPolicy = store.Policy{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Protocol: "tcp",
+					Port:     8080,
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+			}
+			spacePolicy = store.Policy{
+				Source: store.Source{ID: "some-space-guid", Type: "space"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Protocol: "tcp",
+					Port:     9090,
+					Ports:    store.Ports{Start: 9090, End: 9090},
+				},
+			}
+			otherPolicy = store.Policy{
+				Source: store.Source{ID: "unrelated-app-guid"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Protocol: "udp",
+					Port:     53,
+					Ports:    store.Ports{Start: 53, End: 53},
+				},
+			}
+			err := dataStore.Create([]store.Policy{appPolicy, spacePolicy, otherPolicy})
+			Expect(err).NotTo(HaveOccurred())
+
+			lastUpdated, err = dataStore.LastUpdated()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("replaces the policies of the sources and records events", func() {
+			created := appPolicy
+			created.Destination.Port = 8081
+			created.Destination.Ports = store.Ports{Start: 8081, End: 8081}
+			updated := spacePolicy
+			updated.Metadata = store.Metadata{Description: "monitoring"}
+
+			err := dataStore.ReconcileSources([]string{"some-space-guid", "some-app-guid"},
+				[]store.Policy{created, updated}, lastUpdated, store.Actor{Name: "some-user"})
+			Expect(err).NotTo(HaveOccurred())
+
+			policies, err := dataStore.All()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policies).To(HaveLen(3))
+			Expect(policies).To(ContainElement(WithTransform(func(p store.Policy) int { return p.Destination.Ports.Start }, Equal(8081))))
+			Expect(policies).To(ContainElement(WithTransform(func(p store.Policy) string { return p.Metadata.Description }, Equal("monitoring"))))
+			Expect(policies).To(ContainElement(WithTransform(func(p store.Policy) string { return p.Source.ID }, Equal("unrelated-app-guid"))))
+
+			eventsStore := &store.EventsStore{Conn: realDb}
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(3))
+			Expect(events[0].Action).To(Equal(store.PolicyEventCreate))
+			Expect(events[1].Action).To(Equal(store.PolicyEventDelete))
+			Expect(events[2].Action).To(Equal(store.PolicyEventUpdate))
+
+			lastUpdatedNew, err := dataStore.LastUpdated()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lastUpdatedNew).To(BeNumerically(">", lastUpdated))
+		})
+
+		Context("when the policies have changed since last updated", func() {
+			It("returns ErrPoliciesChanged and changes nothing", func() {
+				err := dataStore.ReconcileSources([]string{"some-app-guid"}, []store.Policy{}, lastUpdated-1, store.Actor{})
+				Expect(err).To(Equal(store.ErrPoliciesChanged))
+
+				policies, err := dataStore.All()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policies).To(HaveLen(3))
+			})
+		})
+
+		Context("when the policies are already in the desired state", func() {
+			It("does not update last updated", func() {
+				err := dataStore.ReconcileSources([]string{"some-space-guid", "some-app-guid"},
+					[]store.Policy{appPolicy, spacePolicy}, lastUpdated, store.Actor{})
+				Expect(err).NotTo(HaveOccurred())
+
+				lastUpdatedNew, err := dataStore.LastUpdated()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lastUpdatedNew).To(Equal(lastUpdated))
+			})
+		})
+	})
+
+	Describe("Quarantine", func() {
+		var appPolicy store.Policy
+
+		BeforeEach(func() {
+			tagLength = 1
+			migrateAndPopulateTags(realDb, tagLength)
+			dataStore = store.New(realDb, group, destination, policy, tagLength)
+
+			appPolicy = store.Policy{
+				Source: store.Source{ID: "some-app-guid"},
+				Destination: store.Destination{
+					ID:       "some-other-app-guid",
+					Protocol: "tcp",
+					Port:     8080,
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+			}
+			err := dataStore.Create([]store.Policy{appPolicy})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("flags the app and records who quarantined it", func() {
+			err := dataStore.Quarantine("some-other-app-guid", store.Actor{Name: "some-user", ClientID: "some-client"})
+			Expect(err).NotTo(HaveOccurred())
+
+			guids, err := dataStore.Quarantined()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(guids).To(Equal([]string{"some-other-app-guid"}))
+
+			By("keeping the policies of the app")
+			policies, err := dataStore.All()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policies).To(HaveLen(1))
+
+			eventsStore := &store.EventsStore{Conn: realDb}
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{AppGuids: []string{"some-other-app-guid"}}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Action).To(Equal(store.PolicyEventQuarantine))
+			Expect(events[0].Actor).To(Equal("some-user"))
+			Expect(events[0].ClientID).To(Equal("some-client"))
+			Expect(events[0].Policies).To(HaveLen(1))
+			Expect(events[0].Policies[0].Source.ID).To(Equal("some-app-guid"))
+		})
+
+		It("updates last updated", func() {
+			lastUpdatedOriginal, err := dataStore.LastUpdated()
+			Expect(err).NotTo(HaveOccurred())
+
+			time.Sleep(10 * time.Millisecond)
+			err = dataStore.Quarantine("some-app-guid", store.Actor{})
+			Expect(err).NotTo(HaveOccurred())
+
+			lastUpdatedNew, err := dataStore.LastUpdated()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lastUpdatedNew).To(BeNumerically(">", lastUpdatedOriginal))
+		})
+
+		Context("when the app has no policies", func() {
+			It("keeps the quarantine when policies are created and deleted", func() {
+				err := dataStore.Quarantine("lonely-app-guid", store.Actor{})
+				Expect(err).NotTo(HaveOccurred())
+
+				lonelyPolicy := store.Policy{
+					Source: store.Source{ID: "lonely-app-guid"},
+					Destination: store.Destination{
+						ID:       "some-app-guid",
+						Protocol: "tcp",
+						Port:     8080,
+						Ports:    store.Ports{Start: 8080, End: 8080},
+					},
+				}
+				err = dataStore.Create([]store.Policy{lonelyPolicy})
+				Expect(err).NotTo(HaveOccurred())
+				err = dataStore.Delete([]store.Policy{lonelyPolicy})
+				Expect(err).NotTo(HaveOccurred())
+
+				guids, err := dataStore.Quarantined()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(guids).To(Equal([]string{"lonely-app-guid"}))
+			})
+		})
+
+		Context("when the app is already quarantined", func() {
+			It("does not record another event", func() {
+				err := dataStore.Quarantine("some-app-guid", store.Actor{})
+				Expect(err).NotTo(HaveOccurred())
+				err = dataStore.Quarantine("some-app-guid", store.Actor{})
+				Expect(err).NotTo(HaveOccurred())
+
+				eventsStore := &store.EventsStore{Conn: realDb}
+				events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(events).To(HaveLen(1))
+			})
+		})
+
+		Context("when a transaction begin fails", func() {
+			It("returns an error", func() {
+				mockDb.BeginxReturns(nil, errors.New("some-db-error"))
+				dataStore = store.New(mockDb, group, destination, policy, 2)
+
+				err := dataStore.Quarantine("some-app-guid", store.Actor{})
+				Expect(err).To(MatchError("create transaction: some-db-error"))
+			})
+		})
+
+		Context("when setting the flag fails", func() {
+			It("rolls back and returns an error", func() {
+				fakeGroup := &fakes.GroupRepo{}
+				fakeGroup.SetQuarantinedReturns(false, errors.New("some-update-error"))
+				dataStore = store.New(realDb, fakeGroup, destination, policy, 2)
+
+				err := dataStore.Quarantine("some-app-guid", store.Actor{})
+				Expect(err).To(MatchError("quarantining group: some-update-error"))
+
+				guids, err := dataStore.Quarantined()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(guids).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("Release", func() {
+		BeforeEach(func() {
+			tagLength = 1
+			migrateAndPopulateTags(realDb, tagLength)
+			dataStore = store.New(realDb, group, destination, policy, tagLength)
+
+			err := dataStore.Quarantine("some-app-guid", store.Actor{})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("lifts the quarantine and records who released the app", func() {
+			err := dataStore.Release("some-app-guid", store.Actor{Name: "some-user"})
+			Expect(err).NotTo(HaveOccurred())
+
+			guids, err := dataStore.Quarantined()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(guids).To(BeEmpty())
+
+			eventsStore := &store.EventsStore{Conn: realDb}
+			events, _, err := eventsStore.Events(store.PolicyEventsFilter{AppGuids: []string{"some-app-guid"}}, store.Page{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+			Expect(events[1].Action).To(Equal(store.PolicyEventRelease))
+			Expect(events[1].Actor).To(Equal("some-user"))
+			Expect(events[1].Policies).To(BeEmpty())
+		})
+
+		It("frees the group of an app without policies", func() {
+			err := dataStore.Release("some-app-guid", store.Actor{})
+			Expect(err).NotTo(HaveOccurred())
+
+			var count int
+			err = realDb.QueryRow(`SELECT COUNT(*) FROM "groups" WHERE guid = 'some-app-guid'`).Scan(&count)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(0))
+		})
+
+		Context("when the app is not quarantined", func() {
+			It("does nothing", func() {
+				err := dataStore.Release("some-other-app-guid", store.Actor{})
+				Expect(err).NotTo(HaveOccurred())
+
+				eventsStore := &store.EventsStore{Conn: realDb}
+				events, _, err := eventsStore.Events(store.PolicyEventsFilter{}, store.Page{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(events).To(HaveLen(1))
+			})
+		})
+	})
+
+	Describe("source scopes", func() {
+		BeforeEach(func() {
+			tagLength = 1
+			migrateAndPopulateTags(realDb, tagLength)
+			dataStore = store.New(realDb, group, destination, policy, tagLength)
+
+			policyFrom := func(source store.Source, port int) store.Policy {
+				return store.Policy{
+					Source: source,
+					Destination: store.Destination{
+						ID:       "some-app-guid",
+						Protocol: "tcp",
+						Ports:    store.Ports{Start: port, End: port},
+					},
+				}
+			}
+			err := dataStore.Create([]store.Policy{
+				policyFrom(store.Source{ID: "app-1"}, 8080),
+				policyFrom(store.Source{ID: "app-1"}, 8081),
+				policyFrom(store.Source{ID: "app-2"}, 8080),
+				policyFrom(store.Source{ID: "space-2", Type: store.GroupTypeSpace}, 8080),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("counts the policies of each space and org once the scopes of their sources are stored", func() {
+			sources, err := dataStore.UnscopedSources()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sources).To(Equal([]store.Source{
+				{ID: "app-1"},
+				{ID: "app-2"},
+				{ID: "space-2", Type: store.GroupTypeSpace},
+			}))
+
+			err = dataStore.SetSourceScopes([]store.SourceScope{
+				{GUID: "app-1", SpaceGUID: "space-1", OrgGUID: "org-1"},
+				{GUID: "app-2", SpaceGUID: "space-2", OrgGUID: "org-1"},
+				{GUID: "space-2", SpaceGUID: "space-2", OrgGUID: "org-1"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			sources, err = dataStore.UnscopedSources()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sources).To(BeEmpty())
+
+			counts, err := dataStore.PolicyCountsByScope(store.GroupTypeSpace, []string{"space-1", "space-2", "space-3"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(Equal(map[string]int{"space-1": 2, "space-2": 2}))
+
+			counts, err = dataStore.PolicyCountsByScope(store.GroupTypeOrg, []string{"org-1"}, []string{"app-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(Equal(map[string]int{"org-1": 2}))
+		})
+
+		Context("when the row of a source is freed", func() {
+			It("forgets the scope of the source", func() {
+				err := dataStore.SetSourceScopes([]store.SourceScope{{GUID: "app-2", SpaceGUID: "space-2", OrgGUID: "org-1"}})
+				Expect(err).NotTo(HaveOccurred())
+
+				policies, err := dataStore.ByGuids([]string{"app-2"}, nil, false)
+				Expect(err).NotTo(HaveOccurred())
+				err = dataStore.Delete(policies)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = dataStore.Create([]store.Policy{{
+					Source:      store.Source{ID: "app-3"},
+					Destination: store.Destination{ID: "some-app-guid", Protocol: "tcp", Ports: store.Ports{Start: 8080, End: 8080}},
+				}})
+				Expect(err).NotTo(HaveOccurred())
+
+				sources, err := dataStore.UnscopedSources()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sources).To(ContainElement(store.Source{ID: "app-3"}))
+			})
+		})
+
+		Context("when the scope type is invalid", func() {
+			It("returns an error", func() {
+				_, err := dataStore.PolicyCountsByScope("app", []string{"some-guid"}, nil)
+				Expect(err).To(MatchError("invalid scope type: app"))
+			})
+		})
+	})
+
+	Describe("SetGroupMembers", func() {
+		var spacePolicy store.Policy
+
+		BeforeEach(func() {
+			tagLength = 1
+			migrateAndPopulateTags(realDb, tagLength)
+			dataStore = store.New(realDb, group, destination, policy, tagLength)
+
+			spacePolicy = store.Policy{
+				Source: store.Source{ID: "some-space-guid", Type: store.GroupTypeSpace},
+				Destination: store.Destination{
+					ID:       "some-app-guid",
+					Protocol: "tcp",
+					Ports:    store.Ports{Start: 8080, End: 8080},
+				},
+			}
+			err := dataStore.Create([]store.Policy{spacePolicy})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("stores the apps of the space with a tag for each", func() {
+			err := dataStore.SetGroupMembers(map[string][]string{
+				"some-space-guid": {"member-app-guid", "some-app-guid"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			members, err := dataStore.GroupMembers([]string{"some-space-guid"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(2))
+			Expect(members[0].GroupGUID).To(Equal("some-space-guid"))
+			Expect(members[0].AppGUID).To(Equal("member-app-guid"))
+			Expect(members[1].AppGUID).To(Equal("some-app-guid"))
+
+			policies, err := dataStore.All()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members[1].AppTag).To(Equal(policies[0].Destination.Tag))
+			Expect(members[0].AppTag).NotTo(Equal(members[1].AppTag))
+
+			groups, err := dataStore.MemberGroups([]string{"member-app-guid", "another-app-guid"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(Equal([]string{"some-space-guid"}))
+		})
+
+		It("only updates last updated when the members change", func() {
+			err := dataStore.SetGroupMembers(map[string][]string{"some-space-guid": {"member-app-guid"}})
+			Expect(err).NotTo(HaveOccurred())
+			lastUpdated, err := dataStore.LastUpdated()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = dataStore.SetGroupMembers(map[string][]string{"some-space-guid": {"member-app-guid"}})
+			Expect(err).NotTo(HaveOccurred())
+			unchanged, err := dataStore.LastUpdated()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unchanged).To(Equal(lastUpdated))
+
+			err = dataStore.SetGroupMembers(map[string][]string{})
+			Expect(err).NotTo(HaveOccurred())
+			changed, err := dataStore.LastUpdated()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeNumerically(">", lastUpdated))
+		})
+
+		It("frees the groups of apps that are no longer members", func() {
+			err := dataStore.SetGroupMembers(map[string][]string{"some-space-guid": {"member-app-guid"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = dataStore.SetGroupMembers(map[string][]string{"some-space-guid": {}})
+			Expect(err).NotTo(HaveOccurred())
+
+			members, err := dataStore.GroupMembers([]string{"some-space-guid"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(BeEmpty())
+
+			var count int
+			err = realDb.QueryRow(`SELECT COUNT(*) FROM "groups" WHERE guid = 'member-app-guid'`).Scan(&count)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(0))
+		})
+
+		It("skips spaces that no longer have policies", func() {
+			err := dataStore.SetGroupMembers(map[string][]string{"another-space-guid": {"member-app-guid"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			members, err := dataStore.GroupMembers([]string{"another-space-guid"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(BeEmpty())
+		})
+
+		Context("when the last policy of the space is deleted", func() {
+			It("frees the space and the groups of its apps", func() {
+				err := dataStore.SetGroupMembers(map[string][]string{"some-space-guid": {"member-app-guid"}})
+				Expect(err).NotTo(HaveOccurred())
+
+				err = dataStore.Delete([]store.Policy{spacePolicy})
+				Expect(err).NotTo(HaveOccurred())
+
+				var count int
+				err = realDb.QueryRow(`SELECT COUNT(*) FROM group_members`).Scan(&count)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(Equal(0))
+
+				err = realDb.QueryRow(`SELECT COUNT(*) FROM "groups" WHERE guid IN ('some-space-guid', 'member-app-guid')`).Scan(&count)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(Equal(0))
+			})
+		})
+	})
+
 	Describe("Delete", func() {
 		BeforeEach(func() {
 			tagLength = 1
@@ -1111,7 +2571,7 @@ var _ = Describe("Store", func() {
 			Context("when getting the destination id fails", func() {
 				Context("when the error is because the destination does not exist", func() {
 					BeforeEach(func() {
-						fakeDestination.GetIDStub = func(db.Transaction, int, int, int, int, string) (int, error) {
+						fakeDestination.GetIDStub = func(db.Transaction, int, int, int, int, string, int, int) (int, error) {
 							if fakeDestination.GetIDCallCount() == 1 {
 								return -1, sql.ErrNoRows
 							}
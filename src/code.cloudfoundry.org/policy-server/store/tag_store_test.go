@@ -101,6 +101,22 @@ var _ = Describe("TagStore", func() {
 			})
 		})
 
+		Context("when a group with the same guid and another type exists", func() {
+			BeforeEach(func() {
+				_, err := tagStore.CreateTag(groupGuid, groupType)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error instead of violating the unique guid", func() {
+				_, err := tagStore.CreateTag(groupGuid, "other-type")
+				Expect(err).To(MatchError(ContainSubstring("group meow-guid already exists with type meow-type")))
+
+				tags, err := tagStore.Tags()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tags).To(HaveLen(1))
+			})
+		})
+
 		Context("when there are no tags left to allocate", func() {
 			var (
 				mockTx    *dbfakes.Transaction
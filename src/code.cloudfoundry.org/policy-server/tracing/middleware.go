@@ -0,0 +1,145 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"code.cloudfoundry.org/cf-networking-helpers/json_client"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// propagator reads and writes the W3C traceparent header
+var propagator = propagation.TraceContext{}
+
+// Middleware records a server span for every request, and serves it with
+// dependencies that record their calls as children of that span. A middleware
+// without a tracer returns the handlers themselves.
+type Middleware struct {
+	Tracer trace.Tracer
+}
+
+func NewMiddleware(provider trace.TracerProvider) *Middleware {
+	return &Middleware{Tracer: provider.Tracer(InstrumentationScope)}
+}
+
+// Wrap records a server span for every request, named after the route of the
+// request. The span continues the trace of the traceparent header of the
+// request when it has one, and is added to the request context for the spans
+// of the handler.
+func (m *Middleware) Wrap(name string, handler http.Handler) http.Handler {
+	if m.Tracer == nil {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+		ctx, span := m.Tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPRequestMethodKey.String(req.Method), semconv.HTTPRoute(name)),
+		)
+		defer span.End()
+
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		handler.ServeHTTP(recorder, req.WithContext(ctx))
+
+		span.SetAttributes(semconv.HTTPResponseStatusCode(recorder.statusCode))
+		if recorder.statusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("http status %d", recorder.statusCode))
+		}
+	})
+}
+
+// Bind serves every request with a copy of the handler whose exported fields
+// that are Traceable are bound to the span of the request, as with
+// BindFields. The handler must be a pointer to a struct.
+func (m *Middleware) Bind(handler http.Handler) http.Handler {
+	if m.Tracer == nil {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if trace.SpanFromContext(req.Context()).IsRecording() {
+			if bound, ok := bindFields(req.Context(), reflect.ValueOf(handler)); ok {
+				bound.Interface().(http.Handler).ServeHTTP(w, req)
+				return
+			}
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (r *statusRecorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Flush lets streaming handlers, like the policies watch, flush through the
+// recorder
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+//counterfeiter:generate -o fakes/http_client.go --fake-name HTTPClient . httpClient
+type httpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// HTTPClient records a client span for every request and adds the
+// traceparent header of the span to it
+type HTTPClient struct {
+	Client  httpClient
+	Context context.Context
+	Service string
+}
+
+func (c *HTTPClient) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := StartChild(c.Context, fmt.Sprintf("%s %s %s", c.Service, req.Method, req.URL.Path), trace.SpanKindClient)
+	span.SetAttributes(semconv.HTTPRequestMethodKey.String(req.Method), semconv.URLPath(req.URL.Path))
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		End(span, err)
+		return resp, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, fmt.Sprintf("http status %d", resp.StatusCode))
+	}
+	span.End()
+	return resp, nil
+}
+
+// JsonClient records a client span for every request
+type JsonClient struct {
+	Client  json_client.JsonClient
+	Context context.Context
+	Service string
+}
+
+func (c *JsonClient) Do(method, route string, reqData, respData interface{}, token string) error {
+	path := strings.SplitN(route, "?", 2)[0]
+	_, span := StartChild(c.Context, fmt.Sprintf("%s %s %s", c.Service, method, path), trace.SpanKindClient)
+	span.SetAttributes(semconv.HTTPRequestMethodKey.String(method), semconv.URLPath(path))
+
+	err := c.Client.Do(method, route, reqData, respData, token)
+	End(span, err)
+	return err
+}
+
+func (c *JsonClient) CloseIdleConnections() {
+	c.Client.CloseIdleConnections()
+}
@@ -0,0 +1,227 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	helpersfakes "code.cloudfoundry.org/cf-networking-helpers/fakes"
+	"code.cloudfoundry.org/policy-server/tracing"
+	"code.cloudfoundry.org/policy-server/tracing/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedHandler struct {
+	Client client
+	served *tracedHandler
+}
+
+func (h *tracedHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h.served.Client = h.Client
+}
+
+var _ = Describe("Middleware", func() {
+	var (
+		recorder   *tracetest.SpanRecorder
+		middleware *tracing.Middleware
+		innerSpan  trace.Span
+		statusCode int
+		handler    http.Handler
+		request    *http.Request
+	)
+
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		middleware = tracing.NewMiddleware(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		statusCode = http.StatusOK
+		handler = middleware.Wrap("create_policies", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			innerSpan = trace.SpanFromContext(req.Context())
+			w.WriteHeader(statusCode)
+		}))
+		request = httptest.NewRequest("POST", "/networking/v1/external/policies", nil)
+	})
+
+	Describe("Wrap", func() {
+		It("records a server span for the request", func() {
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			Expect(recorder.Ended()).To(HaveLen(1))
+			span := recorder.Ended()[0]
+			Expect(span.SpanContext()).To(Equal(innerSpan.SpanContext()))
+			Expect(span.Name()).To(Equal("create_policies"))
+			Expect(span.SpanKind()).To(Equal(trace.SpanKindServer))
+			Expect(span.Parent().IsValid()).To(BeFalse())
+			Expect(span.Attributes()).To(ConsistOf(
+				attribute.String("http.request.method", "POST"),
+				attribute.String("http.route", "create_policies"),
+				attribute.Int("http.response.status_code", 200),
+			))
+			Expect(span.Status().Code).To(Equal(codes.Unset))
+		})
+
+		It("continues the trace of the traceparent header", func() {
+			request.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			span := recorder.Ended()[0]
+			Expect(span.SpanContext().TraceID().String()).To(Equal("0af7651916cd43dd8448eb211c80319c"))
+			Expect(span.Parent().SpanID().String()).To(Equal("b7ad6b7169203331"))
+			Expect(span.Parent().IsRemote()).To(BeTrue())
+		})
+
+		Context("when the handler fails", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("marks the span as failed", func() {
+				handler.ServeHTTP(httptest.NewRecorder(), request)
+
+				span := recorder.Ended()[0]
+				Expect(span.Attributes()).To(ContainElement(attribute.Int("http.response.status_code", 500)))
+				Expect(span.Status().Code).To(Equal(codes.Error))
+				Expect(span.Status().Description).To(Equal("http status 500"))
+			})
+		})
+
+		Context("when there is no tracer", func() {
+			It("returns the handler itself", func() {
+				inner := http.NewServeMux()
+				Expect((&tracing.Middleware{}).Wrap("name", inner)).To(BeIdenticalTo(inner))
+			})
+		})
+	})
+
+	Describe("Bind", func() {
+		var (
+			inner  *tracedHandler
+			served *tracedHandler
+		)
+
+		BeforeEach(func() {
+			served = &tracedHandler{}
+			inner = &tracedHandler{Client: &tracedClient{}, served: served}
+			handler = middleware.Wrap("create_policies", middleware.Bind(inner))
+		})
+
+		It("serves the request with the dependencies bound to the span of the request", func() {
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			Expect(served.Client).NotTo(BeIdenticalTo(inner.Client))
+			Expect(served.Client.(*tracedClient).ctx).NotTo(BeNil())
+			Expect(inner.Client.(*tracedClient).ctx).To(BeNil())
+		})
+
+		It("serves the request with the handler itself when the request has no span", func() {
+			middleware.Bind(inner).ServeHTTP(httptest.NewRecorder(), request)
+
+			Expect(served.Client).To(BeIdenticalTo(inner.Client))
+		})
+
+		Context("when there is no tracer", func() {
+			It("returns the handler itself", func() {
+				Expect((&tracing.Middleware{}).Bind(inner)).To(BeIdenticalTo(inner))
+			})
+		})
+	})
+})
+
+var _ = Describe("Clients", func() {
+	var (
+		recorder *tracetest.SpanRecorder
+		ctx      context.Context
+		parent   trace.Span
+	)
+
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		ctx, parent = provider.Tracer("test").Start(context.Background(), "parent")
+	})
+
+	Describe("HTTPClient", func() {
+		var (
+			httpClient *fakes.HTTPClient
+			client     *tracing.HTTPClient
+		)
+
+		BeforeEach(func() {
+			httpClient = &fakes.HTTPClient{}
+			httpClient.DoReturns(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil)
+			client = &tracing.HTTPClient{Client: httpClient, Context: ctx, Service: "uaa"}
+		})
+
+		It("records a client span and propagates it in the traceparent header", func() {
+			request, err := http.NewRequest("POST", "https://uaa.example.com/check_token?foo=bar", nil)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(recorder.Ended()).To(HaveLen(1))
+			span := recorder.Ended()[0]
+			Expect(span.Name()).To(Equal("uaa POST /check_token"))
+			Expect(span.SpanKind()).To(Equal(trace.SpanKindClient))
+			Expect(span.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+			Expect(span.Attributes()).To(ContainElement(attribute.Int("http.response.status_code", 200)))
+
+			sent := httpClient.DoArgsForCall(0)
+			Expect(sent.Header.Get("traceparent")).To(Equal(
+				"00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String() + "-01",
+			))
+		})
+
+		It("marks the span as failed when the request fails", func() {
+			httpClient.DoReturns(nil, errors.New("banana"))
+			request, _ := http.NewRequest("POST", "https://uaa.example.com/check_token", nil)
+			_, err := client.Do(request)
+			Expect(err).To(MatchError("banana"))
+
+			Expect(recorder.Ended()[0].Status().Code).To(Equal(codes.Error))
+			Expect(recorder.Ended()[0].Status().Description).To(Equal("banana"))
+		})
+
+		It("only passes the request on when the context has no span", func() {
+			client.Context = context.Background()
+			request, _ := http.NewRequest("GET", "https://uaa.example.com/token_keys", nil)
+			_, err := client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(recorder.Ended()).To(BeEmpty())
+			Expect(httpClient.DoArgsForCall(0).Header.Get("traceparent")).To(BeEmpty())
+		})
+	})
+
+	Describe("JsonClient", func() {
+		var jsonClient *helpersfakes.JSONClient
+
+		BeforeEach(func() {
+			jsonClient = &helpersfakes.JSONClient{}
+			jsonClient.DoReturns(errors.New("banana"))
+		})
+
+		It("records a client span for the request", func() {
+			client := &tracing.JsonClient{Client: jsonClient, Context: ctx, Service: "cc"}
+			err := client.Do("GET", "/v3/apps?guids=some-app-guid", nil, nil, "some-token")
+			Expect(err).To(MatchError("banana"))
+
+			method, route, _, _, token := jsonClient.DoArgsForCall(0)
+			Expect(method).To(Equal("GET"))
+			Expect(route).To(Equal("/v3/apps?guids=some-app-guid"))
+			Expect(token).To(Equal("some-token"))
+
+			span := recorder.Ended()[0]
+			Expect(span.Name()).To(Equal("cc GET /v3/apps"))
+			Expect(span.Attributes()).To(ContainElement(attribute.String("url.path", "/v3/apps")))
+			Expect(span.Status().Code).To(Equal(codes.Error))
+		})
+	})
+})
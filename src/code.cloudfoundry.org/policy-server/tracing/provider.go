@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager/v3"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	DefaultTimeout       = 10 * time.Second
+	InstrumentationScope = "code.cloudfoundry.org/policy-server"
+)
+
+// NewProvider returns a tracer provider that sends the sampled spans in
+// batches to `/v1/traces` of the OpenTelemetry collector at the endpoint, with
+// OTLP over HTTP. New traces are sampled by the sample ratio, and spans with a
+// parent when their parent is, so that traces are exported whole. Spans are
+// dropped when the queue is full, so that a slow collector does not slow down
+// requests.
+func NewProvider(endpoint, serviceName string, sampleRatio float64) (*sdktrace.TracerProvider, error) {
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(strings.TrimSuffix(endpoint, "/")+"/v1/traces"),
+		otlptracehttp.WithTimeout(DefaultTimeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating exporter: %s", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	), nil
+}
+
+// Runner shuts the tracer provider down when it is signalled, which sends the
+// spans that are left
+type Runner struct {
+	Logger   lager.Logger
+	Provider *sdktrace.TracerProvider
+}
+
+func (r *Runner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+	<-signals
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	if err := r.Provider.Shutdown(ctx); err != nil {
+		r.Logger.Error("shutdown", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"code.cloudfoundry.org/lager/v3/lagertest"
+	"code.cloudfoundry.org/policy-server/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/tedsuo/ifrit"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+var _ = Describe("Provider", func() {
+	var (
+		collector *ghttp.Server
+		provider  *sdktrace.TracerProvider
+	)
+
+	BeforeEach(func() {
+		collector = ghttp.NewServer()
+		collector.RouteToHandler("POST", "/v1/traces", ghttp.CombineHandlers(
+			ghttp.VerifyContentType("application/x-protobuf"),
+			ghttp.RespondWith(http.StatusOK, nil),
+		))
+
+		var err error
+		provider, err = tracing.NewProvider(collector.URL()+"/", "policy-server", 1)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		collector.Close()
+	})
+
+	It("sends the spans to the collector", func() {
+		_, span := provider.Tracer("test").Start(context.Background(), "name")
+		span.End()
+		Expect(provider.ForceFlush(context.Background())).To(Succeed())
+
+		Expect(collector.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("samples new traces by the sample ratio", func() {
+		unsampled, err := tracing.NewProvider(collector.URL(), "policy-server", 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, span := unsampled.Tracer("test").Start(context.Background(), "name")
+		Expect(span.SpanContext().IsSampled()).To(BeFalse())
+		span.End()
+		Expect(unsampled.ForceFlush(context.Background())).To(Succeed())
+
+		Expect(collector.ReceivedRequests()).To(BeEmpty())
+	})
+
+	Describe("Runner", func() {
+		It("sends the spans that are left when it is signalled", func() {
+			runner := &tracing.Runner{Logger: lagertest.NewTestLogger("test"), Provider: provider}
+			process := ifrit.Invoke(runner)
+
+			_, span := provider.Tracer("test").Start(context.Background(), "name")
+			span.End()
+			Expect(collector.ReceivedRequests()).To(BeEmpty())
+
+			process.Signal(os.Interrupt)
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+			Expect(collector.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+})
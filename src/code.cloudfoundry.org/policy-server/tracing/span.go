@@ -0,0 +1,101 @@
+package tracing
+
+//go:generate counterfeiter -generate
+
+import (
+	"context"
+	"reflect"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartChild starts a span as child of the span in the context, with the
+// tracer provider of that span. The span records nothing when the context has
+// no span, so that callers do not need to check whether tracing is enabled.
+func StartChild(ctx context.Context, name string, kind trace.SpanKind) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(InstrumentationScope)
+	return tracer.Start(ctx, name, trace.WithSpanKind(kind))
+}
+
+// End ends the span, and marks it as failed when the error is not nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Traceable is implemented by dependencies that can record their calls as
+// spans. Traced returns a copy of the dependency that records the spans as
+// children of the span in the context.
+type Traceable interface {
+	Traced(ctx context.Context) interface{}
+}
+
+// Bind returns the copy of the dependency that records spans as children of
+// the span in the context. It returns the dependency itself when the span in
+// the context is not recorded or the dependency cannot record spans.
+func Bind[T any](ctx context.Context, dep T) T {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return dep
+	}
+	if traceable, ok := any(dep).(Traceable); ok {
+		if traced, ok := traceable.Traced(ctx).(T); ok {
+			return traced
+		}
+	}
+	return dep
+}
+
+// BindFields returns a copy of the struct whose exported fields that are
+// Traceable are bound to the context, as with Bind. It returns the struct
+// itself when the span in the context is not recorded or no field is bound.
+// Dependencies implement Traced with it.
+func BindFields[T any](ctx context.Context, dep *T) *T {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return dep
+	}
+	if bound, ok := bindFields(ctx, reflect.ValueOf(dep)); ok {
+		return bound.Interface().(*T)
+	}
+	return dep
+}
+
+func bindFields(ctx context.Context, ptr reflect.Value) (reflect.Value, bool) {
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Struct {
+		return ptr, false
+	}
+
+	copied := reflect.New(ptr.Elem().Type())
+	copied.Elem().Set(ptr.Elem())
+
+	bound := false
+	for i := 0; i < copied.Elem().NumField(); i++ {
+		field := copied.Elem().Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		if field.Kind() != reflect.Interface && field.Kind() != reflect.Ptr || field.IsNil() {
+			continue
+		}
+		traceable, ok := field.Interface().(Traceable)
+		if !ok {
+			continue
+		}
+		traced := reflect.ValueOf(traceable.Traced(ctx))
+		if !traced.IsValid() || !traced.Type().AssignableTo(field.Type()) {
+			continue
+		}
+		field.Set(traced)
+		bound = true
+	}
+	if !bound {
+		return ptr, false
+	}
+	return copied, true
+}
@@ -0,0 +1,134 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/policy-server/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedClient struct {
+	ctx context.Context
+}
+
+func (c *tracedClient) Traced(ctx context.Context) interface{} {
+	return &tracedClient{ctx: ctx}
+}
+
+type client interface{}
+
+type dependent struct {
+	Client      client
+	Untraced    client
+	Name        string
+	otherClient client
+}
+
+var _ = Describe("Span", func() {
+	var (
+		recorder *tracetest.SpanRecorder
+		ctx      context.Context
+		parent   trace.Span
+	)
+
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		ctx, parent = provider.Tracer("test").Start(context.Background(), "parent")
+	})
+
+	Describe("StartChild", func() {
+		It("starts a child span of the span in the context", func() {
+			_, child := tracing.StartChild(ctx, "child", trace.SpanKindClient)
+			child.End()
+
+			Expect(recorder.Ended()).To(HaveLen(1))
+			ended := recorder.Ended()[0]
+			Expect(ended.Name()).To(Equal("child"))
+			Expect(ended.SpanKind()).To(Equal(trace.SpanKindClient))
+			Expect(ended.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+			Expect(ended.SpanContext().TraceID()).To(Equal(parent.SpanContext().TraceID()))
+			Expect(ended.InstrumentationScope().Name).To(Equal(tracing.InstrumentationScope))
+		})
+
+		It("records nothing when the context has no span", func() {
+			_, span := tracing.StartChild(context.Background(), "child", trace.SpanKindClient)
+			Expect(span.IsRecording()).To(BeFalse())
+			span.End()
+
+			Expect(recorder.Ended()).To(BeEmpty())
+		})
+	})
+
+	Describe("End", func() {
+		It("marks the span as failed when there is an error", func() {
+			_, failed := tracing.StartChild(ctx, "failed", trace.SpanKindClient)
+			tracing.End(failed, errors.New("banana"))
+			_, succeeded := tracing.StartChild(ctx, "succeeded", trace.SpanKindClient)
+			tracing.End(succeeded, nil)
+
+			Expect(recorder.Ended()).To(HaveLen(2))
+			Expect(recorder.Ended()[0].Status().Code).To(Equal(codes.Error))
+			Expect(recorder.Ended()[0].Status().Description).To(Equal("banana"))
+			Expect(recorder.Ended()[1].Status().Code).To(Equal(codes.Unset))
+		})
+	})
+
+	Describe("Bind", func() {
+		It("returns the copy of the dependency that is bound to the context", func() {
+			var dep client = &tracedClient{}
+
+			bound := tracing.Bind(ctx, dep)
+			Expect(bound).NotTo(BeIdenticalTo(dep))
+			Expect(bound.(*tracedClient).ctx).To(Equal(ctx))
+		})
+
+		It("returns the dependency itself when the context has no span", func() {
+			var dep client = &tracedClient{}
+			Expect(tracing.Bind(context.Background(), dep)).To(BeIdenticalTo(dep))
+		})
+
+		It("returns the dependency itself when it cannot record spans", func() {
+			Expect(tracing.Bind(ctx, "untraced")).To(Equal("untraced"))
+		})
+	})
+
+	Describe("BindFields", func() {
+		var dep *dependent
+
+		BeforeEach(func() {
+			dep = &dependent{
+				Client:      &tracedClient{},
+				Untraced:    "untraced",
+				Name:        "some-name",
+				otherClient: &tracedClient{},
+			}
+		})
+
+		It("returns a copy whose exported fields are bound to the context", func() {
+			bound := tracing.BindFields(ctx, dep)
+			Expect(bound).NotTo(BeIdenticalTo(dep))
+			Expect(bound.Client.(*tracedClient).ctx).To(Equal(ctx))
+			Expect(bound.Untraced).To(Equal("untraced"))
+			Expect(bound.Name).To(Equal("some-name"))
+			Expect(bound.otherClient).To(BeIdenticalTo(dep.otherClient))
+
+			Expect(dep.Client.(*tracedClient).ctx).To(BeNil())
+		})
+
+		It("returns the struct itself when the context has no span", func() {
+			Expect(tracing.BindFields(context.Background(), dep)).To(BeIdenticalTo(dep))
+		})
+
+		It("returns the struct itself when no field can record spans", func() {
+			dep.Client = nil
+			Expect(tracing.BindFields(ctx, dep)).To(BeIdenticalTo(dep))
+		})
+	})
+})
@@ -3,6 +3,7 @@ package uaa_client
 //go:generate counterfeiter -generate
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"strings"
 
 	"code.cloudfoundry.org/lager/v3"
+	"code.cloudfoundry.org/policy-server/tracing"
 )
 
 type BadUaaResponse struct {
@@ -40,6 +42,14 @@ type httpClient interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
+// Traced returns a copy of the client that records a span for every request
+// to UAA, as child of the span in the context
+func (c *Client) Traced(ctx context.Context) interface{} {
+	traced := *c
+	traced.HTTPClient = &tracing.HTTPClient{Client: c.HTTPClient, Context: ctx, Service: "uaa"}
+	return &traced
+}
+
 type CheckTokenResponse struct {
 	ClientID string   `json:"client_id"`
 	Scope    []string `json:"scope"`